@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// ref. "api/admin.AliasChainArgs" (importing "api/admin" pulls in its
+// profiler dependencies)
+type aliasChainArgs struct {
+	Chain string `json:"chain"`
+	Alias string `json:"alias"`
+}
+
+type successReply struct {
+	Success bool `json:"success"`
+}
+
+// AliasChain aliases [chain] as [alias] (e.g., "/ext/bc/[alias]/rpc") with
+// the admin API of the node at [uri], which must run with
+// "--api-admin-enabled".
+func AliasChain(ctx context.Context, uri string, chain string, alias string) error {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+	// ref. https://docs.avax.network/build/avalanchego-apis/admin
+	req := rpc.NewEndpointRequester(u.Scheme+"://"+u.Host, "/ext/admin", "admin")
+	return req.SendRequest(ctx, "aliasChain", &aliasChainArgs{
+		Chain: chain,
+		Alias: alias,
+	}, new(successReply))
+}
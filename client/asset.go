@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"go.uber.org/zap"
+)
+
+// Asset describes an X-Chain asset (e.g., the staking token of an elastic
+// subnet).
+type Asset struct {
+	ID     ids.ID
+	Name   string
+	Symbol string
+	// Denomination is the number of decimal places of one [Symbol].
+	Denomination uint8
+
+	avax bool
+}
+
+// IsAVAX returns true if [a] is the AVAX asset of the network.
+func (a *Asset) IsAVAX() bool {
+	return a == nil || a.avax
+}
+
+// AVAXAsset returns the AVAX asset of a network, whose ID is [assetID].
+func AVAXAsset(assetID ids.ID) *Asset {
+	return &Asset{ID: assetID, Name: "Avalanche", Symbol: "AVAX", Denomination: 9, avax: true}
+}
+
+func (cc *client) StakingAsset(ctx context.Context, subnetID ids.ID) (*Asset, error) {
+	avax := AVAXAsset(cc.assetID)
+	if subnetID == ids.Empty || subnetID == constants.PrimaryNetworkID {
+		return avax, nil
+	}
+	assetID, err := cc.p.Client().GetStakingAssetID(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	if assetID == cc.assetID {
+		return avax, nil
+	}
+	zap.L().Info("fetching staking asset", zap.String("subnetId", subnetID.String()), zap.String("assetId", assetID.String()))
+	desc, err := cc.xc.GetAssetDescription(ctx, assetID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Asset{
+		ID:           assetID,
+		Name:         desc.Name,
+		Symbol:       desc.Symbol,
+		Denomination: uint8(desc.Denomination),
+	}, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"go.uber.org/zap"
+)
+
+// ref. "api/auth.NewTokenArgs" (importing "api/auth" pulls in its JWT
+// dependency)
+type newTokenArgs struct {
+	Password  string   `json:"password"`
+	Endpoints []string `json:"endpoints"`
+}
+
+type newTokenReply struct {
+	Token string `json:"token"`
+}
+
+// NewAuthToken requests a token for all API endpoints from the auth API of
+// the node at [uri], which must run with "--api-auth-required".
+func NewAuthToken(ctx context.Context, uri string, password string) (string, error) {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+	// ref. https://docs.avax.network/build/avalanchego-apis/auth
+	req := rpc.NewEndpointRequester(u.Scheme+"://"+u.Host, "/ext/auth", "auth")
+	tok := new(newTokenReply)
+	if err := req.SendRequest(ctx, "newToken", &newTokenArgs{
+		Password:  password,
+		Endpoints: []string{"*"},
+	}, tok); err != nil {
+		return "", err
+	}
+	return tok.Token, nil
+}
+
+// SetAuthToken attaches [token] as a bearer token to every request to the
+// host of [uri] that does not already carry an authorization header.
+func SetAuthToken(uri string, token string) error {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+	InstallTransport()
+	transports.mu.Lock()
+	transports.tokens[u.Host] = token
+	transports.mu.Unlock()
+	return nil
+}
+
+// ConfigureAuth sets the auth token of [cfg] for its host, requesting a new
+// one if only the password is set. It is a no-op without credentials.
+func ConfigureAuth(ctx context.Context, cfg Config) error {
+	token := cfg.AuthToken
+	if token == "" && cfg.AuthPassword != "" {
+		zap.L().Info("requesting API auth token")
+		var err error
+		token, err = NewAuthToken(ctx, cfg.URI, cfg.AuthPassword)
+		if err != nil {
+			return err
+		}
+	}
+	if token == "" {
+		return nil
+	}
+	return SetAuthToken(cfg.URI, token)
+}
+
+// withAuth returns [req] with the bearer [token] attached.
+func withAuth(req *http.Request, token string) *http.Request {
+	if req.Header.Get("Authorization") != "" {
+		return req
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthToken(t *testing.T) {
+	t.Parallel()
+
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ext/auth" {
+			authorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		req := new(struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			ID     json.RawMessage `json:"id"`
+		})
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Method != "auth.newToken" {
+			http.Error(w, "unknown method", http.StatusBadRequest)
+			return
+		}
+		args := new(newTokenArgs)
+		if json.Unmarshal(req.Params, args) != nil || args.Password != "secret" {
+			http.Error(w, "invalid password", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"result":  &newTokenReply{Token: "token-" + args.Endpoints[0]},
+			"id":      req.ID,
+		})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if _, err := NewAuthToken(ctx, srv.URL, "wrong"); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+	tok, err := NewAuthToken(ctx, srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok != "token-*" {
+		t.Fatalf("unexpected token %q", tok)
+	}
+
+	if err := SetAuthToken(srv.URL, tok); err != nil {
+		t.Fatal(err)
+	}
+	send := func(header string) {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/ext/P", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	send("")
+	if authorization != "Bearer token-*" {
+		t.Fatalf("unexpected authorization %q", authorization)
+	}
+	// an explicit header is kept
+	send("Basic abc")
+	if authorization != "Basic abc" {
+		t.Fatalf("unexpected authorization %q", authorization)
+	}
+}
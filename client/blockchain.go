@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+// Blockchain is a blockchain as created by its CreateChainTx.
+type Blockchain struct {
+	ID       ids.ID
+	SubnetID ids.ID
+	Name     string
+	VMID     ids.ID
+	Genesis  []byte
+}
+
+// ref. "platformvm.UnsignedCreateChainTx".
+func (pc *p) GetBlockchain(ctx context.Context, blockchainID ids.ID) (*Blockchain, error) {
+	tb, err := pc.cli.GetTx(ctx, blockchainID)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(platformvm.Tx)
+	if _, err = codec.PCodecManager.Unmarshal(tb, tx); err != nil {
+		return nil, err
+	}
+	chainTx, ok := tx.UnsignedTx.(*platformvm.UnsignedCreateChainTx)
+	if !ok {
+		return nil, ErrWrongTxType
+	}
+	return &Blockchain{
+		ID:       blockchainID,
+		SubnetID: chainTx.SubnetID,
+		Name:     chainTx.ChainName,
+		VMID:     chainTx.VMID,
+		Genesis:  chainTx.GenesisData,
+	}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/cache"
+)
+
+const (
+	// ValidatorsCacheTTL is how long validator sets are cached; validator
+	// sets change with every staking tx, so keep this short.
+	ValidatorsCacheTTL = 30 * time.Second
+	// SubnetsCacheTTL is how long subnet lookups are cached.
+	SubnetsCacheTTL = 5 * time.Minute
+	// BlockchainsCacheTTL is how long blockchain lookups are cached.
+	BlockchainsCacheTTL = 5 * time.Minute
+)
+
+var _ platformvm.Client = &cachedPClient{}
+
+// cachedPClient serves the read-only lookups of [platformvm.Client] from
+// the local cache. All other calls go to the endpoint.
+type cachedPClient struct {
+	platformvm.Client
+
+	uri   string
+	cache *cache.Cache
+}
+
+func newCachedPClient(cli platformvm.Client, uri string, c *cache.Cache) *cachedPClient {
+	return &cachedPClient{Client: cli, uri: uri, cache: c}
+}
+
+// cached decodes the cached response of [method] with [args] into [v], or
+// calls [fetch] (which must fill [v]) and caches the result for [ttl].
+func (cc *cachedPClient) cached(method string, args []interface{}, ttl time.Duration, v interface{}, fetch func() error) error {
+	k, err := cache.Key(cc.uri, method, args)
+	if err != nil {
+		return err
+	}
+	if cc.cache.Get(k, v) {
+		zap.L().Debug("cache hit", zap.String("method", method))
+		return nil
+	}
+	if err := fetch(); err != nil {
+		return err
+	}
+	if err := cc.cache.Put(k, v, ttl); err != nil {
+		// a read-only cache must never fail the lookup itself
+		zap.L().Warn("failed to cache response", zap.String("method", method), zap.Error(err))
+	}
+	return nil
+}
+
+func (cc *cachedPClient) GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.ShortID) ([]interface{}, error) {
+	var vs []interface{}
+	err := cc.cached("platform.getCurrentValidators", []interface{}{subnetID, nodeIDs}, ValidatorsCacheTTL, &vs, func() (err error) {
+		vs, err = cc.Client.GetCurrentValidators(ctx, subnetID, nodeIDs)
+		return err
+	})
+	return vs, err
+}
+
+type pendingValidators struct {
+	Validators []interface{} `json:"validators"`
+	Delegators []interface{} `json:"delegators"`
+}
+
+func (cc *cachedPClient) GetPendingValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.ShortID) ([]interface{}, []interface{}, error) {
+	var pv pendingValidators
+	err := cc.cached("platform.getPendingValidators", []interface{}{subnetID, nodeIDs}, ValidatorsCacheTTL, &pv, func() (err error) {
+		pv.Validators, pv.Delegators, err = cc.Client.GetPendingValidators(ctx, subnetID, nodeIDs)
+		return err
+	})
+	return pv.Validators, pv.Delegators, err
+}
+
+func (cc *cachedPClient) GetSubnets(ctx context.Context, subnetIDs []ids.ID) ([]platformvm.APISubnet, error) {
+	var ss []platformvm.APISubnet
+	err := cc.cached("platform.getSubnets", []interface{}{subnetIDs}, SubnetsCacheTTL, &ss, func() (err error) {
+		ss, err = cc.Client.GetSubnets(ctx, subnetIDs)
+		return err
+	})
+	return ss, err
+}
+
+func (cc *cachedPClient) GetBlockchains(ctx context.Context) ([]platformvm.APIBlockchain, error) {
+	var bcs []platformvm.APIBlockchain
+	err := cc.cached("platform.getBlockchains", nil, BlockchainsCacheTTL, &bcs, func() (err error) {
+		bcs, err = cc.Client.GetBlockchains(ctx)
+		return err
+	})
+	return bcs, err
+}
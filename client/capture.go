@@ -0,0 +1,214 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+// Redacted replaces secrets in captured requests and responses.
+const Redacted = redact.Redacted
+
+// redactedHeaders are never written to captures.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// redactedFields matches the JSON fields whose values are secrets (e.g.,
+// keystore passwords, exported private keys).
+var redactedFields = regexp.MustCompile(`(?i)(password|privatekey|secret|token|mnemonic|seed|apikey)`)
+
+// capturingTransport records every request and response it forwards.
+type capturingTransport struct {
+	next   http.RoundTripper
+	record func(c capture, rpcMethod string)
+}
+
+var captureMu sync.Mutex
+
+func wrapTransport(record func(c capture, rpcMethod string)) {
+	InstallTransport()
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	http.DefaultClient.Transport = &capturingTransport{next: http.DefaultClient.Transport, record: record}
+}
+
+// CaptureHTTP records every HTTP request and response (with secrets
+// redacted) as a JSON file in [dir], so traces can be attached to bug
+// reports. It must be called before creating clients.
+func CaptureHTTP(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	w := &captureWriter{dir: dir}
+	wrapTransport(w.write)
+	return nil
+}
+
+// LogHTTP logs every HTTP request and response (with secrets redacted) at
+// debug level. It must be called before creating clients.
+func LogHTTP() {
+	wrapTransport(func(c capture, rpcMethod string) {
+		zap.L().Debug("http exchange",
+			zap.String("rpcMethod", rpcMethod),
+			zap.String("url", c.URL),
+			zap.Int("status", c.Status),
+			zap.String("duration", c.Duration),
+			zap.Any("request", c.Request),
+			zap.Any("response", c.Response),
+			zap.String("error", c.Error),
+		)
+	})
+}
+
+// capture is one recorded exchange.
+type capture struct {
+	Time     time.Time   `json:"time"`
+	Duration string      `json:"duration"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Request  interface{} `json:"request,omitempty"`
+	Headers  http.Header `json:"headers,omitempty"`
+
+	Status          int         `json:"status,omitempty"`
+	Response        interface{} `json:"response,omitempty"`
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty"`
+	Error           string      `json:"error,omitempty"`
+}
+
+func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := capture{
+		Time:    time.Now(),
+		Method:  req.Method,
+		URL:     redact.String(req.URL.Redacted()),
+		Headers: redactHeaders(req.Header),
+	}
+	rpcMethod := ""
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		c.Request = redactBody(redact.Bytes(b))
+		var rpc struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(b, &rpc) == nil {
+			rpcMethod = rpc.Method
+		}
+	}
+
+	res, err := t.next.RoundTrip(req)
+	c.Duration = time.Since(c.Time).String()
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		b, rerr := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(b))
+		if rerr != nil {
+			return nil, rerr
+		}
+		c.Status = res.StatusCode
+		c.Response = redactBody(redact.Bytes(b))
+		c.ResponseHeaders = redactHeaders(res.Header)
+	}
+	t.record(c, rpcMethod)
+	return res, err
+}
+
+// captureWriter writes each exchange to its own file.
+type captureWriter struct {
+	dir string
+	seq uint64
+}
+
+func (t *captureWriter) write(c capture, rpcMethod string) {
+	seq := atomic.AddUint64(&t.seq, 1)
+	name := fmt.Sprintf("%s-%04d", c.Time.Format("20060102T150405"), seq)
+	if rpcMethod != "" {
+		name += "-" + strings.NewReplacer("/", "_", ".", "_").Replace(rpcMethod)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(c)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, name+".json"), buf.Bytes(), 0o600)
+	}
+	if err != nil {
+		// capturing must never fail the request itself
+		zap.L().Warn("failed to capture HTTP exchange", zap.String("url", c.URL), zap.Error(err))
+	}
+}
+
+func redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		if redactedHeaders[http.CanonicalHeaderKey(k)] {
+			v = []string{Redacted}
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// redactBody returns the JSON body [b] with secret fields redacted, or
+// [b] as a string if it is not JSON.
+func redactBody(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	// keep large integers (e.g., nAVAX amounts) exact
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return string(b)
+	}
+	return redactValue(v)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if redactedFields.MatchString(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redactValue(fv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/cassette"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+// RecordHTTP records every HTTP exchange (with secrets redacted) to the
+// cassette [path], so it can be replayed with [ReplayHTTP]. It must be
+// called before creating clients.
+func RecordHTTP(path string) error {
+	r, err := cassette.NewRecorder(path)
+	if err != nil {
+		return err
+	}
+	wrapTransport(func(c capture, rpcMethod string) {
+		err := r.Add(cassette.Interaction{
+			Method:          c.Method,
+			URL:             c.URL,
+			RPCMethod:       rpcMethod,
+			Request:         c.Request,
+			Status:          c.Status,
+			Response:        c.Response,
+			ResponseHeaders: c.ResponseHeaders,
+			Error:           c.Error,
+		})
+		if err != nil {
+			// recording must never fail the request itself
+			zap.L().Warn("failed to record HTTP exchange", zap.String("url", c.URL), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// ReplayHTTP serves every HTTP request from the cassette [path] instead of
+// the network; requests without a recorded exchange fail. It must be
+// called before creating clients, and before [RecordHTTP] or
+// [CaptureHTTP] if combined with them.
+func ReplayHTTP(path string) error {
+	c, err := cassette.Load(path)
+	if err != nil {
+		return err
+	}
+	InstallTransport()
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	http.DefaultClient.Transport = cassette.NewPlayer(c, recordedURL)
+	return nil
+}
+
+// recordedURL returns [u] as it is recorded (see [capturingTransport]).
+func recordedURL(u string) string {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return redact.String(u)
+	}
+	return redact.String(pu.Redacted())
+}
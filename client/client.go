@@ -8,15 +8,21 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/subnet-cli/internal/cache"
+	"github.com/ava-labs/subnet-cli/internal/evm"
 	internal_platformvm "github.com/ava-labs/subnet-cli/internal/platformvm"
 	"github.com/ava-labs/subnet-cli/internal/poll"
+	"github.com/ava-labs/subnet-cli/internal/progress"
 	"go.uber.org/zap"
 )
 
@@ -30,6 +36,33 @@ type Config struct {
 	URI          string
 	u            *url.URL
 	PollInterval time.Duration
+	// Cache, if set, serves read-only P-Chain lookups (validators,
+	// subnets, blockchains) from the local cache. Only set it for
+	// read-only commands, since cached responses may be stale.
+	Cache *cache.Cache
+	// Proxy is the URL of an HTTP(S) or SOCKS5 proxy
+	// (e.g., "socks5://localhost:1080"). If empty, HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY are honored.
+	Proxy string
+	// TLS adds trusted CAs and a client certificate (e.g., for mTLS).
+	TLS TLSConfig
+	// AuthToken is attached as a bearer token to every request, for nodes
+	// running with "--api-auth-required".
+	AuthToken string
+	// AuthPassword, if set without [AuthToken], requests a new token from
+	// the auth API.
+	AuthPassword string
+	// Progress, if set, renders a progress indicator for the tx acceptance
+	// and blockchain bootstrap waits to it (e.g., a terminal).
+	Progress io.Writer
+	// ExpireAt, if set, is the wall-clock time after which no tx is issued,
+	// so a stale retry of a prepared operation never issues it late.
+	ExpireAt time.Time
+	// RateLimit is the maximum number of requests per second to the host of
+	// [URI]. Zero defaults to [PublicRateLimit] for known public endpoints
+	// (e.g., api.avax.network) and no limit otherwise; a negative value
+	// disables rate limiting.
+	RateLimit float64
 }
 
 var _ Client = &client{}
@@ -39,7 +72,16 @@ type Client interface {
 	Config() Config
 	Info() Info
 	KeyStore() KeyStore
+	Fees() FeeCalculator
 	P() P
+	X() X
+	// StakingAsset returns the asset validators of [subnetID] stake: AVAX,
+	// or a custom asset for elastic subnets. Permissioned subnets have no
+	// staking asset and fail.
+	StakingAsset(ctx context.Context, subnetID ids.ID) (*Asset, error)
+	// EVM returns the EVM JSON-RPC client of [chain] (a blockchain ID or
+	// alias, e.g., "C"), created on first use and reused afterwards.
+	EVM(chain string) *evm.Client
 }
 
 type client struct {
@@ -52,12 +94,20 @@ type client struct {
 	xChainID    ids.ID
 	pChainID    ids.ID
 
-	i *info
-	k *keyStore
-	p *p
+	xc   avm.Client
+	i    *info
+	k    *keyStore
+	p    *p
+	x    *x
+	fees FeeCalculator
+
+	evmMu sync.Mutex
+	evms  map[string]*evm.Client
 }
 
-func New(cfg Config) (Client, error) {
+// New connects to [cfg.URI] and fetches the network information; [ctx]
+// bounds these initial requests.
+func New(ctx context.Context, cfg Config) (Client, error) {
 	if cfg.URI == "" {
 		return nil, ErrEmptyURI
 	}
@@ -65,21 +115,35 @@ func New(cfg Config) (Client, error) {
 		return nil, ErrInvalidInterval
 	}
 
-	u, err := url.Parse(cfg.URI)
+	InstallTransport()
+
+	u, err := ParseURI(cfg.URI)
 	if err != nil {
 		return nil, err
 	}
+	// unix sockets are mapped to HTTP URLs, see [ParseURI]
+	cfg.URI = u.String()
 	cfg.u = u
+	if err := ConfigureTransport(cfg); err != nil {
+		return nil, err
+	}
+	if err := ConfigureRateLimit(cfg); err != nil {
+		return nil, err
+	}
+	if err := ConfigureAuth(ctx, cfg); err != nil {
+		return nil, err
+	}
 
 	cli := &client{
 		cfg:      cfg,
 		pChainID: avago_constants.PlatformChainID,
 		i:        newInfo(cfg),
 		k:        newKeyStore(cfg),
+		evms:     make(map[string]*evm.Client),
 	}
 
 	zap.L().Info("fetching X-Chain id")
-	xChainID, err := cli.i.Client().GetBlockchainID(context.TODO(), "X")
+	xChainID, err := cli.i.Client().GetBlockchainID(ctx, "X")
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +160,8 @@ func New(cfg Config) (Client, error) {
 	zap.L().Info("fetching AVAX asset id",
 		zap.String("uri", uriX),
 	)
-	xc := avm.NewClient(uriX, xChainName)
-	avaxDesc, err := xc.GetAssetDescription(context.TODO(), "AVAX")
+	cli.xc = avm.NewClient(uriX, xChainName)
+	avaxDesc, err := cli.xc.GetAssetDescription(ctx, "AVAX")
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +169,7 @@ func New(cfg Config) (Client, error) {
 	zap.L().Info("fetched AVAX asset id", zap.String("id", cli.assetID.String()))
 
 	zap.L().Info("fetching network information")
-	cli.networkName, err = cli.i.Client().GetNetworkName(context.TODO())
+	cli.networkName, err = cli.i.Client().GetNetworkName(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +187,23 @@ func New(cfg Config) (Client, error) {
 	// ref. https://docs.avax.network/build/avalanchego-apis/p-chain
 	uriP := u.Scheme + "://" + u.Host
 	pc := platformvm.NewClient(uriP)
+	poller := poll.New(cfg.PollInterval)
+	if cfg.Progress != nil {
+		poller = progress.NewPoller(poller, cfg.Progress)
+	}
+	var pcli platformvm.Client = pc
+	if cfg.Cache != nil {
+		pcli = newCachedPClient(pc, uriP, cfg.Cache)
+	}
+	cli.x = &x{
+		cfg:       cfg,
+		networkID: cli.networkID,
+		assetID:   cli.assetID,
+		xChainID:  cli.xChainID,
+		cli:       cli.xc,
+		info:      cli.i.Client(),
+	}
+	cli.fees = NewFeeCalculator(uriP, cli.i.Client())
 	cli.p = &p{
 		cfg: cfg,
 
@@ -131,12 +212,12 @@ func New(cfg Config) (Client, error) {
 		assetID:     cli.assetID,
 		pChainID:    cli.pChainID,
 
-		cli:  pc,
-		info: cli.i.Client(),
-		checker: internal_platformvm.NewChecker(
-			poll.New(cfg.PollInterval),
-			pc,
-		),
+		cli:       pcli,
+		requester: rpc.NewEndpointRequester(uriP, "/ext/P", "platform"),
+		info:      cli.i.Client(),
+		fees:      cli.fees,
+		// the checker polls for changes, so it must bypass the cache
+		checker: internal_platformvm.NewChecker(poller, pc),
 	}
 	return cli, nil
 }
@@ -144,7 +225,21 @@ func New(cfg Config) (Client, error) {
 func (cc *client) NetworkID() uint32 { return cc.networkID }
 func (cc *client) Config() Config    { return cc.cfg }
 
-func (cc *client) Info() Info         { return cc.i }
-func (cc *client) KeyStore() KeyStore { return cc.k }
+func (cc *client) Info() Info          { return cc.i }
+func (cc *client) KeyStore() KeyStore  { return cc.k }
+func (cc *client) Fees() FeeCalculator { return cc.fees }
 
 func (cc *client) P() P { return cc.p }
+func (cc *client) X() X { return cc.x }
+
+func (cc *client) EVM(chain string) *evm.Client {
+	cc.evmMu.Lock()
+	defer cc.evmMu.Unlock()
+	ec, ok := cc.evms[chain]
+	if !ok {
+		// e.g., https://api.avax-test.network/ext/bc/C/rpc
+		ec = evm.NewClient(cc.cfg.u.Scheme + "://" + cc.cfg.u.Host + "/ext/bc/" + chain + "/rpc")
+		cc.evms[chain] = ec
+	}
+	return ec
+}
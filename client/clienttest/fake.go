@@ -0,0 +1,387 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package clienttest implements an in-memory fake of [client.Client], so
+// programs embedding the client can be tested without a live network.
+package clienttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/version"
+)
+
+// DefaultFee is the fee of every tx type without a [WithFee] fee.
+const DefaultFee = 1_000_000
+
+// TxTypeCreateAsset is the X-Chain tx type of [client.X.CreateAsset].
+const TxTypeCreateAsset client.TxType = "CreateAssetTx"
+
+var (
+	ErrUnknownSubnet     = errors.New("unknown subnet")
+	ErrUnknownBlockchain = errors.New("unknown blockchain")
+	ErrNoStakingAsset    = errors.New("subnet has no staking asset")
+)
+
+var _ client.Client = &Fake{}
+
+// Tx is a tx the fake accepted.
+type Tx struct {
+	ID   ids.ID
+	Type client.TxType
+	// Fee is the burned fee, excluding stake and L1 validator balances.
+	Fee uint64
+}
+
+// Fake is an in-memory [client.Client]. It keeps balances, subnets,
+// validators and blockchains, and accepts the txs issued through it after
+// the [WithLatency] delay, updating its state, unless a failure was queued
+// with [Fake.Fail]. Dry mode returns the ID the next tx would get.
+//
+// The clients of the underlying APIs (e.g., [client.P.Client]) only serve
+// what the fake keeps; X-Chain, keystore and EVM clients are not faked.
+type Fake struct {
+	mu sync.Mutex
+
+	networkID    uint32
+	networkName  string
+	nodeVersion  string
+	assetID      ids.ID
+	latency      time.Duration
+	fees         map[client.TxType]uint64
+	validatorFee client.ValidatorFee
+
+	balances      map[ids.ShortID]uint64
+	xBalances     map[ids.ShortID]uint64
+	subnets       map[ids.ID]ids.ShortID
+	validators    map[ids.ID]map[ids.ShortID]*client.Validator
+	blockchains   map[ids.ID]*client.Blockchain
+	stakingAssets map[ids.ID]*client.Asset
+	l1Managers    map[ids.ID]l1Manager
+	l1Validators  map[ids.ID]*client.L1Validator
+
+	peers   []ids.ShortID
+	blsKeys map[ids.ShortID]string
+
+	failures map[string][]error
+	txs      []Tx
+}
+
+type l1Manager struct {
+	chainID ids.ID
+	address []byte
+}
+
+// Option configures a [Fake].
+type Option func(*Fake)
+
+// WithNetworkID sets the network of the fake (defaults to the local
+// network).
+func WithNetworkID(networkID uint32) Option {
+	return func(f *Fake) {
+		f.networkID = networkID
+		f.networkName = constants.NetworkName(networkID)
+	}
+}
+
+// WithLatency sets how long each tx takes to be accepted.
+func WithLatency(d time.Duration) Option {
+	return func(f *Fake) { f.latency = d }
+}
+
+// WithFee sets the fee of [txType].
+func WithFee(txType client.TxType, fee uint64) Option {
+	return func(f *Fake) { f.fees[txType] = fee }
+}
+
+// WithNodeVersion sets the avalanchego version the fake node reports
+// (e.g., "avalanche/1.7.6").
+func WithNodeVersion(v string) Option {
+	return func(f *Fake) { f.nodeVersion = v }
+}
+
+// WithValidatorFee sets the continuous fee of L1 validators.
+func WithValidatorFee(fee client.ValidatorFee) Option {
+	return func(f *Fake) { f.validatorFee = fee }
+}
+
+// New returns an empty fake of the local network.
+func New(opts ...Option) *Fake {
+	v := version.Avalanchego
+	f := &Fake{
+		networkID:     constants.LocalID,
+		networkName:   constants.LocalName,
+		nodeVersion:   fmt.Sprintf("avalanche/%d.%d.%d", v.Major(), v.Minor(), v.Patch()),
+		assetID:       hashing.ComputeHash256Array([]byte("AVAX")),
+		fees:          make(map[client.TxType]uint64),
+		validatorFee:  client.ValidatorFee{Price: 512, MinPrice: 512, Target: 10_000, Capacity: 20_000},
+		balances:      make(map[ids.ShortID]uint64),
+		xBalances:     make(map[ids.ShortID]uint64),
+		subnets:       make(map[ids.ID]ids.ShortID),
+		validators:    map[ids.ID]map[ids.ShortID]*client.Validator{constants.PrimaryNetworkID: {}},
+		blockchains:   make(map[ids.ID]*client.Blockchain),
+		stakingAssets: make(map[ids.ID]*client.Asset),
+		l1Managers:    make(map[ids.ID]l1Manager),
+		l1Validators:  make(map[ids.ID]*client.L1Validator),
+		blsKeys:       make(map[ids.ShortID]string),
+		failures:      make(map[string][]error),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// SetBalance sets the P-Chain balance of [addr] in nAVAX.
+func (f *Fake) SetBalance(addr ids.ShortID, nAVAX uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[addr] = nAVAX
+}
+
+// SetXBalance sets the X-Chain balance of [addr] in nAVAX.
+func (f *Fake) SetXBalance(addr ids.ShortID, nAVAX uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.xBalances[addr] = nAVAX
+}
+
+// AddSubnet adds the subnet [subnetID] controlled by [owner].
+func (f *Fake) AddSubnet(subnetID ids.ID, owner ids.ShortID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addSubnet(subnetID, owner)
+}
+
+func (f *Fake) addSubnet(subnetID ids.ID, owner ids.ShortID) {
+	f.subnets[subnetID] = owner
+	if _, ok := f.validators[subnetID]; !ok {
+		f.validators[subnetID] = make(map[ids.ShortID]*client.Validator)
+	}
+}
+
+// AddValidator adds [v] to the validators of [subnetID] (ids.Empty for the
+// primary network). It is pending until [v.Start].
+func (f *Fake) AddValidator(subnetID ids.ID, v client.Validator) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.validators[subnetID]; !ok {
+		f.validators[subnetID] = make(map[ids.ShortID]*client.Validator)
+	}
+	f.validators[subnetID][v.NodeID] = &v
+}
+
+// AddBlockchain adds [b] to its subnet.
+func (f *Fake) AddBlockchain(b client.Blockchain) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blockchains[b.ID] = &b
+}
+
+// SetStakingAsset makes [subnetID] an elastic subnet staking [a].
+func (f *Fake) SetStakingAsset(subnetID ids.ID, a *client.Asset) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stakingAssets[subnetID] = a
+}
+
+// SetPeers sets the nodes the fake node is connected to.
+func (f *Fake) SetPeers(nodeIDs ...ids.ShortID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.peers = append([]ids.ShortID(nil), nodeIDs...)
+}
+
+// SetBLSPublicKey registers the BLS public key [pk] of [nodeID].
+func (f *Fake) SetBLSPublicKey(nodeID ids.ShortID, pk string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blsKeys[nodeID] = pk
+}
+
+// Fail makes the next call of [method] (e.g., "CreateSubnet" or
+// "GetValidators") fail with [err]. Failures of a method are returned in
+// the order they were queued.
+func (f *Fake) Fail(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method] = append(f.failures[method], err)
+}
+
+// Txs returns the txs the fake accepted, in order.
+func (f *Fake) Txs() []Tx {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Tx(nil), f.txs...)
+}
+
+// failure pops the next queued failure of [method]; [f.mu] must be held.
+func (f *Fake) failure(method string) error {
+	errs := f.failures[method]
+	if len(errs) == 0 {
+		return nil
+	}
+	f.failures[method] = errs[1:]
+	return errs[0]
+}
+
+// read returns the queued failure of [method], if any.
+func (f *Fake) read(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failure(method)
+}
+
+func (f *Fake) fee(txType client.TxType) uint64 {
+	if fee, ok := f.fees[txType]; ok {
+		return fee
+	}
+	return DefaultFee
+}
+
+// nextTxID returns the ID of the next accepted tx of [txType]; [f.mu] must
+// be held.
+func (f *Fake) nextTxID(txType client.TxType) ids.ID {
+	p := wrappers.Packer{MaxSize: 1024, Bytes: make([]byte, 0, 64)}
+	p.PackStr(string(txType))
+	p.PackInt(uint32(len(f.txs)))
+	return hashing.ComputeHash256Array(p.Bytes)
+}
+
+// issue accepts a tx of [txType] that burns its fee and [amount] from the
+// [balances] of [k] and then runs [accept] with the tx ID. In dry mode, it
+// only returns the tx ID.
+func (f *Fake) issue(
+	ctx context.Context,
+	method string,
+	txType client.TxType,
+	balances map[ids.ShortID]uint64,
+	k key.Key,
+	amount uint64,
+	opts []client.OpOption,
+	accept func(txID ids.ID) error,
+) (ids.ID, time.Duration, error) {
+	f.mu.Lock()
+	if err := f.failure(method); err != nil {
+		f.mu.Unlock()
+		return ids.Empty, 0, err
+	}
+	txID := f.nextTxID(txType)
+	f.mu.Unlock()
+	if client.ApplyOpts(opts).DryMode() {
+		return txID, 0, nil
+	}
+
+	start := time.Now()
+	if f.latency > 0 {
+		t := time.NewTimer(f.latency)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ids.Empty, time.Since(start), ctx.Err()
+		case <-t.C:
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// another tx may have been accepted while waiting
+	txID = f.nextTxID(txType)
+	fee := f.fee(txType)
+	if have := available(balances, k); have < fee+amount {
+		return ids.Empty, time.Since(start), fmt.Errorf("%w: have %d, need %d", client.ErrInsufficientBalanceForGasFee, have, fee+amount)
+	}
+	if err := accept(txID); err != nil {
+		return ids.Empty, time.Since(start), err
+	}
+	spend(balances, k, fee+amount)
+	f.txs = append(f.txs, Tx{ID: txID, Type: txType, Fee: fee})
+	return txID, time.Since(start), nil
+}
+
+// available returns the total of the [balances] of the addresses of [k].
+func available(balances map[ids.ShortID]uint64, k key.Key) uint64 {
+	total := uint64(0)
+	for _, addr := range k.Addresses() {
+		total += balances[addr]
+	}
+	return total
+}
+
+// spend burns [amount] from the [balances] of the addresses of [k], which
+// must hold it.
+func spend(balances map[ids.ShortID]uint64, k key.Key, amount uint64) {
+	for _, addr := range k.Addresses() {
+		b := balances[addr]
+		if b > amount {
+			b = amount
+		}
+		balances[addr] -= b
+		amount -= b
+	}
+}
+
+func (f *Fake) NetworkID() uint32 { return f.networkID }
+
+func (f *Fake) Config() client.Config {
+	return client.Config{URI: "http://clienttest.invalid", PollInterval: time.Millisecond}
+}
+
+func (f *Fake) Info() client.Info          { return &info{f: f} }
+func (f *Fake) KeyStore() client.KeyStore  { return keyStore{} }
+func (f *Fake) Fees() client.FeeCalculator { return fees{f: f} }
+func (f *Fake) P() client.P                { return &p{f: f} }
+func (f *Fake) X() client.X                { return &x{f: f} }
+func (f *Fake) EVM(chain string) *evm.Client {
+	return evm.NewClient("http://clienttest.invalid/ext/bc/" + chain + "/rpc")
+}
+func (f *Fake) StakingAsset(_ context.Context, subnetID ids.ID) (*client.Asset, error) {
+	if err := f.read("StakingAsset"); err != nil {
+		return nil, err
+	}
+	if subnetID == constants.PrimaryNetworkID {
+		return client.AVAXAsset(f.assetID), nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a, ok := f.stakingAssets[subnetID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoStakingAsset, subnetID)
+	}
+	return a, nil
+}
+
+type fees struct{ f *Fake }
+
+func (fs fees) Fee(_ context.Context, txType client.TxType, _ int) (uint64, error) {
+	if err := fs.f.read("Fee"); err != nil {
+		return 0, err
+	}
+	fs.f.mu.Lock()
+	defer fs.f.mu.Unlock()
+	return fs.f.fee(txType), nil
+}
+
+// sortedValidators returns the validators of [subnetID] by node ID; [f.mu]
+// must be held.
+func (f *Fake) sortedValidators(subnetID ids.ID) []*client.Validator {
+	vs := make([]*client.Validator, 0, len(f.validators[subnetID]))
+	for _, v := range f.validators[subnetID] {
+		vs = append(vs, v)
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].NodeID.String() < vs[j].NodeID.String() })
+	return vs
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clienttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+var errTest = errors.New("test")
+
+func newKey(t *testing.T) key.Key {
+	k, err := key.NewSoft(constants.LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestCreateSubnet(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		balance uint64
+		fail    error
+		opts    []client.OpOption
+		txs     int
+		left    uint64
+		err     error
+	}{
+		{name: "accepted", balance: 3 * DefaultFee, txs: 1, left: 2 * DefaultFee},
+		{name: "dry mode", balance: 3 * DefaultFee, opts: []client.OpOption{client.WithDryMode(true)}, left: 3 * DefaultFee},
+		{name: "insufficient funds", balance: DefaultFee - 1, left: DefaultFee - 1, err: client.ErrInsufficientBalanceForGasFee},
+		{name: "failure", balance: 3 * DefaultFee, fail: errTest, left: 3 * DefaultFee, err: errTest},
+	}
+	for _, tv := range tt {
+		f := New()
+		k := newKey(t)
+		f.SetBalance(k.Addresses()[0], tv.balance)
+		if tv.fail != nil {
+			f.Fail("CreateSubnet", tv.fail)
+		}
+		subnetID, _, err := f.P().CreateSubnet(context.Background(), k, tv.opts...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		if err == nil && subnetID == ids.Empty {
+			t.Fatalf("%s: expected a subnet ID", tv.name)
+		}
+		if txs := f.Txs(); len(txs) != tv.txs {
+			t.Fatalf("%s: expected %d txs, got %d", tv.name, tv.txs, len(txs))
+		}
+		left, err := f.P().Balance(context.Background(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if left != tv.left {
+			t.Fatalf("%s: expected balance %d, got %d", tv.name, tv.left, left)
+		}
+	}
+}
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+
+	f := New(WithLatency(time.Hour))
+	k := newKey(t)
+	f.SetBalance(k.Addresses()[0], DefaultFee)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := f.P().CreateSubnet(ctx, k); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if txs := f.Txs(); len(txs) != 0 {
+		t.Fatalf("expected no txs, got %d", len(txs))
+	}
+}
+
+func TestValidators(t *testing.T) {
+	t.Parallel()
+
+	f := New()
+	k := newKey(t)
+	f.SetBalance(k.Addresses()[0], 100*DefaultFee)
+	ctx := context.Background()
+
+	subnetID, _, err := f.P().CreateSubnet(ctx, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID := ids.GenerateTestShortID()
+	start, end := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	if _, err := f.P().AddSubnetValidator(ctx, k, subnetID, nodeID, start, end, 10); !errors.Is(err, client.ErrNotValidatingPrimaryNetwork) {
+		t.Fatalf("expected %v, got %v", client.ErrNotValidatingPrimaryNetwork, err)
+	}
+	if _, err := f.P().AddValidator(ctx, k, nodeID, start, end, client.WithStakeAmount(DefaultFee)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.P().AddSubnetValidator(ctx, k, subnetID, nodeID, start, end, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.P().AddSubnetValidator(ctx, k, subnetID, nodeID, start, end, 10); !errors.Is(err, client.ErrAlreadySubnetValidator) {
+		t.Fatalf("expected %v, got %v", client.ErrAlreadySubnetValidator, err)
+	}
+	ws, err := f.P().GetValidatorWeights(ctx, subnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) != 1 || ws[nodeID] != 10 {
+		t.Fatalf("unexpected weights %v", ws)
+	}
+	if _, _, err := f.P().GetPendingValidator(ctx, subnetID, nodeID); !errors.Is(err, client.ErrValidatorNotFound) {
+		t.Fatalf("expected %v, got %v", client.ErrValidatorNotFound, err)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clienttest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	api_info "github.com/ava-labs/avalanchego/api/info"
+	api_keystore "github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+var (
+	_ client.Info     = &info{}
+	_ client.KeyStore = keyStore{}
+	_ client.X        = &x{}
+)
+
+type info struct{ f *Fake }
+
+func (i *info) Client() api_info.Client { return &infoClient{f: i.f} }
+
+// infoClient serves the Info API from the fake: the node is bootstrapped
+// and connected to the peers set with [Fake.SetPeers].
+type infoClient struct{ f *Fake }
+
+func (ic *infoClient) GetNodeVersion(context.Context) (*api_info.GetNodeVersionReply, error) {
+	if err := ic.f.read("GetNodeVersion"); err != nil {
+		return nil, err
+	}
+	return &api_info.GetNodeVersionReply{Version: ic.f.nodeVersion}, nil
+}
+
+func (ic *infoClient) GetNodeID(context.Context) (string, error) {
+	if err := ic.f.read("GetNodeID"); err != nil {
+		return "", err
+	}
+	return ids.ShortEmpty.PrefixedString(constants.NodeIDPrefix), nil
+}
+
+func (ic *infoClient) GetNodeIP(context.Context) (string, error) {
+	if err := ic.f.read("GetNodeIP"); err != nil {
+		return "", err
+	}
+	return "127.0.0.1:9651", nil
+}
+
+func (ic *infoClient) GetNetworkID(context.Context) (uint32, error) {
+	if err := ic.f.read("GetNetworkID"); err != nil {
+		return 0, err
+	}
+	return ic.f.networkID, nil
+}
+
+func (ic *infoClient) GetNetworkName(context.Context) (string, error) {
+	if err := ic.f.read("GetNetworkName"); err != nil {
+		return "", err
+	}
+	return ic.f.networkName, nil
+}
+
+func (ic *infoClient) GetBlockchainID(_ context.Context, alias string) (ids.ID, error) {
+	if err := ic.f.read("GetBlockchainID"); err != nil {
+		return ids.Empty, err
+	}
+	if alias == "P" {
+		return constants.PlatformChainID, nil
+	}
+	ic.f.mu.Lock()
+	defer ic.f.mu.Unlock()
+	for id, b := range ic.f.blockchains {
+		if alias == id.String() || alias == b.Name {
+			return id, nil
+		}
+	}
+	return ids.Empty, fmt.Errorf("%w: %q", ErrUnknownBlockchain, alias)
+}
+
+func (ic *infoClient) Peers(context.Context) ([]network.PeerInfo, error) {
+	if err := ic.f.read("Peers"); err != nil {
+		return nil, err
+	}
+	ic.f.mu.Lock()
+	defer ic.f.mu.Unlock()
+	ps := make([]network.PeerInfo, 0, len(ic.f.peers))
+	for _, nodeID := range ic.f.peers {
+		ps = append(ps, network.PeerInfo{ID: nodeID.PrefixedString(constants.NodeIDPrefix)})
+	}
+	return ps, nil
+}
+
+func (ic *infoClient) IsBootstrapped(context.Context, string) (bool, error) {
+	if err := ic.f.read("IsBootstrapped"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (ic *infoClient) GetTxFee(context.Context) (*api_info.GetTxFeeResponse, error) {
+	if err := ic.f.read("GetTxFee"); err != nil {
+		return nil, err
+	}
+	ic.f.mu.Lock()
+	defer ic.f.mu.Unlock()
+	return &api_info.GetTxFeeResponse{
+		TxFee:                 json.Uint64(ic.f.fee(client.TxTypeAddValidator)),
+		CreationTxFee:         json.Uint64(ic.f.fee(TxTypeCreateAsset)),
+		CreateAssetTxFee:      json.Uint64(ic.f.fee(TxTypeCreateAsset)),
+		CreateSubnetTxFee:     json.Uint64(ic.f.fee(client.TxTypeCreateSubnet)),
+		CreateBlockchainTxFee: json.Uint64(ic.f.fee(client.TxTypeCreateBlockchain)),
+	}, nil
+}
+
+func (ic *infoClient) Uptime(context.Context) (*api_info.UptimeResponse, error) {
+	if err := ic.f.read("Uptime"); err != nil {
+		return nil, err
+	}
+	return &api_info.UptimeResponse{RewardingStakePercentage: 100, WeightedAveragePercentage: 100}, nil
+}
+
+func (ic *infoClient) GetVMs(context.Context) (map[ids.ID][]string, error) {
+	if err := ic.f.read("GetVMs"); err != nil {
+		return nil, err
+	}
+	ic.f.mu.Lock()
+	defer ic.f.mu.Unlock()
+	vms := make(map[ids.ID][]string)
+	for _, b := range ic.f.blockchains {
+		vms[b.VMID] = []string{}
+	}
+	return vms, nil
+}
+
+// keyStore is not faked: the fake holds no keystore users.
+type keyStore struct{}
+
+func (keyStore) Client() api_keystore.Client { return nil }
+
+// pClient serves the platformvm API calls the client and commands read
+// directly; the embedded (nil) client panics on any other call.
+type pClient struct {
+	platformvm.Client
+	f *Fake
+}
+
+func (pc *pClient) GetHeight(context.Context) (uint64, error) {
+	if err := pc.f.read("GetHeight"); err != nil {
+		return 0, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	return uint64(len(pc.f.txs)), nil
+}
+
+// GetMinStake returns the genesis minimum stakes of the fake network.
+func (pc *pClient) GetMinStake(context.Context) (uint64, uint64, error) {
+	if err := pc.f.read("GetMinStake"); err != nil {
+		return 0, 0, err
+	}
+	cfg := genesis.GetStakingConfig(pc.f.networkID)
+	return cfg.MinValidatorStake, cfg.MinDelegatorStake, nil
+}
+
+// GetCurrentSupply returns the AVAX the fake holds: balances and primary
+// network stakes.
+func (pc *pClient) GetCurrentSupply(context.Context) (uint64, error) {
+	if err := pc.f.read("GetCurrentSupply"); err != nil {
+		return 0, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	supply := uint64(0)
+	for _, balances := range []map[ids.ShortID]uint64{pc.f.balances, pc.f.xBalances} {
+		for _, b := range balances {
+			supply += b
+		}
+	}
+	for _, v := range pc.f.validators[constants.PrimaryNetworkID] {
+		supply += v.Weight
+	}
+	return supply, nil
+}
+
+func (pc *pClient) GetSubnets(_ context.Context, subnetIDs []ids.ID) ([]platformvm.APISubnet, error) {
+	if err := pc.f.read("GetSubnets"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	if len(subnetIDs) == 0 {
+		for subnetID := range pc.f.subnets {
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+		sort.Slice(subnetIDs, func(i, j int) bool { return subnetIDs[i].String() < subnetIDs[j].String() })
+	}
+	subnets := []platformvm.APISubnet{}
+	for _, subnetID := range subnetIDs {
+		owner, ok := pc.f.subnets[subnetID]
+		if !ok {
+			continue
+		}
+		addr, err := key.FormatPAddress(pc.f.networkID, owner)
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, platformvm.APISubnet{ID: subnetID, ControlKeys: []string{addr}, Threshold: 1})
+	}
+	return subnets, nil
+}
+
+func (pc *pClient) GetStakingAssetID(ctx context.Context, subnetID ids.ID) (ids.ID, error) {
+	if err := pc.f.read("GetStakingAssetID"); err != nil {
+		return ids.Empty, err
+	}
+	a, err := pc.f.StakingAsset(ctx, subnetID)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return a.ID, nil
+}
+
+func (pc *pClient) GetBlockchains(context.Context) ([]platformvm.APIBlockchain, error) {
+	if err := pc.f.read("GetBlockchains"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	bs := []platformvm.APIBlockchain{}
+	for _, b := range pc.f.blockchains {
+		bs = append(bs, platformvm.APIBlockchain{ID: b.ID, Name: b.Name, SubnetID: b.SubnetID, VMID: b.VMID})
+	}
+	sort.Slice(bs, func(i, j int) bool { return bs[i].ID.String() < bs[j].ID.String() })
+	return bs, nil
+}
+
+func (pc *pClient) GetBlockchainStatus(_ context.Context, blockchainID string) (pstatus.BlockchainStatus, error) {
+	if err := pc.f.read("GetBlockchainStatus"); err != nil {
+		return 0, err
+	}
+	id, err := ids.FromString(blockchainID)
+	if err != nil {
+		return 0, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	if _, ok := pc.f.blockchains[id]; !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownBlockchain, id)
+	}
+	return pstatus.Validating, nil
+}
+
+// x fakes the X-Chain AVAX balances; assets are not kept.
+type x struct{ f *Fake }
+
+func (xc *x) Client() avm.Client { return nil }
+
+func (xc *x) Balance(_ context.Context, k key.Key) (uint64, error) {
+	if err := xc.f.read("XBalance"); err != nil {
+		return 0, err
+	}
+	xc.f.mu.Lock()
+	defer xc.f.mu.Unlock()
+	return available(xc.f.xBalances, k), nil
+}
+
+func (xc *x) CreateAssetFee(context.Context) (uint64, error) {
+	if err := xc.f.read("CreateAssetFee"); err != nil {
+		return 0, err
+	}
+	xc.f.mu.Lock()
+	defer xc.f.mu.Unlock()
+	return xc.f.fee(TxTypeCreateAsset), nil
+}
+
+func (xc *x) CreateAsset(
+	ctx context.Context,
+	k key.Key,
+	_ string,
+	_ string,
+	_ uint8,
+	_ *avm.InitialState,
+	opts ...client.OpOption,
+) (ids.ID, time.Duration, error) {
+	return xc.f.issue(ctx, "CreateAsset", TxTypeCreateAsset, xc.f.xBalances, k, 0, opts, func(ids.ID) error { return nil })
+}
@@ -0,0 +1,371 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clienttest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	internal_platformvm "github.com/ava-labs/subnet-cli/internal/platformvm"
+)
+
+var _ client.P = &p{}
+
+type p struct{ f *Fake }
+
+func (pc *p) Client() platformvm.Client            { return &pClient{f: pc.f} }
+func (pc *p) Checker() internal_platformvm.Checker { return checker{} }
+
+func (pc *p) Balance(_ context.Context, k key.Key) (uint64, error) {
+	if err := pc.f.read("Balance"); err != nil {
+		return 0, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	return available(pc.f.balances, k), nil
+}
+
+func (pc *p) CreateSubnet(ctx context.Context, k key.Key, opts ...client.OpOption) (ids.ID, time.Duration, error) {
+	return pc.f.issue(ctx, "CreateSubnet", client.TxTypeCreateSubnet, pc.f.balances, k, 0, opts, func(txID ids.ID) error {
+		pc.f.addSubnet(txID, k.Addresses()[0])
+		return nil
+	})
+}
+
+func (pc *p) AddValidator(
+	ctx context.Context,
+	k key.Key,
+	nodeID ids.ShortID,
+	start time.Time,
+	end time.Time,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	op := client.ApplyOpts(opts)
+	_, took, err := pc.f.issue(ctx, "AddValidator", client.TxTypeAddValidator, pc.f.balances, k, op.StakeAmount(), opts, func(txID ids.ID) error {
+		if _, ok := pc.f.validators[constants.PrimaryNetworkID][nodeID]; ok {
+			return fmt.Errorf("%w: %s", client.ErrAlreadyValidator, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		pc.f.validators[constants.PrimaryNetworkID][nodeID] = &client.Validator{
+			TxID:   txID,
+			NodeID: nodeID,
+			Start:  start,
+			End:    end,
+			Weight: op.StakeAmount(),
+		}
+		return nil
+	})
+	return took, err
+}
+
+func (pc *p) AddSubnetValidator(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	nodeID ids.ShortID,
+	start time.Time,
+	end time.Time,
+	weight uint64,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	_, took, err := pc.f.issue(ctx, "AddSubnetValidator", client.TxTypeAddSubnetValidator, pc.f.balances, k, 0, opts, func(txID ids.ID) error {
+		if _, ok := pc.f.subnets[subnetID]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSubnet, subnetID)
+		}
+		primary, ok := pc.f.validators[constants.PrimaryNetworkID][nodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s", client.ErrNotValidatingPrimaryNetwork, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		if start.Before(primary.Start) || end.After(primary.End) {
+			return fmt.Errorf("%w: %s - %s outside the primary network period", client.ErrInvalidSubnetValidatePeriod, start, end)
+		}
+		if _, ok := pc.f.validators[subnetID][nodeID]; ok {
+			return fmt.Errorf("%w: %s", client.ErrAlreadySubnetValidator, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		pc.f.validators[subnetID][nodeID] = &client.Validator{
+			TxID:   txID,
+			NodeID: nodeID,
+			Start:  start,
+			End:    end,
+			Weight: weight,
+		}
+		return nil
+	})
+	return took, err
+}
+
+func (pc *p) RemoveSubnetValidator(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	nodeID ids.ShortID,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	_, took, err := pc.f.issue(ctx, "RemoveSubnetValidator", client.TxTypeRemoveSubnetValidator, pc.f.balances, k, 0, opts, func(ids.ID) error {
+		if _, ok := pc.f.subnets[subnetID]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSubnet, subnetID)
+		}
+		if _, ok := pc.f.validators[subnetID][nodeID]; !ok {
+			return fmt.Errorf("%w: %s", client.ErrValidatorNotFound, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		delete(pc.f.validators[subnetID], nodeID)
+		return nil
+	})
+	return took, err
+}
+
+func (pc *p) CreateBlockchain(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	chainName string,
+	vmID ids.ID,
+	vmGenesis []byte,
+	opts ...client.OpOption,
+) (ids.ID, time.Duration, error) {
+	return pc.f.issue(ctx, "CreateBlockchain", client.TxTypeCreateBlockchain, pc.f.balances, k, 0, opts, func(txID ids.ID) error {
+		if _, ok := pc.f.subnets[subnetID]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSubnet, subnetID)
+		}
+		pc.f.blockchains[txID] = &client.Blockchain{
+			ID:       txID,
+			SubnetID: subnetID,
+			Name:     chainName,
+			VMID:     vmID,
+			Genesis:  vmGenesis,
+		}
+		return nil
+	})
+}
+
+// validator returns the validator [nodeID] of [subnetID] if it is
+// [pending] (or current otherwise).
+func (pc *p) validator(method string, subnetID ids.ID, nodeID ids.ShortID, pending bool) (time.Time, time.Time, error) {
+	if err := pc.f.read(method); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	v, ok := pc.f.validators[subnetID][nodeID]
+	if !ok || time.Now().Before(v.Start) != pending {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", client.ErrValidatorNotFound, nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	return v.Start, v.End, nil
+}
+
+func (pc *p) GetValidator(_ context.Context, subnetID ids.ID, nodeID ids.ShortID) (time.Time, time.Time, error) {
+	return pc.validator("GetValidator", subnetID, nodeID, false)
+}
+
+func (pc *p) GetPendingValidator(_ context.Context, subnetID ids.ID, nodeID ids.ShortID) (time.Time, time.Time, error) {
+	return pc.validator("GetPendingValidator", subnetID, nodeID, true)
+}
+
+func (pc *p) GetValidatorWeights(_ context.Context, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	if err := pc.f.read("GetValidatorWeights"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	now := time.Now()
+	ws := make(map[ids.ShortID]uint64)
+	for nodeID, v := range pc.f.validators[subnetID] {
+		if !now.Before(v.Start) {
+			ws[nodeID] = v.Weight
+		}
+	}
+	return ws, nil
+}
+
+func (pc *p) GetBLSPublicKeys(context.Context) (map[ids.ShortID]string, error) {
+	if err := pc.f.read("GetBLSPublicKeys"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	keys := make(map[ids.ShortID]string, len(pc.f.blsKeys))
+	for nodeID, pk := range pc.f.blsKeys {
+		keys[nodeID] = pk
+	}
+	return keys, nil
+}
+
+func (pc *p) GetValidators(_ context.Context, subnetID ids.ID) ([]client.Validator, error) {
+	if err := pc.f.read("GetValidators"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	now := time.Now()
+	vs := []client.Validator{}
+	for _, v := range pc.f.sortedValidators(subnetID) {
+		cv := *v
+		cv.Pending = now.Before(v.Start)
+		vs = append(vs, cv)
+	}
+	return vs, nil
+}
+
+func (pc *p) GetBlockchain(_ context.Context, blockchainID ids.ID) (*client.Blockchain, error) {
+	if err := pc.f.read("GetBlockchain"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	b, ok := pc.f.blockchains[blockchainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBlockchain, blockchainID)
+	}
+	cb := *b
+	return &cb, nil
+}
+
+func (pc *p) GetValidatorFee(context.Context) (*client.ValidatorFee, error) {
+	if err := pc.f.read("GetValidatorFee"); err != nil {
+		return nil, err
+	}
+	fee := pc.f.validatorFee
+	return &fee, nil
+}
+
+// ConvertSubnetToL1 replaces the validators of [subnetID] with the L1
+// validators [validators].
+func (pc *p) ConvertSubnetToL1(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	chainID ids.ID,
+	address []byte,
+	validators []*codec.ConvertSubnetToL1Validator,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	balance := uint64(0)
+	for _, v := range validators {
+		balance += v.Balance
+	}
+	_, took, err := pc.f.issue(ctx, "ConvertSubnetToL1", client.TxTypeConvertSubnetToL1, pc.f.balances, k, balance, opts, func(txID ids.ID) error {
+		if _, ok := pc.f.subnets[subnetID]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSubnet, subnetID)
+		}
+		if _, ok := pc.f.l1Managers[subnetID]; ok {
+			return fmt.Errorf("%w: %s is already an L1", ErrUnknownSubnet, subnetID)
+		}
+		pc.f.l1Managers[subnetID] = l1Manager{chainID: chainID, address: address}
+		pc.f.validators[subnetID] = make(map[ids.ShortID]*client.Validator, len(validators))
+		now := time.Now()
+		for i, v := range validators {
+			nodeID, err := ids.ToShortID(v.NodeID)
+			if err != nil {
+				return err
+			}
+			validationID := codec.L1ValidationID(subnetID, uint32(i))
+			pc.f.l1Validators[validationID] = &client.L1Validator{
+				ValidationID: validationID,
+				SubnetID:     subnetID,
+				NodeID:       nodeID,
+				Weight:       v.Weight,
+				Balance:      v.Balance,
+				StartTime:    now,
+			}
+			pc.f.validators[subnetID][nodeID] = &client.Validator{TxID: txID, NodeID: nodeID, Start: now, Weight: v.Weight}
+		}
+		return nil
+	})
+	return took, err
+}
+
+func (pc *p) GetL1Validator(_ context.Context, validationID ids.ID) (*client.L1Validator, error) {
+	if err := pc.f.read("GetL1Validator"); err != nil {
+		return nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	v, ok := pc.f.l1Validators[validationID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", client.ErrValidatorNotFound, validationID)
+	}
+	cv := *v
+	return &cv, nil
+}
+
+func (pc *p) GetL1Manager(_ context.Context, subnetID ids.ID) (ids.ID, []byte, error) {
+	if err := pc.f.read("GetL1Manager"); err != nil {
+		return ids.Empty, nil, err
+	}
+	pc.f.mu.Lock()
+	defer pc.f.mu.Unlock()
+	m, ok := pc.f.l1Managers[subnetID]
+	if !ok {
+		return ids.Empty, nil, fmt.Errorf("%w: %s", client.ErrNotL1, subnetID)
+	}
+	return m.chainID, m.address, nil
+}
+
+// RegisterL1Validator only charges the fee and [balance]: the fake does not
+// decode the Warp message, so the validator set does not change.
+func (pc *p) RegisterL1Validator(
+	ctx context.Context,
+	k key.Key,
+	balance uint64,
+	_ [96]byte,
+	_ []byte,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	_, took, err := pc.f.issue(ctx, "RegisterL1Validator", client.TxTypeRegisterL1Validator, pc.f.balances, k, balance, opts, func(ids.ID) error { return nil })
+	return took, err
+}
+
+// SetL1ValidatorWeight only charges the fee: the fake does not decode the
+// Warp message, so the validator set does not change.
+func (pc *p) SetL1ValidatorWeight(
+	ctx context.Context,
+	k key.Key,
+	_ []byte,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	_, took, err := pc.f.issue(ctx, "SetL1ValidatorWeight", client.TxTypeSetL1ValidatorWeight, pc.f.balances, k, 0, opts, func(ids.ID) error { return nil })
+	return took, err
+}
+
+func (pc *p) IncreaseL1ValidatorBalance(
+	ctx context.Context,
+	k key.Key,
+	validationID ids.ID,
+	balance uint64,
+	opts ...client.OpOption,
+) (time.Duration, error) {
+	_, took, err := pc.f.issue(ctx, "IncreaseL1ValidatorBalance", client.TxTypeIncreaseL1ValidatorBalance, pc.f.balances, k, balance, opts, func(ids.ID) error {
+		v, ok := pc.f.l1Validators[validationID]
+		if !ok {
+			return fmt.Errorf("%w: %s", client.ErrValidatorNotFound, validationID)
+		}
+		v.Balance += balance
+		return nil
+	})
+	return took, err
+}
+
+// checker accepts every tx immediately, since the fake accepts txs when they
+// are issued.
+type checker struct{}
+
+func (checker) PollTx(context.Context, ids.ID, pstatus.Status) (time.Duration, error) {
+	return 0, nil
+}
+
+func (checker) PollSubnet(context.Context, ids.ID) (time.Duration, error) {
+	return 0, nil
+}
+
+func (checker) PollBlockchain(context.Context, ...internal_platformvm.OpOption) (time.Duration, error) {
+	return 0, nil
+}
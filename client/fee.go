@@ -0,0 +1,279 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	api_info "github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/gorilla/rpc/v2/json2"
+	"go.uber.org/zap"
+)
+
+var ErrUnknownTxType = errors.New("unknown tx type")
+
+// TxType identifies the kind of P-Chain transaction a fee is computed for.
+type TxType string
+
+const (
+	TxTypeCreateSubnet       TxType = "CreateSubnetTx"
+	TxTypeCreateBlockchain   TxType = "CreateBlockchainTx"
+	TxTypeAddValidator       TxType = "AddValidatorTx"
+	TxTypeAddSubnetValidator TxType = "AddSubnetValidatorTx"
+	TxTypeBase               TxType = "BaseTx"
+	// only exists since Banff, with the fee of the other subnet txs
+	TxTypeRemoveSubnetValidator TxType = "RemoveSubnetValidatorTx"
+	// the L1 txs only exist since Etna, so they have no static fee
+	TxTypeConvertSubnetToL1          TxType = "ConvertSubnetToL1Tx"
+	TxTypeRegisterL1Validator        TxType = "RegisterL1ValidatorTx"
+	TxTypeSetL1ValidatorWeight       TxType = "SetL1ValidatorWeightTx"
+	TxTypeIncreaseL1ValidatorBalance TxType = "IncreaseL1ValidatorBalanceTx"
+)
+
+// FeeCalculator computes the fee (in nano-AVAX) the P-Chain charges for a
+// transaction of the given type and (signed) size in bytes. A zero size
+// selects the typical size for the type.
+type FeeCalculator interface {
+	Fee(ctx context.Context, txType TxType, size int) (uint64, error)
+}
+
+var (
+	_ FeeCalculator = &staticFeeCalculator{}
+	_ FeeCalculator = &dynamicFeeCalculator{}
+	_ FeeCalculator = &feeCalculator{}
+)
+
+// staticFeeCalculator applies the pre-Etna fixed fee schedule reported by
+// "info.getTxFee". The schedule is fetched once and reused.
+type staticFeeCalculator struct {
+	info api_info.Client
+
+	mu   sync.Mutex
+	resp *api_info.GetTxFeeResponse
+}
+
+func NewStaticFeeCalculator(info api_info.Client) FeeCalculator {
+	return &staticFeeCalculator{info: info}
+}
+
+func (s *staticFeeCalculator) Fee(ctx context.Context, txType TxType, _ int) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resp == nil {
+		resp, err := s.info.GetTxFee(ctx)
+		if err != nil {
+			return 0, err
+		}
+		s.resp = resp
+	}
+	switch txType {
+	case TxTypeCreateSubnet:
+		return uint64(s.resp.CreateSubnetTxFee), nil
+	case TxTypeCreateBlockchain:
+		return uint64(s.resp.CreateBlockchainTxFee), nil
+	case TxTypeAddValidator:
+		// ref. https://docs.avax.network/learn/platform-overview/transaction-fees/#fee-schedule
+		return 0, nil
+	case TxTypeAddSubnetValidator, TxTypeRemoveSubnetValidator, TxTypeBase:
+		return uint64(s.resp.TxFee), nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownTxType, txType)
+	}
+}
+
+// gas dimensions, ref. "vms/components/gas.Dimensions".
+const (
+	bandwidth = iota
+	dbRead
+	dbWrite
+	compute
+	numDimensions
+)
+
+type dimensions [numDimensions]json.Uint64
+
+// intrinsic is the rough per-tx-type complexity (excluding bandwidth) of a
+// transaction with a single input, output and signature.
+var intrinsic = map[TxType]dimensions{
+	TxTypeCreateSubnet:          {0, 2, 3, 200},
+	TxTypeCreateBlockchain:      {0, 3, 3, 200},
+	TxTypeAddValidator:          {0, 4, 4, 200},
+	TxTypeAddSubnetValidator:    {0, 4, 4, 400},
+	TxTypeRemoveSubnetValidator: {0, 4, 4, 400},
+	TxTypeBase:                  {0, 1, 2, 200},
+	// include the BLS verification of proofs of possession and Warp
+	// signatures
+	TxTypeConvertSubnetToL1:          {0, 3, 4, 1250},
+	TxTypeRegisterL1Validator:        {0, 3, 3, 1200},
+	TxTypeSetL1ValidatorWeight:       {0, 3, 3, 1050},
+	TxTypeIncreaseL1ValidatorBalance: {0, 2, 3, 200},
+}
+
+// typicalSize is the signed size in bytes used when the caller does not
+// know the actual size yet.
+var typicalSize = map[TxType]int{
+	TxTypeCreateSubnet:               400,
+	TxTypeCreateBlockchain:           1024,
+	TxTypeAddValidator:               600,
+	TxTypeAddSubnetValidator:         500,
+	TxTypeRemoveSubnetValidator:      450,
+	TxTypeBase:                       400,
+	TxTypeConvertSubnetToL1:          1200,
+	TxTypeRegisterL1Validator:        900,
+	TxTypeSetL1ValidatorWeight:       650,
+	TxTypeIncreaseL1ValidatorBalance: 450,
+}
+
+type feeConfig struct {
+	Weights dimensions `json:"weights"`
+}
+
+type feeState struct {
+	Price json.Uint64 `json:"price"`
+}
+
+// dynamicFeeCalculator prices transactions with the post-Etna dynamic fee
+// mechanism: fee = price * (complexity · weights).
+type dynamicFeeCalculator struct {
+	// url is the platform API endpoint
+	url string
+}
+
+func NewDynamicFeeCalculator(uri string) FeeCalculator {
+	return &dynamicFeeCalculator{url: strings.TrimSuffix(uri, "/") + "/ext/P"}
+}
+
+// maximum size of a fee API response
+const maxFeeResponseSize = 64 * 1024
+
+// sendRequest calls the platform API [method]. Unlike
+// "rpc.EndpointRequester", it decodes the JSON-RPC error of non-2xx
+// responses, so a missing method can be told apart from other failures.
+func (d *dynamicFeeCalculator) sendRequest(ctx context.Context, method string, reply interface{}) error {
+	body, err := json2.EncodeClientRequest("platform."+method, struct{}{})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ok := resp.StatusCode >= 200 && resp.StatusCode <= 299
+	err = json2.DecodeClientResponse(io.LimitReader(resp.Body, maxFeeResponseSize), reply)
+	var jerr *json2.Error
+	switch {
+	case errors.As(err, &jerr):
+		return jerr
+	case !ok:
+		return fmt.Errorf("received status code '%d'", resp.StatusCode)
+	}
+	return err
+}
+
+func (d *dynamicFeeCalculator) Fee(ctx context.Context, txType TxType, size int) (uint64, error) {
+	complexity, ok := intrinsic[txType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownTxType, txType)
+	}
+	if size <= 0 {
+		size = typicalSize[txType]
+	}
+	complexity[bandwidth] = json.Uint64(size)
+
+	cfg := new(feeConfig)
+	if err := d.sendRequest(ctx, "getFeeConfig", cfg); err != nil {
+		return 0, err
+	}
+	state := new(feeState)
+	if err := d.sendRequest(ctx, "getFeeState", state); err != nil {
+		return 0, err
+	}
+	gas := uint64(0)
+	for i := 0; i < numDimensions; i++ {
+		gas += uint64(complexity[i]) * uint64(cfg.Weights[i])
+	}
+	return gas * uint64(state.Price), nil
+}
+
+// feeCalculator uses the dynamic fee mechanism when the node supports it and
+// falls back to the static fee schedule otherwise. The choice is cached once
+// the node answered; a failed detection (e.g., a timeout) is retried by the
+// next call instead of pinning the static fees.
+type feeCalculator struct {
+	static  FeeCalculator
+	dynamic FeeCalculator
+
+	mu     sync.Mutex
+	active FeeCalculator
+}
+
+// NewFeeCalculator returns a FeeCalculator that detects which fee mechanism
+// the node at [uri] implements.
+func NewFeeCalculator(uri string, info api_info.Client) FeeCalculator {
+	return &feeCalculator{
+		static:  NewStaticFeeCalculator(info),
+		dynamic: NewDynamicFeeCalculator(uri),
+	}
+}
+
+func (f *feeCalculator) Fee(ctx context.Context, txType TxType, size int) (uint64, error) {
+	active, err := f.detect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return active.Fee(ctx, txType, size)
+}
+
+func (f *feeCalculator) detect(ctx context.Context) (FeeCalculator, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active != nil {
+		return f.active, nil
+	}
+	fee, err := f.dynamic.Fee(ctx, TxTypeBase, 0)
+	switch {
+	case err == nil:
+		zap.L().Info("using dynamic fees", zap.Uint64("baseTxFee", fee))
+		f.active = f.dynamic
+	case isMethodNotFound(err):
+		zap.L().Debug("dynamic fees not supported; using static fees", zap.Error(err))
+		f.active = f.static
+	default:
+		return nil, fmt.Errorf("failed to detect the fee mechanism: %w", err)
+	}
+	return f.active, nil
+}
+
+// isMethodNotFound returns true if [err] is the response of a node that does
+// not implement the requested API method, as opposed to a transient failure.
+func isMethodNotFound(err error) bool {
+	var jerr *json2.Error
+	if !errors.As(err, &jerr) {
+		return false
+	}
+	switch jerr.Code {
+	case json2.E_NO_METHOD:
+		return true
+	case json2.E_SERVER:
+		// the node's JSON-RPC server reports unknown methods and services
+		// as server errors (ref. "gorilla/rpc/v2.serviceMap.get")
+		return strings.HasPrefix(jerr.Message, "rpc: can't find method") ||
+			strings.HasPrefix(jerr.Message, "rpc: can't find service")
+	}
+	return false
+}
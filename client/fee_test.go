@@ -0,0 +1,233 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	api_info "github.com/ava-labs/avalanchego/api/info"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+func TestIsMethodNotFound(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		err error
+		exp bool
+	}{
+		{err: nil},
+		{err: errors.New("received status code '400'")},
+		{err: errors.New(`rpc: can't find method "platform.getFeeConfig"`)},
+		{err: context.DeadlineExceeded},
+		{err: &json2.Error{Code: json2.E_NO_METHOD, Message: "method not found"}, exp: true},
+		{err: &json2.Error{Code: json2.E_SERVER, Message: `rpc: can't find method "platform.getFeeConfig"`}, exp: true},
+		{err: &json2.Error{Code: json2.E_SERVER, Message: `rpc: can't find service "platform.getFeeConfig"`}, exp: true},
+		{err: fmt.Errorf("wrapped: %w", &json2.Error{Code: json2.E_NO_METHOD}), exp: true},
+		{err: &json2.Error{Code: json2.E_SERVER, Message: "database closed"}},
+		{err: &json2.Error{Code: json2.E_INVALID_REQ, Message: `rpc: can't find method "platform.getFeeConfig"`}},
+		{err: &json2.Error{Code: json2.E_BAD_PARAMS, Message: "invalid params"}},
+	}
+	for i, tv := range tt {
+		if v := isMethodNotFound(tv.err); v != tv.exp {
+			t.Fatalf("#%d: %v expected %v, got %v", i, tv.err, tv.exp, v)
+		}
+	}
+}
+
+// fakeNode answers the fee APIs of a node with or without dynamic fees.
+type fakeNode struct {
+	mu      sync.Mutex
+	dynamic bool
+	down    bool
+	calls   map[string]int
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := new(struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	})
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls[req.Method]++
+	if n.down {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var result interface{}
+	switch {
+	case r.URL.Path == "/ext/info" && req.Method == "info.getTxFee":
+		result = &api_info.GetTxFeeResponse{
+			TxFee:                 1_000_000,
+			CreateSubnetTxFee:     1_000_000_000,
+			CreateBlockchainTxFee: 1_000_000_000,
+		}
+	case r.URL.Path == "/ext/P" && req.Method == "platform.getFeeConfig" && n.dynamic:
+		result = &feeConfig{Weights: dimensions{1, 1000, 1000, 4}}
+	case r.URL.Path == "/ext/P" && req.Method == "platform.getFeeState" && n.dynamic:
+		result = &feeState{Price: 2}
+	default:
+		// as reported by the node's JSON-RPC server
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error": &json2.Error{
+				Code:    json2.E_SERVER,
+				Message: fmt.Sprintf("rpc: can't find method %q", req.Method),
+			},
+			"id": req.ID,
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"result":  result,
+		"id":      req.ID,
+	})
+}
+
+func (n *fakeNode) set(dynamic bool, down bool) {
+	n.mu.Lock()
+	n.dynamic, n.down = dynamic, down
+	n.mu.Unlock()
+}
+
+func (n *fakeNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+func TestFeeCalculator(t *testing.T) {
+	t.Parallel()
+
+	// gas = 400 (bandwidth) + 1*1000 (reads) + 2*1000 (writes) + 200*4 (compute)
+	dynamicBaseFee := uint64(4200 * 2)
+
+	tt := []struct {
+		dynamic  bool
+		down     bool
+		expFee   uint64
+		expErr   bool
+		expInfos int
+	}{
+		{dynamic: false, expFee: 1_000_000_000, expInfos: 1},
+		{dynamic: true, expFee: dynamicBaseFee},
+		{down: true, expErr: true},
+	}
+	for i, tv := range tt {
+		n := &fakeNode{dynamic: tv.dynamic, down: tv.down, calls: make(map[string]int)}
+		srv := httptest.NewServer(n)
+
+		fees := NewFeeCalculator(srv.URL, api_info.NewClient(srv.URL))
+		txType, size := TxTypeCreateSubnet, 0
+		if tv.dynamic {
+			txType, size = TxTypeBase, 400
+		}
+		fee, err := fees.Fee(context.Background(), txType, size)
+		srv.Close()
+		if (err != nil) != tv.expErr {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if fee != tv.expFee {
+			t.Fatalf("#%d: expected fee %d, got %d", i, tv.expFee, fee)
+		}
+		if v := n.count("info.getTxFee"); v != tv.expInfos {
+			t.Fatalf("#%d: expected %d info.getTxFee calls, got %d", i, tv.expInfos, v)
+		}
+	}
+}
+
+func TestFeeCalculatorSwitch(t *testing.T) {
+	t.Parallel()
+
+	n := &fakeNode{down: true, calls: make(map[string]int)}
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	fees := NewFeeCalculator(srv.URL, api_info.NewClient(srv.URL))
+	ctx := context.Background()
+
+	// a failed detection must not pin the static fees
+	if _, err := fees.Fee(ctx, TxTypeBase, 400); err == nil {
+		t.Fatal("expected an error from an unavailable node")
+	}
+	n.set(true, false)
+	fee, err := fees.Fee(ctx, TxTypeBase, 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 4200*2 {
+		t.Fatalf("expected the dynamic fee, got %d", fee)
+	}
+	if v := n.count("info.getTxFee"); v != 0 {
+		t.Fatalf("expected no info.getTxFee calls, got %d", v)
+	}
+
+	// once detected, the node is not probed again
+	configs := n.count("platform.getFeeConfig")
+	if _, err := fees.Fee(ctx, TxTypeCreateSubnet, 0); err != nil {
+		t.Fatal(err)
+	}
+	if v := n.count("platform.getFeeConfig"); v != configs+1 {
+		t.Fatalf("expected a single getFeeConfig call, got %d", v-configs)
+	}
+
+	// a node without dynamic fees falls back to the static schedule
+	n.set(false, false)
+	static := NewFeeCalculator(srv.URL, api_info.NewClient(srv.URL))
+	for i := 0; i < 2; i++ {
+		fee, err = static.Fee(ctx, TxTypeAddSubnetValidator, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee != 1_000_000 {
+			t.Fatalf("expected the static fee, got %d", fee)
+		}
+	}
+	if v := n.count("info.getTxFee"); v != 1 {
+		t.Fatalf("expected a single info.getTxFee call, got %d", v)
+	}
+}
+
+func TestDynamicFeeCalculatorErrors(t *testing.T) {
+	t.Parallel()
+
+	n := &fakeNode{calls: make(map[string]int)}
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+
+	// the JSON-RPC error of a non-2xx response is preserved
+	_, err := NewDynamicFeeCalculator(srv.URL).Fee(context.Background(), TxTypeBase, 0)
+	var jerr *json2.Error
+	if !errors.As(err, &jerr) || jerr.Code != json2.E_SERVER {
+		t.Fatalf("expected a JSON-RPC server error, got %v", err)
+	}
+
+	// a non-JSON error body only reports the status
+	n.set(true, true)
+	_, err = NewDynamicFeeCalculator(srv.URL).Fee(context.Background(), TxTypeBase, 0)
+	if err == nil || errors.As(err, &jerr) || isMethodNotFound(err) {
+		t.Fatalf("expected a status code error, got %v", err)
+	}
+
+	_, err = NewDynamicFeeCalculator(srv.URL).Fee(context.Background(), TxType("FooTx"), 0)
+	if !errors.Is(err, ErrUnknownTxType) {
+		t.Fatalf("expected %v, got %v", ErrUnknownTxType, err)
+	}
+}
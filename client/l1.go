@@ -0,0 +1,367 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+var ErrNotL1 = errors.New("subnet not converted to an L1")
+
+// ValidatorFee is the continuous fee (ACP-77) each L1 validator pays from
+// its balance, as of the last accepted P-Chain block.
+type ValidatorFee struct {
+	// Price is the current fee in nAVAX per second per validator.
+	Price uint64
+	// MinPrice is the fee while the L1 validators are at most [Target].
+	MinPrice uint64
+	// Target is the number of L1 validators above which the price rises.
+	Target uint64
+	// Capacity is the maximum number of active L1 validators.
+	Capacity uint64
+}
+
+// Runtime returns how long [balance] pays the fee of one validator at the
+// current price.
+func (f *ValidatorFee) Runtime(balance uint64) time.Duration {
+	if f.Price == 0 {
+		return 0
+	}
+	secs := balance / f.Price
+	if secs > uint64(1<<63-1)/uint64(time.Second) {
+		secs = uint64(1<<63-1) / uint64(time.Second)
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// Cost returns the fee of one validator over [d] at the current price.
+func (f *ValidatorFee) Cost(d time.Duration) uint64 {
+	return f.Price * uint64(d/time.Second)
+}
+
+// GetValidatorFee returns the current L1 validator fee. Nodes before Etna
+// do not implement it.
+func (pc *p) GetValidatorFee(ctx context.Context) (*ValidatorFee, error) {
+	var cfg struct {
+		Capacity json.Uint64 `json:"capacity"`
+		Target   json.Uint64 `json:"target"`
+		MinPrice json.Uint64 `json:"minPrice"`
+	}
+	if err := pc.requester.SendRequest(ctx, "getValidatorFeeConfig", struct{}{}, &cfg); err != nil {
+		return nil, err
+	}
+	var state struct {
+		Price json.Uint64 `json:"price"`
+	}
+	if err := pc.requester.SendRequest(ctx, "getValidatorFeeState", struct{}{}, &state); err != nil {
+		return nil, err
+	}
+	return &ValidatorFee{
+		Price:    uint64(state.Price),
+		MinPrice: uint64(cfg.MinPrice),
+		Target:   uint64(cfg.Target),
+		Capacity: uint64(cfg.Capacity),
+	}, nil
+}
+
+// ConvertSubnetToL1 converts [subnetID] to a sovereign L1 (ACP-77) whose
+// validator set is managed by the contract at [address] on [chainID],
+// starting with [validators]. The validator balances are burned with the
+// fee, and fund their continuous fees.
+func (pc *p) ConvertSubnetToL1(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	chainID ids.ID,
+	address []byte,
+	validators []*codec.ConvertSubnetToL1Validator,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	if subnetID == ids.Empty || chainID == ids.Empty {
+		return 0, ErrEmptyID
+	}
+	fee, err := pc.fees.Fee(ctx, TxTypeConvertSubnetToL1, 0)
+	if err != nil {
+		return 0, err
+	}
+	burn := fee
+	for _, v := range validators {
+		burn += v.Balance
+	}
+	codec.SortL1Validators(validators)
+
+	zap.L().Info("converting subnet to L1",
+		zap.String("subnetId", subnetID.String()),
+		zap.String("chainId", chainID.String()),
+		zap.Int("validators", len(validators)),
+		zap.Uint64("fee", fee),
+		zap.Uint64("burn", burn),
+	)
+	ins, returnedOuts, _, signers, err := pc.stake(ctx, k, burn)
+	if err != nil {
+		return 0, err
+	}
+	subnetAuth, subnetSigners, err := pc.authorize(ctx, k, subnetID)
+	if err != nil {
+		return 0, err
+	}
+	signers = append(signers, subnetSigners)
+
+	utx := &codec.ConvertSubnetToL1Tx{
+		BaseTx: platformvm.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    pc.networkID,
+			BlockchainID: pc.pChainID,
+			Ins:          ins,
+			Outs:         returnedOuts,
+			Memo:         ret.memo,
+		}},
+		Subnet:     subnetID,
+		ChainID:    chainID,
+		Address:    address,
+		Validators: validators,
+		SubnetAuth: subnetAuth,
+	}
+	pTx := &platformvm.Tx{
+		UnsignedTx: utx,
+	}
+	if err := k.Sign(ctx, pTx, signers); err != nil {
+		return 0, err
+	}
+	if err := utx.SyntacticVerify(&snow.Context{
+		NetworkID: pc.networkID,
+		ChainID:   pc.pChainID,
+	}); err != nil {
+		return 0, err
+	}
+	if ret.dryMode {
+		return 0, nil
+	}
+
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue tx: %w", err)
+	}
+	return pc.checker.PollTx(ctx, txID, pstatus.Committed)
+}
+
+// L1Validator is the state of an L1 validator.
+type L1Validator struct {
+	ValidationID ids.ID
+	SubnetID     ids.ID
+	NodeID       ids.ShortID
+	Weight       uint64
+	// Balance is the nAVAX left for the continuous fee; the validator is
+	// inactive once it runs out.
+	Balance   uint64
+	StartTime time.Time
+	// MinNonce is the lowest nonce a weight change of the validator must
+	// use.
+	MinNonce uint64
+}
+
+// GetL1Validator returns the L1 validator [validationID]. Nodes before Etna
+// do not implement it.
+func (pc *p) GetL1Validator(ctx context.Context, validationID ids.ID) (*L1Validator, error) {
+	var reply struct {
+		SubnetID  ids.ID      `json:"subnetID"`
+		NodeID    string      `json:"nodeID"`
+		Weight    json.Uint64 `json:"weight"`
+		Balance   json.Uint64 `json:"balance"`
+		StartTime json.Uint64 `json:"startTime"`
+		MinNonce  json.Uint64 `json:"minNonce"`
+	}
+	if err := pc.requester.SendRequest(ctx, "getL1Validator", &struct {
+		ValidationID ids.ID `json:"validationID"`
+	}{validationID}, &reply); err != nil {
+		return nil, err
+	}
+	nodeID, err := ids.ShortFromPrefixedString(reply.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return &L1Validator{
+		ValidationID: validationID,
+		SubnetID:     reply.SubnetID,
+		NodeID:       nodeID,
+		Weight:       uint64(reply.Weight),
+		Balance:      uint64(reply.Balance),
+		StartTime:    time.Unix(int64(reply.StartTime), 0),
+		MinNonce:     uint64(reply.MinNonce),
+	}, nil
+}
+
+// IncreaseL1ValidatorBalance burns [balance] into the continuous-fee
+// balance of the L1 validator [validationID]. Anyone can top up any
+// validator, so no subnet authorization is needed.
+func (pc *p) IncreaseL1ValidatorBalance(
+	ctx context.Context,
+	k key.Key,
+	validationID ids.ID,
+	balance uint64,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	if validationID == ids.Empty {
+		return 0, ErrEmptyID
+	}
+	fee, err := pc.fees.Fee(ctx, TxTypeIncreaseL1ValidatorBalance, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	zap.L().Info("increasing L1 validator balance",
+		zap.String("validationId", validationID.String()),
+		zap.Uint64("balance", balance),
+		zap.Uint64("fee", fee),
+	)
+	return pc.issueEtnaTx(ctx, k, fee+balance, func(base platformvm.BaseTx) etnaTx {
+		return &codec.IncreaseL1ValidatorBalanceTx{
+			BaseTx:       base,
+			ValidationID: validationID,
+			Balance:      balance,
+		}
+	}, opts...)
+}
+
+func (pc *p) GetL1Manager(ctx context.Context, subnetID ids.ID) (ids.ID, []byte, error) {
+	var reply struct {
+		ManagerChainID ids.ID `json:"managerChainID"`
+		ManagerAddress string `json:"managerAddress"`
+	}
+	if err := pc.requester.SendRequest(ctx, "getSubnet", &struct {
+		SubnetID ids.ID `json:"subnetID"`
+	}{subnetID}, &reply); err != nil {
+		return ids.Empty, nil, err
+	}
+	if reply.ManagerChainID == ids.Empty {
+		return ids.Empty, nil, fmt.Errorf("%w: %s", ErrNotL1, subnetID)
+	}
+	address, err := hex.DecodeString(strings.TrimPrefix(reply.ManagerAddress, "0x"))
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	return reply.ManagerChainID, address, nil
+}
+
+// RegisterL1Validator adds the L1 validator requested by the signed Warp
+// [message] of its validator manager, burning [balance] for its continuous
+// fee. [pop] proves possession of the BLS key in the message.
+func (pc *p) RegisterL1Validator(
+	ctx context.Context,
+	k key.Key,
+	balance uint64,
+	pop [96]byte,
+	message []byte,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	fee, err := pc.fees.Fee(ctx, TxTypeRegisterL1Validator, 0)
+	if err != nil {
+		return 0, err
+	}
+	zap.L().Info("registering L1 validator",
+		zap.Uint64("balance", balance),
+		zap.Uint64("fee", fee),
+	)
+	return pc.issueEtnaTx(ctx, k, fee+balance, func(base platformvm.BaseTx) etnaTx {
+		return &codec.RegisterL1ValidatorTx{
+			BaseTx:            base,
+			Balance:           balance,
+			ProofOfPossession: pop,
+			Message:           message,
+		}
+	}, opts...)
+}
+
+// SetL1ValidatorWeight sets the weight of the L1 validator in the signed
+// Warp [message] of its validator manager; a zero weight removes it, and
+// returns its remaining balance to its owner.
+func (pc *p) SetL1ValidatorWeight(
+	ctx context.Context,
+	k key.Key,
+	message []byte,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	fee, err := pc.fees.Fee(ctx, TxTypeSetL1ValidatorWeight, 0)
+	if err != nil {
+		return 0, err
+	}
+	zap.L().Info("setting L1 validator weight", zap.Uint64("fee", fee))
+	return pc.issueEtnaTx(ctx, k, fee, func(base platformvm.BaseTx) etnaTx {
+		return &codec.SetL1ValidatorWeightTx{
+			BaseTx:  base,
+			Message: message,
+		}
+	}, opts...)
+}
+
+// etnaTx is an Etna tx that only needs the inputs it burns to be signed.
+type etnaTx interface {
+	platformvm.UnsignedTx
+	SyntacticVerify(*snow.Context) error
+}
+
+// issueEtnaTx burns [burn] in the tx [build] returns for the base tx, and
+// issues it.
+func (pc *p) issueEtnaTx(
+	ctx context.Context,
+	k key.Key,
+	burn uint64,
+	build func(platformvm.BaseTx) etnaTx,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	ins, returnedOuts, _, signers, err := pc.stake(ctx, k, burn)
+	if err != nil {
+		return 0, err
+	}
+	utx := build(platformvm.BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    pc.networkID,
+		BlockchainID: pc.pChainID,
+		Ins:          ins,
+		Outs:         returnedOuts,
+		Memo:         ret.memo,
+	}})
+	pTx := &platformvm.Tx{
+		UnsignedTx: utx,
+	}
+	if err := k.Sign(ctx, pTx, signers); err != nil {
+		return 0, err
+	}
+	if err := utx.SyntacticVerify(&snow.Context{
+		NetworkID: pc.networkID,
+		ChainID:   pc.pChainID,
+	}); err != nil {
+		return 0, err
+	}
+	if ret.dryMode {
+		return 0, nil
+	}
+
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue tx: %w", err)
+	}
+	return pc.checker.PollTx(ctx, txID, pstatus.Committed)
+}
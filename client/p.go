@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -40,6 +41,7 @@ var (
 	ErrInvalidSubnetValidatePeriod = errors.New("invalid subnet validate period")
 	ErrInvalidValidatorData        = errors.New("invalid validator data")
 	ErrValidatorNotFound           = errors.New("validator not found")
+	ErrExpired                     = errors.New("operation expired")
 
 	// ref. "vms.platformvm".
 	ErrWrongTxType   = errors.New("wrong transaction type")
@@ -74,6 +76,15 @@ type P interface {
 		weight uint64,
 		opts ...OpOption,
 	) (took time.Duration, err error)
+	// RemoveSubnetValidator removes [nodeID] from the validators of the
+	// permissioned subnet [subnetID], which needs the Banff upgrade.
+	RemoveSubnetValidator(
+		ctx context.Context,
+		k key.Key,
+		subnetID ids.ID,
+		nodeID ids.ShortID,
+		opts ...OpOption,
+	) (took time.Duration, err error)
 	CreateBlockchain(
 		ctx context.Context,
 		key key.Key,
@@ -88,6 +99,54 @@ type P interface {
 		rsubnetID ids.ID,
 		nodeID ids.ShortID,
 	) (start time.Time, end time.Time, err error)
+	GetPendingValidator(
+		ctx context.Context,
+		rsubnetID ids.ID,
+		nodeID ids.ShortID,
+	) (start time.Time, end time.Time, err error)
+	GetValidatorWeights(
+		ctx context.Context,
+		rsubnetID ids.ID,
+	) (map[ids.ShortID]uint64, error)
+	GetBLSPublicKeys(ctx context.Context) (map[ids.ShortID]string, error)
+	GetValidators(ctx context.Context, rsubnetID ids.ID) ([]Validator, error)
+	// GetBlockchain returns the name, subnet, VM and genesis of a blockchain.
+	GetBlockchain(ctx context.Context, blockchainID ids.ID) (*Blockchain, error)
+	GetValidatorFee(ctx context.Context) (*ValidatorFee, error)
+	ConvertSubnetToL1(
+		ctx context.Context,
+		k key.Key,
+		subnetID ids.ID,
+		chainID ids.ID,
+		address []byte,
+		validators []*codec.ConvertSubnetToL1Validator,
+		opts ...OpOption,
+	) (took time.Duration, err error)
+	GetL1Validator(ctx context.Context, validationID ids.ID) (*L1Validator, error)
+	// GetL1Manager returns the chain and address of the validator manager
+	// of the L1 converted from [subnetID].
+	GetL1Manager(ctx context.Context, subnetID ids.ID) (chainID ids.ID, address []byte, err error)
+	RegisterL1Validator(
+		ctx context.Context,
+		k key.Key,
+		balance uint64,
+		pop [96]byte,
+		message []byte,
+		opts ...OpOption,
+	) (took time.Duration, err error)
+	SetL1ValidatorWeight(
+		ctx context.Context,
+		k key.Key,
+		message []byte,
+		opts ...OpOption,
+	) (took time.Duration, err error)
+	IncreaseL1ValidatorBalance(
+		ctx context.Context,
+		k key.Key,
+		validationID ids.ID,
+		balance uint64,
+		opts ...OpOption,
+	) (took time.Duration, err error)
 }
 
 type p struct {
@@ -97,9 +156,11 @@ type p struct {
 	assetID     ids.ID
 	pChainID    ids.ID
 
-	cli     platformvm.Client
-	info    api_info.Client
-	checker internal_platformvm.Checker
+	cli       platformvm.Client
+	requester rpc.EndpointRequester
+	info      api_info.Client
+	fees      FeeCalculator
+	checker   internal_platformvm.Checker
 }
 
 func (pc *p) Client() platformvm.Client            { return pc.cli }
@@ -122,11 +183,10 @@ func (pc *p) CreateSubnet(
 	ret := &Op{}
 	ret.applyOpts(opts)
 
-	fi, err := pc.info.GetTxFee(ctx)
+	createSubnetTxFee, err := pc.fees.Fee(ctx, TxTypeCreateSubnet, 0)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-	createSubnetTxFee := uint64(fi.CreateSubnetTxFee)
 
 	zap.L().Info("creating subnet",
 		zap.Bool("dryMode", ret.dryMode),
@@ -144,6 +204,7 @@ func (pc *p) CreateSubnet(
 			BlockchainID: pc.pChainID,
 			Ins:          ins,
 			Outs:         returnedOuts,
+			Memo:         ret.memo,
 		}},
 		Owner: &secp256k1fx.OutputOwners{
 			// [threshold] of [ownerAddrs] needed to manage this subnet
@@ -157,7 +218,7 @@ func (pc *p) CreateSubnet(
 	pTx := &platformvm.Tx{
 		UnsignedTx: utx,
 	}
-	if err := k.Sign(pTx, signers); err != nil {
+	if err := k.Sign(ctx, pTx, signers); err != nil {
 		return ids.Empty, 0, err
 	}
 	if err := utx.SyntacticVerify(&snow.Context{
@@ -173,7 +234,7 @@ func (pc *p) CreateSubnet(
 		return subnetID, 0, nil
 	}
 
-	txID, err := pc.cli.IssueTx(ctx, pTx.Bytes())
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
 	if err != nil {
 		return subnetID, 0, fmt.Errorf("failed to issue tx: %w", err)
 	}
@@ -185,6 +246,15 @@ func (pc *p) CreateSubnet(
 	return txID, took, err
 }
 
+// issueTx issues the tx [b] unless the operation expired (see
+// [Config.ExpireAt]).
+func (pc *p) issueTx(ctx context.Context, b []byte) (ids.ID, error) {
+	if exp := pc.cfg.ExpireAt; !exp.IsZero() && time.Now().After(exp) {
+		return ids.Empty, fmt.Errorf("%w at %s", ErrExpired, exp.Format(time.RFC3339))
+	}
+	return pc.cli.IssueTx(ctx, b)
+}
+
 func (pc *p) GetValidator(ctx context.Context, rsubnetID ids.ID, nodeID ids.ShortID) (start time.Time, end time.Time, err error) {
 	// If no [rsubnetID] is provided, just use the PrimaryNetworkID value.
 	subnetID := constants.PrimaryNetworkID
@@ -197,7 +267,98 @@ func (pc *p) GetValidator(ctx context.Context, rsubnetID ids.ID, nodeID ids.Shor
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
+	return findValidator(vs, nodeID)
+}
+
+// GetPendingValidator returns the validation period of a validator that has
+// been added but not yet started validating [rsubnetID].
+func (pc *p) GetPendingValidator(ctx context.Context, rsubnetID ids.ID, nodeID ids.ShortID) (start time.Time, end time.Time, err error) {
+	subnetID := constants.PrimaryNetworkID
+	if rsubnetID != ids.Empty {
+		subnetID = rsubnetID
+	}
+	vs, _, err := pc.Client().GetPendingValidators(ctx, subnetID, []ids.ShortID{nodeID})
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return findValidator(vs, nodeID)
+}
+
+// GetValidatorWeights returns the weight of every current validator of
+// [rsubnetID] (stake amount for the primary network).
+func (pc *p) GetValidatorWeights(ctx context.Context, rsubnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	subnetID := constants.PrimaryNetworkID
+	if rsubnetID != ids.Empty {
+		subnetID = rsubnetID
+	}
+	vs, err := pc.Client().GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	ws := make(map[ids.ShortID]uint64, len(vs))
+	for _, v := range vs {
+		va, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T %+v", ErrInvalidValidatorData, v, v)
+		}
+		nodeIDs, ok := va["nodeID"].(string)
+		if !ok {
+			return nil, ErrInvalidValidatorData
+		}
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDs, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		d, ok := va["weight"].(string)
+		if !ok {
+			d, ok = va["stakeAmount"].(string)
+		}
+		if !ok {
+			return nil, ErrInvalidValidatorData
+		}
+		w, err := strconv.ParseUint(d, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ws[nodeID] = w
+	}
+	return ws, nil
+}
+
+// GetBLSPublicKeys returns the registered BLS public key of every current
+// primary network validator that has one. Nodes that predate BLS
+// proof-of-possession never report a "signer" and are omitted.
+func (pc *p) GetBLSPublicKeys(ctx context.Context) (map[ids.ShortID]string, error) {
+	vs, err := pc.Client().GetCurrentValidators(ctx, constants.PrimaryNetworkID, nil)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[ids.ShortID]string)
+	for _, v := range vs {
+		va, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T %+v", ErrInvalidValidatorData, v, v)
+		}
+		nodeIDs, ok := va["nodeID"].(string)
+		if !ok {
+			return nil, ErrInvalidValidatorData
+		}
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDs, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := va["signer"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pk, ok := signer["publicKey"].(string); ok && pk != "" {
+			keys[nodeID] = pk
+		}
+	}
+	return keys, nil
+}
 
+func findValidator(vs []interface{}, nodeID ids.ShortID) (start time.Time, end time.Time, err error) {
 	// If the validator is not found, it will return a string record indicating
 	// that it was "unable to get mainnet validator record".
 	if len(vs) < 1 {
@@ -290,11 +451,10 @@ func (pc *p) AddSubnetValidator(
 		return 0, fmt.Errorf("%w (validate end %v expected <%v)", ErrInvalidSubnetValidatePeriod, end, validateEnd)
 	}
 
-	fi, err := pc.info.GetTxFee(ctx)
+	txFee, err := pc.fees.Fee(ctx, TxTypeAddSubnetValidator, 0)
 	if err != nil {
 		return 0, err
 	}
-	txFee := uint64(fi.TxFee)
 
 	zap.L().Info("adding subnet validator",
 		zap.String("subnetId", subnetID.String()),
@@ -334,7 +494,7 @@ func (pc *p) AddSubnetValidator(
 	pTx := &platformvm.Tx{
 		UnsignedTx: utx,
 	}
-	if err := k.Sign(pTx, signers); err != nil {
+	if err := k.Sign(ctx, pTx, signers); err != nil {
 		return 0, err
 	}
 	if err := utx.SyntacticVerify(&snow.Context{
@@ -343,7 +503,76 @@ func (pc *p) AddSubnetValidator(
 	}); err != nil {
 		return 0, err
 	}
-	txID, err := pc.cli.IssueTx(ctx, pTx.Bytes())
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue tx: %w", err)
+	}
+
+	return pc.checker.PollTx(ctx, txID, pstatus.Committed)
+}
+
+// ref. "platformvm.VM.newRemoveSubnetValidatorTx" of avalanchego v1.9.
+func (pc *p) RemoveSubnetValidator(
+	ctx context.Context,
+	k key.Key,
+	subnetID ids.ID,
+	nodeID ids.ShortID,
+	opts ...OpOption,
+) (took time.Duration, err error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	if subnetID == ids.Empty || nodeID == ids.ShortEmpty {
+		return 0, ErrEmptyID
+	}
+	if _, _, err := pc.GetValidator(ctx, subnetID, nodeID); err != nil {
+		return 0, err
+	}
+
+	txFee, err := pc.fees.Fee(ctx, TxTypeRemoveSubnetValidator, 0)
+	if err != nil {
+		return 0, err
+	}
+	zap.L().Info("removing subnet validator",
+		zap.String("subnetId", subnetID.String()),
+		zap.String("nodeId", nodeID.String()),
+		zap.Uint64("txFee", txFee),
+	)
+	ins, returnedOuts, _, signers, err := pc.stake(ctx, k, txFee)
+	if err != nil {
+		return 0, err
+	}
+	subnetAuth, subnetSigners, err := pc.authorize(ctx, k, subnetID)
+	if err != nil {
+		return 0, err
+	}
+	signers = append(signers, subnetSigners)
+
+	utx := &codec.RemoveSubnetValidatorTx{
+		BaseTx: platformvm.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    pc.networkID,
+			BlockchainID: pc.pChainID,
+			Ins:          ins,
+			Outs:         returnedOuts,
+			Memo:         ret.memo,
+		}},
+		NodeID:     nodeID,
+		Subnet:     subnetID,
+		SubnetAuth: subnetAuth,
+	}
+	pTx := &platformvm.Tx{
+		UnsignedTx: utx,
+	}
+	if err := k.Sign(ctx, pTx, signers); err != nil {
+		return 0, err
+	}
+	if err := utx.SyntacticVerify(&snow.Context{
+		NetworkID: pc.networkID,
+		ChainID:   pc.pChainID,
+	}); err != nil {
+		return 0, err
+	}
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
 	if err != nil {
 		return 0, fmt.Errorf("failed to issue tx: %w", err)
 	}
@@ -410,8 +639,10 @@ func (pc *p) AddValidator(
 		zap.String("changeAddress", ret.changeAddr.String()),
 	)
 
-	// ref. https://docs.avax.network/learn/platform-overview/transaction-fees/#fee-schedule
-	addStakerTxFee := uint64(0)
+	addStakerTxFee, err := pc.fees.Fee(ctx, TxTypeAddValidator, 0)
+	if err != nil {
+		return 0, err
+	}
 
 	ins, returnedOuts, stakedOuts, signers, err := pc.stake(
 		ctx,
@@ -450,7 +681,7 @@ func (pc *p) AddValidator(
 	pTx := &platformvm.Tx{
 		UnsignedTx: utx,
 	}
-	if err := k.Sign(pTx, signers); err != nil {
+	if err := k.Sign(ctx, pTx, signers); err != nil {
 		return 0, err
 	}
 	if err := utx.SyntacticVerify(&snow.Context{
@@ -459,7 +690,7 @@ func (pc *p) AddValidator(
 	}); err != nil {
 		return 0, err
 	}
-	txID, err := pc.cli.IssueTx(ctx, pTx.Bytes())
+	txID, err := pc.issueTx(ctx, pTx.Bytes())
 	if err != nil {
 		return 0, fmt.Errorf("failed to issue tx: %w", err)
 	}
@@ -487,11 +718,10 @@ func (pc *p) CreateBlockchain(
 		return ids.Empty, 0, ErrEmptyID
 	}
 
-	fi, err := pc.info.GetTxFee(ctx)
+	createBlkChainTxFee, err := pc.fees.Fee(ctx, TxTypeCreateBlockchain, 0)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-	createBlkChainTxFee := uint64(fi.CreateBlockchainTxFee)
 
 	now := time.Now()
 	zap.L().Info("creating blockchain",
@@ -527,7 +757,7 @@ func (pc *p) CreateBlockchain(
 	pTx := &platformvm.Tx{
 		UnsignedTx: utx,
 	}
-	if err := k.Sign(pTx, signers); err != nil {
+	if err := k.Sign(ctx, pTx, signers); err != nil {
 		return ids.Empty, 0, err
 	}
 	if err := utx.SyntacticVerify(&snow.Context{
@@ -536,7 +766,7 @@ func (pc *p) CreateBlockchain(
 	}); err != nil {
 		return ids.Empty, 0, err
 	}
-	blkChainID, err = pc.cli.IssueTx(ctx, pTx.Bytes())
+	blkChainID, err = pc.issueTx(ctx, pTx.Bytes())
 	if err != nil {
 		return ids.Empty, 0, fmt.Errorf("failed to issue tx: %w", err)
 	}
@@ -561,6 +791,7 @@ type Op struct {
 	rewardShares uint32
 	rewardAddr   ids.ShortID
 	changeAddr   ids.ShortID
+	memo         []byte
 
 	dryMode bool
 	poll    bool
@@ -574,6 +805,21 @@ func (op *Op) applyOpts(opts []OpOption) {
 	}
 }
 
+// ApplyOpts returns the options [opts] set, for implementations of the
+// interfaces outside this package (e.g., "clienttest").
+func ApplyOpts(opts []OpOption) *Op {
+	op := &Op{}
+	op.applyOpts(opts)
+	return op
+}
+
+func (op *Op) StakeAmount() uint64        { return op.stakeAmt }
+func (op *Op) RewardShares() uint32       { return op.rewardShares }
+func (op *Op) RewardAddress() ids.ShortID { return op.rewardAddr }
+func (op *Op) ChangeAddress() ids.ShortID { return op.changeAddr }
+func (op *Op) Memo() []byte               { return op.memo }
+func (op *Op) DryMode() bool              { return op.dryMode }
+
 func WithStakeAmount(v uint64) OpOption {
 	return func(op *Op) {
 		op.stakeAmt = v
@@ -598,6 +844,14 @@ func WithChangeAddress(v ids.ShortID) OpOption {
 	}
 }
 
+// WithMemo sets the memo of the issued tx (e.g., a name tag), which must
+// not exceed "avax.MaxMemoSize" bytes.
+func WithMemo(b []byte) OpOption {
+	return func(op *Op) {
+		op.memo = b
+	}
+}
+
 func WithDryMode(b bool) OpOption {
 	return func(op *Op) {
 		op.dryMode = b
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Pool hands out one [Client] per URI, so long-running modes (e.g., a
+// daemon or a TUI refreshing on every tick) connect and fetch the network
+// information once. It is safe for concurrent use.
+type Pool struct {
+	cfg Config
+
+	mu      sync.Mutex
+	clients map[string]*poolEntry
+}
+
+type poolEntry struct {
+	ready chan struct{}
+	cli   Client
+	err   error
+}
+
+// NewPool returns a pool creating clients with [cfg], whose URI is
+// ignored.
+func NewPool(cfg Config) *Pool {
+	return &Pool{cfg: cfg, clients: make(map[string]*poolEntry)}
+}
+
+// Get returns the client of [uri], connecting on first use. Concurrent
+// calls for the same URI share a single connection attempt; failed
+// attempts are not cached.
+func (pl *Pool) Get(ctx context.Context, uri string) (Client, error) {
+	uri = strings.TrimSuffix(uri, "/")
+
+	pl.mu.Lock()
+	e, ok := pl.clients[uri]
+	if !ok {
+		e = &poolEntry{ready: make(chan struct{})}
+		pl.clients[uri] = e
+	}
+	pl.mu.Unlock()
+
+	if ok {
+		select {
+		case <-e.ready:
+			return e.cli, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	cfg := pl.cfg
+	cfg.URI = uri
+	e.cli, e.err = New(ctx, cfg)
+	if e.err != nil {
+		pl.mu.Lock()
+		delete(pl.clients, uri)
+		pl.mu.Unlock()
+	}
+	close(e.ready)
+	return e.cli, e.err
+}
+
+// Close releases the idle connections of every pooled client.
+func (pl *Pool) Close() {
+	pl.mu.Lock()
+	pl.clients = make(map[string]*poolEntry)
+	pl.mu.Unlock()
+	closeIdleConnections()
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolFailureNotCached(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	pl := NewPool(Config{PollInterval: time.Second})
+	defer pl.Close()
+	for i := 1; i <= 2; i++ {
+		if _, err := pl.Get(context.Background(), srv.URL+"/"); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+		if v := atomic.LoadInt32(&requests); v != int32(i) {
+			t.Fatalf("#%d: expected %d requests, got %d", i, i, v)
+		}
+	}
+}
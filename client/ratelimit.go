@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// PublicRateLimit is the default request rate (per second) to known public
+// endpoints, low enough for batch operations not to get the caller's IP
+// banned.
+const PublicRateLimit = 5
+
+// publicBurst is the burst of [PublicRateLimit], so interactive commands
+// making a few requests are not slowed down.
+const publicBurst = 10
+
+var ErrInvalidRateLimit = errors.New("invalid rate limit")
+
+// publicHosts are the public API endpoints (and their subdomains) rate
+// limited by default.
+var publicHosts = []string{
+	"api.avax.network",
+	"api.avax-test.network",
+}
+
+// IsPublicHost returns true if [host] (with an optional port) is a known
+// public API endpoint.
+func IsPublicHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, h := range publicHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimit returns the request rate to [host] for the configured
+// [limit]: 0 picks [PublicRateLimit] for public endpoints and no limit
+// otherwise, a negative limit disables rate limiting.
+func rateLimit(host string, limit float64) (rate.Limit, int) {
+	switch {
+	case limit < 0:
+		return rate.Inf, 0
+	case limit == 0 && IsPublicHost(host):
+		return PublicRateLimit, publicBurst
+	case limit == 0:
+		return rate.Inf, 0
+	}
+	burst := int(limit)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.Limit(limit), burst
+}
+
+// ConfigureRateLimit applies the rate limit of [cfg] (see
+// [Config.RateLimit]) to every request to the host of [cfg.URI], including
+// requests made by API clients created outside of [New].
+func ConfigureRateLimit(cfg Config) error {
+	if math.IsNaN(cfg.RateLimit) || math.IsInf(cfg.RateLimit, 0) {
+		return fmt.Errorf("%w: %v requests per second", ErrInvalidRateLimit, cfg.RateLimit)
+	}
+	u, err := ParseURI(cfg.URI)
+	if err != nil {
+		return err
+	}
+	limit, burst := rateLimit(u.Host, cfg.RateLimit)
+	InstallTransport()
+	transports.mu.Lock()
+	defer transports.mu.Unlock()
+	if limit == rate.Inf {
+		delete(transports.limiters, u.Host)
+		return nil
+	}
+	zap.L().Debug("rate limiting requests",
+		zap.String("host", u.Host),
+		zap.Float64("rps", float64(limit)),
+		zap.Int("burst", burst),
+	)
+	if l, ok := transports.limiters[u.Host]; ok {
+		l.SetLimit(limit)
+		l.SetBurst(burst)
+		return nil
+	}
+	transports.limiters[u.Host] = rate.NewLimiter(limit, burst)
+	return nil
+}
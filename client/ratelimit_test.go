@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestIsPublicHost(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		host string
+		exp  bool
+	}{
+		{host: "api.avax.network", exp: true},
+		{host: "api.avax-test.network", exp: true},
+		{host: "api.avax.network:443", exp: true},
+		{host: "API.Avax.Network", exp: true},
+		{host: "eu.api.avax.network", exp: true},
+		{host: "avax.network"},
+		{host: "myapi.avax.network"},
+		{host: "api.avax.network.example.com"},
+		{host: "127.0.0.1:9650"},
+		{host: "[::1]:9650"},
+		{host: ""},
+	}
+	for i, tv := range tt {
+		if v := IsPublicHost(tv.host); v != tv.exp {
+			t.Fatalf("#%d: %q expected %v, got %v", i, tv.host, tv.exp, v)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		host     string
+		limit    float64
+		expLimit rate.Limit
+		expBurst int
+	}{
+		{host: "api.avax.network", limit: 0, expLimit: PublicRateLimit, expBurst: publicBurst},
+		{host: "127.0.0.1:9650", limit: 0, expLimit: rate.Inf},
+		{host: "api.avax.network", limit: -1, expLimit: rate.Inf},
+		{host: "127.0.0.1:9650", limit: -1, expLimit: rate.Inf},
+		{host: "api.avax.network", limit: 20, expLimit: 20, expBurst: 20},
+		{host: "127.0.0.1:9650", limit: 2.5, expLimit: 2.5, expBurst: 2},
+		{host: "127.0.0.1:9650", limit: 0.5, expLimit: 0.5, expBurst: 1},
+	}
+	for i, tv := range tt {
+		limit, burst := rateLimit(tv.host, tv.limit)
+		if limit != tv.expLimit || burst != tv.expBurst {
+			t.Fatalf("#%d: expected %v/%d, got %v/%d", i, tv.expLimit, tv.expBurst, limit, burst)
+		}
+	}
+}
+
+func TestConfigureRateLimit(t *testing.T) {
+	t.Parallel()
+
+	for i, l := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		err := ConfigureRateLimit(Config{URI: "http://127.0.0.1:9650", RateLimit: l})
+		if !errors.Is(err, ErrInvalidRateLimit) {
+			t.Fatalf("#%d: expected %v, got %v", i, ErrInvalidRateLimit, err)
+		}
+	}
+
+	// a dedicated host, so other tests are not limited
+	uri := "http://ratelimit.test:9650"
+	if err := ConfigureRateLimit(Config{URI: uri, RateLimit: 1}); err != nil {
+		t.Fatal(err)
+	}
+	transports.mu.RLock()
+	l, ok := transports.limiters["ratelimit.test:9650"]
+	transports.mu.RUnlock()
+	if !ok || l.Limit() != 1 || l.Burst() != 1 {
+		t.Fatalf("unexpected limiter %v", l)
+	}
+	if l.Reserve().Delay() != 0 || l.Reserve().Delay() < 500*time.Millisecond {
+		t.Fatal("expected the second request to wait")
+	}
+
+	// disabling the limit removes the limiter
+	if err := ConfigureRateLimit(Config{URI: uri, RateLimit: -1}); err != nil {
+		t.Fatal(err)
+	}
+	transports.mu.RLock()
+	_, ok = transports.limiters["ratelimit.test:9650"]
+	transports.mu.RUnlock()
+	if ok {
+		t.Fatal("expected no limiter")
+	}
+}
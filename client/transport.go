@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrInvalidProxy = errors.New("invalid proxy")
+	ErrInvalidTLS   = errors.New("invalid TLS config")
+)
+
+// TLSConfig configures the TLS connections to an endpoint, e.g., an mTLS
+// ingress in front of the validator APIs.
+type TLSConfig struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system
+	// roots.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key
+	// presented to the endpoint.
+	CertFile string
+	KeyFile  string
+}
+
+func (c TLSConfig) empty() bool {
+	return c.CAFile == "" && c.CertFile == "" && c.KeyFile == ""
+}
+
+// Transport is the HTTP transport shared by every client without a custom
+// proxy or TLS config, so requests to the same endpoint reuse kept-alive
+// connections instead of re-dialing and re-handshaking TLS.
+var Transport = newTransport()
+
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// hostTransports routes each request to the transport configured for its
+// host, falling back to [Transport], attaches the host's auth token and
+// waits for the host's rate limiter.
+type hostTransports struct {
+	mu       sync.RWMutex
+	hosts    map[string]*http.Transport
+	tokens   map[string]string
+	limiters map[string]*rate.Limiter
+	// unix is the set of hosts dialed to unix sockets.
+	unix map[string]bool
+}
+
+var _ http.RoundTripper = &hostTransports{}
+
+var transports = &hostTransports{
+	hosts:    make(map[string]*http.Transport),
+	tokens:   make(map[string]string),
+	limiters: make(map[string]*rate.Limiter),
+	unix:     make(map[string]bool),
+}
+
+func (h *hostTransports) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.RLock()
+	t, ok := h.hosts[req.URL.Host]
+	token := h.tokens[req.URL.Host]
+	limiter := h.limiters[req.URL.Host]
+	h.mu.RUnlock()
+	if !ok {
+		t = Transport
+	}
+	if limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if token != "" {
+		req = withAuth(req, token)
+	}
+	return t.RoundTrip(req)
+}
+
+var installTransportOnce sync.Once
+
+// InstallTransport makes [http.DefaultClient] route requests through the
+// transports configured by [ConfigureTransport]. The avalanchego API
+// clients copy [http.DefaultClient] when they are created, so this must
+// run before creating them; [New] calls it.
+func InstallTransport() {
+	installTransportOnce.Do(func() {
+		http.DefaultClient.Transport = transports
+	})
+}
+
+// ConfigureTransport applies the proxy and TLS settings of [cfg] to every
+// request to the host of [cfg.URI], including requests made by API clients
+// created outside of [New] (e.g., the index API client). It is a no-op
+// without custom settings.
+func ConfigureTransport(cfg Config) error {
+	if cfg.Proxy == "" && cfg.TLS.empty() {
+		return nil
+	}
+	u, err := ParseURI(cfg.URI)
+	if err != nil {
+		return err
+	}
+	if isUnixHost(u.Host) {
+		// proxies and TLS do not apply to local sockets
+		return nil
+	}
+	t := newTransport()
+	if cfg.Proxy != "" {
+		pu, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProxy, err)
+		}
+		switch pu.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("%w: unsupported scheme %q (expected http, https or socks5)", ErrInvalidProxy, pu.Scheme)
+		}
+		t.Proxy = http.ProxyURL(pu)
+	}
+	if !cfg.TLS.empty() {
+		t.TLSClientConfig, err = loadTLSConfig(cfg.TLS)
+		if err != nil {
+			return err
+		}
+	}
+
+	InstallTransport()
+	transports.mu.Lock()
+	if prev, ok := transports.hosts[u.Host]; ok {
+		prev.CloseIdleConnections()
+	}
+	transports.hosts[u.Host] = t
+	transports.mu.Unlock()
+	return nil
+}
+
+func loadTLSConfig(c TLSConfig) (*tls.Config, error) {
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		b, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("%w: no certificates in %q", ErrInvalidTLS, c.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("%w: client certificate and key must be set together", ErrInvalidTLS)
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTLS, err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
+
+func closeIdleConnections() {
+	Transport.CloseIdleConnections()
+	transports.mu.RLock()
+	for _, t := range transports.hosts {
+		t.CloseIdleConnections()
+	}
+	transports.mu.RUnlock()
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidURI = errors.New("invalid URI")
+
+// UnixScheme is the URI scheme of unix domain sockets
+// (e.g., "unix:///var/run/avalanchego/http.sock").
+const UnixScheme = "unix"
+
+// ParseURI parses an API endpoint URI. Besides HTTP(S) URLs (including
+// bracketed IPv6 hosts, e.g., "http://[::1]:9650"), it accepts "host:port"
+// without a scheme and unix domain sockets ("unix:///path/to/http.sock").
+//
+// A unix socket is mapped to a synthetic "http://unix-<hash>" URL whose
+// requests are dialed to the socket, since the API clients only take HTTP
+// URLs.
+func ParseURI(uri string) (*url.URL, error) {
+	if !strings.Contains(uri, "://") && !strings.HasPrefix(uri, UnixScheme+":") {
+		uri = "http://" + uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	switch u.Scheme {
+	case UnixScheme:
+		return unixURL(u)
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q (expected http, https or unix)", ErrInvalidURI, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: %q has no host", ErrInvalidURI, uri)
+	}
+	if strings.Count(u.Hostname(), ":") > 0 && !strings.HasPrefix(u.Host, "[") {
+		return nil, fmt.Errorf("%w: IPv6 hosts must be bracketed (e.g., http://[::1]:9650)", ErrInvalidURI)
+	}
+	return u, nil
+}
+
+func unixURL(u *url.URL) (*url.URL, error) {
+	path := u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+	if u.Host != "" || path == "" {
+		return nil, fmt.Errorf("%w: unix sockets must be given as unix:///path/to/http.sock", ErrInvalidURI)
+	}
+	h := sha256.Sum256([]byte(path))
+	host := "unix-" + hex.EncodeToString(h[:8])
+
+	InstallTransport()
+	transports.mu.Lock()
+	if _, ok := transports.hosts[host]; !ok {
+		t := newTransport()
+		t.Proxy = nil
+		t.DialContext = func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		transports.hosts[host] = t
+		transports.unix[host] = true
+	}
+	transports.mu.Unlock()
+	return &url.URL{Scheme: "http", Host: host}, nil
+}
+
+// isUnixHost returns true if [host] was mapped to a unix socket by
+// [ParseURI].
+func isUnixHost(host string) bool {
+	transports.mu.RLock()
+	defer transports.mu.RUnlock()
+	return transports.unix[host]
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseURI(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		uri     string
+		expURL  string
+		expUnix bool
+		expErr  error
+	}{
+		{uri: "http://127.0.0.1:9650", expURL: "http://127.0.0.1:9650"},
+		{uri: "https://api.avax-test.network", expURL: "https://api.avax-test.network"},
+		{uri: "127.0.0.1:9650", expURL: "http://127.0.0.1:9650"},
+		{uri: "localhost", expURL: "http://localhost"},
+		{uri: "http://[::1]:9650", expURL: "http://[::1]:9650"},
+		{uri: "[::1]:9650", expURL: "http://[::1]:9650"},
+		{uri: "unix:///var/run/avalanchego/http.sock", expURL: "http://unix-", expUnix: true},
+		{uri: "unix:/var/run/avalanchego/http.sock", expURL: "http://unix-", expUnix: true},
+		{uri: "http://::1:9650", expErr: ErrInvalidURI},
+		{uri: "ftp://127.0.0.1:9650", expErr: ErrInvalidURI},
+		{uri: "http://", expErr: ErrInvalidURI},
+		{uri: "http://127.0.0.1:port", expErr: ErrInvalidURI},
+		{uri: "unix://host/var/run/avalanchego/http.sock", expErr: ErrInvalidURI},
+		{uri: "unix://", expErr: ErrInvalidURI},
+	}
+	for i, tv := range tt {
+		u, err := ParseURI(tv.uri)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if tv.expErr != nil {
+			continue
+		}
+		if !strings.HasPrefix(u.String(), tv.expURL) {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.expURL, u)
+		}
+		if isUnixHost(u.Host) != tv.expUnix {
+			t.Fatalf("#%d: expected unix host %v, got %v", i, tv.expUnix, !tv.expUnix)
+		}
+	}
+
+	// the same socket always maps to the same host
+	a, err := ParseURI("unix:///tmp/a.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseURI("unix:///tmp/a.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseURI("unix:///tmp/c.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Host != b.Host || a.Host == c.Host {
+		t.Fatalf("unexpected socket hosts %q, %q, %q", a.Host, b.Host, c.Host)
+	}
+}
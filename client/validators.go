@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Validator is a current or pending validator record of a subnet. Fields
+// the API does not report for the subnet (e.g., uptime of pending
+// validators, reward owner of subnet validators) are left empty.
+type Validator struct {
+	TxID    ids.ID
+	NodeID  ids.ShortID
+	Pending bool
+	Start   time.Time
+	End     time.Time
+	// Weight is the stake amount for the primary network.
+	Weight uint64
+	// Uptime is the observed uptime in [0, 1] as reported by the queried
+	// node; nil if unknown.
+	Uptime    *float64
+	Connected *bool
+
+	RewardOwnerAddrs     []string
+	RewardOwnerThreshold uint32
+	DelegationFee        string
+	PotentialReward      uint64
+}
+
+// GetValidators returns the current and pending validators of [rsubnetID].
+func (pc *p) GetValidators(ctx context.Context, rsubnetID ids.ID) ([]Validator, error) {
+	subnetID := constants.PrimaryNetworkID
+	if rsubnetID != ids.Empty {
+		subnetID = rsubnetID
+	}
+	current, err := pc.Client().GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	pending, _, err := pc.Client().GetPendingValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	vs := make([]Validator, 0, len(current)+len(pending))
+	for i, raw := range append(current, pending...) {
+		v, err := parseValidator(raw, i >= len(current))
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+func parseValidator(raw interface{}, pending bool) (Validator, error) {
+	v := Validator{Pending: pending}
+	va, ok := raw.(map[string]interface{})
+	if !ok {
+		return v, fmt.Errorf("%w: %T %+v", ErrInvalidValidatorData, raw, raw)
+	}
+	nodeIDs, ok := va["nodeID"].(string)
+	if !ok {
+		return v, ErrInvalidValidatorData
+	}
+	var err error
+	if v.NodeID, err = ids.ShortFromPrefixedString(nodeIDs, constants.NodeIDPrefix); err != nil {
+		return v, err
+	}
+	if s, ok := va["txID"].(string); ok {
+		if v.TxID, err = ids.FromString(s); err != nil {
+			return v, err
+		}
+	}
+	start, err := parseUint(va, "startTime")
+	if err != nil {
+		return v, err
+	}
+	end, err := parseUint(va, "endTime")
+	if err != nil {
+		return v, err
+	}
+	v.Start, v.End = time.Unix(int64(start), 0), time.Unix(int64(end), 0)
+	if _, ok := va["weight"]; ok {
+		v.Weight, err = parseUint(va, "weight")
+	} else {
+		v.Weight, err = parseUint(va, "stakeAmount")
+	}
+	if err != nil {
+		return v, err
+	}
+	if s, ok := va["uptime"].(string); ok {
+		u, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return v, fmt.Errorf("%w: uptime %q", ErrInvalidValidatorData, s)
+		}
+		v.Uptime = &u
+	}
+	if c, ok := va["connected"].(bool); ok {
+		v.Connected = &c
+	}
+	if s, ok := va["delegationFee"].(string); ok {
+		v.DelegationFee = s
+	}
+	if _, ok := va["potentialReward"]; ok {
+		if v.PotentialReward, err = parseUint(va, "potentialReward"); err != nil {
+			return v, err
+		}
+	}
+	owner, ok := va["rewardOwner"].(map[string]interface{})
+	if !ok {
+		owner, ok = va["validationRewardOwner"].(map[string]interface{})
+	}
+	if ok {
+		if addrs, ok := owner["addresses"].([]interface{}); ok {
+			for _, a := range addrs {
+				if s, ok := a.(string); ok {
+					v.RewardOwnerAddrs = append(v.RewardOwnerAddrs, s)
+				}
+			}
+		}
+		if _, ok := owner["threshold"]; ok {
+			th, err := parseUint(owner, "threshold")
+			if err != nil {
+				return v, err
+			}
+			v.RewardOwnerThreshold = uint32(th)
+		}
+	}
+	return v, nil
+}
+
+// parseUint parses the "json.Uint64" (string) or number field [k].
+func parseUint(m map[string]interface{}, k string) (uint64, error) {
+	switch d := m[k].(type) {
+	case string:
+		return strconv.ParseUint(d, 10, 64)
+	case float64:
+		return uint64(d), nil
+	}
+	return 0, fmt.Errorf("%w: missing %q", ErrInvalidValidatorData, k)
+}
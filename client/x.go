@@ -0,0 +1,222 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	api_info "github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"go.uber.org/zap"
+
+	internal_avax "github.com/ava-labs/subnet-cli/internal/avax"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+var ErrTxNotAccepted = errors.New("tx not accepted")
+
+// numXFxs is the number of feature extensions of the X-Chain, see
+// [codec.XCodecManager].
+const numXFxs = 3
+
+// X defines X-Chain client operations.
+type X interface {
+	Client() avm.Client
+	// Balance returns the AVAX held by [k] on the X-Chain.
+	Balance(ctx context.Context, k key.Key) (uint64, error)
+	// CreateAssetFee returns the AVAX burned by a CreateAssetTx.
+	CreateAssetFee(ctx context.Context) (uint64, error)
+	// CreateAsset creates the asset [name] ([symbol], with [denomination]
+	// decimal places) with the initial [state], paying the fee with the
+	// AVAX of [k]. The asset ID is the tx ID, so dry mode returns it too.
+	CreateAsset(
+		ctx context.Context,
+		k key.Key,
+		name string,
+		symbol string,
+		denomination uint8,
+		state *avm.InitialState,
+		opts ...OpOption,
+	) (assetID ids.ID, took time.Duration, err error)
+}
+
+type x struct {
+	cfg Config
+
+	networkID uint32
+	assetID   ids.ID
+	xChainID  ids.ID
+
+	cli  avm.Client
+	info api_info.Client
+}
+
+func (xc *x) Client() avm.Client { return xc.cli }
+
+func (xc *x) addresses(k key.Key) ([]string, error) {
+	addrs := make([]string, len(k.Addresses()))
+	for i, addr := range k.Addresses() {
+		s, err := key.FormatXAddress(xc.networkID, addr)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = s
+	}
+	return addrs, nil
+}
+
+func (xc *x) Balance(ctx context.Context, k key.Key) (uint64, error) {
+	addrs, err := xc.addresses(k)
+	if err != nil {
+		return 0, err
+	}
+	balance := uint64(0)
+	for _, addr := range addrs {
+		resp, err := xc.cli.GetBalance(ctx, addr, xc.assetID.String(), false)
+		if err != nil {
+			return 0, err
+		}
+		balance += uint64(resp.Balance)
+	}
+	return balance, nil
+}
+
+func (xc *x) CreateAssetFee(ctx context.Context) (uint64, error) {
+	resp, err := xc.info.GetTxFee(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(resp.CreateAssetTxFee), nil
+}
+
+// ref. "avm.Service.CreateAsset".
+func (xc *x) CreateAsset(
+	ctx context.Context,
+	k key.Key,
+	name string,
+	symbol string,
+	denomination uint8,
+	state *avm.InitialState,
+	opts ...OpOption,
+) (assetID ids.ID, took time.Duration, err error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+	if ret.changeAddr == ids.ShortEmpty {
+		ret.changeAddr = k.Addresses()[0]
+	}
+
+	fee, err := xc.CreateAssetFee(ctx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	zap.L().Info("creating asset",
+		zap.Bool("dryMode", ret.dryMode),
+		zap.String("name", name),
+		zap.String("symbol", symbol),
+		zap.Uint64("createAssetTxFee", fee),
+	)
+	ins, outs, signers, err := xc.burn(ctx, k, fee, ret.changeAddr)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	utx := &avm.CreateAssetTx{
+		BaseTx: avm.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    xc.networkID,
+			BlockchainID: xc.xChainID,
+			Ins:          ins,
+			Outs:         outs,
+			Memo:         ret.memo,
+		}},
+		Name:         name,
+		Symbol:       symbol,
+		Denomination: denomination,
+		States:       []*avm.InitialState{state},
+	}
+	xTx := &avm.Tx{UnsignedTx: utx}
+	if err := key.SignX(ctx, k, xTx, signers); err != nil {
+		return ids.Empty, 0, err
+	}
+	if err := utx.SyntacticVerify(&snow.Context{
+		NetworkID: xc.networkID,
+		ChainID:   xc.xChainID,
+	}, codec.XCodecManager, xc.assetID, fee, fee, numXFxs); err != nil {
+		return ids.Empty, 0, err
+	}
+	if ret.dryMode {
+		return xTx.ID(), 0, nil
+	}
+
+	if exp := xc.cfg.ExpireAt; !exp.IsZero() && time.Now().After(exp) {
+		return ids.Empty, 0, fmt.Errorf("%w at %s", ErrExpired, exp.Format(time.RFC3339))
+	}
+	start := time.Now()
+	txID, err := xc.cli.IssueTx(ctx, xTx.Bytes())
+	if err != nil {
+		return ids.Empty, 0, fmt.Errorf("failed to issue tx: %w", err)
+	}
+	status, err := xc.cli.ConfirmTx(ctx, txID, xc.cfg.PollInterval)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	if status != choices.Accepted {
+		return ids.Empty, 0, fmt.Errorf("%w: %s is %s", ErrTxNotAccepted, txID, status)
+	}
+	return txID, time.Since(start), nil
+}
+
+// burn spends the X-Chain AVAX of [k] to burn [fee], returning the rest to
+// [changeAddr].
+func (xc *x) burn(ctx context.Context, k key.Key, fee uint64, changeAddr ids.ShortID) (
+	ins []*avax.TransferableInput,
+	outs []*avax.TransferableOutput,
+	signers [][]ids.ShortID,
+	err error,
+) {
+	addrs, err := xc.addresses(k)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ubs, _, err := xc.cli.GetUTXOs(ctx, addrs, 1024, "", "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	utxos := make([]*avax.UTXO, 0, len(ubs))
+	for _, ub := range ubs {
+		utxo, err := internal_avax.ParseUTXO(ub, codec.XCodecManager)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, ok := utxo.Out.(*secp256k1fx.TransferOutput); !ok || utxo.AssetID() != xc.assetID {
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	total, ins, signers := k.Spends(utxos, key.WithTime(uint64(time.Now().Unix())), key.WithTargetAmount(fee))
+	if total < fee {
+		return nil, nil, nil, fmt.Errorf("%w: have %d, need %d on the X-Chain", ErrInsufficientBalanceForGasFee, total, fee)
+	}
+	if total > fee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: xc.assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: total - fee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+	return ins, outs, signers, nil
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/dustin/go-humanize"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/pkg/color"
 )
 
 // AddCommand implements "subnet-cli add" command.
@@ -23,35 +25,34 @@ func AddCommand() *cobra.Command {
 		newAddSubnetValidatorCommand(),
 	)
 	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
-	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path")
-	cmd.PersistentFlags().BoolVarP(&useLedger, "ledger", "l", false, "use ledger to sign transactions")
+	addSignerFlags(cmd)
 	return cmd
 }
 
 func CreateAddTable(i *Info) string {
 	buf, tb := BaseTableSetup(i)
-	tb.Append([]string{formatter.F("{{orange}}NODE IDs{{/}}"), formatter.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
+	tb.Append([]string{color.F("{{orange}}NODE IDs{{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
 	if i.subnetID != ids.Empty {
-		tb.Append([]string{formatter.F("{{blue}}SUBNET ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.subnetID)})
+		tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindSubnet, i.subnetID))})
 	}
 	if !i.validateStart.IsZero() {
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE START{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.validateStart.Format(time.RFC3339))})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE START{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.validateStart.Format(time.RFC3339))})
 	}
 	if !i.validateEnd.IsZero() {
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE END{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.validateEnd.Format(time.RFC3339))})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE END{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.validateEnd.Format(time.RFC3339))})
 	}
 	if i.validateWeight > 0 {
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE WEIGHT{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(i.validateWeight)))})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(i.validateWeight)))})
 	}
 	if i.validateRewardFeePercent > 0 {
 		validateRewardFeePercent := humanize.FormatFloat("#,###.###", float64(i.validateRewardFeePercent))
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE REWARD FEE{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} %%", validateRewardFeePercent)})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE REWARD FEE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} %%", validateRewardFeePercent)})
 	}
 	if i.rewardAddr != ids.ShortEmpty {
-		tb.Append([]string{formatter.F("{{cyan}}{{bold}}REWARD ADDRESS{{/}}"), formatter.F("{{light-gray}}%s{{/}}", i.rewardAddr)})
+		tb.Append([]string{color.F("{{cyan}}{{bold}}REWARD ADDRESS{{/}}"), color.F("{{light-gray}}%s{{/}}", i.FormatAddress(i.rewardAddr))})
 	}
 	if i.changeAddr != ids.ShortEmpty {
-		tb.Append([]string{formatter.F("{{cyan}}{{bold}}CHANGE ADDRESS{{/}}"), formatter.F("{{light-gray}}%s{{/}}", i.changeAddr)})
+		tb.Append([]string{color.F("{{cyan}}{{bold}}CHANGE ADDRESS{{/}}"), color.F("{{light-gray}}%s{{/}}", i.FormatAddress(i.changeAddr))})
 	}
 	tb.Render()
 	return buf.String()
@@ -4,16 +4,14 @@
 package cmd
 
 import (
-	"context"
 	"errors"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
 	"github.com/ava-labs/subnet-cli/pkg/color"
-	"github.com/manifoldco/promptui"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +19,15 @@ const (
 	defaultValidateWeight = 1000
 )
 
+// addSubnetValidatorOptions are the flags of "add subnet-validator".
+type addSubnetValidatorOptions struct {
+	subnetID string
+	nodeIDs  []string
+	weight   uint64
+}
+
 func newAddSubnetValidatorCommand() *cobra.Command {
+	o := &addSubnetValidatorOptions{}
 	cmd := &cobra.Command{
 		Use:   "subnet-validator",
 		Short: "Adds a subnet to the validator",
@@ -36,29 +42,35 @@ $ subnet-cli add subnet-validator \
 --validate-weight=1000
 
 `,
-		RunE: createSubnetValidatorFunc,
+		RunE: o.run,
 	}
 
-	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
-	cmd.PersistentFlags().StringSliceVar(&nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
-	cmd.PersistentFlags().Uint64Var(&validateWeight, "validate-weight", defaultValidateWeight, "validate weight")
+	cmd.PersistentFlags().StringVar(&o.subnetID, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringSliceVar(&o.nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
+	cmd.PersistentFlags().Uint64Var(&o.weight, "validate-weight", defaultValidateWeight, "validate weight")
+	addFailureReportFlag(cmd)
 
 	return cmd
 }
 
 var errZeroValidateWeight = errors.New("zero validate weight")
 
-func createSubnetValidatorFunc(cmd *cobra.Command, args []string) error {
-	cli, info, err := InitClient(publicURI, true)
+func (o *addSubnetValidatorOptions) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(o.subnetID)
 	if err != nil {
 		return err
 	}
-	info.subnetID, err = ids.FromString(subnetIDs)
+	info.txFee, err = info.Fee(ctx, client.TxTypeAddSubnetValidator, 1)
 	if err != nil {
 		return err
 	}
-	info.txFee = uint64(info.feeData.TxFee)
-	if err := ParseNodeIDs(cli, info); err != nil {
+	if err := ParseNodeIDs(ctx, cli, info, o.nodeIDs); err != nil {
 		return err
 	}
 	if len(info.nodeIDs) == 0 {
@@ -66,7 +78,7 @@ func createSubnetValidatorFunc(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	info.validateWeight = validateWeight
+	info.validateWeight = o.weight
 	info.validateRewardFeePercent = 0
 	if info.validateWeight == 0 {
 		return errZeroValidateWeight
@@ -75,76 +87,96 @@ func createSubnetValidatorFunc(cmd *cobra.Command, args []string) error {
 	info.rewardAddr = ids.ShortEmpty
 	info.changeAddr = ids.ShortEmpty
 
+	info.validateStart = time.Now().Add(defaultValidateStartBuffer)
+	if err := CheckValidateWindows(ctx, cli, info); err != nil {
+		return err
+	}
+	info.validateStart = time.Time{}
+
 	info.txFee *= uint64(len(info.nodeIDs))
 	info.requiredBalance = info.txFee
+	info.weightChange = o.weight * uint64(len(info.nodeIDs))
 	if err := info.CheckBalance(); err != nil {
 		return err
 	}
-	msg := CreateAddTable(info)
-	if enablePrompt {
-		msg = formatter.F("\n{{blue}}{{bold}}Ready to add subnet validator, should we continue?{{/}}\n") + msg
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, msg)
-
-	if enablePrompt {
-		prompt := promptui.Select{
-			Label:  "\n",
-			Stdout: os.Stdout,
-			Items: []string{
-				formatter.F("{{green}}Yes, let's create! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
-				formatter.F("{{red}}No, stop it!{{/}}"),
-			},
-		}
-		idx, _, err := prompt.Run()
-		if err != nil {
-			return nil //nolint:nilerr
-		}
-		if idx == 1 {
-			return nil
-		}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(CreateAddTable(info), changes, "add subnet validator", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
 	}
 
 	println()
 	println()
 	println()
+	b := new(batch)
+	if err := b.add(ctx, info, client.TxTypeAddSubnetValidator, len(info.nodeIDs), 0); err != nil {
+		return err
+	}
+	r := rollback.New("add subnet-validator", info.networkID)
+	for _, nodeID := range info.nodeIDs {
+		r.Plan(nodeAction(rollback.KindAddSubnetValidator, nodeID))
+	}
 	for _, nodeID := range info.nodeIDs {
+		if err := b.check(ctx, cli, info); err != nil {
+			return reportRollback(info, r, err)
+		}
 		// valInfo is not populated because [ParseNodeIDs] called on info.subnetID
 		//
 		// TODO: cleanup
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 		_, end, err := cli.P().GetValidator(ctx, ids.Empty, nodeID)
-		cancel()
 		if err != nil {
-			return err
+			return reportRollback(info, r, err)
 		}
-		info.validateStart = time.Now().Add(30 * time.Second)
+		info.validateStart = time.Now().Add(defaultValidateStartBuffer)
 		info.validateEnd = end
-		ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
-		took, err := cli.P().AddSubnetValidator(
-			ctx,
-			info.key,
-			info.subnetID,
-			nodeID,
-			info.validateStart,
-			info.validateEnd,
-			validateWeight,
-		)
-		cancel()
+		took, err := refreshStartTime(info, false, func() (time.Duration, error) {
+			return cli.P().AddSubnetValidator(
+				ctx,
+				info.key,
+				info.subnetID,
+				nodeID,
+				info.validateStart,
+				info.validateEnd,
+				o.weight,
+			)
+		})
 		if err != nil {
-			return err
+			return reportRollback(info, r, err)
+		}
+		b.done()
+		a := validatorAction(rollback.KindAddSubnetValidator, nodeID, info.validateEnd)
+		a.SubnetID = info.subnetID.String()
+		r.Complete(a)
+		if err := info.RecordApproval(); err != nil {
+			return reportRollback(info, r, err)
 		}
 		color.Outf("{{magenta}}added %s to subnet %s validator set{{/}} {{light-gray}}(took %v){{/}}\n\n", nodeID, info.subnetID, took)
+		color.Result(nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	if err := WaitValidator(ctx, cli, info.nodeIDs, info); err != nil {
+		return reportRollback(info, r, err)
 	}
-	WaitValidator(cli, info.nodeIDs, info)
 	info.requiredBalance = 0
 	info.stakeAmount = 0
 	info.txFee = 0
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	info.balance, err = cli.P().Balance(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, CreateAddTable(info))
+	color.Print(CreateAddTable(info))
 	return nil
 }
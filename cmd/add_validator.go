@@ -4,19 +4,16 @@
 package cmd
 
 import (
-	"context"
 	"errors"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
 	"github.com/ava-labs/subnet-cli/pkg/color"
-	"github.com/manifoldco/promptui"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
 )
 
@@ -24,10 +21,21 @@ const (
 	defaultStakeAmount   = 1 * units.Avax
 	defaultValFeePercent = 2
 	defaultStagger       = 2 * time.Hour
-	defaultValDuration   = 300 * 24 * time.Hour
+	defaultValidateEnd   = "now+300d"
 )
 
+// addValidatorOptions are the flags of "add validator".
+type addValidatorOptions struct {
+	nodeIDs          []string
+	stakeAmount      uint64
+	window           validateWindowFlags
+	rewardFeePercent uint32
+	rewardAddr       string
+	changeAddr       string
+}
+
 func newAddValidatorCommand() *cobra.Command {
+	o := &addValidatorOptions{stakeAmount: defaultStakeAmount}
 	cmd := &cobra.Command{
 		Use:   "validator",
 		Short: "Adds a node as a validator",
@@ -38,63 +46,71 @@ $ subnet-cli add validator \
 --private-key-path=.insecure.ewoq.key \
 --public-uri=http://localhost:52250 \
 --node-ids="NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH" \
---stake-amount=2000000000000 \
+--stake-amount=2000avax \
+--validate-end=now+14d \
 --validate-reward-fee-percent=2
 
 `,
-		RunE: createValidatorFunc,
+		RunE: o.run,
 	}
 
-	cmd.PersistentFlags().StringSliceVar(&nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
-	cmd.PersistentFlags().Uint64Var(&stakeAmount, "stake-amount", defaultStakeAmount, "stake amount denominated in nano AVAX (minimum amount that a validator must stake is 2,000 AVAX)")
+	cmd.PersistentFlags().StringSliceVar(&o.nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
+	cmd.PersistentFlags().Var((*amountFlag)(&o.stakeAmount), "stake-amount", "stake amount with unit (e.g., 2000avax, 1.5AVAX, 25000000000nAVAX; bare integers are nano AVAX) (minimum amount that a validator must stake is 2,000 AVAX)")
 
-	end := time.Now().Add(defaultValDuration)
-	cmd.PersistentFlags().StringVar(&validateEnds, "validate-end", end.Format(time.RFC3339), "validate start timestamp in RFC3339 format")
-	cmd.PersistentFlags().Uint32Var(&validateRewardFeePercent, "validate-reward-fee-percent", defaultValFeePercent, "percentage of fee that the validator will take rewards from its delegators")
-	cmd.PersistentFlags().StringVar(&rewardAddrs, "reward-address", "", "node address to send rewards to (default to key owner)")
-	cmd.PersistentFlags().StringVar(&changeAddrs, "change-address", "", "node address to send changes to (default to key owner)")
+	o.window.addFlags(cmd)
+	cmd.PersistentFlags().Uint32Var(&o.rewardFeePercent, "validate-reward-fee-percent", defaultValFeePercent, "percentage of fee that the validator will take rewards from its delegators")
+	cmd.PersistentFlags().StringVar(&o.rewardAddr, "reward-address", "", "P-Chain address to send rewards to (default to key owner)")
+	cmd.PersistentFlags().StringVar(&o.changeAddr, "change-address", "", "P-Chain address to send changes to (default to key owner)")
+	addFailureReportFlag(cmd)
 
 	return cmd
 }
 
 var errInvalidValidateRewardFeePercent = errors.New("invalid validate reward fee percent")
 
-func createValidatorFunc(cmd *cobra.Command, args []string) error {
-	cli, info, err := InitClient(publicURI, true)
+func (o *addValidatorOptions) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
 	if err != nil {
 		return err
 	}
-	info.stakeAmount = stakeAmount
+	info.stakeAmount = o.stakeAmount
 
 	info.subnetID = ids.Empty
-	if err := ParseNodeIDs(cli, info); err != nil {
+	if err := ParseNodeIDs(ctx, cli, info, o.nodeIDs); err != nil {
 		return err
 	}
 	if len(info.nodeIDs) == 0 {
 		color.Outf("{{magenta}}no primary network validators to add{{/}}\n")
 		return nil
 	}
-	info.validateEnd, err = time.Parse(time.RFC3339, validateEnds)
-	if err != nil {
+	if info.subnetOnlyValidators() {
+		color.Outf("{{yellow}}%q runs %s: validating an L1 needs no primary network stake (see \"subnet-cli convert l1\" and \"subnet-cli l1 add-validator\"); stake only to validate the primary network or permissioned subnets{{/}}\n", info.networkName, version.Etna)
+	}
+	if err := ParseValidateWindow(info, o.window); err != nil {
+		return err
+	}
+	if err := CheckValidateWindows(ctx, cli, info); err != nil {
 		return err
 	}
 
 	info.validateWeight = 0
-	info.validateRewardFeePercent = validateRewardFeePercent
+	info.validateRewardFeePercent = o.rewardFeePercent
 	if info.validateRewardFeePercent < 2 {
 		return errInvalidValidateRewardFeePercent
 	}
 
-	if rewardAddrs != "" {
-		info.rewardAddr, err = ids.ShortFromPrefixedString(rewardAddrs, constants.NodeIDPrefix)
+	if o.rewardAddr != "" {
+		info.rewardAddr, err = ParseAddress(info.networkID, o.rewardAddr)
 		if err != nil {
 			return err
 		}
 	} else {
 		info.rewardAddr = info.key.Addresses()[0]
 	}
-	if changeAddrs != "" {
-		info.changeAddr, err = ids.ShortFromPrefixedString(changeAddrs, constants.NodeIDPrefix)
+	if o.changeAddr != "" {
+		info.changeAddr, err = ParseAddress(info.networkID, o.changeAddr)
 		if err != nil {
 			return err
 		}
@@ -105,66 +121,81 @@ func createValidatorFunc(cmd *cobra.Command, args []string) error {
 	if err := info.CheckBalance(); err != nil {
 		return err
 	}
-	msg := CreateAddTable(info)
-	if enablePrompt {
-		msg = formatter.F("\n{{blue}}{{bold}}Ready to add validator, should we continue?{{/}}\n") + msg
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, msg)
-
-	if enablePrompt {
-		prompt := promptui.Select{
-			Label:  "\n",
-			Stdout: os.Stdout,
-			Items: []string{
-				formatter.F("{{green}}Yes, let's create! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
-				formatter.F("{{red}}No, stop it!{{/}}"),
-			},
-		}
-		idx, _, err := prompt.Run()
-		if err != nil {
-			return nil //nolint:nilerr
-		}
-		if idx == 1 {
-			return nil
-		}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(CreateAddTable(info), changes, "add validator", "I agree to pay the fee and lock the stake") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
 	}
 
 	println()
 	println()
 	println()
+	b := new(batch)
+	if err := b.add(ctx, info, client.TxTypeAddValidator, len(info.nodeIDs), info.stakeAmount); err != nil {
+		return err
+	}
+	r := rollback.New("add validator", info.networkID)
 	for i, nodeID := range info.nodeIDs {
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-		info.validateStart = time.Now().Add(30 * time.Second)
-		took, err := cli.P().AddValidator(
-			ctx,
-			info.key,
-			nodeID,
-			info.validateStart,
-			info.validateEnd,
-			client.WithStakeAmount(info.stakeAmount),
-			client.WithRewardShares(info.validateRewardFeePercent*10000),
-			client.WithRewardAddress(info.rewardAddr),
-			client.WithChangeAddress(info.changeAddr),
-		)
-		cancel()
+		r.Plan(validatorAction(rollback.KindAddValidator, nodeID, info.validateEnd.Add(time.Duration(i)*defaultStagger)))
+	}
+	for i, nodeID := range info.nodeIDs {
+		if err := b.check(ctx, cli, info); err != nil {
+			return reportRollback(info, r, err)
+		}
+		if !info.fixedValidateStart {
+			info.validateStart = time.Now().Add(defaultValidateStartBuffer)
+		}
+		took, err := refreshStartTime(info, true, func() (time.Duration, error) {
+			return cli.P().AddValidator(
+				ctx,
+				info.key,
+				nodeID,
+				info.validateStart,
+				info.validateEnd,
+				client.WithStakeAmount(info.stakeAmount),
+				client.WithRewardShares(info.validateRewardFeePercent*10000),
+				client.WithRewardAddress(info.rewardAddr),
+				client.WithChangeAddress(info.changeAddr),
+			)
+		})
 		if err != nil {
-			return err
+			return reportRollback(info, r, err)
+		}
+		b.done()
+		r.Complete(validatorAction(rollback.KindAddValidator, nodeID, info.validateEnd))
+		if err := info.RecordApproval(); err != nil {
+			return reportRollback(info, r, err)
 		}
 		color.Outf("{{magenta}}added %s to primary network validator set{{/}} {{light-gray}}(took %v){{/}}\n\n", nodeID, took)
+		color.Result(nodeID.PrefixedString(constants.NodeIDPrefix))
 		if i < len(info.nodeIDs)-1 {
 			info.validateEnd = info.validateEnd.Add(defaultStagger)
 		}
 	}
-	WaitValidator(cli, info.nodeIDs, info)
+	if err := WaitValidator(ctx, cli, info.nodeIDs, info); err != nil {
+		return reportRollback(info, r, err)
+	}
 	info.requiredBalance = 0
 	info.stakeAmount = 0
 	info.txFee = 0
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	info.balance, err = cli.P().Balance(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, CreateAddTable(info))
+	color.Print(CreateAddTable(info))
 	return nil
 }
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/address"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/qr"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	addressNetworkName string
+	addressTo          string
+)
+
+// AddressCommand implements "subnet-cli address" command.
+func AddressCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "address",
+		Short: "Sub-commands for working with addresses",
+	}
+	cmd.AddCommand(
+		newAddressConvertCommand(),
+	)
+	return cmd
+}
+
+func newAddressConvertCommand() *cobra.Command {
+	formats := make([]string, len(address.Formats))
+	for i, f := range address.Formats {
+		formats[i] = string(f)
+	}
+	cmd := &cobra.Command{
+		Use:   "convert [address|public key]...",
+		Short: "Converts between P/X/C-Chain, EVM and short ID addresses",
+		Long: `
+Converts P/X/C-Chain bech32 addresses, raw short IDs, EVM (0x) addresses and
+compressed hex public keys into the other address formats of the same key.
+Without arguments, converts the key at "--private-key-path".
+
+The bech32 addresses of all chains and the short ID share the hash of the
+public key, while EVM addresses hash it differently, so only a public key
+(or the key file) converts into both.
+
+$ subnet-cli address convert P-fuji1...
+$ subnet-cli address convert 6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV --network-name=local --to=x
+$ subnet-cli address convert --private-key-path=.insecure.ewoq.key --network-name=local
+
+With "--network-name", bech32 addresses of other networks are rejected.
+
+`,
+		RunE: addressConvertFunc,
+	}
+	cmd.PersistentFlags().StringVar(&addressNetworkName, "network-name", "", "network of the addresses (e.g., fuji, mainnet); empty to infer from bech32 addresses")
+	cmd.PersistentFlags().StringVar(&addressTo, "to", "", fmt.Sprintf("only print this format (%s)", strings.Join(formats, ", ")))
+	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path, converted without arguments")
+	return cmd
+}
+
+func addressConvertFunc(cmd *cobra.Command, args []string) error {
+	hrp := ""
+	networkID := uint32(0)
+	if addressNetworkName != "" {
+		var err error
+		networkID, err = constants.NetworkID(addressNetworkName)
+		if err != nil {
+			return err
+		}
+		hrp = constants.GetHRP(networkID)
+	}
+	inputs := args
+	if len(inputs) == 0 {
+		k, err := key.LoadSoft(networkID, privKeyPath)
+		if err != nil {
+			return err
+		}
+		inputs = []string{"0x" + hex.EncodeToString(k.Key().PublicKey().Bytes())}
+	}
+
+	for _, s := range inputs {
+		a, err := address.Convert(s, hrp)
+		if err != nil {
+			return err
+		}
+		if addressTo != "" {
+			v, err := a.Get(address.Format(addressTo))
+			if err != nil {
+				return fmt.Errorf("%q: %w", s, err)
+			}
+			color.Outf("%s\n", v)
+			color.Result(v)
+			printQR(v, v, qr.Medium)
+			continue
+		}
+		network := a.Network()
+		if network == "" {
+			network = a.HRP
+		}
+		color.Outf("{{blue}}{{bold}}%s{{/}} {{light-gray}}(%s, network %q){{/}}\n", s, a.Input, network)
+		for _, f := range address.Formats {
+			v, err := a.Get(f)
+			if err != nil {
+				color.Outf("  {{cyan}}%-9s{{/}} {{light-gray}}%v{{/}}\n", f+":", err)
+				continue
+			}
+			color.Outf("  {{cyan}}%-9s{{/}} %s\n", f+":", v)
+		}
+	}
+	return nil
+}
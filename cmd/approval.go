@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ava-labs/subnet-cli/internal/approval"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/policy"
+	"github.com/ava-labs/subnet-cli/internal/qr"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	proposePath string
+	approvePath string
+)
+
+// unboundFlags select who runs an operation and how, not what it does, so
+// they may differ between the proposer and the approver.
+var unboundFlags = map[string]struct{}{
+	"propose": {}, "approve": {},
+	"private-key-path": {}, "key-vault-path": {}, "ledger": {}, "signer": {}, "kms-key-arn": {}, "kms-key-name": {},
+	"fireblocks-url": {}, "fireblocks-api-key": {}, "fireblocks-secret-path": {}, "fireblocks-vault-id": {}, "fireblocks-address-index": {},
+	"custody-url": {}, "custody-token-path": {}, "approval-timeout": {},
+	"public-uri": {}, "private-uri": {}, "proxy": {}, "tls-ca-file": {}, "tls-cert-file": {}, "tls-key-file": {},
+	"auth-token": {}, "auth-password": {}, "timeout": {}, "request-timeout": {}, "poll-interval": {},
+	"enable-prompt": {}, "quiet": {}, "verbose": {}, "log-level": {}, "no-color": {},
+	"i-am-sure-mainnet": {}, "debug-http": {}, "price-source": {}, "fiat-currency": {},
+}
+
+// CheckApproval implements the two-person approval mode, which the policy
+// in effect must allow by listing the proposer and approver addresses, and
+// then requires: without "--propose" or "--approve", it fails. With
+// "--propose", it signs the operation of [cmd] into a proposal file and
+// returns false so that nothing is issued. With "--approve", it returns
+// true only if the proposal is valid, matches the operation and was made
+// with another key; the approval is recorded in the proposal file once a
+// tx is accepted (see [Info.RecordApproval]).
+func (i *Info) CheckApproval(ctx context.Context, cmd *cobra.Command) (bool, error) {
+	if proposePath != "" && approvePath != "" {
+		return false, fmt.Errorf("%w: --propose and --approve", approval.ErrMismatch)
+	}
+	path := policy.Find(policyPath)
+	if proposePath == "" && approvePath == "" {
+		if path == "" {
+			return true, nil
+		}
+		pol, err := policy.Load(path)
+		if err != nil {
+			return false, err
+		}
+		if pol.RequiresApproval() {
+			color.Outf("{{red}}{{bold}}policy %q requires two-person approval (--propose, then --approve){{/}}\n", pol.Path())
+			return false, fmt.Errorf("%w: %s", policy.ErrApprovalRequired, pol.Path())
+		}
+		return true, nil
+	}
+	if path == "" {
+		return false, fmt.Errorf("%w: two-person approval needs a policy (--policy-path)", policy.ErrNoApprovalList)
+	}
+	pol, err := policy.Load(path)
+	if err != nil {
+		return false, err
+	}
+	s, err := signerOf(i.key)
+	if err != nil {
+		return false, err
+	}
+	addrs := s.Addresses()
+	if len(addrs) == 0 {
+		return false, fmt.Errorf("%w: signer has no address", key.ErrCantSign)
+	}
+	now := time.Now().UTC()
+	op := i.proposal(cmd, now)
+
+	if proposePath != "" {
+		if err := pol.CheckProposer(addrs[0]); err != nil {
+			color.Outf("{{red}}{{bold}}refused by policy %q: %v{{/}}\n", pol.Path(), err)
+			return false, err
+		}
+		if err := op.Sign(ctx, s); err != nil {
+			return false, err
+		}
+		if err := op.Save(proposePath); err != nil {
+			return false, err
+		}
+		color.Outf("{{magenta}}wrote proposal{{/}} %q {{light-gray}}(proposer %s, expires %s){{/}}\n", proposePath, op.Proposer, op.Expires.Format(time.RFC3339))
+		color.Outf("{{blue}}a second operator must re-run the same command with --approve=%s{{/}}\n", proposePath)
+		if showQR {
+			b, err := json.Marshal(op)
+			if err != nil {
+				return false, err
+			}
+			printQR("proposal "+proposePath, string(b), qr.Low)
+		}
+		color.Result(proposePath)
+		return false, nil
+	}
+
+	p, err := approval.Load(approvePath)
+	if err != nil {
+		return false, err
+	}
+	if err := p.Verify(); err != nil {
+		return false, err
+	}
+	if err := p.Match(op); err != nil {
+		color.Outf("{{red}}{{bold}}proposal %q does not match this command{{/}}\n", approvePath)
+		return false, err
+	}
+	// the proposal file is signed by its proposer, so check it again
+	if err := pol.CheckProposer(p.Proposer); err != nil {
+		color.Outf("{{red}}{{bold}}refused by policy %q: %v{{/}}\n", pol.Path(), err)
+		return false, err
+	}
+	if err := pol.CheckApprover(addrs[0]); err != nil {
+		color.Outf("{{red}}{{bold}}refused by policy %q: %v{{/}}\n", pol.Path(), err)
+		return false, err
+	}
+	if err := p.Approve(ctx, s, now); err != nil {
+		return false, err
+	}
+	i.approved = p
+	color.Outf("{{green}}verified proposal{{/}} %q {{light-gray}}(proposer %s, approver %s; recorded once a tx is accepted){{/}}\n", approvePath, p.Proposer, p.Approval.Approver)
+	return true, nil
+}
+
+// RecordApproval records the approval checked by [Info.CheckApproval] in
+// the proposal file, so that it can't be approved again. Commands call it
+// after each accepted tx; only the first call writes.
+func (i *Info) RecordApproval() error {
+	if i.approved == nil {
+		return nil
+	}
+	if err := i.approved.Save(approvePath); err != nil {
+		return err
+	}
+	color.Outf("{{green}}approved proposal{{/}} %q {{light-gray}}(proposer %s, approver %s){{/}}\n", approvePath, i.approved.Proposer, i.approved.Approval.Approver)
+	i.approved = nil
+	return nil
+}
+
+// proposal describes the operation of [cmd] as run.
+func (i *Info) proposal(cmd *cobra.Command, now time.Time) *approval.Proposal {
+	flags := map[string]string{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if _, ok := unboundFlags[f.Name]; ok {
+			return
+		}
+		flags[f.Name] = f.Value.String()
+	})
+	return &approval.Proposal{
+		Version:      approval.CurrentVersion,
+		Command:      strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Args:         cmd.Flags().Args(),
+		Flags:        flags,
+		Network:      i.networkName,
+		Spend:        i.spend(),
+		WeightChange: i.weightChange,
+		Created:      now,
+		Expires:      now.Add(approval.DefaultTTL),
+	}
+}
+
+// signerOf returns the signing backend of [k].
+func signerOf(k key.Key) (key.Signer, error) {
+	switch s := k.(type) {
+	case *key.SignerKey:
+		return s.Signer(), nil
+	case key.Signer:
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: %T can't sign proposals", key.ErrCantSign, k)
+}
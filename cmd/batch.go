@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+)
+
+// batch tracks the cost (fee + stake, in nano-AVAX) of every tx a multi-tx
+// operation still has to issue, so the P-Chain balance can be re-checked
+// between txs.
+type batch struct {
+	costs []uint64
+}
+
+// add plans [n] txs of [txType], each locking [stake] in addition to the
+// tx fee.
+func (b *batch) add(ctx context.Context, i *Info, txType client.TxType, n int, stake uint64) error {
+	if n == 0 {
+		return nil
+	}
+	fee, err := i.Fee(ctx, txType, 1)
+	if err != nil {
+		return err
+	}
+	for j := 0; j < n; j++ {
+		b.costs = append(b.costs, fee+stake)
+	}
+	return nil
+}
+
+// remaining returns the total cost of all txs not yet issued.
+func (b *batch) remaining() (total uint64) {
+	for _, c := range b.costs {
+		total += c
+	}
+	return total
+}
+
+// check re-fetches the P-Chain balance and fails early if it no longer
+// covers the remaining txs.
+func (b *batch) check(ctx context.Context, cli client.Client, i *Info) error {
+	if len(b.costs) == 0 {
+		return nil
+	}
+	balance, err := cli.P().Balance(ctx, i.key)
+	if err != nil {
+		return err
+	}
+	i.balance = balance
+	need := b.remaining()
+	if balance >= need {
+		return nil
+	}
+	return fmt.Errorf("%w: on %s need %s more to finish the remaining %d txs (expected=%d, have=%d)",
+		ErrInsufficientFunds, i.key.P(), amount.Format(need-balance), len(b.costs), need, balance)
+}
+
+// done marks the next planned tx as issued.
+func (b *batch) done() {
+	if len(b.costs) > 0 {
+		b.costs = b.costs[1:]
+	}
+}
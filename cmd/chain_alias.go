@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var ErrAliasFailed = errors.New("failed to alias chain")
+
+// AliasChain sets [alias] for the new blockchain with the admin API of
+// every validator in "--validator-uris" (default to the connected URI),
+// and records it with the blockchain name. The nodes must run with
+// "--api-admin-enabled". Aliases set by the API do not survive a restart,
+// so the chain aliases file of the nodes should be updated as well.
+func AliasChain(ctx context.Context, i *Info, alias string) error {
+	uris := validatorURIs
+	if len(uris) == 0 {
+		uris = []string{i.uri}
+	}
+	color.Outf("\n{{blue}}{{bold}}Aliasing %s as %q:{{/}}\n", i.blockchainID, alias)
+	failed := 0
+	for _, uri := range uris {
+		err := client.AliasChain(ctx, uri, i.blockchainID.String(), alias)
+		if err != nil {
+			failed++
+			color.Outf("  {{red}}%s: %v{{/}}\n", uri, err)
+			continue
+		}
+		color.Outf("  {{cyan}}%s/ext/bc/%s/rpc{{/}}\n", strings.TrimSuffix(uri, "/"), alias)
+	}
+
+	reg, err := nameRegistry()
+	if err == nil {
+		err = reg.SetAlias(i.networkID, i.blockchainID, alias)
+	}
+	if err == nil {
+		err = reg.Save()
+	}
+	if err != nil {
+		color.Outf("{{yellow}}failed to record alias %q: %v{{/}}\n", alias, err)
+	}
+
+	color.Outf("{{yellow}}to keep the alias across restarts, add it to the chain aliases file of the nodes:{{/}}\n")
+	color.Outf("  {\"%s\": [\"%s\"]}\n", i.blockchainID, alias)
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d nodes (is \"--api-admin-enabled\" set?)", ErrAliasFailed, failed, len(uris))
+	}
+	return nil
+}
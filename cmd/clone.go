@@ -0,0 +1,317 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/spec"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// cloneSpecFile is the spec "clone" writes to "--output-dir".
+const cloneSpecFile = "clone.spec.yaml"
+
+var (
+	cloneFromURI    string
+	cloneFromSubnet string
+	cloneToNetwork  string
+	cloneToURI      string
+	cloneOutputDir  string
+
+	ErrUnknownTargetNetwork = errors.New("unknown target network")
+)
+
+// publicURIs are the public API endpoints of the well-known networks.
+var publicURIs = map[string]string{
+	constants.MainnetName: "https://api.avax.network",
+	constants.FujiName:    "https://api.avax-test.network",
+}
+
+// CloneCommand implements "subnet-cli clone" command.
+func CloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Replays the deployment of a subnet onto another network",
+		Long: `
+Reads the chains (with their genesis, decoded from the CreateChainTx) and
+the current validator weights of a subnet, writes them as a deployment spec
+with genesis files to "--output-dir", then creates the subnet, its
+validators and its chains on "--to-network" (e.g., staging to production).
+The subnet validators must already validate the primary network of the
+target network; those that do not are skipped.
+
+$ subnet-cli clone \
+--from-uri=http://localhost:57786 \
+--from-subnet=24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1 \
+--to-network=fuji \
+--private-key-path=.fuji.key
+
+Use "--dry-run" to only write the spec (e.g., to review it with
+"subnet-cli simulate -f clone/clone.spec.yaml").
+
+`,
+		Args: cobra.NoArgs,
+		RunE: cloneFunc,
+	}
+	cmd.PersistentFlags().StringVar(&cloneFromURI, "from-uri", "https://api.avax-test.network", "URI of the network of the source subnet")
+	cmd.PersistentFlags().StringVar(&cloneFromSubnet, "from-subnet", "", "source subnet ID or name (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&cloneToNetwork, "to-network", constants.FujiName, "target network name (fuji, mainnet or local)")
+	cmd.PersistentFlags().StringVar(&cloneToURI, "to-uri", "", "URI of the target network (defaults to the public API of --to-network)")
+	cmd.PersistentFlags().StringVar(&cloneOutputDir, "output-dir", "clone", "directory to write the spec and genesis files to")
+	cmd.PersistentFlags().StringVar(&subnetName, "subnet-name", "", "name of the new subnet (defaults to the source name)")
+	addSignerFlags(cmd)
+	addFailureReportFlag(cmd)
+	return cmd
+}
+
+func cloneFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	toNetworkID, err := constants.NetworkID(cloneToNetwork)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnknownTargetNetwork, cloneToNetwork)
+	}
+	toURI := cloneToURI
+	if toURI == "" {
+		var ok bool
+		if toURI, ok = publicURIs[cloneToNetwork]; !ok {
+			return fmt.Errorf("%w: %q needs --to-uri", ErrUnknownTargetNetwork, cloneToNetwork)
+		}
+	}
+
+	src, srcInfo, err := InitReadClient(ctx, cloneFromURI)
+	if err != nil {
+		return err
+	}
+	srcSubnetID, err := resolveSubnetID(srcInfo.networkID, cloneFromSubnet)
+	if err != nil {
+		return err
+	}
+	s, err := readClone(ctx, src, srcInfo.networkID, srcSubnetID)
+	if err != nil {
+		return err
+	}
+	p := filepath.Join(cloneOutputDir, cloneSpecFile)
+	if err := s.Save(p); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}wrote clone spec{{/}} %q\n", p)
+	if dryRun {
+		color.Result(p)
+		return nil
+	}
+
+	cli, info, err := InitClient(ctx, toURI, true)
+	if err != nil {
+		return err
+	}
+	if info.networkID != toNetworkID {
+		return fmt.Errorf("%w: --to-network %s, %s reports %s", ErrNetworkMismatch, cloneToNetwork, toURI, info.networkName)
+	}
+	return applyClone(ctx, cmd, cli, info, s)
+}
+
+// readClone reads subnet [subnetID] as a spec for "--to-network".
+func readClone(ctx context.Context, src client.Client, networkID uint32, subnetID ids.ID) (*spec.Spec, error) {
+	weights, err := src.P().GetValidatorWeights(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	bcs, err := src.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chains := []spec.SourceChain{}
+	for _, bc := range bcs {
+		if bc.SubnetID != subnetID {
+			continue
+		}
+		b, err := src.P().GetBlockchain(ctx, bc.ID)
+		if err != nil {
+			// e.g., the node pruned or never indexed the tx
+			color.Outf("{{yellow}}skipping chain %q: genesis not retrievable: %v{{/}}\n", bc.Name, err)
+			continue
+		}
+		chains = append(chains, spec.SourceChain{Name: b.Name, VMID: b.VMID, Genesis: b.Genesis})
+	}
+
+	name := subnetName
+	if name == "" {
+		name = fmt.Sprintf("clone-of-%s", subnetID)
+		if reg, err := nameRegistry(); err == nil {
+			if n, ok := reg.Name(networkID, names.KindSubnet, subnetID); ok {
+				name = n
+			}
+		}
+	}
+	color.Outf("{{blue}}read subnet %s: %d validators, %d chains{{/}}\n", subnetID, len(weights), len(chains))
+	return spec.Clone(cloneToNetwork, name, weights, chains, cloneOutputDir)
+}
+
+// applyClone creates the subnet of [s], adds its validators that validate
+// the primary network and creates its chains.
+func applyClone(ctx context.Context, cmd *cobra.Command, cli client.Client, info *Info, s *spec.Spec) error {
+	sn := s.Subnets[0]
+	type cloneValidator struct {
+		nodeID ids.ShortID
+		weight uint64
+		end    time.Time
+	}
+	validators := []cloneValidator{}
+	for _, v := range sn.Validators {
+		nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		_, end, err := cli.P().GetValidator(ctx, ids.Empty, nodeID)
+		if err != nil {
+			color.Outf("{{yellow}}skipping %s: not a primary network validator on %s{{/}}\n", v.NodeID, info.networkName)
+			continue
+		}
+		validators = append(validators, cloneValidator{nodeID: nodeID, weight: v.Weight, end: end})
+		info.weightChange += v.Weight
+	}
+
+	fee := uint64(0)
+	for _, t := range []struct {
+		txType client.TxType
+		n      int
+	}{
+		{client.TxTypeCreateSubnet, 1},
+		{client.TxTypeAddSubnetValidator, len(validators)},
+		{client.TxTypeCreateBlockchain, len(sn.Chains)},
+	} {
+		f, err := info.Fee(ctx, t.txType, t.n)
+		if err != nil {
+			return err
+		}
+		fee += f
+	}
+	info.txFee = fee
+	info.requiredBalance = fee
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}SUBNET NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", sn.Name)})
+	for _, v := range validators {
+		tb.Append([]string{color.F("{{orange}}%s{{/}}", v.nodeID.PrefixedString(constants.NodeIDPrefix)), color.F("{{light-gray}}weight %d, until %s{{/}}", v.weight, v.end.Format(time.RFC3339))})
+	}
+	for _, c := range sn.Chains {
+		tb.Append([]string{color.F("{{dark-green}}CHAIN %s{{/}}", c.Name), color.F("{{light-gray}}{{bold}}%s{{/}}", c.VMID)})
+	}
+	tb.Render()
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	// the clone starts without validators
+	cloneWeights := make(map[ids.ShortID]uint64, len(validators))
+	for _, v := range validators {
+		cloneWeights[v.nodeID] = v.weight
+	}
+	changes = append(validatorChanges(false, nil, cloneWeights), changes...)
+	if !confirmChanges(buf.String(), changes, fmt.Sprintf("clone the subnet onto %s", info.networkName), "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+
+	r := rollback.New("clone", info.networkID)
+	r.Plan(rollback.Action{Kind: rollback.KindCreateSubnet, Name: sn.Name})
+	for _, v := range validators {
+		a := validatorAction(rollback.KindAddSubnetValidator, v.nodeID, v.end)
+		a.Weight = v.weight
+		r.Plan(a)
+	}
+	for _, c := range sn.Chains {
+		r.Plan(rollback.Action{Kind: rollback.KindCreateBlockchain, Name: c.Name, VMID: c.VMID, GenesisPath: s.GenesisPath(c)})
+	}
+
+	subnetID, took, err := cli.P().CreateSubnet(ctx, info.key, client.WithMemo(names.Memo(sn.Name)))
+	if err != nil {
+		return reportRollback(info, r, err)
+	}
+	r.Complete(rollback.Action{Kind: rollback.KindCreateSubnet, SubnetID: subnetID.String(), Name: sn.Name})
+	// the remaining actions need the new subnet
+	for j := range r.Remaining {
+		r.Remaining[j].SubnetID = subnetID.String()
+	}
+	if err := info.RecordApproval(); err != nil {
+		return reportRollback(info, r, err)
+	}
+	info.subnetID = subnetID
+	color.Outf("{{magenta}}created subnet{{/}} %q {{light-gray}}(took %v){{/}}\n", subnetID, took)
+	color.Result(subnetID)
+	recordName(info.networkID, names.KindSubnet, sn.Name, subnetID)
+
+	for _, v := range validators {
+		info.validateStart = time.Now().Add(defaultValidateStartBuffer)
+		info.validateEnd = v.end
+		took, err := refreshStartTime(info, false, func() (time.Duration, error) {
+			return cli.P().AddSubnetValidator(ctx, info.key, subnetID, v.nodeID, info.validateStart, info.validateEnd, v.weight)
+		})
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		a := validatorAction(rollback.KindAddSubnetValidator, v.nodeID, v.end)
+		a.SubnetID, a.Weight = subnetID.String(), v.weight
+		r.Complete(a)
+		color.Outf("{{magenta}}added %s to subnet %s validator set{{/}} {{light-gray}}(took %v){{/}}\n", v.nodeID, subnetID, took)
+	}
+
+	for _, c := range sn.Chains {
+		vmID, err := ids.FromString(c.VMID)
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		genesis, err := ioutil.ReadFile(s.GenesisPath(c))
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		blockchainID, took, err := cli.P().CreateBlockchain(ctx, info.key, subnetID, c.Name, vmID, genesis)
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		r.Complete(rollback.Action{
+			Kind:         rollback.KindCreateBlockchain,
+			SubnetID:     subnetID.String(),
+			BlockchainID: blockchainID.String(),
+			Name:         c.Name,
+			VMID:         c.VMID,
+			GenesisPath:  s.GenesisPath(c),
+		})
+		color.Outf("{{magenta}}created blockchain{{/}} %q {{light-gray}}(%s, took %v){{/}}\n", blockchainID, c.Name, took)
+		color.Result(blockchainID)
+		recordName(info.networkID, names.KindBlockchain, c.Name, blockchainID)
+	}
+
+	// record the new subnet ID, so the spec describes the clone from now on
+	s.Subnets[0].ID = subnetID.String()
+	return s.Save(filepath.Join(cloneOutputDir, cloneSpecFile))
+}
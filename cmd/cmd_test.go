@@ -0,0 +1,1314 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/client/clienttest"
+	"github.com/ava-labs/subnet-cli/internal/alert"
+	"github.com/ava-labs/subnet-cli/internal/approval"
+	"github.com/ava-labs/subnet-cli/internal/decommission"
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/feeconfig"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/liveness"
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/internal/policy"
+	"github.com/ava-labs/subnet-cli/internal/precompile"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+type testFactory struct {
+	cli client.Client
+	k   key.Key
+}
+
+func (f *testFactory) NewClient(context.Context, client.Config) (client.Client, error) {
+	return f.cli, nil
+}
+
+func (f *testFactory) LoadKey(context.Context, uint32) (key.Key, error) { return f.k, nil }
+
+// newTestFactory returns a factory of [fake] and a key holding [balance]
+// on the P-Chain.
+func newTestFactory(t *testing.T, fake *clienttest.Fake, balance uint64) *testFactory {
+	k, err := key.NewSoft(constants.LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.SetBalance(k.Addresses()[0], balance)
+	return &testFactory{cli: fake, k: k}
+}
+
+// run executes the command line [args] with [f] in quiet mode and returns
+// the results it printed. The commands share package state (flag values,
+// output), so tests using it must not run in parallel.
+func run(t *testing.T, f Factory, args ...string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	stdout := color.Stdout
+	color.Stdout = buf
+	defer func() { color.Stdout = stdout }()
+	// the name registries are loaded once per invocation
+	registryOnce, vmRegistryOnce = sync.Once{}, sync.Once{}
+
+	root := NewRootCommand()
+	root.SetArgs(append(args, "--quiet", "--enable-prompt=false"))
+	root.SetOut(ioutil.Discard)
+	root.SetErr(ioutil.Discard)
+	err := root.ExecuteContext(WithFactory(context.Background(), f))
+	return buf.String(), err
+}
+
+func TestFactoryFrom(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := factoryFrom(context.Background()).(DefaultFactory); !ok {
+		t.Fatal("expected the default factory")
+	}
+	f := &testFactory{}
+	if got := factoryFrom(WithFactory(context.Background(), f)); got != f {
+		t.Fatalf("unexpected factory %T", got)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	t.Parallel()
+
+	id := ids.GenerateTestShortID()
+	addr, err := key.FormatPAddress(constants.LocalID, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{addr, id.String()} {
+		got, err := ParseAddress(constants.LocalID, s)
+		if err != nil || got != id {
+			t.Fatalf("%s: unexpected address %s (%v)", s, got, err)
+		}
+	}
+	if _, err := ParseAddress(constants.FujiID, addr); !errors.Is(err, key.ErrInvalidAddressHRP) {
+		t.Fatalf("expected %v, got %v", key.ErrInvalidAddressHRP, err)
+	}
+	for _, s := range []string{"NodeID-" + id.String(), "X" + addr[1:], "local1abc"} {
+		if _, err := ParseAddress(constants.LocalID, s); !errors.Is(err, ErrUnknownAddress) {
+			t.Fatalf("%s: expected %v, got %v", s, ErrUnknownAddress, err)
+		}
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	b, err := node.NewBundle(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := b.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	out, err := run(t, newTestFactory(t, clienttest.New(), 0), "node", "id", "--cert="+filepath.Join(dir, node.StakingCertFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := b.NodeID.PrefixedString(constants.NodeIDPrefix); strings.TrimSpace(out) != exp {
+		t.Fatalf("unexpected output %q, expected %q", out, exp)
+	}
+}
+
+func TestAddressConvert(t *testing.T) {
+	id := ids.GenerateTestShortID()
+	addr, err := key.FormatPAddress(constants.LocalID, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := run(t, newTestFactory(t, clienttest.New(), 0), "address", "convert", addr, "--to=short-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != id.String() {
+		t.Fatalf("unexpected output %q, expected %q", out, id)
+	}
+}
+
+func TestCreateSubnet(t *testing.T) {
+	tt := []struct {
+		name    string
+		args    []string
+		balance uint64
+		txs     int
+		err     error
+	}{
+		{name: "issue", balance: 10 * clienttest.DefaultFee, txs: 1},
+		{name: "dry run", args: []string{"--dry-run"}, balance: 10 * clienttest.DefaultFee},
+		{name: "insufficient funds", balance: clienttest.DefaultFee - 1, err: ErrInsufficientFunds},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New()
+		out, err := run(t, newTestFactory(t, fake, tv.balance), append([]string{"create", "subnet"}, tv.args...)...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		txs := fake.Txs()
+		if len(txs) != tv.txs {
+			t.Fatalf("%s: expected %d txs, got %d", tv.name, tv.txs, len(txs))
+		}
+		expected := ""
+		if len(txs) > 0 {
+			expected = txs[0].ID.String() + "\n"
+		}
+		if strings.TrimLeft(out, "\n") != expected {
+			t.Fatalf("%s: expected output %q, got %q", tv.name, expected, out)
+		}
+	}
+}
+
+// countingFees counts the fee lookups of each tx type.
+type countingFees struct {
+	calls map[client.TxType]int
+}
+
+func (c *countingFees) Fee(_ context.Context, txType client.TxType, _ int) (uint64, error) {
+	c.calls[txType]++
+	return clienttest.DefaultFee, nil
+}
+
+func TestNetworksJSONRequiresValidatorURIs(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "networks.json")
+	_, err := run(t, newTestFactory(t, clienttest.New(), 0), "create", "blockchain", "--networks-json="+out)
+	if !errors.Is(err, ErrNoValidatorURIs) {
+		t.Fatalf("expected %v, got %v", ErrNoValidatorURIs, err)
+	}
+}
+
+func TestSmokeTestRequiresNodeURI(t *testing.T) {
+	_, err := run(t, newTestFactory(t, clienttest.New(), 0), "create", "blockchain", "--smoke-test")
+	if !errors.Is(err, ErrNoSmokeNodeURI) {
+		t.Fatalf("expected %v, got %v", ErrNoSmokeNodeURI, err)
+	}
+}
+
+func TestCachedFees(t *testing.T) {
+	t.Parallel()
+
+	counting := &countingFees{calls: make(map[client.TxType]int)}
+	fees := newCachedFees(counting)
+	for i := 0; i < 3; i++ {
+		for _, txType := range []client.TxType{client.TxTypeCreateSubnet, client.TxTypeBase} {
+			fee, err := fees.Fee(context.Background(), txType, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fee != clienttest.DefaultFee {
+				t.Fatalf("unexpected fee %d", fee)
+			}
+		}
+	}
+	for txType, n := range counting.calls {
+		if n != 1 {
+			t.Fatalf("%s: expected 1 lookup, got %d", txType, n)
+		}
+	}
+}
+
+func TestStatusNetwork(t *testing.T) {
+	fake := clienttest.New()
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "network"); err != nil {
+		t.Fatal(err)
+	}
+	fake.Fail("GetMinStake", errors.New("unavailable"))
+	fake.Fail("GetCurrentSupply", errors.New("unavailable"))
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "network"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, newTestFactory(t, fake, 5*clienttest.DefaultFee), "status", "network", "--format=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st networkStatus
+	if err := json.Unmarshal([]byte(out), &st); err != nil {
+		t.Fatal(err)
+	}
+	cfg := genesis.GetStakingConfig(constants.LocalID)
+	if st.NetworkID != constants.LocalID || st.MinValidatorStake != cfg.MinValidatorStake || st.MaxStakeDurationSeconds != uint64(cfg.MaxStakeDuration/time.Second) {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	if st.CurrentSupply == nil || *st.CurrentSupply != 5*clienttest.DefaultFee {
+		t.Fatalf("unexpected supply %v", st.CurrentSupply)
+	}
+	for _, f := range st.Fees {
+		if f.Fee == nil || *f.Fee != clienttest.DefaultFee {
+			t.Fatalf("unexpected %s fee %v (%s)", f.TxType, f.Fee, f.Error)
+		}
+	}
+
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "network", "--format=yaml"); !errors.Is(err, ErrUnknownExportFormat) {
+		t.Fatalf("expected %v, got %v", ErrUnknownExportFormat, err)
+	}
+}
+
+func TestStatusWarp(t *testing.T) {
+	fake := clienttest.New()
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, ids.GenerateTestShortID())
+	nodeIDs := []ids.ShortID{ids.GenerateTestShortID(), ids.GenerateTestShortID()}
+	for _, nodeID := range nodeIDs {
+		fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 90, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	}
+	fake.AddValidator(subnetID, client.Validator{NodeID: ids.GenerateTestShortID(), Weight: 20, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	for _, nodeID := range nodeIDs {
+		fake.SetBLSPublicKey(nodeID, "0x01")
+	}
+
+	// above quorum, but one validator has no BLS key
+	_, err := run(t, newTestFactory(t, fake, 0), "status", "warp", "--subnet-id="+subnetID.String())
+	if !errors.Is(err, ErrWarpNotReady) || !strings.Contains(err.Error(), "1 of 3 validators") {
+		t.Fatalf("expected %v, got %v", ErrWarpNotReady, err)
+	}
+}
+
+func TestIssueAt(t *testing.T) {
+	fake := clienttest.New()
+	start := time.Now()
+	if _, err := run(t, newTestFactory(t, fake, 10*clienttest.DefaultFee), "create", "subnet", "--issue-at=now+500ms"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Fatalf("issued after %v, before --issue-at", elapsed)
+	}
+	if txs := fake.Txs(); len(txs) != 1 {
+		t.Fatalf("expected 1 tx, got %d", len(txs))
+	}
+
+	// the fake P-Chain height is its number of accepted txs
+	if _, err := run(t, newTestFactory(t, fake, 10*clienttest.DefaultFee), "create", "subnet", "--issue-at-height=1"); err != nil {
+		t.Fatal(err)
+	}
+	if txs := fake.Txs(); len(txs) != 2 {
+		t.Fatalf("expected 2 txs, got %d", len(txs))
+	}
+
+	_, err := run(t, newTestFactory(t, fake, 10*clienttest.DefaultFee), "create", "subnet", "--issue-at=now+2h", "--expire-at=now+1h")
+	if !errors.Is(err, ErrInvalidIssueAt) {
+		t.Fatalf("expected %v, got %v", ErrInvalidIssueAt, err)
+	}
+
+	// --timeout bounds the whole command, after the --issue-at wait
+	if _, err := run(t, newTestFactory(t, fake, 10*clienttest.DefaultFee), "create", "subnet", "--issue-at=now+500ms", "--timeout=400ms"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = run(t, newTestFactory(t, fake, 10*clienttest.DefaultFee), "create", "subnet", "--issue-at-height=100", "--timeout=200ms", "--poll-interval=10ms")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if txs := fake.Txs(); len(txs) != 3 {
+		t.Fatalf("expected 3 txs, got %d", len(txs))
+	}
+}
+
+func TestFailureReport(t *testing.T) {
+	tt := []struct {
+		nodeVersion string
+		undo        bool
+	}{
+		// subnet validators can be removed since Banff
+		{nodeVersion: "avalanche/1.9.0", undo: true},
+		{nodeVersion: "avalanche/1.7.6"},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New(clienttest.WithNodeVersion(tv.nodeVersion))
+		f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+		subnetID := ids.GenerateTestID()
+		fake.AddSubnet(subnetID, f.k.Addresses()[0])
+		nodeIDs := []string{}
+		for i := 0; i < 2; i++ {
+			nodeID := ids.GenerateTestShortID()
+			fake.AddValidator(ids.Empty, client.Validator{NodeID: nodeID, Start: time.Now().Add(-time.Hour), End: time.Now().Add(30 * 24 * time.Hour)})
+			nodeIDs = append(nodeIDs, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		fake.Fail("AddSubnetValidator", nil)
+		errTest := errors.New("unavailable")
+		fake.Fail("AddSubnetValidator", errTest)
+
+		p := filepath.Join(t.TempDir(), "report.json")
+		_, err := run(t, f, "add", "subnet-validator", "--subnet-id="+subnetID.String(), "--node-ids="+strings.Join(nodeIDs, ","), "--failure-report="+p)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("%s: expected %v, got %v", tv.nodeVersion, errTest, err)
+		}
+		if txs := fake.Txs(); len(txs) != 1 {
+			t.Fatalf("%s: expected 1 tx, got %d", tv.nodeVersion, len(txs))
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r rollback.Report
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Operation != "add subnet-validator" || r.Error == "" || len(r.Done) != 1 || len(r.Remaining) != 1 {
+			t.Fatalf("%s: unexpected report %+v", tv.nodeVersion, r)
+		}
+		done := r.Done[0]
+		if done.SubnetID != subnetID.String() || done.End == nil {
+			t.Fatalf("%s: unexpected done action %+v", tv.nodeVersion, done)
+		}
+		if tv.undo && !strings.HasPrefix(done.Undo, "subnet-cli remove subnet-validator") || !tv.undo && (done.Undo != "" || done.Note == "") {
+			t.Fatalf("%s: unexpected undo %q (%q)", tv.nodeVersion, done.Undo, done.Note)
+		}
+		if resume := r.Remaining[0].Resume; !strings.Contains(resume, "--subnet-id="+subnetID.String()) || !strings.Contains(resume, "--node-ids="+r.Remaining[0].NodeID) {
+			t.Fatalf("%s: unexpected resume command %q", tv.nodeVersion, resume)
+		}
+	}
+}
+
+func TestRemoveSubnetValidator(t *testing.T) {
+	fake := clienttest.New(clienttest.WithNodeVersion("avalanche/1.9.0"))
+	f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, f.k.Addresses()[0])
+	nodeID := ids.GenerateTestShortID()
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+
+	args := []string{"remove", "subnet-validator", "--subnet-id=" + subnetID.String()}
+	if _, err := run(t, f, append(args, "--node-ids="+ids.GenerateTestShortID().PrefixedString(constants.NodeIDPrefix))...); !errors.Is(err, client.ErrValidatorNotFound) {
+		t.Fatalf("expected %v, got %v", client.ErrValidatorNotFound, err)
+	}
+	args = append(args, "--node-ids="+nodeID.PrefixedString(constants.NodeIDPrefix))
+	if _, err := run(t, f, args...); err != nil {
+		t.Fatal(err)
+	}
+	if txs := fake.Txs(); len(txs) != 1 || txs[0].Type != client.TxTypeRemoveSubnetValidator {
+		t.Fatalf("unexpected txs %+v", txs)
+	}
+
+	// without RemoveSubnetValidatorTx, validators stay until their end
+	fake = clienttest.New()
+	f = newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	fake.AddSubnet(subnetID, f.k.Addresses()[0])
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	if _, err := run(t, f, args...); !errors.Is(err, ErrUpgradeInactive) {
+		t.Fatalf("expected %v, got %v", ErrUpgradeInactive, err)
+	}
+	if txs := fake.Txs(); len(txs) != 0 {
+		t.Fatalf("unexpected txs %+v", txs)
+	}
+}
+
+func TestSetWeight(t *testing.T) {
+	fake := clienttest.New(clienttest.WithNodeVersion("avalanche/1.9.0"))
+	f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, f.k.Addresses()[0])
+	nodeID := ids.GenerateTestShortID()
+	primaryEnd := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	fake.AddValidator(ids.Empty, client.Validator{NodeID: nodeID, Start: time.Now().Add(-time.Hour), End: primaryEnd})
+	subnetEnd := time.Now().Add(time.Hour).Truncate(time.Second)
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: subnetEnd})
+
+	args := []string{"set", "weight", "--subnet-id=" + subnetID.String(), "--node-ids=" + nodeID.PrefixedString(constants.NodeIDPrefix), "--validate-weight=20"}
+	if _, err := run(t, f, args...); err != nil {
+		t.Fatal(err)
+	}
+	txs := fake.Txs()
+	if len(txs) != 2 || txs[0].Type != client.TxTypeRemoveSubnetValidator || txs[1].Type != client.TxTypeAddSubnetValidator {
+		t.Fatalf("unexpected txs %+v", txs)
+	}
+	// re-added shortly after issuing, until the subnet validation ends
+	if _, end, err := fake.P().GetPendingValidator(context.Background(), subnetID, nodeID); err != nil || !end.Equal(subnetEnd) {
+		t.Fatalf("unexpected end %v (%v)", end, err)
+	}
+
+	// a failed re-add reports how to restore the validator
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 20, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	errTest := errors.New("unavailable")
+	fake.Fail("AddSubnetValidator", errTest)
+	p := filepath.Join(t.TempDir(), "report.json")
+	args30 := append(args[:len(args)-1:len(args)-1], "--validate-weight=30", "--failure-report="+p)
+	if _, err := run(t, f, args30...); !errors.Is(err, errTest) {
+		t.Fatalf("expected %v, got %v", errTest, err)
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r rollback.Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Done) != 1 || r.Done[0].Kind != rollback.KindRemoveSubnetValidator || !strings.Contains(r.Done[0].Undo, "--validate-weight=20") {
+		t.Fatalf("unexpected done actions %+v", r.Done)
+	}
+	if len(r.Remaining) != 1 || !strings.Contains(r.Remaining[0].Resume, "--validate-weight=30") {
+		t.Fatalf("unexpected remaining actions %+v", r.Remaining)
+	}
+
+	// without RemoveSubnetValidatorTx, the weight can't change
+	fake = clienttest.New()
+	f = newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	fake.AddSubnet(subnetID, f.k.Addresses()[0])
+	fake.AddValidator(ids.Empty, client.Validator{NodeID: nodeID, Start: time.Now().Add(-time.Hour), End: primaryEnd})
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	if _, err := run(t, f, args...); !errors.Is(err, ErrUpgradeInactive) {
+		t.Fatalf("expected %v, got %v", ErrUpgradeInactive, err)
+	}
+	if txs := fake.Txs(); len(txs) != 0 {
+		t.Fatalf("unexpected txs %+v", txs)
+	}
+}
+
+func TestAddValidator(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	validating := ids.GenerateTestShortID()
+	tt := []struct {
+		name    string
+		nodeID  ids.ShortID
+		args    []string
+		balance uint64
+		txs     int
+		err     error
+	}{
+		// the fake reports the validator once its start passes, so the
+		// wait for it runs into --timeout
+		{name: "issue", nodeID: nodeID, args: []string{"--stake-amount=2000avax", "--validate-start=now+1m", "--duration=30d", "--timeout=1s"}, balance: 3000 * units.Avax, txs: 1, err: context.DeadlineExceeded},
+		{name: "dry run", nodeID: nodeID, args: []string{"--dry-run"}, balance: 3000 * units.Avax},
+		{name: "already validating", nodeID: validating, balance: 3000 * units.Avax},
+		{name: "reward fee", nodeID: nodeID, args: []string{"--validate-reward-fee-percent=1"}, balance: 3000 * units.Avax, err: errInvalidValidateRewardFeePercent},
+		{name: "short window", nodeID: nodeID, args: []string{"--validate-end=now+1h"}, balance: 3000 * units.Avax, err: ErrInvalidValidateWindow},
+		{name: "insufficient funds", nodeID: nodeID, args: []string{"--stake-amount=2000avax"}, balance: 1999 * units.Avax, err: ErrInsufficientFunds},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New()
+		fake.AddValidator(ids.Empty, client.Validator{NodeID: validating, Start: time.Now().Add(-time.Hour), End: time.Now().Add(30 * 24 * time.Hour)})
+		args := append([]string{"add", "validator", "--node-ids=" + tv.nodeID.PrefixedString(constants.NodeIDPrefix)}, tv.args...)
+		_, err := run(t, newTestFactory(t, fake, tv.balance), args...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		txs := fake.Txs()
+		if len(txs) != tv.txs {
+			t.Fatalf("%s: expected %d txs, got %d", tv.name, tv.txs, len(txs))
+		}
+		if tv.txs == 0 {
+			continue
+		}
+		if txs[0].Type != client.TxTypeAddValidator {
+			t.Fatalf("%s: unexpected txs %+v", tv.name, txs)
+		}
+		start, end, err := fake.P().GetPendingValidator(context.Background(), ids.Empty, tv.nodeID)
+		if err != nil || end.Sub(start) != 30*24*time.Hour {
+			t.Fatalf("%s: unexpected window %v - %v (%v)", tv.name, start, end, err)
+		}
+	}
+}
+
+func TestAddSubnetValidator(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestShortID()
+	validating := ids.GenerateTestShortID()
+	primaryEnd := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	tt := []struct {
+		name   string
+		nodeID ids.ShortID
+		args   []string
+		txs    int
+		err    error
+	}{
+		// the fake reports the validator once its start passes, so the
+		// wait for it runs into --timeout
+		{name: "issue", nodeID: nodeID, args: []string{"--validate-weight=20", "--timeout=1s"}, txs: 1, err: context.DeadlineExceeded},
+		{name: "dry run", nodeID: nodeID, args: []string{"--dry-run"}},
+		{name: "already validating", nodeID: validating},
+		{name: "zero weight", nodeID: nodeID, args: []string{"--validate-weight=0"}, err: errZeroValidateWeight},
+		{name: "not a primary validator", nodeID: ids.GenerateTestShortID(), err: client.ErrValidatorNotFound},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New()
+		f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+		fake.AddSubnet(subnetID, f.k.Addresses()[0])
+		for _, id := range []ids.ShortID{nodeID, validating} {
+			fake.AddValidator(ids.Empty, client.Validator{NodeID: id, Start: time.Now().Add(-time.Hour), End: primaryEnd})
+		}
+		fake.AddValidator(subnetID, client.Validator{NodeID: validating, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+		args := append([]string{"add", "subnet-validator", "--subnet-id=" + subnetID.String(), "--node-ids=" + tv.nodeID.PrefixedString(constants.NodeIDPrefix)}, tv.args...)
+		_, err := run(t, f, args...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		txs := fake.Txs()
+		if len(txs) != tv.txs {
+			t.Fatalf("%s: expected %d txs, got %d", tv.name, tv.txs, len(txs))
+		}
+		if tv.txs == 0 {
+			continue
+		}
+		if txs[0].Type != client.TxTypeAddSubnetValidator {
+			t.Fatalf("%s: unexpected txs %+v", tv.name, txs)
+		}
+		// validates until the end of the primary network validation
+		if _, end, err := fake.P().GetPendingValidator(context.Background(), subnetID, tv.nodeID); err != nil || !end.Equal(primaryEnd) {
+			t.Fatalf("%s: unexpected end %v (%v)", tv.name, end, err)
+		}
+	}
+}
+
+func TestCreateBlockchain(t *testing.T) {
+	dir := t.TempDir()
+	genesis := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(genesis, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vmID := ids.GenerateTestID().String()
+	chain := func(name string) string {
+		return "--chain=name=" + name + ",vm-id=" + vmID + ",genesis=" + genesis
+	}
+	tt := []struct {
+		name string
+		args []string
+		txs  int
+		err  error
+	}{
+		{name: "issue", args: []string{"--chain-name=a", "--vm-id=" + vmID, "--vm-genesis-path=" + genesis}, txs: 1},
+		{name: "chains", args: []string{"--chain-name=a", "--vm-id=" + vmID, "--vm-genesis-path=" + genesis, chain("b")}, txs: 2},
+		{name: "dry run", args: []string{"--chain-name=a", "--vm-id=" + vmID, "--vm-genesis-path=" + genesis, "--dry-run"}},
+		{name: "no chains", err: ErrInvalidChainDef},
+		{name: "duplicate name", args: []string{chain("a"), chain("a")}, err: ErrInvalidChainDef},
+		{name: "alias of chains", args: []string{chain("a"), chain("b"), "--chain-alias=a"}, err: ErrInvalidChainDef},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New()
+		f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+		subnetID := ids.GenerateTestID()
+		fake.AddSubnet(subnetID, f.k.Addresses()[0])
+		args := append([]string{"create", "blockchain", "--subnet-id=" + subnetID.String(), "--names-path=" + filepath.Join(dir, "names.json")}, tv.args...)
+		out, err := run(t, f, args...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		txs := fake.Txs()
+		if len(txs) != tv.txs {
+			t.Fatalf("%s: expected %d txs, got %d", tv.name, tv.txs, len(txs))
+		}
+		for _, tx := range txs {
+			if tx.Type != client.TxTypeCreateBlockchain || !strings.Contains(out, tx.ID.String()) {
+				t.Fatalf("%s: unexpected tx %+v (output %q)", tv.name, tx, out)
+			}
+		}
+	}
+}
+
+func TestWizard(t *testing.T) {
+	dir := t.TempDir()
+	genesis := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(genesis, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	nodeID := ids.GenerateTestShortID()
+	// not a primary network validator
+	newNodeIDs := "--node-ids=" + ids.GenerateTestShortID().PrefixedString(constants.NodeIDPrefix)
+	etna := clienttest.WithNodeVersion("avalanche/1.12.0")
+	chainArgs := []string{"--chain-name=a", "--vm-id=" + ids.GenerateTestID().String(), "--names-path=" + filepath.Join(dir, "names.json")}
+	tt := []struct {
+		name string
+		opts []clienttest.Option
+		args []string
+		txs  []client.TxType
+		err  error
+	}{
+		// the fake reports the subnet validator once its start passes, so
+		// the wait for it runs into --timeout
+		{name: "issue", args: []string{"--node-ids=" + nodeID.PrefixedString(constants.NodeIDPrefix), "--vm-genesis-path=" + genesis, "--timeout=1s"}, txs: []client.TxType{client.TxTypeCreateSubnet, client.TxTypeAddSubnetValidator}, err: context.DeadlineExceeded},
+		{name: "dry run", args: []string{"--node-ids=" + nodeID.PrefixedString(constants.NodeIDPrefix), "--vm-genesis-path=" + genesis, "--dry-run"}},
+		{name: "no node IDs", args: []string{"--vm-genesis-path=" + genesis}, err: errNoNodeIDs},
+		// the new nodes validate the L1 once it is converted, so the subnet
+		// starts without validators
+		{name: "l1", opts: []clienttest.Option{etna}, args: []string{newNodeIDs, "--vm-genesis-path=" + genesis, "--l1"}, txs: []client.TxType{client.TxTypeCreateSubnet, client.TxTypeCreateBlockchain}},
+		// without --l1, the new nodes are staked, with txs this build can
+		// no longer issue
+		{name: "stake after etna", opts: []clienttest.Option{etna}, args: []string{newNodeIDs, "--vm-genesis-path=" + genesis}, err: ErrUnsupportedUpgrade},
+		{name: "l1 before etna", args: []string{newNodeIDs, "--vm-genesis-path=" + genesis, "--l1"}, err: ErrUpgradeInactive},
+		{name: "no genesis", args: []string{"--node-ids=" + nodeID.PrefixedString(constants.NodeIDPrefix), "--vm-genesis-path=" + filepath.Join(dir, "missing.json")}, err: os.ErrNotExist},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New(tv.opts...)
+		fake.AddValidator(ids.Empty, client.Validator{NodeID: nodeID, Start: time.Now().Add(-time.Hour), End: time.Now().Add(30 * 24 * time.Hour)})
+		args := append(append([]string{"wizard"}, chainArgs...), tv.args...)
+		_, err := run(t, newTestFactory(t, fake, 3000*units.Avax), args...)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		txs := fake.Txs()
+		if len(txs) != len(tv.txs) {
+			t.Fatalf("%s: unexpected txs %+v", tv.name, txs)
+		}
+		for i, tx := range txs {
+			if tx.Type != tv.txs[i] {
+				t.Fatalf("%s: unexpected txs %+v", tv.name, txs)
+			}
+		}
+	}
+}
+
+func TestDecommission(t *testing.T) {
+	tt := []struct {
+		nodeVersion string
+		txs         int
+		removed     bool
+	}{
+		// RemoveSubnetValidatorTx exists since Banff
+		{nodeVersion: "avalanche/1.9.0", txs: 2, removed: true},
+		{nodeVersion: "avalanche/1.7.6"},
+	}
+	for _, tv := range tt {
+		fake := clienttest.New(clienttest.WithNodeVersion(tv.nodeVersion))
+		f := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+		subnetID := ids.GenerateTestID()
+		fake.AddSubnet(subnetID, f.k.Addresses()[0])
+		for i := 0; i < 2; i++ {
+			fake.AddValidator(subnetID, client.Validator{NodeID: ids.GenerateTestShortID(), Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+		}
+
+		dir := t.TempDir()
+		args := []string{"decommission", "--subnet-id=" + subnetID.String(), "--archive-dir=" + dir, "--names-path=" + filepath.Join(dir, "names.json")}
+		if tv.removed {
+			// removing validators is subject to two-person approval
+			policyPath := filepath.Join(dir, "policy.yaml")
+			s := "proposers: [" + f.k.Addresses()[0].String() + "]\napprovers: [" + f.k.Addresses()[0].String() + "]\n"
+			if err := ioutil.WriteFile(policyPath, []byte(s), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := run(t, f, append(args, "--policy-path="+policyPath)...); !errors.Is(err, policy.ErrApprovalRequired) {
+				t.Fatalf("%s: expected %v, got %v", tv.nodeVersion, policy.ErrApprovalRequired, err)
+			}
+			if txs := fake.Txs(); len(txs) != 0 {
+				t.Fatalf("%s: unexpected txs %+v", tv.nodeVersion, txs)
+			}
+		}
+		out, err := run(t, f, args...)
+		if err != nil {
+			t.Fatalf("%s: %v", tv.nodeVersion, err)
+		}
+		if txs := fake.Txs(); len(txs) != tv.txs {
+			t.Fatalf("%s: unexpected txs %+v", tv.nodeVersion, txs)
+		}
+		b, err := ioutil.ReadFile(strings.TrimSpace(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r decommission.Report
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Validators) != 2 || r.Validators[0].Removed != tv.removed || r.LastEnd().IsZero() == !tv.removed {
+			t.Fatalf("%s: unexpected validators %+v", tv.nodeVersion, r.Validators)
+		}
+		if permanent := r.Permanent[len(r.Permanent)-1] == decommission.ValidatorsPermanent; permanent == tv.removed {
+			t.Fatalf("%s: unexpected permanent resources %q", tv.nodeVersion, r.Permanent)
+		}
+	}
+}
+
+func TestApproval(t *testing.T) {
+	fake := clienttest.New()
+	proposer := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	approver := newTestFactory(t, fake, 10*clienttest.DefaultFee)
+	dir := t.TempDir()
+	proposal := filepath.Join(dir, "proposal.json")
+	policyPath := filepath.Join(dir, "policy.yaml")
+	if _, err := run(t, proposer, "create", "subnet", "--propose="+proposal); !errors.Is(err, policy.ErrNoApprovalList) {
+		t.Fatalf("expected %v, got %v", policy.ErrNoApprovalList, err)
+	}
+	s := "proposers: [" + proposer.k.Addresses()[0].String() + "]\napprovers: [" + approver.k.Addresses()[0].String() + "]\n"
+	if err := ioutil.WriteFile(policyPath, []byte(s), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"create", "subnet", "--policy-path=" + policyPath}
+
+	if _, err := run(t, proposer, args...); !errors.Is(err, policy.ErrApprovalRequired) {
+		t.Fatalf("expected %v, got %v", policy.ErrApprovalRequired, err)
+	}
+	if txs := fake.Txs(); len(txs) != 0 {
+		t.Fatalf("expected no tx, got %d", len(txs))
+	}
+	if _, err := run(t, approver, append(args, "--propose="+proposal)...); !errors.Is(err, policy.ErrNotProposer) {
+		t.Fatalf("expected %v, got %v", policy.ErrNotProposer, err)
+	}
+	if _, err := run(t, proposer, append(args, "--propose="+proposal)...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, proposer, append(args, "--approve="+proposal)...); !errors.Is(err, policy.ErrNotApprover) {
+		t.Fatalf("expected %v, got %v", policy.ErrNotApprover, err)
+	}
+
+	// a failed tx leaves the proposal to approve again
+	errTest := errors.New("unavailable")
+	fake.Fail("CreateSubnet", errTest)
+	if _, err := run(t, approver, append(args, "--approve="+proposal)...); !errors.Is(err, errTest) {
+		t.Fatalf("expected %v, got %v", errTest, err)
+	}
+	p, err := approval.Load(proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Approval != nil {
+		t.Fatalf("unexpected approval %+v", p.Approval)
+	}
+
+	if _, err := run(t, approver, append(args, "--approve="+proposal)...); err != nil {
+		t.Fatal(err)
+	}
+	if txs := fake.Txs(); len(txs) != 1 {
+		t.Fatalf("expected 1 tx, got %d", len(txs))
+	}
+	p, err = approval.Load(proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Approval == nil || p.Approval.Approver != approver.k.Addresses()[0] {
+		t.Fatalf("unexpected approval %+v", p.Approval)
+	}
+	if _, err := run(t, approver, append(args, "--approve="+proposal)...); !errors.Is(err, approval.ErrAlreadyApproved) {
+		t.Fatalf("expected %v, got %v", approval.ErrAlreadyApproved, err)
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		profiles []string
+		exp      []statusProfile
+		err      error
+	}{
+		{
+			profiles: []string{"fuji", "mainnet"},
+			exp: []statusProfile{
+				{Name: "fuji", URI: "https://api.avax-test.network"},
+				{Name: "mainnet", URI: "https://api.avax.network"},
+			},
+		},
+		{
+			profiles: []string{"staging=http://10.0.0.1:9650", "http://localhost:9650/?a=b"},
+			exp: []statusProfile{
+				{Name: "staging", URI: "http://10.0.0.1:9650"},
+				{Name: "http://localhost:9650/?a=b", URI: "http://localhost:9650/?a=b"},
+			},
+		},
+		{profiles: []string{"devnet"}, err: ErrUnknownProfile},
+		{profiles: []string{"=http://localhost:9650"}, err: ErrUnknownProfile},
+		{profiles: []string{"fuji", "fuji"}, err: ErrUnknownProfile},
+	}
+	for i, tv := range tt {
+		ps, err := parseProfiles(tv.profiles)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		if len(ps) != len(tv.exp) {
+			t.Fatalf("#%d: expected %d profiles, got %+v", i, len(tv.exp), ps)
+		}
+		for j := range tv.exp {
+			if ps[j] != tv.exp[j] {
+				t.Fatalf("#%d: expected %+v, got %+v", i, tv.exp[j], ps[j])
+			}
+		}
+	}
+}
+
+func TestStatusProfiles(t *testing.T) {
+	fake := clienttest.New()
+	out, err := run(t, newTestFactory(t, fake, 0), "status", "network", "--profiles=staging=http://localhost:9650,production=http://localhost:9660", "--format=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sts []profileStatus
+	if err := json.Unmarshal([]byte(out), &sts); err != nil {
+		t.Fatal(err)
+	}
+	if len(sts) != 2 || sts[0].Profile != "staging" || sts[1].Profile != "production" {
+		t.Fatalf("unexpected profiles %+v", sts)
+	}
+	for _, st := range sts {
+		if st.Status == nil || st.Status.NetworkID != constants.LocalID {
+			t.Fatalf("unexpected status %+v", st)
+		}
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "network", "--profiles=fuji", "--private-uri=http://localhost:9650"); !errors.Is(err, ErrProfilesPrivateURI) {
+		t.Fatalf("expected %v, got %v", ErrProfilesPrivateURI, err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "network", "--profiles=staging=http://localhost:9650,production=http://localhost:9660"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusPeers(t *testing.T) {
+	fake := clienttest.New()
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, ids.GenerateTestShortID())
+	// the fake node is "NodeID-111111111111111111116DBWJs"
+	a, b := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	for _, nodeID := range []ids.ShortID{ids.ShortEmpty, a, b} {
+		fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	}
+	fake.SetPeers(a)
+
+	out, err := run(t, newTestFactory(t, fake, 0), "status", "peers", "--subnet-id="+subnetID.String(), "--format=json")
+	if !errors.Is(err, ErrValidatorsDisconnected) {
+		t.Fatalf("expected %v, got %v", ErrValidatorsDisconnected, err)
+	}
+	var st peersStatus
+	if err := json.Unmarshal([]byte(out), &st); err != nil {
+		t.Fatal(err)
+	}
+	self, bID := ids.ShortEmpty.PrefixedString(constants.NodeIDPrefix), b.PrefixedString(constants.NodeIDPrefix)
+	if len(st.Nodes) != 3 || len(st.Missing) != 1 || st.Missing[0] != (peersLink{From: self, To: bID}) {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	if len(st.Isolated) != 1 || st.Isolated[0] != bID {
+		t.Fatalf("expected %s to be isolated, got %v", bID, st.Isolated)
+	}
+
+	fake.SetPeers(a, b)
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "peers", "--subnet-id="+subnetID.String()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusLiveness(t *testing.T) {
+	fake := clienttest.New()
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, ids.GenerateTestShortID())
+	// the fake node is probed; the other validator is unknown to it
+	other := ids.GenerateTestShortID()
+	fake.AddValidator(subnetID, client.Validator{NodeID: ids.ShortEmpty, Weight: 90, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	fake.AddValidator(subnetID, client.Validator{NodeID: other, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+
+	// the fake serves neither EVM nor index APIs
+	fake.AddBlockchain(client.Blockchain{ID: ids.GenerateTestID(), Name: "mychain", SubnetID: subnetID})
+
+	out, err := run(t, newTestFactory(t, fake, 0), "status", "liveness", "--subnet-id="+subnetID.String(), "--sample-interval=0s", "--format=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st livenessStatus
+	if err := json.Unmarshal([]byte(out), &st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Live || st.ResponsiveWeight != 90 || st.TotalWeight != 100 || len(st.Validators) != 2 {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	if len(st.Chains) != 1 || st.Chains[0].State != string(liveness.ChainUnknown) {
+		t.Fatalf("expected an unknown chain, got %+v", st.Chains)
+	}
+	if v := st.Validators[1]; v.NodeID != other.PrefixedString(constants.NodeIDPrefix) || v.Responsive {
+		t.Fatalf("expected %s not to be responsive, got %+v", other, v)
+	}
+
+	fake.AddValidator(subnetID, client.Validator{NodeID: other, Weight: 30, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	if _, err := run(t, newTestFactory(t, fake, 0), "status", "liveness", "--subnet-id="+subnetID.String(), "--sample-interval=0s"); !errors.Is(err, ErrSubnetNotLive) {
+		t.Fatalf("expected %v, got %v", ErrSubnetNotLive, err)
+	}
+}
+
+func TestExportGrafana(t *testing.T) {
+	fake := clienttest.New()
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, ids.GenerateTestShortID())
+	nodeID := ids.GenerateTestShortID()
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(48 * time.Hour)})
+	chainID := ids.GenerateTestID()
+	fake.AddBlockchain(client.Blockchain{ID: chainID, Name: "mychain", SubnetID: subnetID})
+
+	dir := t.TempDir()
+	dashboard, rules := filepath.Join(dir, "dashboard.json"), filepath.Join(dir, "alerts.yaml")
+	if _, err := run(t, newTestFactory(t, fake, 0), "export", "grafana", "--subnet-id="+subnetID.String(), "--dashboard-output="+dashboard, "--rules-output="+rules); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{dashboard, rules} {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []string{chainID.String(), nodeID.PrefixedString(constants.NodeIDPrefix)} {
+			if !strings.Contains(string(b), s) {
+				t.Fatalf("expected %s to contain %s", p, s)
+			}
+		}
+	}
+}
+
+func TestGenesisFeeConfig(t *testing.T) {
+	fake := clienttest.New()
+
+	out, err := run(t, newTestFactory(t, fake, 0), "genesis", "fee-config", "--preset=gaming", "--gas-limit=10000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c feeconfig.Config
+	if err := json.Unmarshal([]byte(out), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.GasLimit != 10_000_000 || c.TargetBlockRate != 1 {
+		t.Fatalf("unexpected fee config %+v", c)
+	}
+
+	p := filepath.Join(t.TempDir(), "subnet-evm.genesis")
+	if err := ioutil.WriteFile(p, []byte(`{"config":{"chainId":99999},"alloc":{}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), "genesis", "fee-config", "--vm-genesis-path="+p); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"feeConfig"`) || !strings.Contains(string(b), `"chainId": 99999`) {
+		t.Fatalf("unexpected genesis %s", b)
+	}
+
+	if _, err := run(t, newTestFactory(t, fake, 0), "genesis", "fee-config", "--preset=fast"); !errors.Is(err, feeconfig.ErrUnknownPreset) {
+		t.Fatalf("expected %v, got %v", feeconfig.ErrUnknownPreset, err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), "genesis", "fee-config", "--target-block-rate=0"); !errors.Is(err, feeconfig.ErrInvalidFeeConfig) {
+		t.Fatalf("expected %v, got %v", feeconfig.ErrInvalidFeeConfig, err)
+	}
+}
+
+// fakeEVM serves the JSON-RPC of a subnet-evm chain with the precompiles
+// of [active] activated and the allow list [roles] (by precompile and
+// address). Other calls return [calls] by selector; txs always succeed.
+type fakeEVM struct {
+	mu     sync.Mutex
+	active map[evm.Address]bool
+	roles  map[[2]evm.Address]precompile.Role
+	calls  map[string][]byte
+	txs    [][]byte
+	// contract, if set, is the address receipts report as created
+	contract *evm.Address
+}
+
+func newFakeEVM(t *testing.T) (*fakeEVM, string) {
+	f := &fakeEVM{
+		active: map[evm.Address]bool{},
+		roles:  map[[2]evm.Address]precompile.Role{},
+		calls:  map[string][]byte{},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var result interface{}
+		switch req.Method {
+		case "eth_chainId":
+			result = "0xa868"
+		case "eth_getTransactionCount", "eth_getBalance":
+			result = "0x0"
+		case "eth_gasPrice":
+			result = "0x1"
+		case "eth_estimateGas":
+			result = "0x5208"
+		case "eth_getCode":
+			var s string
+			_ = json.Unmarshal(req.Params[0], &s)
+			a, _ := evm.ParseAddress(s)
+			result = "0x"
+			if f.active[a] {
+				result = "0x01"
+			}
+		case "eth_call":
+			var msg struct {
+				To   string `json:"to"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(req.Params[0], &msg)
+			to, _ := evm.ParseAddress(msg.To)
+			data, _ := hex.DecodeString(strings.TrimPrefix(msg.Data, "0x"))
+			ret := f.calls[hex.EncodeToString(data[:4])]
+			if bytes.Equal(data[:4], evm.Selector("readAllowList(address)")) {
+				role := f.roles[[2]evm.Address{to, evm.DecodeAddress(data[4:], 0)}]
+				ret = evm.WordUint(new(big.Int).SetUint64(uint64(role)))
+			}
+			result = "0x" + hex.EncodeToString(ret)
+		case "eth_sendRawTransaction":
+			var s string
+			_ = json.Unmarshal(req.Params[0], &s)
+			raw, _ := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+			f.txs = append(f.txs, raw)
+			result = "0x" + hex.EncodeToString(evm.Keccak256(raw))
+		case "eth_getTransactionReceipt":
+			r := map[string]string{"status": "0x1", "blockNumber": "0x1", "gasUsed": "0x5208"}
+			if f.contract != nil {
+				r["contractAddress"] = f.contract.Hex()
+			}
+			result = r
+		default:
+			http.Error(w, "unsupported method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+// sent returns the number of txs issued to [f].
+func (f *fakeEVM) sent() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.txs)
+}
+
+// writeEVMKey writes the ewoq key and returns its path and address.
+func writeEVMKey(t *testing.T) (string, evm.Address) {
+	p := filepath.Join(t.TempDir(), "evm.pk")
+	if err := ioutil.WriteFile(p, []byte("56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := evm.ParseAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, a
+}
+
+func TestEVMAllowList(t *testing.T) {
+	fake := clienttest.New()
+	f, uri := newFakeEVM(t)
+	keyPath, admin := writeEVMKey(t)
+	user := evm.Address{1}
+	f.active[precompile.TxAllowList.Address] = true
+	f.roles[[2]evm.Address{precompile.TxAllowList.Address, admin}] = precompile.Admin
+
+	args := []string{"evm", "--evm-rpc=" + uri, "--evm-private-key-path=" + keyPath, "allowlist"}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "add", "--address="+user.Hex(), "--expire-at=2022-01-01T00:00:00Z")...); !errors.Is(err, client.ErrExpired) {
+		t.Fatalf("expected %v, got %v", client.ErrExpired, err)
+	}
+	if f.sent() != 0 {
+		t.Fatalf("expected no tx after --expire-at, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "add", "--address="+user.Hex())...); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent() != 1 {
+		t.Fatalf("expected 1 tx, got %d", f.sent())
+	}
+	// already removed
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "remove", "--address="+user.Hex())...); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent() != 1 {
+		t.Fatalf("expected 1 tx, got %d", f.sent())
+	}
+
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "add", "--precompile=deployer", "--address="+user.Hex())...); !errors.Is(err, ErrPrecompileInactive) {
+		t.Fatalf("expected %v, got %v", ErrPrecompileInactive, err)
+	}
+	f.roles[[2]evm.Address{precompile.TxAllowList.Address, admin}] = precompile.Manager
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "add", "--role=admin", "--address="+user.Hex())...); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected %v, got %v", ErrNotAllowed, err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "add", "--role=none", "--address="+user.Hex())...); !errors.Is(err, precompile.ErrUnknownRole) {
+		t.Fatalf("expected %v, got %v", precompile.ErrUnknownRole, err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "status", "--address="+user.Hex())...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEVMMint(t *testing.T) {
+	fake := clienttest.New()
+	f, uri := newFakeEVM(t)
+	keyPath, minter := writeEVMKey(t)
+	args := []string{"evm", "--evm-rpc=" + uri, "--evm-private-key-path=" + keyPath, "mint", "--to=" + evm.Address{1}.Hex(), "--to=" + evm.Address{2}.Hex()}
+
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=10.5")...); !errors.Is(err, ErrPrecompileInactive) {
+		t.Fatalf("expected %v, got %v", ErrPrecompileInactive, err)
+	}
+	f.active[precompile.NativeMinter.Address] = true
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=10.5")...); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected %v, got %v", ErrNotAllowed, err)
+	}
+	f.roles[[2]evm.Address{precompile.NativeMinter.Address, minter}] = precompile.Enabled
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=10.5", "--dry-run")...); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent() != 0 {
+		t.Fatalf("expected no tx in a dry run, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=10.5", "--expire-at=2022-01-01T00:00:00Z")...); !errors.Is(err, client.ErrExpired) {
+		t.Fatalf("expected %v, got %v", client.ErrExpired, err)
+	}
+	if f.sent() != 0 {
+		t.Fatalf("expected no tx after --expire-at, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=10.5")...); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent() != 2 {
+		t.Fatalf("expected 2 txs, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--amount=-1")...); !errors.Is(err, evm.ErrInvalidAmount) {
+		t.Fatalf("expected %v, got %v", evm.ErrInvalidAmount, err)
+	}
+}
+
+func TestL1DeployManager(t *testing.T) {
+	fake := clienttest.New(clienttest.WithNodeVersion("avalanche/1.12.0"))
+	f, uri := newFakeEVM(t)
+	keyPath, owner := writeEVMKey(t)
+	manager := evm.Address{7}
+	f.contract = &manager
+	bytecode := filepath.Join(t.TempDir(), "manager.bin")
+	if err := ioutil.WriteFile(bytecode, []byte("6080"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{
+		"l1", "deploy-manager",
+		"--subnet-id=" + ids.GenerateTestID().String(),
+		"--chain-id=" + ids.GenerateTestID().String(),
+		"--evm-rpc=" + uri,
+		"--evm-private-key-path=" + keyPath,
+		"--manager-bytecode=" + bytecode,
+	}
+
+	// another account initialized the contract before the initialize tx
+	f.calls[hex.EncodeToString(evm.Selector("owner()"))] = evm.WordAddress(evm.Address{8})
+	if _, err := run(t, newTestFactory(t, fake, 0), args...); !errors.Is(err, ErrManagerOwner) {
+		t.Fatalf("expected %v, got %v", ErrManagerOwner, err)
+	}
+
+	f.calls[hex.EncodeToString(evm.Selector("owner()"))] = evm.WordAddress(owner)
+	out, err := run(t, newTestFactory(t, fake, 0), args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != manager.Hex() {
+		t.Fatalf("unexpected output %q, expected %q", out, manager.Hex())
+	}
+	// deploys and initializes, in both runs
+	if f.sent() != 4 {
+		t.Fatalf("expected 4 txs, got %d", f.sent())
+	}
+}
+
+func TestEVMRewards(t *testing.T) {
+	fake := clienttest.New()
+	f, uri := newFakeEVM(t)
+	keyPath, admin := writeEVMKey(t)
+	f.calls[hex.EncodeToString(precompile.CurrentRewardAddress()[:4])] = evm.WordAddress(precompile.BlackholeAddress)
+	args := []string{"evm", "--evm-rpc=" + uri, "--evm-private-key-path=" + keyPath, "rewards"}
+
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "status")...); !errors.Is(err, ErrPrecompileInactive) {
+		t.Fatalf("expected %v, got %v", ErrPrecompileInactive, err)
+	}
+	f.active[precompile.RewardManager.Address] = true
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "status")...); err != nil {
+		t.Fatal(err)
+	}
+	// already burned
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "burn")...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "allow-fee-recipients")...); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected %v, got %v", ErrNotAllowed, err)
+	}
+	f.roles[[2]evm.Address{precompile.RewardManager.Address, admin}] = precompile.Admin
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "allow-fee-recipients", "--expire-at=2022-01-01T00:00:00Z")...); !errors.Is(err, client.ErrExpired) {
+		t.Fatalf("expected %v, got %v", client.ErrExpired, err)
+	}
+	if f.sent() != 0 {
+		t.Fatalf("expected no tx after --expire-at, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "allow-fee-recipients")...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "set-address", "--address="+evm.Address{19: 1}.Hex())...); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent() != 2 {
+		t.Fatalf("expected 2 txs, got %d", f.sent())
+	}
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "set-address", "--address="+precompile.BlackholeAddress.Hex())...); !errors.Is(err, ErrBlackholeRewardAddress) {
+		t.Fatalf("expected %v, got %v", ErrBlackholeRewardAddress, err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	fake := clienttest.New()
+	subnetID := ids.GenerateTestID()
+	fake.AddSubnet(subnetID, ids.GenerateTestShortID())
+	nodeID := ids.GenerateTestShortID()
+	fake.AddValidator(subnetID, client.Validator{NodeID: nodeID, Weight: 10, Start: time.Now().Add(-time.Hour), End: time.Now().Add(48 * time.Hour)})
+
+	dir := t.TempDir()
+	rules := func(s string) string {
+		p := filepath.Join(dir, "alerts.yaml")
+		if err := ioutil.WriteFile(p, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	args := []string{"watch", "--subnet-id=" + subnetID.String(), "--once"}
+
+	p := rules("rules:\n  - when: validator expires < 7d\n    actions: [log, exit]\n")
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--rules="+p)...); !errors.Is(err, ErrAlertFiring) {
+		t.Fatalf("expected %v, got %v", ErrAlertFiring, err)
+	}
+	// logged only
+	p = rules("rules:\n  - when: validator expires < 7d\n")
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--rules="+p)...); err != nil {
+		t.Fatal(err)
+	}
+	p = rules("rules:\n  - when: validator expires < 1d\n    actions: [exit]\n")
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--rules="+p)...); err != nil {
+		t.Fatal(err)
+	}
+	p = rules("rules:\n  - when: validator is late\n")
+	if _, err := run(t, newTestFactory(t, fake, 0), append(args, "--rules="+p)...); !errors.Is(err, alert.ErrInvalidRules) {
+		t.Fatalf("expected %v, got %v", alert.ErrInvalidRules, err)
+	}
+}
+
+func TestRegisterSecrets(t *testing.T) {
+	cmd := newFaucetRequestCommand()
+	secrets := []string{"faucet-key-3b1e9f", "captcha-token-8c2d4a"}
+	if err := cmd.ParseFlags([]string{"--faucet-api-key=" + secrets[0], "--captcha-token=" + secrets[1]}); err != nil {
+		t.Fatal(err)
+	}
+	registerSecrets(cmd)
+	for _, secret := range secrets {
+		if s := redact.String("sent " + secret); strings.Contains(s, secret) {
+			t.Fatalf("%q not redacted: %q", secret, s)
+		}
+	}
+}
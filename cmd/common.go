@@ -8,19 +8,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
-	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/dustin/go-humanize"
 	"github.com/olekukonko/tablewriter"
-	"github.com/onsi/ginkgo/v2/formatter"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/approval"
+	"github.com/ava-labs/subnet-cli/internal/cache"
 	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/price"
+	"github.com/ava-labs/subnet-cli/internal/qr"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+	"github.com/ava-labs/subnet-cli/internal/version"
 	"github.com/ava-labs/subnet-cli/pkg/color"
 	"github.com/ava-labs/subnet-cli/pkg/logutil"
 )
@@ -33,88 +42,350 @@ type ValInfo struct {
 type Info struct {
 	uri string
 
-	feeData *info.GetTxFeeResponse
+	fees    client.FeeCalculator
 	balance uint64
 
 	txFee            uint64
 	stakeAmount      uint64
 	totalStakeAmount uint64
 	requiredBalance  uint64
+	// stakeAsset is the staking asset of [subnetID] stake amounts are shown
+	// in (see [Info.loadStakeAsset]); nil for AVAX
+	stakeAsset *client.Asset
 
 	key key.Key
 
 	networkName string
+	networkID   uint32
+	// params are the staking constants of the network
+	params networkParams
 
 	subnetIDType string
 	subnetID     ids.ID
 
 	nodeIDs    []ids.ShortID
 	allNodeIDs []ids.ShortID
+	// sovNodeIDs are the nodes left to validate the subnet once it is
+	// converted to an L1, instead of staking on the primary network
+	sovNodeIDs []ids.ShortID
 	valInfos   map[ids.ShortID]*ValInfo
 
 	blockchainID  ids.ID
 	chainName     string
 	vmID          ids.ID
 	vmGenesisPath string
+	// chains are the blockchains of a multi-chain "create blockchain"
+	chains []*chainDef
 
-	validateStart            time.Time
+	validateStart time.Time
+	// fixedValidateStart is true if "--validate-start" is set; otherwise
+	// [validateStart] moves with the time each tx is issued
+	fixedValidateStart       bool
 	validateEnd              time.Time
 	validateWeight           uint64
 	validateRewardFeePercent uint32
+	// newWeights, if set, is the weight of every validator the operation
+	// adds or changes (0 removes it), for the confirmed state changes (see
+	// [Info.weightChanges])
+	newWeights map[ids.ShortID]uint64
+	// weightChange is the total subnet validator weight the operation adds
+	// or changes (see "--policy-path")
+	weightChange uint64
+	// approved is the proposal approved by [Info.CheckApproval], recorded
+	// once a tx is accepted (see [Info.RecordApproval])
+	approved *approval.Proposal
 
 	rewardAddr ids.ShortID
 	changeAddr ids.ShortID
+
+	// quote converts amounts to fiat if "--price-source" is set
+	quote *price.Quote
+
+	// upgrades are the network upgrades active on the connected network
+	upgrades []version.Upgrade
+
+	// issueAt and issueAtHeight delay issuing once the operation is
+	// confirmed (see [Info.waitIssueAt])
+	issueAt       time.Time
+	issueAtHeight uint64
+}
+
+// commandContext returns the context every request and poll of [cmd]
+// derives from. Commands call it once, so "--timeout" bounds the whole
+// command rather than each request; the deadline moves back by the waits
+// the command is asked for ("--issue-at", the smoke test bootstrap and the
+// approval of a custody signing request).
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if commandTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	deadline := time.Now()
+	if issueAt, err := parseIssueAt(time.Time{}); err == nil && issueAt.After(deadline) {
+		// invalid "--issue-at" is reported once the client is initialized
+		deadline = issueAt
+	}
+	if smokeTest {
+		deadline = deadline.Add(smokeBootstrapTimeout)
+	}
+	switch signerType {
+	case signerFireblocks, signerCustody:
+		deadline = deadline.Add(approvalTimeout)
+	}
+	return context.WithDeadline(ctx, deadline.Add(commandTimeout))
+}
+
+// stepContext bounds one step of a long-running command (e.g., an
+// iteration of "watch") by "--timeout".
+func stepContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if commandTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, commandTimeout)
 }
 
-func InitClient(uri string, loadKey bool) (client.Client, *Info, error) {
-	cli, err := client.New(client.Config{
+func InitClient(ctx context.Context, uri string, loadKey bool) (client.Client, *Info, error) {
+	return initClient(ctx, uri, loadKey, nil)
+}
+
+// InitReadClient initializes a client for read-only commands, which serves
+// repeated lookups from the local cache unless "--no-cache" is set.
+func InitReadClient(ctx context.Context, uri string) (client.Client, *Info, error) {
+	if noCache {
+		return initClient(ctx, uri, false, nil)
+	}
+	c, err := cache.Default()
+	if err != nil {
+		// caching is best-effort (e.g., no writable home directory)
+		zap.L().Warn("failed to open cache", zap.Error(err))
+		c = nil
+	}
+	return initClient(ctx, uri, false, c)
+}
+
+func initClient(ctx context.Context, uri string, loadKey bool, c *cache.Cache) (client.Client, *Info, error) {
+	expireAt, err := parseExpireAt(loadKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	issueAt, err := parseIssueAt(expireAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	f := factoryFrom(ctx)
+	cli, err := f.NewClient(ctx, client.Config{
 		URI:          uri,
 		PollInterval: pollInterval,
+		Cache:        c,
+		Proxy:        proxyURI,
+		TLS:          clientTLSConfig(),
+		AuthToken:    authToken,
+		AuthPassword: authPassword,
+		Progress:     progressOutput(),
+		ExpireAt:     expireAt,
+		RateLimit:    rateLimit,
 	})
 	if err != nil {
 		return nil, nil, err
 	}
-	txFee, err := cli.Info().Client().GetTxFee(context.TODO())
+	nodeVersion, err := checkNodeVersion(ctx, cli)
 	if err != nil {
 		return nil, nil, err
 	}
-	networkName, err := cli.Info().Client().GetNetworkName(context.TODO())
+	networkName, err := cli.Info().Client().GetNetworkName(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 	info := &Info{
-		uri:         uri,
-		feeData:     txFee,
+		// unix sockets are mapped to HTTP URLs, see [client.ParseURI]
+		uri:         cli.Config().URI,
+		fees:        newCachedFees(cli.Fees()),
 		networkName: networkName,
+		networkID:   cli.NetworkID(),
+		params:      loadNetworkParams(ctx, cli, cli.NetworkID()),
 		valInfos:    map[ids.ShortID]*ValInfo{},
+		upgrades:    version.Active(cli.NetworkID(), nodeVersion, time.Now()),
+
+		issueAt:       issueAt,
+		issueAtHeight: issueAtHeight,
 	}
+	info.quote = fetchQuote(ctx)
 	if !loadKey {
 		return cli, info, nil
 	}
+	if err := info.loadKey(ctx, cli); err != nil {
+		return nil, nil, err
+	}
+	return cli, info, nil
+}
 
-	if !useLedger {
-		info.key, err = key.LoadSoft(cli.NetworkID(), privKeyPath)
-		if err != nil {
-			return nil, nil, err
-		}
-	} else {
-		info.key, err = key.NewHard(cli.NetworkID())
-		if err != nil {
-			return nil, nil, err
+// loadKey loads the key and its P-Chain balance, for commands that only
+// issue txs on some networks (see [InitClient]).
+func (i *Info) loadKey(ctx context.Context, cli client.Client) (err error) {
+	i.warnUpgrades()
+
+	i.key, err = factoryFrom(ctx).LoadKey(ctx, cli.NetworkID())
+	if err != nil {
+		return err
+	}
+
+	i.balance, err = cli.P().Balance(ctx, i.key)
+	return err
+}
+
+// checkNodeVersion warns if the connected node runs an avalanchego version
+// other than the one subnet-cli was built against, whose APIs or tx format
+// may differ, and fails instead with "--strict-version".
+func checkNodeVersion(ctx context.Context, cli client.Client) (string, error) {
+	reply, err := cli.Info().Client().GetNodeVersion(ctx)
+	nodeVersion := ""
+	if err == nil {
+		nodeVersion = reply.Version
+		var c version.Compat
+		c, err = version.CheckNode(reply.Version)
+		if err == nil {
+			if c == version.Compatible {
+				return nodeVersion, nil
+			}
+			err = fmt.Errorf("%w: node runs %s (%s) but subnet-cli was built against %s", ErrIncompatibleNode, reply.Version, c, version.Avalanchego)
 		}
 	}
+	if strictVersion {
+		return nodeVersion, err
+	}
+	if errors.Is(err, ErrIncompatibleNode) {
+		color.Errf("{{red}}{{bold}}WARNING: %v; txs may be rejected (use --strict-version to refuse, or \"subnet-cli update\"){{/}}\n", err)
+		return nodeVersion, nil
+	}
+	zap.L().Warn("failed to check node version", zap.Error(err))
+	return nodeVersion, nil
+}
 
-	info.balance, err = cli.P().Balance(context.TODO(), info.key)
+// fetchQuote returns the AVAX price of "--price-source", if set. Fiat
+// amounts are only informational, so a failing feed is only reported.
+func fetchQuote(ctx context.Context) *price.Quote {
+	if priceSource == "" {
+		return nil
+	}
+	q, err := price.Fetch(ctx, priceSource, fiatCurrency)
 	if err != nil {
-		return nil, nil, err
+		color.Errf("{{yellow}}failed to fetch AVAX price, showing AVAX amounts only: %v{{/}}\n", err)
+		return nil
 	}
-	return cli, info, nil
+	return q
+}
+
+// fiat formats the fiat value of [nAVAX] for tables (e.g., " (≈ $12.50)"),
+// or returns "" without a price.
+func (i *Info) fiat(nAVAX uint64) string {
+	if i.quote == nil {
+		return ""
+	}
+	return color.F(" {{light-gray}}(≈ %s){{/}}", i.quote.Format(nAVAX))
+}
+
+// clientTLSConfig returns the TLS settings of the "--tls-*" flags.
+func clientTLSConfig() client.TLSConfig {
+	return client.TLSConfig{
+		CAFile:   tlsCAFile,
+		CertFile: tlsCertFile,
+		KeyFile:  tlsKeyFile,
+	}
+}
+
+// progressOutput returns where polling progress is rendered, if anywhere;
+// quiet mode and redirected stderr only get the final results.
+func progressOutput() io.Writer {
+	if !color.Interactive() {
+		return nil
+	}
+	return color.Stderr
+}
+
+// Fee returns the total fee for [n] transactions of [txType].
+func (i *Info) Fee(ctx context.Context, txType client.TxType, n int) (uint64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	if err := i.CheckTxFormat(txType); err != nil {
+		return 0, err
+	}
+	fee, err := i.fees.Fee(ctx, txType, 0)
+	if err != nil {
+		return 0, err
+	}
+	return fee * uint64(n), nil
+}
+
+// CheckKeyNetwork refuses to load a key that was labeled for a different
+// network than the connected node, unless "--force" is set.
+func CheckKeyNetwork(networkID uint32) error {
+	labeled, ok, err := key.LoadNetworkLabel(privKeyPath)
+	if err != nil {
+		return err
+	}
+	if !ok || labeled == networkID {
+		return nil
+	}
+	if force {
+		color.Outf("{{yellow}}key %q was created for network %q but connected to %q (--force set){{/}}\n", privKeyPath, constants.NetworkName(labeled), constants.NetworkName(networkID))
+		return nil
+	}
+	color.Outf("{{red}}key %q was created for network %q but connected to %q (use --force to override){{/}}\n", privKeyPath, constants.NetworkName(labeled), constants.NetworkName(networkID))
+	return fmt.Errorf("%w: key labeled %d, node reports %d", ErrNetworkMismatch, labeled, networkID)
+}
+
+// ParseAddress parses either a P-Chain bech32 address (e.g., "P-fuji1...")
+// or a raw short ID, rejecting bech32 addresses of a different network.
+func ParseAddress(networkID uint32, addr string) (ids.ShortID, error) {
+	if strings.HasPrefix(addr, "P-") {
+		return key.ParsePAddress(networkID, addr)
+	}
+	if strings.Contains(addr, "-") {
+		// e.g., "NodeID-..." or an X/C-Chain address
+		return ids.ShortEmpty, fmt.Errorf("%w: %q (expected a P-Chain address or a short ID)", ErrUnknownAddress, addr)
+	}
+	id, err := ids.ShortFromString(addr)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("%w: %q (expected a P-Chain address or a short ID): %v", ErrUnknownAddress, addr, err)
+	}
+	return id, nil
+}
+
+// FormatAddress formats [addr] as a P-Chain address for the connected network.
+func (i *Info) FormatAddress(addr ids.ShortID) string {
+	s, err := key.FormatPAddress(i.networkID, addr)
+	if err != nil {
+		return addr.String()
+	}
+	return s
+}
+
+// parseExpireAt returns the "--expire-at" time, and refuses to start an
+// operation issuing txs (signed with a key) that already expired.
+func parseExpireAt(issuing bool) (time.Time, error) {
+	if expireAts == "" {
+		return time.Time{}, nil
+	}
+	now := time.Now()
+	t, err := timeexpr.Parse(expireAts, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--expire-at: %w", err)
+	}
+	if issuing && now.After(t) {
+		color.Outf("{{red}}{{bold}}operation expired at %s (--expire-at), not issuing{{/}}\n", t.Format(time.RFC3339))
+		return time.Time{}, fmt.Errorf("%w at %s", client.ErrExpired, t.Format(time.RFC3339))
+	}
+	return t, nil
 }
 
 func CreateLogger() error {
 	lcfg := logutil.GetDefaultZapLoggerConfig()
 	lcfg.Level = zap.NewAtomicLevelAt(logutil.ConvertToZapLevel(logLevel))
-	logger, err := lcfg.Build()
+	logger, err := lcfg.Build(zap.WrapCore(redact.Core))
 	if err != nil {
 		return err
 	}
@@ -125,11 +396,38 @@ func CreateLogger() error {
 func (i *Info) CheckBalance() error {
 	if i.balance < i.requiredBalance {
 		color.Outf("{{red}}insufficient funds to perform operation. get more at https://faucet.avax-test.network{{/}}\n")
+		printQR("P-Chain address to fund", i.key.P()[0], qr.Medium)
 		return fmt.Errorf("%w: on %s (expected=%d, have=%d)", ErrInsufficientFunds, i.key.P(), i.requiredBalance, i.balance)
 	}
 	return nil
 }
 
+// formatStake formats the stake amount [v] in the staking asset of the
+// subnet: AVAX (with its fiat value) unless [i.stakeAsset] is custom.
+func (i *Info) formatStake(v uint64) string {
+	if i.stakeAsset.IsAVAX() {
+		avax := humanize.FormatFloat("#,###.###", float64(v)/float64(units.Avax))
+		return color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX {{light-gray}}(%s nAVAX){{/}}", avax, humanize.Comma(int64(v))) + i.fiat(v)
+	}
+	a := i.stakeAsset
+	return color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} {{light-gray}}(%s, %s base units){{/}}", amount.FormatAsset(v, a.Denomination, a.Symbol), a.Name, humanize.Comma(int64(v)))
+}
+
+// loadStakeAsset fetches the staking asset of [i.subnetID] from the
+// X-Chain, for elastic subnets that stake a custom asset. Stake amounts
+// are only displayed in it, so failures (e.g., permissioned subnets, which
+// have no staking asset) keep AVAX.
+func (i *Info) loadStakeAsset(ctx context.Context, cli client.Client) {
+	a, err := cli.StakingAsset(ctx, i.subnetID)
+	if err != nil {
+		zap.L().Debug("no staking asset, showing AVAX", zap.String("subnetId", i.subnetID.String()), zap.Error(err))
+		return
+	}
+	if !a.IsAVAX() {
+		i.stakeAsset = a
+	}
+}
+
 func BaseTableSetup(i *Info) (*bytes.Buffer, *tablewriter.Table) {
 	// P-Chain balance is denominated by units.Avax or 10^9 nano-Avax
 	curPChainDenominatedP := float64(i.balance) / float64(units.Avax)
@@ -145,35 +443,34 @@ func BaseTableSetup(i *Info) (*bytes.Buffer, *tablewriter.Table) {
 	tb.SetRowLine(true)
 	tb.SetAlignment(tablewriter.ALIGN_LEFT)
 
-	tb.Append([]string{formatter.F("{{cyan}}{{bold}}PRIMARY P-CHAIN ADDRESS{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.key.P()[0])})
-	tb.Append([]string{formatter.F("{{coral}}{{bold}}TOTAL P-CHAIN BALANCE{{/}} "), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", curPChainDenominatedBalanceP)})
+	// read-only commands load no key
+	if i.key != nil {
+		tb.Append([]string{color.F("{{cyan}}{{bold}}PRIMARY P-CHAIN ADDRESS{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.key.P()[0])})
+		tb.Append([]string{color.F("{{coral}}{{bold}}TOTAL P-CHAIN BALANCE{{/}} "), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", curPChainDenominatedBalanceP) + i.fiat(i.balance)})
+	}
 	if i.txFee > 0 {
 		txFee := float64(i.txFee) / float64(units.Avax)
 		txFees := humanize.FormatFloat("#,###.###", txFee)
-		tb.Append([]string{formatter.F("{{red}}{{bold}}TX FEE{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", txFees)})
+		tb.Append([]string{color.F("{{red}}{{bold}}TX FEE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", txFees) + i.fiat(i.txFee)})
 	}
 	if i.stakeAmount > 0 {
-		stakeAmount := float64(i.stakeAmount) / float64(units.Avax)
-		stakeAmounts := humanize.FormatFloat("#,###.###", stakeAmount)
-		tb.Append([]string{formatter.F("{{red}}{{bold}}EACH STAKE AMOUNT{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", stakeAmounts)})
+		tb.Append([]string{color.F("{{red}}{{bold}}EACH STAKE AMOUNT{{/}}"), i.formatStake(i.stakeAmount)})
 	}
 	if i.totalStakeAmount > 0 {
-		totalStakeAmount := float64(i.totalStakeAmount) / float64(units.Avax)
-		totalStakeAmounts := humanize.FormatFloat("#,###.###", totalStakeAmount)
-		tb.Append([]string{formatter.F("{{red}}{{bold}}TOTAL STAKE AMOUNT{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", totalStakeAmounts)})
+		tb.Append([]string{color.F("{{red}}{{bold}}TOTAL STAKE AMOUNT{{/}}"), i.formatStake(i.totalStakeAmount)})
 	}
 	if i.requiredBalance > 0 {
 		requiredBalance := float64(i.requiredBalance) / float64(units.Avax)
 		requiredBalances := humanize.FormatFloat("#,###.###", requiredBalance)
-		tb.Append([]string{formatter.F("{{red}}{{bold}}REQUIRED BALANCE{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", requiredBalances)})
+		tb.Append([]string{color.F("{{red}}{{bold}}REQUIRED BALANCE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} $AVAX", requiredBalances) + i.fiat(i.requiredBalance)})
 	}
 
-	tb.Append([]string{formatter.F("{{orange}}URI{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.uri)})
-	tb.Append([]string{formatter.F("{{orange}}NETWORK NAME{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.networkName)})
+	tb.Append([]string{color.F("{{orange}}URI{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.uri)})
+	tb.Append([]string{color.F("{{orange}}NETWORK NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.networkName)})
 	return buf, tb
 }
 
-func ParseNodeIDs(cli client.Client, i *Info) error {
+func ParseNodeIDs(ctx context.Context, cli client.Client, i *Info, nodeIDs []string) error {
 	// TODO: make this parsing logic more explicit (+ store per subnetID, not
 	// just whatever was called last)
 	i.nodeIDs = []ids.ShortID{}
@@ -185,7 +482,7 @@ func ParseNodeIDs(cli client.Client, i *Info) error {
 		}
 		i.allNodeIDs[idx] = nodeID
 
-		start, end, err := cli.P().GetValidator(context.Background(), i.subnetID, nodeID)
+		start, end, err := cli.P().GetValidator(ctx, i.subnetID, nodeID)
 		i.valInfos[nodeID] = &ValInfo{start, end}
 		switch {
 		case errors.Is(err, client.ErrValidatorNotFound):
@@ -199,18 +496,25 @@ func ParseNodeIDs(cli client.Client, i *Info) error {
 	return nil
 }
 
-func WaitValidator(cli client.Client, nodeIDs []ids.ShortID, i *Info) {
+// WaitValidator blocks until every node in [nodeIDs] validates
+// [i.subnetID], or [ctx] is done.
+func WaitValidator(ctx context.Context, cli client.Client, nodeIDs []ids.ShortID, i *Info) error {
 	for _, nodeID := range nodeIDs {
 		color.Outf("{{yellow}}waiting for validator %s to start validating %s...(could take a few minutes){{/}}\n", nodeID, i.subnetID)
 		for {
-			start, end, err := cli.P().GetValidator(context.Background(), i.subnetID, nodeID)
+			start, end, err := cli.P().GetValidator(ctx, i.subnetID, nodeID)
 			if err == nil {
 				if i.subnetID == ids.Empty {
 					i.valInfos[nodeID] = &ValInfo{start, end}
 				}
 				break
 			}
-			time.Sleep(10 * time.Second)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(10 * time.Second):
+			}
 		}
 	}
+	return nil
 }
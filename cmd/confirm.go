@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/dustin/go-humanize"
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/weights"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var dryRun bool
+
+// stateChange is a "before → after" change of a chain state value that the
+// operator confirms.
+type stateChange struct {
+	name   string
+	before uint64
+	after  uint64
+	format func(uint64) string
+}
+
+func formatCount(v uint64) string { return humanize.Comma(int64(v)) }
+
+// weightChanges returns the weight of every validator the operation adds
+// or changes (a zero weight removes it): [i.newWeights] if set, otherwise
+// [i.nodeIDs] at the stake amount (primary network) or validate weight.
+func (i *Info) weightChanges() map[ids.ShortID]uint64 {
+	if i.newWeights != nil {
+		return i.newWeights
+	}
+	w := i.validateWeight
+	if i.subnetID == ids.Empty {
+		w = i.stakeAmount
+	}
+	if w == 0 {
+		return nil
+	}
+	changes := make(map[ids.ShortID]uint64, len(i.nodeIDs))
+	for _, nodeID := range i.nodeIDs {
+		changes[nodeID] = w
+	}
+	return changes
+}
+
+// stateChanges returns the changes of the operation: the validator set
+// size and total weight of [i.subnetID] if validators change, and the
+// P-Chain balance after fees (and stake).
+func (i *Info) stateChanges(ctx context.Context, cli client.Client) ([]stateChange, error) {
+	changes := []stateChange{}
+	if wc := i.weightChanges(); len(wc) > 0 {
+		ws, err := cli.P().GetValidatorWeights(ctx, i.subnetID)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, validatorChanges(i.subnetID == ids.Empty, ws, weights.Apply(ws, wc))...)
+	}
+	after := uint64(0)
+	if spend := i.spend(); i.balance > spend {
+		after = i.balance - spend
+	}
+	changes = append(changes, stateChange{name: "P-CHAIN BALANCE", before: i.balance, after: after, format: amount.Format})
+	return changes, nil
+}
+
+// validatorChanges returns the validator set size and total weight
+// changes from the weights [before] to [after] of a subnet, or of the
+// [primary] network.
+func validatorChanges(primary bool, before map[ids.ShortID]uint64, after map[ids.ShortID]uint64) []stateChange {
+	b, a := weights.Analyze(before), weights.Analyze(after)
+	if primary {
+		// primary network weights are stake amounts
+		return []stateChange{
+			{name: "PRIMARY NETWORK VALIDATORS", before: uint64(len(b.Shares)), after: uint64(len(a.Shares)), format: formatCount},
+			{name: "PRIMARY NETWORK STAKE", before: b.Total, after: a.Total, format: amount.Format},
+		}
+	}
+	return []stateChange{
+		{name: "SUBNET VALIDATORS", before: uint64(len(b.Shares)), after: uint64(len(a.Shares)), format: formatCount},
+		{name: "SUBNET TOTAL WEIGHT", before: b.Total, after: a.Total, format: formatCount},
+	}
+}
+
+// makeChangesTable renders [changes] as "before → after (delta)" rows.
+func makeChangesTable(changes []stateChange) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	for _, c := range changes {
+		delta := color.F("{{light-gray}}(unchanged){{/}}")
+		switch {
+		case c.after > c.before:
+			delta = color.F("{{green}}(+%s){{/}}", c.format(c.after-c.before))
+		case c.after < c.before:
+			delta = color.F("{{red}}(-%s){{/}}", c.format(c.before-c.after))
+		}
+		tb.Append([]string{
+			color.F("{{cyan}}{{bold}}%s{{/}}", c.name),
+			color.F("{{light-gray}}%s{{/}} → {{bold}}%s{{/}} ", c.format(c.before), c.format(c.after)) + delta,
+		})
+	}
+	tb.Render()
+	return buf.String()
+}
+
+// confirmChanges prints the preview [table] and the state [changes] of the
+// operation, and asks the operator to apply them to [action], agreeing to
+// [agree] (e.g., "I agree to pay the fee"). It returns false if the
+// operation must stop: the operator declined, or "--dry-run" is set.
+func confirmChanges(table string, changes []stateChange, action string, agree string) bool {
+	if enablePrompt {
+		table = color.F("\n{{blue}}{{bold}}Ready to %s, should we continue?{{/}}\n", action) + table
+	}
+	color.Print(table)
+	color.Print(color.F("\n{{blue}}{{bold}}Changes:{{/}}\n") + makeChangesTable(changes))
+	if dryRun {
+		color.Outf("{{yellow}}dry run, no tx issued{{/}}\n")
+		return false
+	}
+	if !enablePrompt {
+		return true
+	}
+	prompt := promptui.Select{
+		Label:  color.F("\n{{blue}}{{bold}}Apply these changes?{{/}}"),
+		Stdout: os.Stdout,
+		Items: []string{
+			color.F("{{green}}Yes, apply them! {{bold}}{{underline}}%s{{/}}{{green}}!{{/}}", agree),
+			color.F("{{red}}No, stop it!{{/}}"),
+		},
+	}
+	idx, _, err := prompt.Run()
+	return err == nil && idx == 0
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// defaultL1ValidatorBalance prepays about a month of the minimum continuous
+// fee (512 nAVAX/s).
+const defaultL1ValidatorBalance = 1_500_000_000
+
+var (
+	managerAddrs       string
+	l1Validators       []string
+	l1ValidatorBalance uint64
+)
+
+// ConvertCommand implements "subnet-cli convert" command.
+func ConvertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Sub-commands for converting subnets",
+	}
+	cmd.AddCommand(
+		newConvertL1Command(),
+	)
+	return cmd
+}
+
+func newConvertL1Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "l1 [options]",
+		Short: "Converts a subnet to a sovereign L1 (ACP-77)",
+		Long: `
+Converts a permissioned subnet to a sovereign L1 (ACP-77, activated by the
+Etna upgrade). The validator set of the L1 is then managed by the contract
+at "--manager-address" on "--chain-id", starting with the "--validator"
+nodes. The conversion is irreversible: the subnet owner can no longer add
+validators or blockchains.
+
+Each validator pays a continuous fee from its balance, which is burned
+with the tx fee; a validator whose balance runs out is deactivated. The
+preview shows how long the balances last at the current fee.
+
+$ subnet-cli convert l1 \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--chain-id="2XDnKyAEr1RhhWpTpMXqrjeejN23vETmDykVzkb4PrU1fQjewh" \
+--manager-address=0x0feedc0de0000000000000000000000000000000 \
+--validator=node-id=NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH,weight=100,balance=2avax,bls-public-key=0x...,bls-pop=0x...
+
+`,
+		Args: cobra.NoArgs,
+		RunE: convertL1Func,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&blockchainID, "chain-id", "", "blockchain ID of the validator manager contract")
+	cmd.PersistentFlags().StringVar(&managerAddrs, "manager-address", "", "hex address of the validator manager contract")
+	cmd.PersistentFlags().StringArrayVar(&l1Validators, "validator", nil, "initial validator as node-id=...,weight=...,bls-public-key=0x...,bls-pop=0x...[,balance=...] (repeatable)")
+	l1ValidatorBalance = defaultL1ValidatorBalance
+	cmd.PersistentFlags().Var((*amountFlag)(&l1ValidatorBalance), "validator-balance", "balance of each validator without balance=..., funding its continuous fee")
+	return cmd
+}
+
+func convertL1Func(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	info.subnetIDType = "SUBNET ID"
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+	chainID, err := ids.FromString(blockchainID)
+	if err != nil {
+		return fmt.Errorf("%w: --chain-id %q", err, blockchainID)
+	}
+	address, err := hex.DecodeString(strings.TrimPrefix(managerAddrs, "0x"))
+	if err != nil || len(address) == 0 {
+		return fmt.Errorf("%w: --manager-address %q", l1.ErrInvalidValidator, managerAddrs)
+	}
+	if len(l1Validators) == 0 {
+		return fmt.Errorf("%w: --validator required", codec.ErrNoL1Validators)
+	}
+	owner := info.key.Addresses()[0]
+	vs := make([]l1.Validator, 0, len(l1Validators))
+	validators := make([]*codec.ConvertSubnetToL1Validator, 0, len(l1Validators))
+	balances := uint64(0)
+	for _, s := range l1Validators {
+		v, err := l1.ParseValidator(s, l1ValidatorBalance)
+		if err != nil {
+			return err
+		}
+		vs = append(vs, v)
+		validators = append(validators, v.Codec(owner))
+		balances += v.Balance
+	}
+
+	fee, err := cli.P().GetValidatorFee(ctx)
+	if err != nil {
+		return err
+	}
+
+	info.txFee, err = info.Fee(ctx, client.TxTypeConvertSubnetToL1, 0)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee + balances
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(makeConvertL1Table(info, chainID, address, vs, fee), changes, "convert the subnet to an L1 (this cannot be undone)", "I agree to pay the fee and the validator balances") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+	println()
+	println()
+	println()
+	took, err := cli.P().ConvertSubnetToL1(ctx, info.key, info.subnetID, chainID, address, validators)
+	if err != nil {
+		return err
+	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}converted subnet{{/}} %q {{magenta}}to an L1{{/}} {{light-gray}}(took %v){{/}}\n\n", info.subnetID, took)
+	color.Result(info.subnetID)
+	// the validators are sorted by node ID, the order of their validation IDs
+	for i, v := range validators {
+		nodeID, _ := ids.ToShortID(v.NodeID)
+		color.Outf("{{orange}}%s{{/}} {{light-gray}}validation ID{{/}} %s\n", nodeID.PrefixedString(constants.NodeIDPrefix), codec.L1ValidationID(info.subnetID, uint32(i)))
+	}
+	return nil
+}
+
+// makeConvertL1Table previews the conversion with the continuous fee
+// [fee] of its validators.
+func makeConvertL1Table(i *Info, chainID ids.ID, address []byte, vs []l1.Validator, fee *client.ValidatorFee) string {
+	buf, tb := BaseTableSetup(i)
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindSubnet, i.subnetID))})
+	tb.Append([]string{color.F("{{dark-green}}MANAGER CHAIN ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindBlockchain, chainID))})
+	tb.Append([]string{color.F("{{dark-green}}MANAGER ADDRESS{{/}}"), color.F("{{light-gray}}{{bold}}0x%x{{/}}", address)})
+	for _, v := range vs {
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", v.NodeID.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}weight {{bold}}%d{{/}}{{light-gray}}, balance {{bold}}%s{{/}}{{light-gray}} (%s){{/}}", v.Weight, amount.Format(v.Balance), l1BalanceStatus(fee, v.Balance)) + i.fiat(v.Balance),
+		})
+	}
+	tb.Append([]string{color.F("{{red}}{{bold}}CONTINUOUS FEE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} nAVAX/s per validator {{light-gray}}(min %s nAVAX/s, rises above %d L1 validators){{/}}", humanize.Comma(int64(fee.Price)), humanize.Comma(int64(fee.MinPrice)), fee.Target)})
+	month := fee.Cost(30*24*time.Hour) * uint64(len(vs))
+	tb.Append([]string{color.F("{{red}}{{bold}}MONTHLY COST{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} for %d validators at the current fee", amount.Format(month), len(vs)) + i.fiat(month)})
+	tb.Render()
+	return buf.String()
+}
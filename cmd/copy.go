@@ -0,0 +1,36 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/subnet-cli/internal/clipboard"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var copyResult bool
+
+// copyResults copies the results of a successful command (e.g., the new
+// blockchain ID) to the clipboard if "--copy" is set, one per line. A
+// clipboard failure only warns, since the command itself succeeded.
+func copyResults(ctx context.Context) {
+	results := color.Results()
+	if !copyResult || len(results) == 0 {
+		return
+	}
+	// the OSC 52 fallback must reach the terminal, not a pipe
+	var term io.Writer
+	if fi, err := os.Stderr.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		term = os.Stderr
+	}
+	if err := clipboard.Write(ctx, strings.Join(results, "\n"), term); err != nil {
+		color.Errf("{{yellow}}cannot copy to the clipboard: %v{{/}}\n", err)
+		return
+	}
+	color.Outf("{{green}}copied %s to the clipboard{{/}}\n", strings.Join(results, ", "))
+}
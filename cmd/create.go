@@ -5,8 +5,10 @@ package cmd
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/pkg/color"
 )
 
 // CreateCommand implements "subnet-cli create" command.
@@ -20,25 +22,37 @@ func CreateCommand() *cobra.Command {
 		newCreateSubnetCommand(),
 		newCreateBlockchainCommand(),
 		newCreateVMIDCommand(),
+		newCreateAssetCommand(),
 	)
 	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
-	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path")
-	cmd.PersistentFlags().BoolVarP(&useLedger, "ledger", "l", false, "use ledger to sign transactions")
+	addSignerFlags(cmd)
 	return cmd
 }
 
 func MakeCreateTable(i *Info) string {
 	buf, tb := BaseTableSetup(i)
 	if i.subnetID != ids.Empty {
-		tb.Append([]string{formatter.F("{{blue}}%s{{/}}", i.subnetIDType), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.subnetID)})
+		tb.Append([]string{color.F("{{blue}}%s{{/}}", i.subnetIDType), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindSubnet, i.subnetID))})
+	}
+	if len(i.chains) > 0 {
+		for _, c := range i.chains {
+			if c.blockchainID != ids.Empty {
+				tb.Append([]string{color.F("{{blue}}CREATED BLOCKCHAIN ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindBlockchain, c.blockchainID))})
+			}
+			tb.Append([]string{color.F("{{dark-green}}CHAIN NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", c.name)})
+			tb.Append([]string{color.F("{{dark-green}}VM ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", c.vmID)})
+			tb.Append([]string{color.F("{{dark-green}}VM GENESIS PATH{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", c.genesisPath)})
+		}
+		tb.Render()
+		return buf.String()
 	}
 	if i.blockchainID != ids.Empty {
-		tb.Append([]string{formatter.F("{{blue}}CREATED BLOCKCHAIN ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.blockchainID)})
+		tb.Append([]string{color.F("{{blue}}CREATED BLOCKCHAIN ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindBlockchain, i.blockchainID))})
 	}
 	if i.chainName != "" {
-		tb.Append([]string{formatter.F("{{dark-green}}CHAIN NAME{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
-		tb.Append([]string{formatter.F("{{dark-green}}VM ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
-		tb.Append([]string{formatter.F("{{dark-green}}VM GENESIS PATH{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
+		tb.Append([]string{color.F("{{dark-green}}CHAIN NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
+		tb.Append([]string{color.F("{{dark-green}}VM ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
+		tb.Append([]string{color.F("{{dark-green}}VM GENESIS PATH{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
 	}
 	tb.Render()
 	return buf.String()
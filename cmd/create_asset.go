@@ -0,0 +1,238 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/asset"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	assetName         string
+	assetSymbol       string
+	assetDenomination uint8
+	assetHolders      []string
+	assetMinterSets   []string
+)
+
+func newCreateAssetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "asset",
+		Short: "Creates an X-Chain asset (e.g., the staking token of an elastic subnet)",
+		Long: `
+Creates an X-Chain asset with the initial holder allocations and minter
+sets. Without minter sets, the supply is fixed, as the staking token of an
+elastic subnet requires. Amounts are in units of the asset (with
+"--denomination" decimal places). The key pays the fee in X-Chain AVAX.
+
+$ subnet-cli create asset \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=http://localhost:52250 \
+--asset-name="My Token" \
+--symbol=TKN \
+--denomination=9 \
+--holder="address=X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u,amount=720000000"
+
+A mintable asset has one or more minter sets, of which "threshold" of the
+addresses must sign to mint more:
+
+$ subnet-cli create asset \
+--asset-name="My Token" \
+--symbol=TKN \
+--minter-set="threshold=1,address=X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+
+`,
+		RunE: createAssetFunc,
+	}
+
+	cmd.PersistentFlags().StringVar(&assetName, "asset-name", "", "asset name (letters, numbers and spaces)")
+	cmd.PersistentFlags().StringVar(&assetSymbol, "symbol", "", "asset symbol (1-4 upper case letters)")
+	cmd.PersistentFlags().Uint8Var(&assetDenomination, "denomination", 9, "number of decimal places of the asset")
+	cmd.PersistentFlags().StringArrayVar(&assetHolders, "holder", nil, "initial allocation as comma-separated key=value pairs (address, amount); repeatable")
+	cmd.PersistentFlags().StringArrayVar(&assetMinterSets, "minter-set", nil, "minters as comma-separated key=value pairs (threshold, one address per minter); repeatable")
+	return cmd
+}
+
+// newAsset is the asset "create asset" creates.
+type newAsset struct {
+	name    string
+	symbol  string
+	holders []asset.Holder
+	minters []asset.MinterSet
+	supply  uint64
+	state   *avm.InitialState
+
+	xAddr    string
+	xBalance uint64
+	assetID  ids.ID
+	idType   string
+}
+
+func parseNewAsset(networkID uint32) (*newAsset, error) {
+	a := &newAsset{name: assetName, symbol: assetSymbol}
+	for _, s := range assetHolders {
+		h, err := asset.ParseHolder(networkID, s, assetDenomination)
+		if err != nil {
+			return nil, err
+		}
+		a.holders = append(a.holders, h)
+	}
+	for _, s := range assetMinterSets {
+		m, err := asset.ParseMinterSet(networkID, s)
+		if err != nil {
+			return nil, err
+		}
+		a.minters = append(a.minters, m)
+	}
+	var err error
+	a.supply, err = asset.Supply(a.holders)
+	if err != nil {
+		return nil, err
+	}
+	a.state, err = asset.InitialState(a.holders, a.minters)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func createAssetFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	a, err := parseNewAsset(info.networkID)
+	if err != nil {
+		return err
+	}
+	a.xAddr, err = key.FormatXAddress(info.networkID, info.key.Addresses()[0])
+	if err != nil {
+		return err
+	}
+
+	a.assetID, _, err = cli.X().CreateAsset(ctx, info.key, a.name, a.symbol, assetDenomination, a.state, client.WithDryMode(true))
+	if err != nil {
+		return err
+	}
+	a.idType = "EXPECTED ASSET ID"
+	info.txFee, err = cli.X().CreateAssetFee(ctx)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee
+	a.xBalance, err = cli.X().Balance(ctx, info.key)
+	if err != nil {
+		return err
+	}
+	if a.xBalance < info.txFee {
+		color.Outf("{{red}}insufficient X-Chain funds to create the asset (export AVAX from the P-Chain or C-Chain first){{/}}\n")
+		return fmt.Errorf("%w: on %s (expected=%d, have=%d)", ErrInsufficientFunds, a.xAddr, info.txFee, a.xBalance)
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	// the fee is burned on the X-Chain, so the P-Chain balance is unchanged
+	changes := []stateChange{{name: "X-CHAIN BALANCE", before: a.xBalance, after: a.xBalance - info.txFee, format: amount.Format}}
+	if !confirmChanges(makeCreateAssetTable(info, a), changes, "create the asset", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+
+	println()
+	println()
+	println()
+	assetID, took, err := cli.X().CreateAsset(ctx, info.key, a.name, a.symbol, assetDenomination, a.state)
+	if err != nil {
+		return err
+	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
+	a.assetID = assetID
+	a.idType = "CREATED ASSET ID"
+
+	color.Outf("{{magenta}}created asset{{/}} %q {{light-gray}}(took %v){{/}}\n", assetID, took)
+	color.Result(assetID)
+	if len(a.minters) == 0 {
+		color.Outf("({{orange}}the supply is fixed; use the asset ID as the staking asset of an elastic subnet{{/}})\n\n")
+	}
+
+	info.txFee = 0
+	info.requiredBalance = 0
+	a.xBalance, err = cli.X().Balance(ctx, info.key)
+	if err != nil {
+		return err
+	}
+	color.Print(makeCreateAssetTable(info, a))
+	return nil
+}
+
+func makeCreateAssetTable(i *Info, a *newAsset) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	tb.Append([]string{color.F("{{cyan}}{{bold}}PRIMARY X-CHAIN ADDRESS{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", a.xAddr)})
+	tb.Append([]string{color.F("{{coral}}{{bold}}TOTAL X-CHAIN BALANCE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}}", amount.Format(a.xBalance)) + i.fiat(a.xBalance)})
+	if i.txFee > 0 {
+		tb.Append([]string{color.F("{{red}}{{bold}}TX FEE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}}", amount.Format(i.txFee)) + i.fiat(i.txFee)})
+	}
+	tb.Append([]string{color.F("{{blue}}%s{{/}}", a.idType), color.F("{{light-gray}}{{bold}}%s{{/}}", a.assetID)})
+	tb.Append([]string{color.F("{{dark-green}}ASSET NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", a.name)})
+	tb.Append([]string{color.F("{{dark-green}}SYMBOL{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", a.symbol)})
+	tb.Append([]string{color.F("{{dark-green}}DENOMINATION{{/}}"), color.F("{{light-gray}}{{bold}}%d{{/}}", assetDenomination)})
+	supply := "fixed"
+	if len(a.minters) > 0 {
+		supply = "mintable"
+	}
+	tb.Append([]string{color.F("{{dark-green}}INITIAL SUPPLY{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} {{light-gray}}(%s){{/}}", amount.FormatAsset(a.supply, assetDenomination, a.symbol), supply)})
+	for _, h := range a.holders {
+		addr, err := key.FormatXAddress(i.networkID, h.Address)
+		if err != nil {
+			addr = h.Address.String()
+		}
+		tb.Append([]string{color.F("{{dark-green}}HOLDER{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}: %s", addr, amount.FormatAsset(h.Amount, assetDenomination, a.symbol))})
+	}
+	for _, m := range a.minters {
+		addrs := make([]string, len(m.Addresses))
+		for j, addr := range m.Addresses {
+			s, err := key.FormatXAddress(i.networkID, addr)
+			if err != nil {
+				s = addr.String()
+			}
+			addrs[j] = s
+		}
+		tb.Append([]string{color.F("{{dark-green}}MINTER SET{{/}}"), color.F("{{light-gray}}{{bold}}%d of %v{{/}}", m.Threshold, addrs)})
+	}
+	tb.Append([]string{color.F("{{orange}}URI{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.uri)})
+	tb.Append([]string{color.F("{{orange}}NETWORK NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.networkName)})
+	tb.Render()
+	return buf.String()
+}
@@ -4,19 +4,35 @@
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/spec"
 	"github.com/ava-labs/subnet-cli/pkg/color"
-	"github.com/manifoldco/promptui"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
 )
 
+var ErrInvalidChainDef = errors.New("invalid chain definition")
+
+// createBlockchainOptions are the flags of "create blockchain".
+type createBlockchainOptions struct {
+	subnetID      string
+	chainName     string
+	vmID          string
+	vmName        string
+	vmGenesisPath string
+	chainSpecs    []string
+	specPath      string
+	chainAlias    string
+}
+
 func newCreateBlockchainCommand() *cobra.Command {
+	o := &createBlockchainOptions{}
 	cmd := &cobra.Command{
 		Use:   "blockchain [options]",
 		Short: "Creates a blockchain",
@@ -28,97 +44,281 @@ $ subnet-cli create blockchain \
 --subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
 --chain-name=my-custom-chain \
 --vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH \
---vm-genesis-path=.my-custom-vm.genesis
+--vm-genesis-path=.my-custom-vm.genesis \
+--chain-alias=mychain
+
+Several chains of the same subnet are created in one run with "--chain"
+(repeatable), or from the spec subnet whose "id" is "--subnet-id":
+
+$ subnet-cli create blockchain \
+--private-key-path=.insecure.ewoq.key \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--chain=name=chain-a,vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH,genesis=.a.genesis \
+--chain=name=chain-b,vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH,genesis=.b.genesis
+
+$ subnet-cli create blockchain -f spec.yaml --subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"
 
 `,
-		RunE: createBlockchainFunc,
+		RunE: o.run,
 	}
 
-	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
-	cmd.PersistentFlags().StringVar(&chainName, "chain-name", "", "chain name")
-	cmd.PersistentFlags().StringVar(&vmIDs, "vm-id", "", "VM ID (must be formatted in ids.ID)")
-	cmd.PersistentFlags().StringVar(&vmGenesisPath, "vm-genesis-path", "", "VM genesis file path")
+	cmd.PersistentFlags().StringVar(&o.subnetID, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringVar(&o.chainName, "chain-name", "", "chain name")
+	cmd.PersistentFlags().StringVar(&o.vmID, "vm-id", "", "VM ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringVar(&o.vmName, "vm-name", "", "registered VM name (resolves --vm-id if empty; reusing a VM ID under a different name needs --force)")
+	cmd.PersistentFlags().StringVar(&o.vmGenesisPath, "vm-genesis-path", "", "VM genesis file path")
+	cmd.PersistentFlags().StringArrayVar(&o.chainSpecs, "chain", nil, "additional chain as name=...,vm-id=...,genesis=... (repeatable)")
+	cmd.PersistentFlags().StringVarP(&o.specPath, "file", "f", "", "deployment spec file path (creates the chains of the subnet with id --subnet-id)")
+	cmd.PersistentFlags().StringVar(&o.chainAlias, "chain-alias", "", "alias to set for the blockchain on the validators with the admin API (e.g., /ext/bc/[alias]/rpc)")
+	addFailureReportFlag(cmd)
+	addRPCEndpointFlags(cmd)
+	addSmokeTestFlags(cmd)
 
 	return cmd
 }
 
-func createBlockchainFunc(cmd *cobra.Command, args []string) error {
-	cli, info, err := InitClient(publicURI, true)
+func (o *createBlockchainOptions) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if err := checkRPCEndpointFlags(); err != nil {
+		return err
+	}
+	if smokeTest {
+		if _, err := smokeTestURI(); err != nil {
+			return err
+		}
+	}
+	cli, info, err := InitClient(ctx, publicURI, true)
 	if err != nil {
 		return err
 	}
 	info.subnetIDType = "SUBNET ID"
-	info.subnetID, err = ids.FromString(subnetIDs)
+	chains, err := o.loadChainDefs()
 	if err != nil {
 		return err
 	}
-	info.vmID, err = ids.FromString(vmIDs)
+	info.subnetID, err = ids.FromString(o.subnetID)
 	if err != nil {
 		return err
 	}
-	vmGenesisBytes, err := ioutil.ReadFile(vmGenesisPath)
+	if len(chains) > 1 && o.chainAlias != "" {
+		return fmt.Errorf("%w: --chain-alias with %d chains", ErrInvalidChainDef, len(chains))
+	}
+	info.txFee, err = info.Fee(ctx, client.TxTypeCreateBlockchain, len(chains))
 	if err != nil {
 		return err
 	}
-	info.txFee = uint64(info.feeData.CreateBlockchainTxFee)
 	info.requiredBalance = info.txFee
 	if err := info.CheckBalance(); err != nil {
 		return err
 	}
-	info.chainName = chainName
-	info.vmGenesisPath = vmGenesisPath
-
-	msg := MakeCreateTable(info)
-	if enablePrompt {
-		msg = formatter.F("\n{{blue}}{{bold}}Ready to create blockchain resources, should we continue?{{/}}\n") + msg
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, msg)
-
-	if enablePrompt {
-		prompt := promptui.Select{
-			Label:  "\n",
-			Stdout: os.Stdout,
-			Items: []string{
-				formatter.F("{{green}}Yes, let's create! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
-				formatter.F("{{red}}No, stop it!{{/}}"),
-			},
-		}
-		idx, _, err := prompt.Run()
-		if err != nil {
-			return nil //nolint:nilerr
-		}
-		if idx == 1 {
-			return nil
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+	if o.chainAlias != "" {
+		if err := names.ValidateAlias(o.chainAlias); err != nil {
+			return err
 		}
 	}
+	info.setChain(chains[0])
+	if len(chains) > 1 {
+		info.chains = chains
+	}
+
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(MakeCreateTable(info), changes, "create blockchain resources", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
 	println()
 	println()
 	println()
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	blockchainID, took, err := cli.P().CreateBlockchain(
-		ctx,
-		info.key,
-		info.subnetID,
-		info.chainName,
-		info.vmID,
-		vmGenesisBytes,
-	)
-	cancel()
-	if err != nil {
-		return err
+	r := rollback.New("create blockchain", info.networkID)
+	for _, c := range chains {
+		r.Plan(chainAction(info.subnetID, c))
+	}
+	for _, c := range chains {
+		blockchainID, took, err := cli.P().CreateBlockchain(
+			ctx,
+			info.key,
+			info.subnetID,
+			c.name,
+			c.vmID,
+			c.genesis,
+		)
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		c.blockchainID = blockchainID
+		r.Complete(chainAction(info.subnetID, c))
+		if err := info.RecordApproval(); err != nil {
+			return reportRollback(info, r, err)
+		}
+		color.Outf("{{magenta}}created blockchain{{/}} %q {{light-gray}}(%s, took %v){{/}}\n\n", blockchainID, c.name, took)
+		color.Result(blockchainID)
+		recordName(info.networkID, names.KindBlockchain, c.name, blockchainID)
 	}
-	info.blockchainID = blockchainID
-	color.Outf("{{magenta}}created blockchain{{/}} %q {{light-gray}}(took %v){{/}}\n\n", info.blockchainID, took)
 
 	info.requiredBalance = 0
 	info.stakeAmount = 0
 	info.txFee = 0
-	ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
 	info.balance, err = cli.P().Balance(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, MakeCreateTable(info))
+	info.setChain(chains[0])
+	color.Print(MakeCreateTable(info))
+	for _, c := range chains {
+		info.setChain(c)
+		if err := PrintRPCEndpoints(info, c.genesis); err != nil {
+			return err
+		}
+		if o.chainAlias != "" {
+			if err := AliasChain(ctx, info, o.chainAlias); err != nil {
+				return err
+			}
+		}
+		if smokeTest {
+			if err := RunSmokeTest(ctx, cli, info, c.genesis); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
+
+// chainDef is a blockchain to create.
+type chainDef struct {
+	name         string
+	vmID         ids.ID
+	genesisPath  string
+	genesis      []byte
+	blockchainID ids.ID
+}
+
+func (i *Info) setChain(c *chainDef) {
+	i.chainName = c.name
+	i.vmID = c.vmID
+	i.vmGenesisPath = c.genesisPath
+	i.blockchainID = c.blockchainID
+}
+
+// chainAction returns the create blockchain action of [c] on [subnetID].
+func chainAction(subnetID ids.ID, c *chainDef) rollback.Action {
+	a := rollback.Action{
+		Kind:        rollback.KindCreateBlockchain,
+		SubnetID:    subnetID.String(),
+		Name:        c.name,
+		VMID:        c.vmID.String(),
+		GenesisPath: c.genesisPath,
+	}
+	if c.blockchainID != ids.Empty {
+		a.BlockchainID = c.blockchainID.String()
+	}
+	return a
+}
+
+// loadChainDefs returns the chains to create: the one of "--chain-name",
+// every "--chain" and the chains of the spec subnet of "--subnet-id".
+func (o *createBlockchainOptions) loadChainDefs() ([]*chainDef, error) {
+	chains := []*chainDef{}
+	if o.chainName != "" {
+		vmID, err := resolveVMID(o.vmID, o.vmName)
+		if err != nil {
+			return nil, err
+		}
+		c, err := newChainDef(o.chainName, vmID, o.vmGenesisPath)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, c)
+	}
+	defs := []spec.Chain{}
+	for _, s := range o.chainSpecs {
+		c, err := spec.ParseChain(s)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, c)
+	}
+	var s *spec.Spec
+	if o.specPath != "" {
+		var err error
+		s, err = spec.Load(o.specPath)
+		if err != nil {
+			return nil, err
+		}
+		sn, ok := s.SubnetByID(o.subnetID)
+		if !ok {
+			return nil, fmt.Errorf("%w: no subnet with id %q in %s", ErrInvalidChainDef, o.subnetID, o.specPath)
+		}
+		if o.subnetID == "" {
+			o.subnetID = sn.ID
+		}
+		defs = append(defs, sn.Chains...)
+	}
+	for _, d := range defs {
+		vmID, err := ids.FromString(d.VMID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: chain %q vm-id %q", err, d.Name, d.VMID)
+		}
+		p := d.Genesis
+		if s != nil {
+			p = s.GenesisPath(d)
+		}
+		c, err := newChainDef(d.Name, vmID, p)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, c)
+	}
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("%w: --chain-name, --chain or --file required", ErrInvalidChainDef)
+	}
+	seen := map[string]bool{}
+	for _, c := range chains {
+		if seen[c.name] {
+			return nil, fmt.Errorf("%w: duplicate chain name %q", ErrInvalidChainDef, c.name)
+		}
+		seen[c.name] = true
+	}
+	return chains, nil
+}
+
+// resolveVMID parses [vmID], or looks up [name] in the VM registries if
+// [vmID] is empty. A named VM ID is registered (see "--vm-name").
+func resolveVMID(vmID string, name string) (id ids.ID, err error) {
+	if vmID == "" && name != "" {
+		id, err = lookupVM(name)
+	} else {
+		id, err = ids.FromString(vmID)
+	}
+	if err != nil {
+		return ids.Empty, err
+	}
+	if name != "" {
+		if err := registerVM(name, id); err != nil {
+			return ids.Empty, err
+		}
+	}
+	return id, nil
+}
+
+func newChainDef(name string, vmID ids.ID, genesisPath string) (*chainDef, error) {
+	b, err := ioutil.ReadFile(genesisPath)
+	if err != nil {
+		return nil, err
+	}
+	return &chainDef{name: name, vmID: vmID, genesisPath: genesisPath, genesis: b}, nil
+}
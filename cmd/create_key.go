@@ -6,7 +6,9 @@ package cmd
 import (
 	"os"
 
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/qr"
 	"github.com/ava-labs/subnet-cli/pkg/color"
 	"github.com/spf13/cobra"
 )
@@ -20,24 +22,46 @@ Generates a private key.
 
 $ subnet-cli create key --private-key-path=.insecure.test.key
 
+# label the key for a network so that it is never used elsewhere
+$ subnet-cli create key --private-key-path=.insecure.test.key --network-name=fuji
+
 `,
 		RunE: createKeyFunc,
 	}
+	cmd.PersistentFlags().StringVar(&keyNetworkName, "network-name", "", "network the key is intended for (e.g., fuji, mainnet); empty to not label")
 	return cmd
 }
 
+var keyNetworkName string
+
 func createKeyFunc(cmd *cobra.Command, args []string) error {
 	if _, err := os.Stat(privKeyPath); err == nil {
 		color.Outf("{{red}}key already found at %q{{/}}\n", privKeyPath)
 		return os.ErrExist
 	}
-	k, err := key.NewSoft(0)
+	networkID := uint32(0)
+	if keyNetworkName != "" {
+		var err error
+		networkID, err = constants.NetworkID(keyNetworkName)
+		if err != nil {
+			return err
+		}
+	}
+	k, err := key.NewSoft(networkID)
 	if err != nil {
 		return err
 	}
 	if err := k.Save(privKeyPath); err != nil {
 		return err
 	}
+	if keyNetworkName != "" {
+		if err := key.SaveNetworkLabel(privKeyPath, networkID); err != nil {
+			return err
+		}
+		color.Outf("{{green}}labeled key %q for network %q (%s){{/}}\n", privKeyPath, constants.NetworkName(networkID), k.P()[0])
+	}
 	color.Outf("{{green}}created a new key %q{{/}}\n", privKeyPath)
+	color.Result(k.P()[0])
+	printQR("P-Chain address "+k.P()[0], k.P()[0], qr.Medium)
 	return nil
 }
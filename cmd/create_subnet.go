@@ -5,16 +5,16 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"os"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
 	"github.com/ava-labs/subnet-cli/pkg/color"
-	"github.com/manifoldco/promptui"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
 )
 
+var subnetName string
+
 func newCreateSubnetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "subnet",
@@ -26,79 +26,144 @@ $ subnet-cli create subnet \
 --private-key-path=.insecure.ewoq.key \
 --public-uri=http://localhost:52250
 
+With "--subnet-name", the name is embedded in the tx memo and recorded in
+"--names-path", so re-running the command finds the existing subnet
+instead of creating a duplicate:
+
+$ subnet-cli create subnet \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=http://localhost:52250 \
+--subnet-name=my-subnet
+
 `,
 		RunE: createSubnetFunc,
 	}
 
+	cmd.PersistentFlags().StringVar(&subnetName, "subnet-name", "", "name to tag the subnet with; skips creation if a subnet with this name exists")
 	return cmd
 }
 
+// existingSubnet returns the subnet named [name] in [reg] if it exists on
+// the network.
+func existingSubnet(ctx context.Context, cli client.Client, reg *names.Registry, name string) (ids.ID, bool, error) {
+	id, ok := reg.Lookup(cli.NetworkID(), names.KindSubnet, name)
+	if !ok {
+		return ids.Empty, false, nil
+	}
+	ss, err := cli.P().Client().GetSubnets(ctx, []ids.ID{id})
+	if err != nil {
+		return ids.Empty, false, err
+	}
+	if len(ss) == 0 {
+		// e.g., a local network that was reset since
+		color.Outf("{{yellow}}subnet %q (%s) is recorded in %q but does not exist, creating it again{{/}}\n", name, id, namesPath)
+		return ids.Empty, false, nil
+	}
+	return id, true, nil
+}
+
 func createSubnetFunc(cmd *cobra.Command, args []string) error {
-	cli, info, err := InitClient(publicURI, true)
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	var reg *names.Registry
+	if subnetName != "" {
+		if err := names.Validate(subnetName); err != nil {
+			return err
+		}
+		var err error
+		reg, err = nameRegistry()
+		if err != nil {
+			return err
+		}
+	}
+	cli, info, err := InitClient(ctx, publicURI, true)
 	if err != nil {
 		return err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	sid, _, err := cli.P().CreateSubnet(ctx, info.key, client.WithDryMode(true))
-	cancel()
+	var opts []client.OpOption
+	if reg != nil {
+		id, ok, err := existingSubnet(ctx, cli, reg, subnetName)
+		if err != nil {
+			return err
+		}
+		if ok {
+			color.Outf("{{green}}subnet %q already exists, skipping creation{{/}}\n", subnetName)
+			color.Result(id)
+			info.subnetIDType = "EXISTING SUBNET ID"
+			info.subnetID = id
+			color.Print(MakeCreateTable(info))
+			return nil
+		}
+		opts = append(opts, client.WithMemo(names.Memo(subnetName)))
+	}
+
+	sid, _, err := cli.P().CreateSubnet(ctx, info.key, append(opts, client.WithDryMode(true))...)
 	if err != nil {
 		return err
 	}
-	info.txFee = uint64(info.feeData.CreateSubnetTxFee)
+	info.txFee, err = info.Fee(ctx, client.TxTypeCreateSubnet, 1)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee
 	info.subnetIDType = "EXPECTED SUBNET ID"
 	info.subnetID = sid
 	if err := info.CheckBalance(); err != nil {
 		return err
 	}
-
-	msg := MakeCreateTable(info)
-	if enablePrompt {
-		msg = formatter.F("\n{{blue}}{{bold}}Ready to create subnet resources, should we continue?{{/}}\n") + msg
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, msg)
 
-	if enablePrompt {
-		prompt := promptui.Select{
-			Label:  "\n",
-			Stdout: os.Stdout,
-			Items: []string{
-				formatter.F("{{red}}No, stop it!{{/}}"),
-				formatter.F("{{green}}Yes, let's create! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
-			},
-		}
-		idx, _, err := prompt.Run()
-		if err != nil {
-			panic(err)
-		}
-		if idx == 0 {
-			return nil
-		}
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(MakeCreateTable(info), changes, "create subnet resources", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
 	}
 
 	println()
 	println()
 	println()
-	ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
-	subnetID, took, err := cli.P().CreateSubnet(ctx, info.key)
-	cancel()
+	subnetID, took, err := cli.P().CreateSubnet(ctx, info.key, opts...)
 	if err != nil {
 		return err
 	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
 	info.subnetIDType = "CREATED SUBNET ID"
 	info.subnetID = subnetID
+	if reg != nil {
+		if err := reg.Set(cli.NetworkID(), names.KindSubnet, subnetName, subnetID); err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+	}
 
 	color.Outf("{{magenta}}created subnet{{/}} %q {{light-gray}}(took %v){{/}}\n", info.subnetID, took)
+	color.Result(info.subnetID)
 	color.Outf("({{orange}}subnet must be whitelisted beforehand via{{/}} {{cyan}}{{bold}}--whitelisted-subnets{{/}} {{orange}}flag!{{/}})\n\n")
 
 	info.requiredBalance = 0
 	info.stakeAmount = 0
 	info.txFee = 0
-	ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
 	info.balance, err = cli.P().Balance(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, MakeCreateTable(info))
+	color.Print(MakeCreateTable(info))
 	return nil
 }
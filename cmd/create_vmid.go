@@ -16,16 +16,29 @@ const (
 	IDLen = 32
 )
 
-var h bool
+var (
+	h          bool
+	registerID bool
+)
 
 func newCreateVMIDCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "VMID [options] <identifier>",
 		Short: "Creates a new encoded VMID from a string",
-		RunE:  createVMIDFunc,
+		Long: `
+Creates a new encoded VMID from a string. With "--register", the identifier
+is recorded as the VM name in "--vm-registry-path" (or "--names-path"), so
+"create blockchain --vm-name" can refer to it and a VM ID is not reused
+for a different VM by accident.
+
+$ subnet-cli create VMID subnetevm --register --vm-registry-path=team-vms.json
+
+`,
+		RunE: createVMIDFunc,
 	}
 
 	cmd.PersistentFlags().BoolVar(&h, "hash", false, "whether or not to hash the identifier argument")
+	cmd.PersistentFlags().BoolVar(&registerID, "register", false, "'true' to record the identifier as the VM name")
 
 	return cmd
 }
@@ -35,23 +48,35 @@ func createVMIDFunc(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected 1 argument but got %d", len(args))
 	}
 
-	identifier := []byte(args[0]) //nolint:ifshort
+	id, err := VMIDFromName(args[0], h)
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}created a new VMID %s from %s{{/}}\n", id.String(), args[0])
+	color.Result(id)
+	if !registerID {
+		return nil
+	}
+	if err := registerVM(args[0], id); err != nil {
+		return err
+	}
+	color.Outf("{{green}}registered VM %q{{/}}\n", args[0])
+	return nil
+}
+
+// VMIDFromName encodes [name] (or its hash) as a VMID.
+func VMIDFromName(name string, hash bool) (ids.ID, error) {
+	identifier := []byte(name) //nolint:ifshort
 	var b []byte
-	if h {
+	if hash {
 		b = hashing.ComputeHash256(identifier)
 	} else {
 		if len(identifier) > IDLen {
-			return fmt.Errorf("non-hashed name must be <= 32 bytes, found %d", len(identifier))
+			return ids.Empty, fmt.Errorf("non-hashed name must be <= 32 bytes, found %d", len(identifier))
 		}
 		b = make([]byte, IDLen)
 		copy(b, identifier)
 	}
-
-	id, err := ids.ToID(b)
-	if err != nil {
-		return err
-	}
-
-	color.Outf("{{green}}created a new VMID %s from %s{{/}}\n", id.String(), args[0])
-	return nil
+	return ids.ToID(b)
 }
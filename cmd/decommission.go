@@ -0,0 +1,259 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/decommission"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	archiveDir string
+	keepNames  bool
+)
+
+// DecommissionCommand implements "subnet-cli decommission" command.
+func DecommissionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decommission",
+		Short: "Retires a subnet",
+		Long: `
+Walks through retiring a (test) subnet: lists its validators and when each
+stops validating, lists the resources that cannot be deleted on-chain and
+what node operators should do, then archives the record (and the local
+names of the subnet and its blockchains) to "--archive-dir".
+
+Once the network activates Banff, the validators are removed with a
+RemoveSubnetValidatorTx each, which the subnet control keys sign. Before
+Banff, they cannot be removed before their end time, so no tx is issued.
+
+$ subnet-cli decommission \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--archive-dir=retired
+
+`,
+		Args: cobra.NoArgs,
+		RunE: decommissionFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&archiveDir, "archive-dir", ".", "directory to write the decommission record to")
+	cmd.PersistentFlags().BoolVar(&keepNames, "keep-names", false, "'true' to keep the names of the subnet and its blockchains in --names-path")
+	addFailureReportFlag(cmd)
+	return cmd
+}
+
+func decommissionFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, false)
+	if err != nil {
+		return err
+	}
+	info.subnetIDType = "SUBNET ID"
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	r := decommission.New(info.networkID, info.subnetID, time.Now())
+	vs, err := cli.P().GetValidators(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	banff := info.upgradeActive(version.Banff)
+	remove := banff && len(vs) > 0
+	if !banff {
+		r.Permanent = append(r.Permanent, decommission.ValidatorsPermanent)
+	}
+	for _, v := range vs {
+		r.Validators = append(r.Validators, decommission.Validator{
+			NodeID:  v.NodeID.PrefixedString(constants.NodeIDPrefix),
+			Weight:  v.Weight,
+			Start:   v.Start,
+			End:     v.End,
+			Pending: v.Pending,
+		})
+	}
+	bcs, err := cli.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return err
+	}
+	for _, bc := range bcs {
+		if bc.SubnetID == info.subnetID {
+			r.Blockchains = append(r.Blockchains, decommission.Blockchain{ID: bc.ID, Name: bc.Name, VMID: bc.VMID})
+		}
+	}
+
+	if remove {
+		if err := info.loadKey(ctx, cli); err != nil {
+			return err
+		}
+		info.txFee, err = info.Fee(ctx, client.TxTypeRemoveSubnetValidator, len(vs))
+		if err != nil {
+			return err
+		}
+		info.requiredBalance = info.txFee
+		for _, v := range vs {
+			info.weightChange += v.Weight
+		}
+		if err := info.CheckBalance(); err != nil {
+			return err
+		}
+		if err := info.CheckPolicy(cmd); err != nil {
+			return err
+		}
+		if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+			return err
+		}
+		if err := info.CheckMainnetSpend(); err != nil {
+			return err
+		}
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	for _, bc := range r.Blockchains {
+		tb.Append([]string{color.F("{{dark-green}}BLOCKCHAIN{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} (%s)", bc.Name, bc.ID)})
+	}
+	for _, v := range r.Validators {
+		status := fmt.Sprintf("validating until %s", v.End.Local().Format(time.RFC3339))
+		if v.Pending {
+			status = fmt.Sprintf("pending, validates %s to %s", v.Start.Local().Format(time.RFC3339), v.End.Local().Format(time.RFC3339))
+		}
+		tb.Append([]string{color.F("{{orange}}%s{{/}}", v.NodeID), color.F("{{light-gray}}%s{{/}}", status)})
+	}
+	tb.Render()
+	color.Print(buf.String())
+
+	switch last := r.LastEnd(); {
+	case remove:
+		color.Outf("{{yellow}}removing the %d validators (RemoveSubnetValidatorTx){{/}}\n", len(r.Validators))
+	case !last.IsZero():
+		color.Outf("{{yellow}}the validator set empties at %s (in %v); validators cannot be removed earlier without Banff{{/}}\n", last.Local().Format(time.RFC3339), time.Until(last).Round(time.Minute))
+	default:
+		color.Outf("{{green}}the subnet has no validators{{/}}\n")
+	}
+	color.Outf("\n{{bold}}cannot be deleted on-chain:{{/}}\n")
+	for _, p := range r.Permanent {
+		color.Outf("  - %s\n", p)
+	}
+	color.Outf("\n{{bold}}node operators should:{{/}}\n")
+	for _, s := range r.NodeSteps {
+		color.Outf("  - %s\n", s)
+	}
+
+	if enablePrompt {
+		label := color.F("\n{{blue}}{{bold}}Archive the record to %q and retire the local names?{{/}}", archiveDir)
+		if remove {
+			label = color.F("\n{{blue}}{{bold}}Remove the %d validators, archive the record to %q and retire the local names?{{/}}", len(vs), archiveDir)
+		}
+		prompt := promptui.Select{
+			Label:  label,
+			Stdout: os.Stdout,
+			Items: []string{
+				color.F("{{green}}Yes, archive it!{{/}}"),
+				color.F("{{red}}No, stop it!{{/}}"),
+			},
+		}
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		if idx == 1 {
+			return nil
+		}
+	}
+
+	if remove {
+		b := new(batch)
+		if err := b.add(ctx, info, client.TxTypeRemoveSubnetValidator, len(vs), 0); err != nil {
+			return err
+		}
+		rb := rollback.New("decommission", info.networkID)
+		for _, v := range vs {
+			rb.Plan(nodeAction(rollback.KindRemoveSubnetValidator, v.NodeID))
+		}
+		for i, v := range vs {
+			if err := b.check(ctx, cli, info); err != nil {
+				return reportRollback(info, rb, err)
+			}
+			took, err := cli.P().RemoveSubnetValidator(ctx, info.key, info.subnetID, v.NodeID)
+			if err != nil {
+				return reportRollback(info, rb, err)
+			}
+			b.done()
+			a := nodeAction(rollback.KindRemoveSubnetValidator, v.NodeID)
+			a.SubnetID, a.Weight = info.subnetID.String(), v.Weight
+			rb.Complete(a)
+			if err := info.RecordApproval(); err != nil {
+				return reportRollback(info, rb, err)
+			}
+			r.Validators[i].Removed = true
+			color.Outf("{{magenta}}removed %s from subnet %s{{/}} {{light-gray}}(took %v){{/}}\n", r.Validators[i].NodeID, info.subnetID, took)
+		}
+	}
+
+	reg, err := nameRegistry()
+	if err != nil {
+		return err
+	}
+	retired := map[ids.ID]bool{info.subnetID: true}
+	for _, bc := range r.Blockchains {
+		retired[bc.ID] = true
+	}
+	for _, e := range reg.Entries {
+		if e.NetworkID == info.networkID && e.Kind != names.KindVM && retired[e.ID] {
+			r.Names = append(r.Names, e)
+		}
+	}
+	p, err := r.Save(archiveDir)
+	if err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}archived decommission record{{/}} %q\n", p)
+	color.Result(p)
+
+	if keepNames || len(r.Names) == 0 {
+		return nil
+	}
+	for _, e := range r.Names {
+		reg.Remove(e.NetworkID, e.Kind, e.ID)
+	}
+	if err := reg.Save(); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}removed %d names from{{/}} %q\n", len(r.Names), namesPath)
+	return nil
+}
+
+// resolveSubnetID parses [s] as a subnet ID, or looks it up as a subnet
+// name.
+func resolveSubnetID(networkID uint32, s string) (ids.ID, error) {
+	if id, err := ids.FromString(s); err == nil {
+		return id, nil
+	}
+	reg, err := nameRegistry()
+	if err != nil {
+		return ids.Empty, err
+	}
+	if id, ok := reg.Lookup(networkID, names.KindSubnet, s); ok {
+		return id, nil
+	}
+	return ids.Empty, fmt.Errorf("%w: subnet %q", ErrUnknownName, s)
+}
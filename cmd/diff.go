@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/spec"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var ErrDrift = errors.New("live state drifted from spec")
+
+// DiffCommand implements "subnet-cli diff" command.
+func DiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compares a deployment spec with the live chain state",
+		Long: `
+Compares the validators, weights and chains declared in the spec with the
+live state of the network and prints the differences. Exits non-zero when
+the state drifted, so it can run from a cron job.
+
+$ subnet-cli diff \
+-f spec.yaml \
+--public-uri=https://api.avax-test.network
+
+`,
+		RunE: diffFunc,
+	}
+	cmd.PersistentFlags().StringVarP(&specPath, "file", "f", "", "deployment spec file path")
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints (overrides the spec)")
+	return cmd
+}
+
+func diffFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	s, err := spec.Load(specPath)
+	if err != nil {
+		return err
+	}
+	uri := publicURI
+	if s.URI != "" && !cmd.Flags().Changed("public-uri") {
+		uri = s.URI
+	}
+	cli, _, err := InitClient(ctx, uri, false)
+	if err != nil {
+		return err
+	}
+	st, err := liveState(ctx, cli, s)
+	if err != nil {
+		return err
+	}
+
+	changes := s.Diff(st)
+	for _, c := range changes {
+		switch c.Kind {
+		case spec.ChangeMissing:
+			color.Outf("{{red}}- %s{{/}}\n", c)
+		case spec.ChangeExtra:
+			color.Outf("{{green}}+ %s{{/}}\n", c)
+		default:
+			color.Outf("{{yellow}}~ %s{{/}}\n", c)
+		}
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("%w: %d change(s)", ErrDrift, len(changes))
+	}
+	color.Outf("{{green}}no drift from %s{{/}}\n", specPath)
+	return nil
+}
+
+// liveState fetches the state of the primary network and of every spec
+// subnet with a known ID.
+func liveState(ctx context.Context, cli client.Client, s *spec.Spec) (spec.State, error) {
+	st := spec.State{}
+
+	primary, err := subnetState(ctx, cli, constants.PrimaryNetworkID, s.Validators)
+	if err != nil {
+		return nil, err
+	}
+	st[constants.PrimaryNetworkID] = primary
+
+	subnetIDs := []ids.ID{}
+	for _, sn := range s.Subnets {
+		if sn.ID == "" {
+			continue
+		}
+		subnetID, err := ids.FromString(sn.ID)
+		if err != nil {
+			return nil, err
+		}
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+	if len(subnetIDs) == 0 {
+		return st, nil
+	}
+
+	subnets, err := cli.P().Client().GetSubnets(ctx, subnetIDs)
+	if err != nil {
+		return nil, err
+	}
+	bcs, err := cli.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subnets {
+		var declared []spec.Validator
+		for _, sn := range s.Subnets {
+			if sn.ID != sub.ID.String() {
+				continue
+			}
+			for _, v := range sn.Validators {
+				declared = append(declared, spec.Validator{NodeID: v.NodeID})
+			}
+		}
+		ss, err := subnetState(ctx, cli, sub.ID, declared)
+		if err != nil {
+			return nil, err
+		}
+		for _, bc := range bcs {
+			if bc.SubnetID == sub.ID {
+				ss.Chains = append(ss.Chains, spec.ChainState{ID: bc.ID, Name: bc.Name, VMID: bc.VMID})
+			}
+		}
+		st[sub.ID] = ss
+	}
+	return st, nil
+}
+
+// subnetState fetches the current validators of [subnetID] and which of the
+// [declared] ones that are not validating yet are pending.
+func subnetState(ctx context.Context, cli client.Client, subnetID ids.ID, declared []spec.Validator) (*spec.SubnetState, error) {
+	ws, err := cli.P().GetValidatorWeights(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	ss := &spec.SubnetState{Validators: ws, Pending: map[ids.ShortID]bool{}}
+	for _, v := range declared {
+		nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ws[nodeID]; ok {
+			continue
+		}
+		_, _, err = cli.P().GetPendingValidator(ctx, subnetID, nodeID)
+		switch {
+		case err == nil:
+			ss.Pending[nodeID] = true
+		case !errors.Is(err, client.ErrValidatorNotFound):
+			return nil, err
+		}
+	}
+	return ss, nil
+}
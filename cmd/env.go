@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+const (
+	// envPrefix prefixes the environment variable of every flag (e.g.,
+	// SUBNET_CLI_PUBLIC_URI for "--public-uri").
+	envPrefix = "SUBNET_CLI_"
+
+	// EnvPrivateKey holds the private key itself (CB58 "PrivateKey-..." or
+	// hex), so CI jobs need no key file.
+	EnvPrivateKey = envPrefix + "PRIVATE_KEY"
+)
+
+// envSkipFlags have no environment variable.
+var envSkipFlags = map[string]struct{}{
+	"help":    {},
+	"version": {},
+}
+
+// envPrivKey is the $SUBNET_CLI_PRIVATE_KEY key to sign with, if it applies.
+var envPrivKey string
+
+// envName returns the environment variable of the flag [name].
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyEnv sets every flag of [cmd] that is not set on the command line
+// from its environment variable. Command-line flags take precedence over
+// environment variables, which take precedence over defaults.
+func applyEnv(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		if _, ok := envSkipFlags[f.Name]; ok {
+			return
+		}
+		v, ok := os.LookupEnv(envName(f.Name))
+		if !ok {
+			return
+		}
+		if serr := cmd.Flags().Set(f.Name, v); serr != nil {
+			err = fmt.Errorf("%s: %w", envName(f.Name), serr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// an explicit key file (or vault path) wins over the key value
+	envPrivKey = ""
+	v := os.Getenv(EnvPrivateKey)
+	if v == "" {
+		return nil
+	}
+	redact.Register(v, strings.TrimPrefix(strings.TrimSpace(v), "0x"))
+	for _, name := range []string{"private-key-path", "key-vault-path"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return nil
+		}
+	}
+	envPrivKey = v
+	return nil
+}
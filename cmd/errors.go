@@ -7,4 +7,19 @@ import (
 	"errors"
 )
 
-var ErrInsufficientFunds = errors.New("insufficient funds")
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrNetworkMismatch   = errors.New("network mismatch")
+	ErrUnknownAddress    = errors.New("unknown address format")
+
+	ErrMainnetNotConfirmed   = errors.New("mainnet spend not confirmed")
+	ErrInvalidValidateWindow = errors.New("invalid validate window")
+	ErrInvalidIssueAt        = errors.New("invalid issue time")
+
+	ErrQuietVerbose = errors.New("--quiet and --verbose are mutually exclusive")
+	ErrRecordReplay = errors.New("--record and --replay are mutually exclusive")
+
+	ErrIncompatibleNode   = errors.New("incompatible node version")
+	ErrUnsupportedUpgrade = errors.New("tx format not supported after network upgrade")
+	ErrUpgradeInactive    = errors.New("network upgrade not active")
+)
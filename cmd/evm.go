@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	evmRPC         string
+	evmPrivKeyPath string
+)
+
+var ErrEmptyEVMRPC = errors.New("empty --evm-rpc")
+
+// EVMCommand implements "subnet-cli evm" command.
+func EVMCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evm",
+		Short: "Sub-commands for administering subnet-evm chains",
+	}
+	cmd.AddCommand(
+		newEVMTeleporterCommand(),
+		newEVMAllowListCommand(),
+		newEVMMintCommand(),
+		newEVMRewardsCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&evmRPC, "evm-rpc", "", "EVM RPC endpoint of the chain (e.g., http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc)")
+	cmd.PersistentFlags().StringVar(&evmPrivKeyPath, "evm-private-key-path", ".subnet-cli.pk", "EVM private key file path (hex or PrivateKey- encoded)")
+	return cmd
+}
+
+// InitEVMClient connects to "--evm-rpc" and loads the EVM key.
+func InitEVMClient() (*evm.Client, *crypto.PrivateKeySECP256K1R, error) {
+	if evmRPC == "" {
+		return nil, nil, ErrEmptyEVMRPC
+	}
+	k, err := key.LoadSoft(0, evmPrivKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	color.Outf("{{yellow}}using EVM address %s{{/}}\n", evm.AddressFromKey(k.Key()))
+	return evm.NewClient(evmRPC), k.Key(), nil
+}
+
+// checkExpired fails once [expireAt] (see "--expire-at") passed; EVM
+// commands call it before each tx, as the P-Chain client does.
+func checkExpired(expireAt time.Time) error {
+	if !expireAt.IsZero() && time.Now().After(expireAt) {
+		return fmt.Errorf("%w at %s", client.ErrExpired, expireAt.Format(time.RFC3339))
+	}
+	return nil
+}
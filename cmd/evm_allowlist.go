@@ -0,0 +1,258 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/precompile"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrPrecompileInactive = errors.New("precompile not activated on the chain")
+	ErrNotAllowed         = errors.New("role not allowed by the allow list")
+	ErrNoAddress          = errors.New("--address required")
+)
+
+var (
+	precompileName   string
+	precompileFilter string
+	allowListAddrs   []string
+	allowListRole    string
+)
+
+func newEVMAllowListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allowlist",
+		Short: "Sub-commands for the allow lists of subnet-evm precompiles",
+		Long: `
+Reads and changes the allow lists of the subnet-evm admin precompiles of a
+deployed chain: "tx" (tx allow list), "deployer" (contract deployer allow
+list), "fee-manager", "minter" (native minter) and "reward-manager".
+
+Changing a role takes an EVM key with the admin role, or the manager role
+to enable and disable addresses that are not admins or managers.
+
+$ subnet-cli evm allowlist add \
+--evm-rpc=http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc \
+--evm-private-key-path=.insecure.ewoq.key \
+--precompile=tx \
+--address=0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC
+
+$ subnet-cli evm allowlist status --evm-rpc=... --address=0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC
+
+`,
+	}
+	cmd.AddCommand(
+		newEVMAllowListAddCommand(),
+		newEVMAllowListRemoveCommand(),
+		newEVMAllowListStatusCommand(),
+	)
+	cmd.PersistentFlags().StringSliceVar(&allowListAddrs, "address", nil, "EVM address (repeatable)")
+	return cmd
+}
+
+func newEVMAllowListAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Adds addresses to the allow list of a precompile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := precompile.ParseRole(allowListRole)
+			if err != nil {
+				return err
+			}
+			if role == precompile.None {
+				return fmt.Errorf("%w: use \"allowlist remove\" to remove addresses", precompile.ErrUnknownRole)
+			}
+			return setAllowListRole(cmd, role)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&precompileName, "precompile", precompile.TxAllowList.Name, "precompile (tx, deployer, fee-manager, minter or reward-manager)")
+	cmd.PersistentFlags().StringVar(&allowListRole, "role", precompile.Enabled.String(), "role to grant (enabled, manager or admin)")
+	return cmd
+}
+
+func newEVMAllowListRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Removes addresses from the allow list of a precompile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setAllowListRole(cmd, precompile.None)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&precompileName, "precompile", precompile.TxAllowList.Name, "precompile (tx, deployer, fee-manager, minter or reward-manager)")
+	return cmd
+}
+
+func newEVMAllowListStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the roles of addresses in the allow lists of the precompiles",
+		Long: `
+Shows whether each precompile is activated on the chain and the role of
+each "--address" (or of the EVM key) in its allow list.
+
+`,
+		Args: cobra.NoArgs,
+		RunE: evmAllowListStatusFunc,
+	}
+	cmd.PersistentFlags().StringVar(&precompileFilter, "precompile", "", "precompile to show (all if empty)")
+	return cmd
+}
+
+// parseEVMAddresses parses "--address".
+func parseEVMAddresses() ([]evm.Address, error) {
+	as := make([]evm.Address, 0, len(allowListAddrs))
+	for _, s := range allowListAddrs {
+		a, err := evm.ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
+
+// precompileRole returns the role of [a] in the allow list of [p], or
+// [ErrPrecompileInactive] if [p] is not activated on the chain.
+func precompileRole(ctx context.Context, cli *evm.Client, p precompile.Precompile, a evm.Address) (precompile.Role, error) {
+	code, err := cli.Code(ctx, p.Address)
+	if err != nil {
+		return precompile.None, err
+	}
+	if !precompile.Active(code) {
+		return precompile.None, fmt.Errorf("%w: %s (%s)", ErrPrecompileInactive, p.Description, p.Address)
+	}
+	ret, err := cli.CallContract(ctx, a, p.Address, precompile.ReadAllowList(a))
+	if err != nil {
+		return precompile.None, err
+	}
+	return precompile.DecodeRole(ret), nil
+}
+
+func setAllowListRole(cmd *cobra.Command, role precompile.Role) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	p, err := precompile.Lookup(precompileName)
+	if err != nil {
+		return err
+	}
+	as, err := parseEVMAddresses()
+	if err != nil {
+		return err
+	}
+	if len(as) == 0 {
+		return ErrNoAddress
+	}
+	expireAt, err := parseExpireAt(true)
+	if err != nil {
+		return err
+	}
+	cli, k, err := InitEVMClient()
+	if err != nil {
+		return err
+	}
+	from := evm.AddressFromKey(k)
+
+	caller, err := precompileRole(ctx, cli, p, from)
+	if err != nil {
+		return err
+	}
+	for _, a := range as {
+		current, err := precompileRole(ctx, cli, p, a)
+		if err != nil {
+			return err
+		}
+		if current == role {
+			color.Outf("{{yellow}}%s already has the %s role in the %s{{/}}\n", a, role, p.Description)
+			continue
+		}
+		// the precompile reverts without a reason, so check first
+		if !caller.CanSet(current, role) {
+			return fmt.Errorf("%w: %s (%s) cannot change %s from %s to %s in the %s", ErrNotAllowed, from, caller, a, current, role, p.Description)
+		}
+		if err := checkExpired(expireAt); err != nil {
+			return err
+		}
+		hash, _, err := cli.Transact(ctx, k, &evm.Tx{To: &p.Address, Data: precompile.SetRole(a, role)}, pollInterval)
+		if err != nil {
+			return err
+		}
+		color.Outf("{{magenta}}set the %s role of %s in the %s{{/}} {{light-gray}}(was %s, tx %s){{/}}\n", role, a, p.Description, current, evm.HashString(hash))
+	}
+	return nil
+}
+
+func evmAllowListStatusFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	ps := precompile.Precompiles
+	if precompileFilter != "" {
+		p, err := precompile.Lookup(precompileFilter)
+		if err != nil {
+			return err
+		}
+		ps = []precompile.Precompile{p}
+	}
+	as, err := parseEVMAddresses()
+	if err != nil {
+		return err
+	}
+	var cli *evm.Client
+	if len(as) == 0 {
+		c, k, err := InitEVMClient()
+		if err != nil {
+			return err
+		}
+		cli, as = c, []evm.Address{evm.AddressFromKey(k)}
+	} else {
+		if evmRPC == "" {
+			return ErrEmptyEVMRPC
+		}
+		cli = evm.NewClient(evmRPC)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	header := []string{"PRECOMPILE"}
+	for _, a := range as {
+		header = append(header, a.Hex())
+	}
+	tb.SetHeader(header)
+	for _, p := range ps {
+		row := []string{color.F("{{cyan}}{{bold}}%s{{/}} {{light-gray}}%s{{/}}", p.Name, p.Address)}
+		for _, a := range as {
+			role, err := precompileRole(ctx, cli, p, a)
+			switch {
+			case errors.Is(err, ErrPrecompileInactive):
+				row = append(row, color.F("{{light-gray}}not activated{{/}}"))
+			case err != nil:
+				return err
+			case role.CanUse():
+				row = append(row, color.F("{{green}}%s{{/}}", role))
+			default:
+				row = append(row, color.F("{{red}}%s{{/}}", role))
+			}
+		}
+		tb.Append(row)
+	}
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/precompile"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var ErrNoRecipient = errors.New("--to required")
+
+var (
+	mintTo     []string
+	mintAmount string
+)
+
+func newEVMMintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mint",
+		Short: "Mints native tokens with the native minter precompile",
+		Long: `
+Mints native tokens to each "--to" address through the native minter
+precompile of subnet-evm (e.g., to top up a testnet faucet or to pay out
+in-game rewards). The EVM key must be enabled in the allow list of the
+native minter.
+
+The amount is in native tokens (18 decimals) and is minted to every
+recipient.
+
+$ subnet-cli evm mint \
+--evm-rpc=http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc \
+--evm-private-key-path=.insecure.ewoq.key \
+--to=0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC \
+--amount=1000.5
+
+`,
+		Args: cobra.NoArgs,
+		RunE: evmMintFunc,
+	}
+	cmd.PersistentFlags().StringSliceVar(&mintTo, "to", nil, "recipient EVM address (repeatable)")
+	cmd.PersistentFlags().StringVar(&mintAmount, "amount", "", "native tokens to mint to each recipient (e.g., 1000.5)")
+	return cmd
+}
+
+func evmMintFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	amount, err := evm.ParseEther(mintAmount)
+	if err != nil {
+		return err
+	}
+	if amount.Sign() == 0 {
+		return fmt.Errorf("%w: zero --amount", evm.ErrInvalidAmount)
+	}
+	if len(mintTo) == 0 {
+		return ErrNoRecipient
+	}
+	recipients := make([]evm.Address, 0, len(mintTo))
+	for _, s := range mintTo {
+		a, err := evm.ParseAddress(s)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, a)
+	}
+	expireAt, err := parseExpireAt(true)
+	if err != nil {
+		return err
+	}
+	cli, k, err := InitEVMClient()
+	if err != nil {
+		return err
+	}
+	from := evm.AddressFromKey(k)
+
+	// the precompile reverts without a reason, so check first
+	role, err := precompileRole(ctx, cli, precompile.NativeMinter, from)
+	if err != nil {
+		return err
+	}
+	if !role.CanUse() {
+		return fmt.Errorf("%w: %s (%s) cannot mint with the %s", ErrNotAllowed, from, role, precompile.NativeMinter.Description)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	tb.SetHeader([]string{"RECIPIENT", "BALANCE", "MINT"})
+	for _, a := range recipients {
+		balance, err := cli.Balance(ctx, a)
+		if err != nil {
+			return err
+		}
+		tb.Append([]string{
+			color.F("{{cyan}}{{bold}}%s{{/}}", a),
+			color.F("{{light-gray}}%s{{/}}", evm.FormatEther(balance)),
+			color.F("{{green}}+%s{{/}}", evm.FormatEther(amount)),
+		})
+	}
+	tb.Render()
+	color.Print(buf.String())
+	total := new(big.Int).Mul(amount, big.NewInt(int64(len(recipients))))
+	color.Outf("{{magenta}}minting %s native tokens in total{{/}}\n", evm.FormatEther(total))
+
+	if dryRun {
+		color.Outf("{{yellow}}dry run, no tx issued{{/}}\n")
+		return nil
+	}
+	if enablePrompt {
+		prompt := promptui.Select{
+			Label:  color.F("\n{{blue}}{{bold}}Mint to these recipients?{{/}}"),
+			Stdout: os.Stdout,
+			Items: []string{
+				color.F("{{green}}Yes, mint!{{/}}"),
+				color.F("{{red}}No, stop it!{{/}}"),
+			},
+		}
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		if idx == 1 {
+			return nil
+		}
+	}
+
+	minter := precompile.NativeMinter.Address
+	for _, a := range recipients {
+		if err := checkExpired(expireAt); err != nil {
+			return err
+		}
+		hash, _, err := cli.Transact(ctx, k, &evm.Tx{To: &minter, Data: precompile.MintNativeCoin(a, amount)}, pollInterval)
+		if err != nil {
+			return err
+		}
+		color.Outf("{{magenta}}minted %s native tokens to %s{{/}} {{light-gray}}(tx %s){{/}}\n", evm.FormatEther(amount), a, evm.HashString(hash))
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/precompile"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var ErrBlackholeRewardAddress = errors.New("reward address is the blackhole address")
+
+var rewardAddress string
+
+func newEVMRewardsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rewards",
+		Short: "Sub-commands for the fee rewards of the reward manager precompile",
+		Long: `
+Configures where the reward manager precompile of subnet-evm sends the fees
+of each block: burned (the default), to the fee recipient each block
+producer sets, or to a fixed reward address. Changing it takes an EVM key
+enabled in the allow list of the reward manager.
+
+$ subnet-cli evm rewards set-address \
+--evm-rpc=http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc \
+--evm-private-key-path=.insecure.ewoq.key \
+--address=0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC
+
+$ subnet-cli evm rewards allow-fee-recipients --evm-rpc=...
+$ subnet-cli evm rewards burn --evm-rpc=...
+$ subnet-cli evm rewards status --evm-rpc=...
+
+`,
+	}
+	cmd.AddCommand(
+		newEVMRewardsSetAddressCommand(),
+		newEVMRewardsAllowFeeRecipientsCommand(),
+		newEVMRewardsBurnCommand(),
+		newEVMRewardsStatusCommand(),
+	)
+	return cmd
+}
+
+func newEVMRewardsSetAddressCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-address",
+		Short: "Sends the fees to a fixed reward address",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rewardAddress == "" {
+				return ErrNoAddress
+			}
+			a, err := evm.ParseAddress(rewardAddress)
+			if err != nil {
+				return err
+			}
+			if a == precompile.BlackholeAddress {
+				return fmt.Errorf("%w: use \"rewards burn\" to burn the fees", ErrBlackholeRewardAddress)
+			}
+			return setRewards(cmd, precompile.Rewards{Mode: precompile.RewardAddress, Address: a})
+		},
+	}
+	cmd.PersistentFlags().StringVar(&rewardAddress, "address", "", "EVM address to send the fees to")
+	return cmd
+}
+
+func newEVMRewardsAllowFeeRecipientsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "allow-fee-recipients",
+		Short: "Sends the fees to the fee recipients of the block producers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setRewards(cmd, precompile.Rewards{Mode: precompile.FeeRecipients})
+		},
+	}
+}
+
+func newEVMRewardsBurnCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "burn",
+		Short: "Burns the fees",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setRewards(cmd, precompile.Rewards{Mode: precompile.Burn, Address: precompile.BlackholeAddress})
+		},
+	}
+}
+
+func newEVMRewardsStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Shows where the fees go",
+		Args:  cobra.NoArgs,
+		RunE:  evmRewardsStatusFunc,
+	}
+}
+
+// readRewards returns the reward configuration of the chain, or
+// [ErrPrecompileInactive] if the reward manager is not activated.
+func readRewards(ctx context.Context, cli *evm.Client) (precompile.Rewards, error) {
+	p := precompile.RewardManager
+	code, err := cli.Code(ctx, p.Address)
+	if err != nil {
+		return precompile.Rewards{}, err
+	}
+	if !precompile.Active(code) {
+		return precompile.Rewards{}, fmt.Errorf("%w: %s (%s)", ErrPrecompileInactive, p.Description, p.Address)
+	}
+	allowed, err := cli.CallContract(ctx, evm.Address{}, p.Address, precompile.AreFeeRecipientsAllowed())
+	if err != nil {
+		return precompile.Rewards{}, err
+	}
+	current, err := cli.CallContract(ctx, evm.Address{}, p.Address, precompile.CurrentRewardAddress())
+	if err != nil {
+		return precompile.Rewards{}, err
+	}
+	return precompile.DecodeRewards(allowed, current), nil
+}
+
+func describeRewards(r precompile.Rewards) string {
+	switch r.Mode {
+	case precompile.Burn:
+		return color.F("{{red}}burned{{/}} {{light-gray}}(sent to %s){{/}}", r.Address)
+	case precompile.FeeRecipients:
+		return color.F("{{green}}sent to the fee recipients of the block producers{{/}}")
+	}
+	return color.F("{{green}}sent to %s{{/}}", r.Address)
+}
+
+func setRewards(cmd *cobra.Command, target precompile.Rewards) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	expireAt, err := parseExpireAt(true)
+	if err != nil {
+		return err
+	}
+	cli, k, err := InitEVMClient()
+	if err != nil {
+		return err
+	}
+	from := evm.AddressFromKey(k)
+	p := precompile.RewardManager
+
+	current, err := readRewards(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if current == target {
+		color.Outf("{{yellow}}fees are already %s{{/}}\n", describeRewards(current))
+		return nil
+	}
+	// the precompile reverts without a reason, so check first
+	role, err := precompileRole(ctx, cli, p, from)
+	if err != nil {
+		return err
+	}
+	if !role.CanUse() {
+		return fmt.Errorf("%w: %s (%s) cannot configure the %s", ErrNotAllowed, from, role, p.Description)
+	}
+
+	var data []byte
+	switch target.Mode {
+	case precompile.Burn:
+		data = precompile.DisableRewards()
+	case precompile.FeeRecipients:
+		data = precompile.AllowFeeRecipients()
+	default:
+		data = precompile.SetRewardAddress(target.Address)
+	}
+	color.Outf("{{magenta}}fees will be %s{{/}} {{light-gray}}(were %s){{/}}\n", describeRewards(target), describeRewards(current))
+	if dryRun {
+		color.Outf("{{yellow}}dry run, no tx issued{{/}}\n")
+		return nil
+	}
+	if err := checkExpired(expireAt); err != nil {
+		return err
+	}
+	hash, _, err := cli.Transact(ctx, k, &evm.Tx{To: &p.Address, Data: data}, pollInterval)
+	if err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}configured the %s{{/}} {{light-gray}}(tx %s){{/}}\n", p.Description, evm.HashString(hash))
+	return nil
+}
+
+func evmRewardsStatusFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if evmRPC == "" {
+		return ErrEmptyEVMRPC
+	}
+	cli := evm.NewClient(evmRPC)
+
+	r, err := readRewards(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	tb.Append([]string{color.F("{{cyan}}{{bold}}REWARD MANAGER{{/}}"), color.F("{{light-gray}}%s{{/}}", precompile.RewardManager.Address)})
+	tb.Append([]string{color.F("{{cyan}}{{bold}}MODE{{/}}"), r.Mode.String()})
+	tb.Append([]string{color.F("{{cyan}}{{bold}}FEES{{/}}"), describeRewards(r)})
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
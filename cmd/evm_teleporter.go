@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	teleporterTxPath       string
+	teleporterDeployer     string
+	teleporterAddress      string
+	teleporterRegistryPath string
+	teleporterFunding      uint64
+)
+
+func newEVMTeleporterCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teleporter",
+		Short: "Deploys the Teleporter messenger and registry contracts",
+		Long: `
+Deploys the Teleporter messenger (via its pre-signed, chain-agnostic
+deployment transaction) and optionally the Teleporter registry to a
+subnet-evm chain, wiring it for cross-subnet messaging.
+
+The deployment transaction, deployer address, and contract address are
+published with every Teleporter release.
+
+$ subnet-cli evm teleporter \
+--evm-rpc=http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc \
+--evm-private-key-path=.insecure.ewoq.key \
+--messenger-deployment-tx=TeleporterMessenger_Deployment_Transaction_v1.0.0.txt \
+--messenger-deployer-address=0x618FEdD9A45a8C456812ecAAE70C671c6249DfaC \
+--messenger-contract-address=0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf \
+--registry-bytecode=TeleporterRegistry_Bytecode_v1.0.0.txt
+
+`,
+		RunE: evmTeleporterFunc,
+	}
+	cmd.PersistentFlags().StringVar(&teleporterTxPath, "messenger-deployment-tx", "", "file with the hex-encoded pre-signed messenger deployment tx")
+	cmd.PersistentFlags().StringVar(&teleporterDeployer, "messenger-deployer-address", "", "address that signed the messenger deployment tx")
+	cmd.PersistentFlags().StringVar(&teleporterAddress, "messenger-contract-address", "", "expected messenger contract address")
+	cmd.PersistentFlags().StringVar(&teleporterRegistryPath, "registry-bytecode", "", "file with the hex-encoded registry bytecode (empty to skip the registry)")
+	cmd.PersistentFlags().Uint64Var(&teleporterFunding, "messenger-deployer-funding", 10, "native tokens to send to the messenger deployer")
+	return cmd
+}
+
+func readHexFile(p string) ([]byte, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+}
+
+func evmTeleporterFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, k, err := InitEVMClient()
+	if err != nil {
+		return err
+	}
+	deployer, err := evm.ParseAddress(teleporterDeployer)
+	if err != nil {
+		return err
+	}
+	messenger, err := evm.ParseAddress(teleporterAddress)
+	if err != nil {
+		return err
+	}
+	deploymentTx, err := readHexFile(teleporterTxPath)
+	if err != nil {
+		return err
+	}
+
+	code, err := cli.Code(ctx, messenger)
+	if err != nil {
+		return err
+	}
+	if len(code) > 0 {
+		color.Outf("{{yellow}}Teleporter messenger already deployed at %s{{/}}\n", messenger)
+	} else {
+		funding := new(big.Int).Mul(new(big.Int).SetUint64(teleporterFunding), evm.Ether)
+		balance, err := cli.Balance(ctx, deployer)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(funding) < 0 {
+			need := new(big.Int).Sub(funding, balance)
+			color.Outf("{{blue}}funding messenger deployer %s with %s{{/}}\n", deployer, evm.FormatEther(need))
+			hash, _, err := cli.Transact(ctx, k, &evm.Tx{To: &deployer, Value: need, Gas: 21000}, pollInterval)
+			if err != nil {
+				return err
+			}
+			color.Outf("{{magenta}}funded deployer{{/}} {{light-gray}}(tx %s){{/}}\n", evm.HashString(hash))
+		}
+		hash, err := cli.SendRawTransaction(ctx, deploymentTx)
+		if err != nil {
+			return err
+		}
+		if _, err := cli.WaitReceipt(ctx, hash, pollInterval); err != nil {
+			return err
+		}
+		code, err = cli.Code(ctx, messenger)
+		if err != nil {
+			return err
+		}
+		if len(code) == 0 {
+			color.Outf("{{red}}no code at %s after deployment; check --messenger-contract-address{{/}}\n", messenger)
+			return evm.ErrTxFailed
+		}
+		color.Outf("{{magenta}}deployed Teleporter messenger at %s{{/}} {{light-gray}}(tx %s){{/}}\n", messenger, evm.HashString(hash))
+	}
+
+	if teleporterRegistryPath == "" {
+		return nil
+	}
+	bytecode, err := readHexFile(teleporterRegistryPath)
+	if err != nil {
+		return err
+	}
+	// constructor(ProtocolRegistryEntry[] initialEntries) with a single
+	// (version 1, messenger) entry
+	data := bytecode
+	for _, w := range [][]byte{
+		evm.WordUint(big.NewInt(32)), // offset of the array
+		evm.WordUint(big.NewInt(1)),  // array length
+		evm.WordUint(big.NewInt(1)),  // version
+		evm.WordAddress(messenger),   // protocol address
+	} {
+		data = append(data, w...)
+	}
+	hash, r, err := cli.Transact(ctx, k, &evm.Tx{Data: data}, pollInterval)
+	if err != nil {
+		return err
+	}
+	registry := "(unknown)"
+	if r.ContractAddress != nil {
+		registry = *r.ContractAddress
+	}
+	color.Outf("{{magenta}}deployed Teleporter registry at %s{{/}} {{light-gray}}(tx %s){{/}}\n", registry, evm.HashString(hash))
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ExportCommand implements "subnet-cli export" command.
+func ExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Sub-commands for exporting chain state",
+	}
+	cmd.AddCommand(
+		newExportValidatorsCommand(),
+		newExportAvalancheCLICommand(),
+		newExportGrafanaCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "'true' to bypass the local cache of validator/subnet lookups")
+	return cmd
+}
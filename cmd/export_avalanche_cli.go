@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/avacli"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var exportOutputDir string
+
+func newExportAvalancheCLICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "avalanche-cli",
+		Short: "Exports a deployed blockchain as an avalanche-cli subnet configuration",
+		Long: `
+Writes the sidecar.json and genesis.json of an avalanche-cli subnet
+configuration for a deployed blockchain, so avalanche-cli can manage it.
+The configuration is named after the chain. If it already exists (e.g.,
+exported from another network), the deployment on this network is added.
+
+$ subnet-cli export avalanche-cli \
+--public-uri=https://api.avax-test.network \
+--blockchain-id=2tig763Fw6aEczGgdZTkwNJEvr4TXHXZV5yQE5V2BcDB1Jy2Ut \
+--output-dir=$HOME/.avalanche-cli/subnets
+
+`,
+		RunE: exportAvalancheCLIFunc,
+	}
+	cmd.PersistentFlags().StringVar(&blockchainID, "blockchain-id", "", "blockchain to export")
+	cmd.PersistentFlags().StringVar(&exportOutputDir, "output-dir", ".", "directory to write the subnet configuration directory to")
+	return cmd
+}
+
+func exportAvalancheCLIFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitReadClient(ctx, publicURI)
+	if err != nil {
+		return err
+	}
+	bchID, err := ids.FromString(blockchainID)
+	if err != nil {
+		return err
+	}
+	bc, err := cli.P().GetBlockchain(ctx, bchID)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(exportOutputDir, bc.Name)
+	sc, err := avacli.Load(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		sc = &avacli.Sidecar{Name: bc.Name, Subnet: bc.Name}
+	case err != nil:
+		return err
+	}
+	sc.VM = avacli.VMKind(bc.Genesis)
+	sc.SetVMID(bc.VMID)
+	if err := sc.SetDeployment(info.networkName, avacli.NetworkData{
+		SubnetID:     bc.SubnetID.String(),
+		BlockchainID: bc.ID.String(),
+	}); err != nil {
+		return err
+	}
+	if err := sc.Save(dir, bc.Genesis); err != nil {
+		return err
+	}
+	color.Outf("{{green}}exported %s blockchain %q (%s) to %q{{/}}\n", sc.VM, bc.Name, bc.ID, dir)
+	color.Result(dir)
+	return nil
+}
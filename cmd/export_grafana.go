@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/grafana"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	grafanaDashboardOutput string
+	grafanaRulesOutput     string
+	grafanaNodeLabel       string
+	grafanaMinUptime       float64
+)
+
+func newExportGrafanaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grafana",
+		Short: "Exports a Grafana dashboard and Prometheus alert rules of a subnet",
+		Long: `
+Writes a Grafana dashboard (ready to import, it asks for the Prometheus data
+source) and Prometheus alert rules templated with the blockchain IDs and the
+validator node IDs of the subnet: validators down, without peers or with a
+low uptime, and stalled or lagging chains.
+
+The rules match the avalanchego metrics ("/ext/metrics") of each validator
+by the "--node-label" label, which the Prometheus scrape config must set to
+the node ID of each target, e.g.:
+
+  - job_name: avalanchego
+    metrics_path: /ext/metrics
+    static_configs:
+      - targets: ["10.0.0.1:9650"]
+        labels: {node_id: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"}
+
+$ subnet-cli export grafana \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--dashboard-output=dashboard.json \
+--rules-output=alerts.yaml
+
+`,
+		Args: cobra.NoArgs,
+		RunE: exportGrafanaFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&grafanaDashboardOutput, "dashboard-output", "grafana-dashboard.json", "file to write the Grafana dashboard to")
+	cmd.PersistentFlags().StringVar(&grafanaRulesOutput, "rules-output", "prometheus-alerts.yaml", "file to write the Prometheus alert rules to")
+	cmd.PersistentFlags().StringVar(&grafanaNodeLabel, "node-label", grafana.DefaultNodeLabel, "Prometheus label holding the node ID of each scrape target")
+	cmd.PersistentFlags().Float64Var(&grafanaMinUptime, "min-uptime", 85, "weighted average uptime percentage to alert below")
+	return cmd
+}
+
+func exportGrafanaFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitReadClient(ctx, publicURI)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	vs, err := cli.P().GetValidators(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	bcs, err := cli.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := grafana.Target{
+		Network:   info.networkName,
+		SubnetID:  info.subnetID,
+		NodeLabel: grafanaNodeLabel,
+		MinUptime: grafanaMinUptime,
+	}
+	// pending validators are included so the rules cover them once they
+	// start validating
+	for _, v := range vs {
+		t.NodeIDs = append(t.NodeIDs, v.NodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	sort.Strings(t.NodeIDs)
+	for _, bc := range bcs {
+		if bc.SubnetID == info.subnetID {
+			t.Chains = append(t.Chains, grafana.Chain{ID: bc.ID, Name: bc.Name})
+		}
+	}
+	if len(t.NodeIDs) == 0 {
+		color.Outf("{{yellow}}subnet %s has no validators; the rules match no node{{/}}\n", info.subnetID)
+	}
+
+	b, err := grafana.Dashboard(t)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(grafanaDashboardOutput, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	b, err = grafana.AlertRules(t)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(grafanaRulesOutput, b, 0o644); err != nil {
+		return err
+	}
+	color.Outf("{{green}}exported the dashboard of %d validator(s) and %d blockchain(s) to %q and the alert rules to %q{{/}}\n", len(t.NodeIDs), len(t.Chains), grafanaDashboardOutput, grafanaRulesOutput)
+	color.Result(grafanaDashboardOutput)
+	color.Result(grafanaRulesOutput)
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/price"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+
+	ErrUnknownExportFormat = errors.New("unknown export format")
+)
+
+func newExportValidatorsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validators",
+		Short: "Exports the current and pending validators of a subnet",
+		Long: `
+Dumps the full current and pending validator set (weights, validation
+period, uptime, connectivity and reward owners) as CSV or JSON, for
+reporting and compliance snapshots. Exports the primary network if
+--subnet-id is empty. Uptimes are as observed by the queried node.
+
+$ subnet-cli export validators \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--format=csv \
+--output=validators.csv
+
+`,
+		RunE: exportValidatorsFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID); empty for the primary network")
+	cmd.PersistentFlags().StringVar(&exportFormat, "format", exportFormatCSV, "output format (csv, json)")
+	cmd.PersistentFlags().StringVarP(&exportOutput, "output", "o", "", "output file path; empty for stdout")
+	return cmd
+}
+
+func exportValidatorsFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if exportFormat != exportFormatCSV && exportFormat != exportFormatJSON {
+		return fmt.Errorf("%w: %q", ErrUnknownExportFormat, exportFormat)
+	}
+	cli, info, err := InitReadClient(ctx, publicURI)
+	if err != nil {
+		return err
+	}
+	subnetID := constants.PrimaryNetworkID
+	if subnetIDs != "" {
+		if subnetID, err = ids.FromString(subnetIDs); err != nil {
+			return err
+		}
+	}
+
+	vs, err := cli.P().GetValidators(ctx, subnetID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(vs, func(i, j int) bool {
+		if vs[i].Pending != vs[j].Pending {
+			return !vs[i].Pending
+		}
+		return vs[i].NodeID.String() < vs[j].NodeID.String()
+	})
+
+	w := io.Writer(os.Stdout)
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	snapshot := validatorSnapshot{
+		Network:    info.networkName,
+		SubnetID:   subnetID.String(),
+		Time:       time.Now().UTC().Format(time.RFC3339),
+		Validators: make([]validatorRecord, len(vs)),
+	}
+	for i, v := range vs {
+		snapshot.Validators[i] = newValidatorRecord(v)
+		// the weight of primary network validators is their stake
+		if info.quote != nil && subnetID == constants.PrimaryNetworkID {
+			f := info.quote.Value(v.Weight)
+			snapshot.Validators[i].WeightFiat = &f
+		}
+	}
+	snapshot.Price = info.quote
+	if exportFormat == exportFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(snapshot)
+	} else {
+		err = writeValidatorsCSV(w, snapshot.Validators)
+	}
+	if err != nil {
+		return err
+	}
+	if exportOutput != "" {
+		color.Outf("{{green}}exported %d validator(s) of %s to %q{{/}}\n", len(vs), subnetID, exportOutput)
+	}
+	return nil
+}
+
+type validatorSnapshot struct {
+	Network  string `json:"network"`
+	SubnetID string `json:"subnetID"`
+	Time     string `json:"time"`
+	// Price is the AVAX price of "--price-source" the fiat values are
+	// computed with.
+	Price      *price.Quote      `json:"price,omitempty"`
+	Validators []validatorRecord `json:"validators"`
+}
+
+type validatorRecord struct {
+	NodeID               string   `json:"nodeID"`
+	Status               string   `json:"status"`
+	TxID                 string   `json:"txID,omitempty"`
+	StartTime            string   `json:"startTime"`
+	EndTime              string   `json:"endTime"`
+	Weight               uint64   `json:"weight"`
+	WeightFiat           *float64 `json:"weightFiat,omitempty"`
+	Uptime               *float64 `json:"uptime,omitempty"`
+	Connected            *bool    `json:"connected,omitempty"`
+	RewardOwnerAddresses []string `json:"rewardOwnerAddresses,omitempty"`
+	RewardOwnerThreshold uint32   `json:"rewardOwnerThreshold,omitempty"`
+	DelegationFee        string   `json:"delegationFee,omitempty"`
+	PotentialReward      uint64   `json:"potentialReward,omitempty"`
+}
+
+func newValidatorRecord(v client.Validator) validatorRecord {
+	r := validatorRecord{
+		NodeID:               v.NodeID.PrefixedString(constants.NodeIDPrefix),
+		Status:               "current",
+		StartTime:            v.Start.UTC().Format(time.RFC3339),
+		EndTime:              v.End.UTC().Format(time.RFC3339),
+		Weight:               v.Weight,
+		Uptime:               v.Uptime,
+		Connected:            v.Connected,
+		RewardOwnerAddresses: v.RewardOwnerAddrs,
+		RewardOwnerThreshold: v.RewardOwnerThreshold,
+		DelegationFee:        v.DelegationFee,
+		PotentialReward:      v.PotentialReward,
+	}
+	if v.Pending {
+		r.Status = "pending"
+	}
+	if v.TxID != ids.Empty {
+		r.TxID = v.TxID.String()
+	}
+	return r
+}
+
+func writeValidatorsCSV(w io.Writer, rs []validatorRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"node_id", "status", "tx_id", "start_time", "end_time", "weight", "uptime", "connected",
+		"reward_owner_addresses", "reward_owner_threshold", "delegation_fee", "potential_reward",
+	}); err != nil {
+		return err
+	}
+	for _, r := range rs {
+		uptime, connected := "", ""
+		if r.Uptime != nil {
+			uptime = strconv.FormatFloat(*r.Uptime, 'f', -1, 64)
+		}
+		if r.Connected != nil {
+			connected = strconv.FormatBool(*r.Connected)
+		}
+		threshold := ""
+		if len(r.RewardOwnerAddresses) > 0 {
+			threshold = strconv.FormatUint(uint64(r.RewardOwnerThreshold), 10)
+		}
+		if err := cw.Write([]string{
+			r.NodeID,
+			r.Status,
+			r.TxID,
+			r.StartTime,
+			r.EndTime,
+			strconv.FormatUint(r.Weight, 10),
+			uptime,
+			connected,
+			strings.Join(r.RewardOwnerAddresses, " "),
+			threshold,
+			r.DelegationFee,
+			strconv.FormatUint(r.PotentialReward, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
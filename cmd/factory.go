@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+// Factory creates the client and the signing key of a command. Commands
+// take it from their context (see [WithFactory]), so tests and programs
+// embedding the commands (e.g., a daemon or a TUI) can inject a fake client
+// or a key of their own instead of connecting to a node and reading the key
+// flags.
+type Factory interface {
+	// NewClient connects to the node of [cfg] (see [client.New]).
+	NewClient(ctx context.Context, cfg client.Config) (client.Client, error)
+	// LoadKey loads the signing key for [networkID].
+	LoadKey(ctx context.Context, networkID uint32) (key.Key, error)
+}
+
+var _ Factory = DefaultFactory{}
+
+// DefaultFactory connects to the node with [client.New] and loads the key
+// selected by the signer flags (e.g., "--private-key-path").
+type DefaultFactory struct{}
+
+func (DefaultFactory) NewClient(ctx context.Context, cfg client.Config) (client.Client, error) {
+	return client.New(ctx, cfg)
+}
+
+func (DefaultFactory) LoadKey(ctx context.Context, networkID uint32) (key.Key, error) {
+	return loadSignerKey(ctx, networkID)
+}
+
+type factoryKey struct{}
+
+// WithFactory returns a copy of [ctx] whose commands use [f], e.g.:
+//
+//	root := cmd.NewRootCommand()
+//	root.SetArgs([]string{"create", "subnet"})
+//	err := root.ExecuteContext(cmd.WithFactory(ctx, f))
+func WithFactory(ctx context.Context, f Factory) context.Context {
+	return context.WithValue(ctx, factoryKey{}, f)
+}
+
+// factoryFrom returns the factory of [ctx], or [DefaultFactory].
+func factoryFrom(ctx context.Context) Factory {
+	if f, ok := ctx.Value(factoryKey{}).(Factory); ok {
+		return f
+	}
+	return DefaultFactory{}
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/faucet"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	faucetURL     string
+	faucetChain   string
+	faucetToken   string
+	faucetAPIKey  string
+	faucetTimeout time.Duration
+
+	ErrFaucetMainnet = errors.New("faucet is not available on mainnet")
+)
+
+// FaucetCommand implements "subnet-cli faucet" command.
+func FaucetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "faucet",
+		Short: "Sub-commands for funding keys on test networks",
+	}
+	cmd.AddCommand(
+		newFaucetRequestCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	addSignerFlags(cmd)
+	return cmd
+}
+
+func newFaucetRequestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "request",
+		Short: "Requests test tokens for the loaded key",
+		Long: `
+Requests test tokens from the faucet for the loaded key and waits until
+the balance appears.
+
+$ subnet-cli faucet request \
+--private-key-path=.insecure.test.key \
+--chain=P \
+--captcha-token=[CAPTCHA RESPONSE TOKEN]
+
+`,
+		RunE: faucetRequestFunc,
+	}
+	cmd.PersistentFlags().StringVar(&faucetURL, "faucet-url", faucet.DefaultURL, "faucet 'sendToken' endpoint")
+	cmd.PersistentFlags().StringVar(&faucetChain, "chain", "P", "chain to fund (P or C)")
+	cmd.PersistentFlags().StringVar(&faucetToken, "captcha-token", "", "captcha response token")
+	cmd.PersistentFlags().StringVar(&faucetAPIKey, "faucet-api-key", "", "faucet API key (sent as a bearer token)")
+	cmd.PersistentFlags().DurationVar(&faucetTimeout, "wait-timeout", 5*time.Minute, "how long to wait for the balance to appear")
+	return cmd
+}
+
+func faucetRequestFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	if info.networkID == constants.MainnetID {
+		return ErrFaucetMainnet
+	}
+
+	var (
+		addr    string
+		balance func(ctx context.Context) (*big.Int, error)
+	)
+	switch strings.ToUpper(faucetChain) {
+	case "P":
+		addr = info.key.P()[0]
+		balance = func(ctx context.Context) (*big.Int, error) {
+			b, err := cli.P().Balance(ctx, info.key)
+			return new(big.Int).SetUint64(b), err
+		}
+	case "C":
+		sk, ok := info.key.(*key.SoftKey)
+		if !ok {
+			return fmt.Errorf("%w: C-Chain faucet requires a soft key", key.ErrInvalidType)
+		}
+		a := evm.AddressFromKey(sk.Key())
+		addr = a.Hex()
+		ecli := cli.EVM("C")
+		balance = func(ctx context.Context) (*big.Int, error) {
+			return ecli.Balance(ctx, a)
+		}
+	default:
+		return fmt.Errorf("unknown chain %q (expected P or C)", faucetChain)
+	}
+
+	before, err := balance(ctx)
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{blue}}requesting tokens for %s from %s{{/}}\n", addr, faucetURL)
+	resp, err := faucet.Send(ctx, faucetURL, faucetAPIKey, faucet.Request{
+		Address: addr,
+		Chain:   strings.ToUpper(faucetChain),
+		Token:   faucetToken,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.TxHash != "" {
+		color.Outf("{{magenta}}faucet issued tx %s{{/}}\n", resp.TxHash)
+	}
+
+	color.Outf("{{yellow}}waiting for the balance of %s to increase...{{/}}\n", addr)
+	ctx, cancel = context.WithTimeout(ctx, faucetTimeout)
+	defer cancel()
+	for {
+		after, err := balance(ctx)
+		if err == nil && after.Cmp(before) > 0 {
+			color.Outf("{{green}}received funds on %s-Chain (balance %s → %s){{/}}\n", strings.ToUpper(faucetChain), before, after)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
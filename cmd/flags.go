@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/ava-labs/subnet-cli/internal/amount"
+)
+
+// amountFlag is a "pflag.Value" that accepts human-readable AVAX amounts
+// (e.g., "2000avax", "1.5AVAX", "25000000000nAVAX") and stores nano-AVAX.
+type amountFlag uint64
+
+func (a *amountFlag) String() string { return amount.Format(uint64(*a)) }
+func (a *amountFlag) Type() string   { return "amount" }
+
+func (a *amountFlag) Set(s string) error {
+	v, err := amount.Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amountFlag(v)
+	return nil
+}
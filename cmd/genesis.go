@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/genesis"
+)
+
+var ErrNoGenesisPlugin = errors.New("no genesis plugin")
+
+var (
+	genesisVM         string
+	genesisPluginAddr string
+	genesisParams     []string
+	genesisParamsFile string
+)
+
+// GenesisCommand implements "subnet-cli genesis" command.
+func GenesisCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "genesis",
+		Short: "Sub-commands for VM genesis",
+		Long: `
+Builds and checks VM genesis files with the VM's own tooling. The genesis
+of "--vm <name>" is delegated to the gRPC plugin "subnet-cli-genesis-<name>"
+on PATH (see pkg/genesis/genesis.proto), or to a plugin already serving on
+"--plugin-addr".
+
+$ subnet-cli genesis generate --vm spacesvm --param magic=1 --vm-genesis-path=spacesvm.genesis
+
+`,
+	}
+	cmd.PersistentFlags().StringVar(&genesisVM, "vm", "", "VM name (resolves the plugin subnet-cli-genesis-<vm> on PATH)")
+	cmd.PersistentFlags().StringVar(&genesisPluginAddr, "plugin-addr", "", "address of a running genesis plugin (overrides --vm)")
+	cmd.PersistentFlags().StringVar(&vmGenesisPath, "vm-genesis-path", "", "VM genesis file path")
+	cmd.AddCommand(
+		newGenesisGenerateCommand(),
+		newGenesisValidateCommand(),
+		newGenesisFeeConfigCommand(),
+	)
+	return cmd
+}
+
+// genesisPlugin connects to the genesis plugin selected by the flags. The
+// returned function stops it.
+func genesisPlugin(ctx context.Context) (genesis.Generator, func() error, error) {
+	if genesisPluginAddr != "" {
+		return genesis.Dial(ctx, genesisPluginAddr)
+	}
+	if genesisVM == "" {
+		return nil, nil, fmt.Errorf("%w: --vm or --plugin-addr required", ErrNoGenesisPlugin)
+	}
+	p, err := exec.LookPath(genesis.PluginPrefix + genesisVM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s%s not on PATH", ErrNoGenesisPlugin, genesis.PluginPrefix, genesisVM)
+	}
+	return genesis.Launch(ctx, p)
+}
+
+// loadGenesisParams merges "--params-file" (a JSON object) and "--param"
+// (key=value, where values are parsed as JSON if possible and kept as
+// strings otherwise).
+func loadGenesisParams() (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if genesisParamsFile != "" {
+		b, err := ioutil.ReadFile(genesisParamsFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &params); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, genesisParamsFile)
+		}
+	}
+	for _, kv := range genesisParams {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 || kvs[0] == "" {
+			return nil, fmt.Errorf("invalid --param %q (expected key=value)", kv)
+		}
+		k, v := kvs[0], kvs[1]
+		var value interface{}
+		if err := json.Unmarshal([]byte(v), &value); err != nil {
+			value = v
+		}
+		params[k] = value
+	}
+	return params, nil
+}
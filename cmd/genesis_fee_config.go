@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/feeconfig"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	feePreset          string
+	feeGasLimit        uint64
+	feeTargetGas       uint64
+	feeMinBaseFee      uint64
+	feeTargetBlockRate uint64
+)
+
+func newGenesisFeeConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-config",
+		Short: "Fills in the fee config of a subnet-evm genesis from a preset",
+		Long: `
+Fills in the fee config (gas limit, target gas, base fee, block rate) of a
+subnet-evm genesis from a preset, explains each field and warns about
+settings that stall or price out the chain under load:
+
+  c-chain  C-Chain-like defaults
+  gaming   low latency: fast blocks, low and slowly moving fees
+  defi     high throughput: large blocks, fees rising quickly under congestion
+
+Without "--preset", asks for one (or uses "c-chain" if prompts are
+disabled). "--gas-limit", "--target-gas", "--min-base-fee" (in wei) and
+"--target-block-rate" (in seconds) override the preset. The fee config is
+written into "--vm-genesis-path" (the other fields are kept), or printed if
+empty.
+
+$ subnet-cli genesis fee-config --preset=gaming --vm-genesis-path=subnet-evm.genesis
+
+`,
+		Args: cobra.NoArgs,
+		RunE: genesisFeeConfigFunc,
+	}
+	cmd.PersistentFlags().StringVar(&feePreset, "preset", "", "fee preset (c-chain, gaming or defi)")
+	cmd.PersistentFlags().Uint64Var(&feeGasLimit, "gas-limit", 0, "block gas limit (overrides the preset)")
+	cmd.PersistentFlags().Uint64Var(&feeTargetGas, "target-gas", 0, "gas per 10s the base fee aims for (overrides the preset)")
+	cmd.PersistentFlags().Uint64Var(&feeMinBaseFee, "min-base-fee", 0, "minimum base fee in wei (overrides the preset)")
+	cmd.PersistentFlags().Uint64Var(&feeTargetBlockRate, "target-block-rate", 0, "seconds between blocks (overrides the preset)")
+	return cmd
+}
+
+func genesisFeeConfigFunc(cmd *cobra.Command, args []string) error {
+	name := feePreset
+	if name == "" {
+		name = feeconfig.DefaultPreset
+		if enablePrompt {
+			items := make([]string, 0, len(feeconfig.Presets))
+			for _, p := range feeconfig.Presets {
+				items = append(items, color.F("{{bold}}%s{{/}} {{light-gray}}%s{{/}}", p.Name, p.Description))
+			}
+			prompt := promptui.Select{
+				Label:  color.F("{{blue}}{{bold}}Which fee preset fits the chain?{{/}}"),
+				Stdout: os.Stdout,
+				Items:  items,
+			}
+			idx, _, err := prompt.Run()
+			if err != nil {
+				return err
+			}
+			name = feeconfig.Presets[idx].Name
+		}
+	}
+	p, err := feeconfig.Lookup(name)
+	if err != nil {
+		return err
+	}
+	c := p.Config
+	flags := cmd.Flags()
+	if flags.Changed("gas-limit") {
+		c.GasLimit = feeGasLimit
+	}
+	if flags.Changed("target-gas") {
+		c.TargetGas = feeTargetGas
+	}
+	if flags.Changed("min-base-fee") {
+		c.MinBaseFee = feeMinBaseFee
+	}
+	if flags.Changed("target-block-rate") {
+		c.TargetBlockRate = feeTargetBlockRate
+	}
+	warnings, err := feeconfig.Check(c)
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{blue}}fee preset %q:{{/}} {{light-gray}}%s{{/}}\n", p.Name, p.Description)
+	color.Print(makeFeeConfigTable(c))
+	for _, w := range warnings {
+		color.Outf("{{yellow}}warning: %s{{/}}\n", w)
+	}
+
+	if vmGenesisPath == "" {
+		b, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		// machine-readable output is printed even in quiet mode
+		fmt.Fprintln(color.Stdout, string(b))
+		return nil
+	}
+	genesis, err := ioutil.ReadFile(vmGenesisPath)
+	if err != nil {
+		return err
+	}
+	b, err := feeconfig.Apply(genesis, c)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, vmGenesisPath)
+	}
+	if err := ioutil.WriteFile(vmGenesisPath, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}wrote fee config to{{/}} %q\n", vmGenesisPath)
+	color.Result(vmGenesisPath)
+	return nil
+}
+
+func makeFeeConfigTable(c feeconfig.Config) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	for _, f := range feeconfig.Explain(c) {
+		tb.Append([]string{
+			color.F("{{cyan}}{{bold}}%s{{/}}", f.Name),
+			color.F("{{light-gray}}{{bold}}%s{{/}}", formatCount(f.Value)),
+			color.F("{{light-gray}}%s{{/}}", f.Explanation),
+		})
+	}
+	tb.Render()
+	return buf.String()
+}
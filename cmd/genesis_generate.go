@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newGenesisGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generates a VM genesis with a genesis plugin",
+		Long: `
+Generates a VM genesis from "--param" and "--params-file" with the genesis
+plugin, and writes it to "--vm-genesis-path" (or stdout if empty).
+
+$ subnet-cli genesis generate \
+--vm=spacesvm \
+--params-file=spacesvm.params.json \
+--param=magic=1 \
+--vm-genesis-path=spacesvm.genesis
+
+`,
+		Args: cobra.NoArgs,
+		RunE: genesisGenerateFunc,
+	}
+	cmd.PersistentFlags().StringArrayVar(&genesisParams, "param", nil, "genesis parameter as key=value (value parsed as JSON if possible)")
+	cmd.PersistentFlags().StringVar(&genesisParamsFile, "params-file", "", "JSON object of genesis parameters")
+	return cmd
+}
+
+func genesisGenerateFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	params, err := loadGenesisParams()
+	if err != nil {
+		return err
+	}
+	g, stop, err := genesisPlugin(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	b, err := g.Generate(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to generate genesis: %w", err)
+	}
+	if vmGenesisPath == "" {
+		fmt.Fprintln(color.Stdout, string(b))
+		return nil
+	}
+	if err := ioutil.WriteFile(vmGenesisPath, b, 0o644); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}wrote genesis{{/}} %q {{light-gray}}(%d bytes){{/}}\n", vmGenesisPath, len(b))
+	color.Result(vmGenesisPath)
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newGenesisValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates a VM genesis with a genesis plugin",
+		Long: `
+Checks "--vm-genesis-path" with the genesis plugin, before paying for a
+blockchain the VM would fail to start.
+
+$ subnet-cli genesis validate --vm=spacesvm --vm-genesis-path=spacesvm.genesis
+
+`,
+		Args: cobra.NoArgs,
+		RunE: genesisValidateFunc,
+	}
+	return cmd
+}
+
+func genesisValidateFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	b, err := ioutil.ReadFile(vmGenesisPath)
+	if err != nil {
+		return err
+	}
+	g, stop, err := genesisPlugin(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	err = g.Validate(ctx, b)
+	if err != nil {
+		return fmt.Errorf("invalid genesis %q: %w", vmGenesisPath, err)
+	}
+	color.Outf("{{green}}genesis %q is valid{{/}}\n", vmGenesisPath)
+	return nil
+}
@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ImportCommand implements "subnet-cli import" command.
+func ImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Sub-commands for importing configurations of other tools",
+	}
+	cmd.AddCommand(
+		newImportAvalancheCLICommand(),
+	)
+	return cmd
+}
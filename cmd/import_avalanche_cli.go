@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/avacli"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	importNetworkName string
+	importOutputDir   string
+)
+
+func newImportAvalancheCLICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "avalanche-cli [SUBNET DIR]",
+		Short: "Converts an avalanche-cli subnet configuration to a spec",
+		Long: `
+Reads the sidecar.json and genesis.json of an avalanche-cli subnet
+configuration and writes an equivalent spec (see "subnet-cli validate") and
+its genesis to "--output-dir". A subnet already deployed on the network is
+referenced by its ID. Validators are not part of the configuration, so add
+them to the spec before deploying it.
+
+$ subnet-cli import avalanche-cli ~/.avalanche-cli/subnets/mysubnet \
+--network-name=fuji \
+--output-dir=.
+
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: importAvalancheCLIFunc,
+	}
+	cmd.PersistentFlags().StringVar(&importNetworkName, "network-name", "fuji", "network of the spec (e.g., fuji, mainnet, local)")
+	cmd.PersistentFlags().StringVar(&importOutputDir, "output-dir", ".", "directory to write the spec and genesis to")
+	return cmd
+}
+
+func importAvalancheCLIFunc(cmd *cobra.Command, args []string) error {
+	sc, err := avacli.Load(args[0])
+	if err != nil {
+		return err
+	}
+	genesis, err := ioutil.ReadFile(filepath.Join(args[0], avacli.GenesisFile))
+	if err != nil {
+		return err
+	}
+
+	genesisFile := fmt.Sprintf("%s.genesis.json", sc.Name)
+	s, err := sc.ToSpec(importNetworkName, genesisFile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(importOutputDir, 0o755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(importOutputDir, genesisFile), genesis, 0o644); err != nil {
+		return err
+	}
+	specFile := filepath.Join(importOutputDir, fmt.Sprintf("%s.spec.yaml", sc.Name))
+	if err := s.Save(specFile); err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}imported %s subnet %q (VM ID %s) to %q{{/}}\n", sc.VM, sc.Name, s.Subnets[0].Chains[0].VMID, specFile)
+	if nd, ok, _ := sc.Deployment(importNetworkName); ok && nd.BlockchainID != "" {
+		color.Outf("{{yellow}}the chain is already deployed on %s as %s; remove it from the spec to not create it again{{/}}\n", importNetworkName, nd.BlockchainID)
+	}
+	color.Outf("{{yellow}}add the validators to the spec, then check it with:{{/}}\n$ subnet-cli validate -f %s\n", specFile)
+	color.Result(specFile)
+	return nil
+}
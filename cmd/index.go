@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var indexDBPath string
+
+// IndexCommand implements "subnet-cli index" command.
+func IndexCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Sub-commands for the local P-Chain event index",
+	}
+	cmd.AddCommand(
+		newIndexRunCommand(),
+		newIndexHistoryCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&indexDBPath, "db-path", ".subnet-cli-index.db", "index database file (one per network)")
+	return cmd
+}
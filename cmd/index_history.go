@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/index"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var historyNodeID string
+
+func newIndexHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Shows the indexed events of a subnet",
+		Long: `
+Shows the indexed events of a subnet (or of the primary network if
+--subnet-id is empty) offline, from the index built by "subnet-cli index run".
+
+$ subnet-cli index history \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--node-id=NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg
+
+`,
+		RunE: indexHistoryFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID); empty for the primary network")
+	cmd.PersistentFlags().StringVar(&historyNodeID, "node-id", "", "only show the events of this node")
+	return cmd
+}
+
+func indexHistoryFunc(cmd *cobra.Command, args []string) error {
+	subnetID := constants.PrimaryNetworkID
+	if subnetIDs != "" {
+		var err error
+		if subnetID, err = ids.FromString(subnetIDs); err != nil {
+			return err
+		}
+	}
+	nodeID := ids.ShortEmpty
+	if historyNodeID != "" {
+		var err error
+		if nodeID, err = ids.ShortFromPrefixedString(historyNodeID, constants.NodeIDPrefix); err != nil {
+			return err
+		}
+	}
+
+	s, err := index.Open(indexDBPath)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	evs, err := s.Events(subnetID, nodeID)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"index", "accepted", "event", "details", "tx id"})
+	for _, ev := range evs {
+		details := ""
+		switch ev.Kind {
+		case index.EventChainCreated:
+			details = fmt.Sprintf("%s (chain %s, VM %s)", ev.ChainName, ev.ChainID, ev.VMID)
+		case index.EventValidatorAdded, index.EventValidatorRemoved:
+			details = fmt.Sprintf("%s weight %d, %s - %s",
+				color.F("{{orange}}%s{{/}}", ev.NodeID.PrefixedString(constants.NodeIDPrefix)),
+				ev.Weight,
+				ev.Start.UTC().Format(time.RFC3339),
+				ev.End.UTC().Format(time.RFC3339),
+			)
+		}
+		tb.Append([]string{
+			strconv.FormatUint(ev.Index, 10),
+			ev.Time.UTC().Format(time.RFC3339),
+			string(ev.Kind),
+			details,
+			ev.TxID.String(),
+		})
+	}
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/index"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	indexFollow       bool
+	indexSyncInterval time.Duration
+)
+
+func newIndexRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Indexes the P-Chain blocks accepted since the last run",
+		Long: `
+Follows P-Chain blocks through the index API (the node must run with
+--index-enabled) and stores subnet creations, chain creations and validator
+joins/leaves in the local index, for "subnet-cli index history".
+
+$ subnet-cli index run \
+--public-uri=http://localhost:9650 \
+--db-path=.subnet-cli-index.db \
+--follow
+
+`,
+		RunE: indexRunFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().BoolVar(&indexFollow, "follow", false, "keep indexing new blocks until interrupted")
+	cmd.PersistentFlags().DurationVar(&indexSyncInterval, "sync-interval", 10*time.Second, "interval between syncs with --follow")
+	return cmd
+}
+
+func indexRunFunc(cmd *cobra.Command, args []string) error {
+	s, err := index.Open(indexDBPath)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	ccfg := client.Config{
+		URI:          publicURI,
+		Proxy:        proxyURI,
+		TLS:          clientTLSConfig(),
+		AuthToken:    authToken,
+		AuthPassword: authPassword,
+		RateLimit:    rateLimit,
+	}
+	if err := client.ConfigureTransport(ccfg); err != nil {
+		return err
+	}
+	if err := client.ConfigureRateLimit(ccfg); err != nil {
+		return err
+	}
+	// "--follow" runs until interrupted, so each sync has its own deadline
+	actx, cancel := stepContext(cmd.Context())
+	err = client.ConfigureAuth(actx, ccfg)
+	cancel()
+	if err != nil {
+		return err
+	}
+	u, err := client.ParseURI(publicURI)
+	if err != nil {
+		return err
+	}
+	cli := indexer.NewClient(u.Scheme+"://"+u.Host, index.Endpoint)
+	for {
+		ctx, cancel := stepContext(cmd.Context())
+		n, err := index.Sync(ctx, cli, s, index.DefaultBatchSize)
+		cancel()
+		if err != nil && cmd.Context().Err() == nil {
+			return err
+		}
+		next, nerr := s.Next()
+		if nerr != nil {
+			return nerr
+		}
+		color.Outf("{{green}}indexed %d block(s), next index %d{{/}}\n", n, next)
+		skipped, serr := s.Skipped()
+		if serr != nil {
+			return serr
+		}
+		if len(skipped) > 0 {
+			color.Outf("{{yellow}}skipped %d undecodable block(s), their events are missing: %v{{/}}\n", len(skipped), skipped)
+		}
+		if !indexFollow {
+			return nil
+		}
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(indexSyncInterval):
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/poll"
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	issueAts      string
+	issueAtHeight uint64
+)
+
+// parseIssueAt returns the "--issue-at" time, which must be before
+// [expireAt] (if set) for the operation to ever issue.
+func parseIssueAt(expireAt time.Time) (time.Time, error) {
+	if issueAts == "" {
+		return time.Time{}, nil
+	}
+	t, err := timeexpr.Parse(issueAts, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--issue-at: %w", err)
+	}
+	if !expireAt.IsZero() && !t.Before(expireAt) {
+		return time.Time{}, fmt.Errorf("%w: --issue-at %s is not before --expire-at %s", ErrInvalidIssueAt, t.Format(time.RFC3339), expireAt.Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+// waitIssueAt waits, once the operation is confirmed, until
+// [i.issueAt] and until the P-Chain reaches [i.issueAtHeight], polling
+// with the "--poll-interval" poller. It is a no-op without "--issue-at"
+// or "--issue-at-height".
+func (i *Info) waitIssueAt(ctx context.Context, cli client.Client) error {
+	if i.issueAt.IsZero() && i.issueAtHeight == 0 {
+		return nil
+	}
+	if i.fixedValidateStart && !i.validateStart.IsZero() && i.validateStart.Before(i.issueAt.Add(validatePropagationBuffer)) {
+		return fmt.Errorf("%w: validation starts at %s, before --issue-at %s", ErrInvalidValidateWindow, i.validateStart.Format(time.RFC3339), i.issueAt.Format(time.RFC3339))
+	}
+	pl := poll.New(pollInterval)
+	if !i.issueAt.IsZero() {
+		color.Outf("{{blue}}waiting until %s to issue (--issue-at){{/}}\n", i.issueAt.Format(time.RFC3339))
+		pctx := poll.WithPhase(ctx, "issue-at", time.Until(i.issueAt))
+		if _, err := pl.Poll(pctx, func() (bool, error) {
+			return sleepUntil(ctx, i.issueAt, pollInterval)
+		}); err != nil {
+			return err
+		}
+	}
+	if i.issueAtHeight > 0 {
+		color.Outf("{{blue}}waiting until P-Chain height %d to issue (--issue-at-height){{/}}\n", i.issueAtHeight)
+		pctx := poll.WithPhase(ctx, "issue-at-height", 0)
+		if _, err := pl.Poll(pctx, func() (bool, error) {
+			height, err := cli.P().Client().GetHeight(ctx)
+			if err != nil {
+				return false, err
+			}
+			zap.L().Debug("waiting for P-Chain height", zap.Uint64("height", height), zap.Uint64("target", i.issueAtHeight))
+			return height >= i.issueAtHeight, nil
+		}); err != nil {
+			return err
+		}
+	}
+	color.Outf("{{green}}issuing at %s{{/}}\n", time.Now().Format(time.RFC3339Nano))
+	return nil
+}
+
+// sleepUntil returns true once [t] is reached, sleeping the rest of the
+// wait if it is shorter than the poll [interval], so the operation is
+// issued at [t] rather than up to an interval later.
+func sleepUntil(ctx context.Context, t time.Time, interval time.Duration) (bool, error) {
+	left := time.Until(t)
+	if left <= 0 {
+		return true, nil
+	}
+	if left >= interval {
+		return false, nil
+	}
+	timer := time.NewTimer(left)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, poll.Fatal(ctx.Err())
+	case <-timer.C:
+		return true, nil
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// KeyCommand implements "subnet-cli key" command.
+func KeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "Sub-commands for managing keys",
+	}
+	cmd.AddCommand(
+		newKeyImportCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path")
+	return cmd
+}
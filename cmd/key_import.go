@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/qr"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	importFormat       string
+	importPasswordPath string
+	importAddressIndex uint32
+)
+
+func newKeyImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [key file|-]",
+		Short: "Imports a private key from another format",
+		Long: `
+Converts a private key into the CLI key file. Accepts CB58 "PrivateKey-..."
+strings, hex EVM keys, PEM-encoded secp256k1 keys, web wallet keystore
+exports and mnemonic phrases (m/44'/9000'/0'/0/<address-index>). The format
+is detected unless --format is set. Reads stdin for "-", so the key does not
+end up in the shell history.
+
+$ subnet-cli key import wallet-keystore.json --private-key-path=.subnet-cli.pk
+
+$ pbpaste | subnet-cli key import - --network-name=fuji
+
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: keyImportFunc,
+	}
+	formats := make([]string, len(key.Formats))
+	for i, f := range key.Formats {
+		formats[i] = string(f)
+	}
+	cmd.PersistentFlags().StringVar(&importFormat, "format", "", fmt.Sprintf("key format (%s); empty to detect", strings.Join(formats, ", ")))
+	cmd.PersistentFlags().StringVar(&importPasswordPath, "keystore-password-path", "", "keystore password file path; empty to prompt")
+	cmd.PersistentFlags().Uint32Var(&importAddressIndex, "address-index", 0, "address index to derive from a mnemonic or mnemonic keystore")
+	cmd.PersistentFlags().StringVar(&keyNetworkName, "network-name", "", "network the key is intended for (e.g., fuji, mainnet); empty to not label")
+	return cmd
+}
+
+func keyImportFunc(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(privKeyPath); err == nil {
+		color.Outf("{{red}}key already found at %q{{/}}\n", privKeyPath)
+		return os.ErrExist
+	}
+	networkID := uint32(0)
+	if keyNetworkName != "" {
+		var err error
+		networkID, err = constants.NetworkID(keyNetworkName)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if args[0] == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	format := key.Format(importFormat)
+	if format == key.FormatAuto {
+		format = key.DetectFormat(data)
+	}
+	opts := []key.IOpOption{
+		key.WithFormat(format),
+		key.WithAddressIndex(importAddressIndex),
+	}
+	if format == key.FormatKeystore {
+		password, err := keystorePassword()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, key.WithPassword(password))
+	}
+	pk, format, err := key.Import(data, opts...)
+	if err != nil {
+		return err
+	}
+
+	k, err := key.NewSoft(networkID, key.WithPrivateKey(pk))
+	if err != nil {
+		return err
+	}
+	addr := k.Addresses()[0]
+	xAddr, err := key.FormatXAddress(networkID, addr)
+	if err != nil {
+		return err
+	}
+	if err := k.Save(privKeyPath); err != nil {
+		return err
+	}
+	if keyNetworkName != "" {
+		if err := key.SaveNetworkLabel(privKeyPath, networkID); err != nil {
+			return err
+		}
+		color.Outf("{{green}}labeled key %q for network %q{{/}}\n", privKeyPath, constants.NetworkName(networkID))
+	}
+	color.Outf("{{green}}imported %s key to %q{{/}}\n", format, privKeyPath)
+	color.Outf("{{blue}}P-Chain address:{{/}} %s\n", k.P()[0])
+	color.Result(k.P()[0])
+	color.Outf("{{blue}}X-Chain address:{{/}} %s\n", xAddr)
+	color.Outf("{{blue}}C-Chain address:{{/}} %s\n", evm.AddressFromKey(pk))
+	printQR("P-Chain address "+k.P()[0], k.P()[0], qr.Medium)
+	return nil
+}
+
+func keystorePassword() (string, error) {
+	if importPasswordPath != "" {
+		b, err := ioutil.ReadFile(importPasswordPath)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	prompt := promptui.Prompt{
+		Label: "keystore password",
+		Mask:  '*',
+	}
+	return prompt.Run()
+}
@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrInvalidValidationID = errors.New("invalid --validation-id")
+	ErrZeroAmount          = errors.New("zero --amount")
+)
+
+var (
+	validationIDs []string
+	topUpAmount   uint64
+)
+
+// L1Command implements "subnet-cli l1" command.
+func L1Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "l1",
+		Short: "Sub-commands for the validators of sovereign L1s (ACP-77)",
+	}
+	cmd.AddCommand(
+		newL1BalanceCommand(),
+		newL1TopUpCommand(),
+		newL1AddValidatorCommand(),
+		newL1RemoveValidatorCommand(),
+		newL1DeployManagerCommand(),
+	)
+	return cmd
+}
+
+func newL1BalanceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Shows the continuous-fee balance of L1 validators",
+		Long: `
+Shows the balance L1 validators pay their continuous fee from, and how long
+it lasts at the current fee. A validator whose balance runs out is inactive
+until topped up (see "subnet-cli l1 top-up").
+
+$ subnet-cli l1 balance \
+--public-uri=https://api.avax-test.network \
+--validation-id="2Y7rmCR5V1Mda2pPXEGXAeWBdL5SGXoNnvaVgsGUBSHqX8nGYz"
+
+`,
+		Args: cobra.NoArgs,
+		RunE: l1BalanceFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringSliceVar(&validationIDs, "validation-id", nil, "validation ID of the L1 validator (repeatable)")
+	return cmd
+}
+
+func l1BalanceFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, false)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	vids, err := parseValidationIDs()
+	if err != nil {
+		return err
+	}
+	fee, err := cli.P().GetValidatorFee(ctx)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.Append([]string{color.F("{{red}}{{bold}}CONTINUOUS FEE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} nAVAX/s per validator", humanize.Comma(int64(fee.Price)))})
+	for _, id := range vids {
+		v, err := cli.P().GetL1Validator(ctx, id)
+		if err != nil {
+			return err
+		}
+		tb.Append([]string{color.F("{{blue}}VALIDATION ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", v.ValidationID)})
+		tb.Append([]string{color.F("{{orange}}%s{{/}}", v.NodeID.PrefixedString(constants.NodeIDPrefix)), color.F("{{light-gray}}L1 {{bold}}%s{{/}}{{light-gray}}, weight {{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, v.SubnetID), humanize.Comma(int64(v.Weight)))})
+		tb.Append([]string{color.F("{{coral}}{{bold}}BALANCE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}{{light-gray}} (%s){{/}}", amount.Format(v.Balance), l1BalanceStatus(fee, v.Balance)) + info.fiat(v.Balance)})
+	}
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
+
+func newL1TopUpCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top-up",
+		Short: "Increases the continuous-fee balance of an L1 validator",
+		Long: `
+Increases the balance an L1 validator pays its continuous fee from
+(IncreaseL1ValidatorBalanceTx), reactivating it if the balance ran out.
+Any key can top up any validator; the amount is burned.
+
+$ subnet-cli l1 top-up \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=https://api.avax-test.network \
+--validation-id="2Y7rmCR5V1Mda2pPXEGXAeWBdL5SGXoNnvaVgsGUBSHqX8nGYz" \
+--amount=2avax
+
+`,
+		Args: cobra.NoArgs,
+		RunE: l1TopUpFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringSliceVar(&validationIDs, "validation-id", nil, "validation ID of the L1 validator")
+	cmd.PersistentFlags().Var((*amountFlag)(&topUpAmount), "amount", "amount to add to the balance (e.g., 2avax)")
+	return cmd
+}
+
+func l1TopUpFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	vids, err := parseValidationIDs()
+	if err != nil {
+		return err
+	}
+	if len(vids) != 1 {
+		return fmt.Errorf("%w: top-up takes exactly one", ErrInvalidValidationID)
+	}
+	if topUpAmount == 0 {
+		return ErrZeroAmount
+	}
+	v, err := cli.P().GetL1Validator(ctx, vids[0])
+	if err != nil {
+		return err
+	}
+	fee, err := cli.P().GetValidatorFee(ctx)
+	if err != nil {
+		return err
+	}
+
+	info.txFee, err = info.Fee(ctx, client.TxTypeIncreaseL1ValidatorBalance, 0)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee + topUpAmount
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}VALIDATION ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", v.ValidationID)})
+	tb.Append([]string{color.F("{{orange}}%s{{/}}", v.NodeID.PrefixedString(constants.NodeIDPrefix)), color.F("{{light-gray}}L1 {{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, v.SubnetID))})
+	tb.Append([]string{color.F("{{coral}}{{bold}}BALANCE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}{{light-gray}} (%s){{/}}", amount.Format(v.Balance), l1BalanceStatus(fee, v.Balance))})
+	tb.Append([]string{color.F("{{red}}{{bold}}TOP-UP{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", amount.Format(topUpAmount)) + info.fiat(topUpAmount)})
+	tb.Append([]string{color.F("{{green}}{{bold}}NEW BALANCE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}{{light-gray}} (%s){{/}}", amount.Format(v.Balance+topUpAmount), l1BalanceStatus(fee, v.Balance+topUpAmount))})
+	tb.Render()
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(buf.String(), changes, "top up the L1 validator balance", "I agree to pay the fee and burn the amount") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+	println()
+	println()
+	println()
+	took, err := cli.P().IncreaseL1ValidatorBalance(ctx, info.key, v.ValidationID, topUpAmount)
+	if err != nil {
+		return err
+	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}topped up L1 validator{{/}} %q {{magenta}}by %s{{/}} {{light-gray}}(took %v){{/}}\n\n", v.ValidationID, amount.Format(topUpAmount), took)
+	color.Result(v.ValidationID)
+	return nil
+}
+
+func parseValidationIDs() ([]ids.ID, error) {
+	if len(validationIDs) == 0 {
+		return nil, fmt.Errorf("%w: required", ErrInvalidValidationID)
+	}
+	vids := make([]ids.ID, 0, len(validationIDs))
+	for _, s := range validationIDs {
+		id, err := ids.FromString(s)
+		if err != nil {
+			return nil, err
+		}
+		vids = append(vids, id)
+	}
+	return vids, nil
+}
+
+// l1BalanceStatus projects how long [balance] lasts at the current [fee].
+func l1BalanceStatus(fee *client.ValidatorFee, balance uint64) string {
+	switch {
+	case balance == 0:
+		return "inactive, top up to reactivate"
+	case fee.Price == 0:
+		return "no fee is charged at the current price"
+	}
+	return "lasts " + l1.FormatRuntime(fee.Runtime(balance)) + " at the current fee"
+}
@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+	"github.com/ava-labs/subnet-cli/internal/validatormanager"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/internal/warp"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrManagerMismatch = errors.New("L1 converted with another validator manager")
+	ErrManagerOwner    = errors.New("validator manager owned by another account")
+)
+
+var (
+	managerKind             string
+	managerBytecodePath     string
+	managerOwner            string
+	managerChurnPeriod      time.Duration
+	managerMaxChurnPercent  uint8
+	managerMinStake         uint64
+	managerMaxStake         uint64
+	managerMinStakeDuration time.Duration
+	managerMinDelegationFee uint16
+	managerMaxStakeMultiple uint8
+	managerWeightToValue    uint64
+	managerRewardCalculator string
+)
+
+func newL1DeployManagerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy-manager",
+		Short: "Deploys the validator manager contract of an L1 and registers its initial validators",
+		Long: `
+Sets up the validator manager of an L1 on its EVM chain, in two runs
+around the conversion of the subnet:
+
+1. Before "subnet-cli convert l1", deploys the reference validator manager
+   ("--manager-bytecode", the compiled "PoAValidatorManager" or
+   "NativeTokenStakingManager" of ava-labs/icm-contracts) and initializes it
+   for the subnet: owned by "--owner" for "--kind=poa", or with the staking
+   settings for "--kind=pos". Then convert the subnet with its address.
+
+2. After the conversion, with "--manager-address", registers the initial
+   validators on the contract (initializeValidatorSet) with the conversion
+   message the P-Chain validators signed. "--validator" must repeat the
+   validators of the conversion.
+
+Deploying and initializing the contract are two txs, so anyone watching
+the chain can initialize a new deployment first, with their own owner or
+settings. The initialize tx of deploy-manager then reverts and the command
+fails; for "--kind=poa", it also checks the owner once initialized. Never
+convert the subnet with a manager address deploy-manager did not report
+as initialized; deploy a new one instead.
+
+$ subnet-cli l1 deploy-manager \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--chain-id="2XDnKyAEr1RhhWpTpMXqrjeejN23vETmDykVzkb4PrU1fQjewh" \
+--evm-rpc=http://localhost:9650/ext/bc/2XDnKyAEr1RhhWpTpMXqrjeejN23vETmDykVzkb4PrU1fQjewh/rpc \
+--evm-private-key-path=.insecure.ewoq.key \
+--kind=poa \
+--manager-bytecode=PoAValidatorManager.bin
+
+$ subnet-cli convert l1 ... --manager-address=0x...
+
+$ subnet-cli l1 deploy-manager ... \
+--manager-address=0x... \
+--validator=node-id=NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH,weight=100,bls-public-key=0x...,bls-pop=0x... \
+--signature-aggregator=http://localhost:8080/aggregate-signatures
+
+`,
+		Args: cobra.NoArgs,
+		RunE: l1DeployManagerFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name of the L1 (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&blockchainID, "chain-id", "", "blockchain ID of the EVM chain of the validator manager")
+	cmd.PersistentFlags().StringVar(&evmRPC, "evm-rpc", "", "EVM RPC endpoint of the chain (e.g., http://localhost:9650/ext/bc/[BLOCKCHAIN ID]/rpc)")
+	cmd.PersistentFlags().StringVar(&evmPrivKeyPath, "evm-private-key-path", ".subnet-cli.pk", "EVM private key file path (hex or PrivateKey- encoded)")
+	cmd.PersistentFlags().StringVar(&managerKind, "kind", string(validatormanager.PoA), "validator manager kind (poa or pos)")
+	cmd.PersistentFlags().StringVar(&managerBytecodePath, "manager-bytecode", "", "file with the hex-encoded bytecode of the validator manager")
+	cmd.PersistentFlags().StringVar(&managerAddrs, "manager-address", "", "hex address of the deployed validator manager (registers the initial validators)")
+	cmd.PersistentFlags().StringVar(&managerOwner, "owner", "", "owner of a PoA validator manager (defaults to the EVM key)")
+	cmd.PersistentFlags().DurationVar(&managerChurnPeriod, "churn-period", time.Hour, "period over which the weight changes are bounded")
+	cmd.PersistentFlags().Uint8Var(&managerMaxChurnPercent, "max-churn-percent", 20, "maximum weight change per churn period (percentage of the total weight)")
+	cmd.PersistentFlags().Uint64Var(&managerMinStake, "min-stake", 1, "minimum stake of a validator in native tokens (pos)")
+	cmd.PersistentFlags().Uint64Var(&managerMaxStake, "max-stake", 1_000_000, "maximum stake of a validator in native tokens (pos)")
+	cmd.PersistentFlags().DurationVar(&managerMinStakeDuration, "min-stake-duration", 24*time.Hour, "minimum staking duration (pos)")
+	cmd.PersistentFlags().Uint16Var(&managerMinDelegationFee, "min-delegation-fee-bips", 100, "minimum delegation fee in basis points (pos)")
+	cmd.PersistentFlags().Uint8Var(&managerMaxStakeMultiple, "max-stake-multiplier", 4, "maximum delegated stake as a multiple of the validator stake (pos)")
+	cmd.PersistentFlags().Uint64Var(&managerWeightToValue, "weight-to-value-factor", 1_000_000_000_000, "wei of stake per unit of validator weight (pos)")
+	cmd.PersistentFlags().StringVar(&managerRewardCalculator, "reward-calculator", "", "address of the reward calculator contract (pos)")
+	cmd.PersistentFlags().StringArrayVar(&l1Validators, "validator", nil, "initial validator of the conversion as node-id=...,weight=...,bls-public-key=0x...,bls-pop=0x... (repeatable)")
+	addWarpSignatureFlags(cmd)
+	return cmd
+}
+
+// l1DeployManagerFunc runs each step (deploying, initializing, checking
+// the conversion, aggregating signatures, registering the validators)
+// under its own "--timeout", since the chain may be slow to include each
+// of the txs.
+func l1DeployManagerFunc(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	kind, err := validatormanager.ParseKind(managerKind)
+	if err != nil {
+		return err
+	}
+	sctx, cancel := stepContext(ctx)
+	cli, info, err := InitClient(sctx, publicURI, false)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+	chainID, err := ids.FromString(blockchainID)
+	if err != nil {
+		return fmt.Errorf("%w: --chain-id %q", err, blockchainID)
+	}
+	ecli, k, err := InitEVMClient()
+	if err != nil {
+		return err
+	}
+
+	var manager evm.Address
+	if managerAddrs != "" {
+		if manager, err = evm.ParseAddress(managerAddrs); err != nil {
+			return err
+		}
+	} else {
+		if manager, err = deployManager(ctx, ecli, k, kind, info.subnetID, chainID); err != nil {
+			return err
+		}
+	}
+
+	sctx, cancel = stepContext(ctx)
+	managerChainID, address, err := cli.P().GetL1Manager(sctx, info.subnetID)
+	cancel()
+	if errors.Is(err, client.ErrNotL1) {
+		color.Outf("{{blue}}convert the subnet to an L1 managed by the contract, then re-run with --manager-address to register the initial validators:{{/}}\n")
+		color.Outf("  subnet-cli convert l1 --subnet-id=%s --chain-id=%s --manager-address=%s --validator=...\n", info.subnetID, chainID, manager)
+		color.Result(manager.Hex())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if managerChainID != chainID || !bytes.Equal(address, manager[:]) {
+		return fmt.Errorf("%w: %s at 0x%x", ErrManagerMismatch, managerChainID, address)
+	}
+
+	if len(l1Validators) == 0 {
+		return fmt.Errorf("%w: --validator required to register the initial validators", codec.ErrNoL1Validators)
+	}
+	vs := make([]l1.Validator, 0, len(l1Validators))
+	for _, s := range l1Validators {
+		v, err := l1.ParseValidator(s, 0)
+		if err != nil {
+			return err
+		}
+		vs = append(vs, v)
+	}
+	data := validatormanager.ConversionData(info.subnetID, chainID, manager, vs)
+	conversionID, err := data.ConversionID()
+	if err != nil {
+		return err
+	}
+	msg, err := (&warp.SubnetToL1Conversion{ID: conversionID}).Bytes()
+	if err != nil {
+		return err
+	}
+	unsigned, err := warp.NewPChainMessage(info.networkID, msg)
+	if err != nil {
+		return err
+	}
+	// the P-Chain message is signed by the primary network validators,
+	// given the converted subnet
+	sctx, cancel = stepContext(ctx)
+	signed, err := signWarpMessage(sctx, unsigned, info.subnetID[:], constants.PrimaryNetworkID)
+	cancel()
+	if err != nil {
+		return err
+	}
+	sctx, cancel = stepContext(ctx)
+	hash, _, err := ecli.Transact(sctx, k, &evm.Tx{
+		To:         &manager,
+		Data:       validatormanager.InitializeValidatorSet(data),
+		AccessList: []evm.AccessTuple{validatormanager.Predicate(signed)},
+	}, pollInterval)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to register the initial validators (wrong --validator, or already registered?): %w", err)
+	}
+	color.Outf("{{magenta}}registered %d initial validator(s) on the validator manager %s{{/}} {{light-gray}}(conversion %s, tx %s){{/}}\n", len(vs), manager, conversionID, evm.HashString(hash))
+	for i, v := range data.Validators {
+		nodeID, _ := ids.ToShortID(v.NodeID)
+		color.Outf("{{orange}}%s{{/}} {{light-gray}}validation ID{{/}} %s\n", nodeID.PrefixedString(constants.NodeIDPrefix), codec.L1ValidationID(info.subnetID, uint32(i)))
+	}
+	color.Result(manager.Hex())
+	return nil
+}
+
+// deployManager deploys a validator manager of [kind] for [subnetID] with
+// [k] and initializes it, returning its address. Each tx gets its own
+// "--timeout" from [ctx].
+func deployManager(ctx context.Context, ecli *evm.Client, k *crypto.PrivateKeySECP256K1R, kind validatormanager.Kind, subnetID ids.ID, chainID ids.ID) (evm.Address, error) {
+	var manager evm.Address
+	if managerBytecodePath == "" {
+		return manager, fmt.Errorf("%w: --manager-bytecode or --manager-address required", validatormanager.ErrInvalidSettings)
+	}
+	bytecode, err := readHexFile(managerBytecodePath)
+	if err != nil {
+		return manager, err
+	}
+	settings := validatormanager.Settings{SubnetID: subnetID, ChurnPeriod: managerChurnPeriod, MaxChurnPercent: managerMaxChurnPercent}
+	var (
+		initialize []byte
+		owner      = evm.AddressFromKey(k)
+	)
+	switch kind {
+	case validatormanager.PoA:
+		if managerOwner != "" {
+			if owner, err = evm.ParseAddress(managerOwner); err != nil {
+				return manager, err
+			}
+		}
+		initialize, err = validatormanager.InitializePoA(settings, owner)
+	case validatormanager.PoS:
+		s := validatormanager.PoSSettings{
+			Settings:             settings,
+			MinStake:             new(big.Int).Mul(new(big.Int).SetUint64(managerMinStake), evm.Ether),
+			MaxStake:             new(big.Int).Mul(new(big.Int).SetUint64(managerMaxStake), evm.Ether),
+			MinStakeDuration:     managerMinStakeDuration,
+			MinDelegationFeeBips: managerMinDelegationFee,
+			MaxStakeMultiplier:   managerMaxStakeMultiple,
+			WeightToValueFactor:  new(big.Int).SetUint64(managerWeightToValue),
+			UptimeBlockchainID:   chainID,
+		}
+		if managerRewardCalculator != "" {
+			if s.RewardCalculator, err = evm.ParseAddress(managerRewardCalculator); err != nil {
+				return manager, err
+			}
+		}
+		initialize, err = validatormanager.InitializePoS(s)
+	}
+	if err != nil {
+		return manager, err
+	}
+
+	sctx, cancel := stepContext(ctx)
+	hash, r, err := ecli.Transact(sctx, k, &evm.Tx{Data: validatormanager.DeployData(bytecode)}, pollInterval)
+	cancel()
+	if err != nil {
+		return manager, err
+	}
+	if r.ContractAddress == nil {
+		return manager, fmt.Errorf("%w: no contract address in the receipt of %s", evm.ErrTxFailed, evm.HashString(hash))
+	}
+	if manager, err = evm.ParseAddress(*r.ContractAddress); err != nil {
+		return manager, err
+	}
+	color.Outf("{{magenta}}deployed %s validator manager at %s{{/}} {{light-gray}}(tx %s){{/}}\n", strings.ToUpper(string(kind)), manager, evm.HashString(hash))
+	sctx, cancel = stepContext(ctx)
+	hash, _, err = ecli.Transact(sctx, k, &evm.Tx{To: &manager, Data: initialize}, pollInterval)
+	cancel()
+	if err != nil {
+		return manager, fmt.Errorf("failed to initialize the validator manager at %s (initialized by another account first? deploy a new one rather than converting with it): %w", manager, err)
+	}
+	if kind == validatormanager.PoA {
+		sctx, cancel = stepContext(ctx)
+		ret, err := ecli.CallContract(sctx, evm.Address{}, manager, validatormanager.Owner())
+		cancel()
+		if err != nil {
+			return manager, err
+		}
+		if got := evm.DecodeAddress(ret, 0); got != owner {
+			return manager, fmt.Errorf("%w: %s owns the validator manager at %s, expected %s", ErrManagerOwner, got, manager, owner)
+		}
+	}
+	color.Outf("{{magenta}}initialized the validator manager for subnet %s{{/}} {{light-gray}}(tx %s){{/}}\n", subnetID, evm.HashString(hash))
+	return manager, nil
+}
@@ -0,0 +1,360 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/internal/warp"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrNoWarpSignature = errors.New("--signature-aggregator or --signed-message-path required")
+	ErrWarpMismatch    = errors.New("signed message differs from the message to sign")
+)
+
+var (
+	l1ValidatorSpec     string
+	registrationExpiry  string
+	weightNonce         uint64
+	signatureAggregator string
+	signedMessagePath   string
+)
+
+func addWarpSignatureFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&signatureAggregator, "signature-aggregator", "", "\"aggregate-signatures\" endpoint of a signature aggregator that collects the L1 validator signatures (e.g., http://localhost:8080/aggregate-signatures)")
+	cmd.PersistentFlags().StringVar(&signedMessagePath, "signed-message-path", "", "file with the hex-encoded signed Warp message (instead of --signature-aggregator)")
+}
+
+func newL1AddValidatorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-validator",
+		Short: "Registers a validator of an L1 (RegisterL1ValidatorTx)",
+		Long: `
+Registers a validator of an L1 on the P-Chain. The Warp message of the
+validator manager is built from the flags (the remaining balance and
+disable owner is the key), signed by the L1 validators through
+"--signature-aggregator", and issued with the validator balance.
+
+The L1 validators only sign a message their validator manager sent, so
+initiate the registration on the manager first with the same node ID, BLS
+key, weight and expiry.
+
+$ subnet-cli l1 add-validator \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--validator=node-id=NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH,weight=100,balance=2avax,bls-public-key=0x...,bls-pop=0x... \
+--expiry=2024-12-20T10:00:00Z \
+--signature-aggregator=http://localhost:8080/aggregate-signatures
+
+`,
+		Args: cobra.NoArgs,
+		RunE: l1AddValidatorFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name of the L1 (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&l1ValidatorSpec, "validator", "", "validator as node-id=...,weight=...,bls-public-key=0x...,bls-pop=0x...[,balance=...]")
+	l1ValidatorBalance = defaultL1ValidatorBalance
+	cmd.PersistentFlags().Var((*amountFlag)(&l1ValidatorBalance), "validator-balance", "balance of the validator without balance=..., funding its continuous fee")
+	cmd.PersistentFlags().StringVar(&registrationExpiry, "expiry", "", "registration expiry the validator manager set (RFC3339, Unix seconds or relative, e.g., now+1h)")
+	addWarpSignatureFlags(cmd)
+	return cmd
+}
+
+func l1AddValidatorFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	info.subnetIDType = "L1 SUBNET ID"
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+	v, err := l1.ParseValidator(l1ValidatorSpec, l1ValidatorBalance)
+	if err != nil {
+		return err
+	}
+	expiry, err := parseRegistrationExpiry(registrationExpiry)
+	if err != nil {
+		return err
+	}
+	owner := codec.PChainOwner{Threshold: 1, Addresses: []ids.ShortID{info.key.Addresses()[0]}}
+	reg := &warp.RegisterL1Validator{
+		SubnetID:              info.subnetID,
+		NodeID:                v.NodeID.Bytes(),
+		BLSPublicKey:          v.PublicKey,
+		Expiry:                uint64(expiry.Unix()),
+		RemainingBalanceOwner: owner,
+		DisableOwner:          owner,
+		Weight:                v.Weight,
+	}
+	validationID, err := reg.ValidationID()
+	if err != nil {
+		return err
+	}
+	msg, err := reg.Bytes()
+	if err != nil {
+		return err
+	}
+	signed, err := signManagerMessage(ctx, cli, info, info.subnetID, msg)
+	if err != nil {
+		return err
+	}
+	fee, err := cli.P().GetValidatorFee(ctx)
+	if err != nil {
+		return err
+	}
+
+	info.txFee, err = info.Fee(ctx, client.TxTypeRegisterL1Validator, 0)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee + v.Balance
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}L1 SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	tb.Append([]string{color.F("{{blue}}VALIDATION ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", validationID)})
+	tb.Append([]string{
+		color.F("{{orange}}%s{{/}}", v.NodeID.PrefixedString(constants.NodeIDPrefix)),
+		color.F("{{light-gray}}weight {{bold}}%s{{/}}{{light-gray}}, balance {{bold}}%s{{/}}{{light-gray}} (%s){{/}}", humanize.Comma(int64(v.Weight)), amount.Format(v.Balance), l1BalanceStatus(fee, v.Balance)) + info.fiat(v.Balance),
+	})
+	tb.Append([]string{color.F("{{magenta}}REGISTRATION EXPIRY{{/}}"), color.F("{{light-gray}}%s{{/}}", expiry.Local().Format(time.RFC3339))})
+	tb.Render()
+	info.newWeights = map[ids.ShortID]uint64{v.NodeID: v.Weight}
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(buf.String(), changes, "register the L1 validator", "I agree to pay the fee and the validator balance") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+	println()
+	println()
+	println()
+	took, err := cli.P().RegisterL1Validator(ctx, info.key, v.Balance, v.PoP, signed)
+	if err != nil {
+		return err
+	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}registered L1 validator{{/}} %q {{light-gray}}(%s, took %v){{/}}\n\n", validationID, v.NodeID.PrefixedString(constants.NodeIDPrefix), took)
+	color.Result(validationID)
+	return nil
+}
+
+func newL1RemoveValidatorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-validator",
+		Short: "Removes a validator of an L1 (SetL1ValidatorWeightTx)",
+		Long: `
+Removes a validator of an L1 on the P-Chain, returning its remaining
+balance to its owner. The Warp message of the validator manager (a zero
+weight) is built from the validator state, signed by the L1 validators
+through "--signature-aggregator", and issued.
+
+The L1 validators only sign a message their validator manager sent, so
+initiate the removal on the manager first; "--nonce" must match it
+(defaults to the lowest nonce the P-Chain accepts).
+
+$ subnet-cli l1 remove-validator \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=https://api.avax-test.network \
+--validation-id="2Y7rmCR5V1Mda2pPXEGXAeWBdL5SGXoNnvaVgsGUBSHqX8nGYz" \
+--signature-aggregator=http://localhost:8080/aggregate-signatures
+
+`,
+		Args: cobra.NoArgs,
+		RunE: l1RemoveValidatorFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringSliceVar(&validationIDs, "validation-id", nil, "validation ID of the L1 validator")
+	cmd.PersistentFlags().Uint64Var(&weightNonce, "nonce", 0, "nonce of the weight change the validator manager sent (defaults to the minimum nonce of the validator)")
+	addWarpSignatureFlags(cmd)
+	return cmd
+}
+
+func l1RemoveValidatorFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+		return err
+	}
+	vids, err := parseValidationIDs()
+	if err != nil {
+		return err
+	}
+	if len(vids) != 1 {
+		return fmt.Errorf("%w: remove-validator takes exactly one", ErrInvalidValidationID)
+	}
+	v, err := cli.P().GetL1Validator(ctx, vids[0])
+	if err != nil {
+		return err
+	}
+	info.subnetIDType = "L1 SUBNET ID"
+	info.subnetID = v.SubnetID
+	nonce := v.MinNonce
+	if cmd.Flags().Changed("nonce") {
+		nonce = weightNonce
+	}
+	msg, err := (&warp.L1ValidatorWeight{ValidationID: v.ValidationID, Nonce: nonce, Weight: 0}).Bytes()
+	if err != nil {
+		return err
+	}
+	signed, err := signManagerMessage(ctx, cli, info, v.SubnetID, msg)
+	if err != nil {
+		return err
+	}
+
+	info.txFee, err = info.Fee(ctx, client.TxTypeSetL1ValidatorWeight, 0)
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}L1 SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, v.SubnetID))})
+	tb.Append([]string{color.F("{{blue}}VALIDATION ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", v.ValidationID)})
+	tb.Append([]string{
+		color.F("{{orange}}%s{{/}}", v.NodeID.PrefixedString(constants.NodeIDPrefix)),
+		color.F("{{light-gray}}weight {{bold}}%s{{/}}{{light-gray}} -> {{bold}}0{{/}}{{light-gray}}, returns {{bold}}%s{{/}}", humanize.Comma(int64(v.Weight)), amount.Format(v.Balance)),
+	})
+	tb.Append([]string{color.F("{{magenta}}NONCE{{/}}"), color.F("{{light-gray}}%d{{/}}", nonce)})
+	tb.Render()
+	info.newWeights = map[ids.ShortID]uint64{v.NodeID: 0}
+	changes, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(buf.String(), changes, "remove the L1 validator", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+	println()
+	println()
+	println()
+	took, err := cli.P().SetL1ValidatorWeight(ctx, info.key, signed)
+	if err != nil {
+		return err
+	}
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
+	color.Outf("{{magenta}}removed L1 validator{{/}} %q {{light-gray}}(%s, took %v){{/}}\n\n", v.ValidationID, v.NodeID.PrefixedString(constants.NodeIDPrefix), took)
+	color.Result(v.ValidationID)
+	return nil
+}
+
+// parseRegistrationExpiry parses "--expiry", also accepting the Unix
+// seconds validator manager contracts take.
+func parseRegistrationExpiry(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	t, err := timeexpr.Parse(s, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--expiry: %w", err)
+	}
+	return t, nil
+}
+
+// signManagerMessage returns the Warp message the validator manager of the
+// L1 [subnetID] sends with the P-Chain message [msg], signed by the L1
+// validators.
+func signManagerMessage(ctx context.Context, cli client.Client, info *Info, subnetID ids.ID, msg []byte) ([]byte, error) {
+	chainID, address, err := cli.P().GetL1Manager(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	unsigned, err := warp.NewManagerMessage(info.networkID, chainID, address, msg)
+	if err != nil {
+		return nil, err
+	}
+	return signWarpMessage(ctx, unsigned, nil, subnetID)
+}
+
+// signWarpMessage returns the [unsigned] message signed by the validators
+// of [subnetID], read from "--signed-message-path" or collected through
+// "--signature-aggregator" given the [justification] of its content.
+func signWarpMessage(ctx context.Context, unsigned []byte, justification []byte, subnetID ids.ID) ([]byte, error) {
+	switch {
+	case signedMessagePath != "":
+		signed, err := readHexFile(signedMessagePath)
+		if err != nil {
+			return nil, err
+		}
+		m, err := warp.ParseMessage(signed)
+		if err != nil {
+			return nil, err
+		}
+		if got, err := m.UnsignedMessage.Bytes(); err != nil || !bytes.Equal(got, unsigned) {
+			return nil, fmt.Errorf("%w: %s", ErrWarpMismatch, signedMessagePath)
+		}
+		return signed, nil
+	case signatureAggregator != "":
+		color.Outf("{{blue}}collecting validator signatures from{{/}} %q\n", signatureAggregator)
+		_, signed, err := warp.Aggregate(ctx, signatureAggregator, unsigned, justification, subnetID, uint64(warpQuorum*100))
+		return signed, err
+	default:
+		return nil, ErrNoWarpSignature
+	}
+}
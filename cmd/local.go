@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// LocalCommand implements "subnet-cli local" command.
+func LocalCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "local",
+		Short: "Sub-commands for local development networks",
+	}
+	cmd.AddCommand(
+		newLocalComposeCommand(),
+		newLocalSnapshotCommand(),
+	)
+	return cmd
+}
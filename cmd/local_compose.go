@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/devnet"
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	devnetValidators int
+	devnetVM         string
+	devnetImage      string
+	devnetSubnet     string
+	devnetOutputDir  string
+)
+
+func newLocalComposeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Generates a docker-compose devnet for the subnet being developed",
+		Long: `
+Writes a docker-compose.yaml (plus staking keys, chain configs and the
+VM genesis) for a reproducible containerized "local" network.
+
+$ subnet-cli local compose \
+--validators=5 \
+--vm=subnet-evm \
+--vm-binary=./build/subnet-evm \
+--vm-genesis-path=./genesis.json \
+--output-dir=devnet
+
+$ cd devnet && docker compose up -d
+
+`,
+		RunE: localComposeFunc,
+	}
+	cmd.PersistentFlags().IntVar(&devnetValidators, "validators", devnet.MaxValidators, "number of validator nodes (local network genesis has at most 5)")
+	cmd.PersistentFlags().StringVar(&devnetVM, "vm", "", "VM name (the VMID is derived from the name unless --vm-id is set)")
+	cmd.PersistentFlags().StringVar(&vmIDs, "vm-id", "", "VM ID of the plugin")
+	cmd.PersistentFlags().StringVar(&vmBinaryPath, "vm-binary", "", "VM plugin binary mounted into every node")
+	cmd.PersistentFlags().StringVar(&vmGenesisPath, "vm-genesis-path", "", "VM genesis file copied into the devnet")
+	cmd.PersistentFlags().StringVar(&avalanchegoVersion, "avalanchego-version", node.DefaultVersion, "avalanchego image tag")
+	cmd.PersistentFlags().StringVar(&devnetImage, "image", devnet.DefaultImage, "avalanchego image")
+	cmd.PersistentFlags().StringVar(&devnetSubnet, "docker-subnet", devnet.DefaultSubnet, "docker network subnet for static node IPs")
+	cmd.PersistentFlags().StringSliceVar(&trackSubnets, "track-subnets", nil, "subnet IDs the nodes track")
+	cmd.PersistentFlags().StringVar(&devnetOutputDir, "output-dir", "devnet", "directory to write the devnet to")
+	return cmd
+}
+
+func localComposeFunc(cmd *cobra.Command, args []string) error {
+	cfg := devnet.Config{
+		Name:         filepath.Base(devnetOutputDir),
+		Validators:   devnetValidators,
+		Image:        devnetImage,
+		Version:      avalanchegoVersion,
+		Subnet:       devnetSubnet,
+		TrackSubnets: trackSubnets,
+	}
+	vmName := devnetVM
+	switch {
+	case vmIDs != "":
+		vmID, err := ids.FromString(vmIDs)
+		if err != nil {
+			return err
+		}
+		cfg.VMID = vmID
+	case devnetVM != "":
+		vmID, err := VMIDFromName(devnetVM, false)
+		if err != nil {
+			return err
+		}
+		cfg.VMID = vmID
+	}
+	if vmName == "" {
+		vmName = cfg.VMID.String()
+	}
+	if vmBinaryPath != "" {
+		if cfg.VMID == ids.Empty {
+			return fmt.Errorf("%w: --vm or --vm-id is required with --vm-binary", client.ErrEmptyID)
+		}
+		p, err := filepath.Abs(vmBinaryPath)
+		if err != nil {
+			return err
+		}
+		cfg.VMBinary = p
+	}
+	for _, s := range trackSubnets {
+		if _, err := ids.FromString(s); err != nil {
+			return fmt.Errorf("%w: invalid subnet ID %q", err, s)
+		}
+	}
+
+	b, err := devnet.Compose(cfg)
+	if err != nil {
+		return err
+	}
+	ss, err := devnet.Stakers(devnetValidators)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"docker-compose.yaml": b,
+	}
+	for _, s := range ss {
+		files[filepath.Join("stakers", s.Name+".crt")] = s.Cert
+		files[filepath.Join("stakers", s.Name+".key")] = s.Key
+	}
+	if cfg.VMID != ids.Empty {
+		// avalanchego looks up chain configs by blockchain ID or alias; rename
+		// (or alias) once the blockchain is created
+		files[filepath.Join("chain-configs", vmName, "config.json")] = []byte("{}\n")
+	}
+	if vmGenesisPath != "" {
+		g, err := ioutil.ReadFile(vmGenesisPath)
+		if err != nil {
+			return err
+		}
+		files[filepath.Join("genesis", vmName+".json")] = g
+	}
+	// directories mounted into every node must exist
+	for _, d := range []string{"chain-configs", "genesis"} {
+		if err := os.MkdirAll(filepath.Join(devnetOutputDir, d), 0o755); err != nil {
+			return err
+		}
+	}
+	for p, b := range files {
+		p = filepath.Join(devnetOutputDir, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	color.Outf("{{green}}wrote %d-node devnet to %s{{/}}\n", devnetValidators, devnetOutputDir)
+	for i, s := range ss {
+		color.Outf("{{magenta}}node%d{{/}} %s {{light-gray}}http://localhost:%d{{/}}\n", i+1, s.NodeID.PrefixedString(constants.NodeIDPrefix), devnet.HostHTTPPort(i))
+	}
+	color.Outf("\n{{cyan}}$ cd %s && docker compose up -d{{/}}\n", devnetOutputDir)
+	return nil
+}
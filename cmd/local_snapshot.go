@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/devnet"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const snapshotTimeout = 10 * time.Minute
+
+var (
+	devnetDir   string
+	snapshotDir string
+)
+
+func newLocalSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Saves and restores the state of a local devnet",
+	}
+	cmd.AddCommand(
+		newLocalSnapshotSaveCommand(),
+		newLocalSnapshotLoadCommand(),
+	)
+	home, _ := os.UserHomeDir()
+	cmd.PersistentFlags().StringVar(&devnetDir, "devnet-dir", "devnet", "directory of the devnet generated by 'local compose'")
+	cmd.PersistentFlags().StringVar(&snapshotDir, "snapshot-dir", filepath.Join(home, ".subnet-cli", "snapshots"), "directory snapshots are stored in")
+	return cmd
+}
+
+func newLocalSnapshotSaveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "save <name>",
+		Short: "Captures the devnet files and node data",
+		Long: `
+Stops the devnet, captures its files (compose file, staking keys, chain
+configs, genesis) and the data of every node, then starts it again.
+
+$ subnet-cli local snapshot save deployed --devnet-dir=devnet
+
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: localSnapshotSaveFunc,
+	}
+}
+
+func newLocalSnapshotLoadCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "load <name>",
+		Short: "Restores the devnet files and node data",
+		Long: `
+Replaces the devnet (and the data of every node) with a saved snapshot
+and starts it, instead of redeploying genesis and validators.
+
+$ subnet-cli local snapshot load deployed --devnet-dir=devnet
+
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: localSnapshotLoadFunc,
+	}
+}
+
+func localSnapshotSaveFunc(cmd *cobra.Command, args []string) error {
+	p := filepath.Join(snapshotDir, args[0])
+	color.Outf("{{blue}}saving %s to %s (the devnet restarts){{/}}\n", devnetDir, p)
+	ctx, cancel := context.WithTimeout(cmd.Context(), snapshotTimeout)
+	defer cancel()
+	if err := devnet.SaveSnapshot(ctx, devnetDir, p); err != nil {
+		return fmt.Errorf("%w: remove %s before retrying", err, p)
+	}
+	color.Outf("{{green}}saved snapshot %q{{/}}\n", args[0])
+	return nil
+}
+
+func localSnapshotLoadFunc(cmd *cobra.Command, args []string) error {
+	p := filepath.Join(snapshotDir, args[0])
+	color.Outf("{{blue}}loading %s into %s{{/}}\n", p, devnetDir)
+	ctx, cancel := context.WithTimeout(cmd.Context(), snapshotTimeout)
+	defer cancel()
+	if err := devnet.LoadSnapshot(ctx, p, devnetDir); err != nil {
+		return err
+	}
+	color.Outf("{{green}}loaded snapshot %q{{/}}\n", args[0])
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/dustin/go-humanize"
+	"github.com/manifoldco/promptui"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const defaultMainnetSpendThreshold = 10 * units.Avax
+
+// spend returns the total amount of nano-AVAX the operation will consume
+// (fees and stake).
+func (i *Info) spend() uint64 {
+	if i.requiredBalance > i.txFee {
+		return i.requiredBalance
+	}
+	return i.txFee
+}
+
+// CheckMainnetSpend requires an explicit confirmation whenever the connected
+// network is mainnet and the operation spends more than the configured
+// threshold. The operator either passes "--i-am-sure-mainnet" or types the
+// exact spend amount at the prompt.
+func (i *Info) CheckMainnetSpend() error {
+	if i.networkID != constants.MainnetID {
+		return nil
+	}
+	spend := i.spend()
+	if spend <= mainnetSpendThreshold {
+		return nil
+	}
+	amount := humanize.FormatFloat("#,###.#########", float64(spend)/float64(units.Avax))
+	if iAmSureMainnet {
+		color.Outf("{{red}}{{bold}}spending %s AVAX on MAINNET (--i-am-sure-mainnet set){{/}}\n", amount)
+		return nil
+	}
+	if !enablePrompt {
+		color.Outf("{{red}}refusing to spend %s AVAX on MAINNET without --i-am-sure-mainnet{{/}}\n", amount)
+		return fmt.Errorf("%w: spending %d nAVAX", ErrMainnetNotConfirmed, spend)
+	}
+
+	expected := strings.ReplaceAll(amount, ",", "")
+	color.Outf("\n{{red}}{{bold}}You are about to spend %s AVAX on MAINNET (%s).{{/}}\n", amount, i.uri)
+	prompt := promptui.Prompt{
+		Label:  fmt.Sprintf("Type %q to confirm", expected),
+		Stdout: os.Stdout,
+	}
+	typed, err := prompt.Run()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMainnetNotConfirmed, err)
+	}
+	if strings.ReplaceAll(strings.TrimSpace(typed), ",", "") != expected {
+		return fmt.Errorf("%w: typed %q, expected %q", ErrMainnetNotConfirmed, typed, expected)
+	}
+	return nil
+}
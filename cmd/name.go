@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var nameNetworkName string
+
+// NameCommand implements "subnet-cli name" command.
+func NameCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "name",
+		Short: "Sub-commands for naming subnets and blockchains",
+	}
+	cmd.AddCommand(
+		newNameSetCommand(),
+		newNameListCommand(),
+	)
+	return cmd
+}
+
+var (
+	registryOnce sync.Once
+	registry     *names.Registry
+	registryErr  error
+
+	vmRegistryOnce sync.Once
+	vmRegistry     *names.Registry
+	vmRegistryErr  error
+)
+
+// nameRegistry returns the registry at "--names-path", loaded once per
+// invocation.
+func nameRegistry() (*names.Registry, error) {
+	registryOnce.Do(func() {
+		registry, registryErr = names.Load(namesPath)
+		if registryErr != nil {
+			zap.L().Warn("failed to load names", zap.String("path", namesPath), zap.Error(registryErr))
+		}
+	})
+	return registry, registryErr
+}
+
+// namedID formats [id] with its name, if any (e.g., "my-subnet (2Z3b...)").
+// Names are only decoration, so a broken names file is ignored.
+func namedID(networkID uint32, kind names.Kind, id ids.ID) string {
+	reg, err := nameRegistry()
+	if err != nil {
+		return id.String()
+	}
+	if name, ok := reg.Name(networkID, kind, id); ok {
+		return fmt.Sprintf("%s (%s)", name, id)
+	}
+	return id.String()
+}
+
+// recordName names [id] after it was created; a failure is only reported,
+// since the resource exists either way.
+func recordName(networkID uint32, kind names.Kind, name string, id ids.ID) {
+	reg, err := nameRegistry()
+	if err == nil {
+		err = reg.Set(networkID, kind, name, id)
+	}
+	if err == nil {
+		err = reg.Save()
+	}
+	if err != nil {
+		color.Outf("{{yellow}}failed to record %s name %q: %v{{/}}\n", kind, name, err)
+	}
+}
+
+// vmRegistries returns the registries of VM names: the shared one at
+// "--vm-registry-path", if set, and the local one at "--names-path".
+func vmRegistries() ([]*names.Registry, error) {
+	local, err := nameRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if vmRegistryPath == "" {
+		return []*names.Registry{local}, nil
+	}
+	vmRegistryOnce.Do(func() {
+		vmRegistry, vmRegistryErr = names.Load(vmRegistryPath)
+	})
+	if vmRegistryErr != nil {
+		return nil, vmRegistryErr
+	}
+	return []*names.Registry{vmRegistry, local}, nil
+}
+
+// lookupVM returns the VM ID named [name].
+func lookupVM(name string) (ids.ID, error) {
+	regs, err := vmRegistries()
+	if err != nil {
+		return ids.Empty, err
+	}
+	for _, reg := range regs {
+		if id, ok := reg.Lookup(names.VMNetworkID, names.KindVM, name); ok {
+			return id, nil
+		}
+	}
+	return ids.Empty, fmt.Errorf("%w: VM %q (use \"create VMID\" or \"name set vm\")", ErrUnknownName, name)
+}
+
+// registerVM names VM [id] as [name] in the shared registry, if any, or
+// the local one. Reusing a name or ID of another VM is refused, unless
+// "--force" is set.
+func registerVM(name string, id ids.ID) error {
+	regs, err := vmRegistries()
+	if err != nil {
+		return err
+	}
+	for _, reg := range regs {
+		if err := reg.CheckVM(name, id); err != nil {
+			if !force {
+				return fmt.Errorf("%w (use --force to override)", err)
+			}
+			color.Outf("{{yellow}}%v (--force set){{/}}\n", err)
+		}
+	}
+	reg := regs[0]
+	if err := reg.Set(names.VMNetworkID, names.KindVM, name, id); err != nil {
+		return err
+	}
+	return reg.Save()
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newNameListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists named subnets and blockchains",
+		Long: `
+Lists the names in "--names-path", and the VM names in "--vm-registry-path"
+if set.
+
+$ subnet-cli name list
+
+`,
+		Args: cobra.NoArgs,
+		RunE: nameListFunc,
+	}
+	return cmd
+}
+
+func nameListFunc(cmd *cobra.Command, args []string) error {
+	reg, err := nameRegistry()
+	if err != nil {
+		return err
+	}
+	entries := reg.Entries
+	if vmRegistryPath != "" {
+		regs, err := vmRegistries()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, regs[0].Entries...)
+	}
+	if len(entries) == 0 {
+		color.Outf("{{yellow}}no names in %q{{/}}\n", namesPath)
+		return nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"network", "kind", "name", "id"})
+	for _, e := range entries {
+		network := constants.NetworkName(e.NetworkID)
+		if e.Kind == names.KindVM {
+			network = "*"
+		}
+		tb.Append([]string{
+			network,
+			string(e.Kind),
+			color.F("{{light-gray}}{{bold}}%s{{/}}", e.Name),
+			e.ID.String(),
+		})
+	}
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
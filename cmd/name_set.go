@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrUnknownKind = errors.New("unknown kind")
+	ErrUnknownName = errors.New("unknown name")
+)
+
+func newNameSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [subnet|blockchain|vm] [ID] [NAME]",
+		Short: "Names a subnet, blockchain or VM",
+		Long: `
+Names a subnet, blockchain or VM, so tables show the name alongside its ID.
+Names are stored in "--names-path" per network. VM names are the same on
+every network, and are stored in "--vm-registry-path" if set.
+
+$ subnet-cli name set subnet 24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1 my-subnet \
+--network-name=fuji
+
+$ subnet-cli name set vm srEXiWaHuhNyGwPUi444Tu47ZEDwxTWrbQiuD7FmgSAQ6X7Dy subnetevm
+
+`,
+		Args: cobra.ExactArgs(3),
+		RunE: nameSetFunc,
+	}
+	cmd.PersistentFlags().StringVar(&nameNetworkName, "network-name", "fuji", "network the ID belongs to (e.g., fuji, mainnet)")
+	return cmd
+}
+
+func parseKind(s string) (names.Kind, error) {
+	switch k := names.Kind(s); k {
+	case names.KindSubnet, names.KindBlockchain, names.KindVM:
+		return k, nil
+	}
+	return "", fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrUnknownKind, s, names.KindSubnet, names.KindBlockchain, names.KindVM)
+}
+
+func nameSetFunc(cmd *cobra.Command, args []string) error {
+	kind, err := parseKind(args[0])
+	if err != nil {
+		return err
+	}
+	id, err := ids.FromString(args[1])
+	if err != nil {
+		return err
+	}
+	if kind == names.KindVM {
+		if err := registerVM(args[2], id); err != nil {
+			return err
+		}
+		color.Outf("{{green}}named VM %s %q{{/}}\n", id, args[2])
+		return nil
+	}
+	networkID, err := constants.NetworkID(nameNetworkName)
+	if err != nil {
+		return err
+	}
+	reg, err := nameRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Set(networkID, kind, args[2], id); err != nil {
+		return err
+	}
+	if err := reg.Save(); err != nil {
+		return err
+	}
+	color.Outf("{{green}}named %s %s %q on %s{{/}}\n", kind, id, args[2], nameNetworkName)
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/client"
+)
+
+// networkParams are the staking constants of the connected network,
+// fetched once per command (see [loadNetworkParams]) and threaded through
+// [Info] instead of re-queried by each check.
+type networkParams struct {
+	minValidatorStake uint64
+	maxValidatorStake uint64
+	minDelegatorStake uint64
+	// minDelegationFee is the lowest delegation fee primary network
+	// validators can charge, in parts per million
+	minDelegationFee uint32
+	minStakeDuration time.Duration
+	maxStakeDuration time.Duration
+}
+
+// loadNetworkParams fetches the minimum stakes from the node, falling back
+// to the genesis defaults of [networkID] if it does not serve them. The
+// API does not expose the other parameters, so they always come from the
+// genesis.
+func loadNetworkParams(ctx context.Context, cli client.Client, networkID uint32) networkParams {
+	cfg := genesis.GetStakingConfig(networkID)
+	params := networkParams{
+		minValidatorStake: cfg.MinValidatorStake,
+		maxValidatorStake: cfg.MaxValidatorStake,
+		minDelegatorStake: cfg.MinDelegatorStake,
+		minDelegationFee:  cfg.MinDelegationFee,
+		minStakeDuration:  cfg.MinStakeDuration,
+		maxStakeDuration:  cfg.MaxStakeDuration,
+	}
+	minValidator, minDelegator, err := cli.P().Client().GetMinStake(ctx)
+	if err != nil {
+		zap.L().Warn("failed to fetch min stake, using genesis defaults", zap.Error(err))
+		return params
+	}
+	params.minValidatorStake, params.minDelegatorStake = minValidator, minDelegator
+	return params
+}
+
+// cachedFees memoizes the fees of [client.FeeCalculator], so every check
+// of a command prices a tx type once.
+type cachedFees struct {
+	fees client.FeeCalculator
+
+	mu     sync.Mutex
+	cached map[feeKey]uint64
+}
+
+type feeKey struct {
+	txType client.TxType
+	size   int
+}
+
+var _ client.FeeCalculator = &cachedFees{}
+
+func newCachedFees(fees client.FeeCalculator) *cachedFees {
+	return &cachedFees{fees: fees, cached: make(map[feeKey]uint64)}
+}
+
+func (c *cachedFees) Fee(ctx context.Context, txType client.TxType, size int) (uint64, error) {
+	k := feeKey{txType: txType, size: size}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fee, ok := c.cached[k]; ok {
+		return fee, nil
+	}
+	fee, err := c.fees.Fee(ctx, txType, size)
+	if err != nil {
+		return 0, err
+	}
+	c.cached[k] = fee
+	return fee, nil
+}
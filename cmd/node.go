@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NodeCommand implements "subnet-cli node" command.
+func NodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Sub-commands for provisioning validator nodes",
+	}
+	cmd.AddCommand(
+		newNodeSetupCommand(),
+		newNodeCreateCommand(),
+		newNodeKubeCommand(),
+		newNodeKeygenCommand(),
+		newNodeIDCommand(),
+	)
+	return cmd
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const launchTimeout = 10 * time.Minute
+
+var (
+	cloudName          string
+	cloudTemplatePath  string
+	nodeCount          int
+	nodeName           string
+	addValidatorsAfter bool
+)
+
+func newNodeCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [flags] [-- add validator flags]",
+		Short: "Launches validator instances in a cloud and provisions avalanchego",
+		Long: `
+Launches validator instances from a cloud template (using the "aws" or
+"gcloud" CLI and its configured credentials), installs and configures
+avalanchego on them over SSH and prints the resulting NodeIDs.
+
+With --add-validator, the NodeIDs are then passed to "add validator";
+flags after "--" are forwarded to it.
+
+$ subnet-cli node create \
+--cloud=aws \
+--template=aws.yaml \
+--count=2 \
+--ssh-key=~/.ssh/validators.pem \
+--add-validator \
+-- \
+--private-key-path=.subnet-cli.pk \
+--stake-amount=2000avax \
+--duration=14d
+
+`,
+		RunE: nodeCreateFunc,
+	}
+	cmd.PersistentFlags().StringVar(&cloudName, "cloud", "aws", "cloud to launch instances in (aws or gcp)")
+	cmd.PersistentFlags().StringVar(&cloudTemplatePath, "template", "", "cloud template file path")
+	cmd.PersistentFlags().IntVar(&nodeCount, "count", 1, "number of instances to launch")
+	cmd.PersistentFlags().StringVar(&nodeName, "name", "subnet-validator", "instance name (prefix)")
+	cmd.PersistentFlags().BoolVar(&addValidatorsAfter, "add-validator", false, "add the new nodes as primary network validators")
+	addNodeSetupFlags(cmd)
+	return cmd
+}
+
+func nodeCreateFunc(cmd *cobra.Command, args []string) error {
+	t, err := node.LoadTemplate(cloudTemplatePath)
+	if err != nil {
+		return err
+	}
+	if t.SSHUser != "" && !cmd.Flags().Changed("ssh-user") {
+		sshUser = t.SSHUser
+	}
+	l, err := node.NewLauncher(cloudName, t)
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{blue}}launching %d instance(s) on %s{{/}}\n", nodeCount, cloudName)
+	ctx, cancel := context.WithTimeout(cmd.Context(), launchTimeout)
+	instances, err := l.Launch(ctx, nodeName, nodeCount)
+	cancel()
+	if err != nil {
+		return err
+	}
+	hosts := make([]string, 0, len(instances))
+	for _, i := range instances {
+		color.Outf("{{magenta}}launched %s at %s{{/}}\n", i.ID, i.PublicIP)
+		hosts = append(hosts, i.PublicIP)
+	}
+
+	results, err := setupNodes(hosts)
+	if err != nil {
+		return err
+	}
+	nodes := printNodeSetupResults(results)
+	if !addValidatorsAfter {
+		return nil
+	}
+
+	println()
+	c := AddCommand()
+	c.SetArgs(append([]string{"validator", fmt.Sprintf("--node-ids=%s", strings.Join(nodes, ","))}, args...))
+	return c.ExecuteContext(cmd.Context())
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	certPaths []string
+
+	ErrEmptyCert = errors.New("empty --cert")
+)
+
+func newNodeIDCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "id",
+		Short: "Prints the NodeID of a staking certificate",
+		Long: `
+Computes the NodeID from a PEM-encoded staking certificate offline, without
+booting the node or calling info.getNodeID.
+
+$ subnet-cli node id --cert ~/.avalanchego/staking/staker.crt
+
+`,
+		RunE: nodeIDFunc,
+	}
+	cmd.PersistentFlags().StringSliceVar(&certPaths, "cert", nil, "staking certificate file paths")
+	return cmd
+}
+
+func nodeIDFunc(cmd *cobra.Command, args []string) error {
+	if len(certPaths) == 0 {
+		return ErrEmptyCert
+	}
+	nodes := make([]string, 0, len(certPaths))
+	for _, p := range certPaths {
+		cert, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		nodeID, err := node.IDFromCert(cert)
+		if err != nil {
+			return fmt.Errorf("%q: %w", p, err)
+		}
+		s := nodeID.PrefixedString(constants.NodeIDPrefix)
+		if len(certPaths) == 1 {
+			color.Outf("%s\n", s)
+			color.Result(s)
+			return nil
+		}
+		color.Outf("{{blue}}%s{{/}} %s\n", p, s)
+		color.Result(s)
+		nodes = append(nodes, s)
+	}
+	color.Outf("\n{{cyan}}--node-ids={{/}}%s\n", strings.Join(nodes, ","))
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	keygenOutputDir string
+	keygenCount     int
+	keygenBLS       bool
+)
+
+func newNodeKeygenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generates staking identities for nodes that do not exist yet",
+		Long: `
+Generates a staker.crt/staker.key (and BLS signer.key) bundle per node and
+prints the resulting NodeIDs, so the nodes can be registered as validators
+before the machines exist. Copy each bundle to the node and point
+avalanchego at it with --staking-tls-cert-file, --staking-tls-key-file and
+--staking-signer-key-file.
+
+$ subnet-cli node keygen --output-dir=staking --count=3
+
+`,
+		RunE: nodeKeygenFunc,
+	}
+	cmd.PersistentFlags().StringVar(&keygenOutputDir, "output-dir", "staking", "directory to write the bundles to (one sub-directory per node if --count > 1)")
+	cmd.PersistentFlags().IntVar(&keygenCount, "count", 1, "number of bundles to generate")
+	cmd.PersistentFlags().BoolVar(&keygenBLS, "bls", true, "also generate a BLS signer key")
+	return cmd
+}
+
+func nodeKeygenFunc(cmd *cobra.Command, args []string) error {
+	if keygenCount < 1 {
+		return fmt.Errorf("invalid --count %d", keygenCount)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"dir", "node id"})
+	nodes := make([]string, 0, keygenCount)
+	for i := 0; i < keygenCount; i++ {
+		dir := keygenOutputDir
+		if keygenCount > 1 {
+			dir = filepath.Join(keygenOutputDir, fmt.Sprintf("node-%d", i+1))
+		}
+		color.Outf("{{yellow}}generating staking bundle in %q...{{/}}\n", dir)
+		b, err := node.NewBundle(keygenBLS)
+		if err != nil {
+			return err
+		}
+		if err := b.Save(dir); err != nil {
+			return err
+		}
+		nodeID := b.NodeID.PrefixedString(constants.NodeIDPrefix)
+		tb.Append([]string{dir, color.F("{{orange}}%s{{/}}", nodeID)})
+		nodes = append(nodes, nodeID)
+		color.Result(nodeID)
+	}
+	tb.Render()
+	color.Print(buf.String())
+	if keygenBLS {
+		color.Outf("{{yellow}}the BLS public keys are reported by info.getNodeID once the nodes run{{/}}\n")
+	}
+	color.Outf("\n{{cyan}}to add the nodes as validators:{{/}}\n$ subnet-cli add validator --node-ids=%s\n", strings.Join(nodes, ","))
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/kube"
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	kubeName         string
+	kubeNamespace    string
+	kubeReplicas     int
+	kubeImage        string
+	kubeVMImage      string
+	kubeVMImagePath  string
+	kubeStorageSize  string
+	kubeStorageClass string
+	kubeFormat       string
+	kubeOutput       string
+)
+
+func newNodeKubeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kube",
+		Short: "Generates Kubernetes manifests or Helm values for validator nodes",
+		Long: `
+Generates a StatefulSet (and headless Service) or Helm values running the
+subnet's validator nodes, with the tracked subnets configured, an init
+container installing the VM binary from an image, and a readiness probe
+that passes once every chain has bootstrapped.
+
+$ subnet-cli node kube \
+--replicas=3 \
+--network-name=fuji \
+--track-subnets=24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1 \
+--vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH \
+--vm-image=example/spacesvm:v0.0.1 \
+--vm-image-path=/spacesvm \
+--format=manifests \
+--output=validators.yaml
+
+`,
+		RunE: nodeKubeFunc,
+	}
+	cmd.PersistentFlags().StringVar(&kubeName, "name", kube.DefaultName, "StatefulSet name")
+	cmd.PersistentFlags().StringVar(&kubeNamespace, "namespace", "", "namespace")
+	cmd.PersistentFlags().IntVar(&kubeReplicas, "replicas", 1, "number of validator nodes")
+	cmd.PersistentFlags().StringVar(&kubeImage, "image", kube.DefaultImage, "avalanchego image")
+	cmd.PersistentFlags().StringVar(&avalanchegoVersion, "avalanchego-version", node.DefaultVersion, "avalanchego image tag")
+	cmd.PersistentFlags().StringVar(&nodeNetworkName, "network-name", "fuji", "network the nodes join")
+	cmd.PersistentFlags().StringSliceVar(&trackSubnets, "track-subnets", nil, "subnet IDs the nodes track")
+	cmd.PersistentFlags().StringVar(&vmIDs, "vm-id", "", "VM ID of the plugin")
+	cmd.PersistentFlags().StringVar(&kubeVMImage, "vm-image", "", "image containing the VM binary (requires --vm-id)")
+	cmd.PersistentFlags().StringVar(&kubeVMImagePath, "vm-image-path", "", "path of the VM binary inside --vm-image")
+	cmd.PersistentFlags().StringVar(&kubeStorageSize, "storage-size", kube.DefaultStorageSize, "persistent volume size")
+	cmd.PersistentFlags().StringVar(&kubeStorageClass, "storage-class", "", "persistent volume storage class")
+	cmd.PersistentFlags().StringVar(&kubeFormat, "format", "manifests", "output format (manifests or helm)")
+	cmd.PersistentFlags().StringVar(&kubeOutput, "output", "", "output file path (default to stdout)")
+	return cmd
+}
+
+func nodeKubeFunc(cmd *cobra.Command, args []string) error {
+	cfg := kube.Config{
+		Name:         kubeName,
+		Namespace:    kubeNamespace,
+		Replicas:     kubeReplicas,
+		Image:        kubeImage,
+		Version:      avalanchegoVersion,
+		NetworkID:    nodeNetworkName,
+		TrackSubnets: trackSubnets,
+		StorageSize:  kubeStorageSize,
+		StorageClass: kubeStorageClass,
+	}
+	for _, s := range trackSubnets {
+		if _, err := ids.FromString(s); err != nil {
+			return fmt.Errorf("%w: invalid subnet ID %q", err, s)
+		}
+	}
+	if kubeVMImage != "" {
+		vmID, err := ids.FromString(vmIDs)
+		if err != nil {
+			return fmt.Errorf("%w: --vm-id is required with --vm-image", err)
+		}
+		cfg.VMID = vmID
+		cfg.VMImage = kubeVMImage
+		cfg.VMImagePath = kubeVMImagePath
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	switch kubeFormat {
+	case "manifests":
+		b, err = kube.Manifests(cfg)
+	case "helm":
+		b, err = kube.HelmValues(cfg)
+	default:
+		return fmt.Errorf("unknown format %q (expected manifests or helm)", kubeFormat)
+	}
+	if err != nil {
+		return err
+	}
+	if kubeOutput == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	if err := ioutil.WriteFile(kubeOutput, b, 0o644); err != nil {
+		return err
+	}
+	color.Outf("{{green}}wrote %s to %s{{/}}\n", kubeFormat, kubeOutput)
+	return nil
+}
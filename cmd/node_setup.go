@@ -0,0 +1,224 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	sshHosts              []string
+	sshUser               string
+	sshKeyPath            string
+	sshInsecureHostKey    bool
+	avalanchegoVersion    string
+	nodeArch              string
+	nodeNetworkName       string
+	trackSubnets          []string
+	vmBinaryPath          string
+	nodeSystemd           bool
+	nodeBootstrapDeadline time.Duration
+
+	ErrEmptySSHHosts = errors.New("empty --ssh hosts")
+)
+
+func newNodeSetupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Installs and configures avalanchego on remote hosts over SSH",
+		Long: `
+Installs avalanchego on each host, configures the tracked subnets,
+installs the VM plugin, starts the node (as a systemd service) and
+prints the resulting NodeID (and BLS public key, if any).
+
+$ subnet-cli node setup \
+--ssh=10.0.0.1,10.0.0.2 \
+--ssh-user=ubuntu \
+--ssh-key=~/.ssh/id_rsa \
+--network-name=fuji \
+--track-subnets=24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1 \
+--vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH \
+--vm-binary=./build/spacesvm
+
+`,
+		RunE: nodeSetupFunc,
+	}
+	cmd.PersistentFlags().StringSliceVar(&sshHosts, "ssh", nil, "hosts to provision ('host' or 'host:port')")
+	addNodeSetupFlags(cmd)
+	return cmd
+}
+
+// addNodeSetupFlags adds the flags shared by commands that provision nodes.
+func addNodeSetupFlags(cmd *cobra.Command) {
+	home, _ := os.UserHomeDir()
+	cmd.PersistentFlags().StringVar(&sshUser, "ssh-user", "ubuntu", "SSH user")
+	cmd.PersistentFlags().StringVar(&sshKeyPath, "ssh-key", filepath.Join(home, ".ssh", "id_rsa"), "SSH private key path")
+	cmd.PersistentFlags().BoolVar(&sshInsecureHostKey, "insecure-ignore-host-key", false, "skip host key verification against ~/.ssh/known_hosts")
+	cmd.PersistentFlags().StringVar(&avalanchegoVersion, "avalanchego-version", node.DefaultVersion, "avalanchego release to install")
+	cmd.PersistentFlags().StringVar(&nodeArch, "arch", "amd64", "remote host architecture")
+	cmd.PersistentFlags().StringVar(&nodeNetworkName, "network-name", "fuji", "network the node joins")
+	cmd.PersistentFlags().StringSliceVar(&trackSubnets, "track-subnets", nil, "subnet IDs the node tracks")
+	cmd.PersistentFlags().StringVar(&vmIDs, "vm-id", "", "VM ID of the plugin (requires --vm-binary)")
+	cmd.PersistentFlags().StringVar(&vmBinaryPath, "vm-binary", "", "local VM plugin binary to install")
+	cmd.PersistentFlags().BoolVar(&nodeSystemd, "systemd", true, "install and (re)start avalanchego as a systemd service")
+	cmd.PersistentFlags().DurationVar(&nodeBootstrapDeadline, "wait-timeout", 5*time.Minute, "how long to wait for SSH and the node API to come up")
+}
+
+type nodeSetupResult struct {
+	host         string
+	nodeID       string
+	blsPublicKey string
+}
+
+func nodeSetupFunc(cmd *cobra.Command, args []string) error {
+	if len(sshHosts) == 0 {
+		return ErrEmptySSHHosts
+	}
+	results, err := setupNodes(sshHosts)
+	if err != nil {
+		return err
+	}
+	printNodeSetupResults(results)
+	return nil
+}
+
+// setupNodes provisions every host in [hosts].
+func setupNodes(hosts []string) ([]nodeSetupResult, error) {
+	var vmBinary []byte
+	if vmBinaryPath != "" {
+		vmID, err := ids.FromString(vmIDs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: --vm-id is required with --vm-binary", err)
+		}
+		vmIDs = vmID.String()
+		vmBinary, err = ioutil.ReadFile(vmBinaryPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range trackSubnets {
+		if _, err := ids.FromString(s); err != nil {
+			return nil, fmt.Errorf("%w: invalid subnet ID %q", err, s)
+		}
+	}
+
+	results := make([]nodeSetupResult, 0, len(hosts))
+	for _, host := range hosts {
+		res, err := setupNode(host, vmBinary)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// printNodeSetupResults prints the provisioned NodeIDs and returns them.
+func printNodeSetupResults(results []nodeSetupResult) []string {
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"host", "node id", "bls public key"})
+	nodes := make([]string, 0, len(results))
+	for _, r := range results {
+		tb.Append([]string{r.host, color.F("{{orange}}%s{{/}}", r.nodeID), r.blsPublicKey})
+		nodes = append(nodes, r.nodeID)
+	}
+	tb.Render()
+	color.Print(buf.String())
+	color.Outf("\n{{cyan}}to add the nodes as validators:{{/}}\n$ subnet-cli add validator --node-ids=%s\n", strings.Join(nodes, ","))
+	return nodes
+}
+
+func setupNode(host string, vmBinary []byte) (res nodeSetupResult, err error) {
+	res.host = host
+	color.Outf("{{blue}}connecting to %s@%s{{/}}\n", sshUser, host)
+	deadline := time.Now().Add(nodeBootstrapDeadline)
+	var s *node.SSH
+	for {
+		// freshly launched machines take a while to accept connections
+		s, err = node.DialSSH(host, sshUser, sshKeyPath, node.WithInsecureHostKey(sshInsecureHostKey))
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return res, err
+		}
+		time.Sleep(pollInterval * 5)
+	}
+	defer s.Close()
+
+	out, err := s.Run("echo $HOME")
+	if err != nil {
+		return res, err
+	}
+	home := strings.TrimSpace(string(out))
+
+	color.Outf("{{blue}}installing avalanchego %s on %s{{/}}\n", avalanchegoVersion, host)
+	if _, err := s.Run(node.InstallScript(avalanchegoVersion, nodeArch)); err != nil {
+		return res, err
+	}
+
+	cfg, err := node.Config(avalanchegoVersion, home, nodeNetworkName, trackSubnets)
+	if err != nil {
+		return res, err
+	}
+	if err := s.Upload(home+"/"+node.ConfigPath, cfg, 0o644); err != nil {
+		return res, err
+	}
+	color.Outf("{{magenta}}configured %s to track %d subnet(s){{/}}\n", host, len(trackSubnets))
+
+	if vmBinary != nil {
+		if err := s.Upload(home+"/"+node.PluginDir+"/"+vmIDs, vmBinary, 0o755); err != nil {
+			return res, err
+		}
+		color.Outf("{{magenta}}installed VM plugin %s on %s{{/}}\n", vmIDs, host)
+	}
+
+	if nodeSystemd {
+		if err := s.Upload("/tmp/avalanchego.service", []byte(node.SystemdUnit(sshUser, home)), 0o644); err != nil {
+			return res, err
+		}
+		if _, err := s.Run("sudo mv /tmp/avalanchego.service /etc/systemd/system/avalanchego.service && sudo systemctl daemon-reload && sudo systemctl enable avalanchego && sudo systemctl restart avalanchego"); err != nil {
+			return res, err
+		}
+		color.Outf("{{magenta}}started avalanchego on %s{{/}}\n", host)
+	}
+
+	color.Outf("{{yellow}}waiting for the node API on %s...{{/}}\n", host)
+	deadline = time.Now().Add(nodeBootstrapDeadline)
+	req := fmt.Sprintf("curl -sSf -X POST --data '%s' -H 'content-type:application/json;' 127.0.0.1:9650/ext/info", node.NodeIDRequest)
+	for {
+		out, err = s.Run(req)
+		if err == nil {
+			res.nodeID, res.blsPublicKey, err = node.ParseNodeIDResponse(out)
+			if err == nil {
+				return res, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return res, err
+		}
+		time.Sleep(pollInterval * 5)
+	}
+}
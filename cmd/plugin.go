@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/plugin"
+	"github.com/ava-labs/subnet-cli/internal/version"
+)
+
+// PluginCommand implements "subnet-cli plugin" command.
+func PluginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Sub-commands for plugins",
+		Long: `
+Any executable named "subnet-cli-<name>" on PATH runs as
+"subnet-cli <name> [args]", so VM teams can ship their own commands
+(e.g., "subnet-cli spacesvm claim"). Built-in commands take precedence.
+
+Plugins inherit the environment plus:
+  SUBNET_CLI             path of the running subnet-cli (to call back into
+                         it, e.g., "$SUBNET_CLI --quiet create subnet")
+  SUBNET_CLI_VERSION     subnet-cli version
+  SUBNET_CLI_NAMES_PATH  default names file (see "subnet-cli name")
+
+Global flags (e.g., --quiet) are passed to the plugin as arguments.
+
+Plugins written in Go can import the "pkg/subnet", "client" and "pkg/color"
+packages to share the API client and output style.
+
+$ subnet-cli plugin list
+
+`,
+	}
+	cmd.AddCommand(
+		newPluginListCommand(),
+	)
+	return cmd
+}
+
+// findPlugin returns the plugin to run for [args], if the first argument
+// is not a built-in command of [root].
+func findPlugin(root *cobra.Command, args []string) (plugin.Plugin, bool) {
+	if len(args) == 0 {
+		return plugin.Plugin{}, false
+	}
+	if c, _, err := root.Find(args); err == nil && c != root {
+		return plugin.Plugin{}, false
+	}
+	return plugin.Find(args[0])
+}
+
+// runPlugin runs [p] with the remaining arguments, which are not parsed
+// as flags of subnet-cli.
+func runPlugin(ctx context.Context, p plugin.Plugin, args []string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	env := map[string]string{
+		plugin.EnvBinary:    self,
+		plugin.EnvVersion:   version.Version,
+		plugin.EnvNamesPath: namesPath,
+	}
+	zap.L().Debug("running plugin", zap.String("name", p.Name), zap.String("path", p.Path))
+	return p.Run(ctx, args, env)
+}
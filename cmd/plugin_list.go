@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/plugin"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+	"github.com/ava-labs/subnet-cli/pkg/genesis"
+)
+
+func newPluginListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the plugins on PATH",
+		Long: `
+Lists the "subnet-cli-<name>" executables on PATH. Plugins shadowed by a
+built-in command are marked and never run.
+
+$ subnet-cli plugin list
+
+`,
+		Args: cobra.NoArgs,
+		RunE: pluginListFunc,
+	}
+	return cmd
+}
+
+func pluginListFunc(cmd *cobra.Command, args []string) error {
+	plugins := []plugin.Plugin{}
+	for _, p := range plugin.List(os.Getenv("PATH")) {
+		// genesis plugins serve "subnet-cli genesis", not commands
+		if !strings.HasPrefix(plugin.Prefix+p.Name, genesis.PluginPrefix) {
+			plugins = append(plugins, p)
+		}
+	}
+	if len(plugins) == 0 {
+		color.Outf("{{yellow}}no %q executables on PATH{{/}}\n", plugin.Prefix+"*")
+		return nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"name", "path"})
+	for _, p := range plugins {
+		name := color.F("{{light-gray}}{{bold}}%s{{/}}", p.Name)
+		if _, ok := findPlugin(cmd.Root(), []string{p.Name}); !ok {
+			name = color.F("{{red}}%s (shadowed by built-in command){{/}}", p.Name)
+		}
+		tb.Append([]string{name, p.Path})
+		color.Result(p.Name)
+	}
+	tb.Render()
+	color.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/policy"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// CheckPolicy refuses the operation of [cmd] if it violates the policy in
+// effect (see [policy.Find]). Unlike other safety checks, "--force" does
+// not override it.
+func (i *Info) CheckPolicy(cmd *cobra.Command) error {
+	path := policy.Find(policyPath)
+	if path == "" {
+		return nil
+	}
+	p, err := policy.Load(path)
+	if err != nil {
+		return err
+	}
+	op := policy.Op{
+		Command:      strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Network:      i.networkName,
+		Spend:        i.spend(),
+		WeightChange: i.weightChange,
+	}
+	if err := p.Check(op); err != nil {
+		color.Outf("{{red}}{{bold}}refused by policy %q: %v{{/}}\n", p.Path(), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/ava-labs/subnet-cli/internal/qr"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var showQR bool
+
+// printQR renders [payload] as a terminal QR code if "--qr" is set, e.g., to
+// fund an address from a mobile wallet. Addresses use a higher error
+// correction [level] than large payloads, which must stay scannable.
+func printQR(label string, payload string, level qr.Level) {
+	if !showQR {
+		return
+	}
+	c, err := qr.Encode([]byte(payload), level)
+	if err != nil {
+		color.Outf("{{yellow}}cannot show %s as a QR code: %v{{/}}\n", label, err)
+		return
+	}
+	color.Outf("\n{{blue}}%s{{/}} {{light-gray}}(QR code){{/}}\n", label)
+	color.Print(c.Terminal())
+}
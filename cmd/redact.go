@@ -0,0 +1,29 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+// secretFlags hold credentials, redacted from all output.
+var secretFlags = map[string]struct{}{
+	"auth-token":         {},
+	"auth-password":      {},
+	"fireblocks-api-key": {},
+	"faucet-api-key":     {},
+	"captcha-token":      {},
+}
+
+// registerSecrets redacts the values of the secret flags of [cmd].
+func registerSecrets(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := secretFlags[f.Name]; ok {
+			redact.Register(f.Value.String())
+		}
+	})
+}
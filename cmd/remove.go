@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// RemoveCommand implements "subnet-cli remove" command.
+func RemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Sub-commands for removing resources",
+	}
+	cmd.AddCommand(
+		newRemoveSubnetValidatorCommand(),
+	)
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	addSignerFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// removeSubnetValidatorOptions are the flags of "remove subnet-validator".
+type removeSubnetValidatorOptions struct {
+	subnetID string
+	nodeIDs  []string
+}
+
+func newRemoveSubnetValidatorCommand() *cobra.Command {
+	o := &removeSubnetValidatorOptions{}
+	cmd := &cobra.Command{
+		Use:   "subnet-validator",
+		Short: "Removes validators from a subnet",
+		Long: `
+Removes validators from a permissioned subnet before their validation
+ends, with a RemoveSubnetValidatorTx each, which the subnet control keys
+sign.
+
+RemoveSubnetValidatorTx needs the Banff upgrade; the command fails on
+networks that have not activated it.
+
+$ subnet-cli remove subnet-validator \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=http://localhost:52250 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--node-ids="NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH"
+
+`,
+		RunE: o.run,
+	}
+
+	cmd.PersistentFlags().StringVar(&o.subnetID, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringSliceVar(&o.nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
+	addFailureReportFlag(cmd)
+
+	return cmd
+}
+
+func (o *removeSubnetValidatorOptions) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(o.subnetID)
+	if err != nil {
+		return err
+	}
+	if err := info.requireUpgrade(version.Banff, "RemoveSubnetValidatorTx"); err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	weights := make(map[ids.ShortID]uint64, len(o.nodeIDs))
+	for _, rnodeID := range o.nodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(rnodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		weight, ok := ws[nodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s is not validating %s", client.ErrValidatorNotFound, rnodeID, info.subnetID)
+		}
+		if _, ok := weights[nodeID]; ok {
+			continue
+		}
+		weights[nodeID] = weight
+		info.nodeIDs = append(info.nodeIDs, nodeID)
+		info.weightChange += weight
+	}
+	if len(info.nodeIDs) == 0 {
+		color.Outf("{{magenta}}no subnet validators to remove{{/}}\n")
+		return nil
+	}
+
+	info.txFee, err = info.Fee(ctx, client.TxTypeRemoveSubnetValidator, len(info.nodeIDs))
+	if err != nil {
+		return err
+	}
+	info.requiredBalance = info.txFee
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	for _, nodeID := range info.nodeIDs {
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", nodeID.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}{{bold}}weight %s{{/}}", humanize.Comma(int64(weights[nodeID]))),
+		})
+	}
+	tb.Render()
+	diff, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(buf.String(), diff, "remove subnet validators", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+
+	println()
+	b := new(batch)
+	if err := b.add(ctx, info, client.TxTypeRemoveSubnetValidator, len(info.nodeIDs), 0); err != nil {
+		return err
+	}
+	r := rollback.New("remove subnet-validator", info.networkID)
+	for _, nodeID := range info.nodeIDs {
+		r.Plan(nodeAction(rollback.KindRemoveSubnetValidator, nodeID))
+	}
+	for _, nodeID := range info.nodeIDs {
+		if err := b.check(ctx, cli, info); err != nil {
+			return reportRollback(info, r, err)
+		}
+		took, err := cli.P().RemoveSubnetValidator(ctx, info.key, info.subnetID, nodeID)
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		b.done()
+		a := nodeAction(rollback.KindRemoveSubnetValidator, nodeID)
+		a.SubnetID = info.subnetID.String()
+		a.Weight = weights[nodeID]
+		r.Complete(a)
+		if err := info.RecordApproval(); err != nil {
+			return reportRollback(info, r, err)
+		}
+		color.Outf("{{magenta}}removed %s from subnet %s{{/}} {{light-gray}}(took %v){{/}}\n", nodeID, info.subnetID, took)
+		color.Result(nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	return nil
+}
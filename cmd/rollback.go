@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var failureReportPath string
+
+// addFailureReportFlag adds "--failure-report" to a multi-tx command.
+func addFailureReportFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&failureReportPath, "failure-report", "", "file to write a JSON report to if the command fails midway (txs issued, txs remaining and how to undo or resume them)")
+}
+
+// nodeAction returns the [kind] action of [nodeID].
+func nodeAction(kind string, nodeID ids.ShortID) rollback.Action {
+	return rollback.Action{Kind: kind, NodeID: nodeID.PrefixedString(constants.NodeIDPrefix)}
+}
+
+// validatorAction returns the [kind] action of [nodeID] validating until
+// [end].
+func validatorAction(kind string, nodeID ids.ShortID, end time.Time) rollback.Action {
+	a := nodeAction(kind, nodeID)
+	end = end.UTC()
+	a.End = &end
+	return a
+}
+
+// reportRollback returns [err] after, if it is set, filling in how to undo
+// each action of [r] that was done and how to resume the remaining ones,
+// printing them if any tx was issued and writing [r] to
+// "--failure-report".
+func reportRollback(i *Info, r *rollback.Report, err error) error {
+	if err == nil {
+		return nil
+	}
+	r.Fail(redact.Error(err), time.Now())
+	for j := range r.Done {
+		compensate(i, &r.Done[j])
+	}
+	for j := range r.Remaining {
+		resume(i, &r.Remaining[j])
+	}
+	if len(r.Done) > 0 {
+		color.Outf("\n{{red}}%s failed midway: %d txs issued, %d remaining{{/}}\n", r.Operation, len(r.Done), len(r.Remaining))
+		color.Print(rollbackTable(r))
+	}
+	if failureReportPath != "" {
+		if serr := r.Save(failureReportPath); serr != nil {
+			color.Outf("{{red}}failed to write failure report: %v{{/}}\n", serr)
+		} else {
+			color.Outf("{{yellow}}wrote failure report to %q{{/}}\n", failureReportPath)
+		}
+	}
+	return err
+}
+
+// compensate fills in how the done action [a] can be undone. No tx removes
+// a primary network validator or deletes a subnet or blockchain, so the
+// notes say when they wind down on their own; subnet validators can be
+// removed once the connected network activates Banff.
+func compensate(i *Info, a *rollback.Action) {
+	switch a.Kind {
+	case rollback.KindAddValidator:
+		a.Note = "primary network validators cannot be removed"
+		if a.End != nil {
+			a.Note += fmt.Sprintf("; the stake unlocks at %s", a.End.Format(time.RFC3339))
+		}
+	case rollback.KindCreateSubnet:
+		a.Undo = fmt.Sprintf("subnet-cli decommission --public-uri=%s --subnet-id=%s", redact.String(i.uri), a.SubnetID)
+		a.Note = fmt.Sprintf("subnets cannot be deleted; decommission it, or reuse it with --subnet-id=%s", a.SubnetID)
+	case rollback.KindAddSubnetValidator:
+		if i.upgradeActive(version.Banff) {
+			a.Undo = fmt.Sprintf("subnet-cli remove subnet-validator --public-uri=%s --subnet-id=%s --node-ids=%s", redact.String(i.uri), a.SubnetID, a.NodeID)
+			return
+		}
+		a.Note = "subnet validators cannot be removed early without Banff"
+		if a.End != nil {
+			a.Note += fmt.Sprintf("; it stops validating at %s", a.End.Format(time.RFC3339))
+		}
+	case rollback.KindCreateBlockchain:
+		a.Note = "blockchains cannot be deleted; decommission the subnet to retire it"
+	case rollback.KindRemoveSubnetValidator:
+		a.Undo = fmt.Sprintf("subnet-cli add subnet-validator --public-uri=%s --subnet-id=%s --node-ids=%s --validate-weight=%d", redact.String(i.uri), a.SubnetID, a.NodeID, a.Weight)
+		a.Note = "re-adds it until the end of its primary network validation"
+	}
+}
+
+// resume fills in the command that issues the remaining action [a].
+func resume(i *Info, a *rollback.Action) {
+	uri := redact.String(i.uri)
+	if a.SubnetID == "" && i.subnetID != ids.Empty {
+		a.SubnetID = i.subnetID.String()
+	}
+	switch a.Kind {
+	case rollback.KindAddValidator:
+		a.Resume = fmt.Sprintf("subnet-cli add validator --public-uri=%s --node-ids=%s --stake-amount=%s", uri, a.NodeID, amount.Format(i.stakeAmount))
+		if a.End != nil {
+			a.Resume += " --validate-end=" + a.End.Format(time.RFC3339)
+		}
+	case rollback.KindCreateSubnet:
+		a.Resume = fmt.Sprintf("subnet-cli create subnet --public-uri=%s", uri)
+	case rollback.KindAddSubnetValidator:
+		if a.SubnetID == "" {
+			a.Note = "needs the subnet to be created first"
+			return
+		}
+		weight := a.Weight
+		if weight == 0 {
+			weight = i.validateWeight
+		}
+		a.Resume = fmt.Sprintf("subnet-cli add subnet-validator --public-uri=%s --subnet-id=%s --node-ids=%s --validate-weight=%d", uri, a.SubnetID, a.NodeID, weight)
+	case rollback.KindCreateBlockchain:
+		if a.SubnetID == "" {
+			a.Note = "needs the subnet to be created first"
+			return
+		}
+		vmID, genesisPath := a.VMID, a.GenesisPath
+		if vmID == "" {
+			vmID, genesisPath = i.vmID.String(), i.vmGenesisPath
+		}
+		a.Resume = fmt.Sprintf("subnet-cli create blockchain --public-uri=%s --subnet-id=%s --chain-name=%s --vm-id=%s --vm-genesis-path=%s", uri, a.SubnetID, a.Name, vmID, genesisPath)
+	case rollback.KindRemoveSubnetValidator:
+		a.Resume = fmt.Sprintf("subnet-cli remove subnet-validator --public-uri=%s --subnet-id=%s --node-ids=%s", uri, a.SubnetID, a.NodeID)
+	}
+}
+
+func rollbackTable(r *rollback.Report) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"status", "action", "undo / resume"})
+	for _, a := range r.Done {
+		next := a.Note
+		if a.Undo != "" {
+			next = a.Undo + "\n" + a.Note
+		}
+		tb.Append([]string{color.F("{{green}}done{{/}}"), describeAction(a), next})
+	}
+	for _, a := range r.Remaining {
+		next := a.Resume
+		if next == "" {
+			next = a.Note
+		}
+		tb.Append([]string{color.F("{{yellow}}remaining{{/}}"), describeAction(a), next})
+	}
+	tb.Render()
+	return buf.String()
+}
+
+func describeAction(a rollback.Action) string {
+	switch {
+	case a.NodeID != "" && a.SubnetID != "":
+		return fmt.Sprintf("%s %s to %s", a.Kind, a.NodeID, a.SubnetID)
+	case a.NodeID != "":
+		return fmt.Sprintf("%s %s", a.Kind, a.NodeID)
+	case a.BlockchainID != "":
+		return fmt.Sprintf("%s %q (%s)", a.Kind, a.Name, a.BlockchainID)
+	case a.Name != "":
+		return fmt.Sprintf("%s %q", a.Kind, a.Name)
+	case a.SubnetID != "":
+		return fmt.Sprintf("%s %s", a.Kind, a.SubnetID)
+	}
+	return a.Kind
+}
@@ -4,22 +4,164 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
 	"github.com/ava-labs/subnet-cli/pkg/logutil"
 )
 
-var rootCmd = &cobra.Command{
-	Use:        "subnet-cli",
-	Short:      "subnet-cli CLI",
-	SuggestFor: []string{"subnet-cli", "subnetcli", "subnetctl"},
+// rootCmd is the command tree [Execute] runs.
+var rootCmd = NewRootCommand()
+
+// NewRootCommand returns the "subnet-cli" command tree. Its commands create
+// their client and key with the [Factory] of the execution context (see
+// [WithFactory]). Every call returns a fresh tree with default flags, so
+// programs embedding the commands (e.g., a daemon, a TUI or tests) execute
+// one tree per command line.
+func NewRootCommand() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:        "subnet-cli",
+		Short:      "subnet-cli CLI",
+		SuggestFor: []string{"subnet-cli", "subnetcli", "subnetctl"},
+		Version:    version.Version,
+
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyEnv(cmd); err != nil {
+				return err
+			}
+			registerSecrets(cmd)
+			if quietOutput && verbosity > 0 {
+				return ErrQuietVerbose
+			}
+			switch {
+			case quietOutput && !cmd.Flags().Changed("log-level"):
+				logLevel = "warn"
+			case verbosity > 0:
+				logLevel = "debug"
+			}
+			// re-create the logger now that the flags are parsed
+			if err := CreateLogger(); err != nil {
+				return err
+			}
+			color.Setup(noColor)
+			color.SetQuiet(quietOutput)
+			if recordPath != "" && replayPath != "" {
+				return ErrRecordReplay
+			}
+			if recordPath != "" || replayPath != "" {
+				// cached lookups would skip the recorded exchanges
+				noCache = true
+			}
+			if replayPath != "" {
+				if err := client.ReplayHTTP(replayPath); err != nil {
+					return err
+				}
+			}
+			if recordPath != "" {
+				if err := client.RecordHTTP(recordPath); err != nil {
+					return err
+				}
+			}
+			if verbosity > 1 {
+				client.LogHTTP()
+			}
+			if debugHTTPDir != "" {
+				return client.CaptureHTTP(debugHTTPDir)
+			}
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(
+		CreateCommand(),
+		AddCommand(),
+		RemoveCommand(),
+		StatusCommand(),
+		WizardCommand(),
+		WeightsCommand(),
+		SetCommand(),
+		EVMCommand(),
+		FaucetCommand(),
+		SimulateCommand(),
+		ValidateCommand(),
+		DiffCommand(),
+		KeyCommand(),
+		ExportCommand(),
+		ImportCommand(),
+		IndexCommand(),
+		NameCommand(),
+		NodeCommand(),
+		LocalCommand(),
+		TelemetryCommand(),
+		UpdateCommand(),
+		VersionCommand(),
+		PluginCommand(),
+		GenesisCommand(),
+		DecommissionCommand(),
+		CloneCommand(),
+		ConvertCommand(),
+		L1Command(),
+		AddressCommand(),
+		WatchCommand(),
+	)
+
+	rootCmd.PersistentFlags().BoolVar(&enablePrompt, "enable-prompt", true, "'true' to enable prompt mode")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "'true' to only print the changes an operation would make, without issuing txs")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", logutil.DefaultLogLevel, "log level")
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, "print only the final IDs, one per line")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "debug logs with poll traces (-v), plus raw API payloads (-vv)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "'true' to disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "'true' to skip safety checks (e.g., key/network mismatch)")
+	rootCmd.PersistentFlags().BoolVar(&iAmSureMainnet, "i-am-sure-mainnet", false, "'true' to confirm spending above the threshold on mainnet without typed confirmation")
+	mainnetSpendThreshold = defaultMainnetSpendThreshold
+	rootCmd.PersistentFlags().Var((*amountFlag)(&mainnetSpendThreshold), "mainnet-spend-threshold", "spend on mainnet above which explicit confirmation is required (e.g., 10avax)")
+	rootCmd.PersistentFlags().DurationVar(&pollInterval, "poll-interval", time.Second, "interval to poll tx/blockchain status")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 10*time.Minute, "deadline of the whole command, including polling for tx acceptance and waiting for --issue-at (0 for none)")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "request-timeout", 10*time.Minute, "deadline of the whole command")
+	_ = rootCmd.PersistentFlags().MarkDeprecated("request-timeout", "use --timeout, which bounds the whole command")
+	rootCmd.PersistentFlags().StringVar(&proxyURI, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for API requests (e.g., socks5://localhost:1080); defaults to HTTP_PROXY/HTTPS_PROXY")
+	rootCmd.PersistentFlags().StringVar(&tlsCAFile, "tls-ca-file", "", "PEM bundle of additional CAs trusted for API endpoints")
+	rootCmd.PersistentFlags().StringVar(&tlsCertFile, "tls-cert-file", "", "PEM client certificate for mTLS API endpoints")
+	rootCmd.PersistentFlags().StringVar(&tlsKeyFile, "tls-key-file", "", "PEM client key for mTLS API endpoints")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "maximum API requests per second (0 defaults to 5 for public endpoints like api.avax.network and no limit otherwise; negative disables)")
+	rootCmd.PersistentFlags().StringVar(&authToken, "auth-token", "", "API auth token for nodes running with --api-auth-required")
+	rootCmd.PersistentFlags().StringVar(&authPassword, "auth-password", "", "API auth password to request a token with (if --auth-token is not set)")
+	rootCmd.PersistentFlags().BoolVar(&strictVersion, "strict-version", false, "'true' to refuse nodes whose avalanchego version differs from the one subnet-cli was built against")
+	rootCmd.PersistentFlags().StringVar(&expireAts, "expire-at", "", "wall-clock time (RFC3339 or relative, e.g., now+1h) after which no tx is issued, so stale retries fail instead")
+	rootCmd.PersistentFlags().StringVar(&issueAts, "issue-at", "", "wall-clock time (RFC3339 or relative, e.g., now+1h) to wait for after confirmation before issuing txs")
+	rootCmd.PersistentFlags().Uint64Var(&issueAtHeight, "issue-at-height", 0, "P-Chain height to wait for after confirmation before issuing txs")
+	rootCmd.PersistentFlags().StringVar(&policyPath, "policy-path", "", "policy file limiting spend, weight changes and networks (defaults to $SUBNET_CLI_POLICY, then /etc/subnet-cli/policy.yaml if it exists)")
+	rootCmd.PersistentFlags().StringVar(&proposePath, "propose", "", "write the operation to this proposal file signed with the key, instead of issuing it")
+	rootCmd.PersistentFlags().StringVar(&approvePath, "approve", "", "issue the operation only if this proposal file matches it and was signed with another key")
+	rootCmd.PersistentFlags().StringVar(&priceSource, "price-source", "", "AVAX price to show fee and stake amounts in fiat with: a static rate (e.g., 25.5), \"coingecko\" or a CoinGecko-compatible price URL")
+	rootCmd.PersistentFlags().StringVar(&fiatCurrency, "fiat-currency", "usd", "fiat currency of --price-source")
+	rootCmd.PersistentFlags().StringVar(&namesPath, "names-path", names.DefaultPath, "file mapping subnet/blockchain names to IDs")
+	rootCmd.PersistentFlags().StringVar(&vmRegistryPath, "vm-registry-path", "", "shared file mapping VM names to VM IDs across a team (consulted with --names-path)")
+	rootCmd.PersistentFlags().BoolVar(&copyResult, "copy", false, "'true' to copy the final IDs (e.g., the new subnet or blockchain ID) to the system clipboard on success")
+	rootCmd.PersistentFlags().BoolVar(&showQR, "qr", false, "'true' to also print the P-Chain address to fund (or the proposal of --propose) as a terminal QR code")
+	rootCmd.PersistentFlags().StringVar(&debugHTTPDir, "debug-http", "", "directory to record every HTTP request/response to (secrets redacted), for bug reports")
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "cassette file to record every HTTP exchange to (secrets redacted), for replay with --replay")
+	rootCmd.PersistentFlags().StringVar(&replayPath, "replay", "", "cassette file (see --record) to serve every HTTP request from, without a network")
+	return rootCmd
 }
 
 var (
 	enablePrompt bool
 	logLevel     string
+	force        bool
+
+	iAmSureMainnet        bool
+	mainnetSpendThreshold uint64
 
 	privKeyPath string
 	useLedger   bool
@@ -28,20 +170,38 @@ var (
 	publicURI  string
 
 	pollInterval   time.Duration
-	requestTimeout time.Duration
+	commandTimeout time.Duration
+	noCache        bool
+	debugHTTPDir   string
+	recordPath     string
+	replayPath     string
+
+	proxyURI    string
+	tlsCAFile   string
+	tlsCertFile string
+	tlsKeyFile  string
+	rateLimit   float64
+
+	authToken    string
+	authPassword string
+
+	strictVersion bool
 
-	subnetIDs   string
-	nodeIDs     []string
-	stakeAmount uint64
+	policyPath string
+	expireAts  string
 
-	validateEnds             string
-	validateWeight           uint64
-	validateRewardFeePercent uint32
+	priceSource  string
+	fiatCurrency string
 
-	rewardAddrs string
-	changeAddrs string
+	namesPath      string
+	vmRegistryPath string
+
+	noColor     bool
+	quietOutput bool
+	verbosity   int
+
+	subnetIDs string
 
-	chainName     string
 	vmIDs         string
 	vmGenesisPath string
 
@@ -51,23 +211,29 @@ var (
 
 func init() {
 	cobra.EnablePrefixMatching = true
-
-	rootCmd.AddCommand(
-		CreateCommand(),
-		AddCommand(),
-		StatusCommand(),
-		WizardCommand(),
-	)
-
-	rootCmd.PersistentFlags().BoolVar(&enablePrompt, "enable-prompt", true, "'true' to enable prompt mode")
-	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", logutil.DefaultLogLevel, "log level")
-	rootCmd.PersistentFlags().DurationVar(&pollInterval, "poll-interval", time.Second, "interval to poll tx/blockchain status")
-	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 2*time.Minute, "request timeout")
 }
 
 func Execute() error {
 	if err := CreateLogger(); err != nil {
 		return err
 	}
-	return rootCmd.Execute()
+	client.InstallTransport()
+	color.Stdout = redact.Writer(color.Stdout)
+	color.Stderr = redact.Writer(color.Stderr)
+	rootCmd.SetOut(redact.Writer(os.Stdout))
+	rootCmd.SetErr(redact.Writer(os.Stderr))
+	// every request derives from this context, so SIGINT/SIGTERM cancels
+	// in-flight requests and polls instead of leaving them running
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if p, ok := findPlugin(rootCmd, os.Args[1:]); ok {
+		return redact.Error(runPlugin(ctx, p, os.Args[2:]))
+	}
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if err == nil {
+		copyResults(ctx)
+	}
+	reportTelemetry(context.Background(), cmd, time.Since(start), err)
+	return redact.Error(err)
 }
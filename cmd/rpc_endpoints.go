@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// placeholderURI stands in for the API URI of a validator when
+// "--validator-uris" is not set: the connected (public) node does not track
+// the subnet, so it does not serve the new chain.
+const placeholderURI = "http://[VALIDATOR IP]:9650"
+
+var ErrNoValidatorURIs = errors.New("--networks-json requires --validator-uris")
+
+var (
+	validatorURIs   []string
+	tokenSymbol     string
+	networksJSONOut string
+)
+
+// addRPCEndpointFlags registers the flags used by [PrintRPCEndpoints].
+func addRPCEndpointFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSliceVar(&validatorURIs, "validator-uris", nil, "API URIs of the subnet validators to print RPC endpoints for (placeholder URLs if empty)")
+	cmd.PersistentFlags().StringVar(&tokenSymbol, "token-symbol", "TOKEN", "native token symbol for the wallet network config")
+	cmd.PersistentFlags().StringVar(&networksJSONOut, "networks-json", "", "file to write a wallet-importable network config to")
+}
+
+// walletNetwork is the network config accepted by MetaMask's
+// "wallet_addEthereumChain" (EIP-3085).
+type walletNetwork struct {
+	ChainID        string   `json:"chainId"`
+	ChainName      string   `json:"chainName"`
+	NativeCurrency currency `json:"nativeCurrency"`
+	RPCURLs        []string `json:"rpcUrls"`
+}
+
+type currency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// checkRPCEndpointFlags fails before issuing if the flags of
+// [PrintRPCEndpoints] cannot produce a usable wallet config.
+func checkRPCEndpointFlags() error {
+	if networksJSONOut != "" && len(validatorURIs) == 0 {
+		return fmt.Errorf("%w: the connected node does not serve the new chain", ErrNoValidatorURIs)
+	}
+	return nil
+}
+
+// PrintRPCEndpoints prints the RPC endpoint of the new blockchain on every
+// validator and, for subnet-evm genesis, a wallet network config. Without
+// "--validator-uris", it prints placeholder URLs to fill in.
+func PrintRPCEndpoints(i *Info, genesis []byte) error {
+	uris := validatorURIs
+	placeholder := len(uris) == 0
+	if placeholder {
+		uris = []string{placeholderURI}
+	}
+	rpcs := make([]string, len(uris))
+	color.Outf("\n{{blue}}{{bold}}RPC endpoints for %s:{{/}}\n", i.blockchainID)
+	for idx, uri := range uris {
+		rpcs[idx] = EVMRPCURI(strings.TrimSuffix(uri, "/"), i)
+		color.Outf("  {{cyan}}%s{{/}}\n", rpcs[idx])
+	}
+	if placeholder {
+		color.Outf("  {{yellow}}placeholder: replace [VALIDATOR IP] with a validator of the subnet, or pass --validator-uris{{/}}\n")
+	}
+
+	chainID, ok := GenesisChainID(genesis)
+	if !ok {
+		return nil
+	}
+	n := walletNetwork{
+		ChainID:   "0x" + chainID.Text(16),
+		ChainName: i.chainName,
+		NativeCurrency: currency{
+			Name:     tokenSymbol,
+			Symbol:   tokenSymbol,
+			Decimals: 18,
+		},
+		RPCURLs: rpcs,
+	}
+	color.Outf("\n{{blue}}{{bold}}MetaMask network config:{{/}}\n")
+	color.Outf("  {{light-gray}}Network name:{{/}}    %s\n", n.ChainName)
+	color.Outf("  {{light-gray}}New RPC URL:{{/}}     %s\n", rpcs[0])
+	color.Outf("  {{light-gray}}Chain ID:{{/}}        %s\n", chainID)
+	color.Outf("  {{light-gray}}Currency symbol:{{/}} %s\n\n", tokenSymbol)
+
+	if networksJSONOut == "" || placeholder {
+		return nil
+	}
+	b, err := json.MarshalIndent([]walletNetwork{n}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(networksJSONOut, b, 0o644); err != nil { //nolint:gosec
+		return err
+	}
+	color.Outf("{{green}}wrote wallet network config to %q{{/}}\n", networksJSONOut)
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// setWeightOptions are the flags of "set weight".
+type setWeightOptions struct {
+	subnetID string
+	nodeIDs  []string
+	weight   uint64
+}
+
+func newSetWeightCommand() *cobra.Command {
+	o := &setWeightOptions{}
+	cmd := &cobra.Command{
+		Use:   "weight",
+		Short: "Changes the weight of subnet validators",
+		Long: `
+Changes the weight of subnet validators.
+
+A permissioned subnet can't change the weight of a validator in place, so
+each validator is removed with a RemoveSubnetValidatorTx and re-added with
+the new weight right away, until the end of its current subnet validation
+(at most that of its primary network validation). The subnet loses the
+validator's weight between the two txs.
+
+RemoveSubnetValidatorTx needs the Banff upgrade; the command fails on
+networks that have not activated it.
+
+$ subnet-cli set weight \
+--private-key-path=.insecure.ewoq.key \
+--public-uri=http://localhost:52250 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--node-ids="NodeID-4B4rc5vdD1758JSBYL1xyvE5NHGzz6xzH" \
+--validate-weight=2000
+
+`,
+		RunE: o.run,
+	}
+
+	cmd.PersistentFlags().StringVar(&o.subnetID, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringSliceVar(&o.nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
+	cmd.PersistentFlags().Uint64Var(&o.weight, "validate-weight", defaultValidateWeight, "new validate weight")
+	addFailureReportFlag(cmd)
+
+	return cmd
+}
+
+type weightChange struct {
+	nodeID  ids.ShortID
+	current uint64
+	end     time.Time
+}
+
+func (o *setWeightOptions) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitClient(ctx, publicURI, true)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(o.subnetID)
+	if err != nil {
+		return err
+	}
+	if o.weight == 0 {
+		return errZeroValidateWeight
+	}
+	info.validateWeight = o.weight
+	if err := info.requireUpgrade(version.Banff, "RemoveSubnetValidatorTx"); err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]weightChange, 0, len(o.nodeIDs))
+	for _, rnodeID := range o.nodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(rnodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		current, ok := ws[nodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s is not validating %s (use 'add subnet-validator')", client.ErrValidatorNotFound, rnodeID, info.subnetID)
+		}
+		if current == o.weight {
+			color.Outf("{{yellow}}%s already has weight %d{{/}}\n", rnodeID, current)
+			continue
+		}
+		_, end, err := cli.P().GetValidator(ctx, info.subnetID, nodeID)
+		if err != nil {
+			return err
+		}
+		// a subnet validation can't outlast the primary network one
+		_, primaryEnd, err := cli.P().GetValidator(ctx, ids.Empty, nodeID)
+		if err != nil {
+			return err
+		}
+		if primaryEnd.Before(end) {
+			end = primaryEnd
+		}
+		changes = append(changes, weightChange{
+			nodeID:  nodeID,
+			current: current,
+			end:     end,
+		})
+	}
+	if len(changes) == 0 {
+		color.Outf("{{magenta}}no subnet validator weights to change{{/}}\n")
+		return nil
+	}
+
+	removeFee, err := info.Fee(ctx, client.TxTypeRemoveSubnetValidator, len(changes))
+	if err != nil {
+		return err
+	}
+	addFee, err := info.Fee(ctx, client.TxTypeAddSubnetValidator, len(changes))
+	if err != nil {
+		return err
+	}
+	info.txFee = removeFee + addFee
+	info.requiredBalance = info.txFee
+	info.newWeights = make(map[ids.ShortID]uint64, len(changes))
+	for _, c := range changes {
+		info.newWeights[c.nodeID] = o.weight
+		if o.weight > c.current {
+			info.weightChange += o.weight - c.current
+		} else {
+			info.weightChange += c.current - o.weight
+		}
+	}
+	if err := info.CheckBalance(); err != nil {
+		return err
+	}
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	for _, c := range changes {
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", c.nodeID.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}{{bold}}%s → %s{{/}} (until %s)", humanize.Comma(int64(c.current)), humanize.Comma(int64(o.weight)), c.end.Format(time.RFC3339)),
+		})
+	}
+	tb.Render()
+	diff, err := info.stateChanges(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(buf.String(), diff, "change subnet validator weights", "I agree to pay the fee") {
+		return nil
+	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
+
+	println()
+	b := new(batch)
+	// each change removes, then re-adds the validator
+	for range changes {
+		if err := b.add(ctx, info, client.TxTypeRemoveSubnetValidator, 1, 0); err != nil {
+			return err
+		}
+		if err := b.add(ctx, info, client.TxTypeAddSubnetValidator, 1, 0); err != nil {
+			return err
+		}
+	}
+	r := rollback.New("set weight", info.networkID)
+	for _, c := range changes {
+		r.Plan(nodeAction(rollback.KindRemoveSubnetValidator, c.nodeID))
+		a := validatorAction(rollback.KindAddSubnetValidator, c.nodeID, c.end)
+		a.SubnetID, a.Weight = info.subnetID.String(), o.weight
+		r.Plan(a)
+	}
+	for _, c := range changes {
+		if err := b.check(ctx, cli, info); err != nil {
+			return reportRollback(info, r, err)
+		}
+		took, err := cli.P().RemoveSubnetValidator(ctx, info.key, info.subnetID, c.nodeID)
+		if err != nil {
+			return reportRollback(info, r, err)
+		}
+		b.done()
+		removed := nodeAction(rollback.KindRemoveSubnetValidator, c.nodeID)
+		removed.SubnetID, removed.Weight = info.subnetID.String(), c.current
+		r.Complete(removed)
+		if err := info.RecordApproval(); err != nil {
+			return reportRollback(info, r, err)
+		}
+		color.Outf("{{magenta}}removed %s from subnet %s{{/}} {{light-gray}}(took %v){{/}}\n", c.nodeID, info.subnetID, took)
+
+		start := time.Now().Add(defaultValidateStartBuffer)
+		took, err = cli.P().AddSubnetValidator(
+			ctx,
+			info.key,
+			info.subnetID,
+			c.nodeID,
+			start,
+			c.end,
+			o.weight,
+		)
+		if err != nil {
+			// the subnet lost the validator until the remaining re-add
+			return reportRollback(info, r, err)
+		}
+		b.done()
+		added := validatorAction(rollback.KindAddSubnetValidator, c.nodeID, c.end)
+		added.SubnetID, added.Weight = info.subnetID.String(), o.weight
+		r.Complete(added)
+		color.Outf("{{magenta}}re-added %s with weight %d on subnet %s from %s{{/}} {{light-gray}}(took %v){{/}}\n", c.nodeID, o.weight, info.subnetID, start.Format(time.RFC3339), took)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+const (
+	signerSoft       = "soft"
+	signerLedger     = "ledger"
+	signerKMS        = "kms"
+	signerFireblocks = "fireblocks"
+	signerCustody    = "custody"
+)
+
+var (
+	signerType string
+	kmsKeyARN  string
+	kmsKeyName string
+
+	fireblocksURL        string
+	fireblocksAPIKey     string
+	fireblocksSecretPath string
+	fireblocksVaultID    uint32
+	fireblocksAddrIndex  uint32
+	custodyURL           string
+	custodyTokenPath     string
+	approvalTimeout      time.Duration
+	keyVaultPath         string
+
+	ErrUnknownSigner = errors.New("unknown signer")
+	ErrEmptyKMSKey   = errors.New("--signer=kms requires --kms-key-arn or --kms-key-name")
+	ErrEmptyCustody  = errors.New("custody signer requires its endpoint and credentials")
+)
+
+// addSignerFlags registers the flags selecting the key that pays for and
+// signs the txs.
+func addSignerFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path")
+	cmd.PersistentFlags().StringVar(&keyVaultPath, "key-vault-path", "", "HashiCorp Vault KV secret holding the private key, as mount/path[#field] (overrides --private-key-path; uses VAULT_ADDR and VAULT_TOKEN)")
+	cmd.PersistentFlags().BoolVarP(&useLedger, "ledger", "l", false, "use ledger to sign transactions (same as --signer=ledger)")
+	cmd.PersistentFlags().StringVar(&signerType, "signer", signerSoft, "signing backend (soft, ledger, kms, fireblocks, custody)")
+	cmd.PersistentFlags().StringVar(&kmsKeyARN, "kms-key-arn", "", "AWS KMS secp256k1 key ARN (with --signer=kms)")
+	cmd.PersistentFlags().StringVar(&kmsKeyName, "kms-key-name", "", "GCP Cloud KMS secp256k1 key version name, projects/.../cryptoKeyVersions/N (with --signer=kms)")
+	cmd.PersistentFlags().StringVar(&fireblocksURL, "fireblocks-url", key.DefaultFireblocksURL, "Fireblocks API endpoint (with --signer=fireblocks)")
+	cmd.PersistentFlags().StringVar(&fireblocksAPIKey, "fireblocks-api-key", "", "Fireblocks API user key (with --signer=fireblocks)")
+	cmd.PersistentFlags().StringVar(&fireblocksSecretPath, "fireblocks-secret-path", "", "Fireblocks API user RSA secret key file path (with --signer=fireblocks)")
+	cmd.PersistentFlags().Uint32Var(&fireblocksVaultID, "fireblocks-vault-id", 0, "Fireblocks vault account ID (with --signer=fireblocks)")
+	cmd.PersistentFlags().Uint32Var(&fireblocksAddrIndex, "fireblocks-address-index", 0, "Fireblocks vault address index (with --signer=fireblocks)")
+	cmd.PersistentFlags().StringVar(&custodyURL, "custody-url", "", "custody signing service endpoint (with --signer=custody)")
+	cmd.PersistentFlags().StringVar(&custodyTokenPath, "custody-token-path", "", "custody signing service bearer token file path (with --signer=custody)")
+	cmd.PersistentFlags().DurationVar(&approvalTimeout, "approval-timeout", key.DefaultApprovalTimeout, "how long to wait for each custody signing request to be approved (--timeout must also cover the approvals after the first)")
+}
+
+// loadSignerKey returns the key selected by the signer flags.
+func loadSignerKey(ctx context.Context, networkID uint32) (key.Key, error) {
+	signer := signerType
+	if useLedger {
+		signer = signerLedger
+	}
+	switch signer {
+	case signerSoft:
+		if keyVaultPath != "" {
+			k, err := key.LoadVault(ctx, networkID, key.VaultConfigFromEnv(), keyVaultPath)
+			if err != nil {
+				return nil, err
+			}
+			return k, nil
+		}
+		if envPrivKey != "" {
+			k, err := key.ParseSoft(networkID, envPrivKey)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", EnvPrivateKey, err)
+			}
+			return k, nil
+		}
+		if err := CheckKeyNetwork(networkID); err != nil {
+			return nil, err
+		}
+		k, err := key.LoadSoft(networkID, privKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	case signerLedger:
+		k, err := key.NewHard(networkID)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	case signerKMS:
+		var (
+			s   key.Signer
+			err error
+		)
+		switch {
+		case kmsKeyARN != "":
+			s, err = key.NewAWSKMSSigner(ctx, kmsKeyARN)
+		case kmsKeyName != "":
+			s, err = key.NewGCPKMSSigner(ctx, kmsKeyName)
+		default:
+			err = ErrEmptyKMSKey
+		}
+		if err != nil {
+			return nil, err
+		}
+		k, err := key.NewSignerKey(networkID, s)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	case signerFireblocks:
+		if fireblocksAPIKey == "" || fireblocksSecretPath == "" {
+			return nil, fmt.Errorf("%w: set --fireblocks-api-key and --fireblocks-secret-path", ErrEmptyCustody)
+		}
+		secret, err := ioutil.ReadFile(fireblocksSecretPath)
+		if err != nil {
+			return nil, err
+		}
+		s, err := key.NewFireblocksSigner(ctx, key.FireblocksConfig{
+			CustodyConfig:  key.CustodyConfig{ApprovalTimeout: approvalTimeout},
+			URL:            fireblocksURL,
+			APIKey:         fireblocksAPIKey,
+			Secret:         secret,
+			VaultAccountID: fireblocksVaultID,
+			AddressIndex:   fireblocksAddrIndex,
+		})
+		if err != nil {
+			return nil, err
+		}
+		k, err := key.NewSignerKey(networkID, s)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	case signerCustody:
+		if custodyURL == "" {
+			return nil, fmt.Errorf("%w: set --custody-url", ErrEmptyCustody)
+		}
+		var token string
+		if custodyTokenPath != "" {
+			b, err := ioutil.ReadFile(custodyTokenPath)
+			if err != nil {
+				return nil, err
+			}
+			token = strings.TrimSpace(string(b))
+			redact.Register(token)
+		}
+		s, err := key.NewHTTPCustodySigner(ctx, custodyURL, token, key.CustodyConfig{ApprovalTimeout: approvalTimeout})
+		if err != nil {
+			return nil, err
+		}
+		k, err := key.NewSignerKey(networkID, s)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSigner, signer)
+}
@@ -0,0 +1,441 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	runner_client "github.com/gyuho/avax-tester/client"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/poll"
+	"github.com/ava-labs/subnet-cli/internal/spec"
+	"github.com/ava-labs/subnet-cli/internal/window"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const simulateHealthTimeout = 5 * time.Minute
+
+var (
+	specPath        string
+	grpcEndpoint    string
+	avalanchegoPath string
+
+	ErrSimulationFailed = errors.New("simulation failed")
+	ErrStakeTooLow      = errors.New("stake below minimum")
+	ErrNoLocalURIs      = errors.New("local network has no node URIs")
+
+	errSkipped = errors.New("skipped")
+)
+
+// SimulateCommand implements "subnet-cli simulate" command.
+func SimulateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replays a deployment spec against an ephemeral local network",
+		Long: `
+Checks every operation in the spec against the parameters of the target
+network, then replays the operations on an ephemeral local network
+(launched via avalanche-network-runner) and reports which txs would fail
+and why.
+
+$ subnet-cli simulate \
+-f spec.yaml \
+--public-uri=https://api.avax-test.network \
+--grpc-endpoint=0.0.0.0:8080 \
+--avalanchego-path=/tmp/avalanchego
+
+`,
+		RunE: simulateFunc,
+	}
+	cmd.PersistentFlags().StringVarP(&specPath, "file", "f", "", "deployment spec file path")
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI of the network whose parameters are simulated")
+	cmd.PersistentFlags().StringVar(&grpcEndpoint, "grpc-endpoint", "0.0.0.0:8080", "avalanche-network-runner gRPC server endpoint")
+	cmd.PersistentFlags().StringVar(&avalanchegoPath, "avalanchego-path", "", "avalanchego executable path (local replay is skipped if empty)")
+	return cmd
+}
+
+type simResult struct {
+	phase string
+	step  string
+	err   error
+}
+
+func simulateFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	s, err := spec.Load(specPath)
+	if err != nil {
+		return err
+	}
+	uri := publicURI
+	if s.URI != "" && !cmd.Flags().Changed("public-uri") {
+		uri = s.URI
+	}
+	cli, info, err := InitClient(ctx, uri, false)
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{blue}}checking spec against %s parameters{{/}}\n", info.networkName)
+	results := preflight(ctx, cli, info, s)
+	if avalanchegoPath == "" {
+		color.Outf("{{yellow}}--avalanchego-path not set; skipping local replay{{/}}\n")
+	} else {
+		results = append(results, replay(ctx, cli, s)...)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetHeader([]string{"phase", "step", "result"})
+	failed := 0
+	for _, r := range results {
+		res := color.F("{{green}}ok{{/}}")
+		switch {
+		case errors.Is(r.err, errSkipped):
+			res = color.F("{{yellow}}%v{{/}}", r.err)
+		case r.err != nil:
+			failed++
+			res = color.F("{{red}}%v{{/}}", r.err)
+		}
+		tb.Append([]string{r.phase, r.step, res})
+	}
+	tb.Render()
+	color.Print(buf.String())
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d steps", ErrSimulationFailed, failed, len(results))
+	}
+	return nil
+}
+
+// preflight checks the spec against the live parameters and state of the
+// target network without issuing any tx.
+func preflight(ctx context.Context, cli client.Client, info *Info, s *spec.Spec) (results []simResult) {
+	cfg := info.params
+	now := time.Now()
+	primary := map[ids.ShortID]bool{}
+	for _, st := range s.Plan() {
+		err := func() error {
+			switch st.Kind {
+			case spec.StepAddValidator:
+				v := s.Validators[st.Index]
+				nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+				if err != nil {
+					return err
+				}
+				primary[nodeID] = true
+				stake, err := v.StakeAmount(cfg.minValidatorStake)
+				if err != nil {
+					return err
+				}
+				if stake < cfg.minValidatorStake {
+					return fmt.Errorf("%w: %s < %s", ErrStakeTooLow, amount.Format(stake), amount.Format(cfg.minValidatorStake))
+				}
+				w, err := v.Window(now)
+				if err != nil {
+					return err
+				}
+				if err := window.Check(w,
+					window.WithNow(now),
+					window.WithDurationLimits(cfg.minStakeDuration, cfg.maxStakeDuration),
+				); err != nil {
+					return err
+				}
+				_, _, err = cli.P().GetValidator(ctx, ids.Empty, nodeID)
+				switch {
+				case err == nil:
+					return client.ErrAlreadyValidator
+				case !errors.Is(err, client.ErrValidatorNotFound):
+					return err
+				}
+			case spec.StepAddSubnetValidator:
+				v := s.Subnets[st.Subnet].Validators[st.Index]
+				nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+				if err != nil {
+					return err
+				}
+				if primary[nodeID] {
+					return nil
+				}
+				_, _, err = cli.P().GetValidator(ctx, ids.Empty, nodeID)
+				if errors.Is(err, client.ErrValidatorNotFound) {
+					return client.ErrNotValidatingPrimaryNetwork
+				}
+				return err
+			case spec.StepCreateBlockchain:
+				c := s.Subnets[st.Subnet].Chains[st.Index]
+				if _, err := ids.FromString(c.VMID); err != nil {
+					return err
+				}
+				_, err := ioutil.ReadFile(s.GenesisPath(c))
+				return err
+			}
+			return nil
+		}()
+		results = append(results, simResult{phase: "preflight", step: s.Describe(st), err: err})
+	}
+	for _, sn := range s.Subnets {
+		if sn.ID == "" {
+			continue
+		}
+		_, err := ids.FromString(sn.ID)
+		results = append(results, simResult{phase: "preflight", step: fmt.Sprintf("reference subnet %q", sn.Name), err: err})
+	}
+	return results
+}
+
+// replay launches a local network, mirrors the relevant state of the target
+// network onto it and issues every tx in the spec with the ewoq key.
+func replay(ctx context.Context, src client.Client, s *spec.Spec) (results []simResult) {
+	fail := func(step string, err error) []simResult {
+		return append(results, simResult{phase: "local", step: step, err: err})
+	}
+
+	runner, err := runner_client.New(runner_client.Config{
+		LogLevel:    logLevel,
+		Endpoint:    grpcEndpoint,
+		DialTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		return fail("connect network runner", err)
+	}
+	defer runner.Close()
+
+	color.Outf("{{blue}}starting local network with %q{{/}}\n", avalanchegoPath)
+	_, err = runner.Start(ctx, avalanchegoPath)
+	if err != nil {
+		return fail("start local network", err)
+	}
+	defer func() {
+		// stop the network even once the command is interrupted or expired
+		sctx, cancel := stepContext(context.Background())
+		_, _ = runner.Stop(sctx)
+		cancel()
+	}()
+
+	color.Outf("{{yellow}}waiting for local network to become healthy...{{/}}\n")
+	deadline := time.Now().Add(simulateHealthTimeout)
+	for {
+		_, err = runner.Health(ctx)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fail("wait local network health", err)
+		}
+		select {
+		case <-ctx.Done():
+			return fail("wait local network health", ctx.Err())
+		case <-time.After(10 * time.Second):
+		}
+	}
+	uris, err := runner.URIs(ctx)
+	if err != nil {
+		return fail("get local network URIs", err)
+	}
+	if len(uris) == 0 {
+		return fail("get local network URIs", ErrNoLocalURIs)
+	}
+	lcli, err := client.New(ctx, client.Config{URI: uris[0], PollInterval: pollInterval})
+	if err != nil {
+		return fail("connect local network", err)
+	}
+	k, err := key.NewSoft(lcli.NetworkID(), key.WithPrivateKeyEncoded(key.EwoqPrivateKey))
+	if err != nil {
+		return fail("load ewoq key", err)
+	}
+	cfg := genesis.GetStakingConfig(lcli.NetworkID())
+
+	addValidator := func(nodeID ids.ShortID, stake uint64, d time.Duration) error {
+		if d < cfg.MinStakeDuration {
+			d = cfg.MinStakeDuration
+		}
+		if d > cfg.MaxStakeDuration {
+			d = cfg.MaxStakeDuration
+		}
+		start := time.Now().Add(defaultValidateStartBuffer)
+		_, err := lcli.P().AddValidator(ctx, k, nodeID, start, start.Add(d), client.WithStakeAmount(stake))
+		return err
+	}
+
+	// fork: mirror primary network validators the spec relies on but does
+	// not add itself, and stand in for subnets that already exist
+	primary := map[ids.ShortID]bool{}
+	for _, v := range s.Validators {
+		if nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix); err == nil {
+			primary[nodeID] = true
+		}
+	}
+	subnetIDs := make([]ids.ID, len(s.Subnets))
+	mirrored := map[ids.ShortID]bool{}
+	for si, sn := range s.Subnets {
+		for _, v := range sn.Validators {
+			nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+			if err != nil || primary[nodeID] {
+				continue
+			}
+			primary[nodeID] = true
+			mirrored[nodeID] = true
+			_, end, err := src.P().GetValidator(ctx, ids.Empty, nodeID)
+			if err == nil {
+				err = addValidator(nodeID, cfg.MinValidatorStake, time.Until(end))
+			}
+			results = append(results, simResult{phase: "fork", step: fmt.Sprintf("mirror primary validator %s", v.NodeID), err: err})
+		}
+		if sn.ID != "" {
+			subnetIDs[si], _, err = lcli.P().CreateSubnet(ctx, k)
+			results = append(results, simResult{phase: "fork", step: fmt.Sprintf("stand in for subnet %q", sn.Name), err: err})
+		}
+	}
+
+	// subnet validators require current primary validators: the ones the
+	// spec adds are awaited as dependencies, mirrored ones once here
+	waited := false
+	e := spec.Executor{
+		Run: func(ctx context.Context, st spec.Step) error {
+			switch st.Kind {
+			case spec.StepAddValidator:
+				v := s.Validators[st.Index]
+				nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+				if err != nil {
+					return err
+				}
+				stake, err := v.StakeAmount(cfg.MinValidatorStake)
+				if err != nil {
+					return err
+				}
+				w, err := v.Window(time.Now())
+				if err != nil {
+					return err
+				}
+				return addValidator(nodeID, stake, w.End.Sub(w.Start))
+			case spec.StepCreateSubnet:
+				subnetID, _, err := lcli.P().CreateSubnet(ctx, k)
+				subnetIDs[st.Subnet] = subnetID
+				return err
+			case spec.StepAddSubnetValidator:
+				if subnetIDs[st.Subnet] == ids.Empty {
+					return fmt.Errorf("%w: subnet not created", errSkipped)
+				}
+				v := s.Subnets[st.Subnet].Validators[st.Index]
+				nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+				if err != nil {
+					return err
+				}
+				if mirrored[nodeID] && !waited {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(defaultValidateStartBuffer + validatePropagationBuffer):
+					}
+					waited = true
+				}
+				_, end, err := lcli.P().GetValidator(ctx, ids.Empty, nodeID)
+				if err != nil {
+					return err
+				}
+				weight := v.Weight
+				if weight == 0 {
+					weight = defaultValidateWeight
+				}
+				_, err = lcli.P().AddSubnetValidator(ctx, k, subnetIDs[st.Subnet], nodeID, time.Now().Add(defaultValidateStartBuffer), end, weight)
+				return err
+			case spec.StepCreateBlockchain:
+				if subnetIDs[st.Subnet] == ids.Empty {
+					return fmt.Errorf("%w: subnet not created", errSkipped)
+				}
+				c := s.Subnets[st.Subnet].Chains[st.Index]
+				vmID, err := ids.FromString(c.VMID)
+				if err != nil {
+					return err
+				}
+				genesisBytes, err := ioutil.ReadFile(s.GenesisPath(c))
+				if err != nil {
+					return err
+				}
+				_, _, err = lcli.P().CreateBlockchain(ctx, k, subnetIDs[st.Subnet], c.Name, vmID, genesisBytes)
+				return err
+			}
+			return nil
+		},
+		Await: func(ctx context.Context, st spec.Step) error {
+			return awaitValidator(ctx, lcli, s, st, subnetIDs)
+		},
+		Report: reportStep(s),
+	}
+	for _, r := range e.Execute(ctx, s) {
+		err := r.Err
+		if r.Status == spec.StatusSkipped {
+			err = fmt.Errorf("%w: %v", errSkipped, err)
+		}
+		results = append(results, simResult{phase: "local", step: s.Describe(r.Step), err: err})
+	}
+	return results
+}
+
+// awaitValidator waits until the validator added by [st] is current, so
+// the steps depending on it (e.g., subnet validators or chains needing an
+// active validator) can run. Other steps are effective once accepted.
+func awaitValidator(ctx context.Context, cli client.Client, s *spec.Spec, st spec.Step, subnetIDs []ids.ID) error {
+	var (
+		nodeID   string
+		subnetID ids.ID
+	)
+	switch st.Kind {
+	case spec.StepAddValidator:
+		nodeID = s.Validators[st.Index].NodeID
+	case spec.StepAddSubnetValidator:
+		nodeID, subnetID = s.Subnets[st.Subnet].Validators[st.Index].NodeID, subnetIDs[st.Subnet]
+	default:
+		return nil
+	}
+	id, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	color.Outf("{{yellow}}waiting for %s to become active...{{/}}\n", nodeID)
+	pctx, cancel := context.WithTimeout(ctx, 2*(defaultValidateStartBuffer+validatePropagationBuffer))
+	defer cancel()
+	_, err = poll.New(pollInterval).Poll(poll.WithPhase(pctx, "validator start", defaultValidateStartBuffer), func() (bool, error) {
+		_, _, err := cli.P().GetValidator(pctx, subnetID, id)
+		if errors.Is(err, client.ErrValidatorNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	return err
+}
+
+// reportStep prints the status changes of the steps of [s].
+func reportStep(s *spec.Spec) func(spec.Step, spec.Status, error) {
+	return func(st spec.Step, status spec.Status, err error) {
+		switch status {
+		case spec.StatusRunning:
+			color.Outf("{{blue}}[%s] %s{{/}}\n", status, s.Describe(st))
+		case spec.StatusDone:
+			color.Outf("{{green}}[%s] %s{{/}}\n", status, s.Describe(st))
+		case spec.StatusSkipped:
+			color.Outf("{{yellow}}[%s] %s: %v{{/}}\n", status, s.Describe(st), err)
+		default:
+			color.Outf("{{red}}[%s] %s: %v{{/}}\n", status, s.Describe(st), err)
+		}
+	}
+}
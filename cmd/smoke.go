@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	api_info "github.com/ava-labs/avalanchego/api/info"
+	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	internal_platformvm "github.com/ava-labs/subnet-cli/internal/platformvm"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrSmokeTestFailed = errors.New("smoke test failed")
+	ErrNoSmokeNodeURI  = errors.New("--smoke-test requires --node-uri (or --validator-uris)")
+)
+
+var (
+	smokeTest             bool
+	smokeNodeURI          string
+	smokeBootstrapTimeout time.Duration
+)
+
+// addSmokeTestFlags registers the flags used by [RunSmokeTest].
+func addSmokeTestFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&smokeTest, "smoke-test", false, "'true' to wait for bootstrap and check the EVM RPC of a subnet-evm chain")
+	cmd.PersistentFlags().StringVar(&smokeNodeURI, "node-uri", "", "API URI of a subnet validator to smoke test (default to the first --validator-uris)")
+	cmd.PersistentFlags().DurationVar(&smokeBootstrapTimeout, "bootstrap-timeout", 10*time.Minute, "how long the smoke test waits for the blockchain to bootstrap")
+}
+
+// smokeTestURI returns the validator the smoke test probes: a public API
+// node does not track a new subnet, so it never serves its chains.
+func smokeTestURI() (string, error) {
+	uri := smokeNodeURI
+	if uri == "" && len(validatorURIs) > 0 {
+		uri = validatorURIs[0]
+	}
+	if uri == "" {
+		return "", ErrNoSmokeNodeURI
+	}
+	return strings.TrimSuffix(uri, "/"), nil
+}
+
+// GenesisChainID returns "config.chainId" of a subnet-evm genesis, or false
+// if the genesis is not an EVM genesis.
+func GenesisChainID(genesis []byte) (*big.Int, bool) {
+	var g struct {
+		Config struct {
+			ChainID json.Number `json:"chainId"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(genesis, &g); err != nil || g.Config.ChainID == "" {
+		return nil, false
+	}
+	v, ok := new(big.Int).SetString(g.Config.ChainID.String(), 10)
+	return v, ok
+}
+
+// EVMRPCURI returns the EVM RPC endpoint of [i.blockchainID] on [uri].
+func EVMRPCURI(uri string, i *Info) string {
+	return fmt.Sprintf("%s/ext/bc/%s/rpc", uri, i.blockchainID)
+}
+
+// RunSmokeTest waits for the new blockchain to bootstrap on the validator
+// of [smokeTestURI] and checks that its EVM RPC responds with the chain ID
+// of the genesis.
+func RunSmokeTest(ctx context.Context, cli client.Client, i *Info, genesis []byte) error {
+	expected, ok := GenesisChainID(genesis)
+	if !ok {
+		color.Outf("{{yellow}}skipping smoke test: %q is not a subnet-evm genesis{{/}}\n", i.vmGenesisPath)
+		return nil
+	}
+	uri, err := smokeTestURI()
+	if err != nil {
+		return err
+	}
+
+	rpc := EVMRPCURI(uri, i)
+	ecli := evm.NewClient(rpc)
+
+	color.Outf("\n{{blue}}Waiting for blockchain %s to bootstrap on %s...{{/}}\n", i.blockchainID, uri)
+	cctx, cancel := context.WithTimeout(ctx, smokeBootstrapTimeout)
+	_, err = cli.P().Checker().PollBlockchain(cctx,
+		internal_platformvm.WithBlockchainID(i.blockchainID),
+		internal_platformvm.WithBlockchainStatus(pstatus.Validating),
+		internal_platformvm.WithCheckBlockchainBootstrapped(api_info.NewClient(uri)),
+		internal_platformvm.WithBootstrapHeights(ecli.BlockNumber, nil),
+	)
+	cancel()
+	if err != nil {
+		color.Outf("{{red}}SMOKE TEST FAIL: blockchain did not bootstrap: %v{{/}}\n", err)
+		return fmt.Errorf("%w: %v", ErrSmokeTestFailed, err)
+	}
+
+	chainID, err := ecli.ChainID(ctx)
+	if err != nil {
+		color.Outf("{{red}}SMOKE TEST FAIL: eth_chainId on %s: %v{{/}}\n", rpc, err)
+		return fmt.Errorf("%w: %v", ErrSmokeTestFailed, err)
+	}
+	height, err := ecli.BlockNumber(ctx)
+	if err != nil {
+		color.Outf("{{red}}SMOKE TEST FAIL: eth_blockNumber on %s: %v{{/}}\n", rpc, err)
+		return fmt.Errorf("%w: %v", ErrSmokeTestFailed, err)
+	}
+	if chainID.Cmp(expected) != 0 {
+		color.Outf("{{red}}SMOKE TEST FAIL: eth_chainId %s does not match genesis chain ID %s{{/}}\n", chainID, expected)
+		return fmt.Errorf("%w: chain ID %s, expected %s", ErrSmokeTestFailed, chainID, expected)
+	}
+	color.Outf("{{green}}SMOKE TEST PASS: %s (chain ID %s, height %d){{/}}\n", rpc, chainID, height)
+	return nil
+}
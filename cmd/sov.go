@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// subnetOnlyValidators returns true if the validators of L1s on the
+// connected network need no primary network stake (ACP-77, Etna). Subnets
+// that are not converted still need primary network validators.
+func (i *Info) subnetOnlyValidators() bool {
+	return i.upgradeActive(version.Etna)
+}
+
+// deferSOVNodes drops the nodes that are not primary network validators
+// from the primary and subnet validators to add, since converting the
+// subnet to an L1 spares them the primary stake, and returns them (see
+// "wizard --l1").
+func (i *Info) deferSOVNodes() []ids.ShortID {
+	sov := i.nodeIDs
+	deferred := make(map[ids.ShortID]bool, len(sov))
+	for _, nodeID := range sov {
+		deferred[nodeID] = true
+	}
+	primary := make([]ids.ShortID, 0, len(i.allNodeIDs))
+	for _, nodeID := range i.allNodeIDs {
+		if !deferred[nodeID] {
+			primary = append(primary, nodeID)
+		}
+	}
+	i.allNodeIDs = primary
+	i.nodeIDs = nil
+	i.stakeAmount = 0
+	i.totalStakeAmount = 0
+	return sov
+}
+
+// printSOVGuidance explains that [nodeIDs], which are not primary network
+// validators, can validate the subnet once it is converted to an L1
+// instead of staking.
+func (i *Info) printSOVGuidance(nodeIDs []ids.ShortID) {
+	stake := i.params.minValidatorStake
+	color.Outf("{{yellow}}%q runs %s: %d node(s) are not primary network validators, and need no %s primary stake to validate an L1.{{/}}\n", i.networkName, version.Etna, len(nodeIDs), amount.Format(stake))
+	color.Outf("{{yellow}}Convert the subnet to an L1 to add them, each paying a continuous fee from its balance (--validator-balance, %s by default):{{/}}\n", amount.Format(defaultL1ValidatorBalance))
+	subnetID := "[SUBNET ID]"
+	if i.subnetID != ids.Empty {
+		subnetID = i.subnetID.String()
+	}
+	weight := i.validateWeight
+	if weight == 0 {
+		weight = defaultValidateWeight
+	}
+	validators := make([]string, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		validators = append(validators, fmt.Sprintf("--validator=node-id=%s,weight=%d,bls-public-key=0x...,bls-pop=0x...", nodeID.PrefixedString(constants.NodeIDPrefix), weight))
+	}
+	color.Outf("{{cyan}}$ subnet-cli convert l1 --subnet-id=%s --chain-id=[MANAGER CHAIN ID] --manager-address=[MANAGER ADDRESS] %s{{/}}\n\n", subnetID, strings.Join(validators, " "))
+}
@@ -15,7 +15,12 @@ func StatusCommand() *cobra.Command {
 	}
 	cmd.AddCommand(
 		newStatusBlockchainCommand(),
+		newStatusLivenessCommand(),
+		newStatusNetworkCommand(),
+		newStatusPeersCommand(),
+		newStatusWarpCommand(),
 	)
 	cmd.PersistentFlags().StringVar(&privateURI, "private-uri", "", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "'true' to bypass the local cache of validator/subnet lookups")
 	return cmd
 }
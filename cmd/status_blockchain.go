@@ -5,10 +5,19 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/ava-labs/avalanchego/ids"
 	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/evm"
 	internal_platformvm "github.com/ava-labs/subnet-cli/internal/platformvm"
 	"github.com/ava-labs/subnet-cli/pkg/color"
 	"github.com/spf13/cobra"
@@ -26,17 +35,49 @@ $ subnet-cli status blockchain \
 --private-uri=http://localhost:49738 \
 --check-bootstrapped
 
+While it waits, it prints the last accepted height of the blockchain (for
+EVM chains) with the rate it grows at. With "--bootstrap-reference-uri" set
+to a node that already bootstrapped the blockchain, it also prints the
+progress and an ETA ("--timeout" bounds the wait):
+
+$ subnet-cli status blockchain \
+--blockchain-id=[BLOCKCHAIN ID] \
+--private-uri=http://localhost:49738 \
+--check-bootstrapped \
+--bootstrap-reference-uri=https://api.avax-test.network \
+--timeout=2h
+
+To recover the genesis the blockchain was created with (decoded from its
+CreateChainTx), instead of checking its status:
+
+$ subnet-cli status blockchain \
+--blockchain-id=[BLOCKCHAIN ID] \
+--private-uri=http://localhost:49738 \
+--show-genesis \
+--genesis-output=recovered.genesis
+
 `,
 		RunE: createStatusFunc,
 	}
 
 	cmd.PersistentFlags().StringVar(&blockchainID, "blockchain-id", "", "blockchain to check the status of")
 	cmd.PersistentFlags().BoolVar(&checkBootstrapped, "check-bootstrapped", false, "'true' to wait until the blockchain is bootstrapped")
+	cmd.PersistentFlags().StringVar(&bootstrapReferenceURI, "bootstrap-reference-uri", "", "URI of a node that bootstrapped the blockchain, to estimate when it is caught up (with --check-bootstrapped)")
+	cmd.PersistentFlags().BoolVar(&showGenesis, "show-genesis", false, "'true' to print the genesis of the blockchain instead of checking its status")
+	cmd.PersistentFlags().StringVar(&genesisOutput, "genesis-output", "", "file to write the raw genesis bytes to (with --show-genesis)")
 	return cmd
 }
 
+var (
+	showGenesis           bool
+	genesisOutput         string
+	bootstrapReferenceURI string
+)
+
 func createStatusFunc(cmd *cobra.Command, args []string) error {
-	cli, _, err := InitClient(privateURI, false)
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, _, err := InitClient(ctx, privateURI, false)
 	if err != nil {
 		return err
 	}
@@ -46,17 +87,58 @@ func createStatusFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if showGenesis {
+		return showBlockchainGenesis(ctx, cli, blkChainID)
+	}
+
 	opts := []internal_platformvm.OpOption{
 		internal_platformvm.WithBlockchainID(blkChainID),
 		internal_platformvm.WithBlockchainStatus(pstatus.Validating),
 	}
 	if checkBootstrapped {
 		opts = append(opts, internal_platformvm.WithCheckBlockchainBootstrapped(cli.Info().Client()))
+		var target func(context.Context) (uint64, error)
+		if bootstrapReferenceURI != "" {
+			rpc := fmt.Sprintf("%s/ext/bc/%s/rpc", strings.TrimSuffix(bootstrapReferenceURI, "/"), blkChainID)
+			target = evm.NewClient(rpc).BlockNumber
+		}
+		opts = append(opts, internal_platformvm.WithBootstrapHeights(cli.EVM(blkChainID.String()).BlockNumber, target))
 	}
 
 	color.Outf("\n{{blue}}Checking blockchain...{{/}}\n")
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	_, err = cli.P().Checker().PollBlockchain(ctx, opts...)
-	cancel()
 	return err
 }
+
+// showBlockchainGenesis prints the genesis of [blkChainID], and writes the
+// raw bytes to "--genesis-output" if set.
+func showBlockchainGenesis(ctx context.Context, cli client.Client, blkChainID ids.ID) error {
+	bc, err := cli.P().GetBlockchain(ctx, blkChainID)
+	if err != nil {
+		return err
+	}
+	color.Outf("{{blue}}blockchain{{/}} %q {{light-gray}}(subnet %s, VM %s, %d-byte genesis){{/}}\n", bc.Name, bc.SubnetID, bc.VMID, len(bc.Genesis))
+	if genesisOutput != "" {
+		if err := ioutil.WriteFile(genesisOutput, bc.Genesis, 0o644); err != nil {
+			return err
+		}
+		color.Outf("{{magenta}}wrote genesis{{/}} %q\n", genesisOutput)
+		color.Result(genesisOutput)
+		return nil
+	}
+	fmt.Fprintln(color.Stdout, formatGenesis(bc.Genesis))
+	return nil
+}
+
+// formatGenesis indents a JSON genesis, returns a text genesis as is and
+// hex-encodes a binary one.
+func formatGenesis(b []byte) string {
+	buf := new(bytes.Buffer)
+	if json.Indent(buf, b, "", "  ") == nil {
+		return buf.String()
+	}
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,423 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/liveness"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/internal/weights"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	livenessSampleInterval time.Duration
+	statusLivenessFormat   string
+
+	ErrSubnetNotLive = errors.New("subnet is not live")
+)
+
+func newStatusLivenessCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "liveness",
+		Short: "Checks whether the chains of a subnet are producing blocks",
+		Long: `
+Samples the last accepted height of every chain of the subnet on every
+validator in "--validator-uris" (default to the connected URI) twice,
+"--sample-interval" apart, and flags the chains that did not grow on any
+validator. Heights come from the EVM JSON-RPC API or, for other VMs, the
+index API (the nodes must run with "--index-enabled").
+
+It also reports the weight of the responsive validators (queried validators
+that answer and bootstrapped every chain; validators not queried count if
+the connected node is connected to them) against the 80% of weight consensus
+needs to make progress. The command fails if a chain stalled or the
+responsive weight is below 80%.
+
+$ subnet-cli status liveness \
+--private-uri=http://localhost:49738 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--validator-uris=http://10.0.0.1:9650,http://10.0.0.2:9650 \
+--sample-interval=30s
+
+`,
+		Args: cobra.NoArgs,
+		RunE: statusLivenessFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringSliceVar(&validatorURIs, "validator-uris", nil, "API URIs of the subnet validators to probe (default to the connected URI)")
+	cmd.PersistentFlags().DurationVar(&livenessSampleInterval, "sample-interval", 10*time.Second, "time between the two height samples of every chain")
+	cmd.PersistentFlags().StringVar(&statusLivenessFormat, "format", "text", "output format (text, json)")
+	return cmd
+}
+
+// livenessStatus is the JSON output of "status liveness".
+type livenessStatus struct {
+	SubnetID string `json:"subnetID"`
+	// ResponsiveWeight and TotalWeight are the subnet weights.
+	ResponsiveWeight  uint64              `json:"responsiveWeight"`
+	TotalWeight       uint64              `json:"totalWeight"`
+	ResponsivePercent float64             `json:"responsivePercent"`
+	ThresholdPercent  float64             `json:"thresholdPercent"`
+	Live              bool                `json:"live"`
+	Chains            []livenessChain     `json:"chains"`
+	Validators        []livenessValidator `json:"validators"`
+	Errors            []peersFailed       `json:"errors,omitempty"`
+}
+
+type livenessChain struct {
+	BlockchainID string   `json:"blockchainID"`
+	Name         string   `json:"name"`
+	State        string   `json:"state"`
+	Height       uint64   `json:"height"`
+	Stuck        []string `json:"stuck,omitempty"`
+}
+
+type livenessValidator struct {
+	NodeID     string `json:"nodeID"`
+	Weight     uint64 `json:"weight"`
+	URI        string `json:"uri,omitempty"`
+	Responsive bool   `json:"responsive"`
+	// Reason is why the validator is not responsive.
+	Reason string `json:"reason,omitempty"`
+}
+
+// livenessNode is a probed validator API.
+type livenessNode struct {
+	URI    string
+	NodeID ids.ShortID
+	cli    client.Client
+	// Unbootstrapped are the chains the node did not bootstrap.
+	Unbootstrapped []ids.ID
+	Err            error
+}
+
+// chainHeight is the last accepted height of a chain on a node.
+type chainHeight struct {
+	Height uint64
+	Err    error
+}
+
+func statusLivenessFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	switch statusLivenessFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExportFormat, statusLivenessFormat)
+	}
+	cli, info, err := InitClient(ctx, privateURI, false)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	bcs, err := cli.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return err
+	}
+	chains := []livenessChain{}
+	chainIDs := []ids.ID{}
+	for _, bc := range bcs {
+		if bc.SubnetID == info.subnetID {
+			chains = append(chains, livenessChain{BlockchainID: bc.ID.String(), Name: bc.Name})
+			chainIDs = append(chainIDs, bc.ID)
+		}
+	}
+	// the connected node tells whether it is connected to the validators
+	// that are not probed (only primary network validators report it)
+	connected := map[ids.ShortID]bool{}
+	pvs, err := cli.P().GetValidators(ctx, ids.Empty)
+	if err != nil {
+		zap.L().Warn("failed to fetch primary network validators", zap.Error(err))
+	}
+	for _, v := range pvs {
+		if v.Connected != nil {
+			connected[v.NodeID] = *v.Connected
+		}
+	}
+
+	uris := validatorURIs
+	if len(uris) == 0 {
+		uris = []string{info.uri}
+	}
+	nodes := connectLivenessNodes(ctx, uris, chainIDs)
+	if len(chainIDs) > 0 {
+		color.Outf("{{blue}}sampling %d chain(s) on %d node(s) %s apart...{{/}}\n", len(chainIDs), len(nodes), livenessSampleInterval)
+	}
+	before := sampleChainHeights(ctx, nodes, chainIDs)
+	if len(chainIDs) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(livenessSampleInterval):
+		}
+	}
+	after := sampleChainHeights(ctx, nodes, chainIDs)
+
+	for c := range chains {
+		probes := []liveness.Probe{}
+		for n, node := range nodes {
+			if node.Err != nil {
+				continue
+			}
+			p := liveness.Probe{NodeID: node.NodeID, Before: before[n][c].Height, After: after[n][c].Height}
+			if p.Err = before[n][c].Err; p.Err == nil {
+				p.Err = after[n][c].Err
+			}
+			probes = append(probes, p)
+		}
+		lc := liveness.CheckChain(probes)
+		chains[c].State = string(lc.State)
+		chains[c].Height = lc.Height
+		for _, nodeID := range lc.Stuck {
+			chains[c].Stuck = append(chains[c].Stuck, nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+	}
+
+	st := makeLivenessStatus(info.subnetID, ws, chains, nodes, connected)
+	if statusLivenessFormat == "json" {
+		b, err := json.MarshalIndent(st, "", "  ")
+		if err != nil {
+			return err
+		}
+		// machine-readable output is printed even in quiet mode
+		fmt.Fprintln(color.Stdout, string(b))
+	} else {
+		printLiveness(info, st)
+	}
+
+	stalled := 0
+	for _, c := range st.Chains {
+		if c.State == string(liveness.ChainStalled) {
+			stalled++
+		}
+	}
+	switch {
+	case stalled > 0:
+		return fmt.Errorf("%w: %d stalled chain(s)", ErrSubnetNotLive, stalled)
+	case !st.Live:
+		return fmt.Errorf("%w: %.1f%% of the weight is responsive (%.0f%% needed)", ErrSubnetNotLive, st.ResponsivePercent, st.ThresholdPercent)
+	}
+	return nil
+}
+
+// connectLivenessNodes connects to every URI concurrently and checks which
+// of [chainIDs] each node bootstrapped; a URI that fails does not fail the
+// others.
+func connectLivenessNodes(ctx context.Context, uris []string, chainIDs []ids.ID) []livenessNode {
+	nodes := make([]livenessNode, len(uris))
+	var wg sync.WaitGroup
+	for idx, uri := range uris {
+		nodes[idx].URI = uri
+		wg.Add(1)
+		go func(n *livenessNode) {
+			defer wg.Done()
+			if n.Err = connectLivenessNode(ctx, n, chainIDs); n.Err != nil {
+				n.Err = redact.Error(n.Err)
+			}
+		}(&nodes[idx])
+	}
+	wg.Wait()
+	return nodes
+}
+
+func connectLivenessNode(ctx context.Context, n *livenessNode, chainIDs []ids.ID) error {
+	cli, _, err := InitClient(ctx, n.URI, false)
+	if err != nil {
+		return err
+	}
+	n.cli = cli
+	rnodeID, err := cli.Info().Client().GetNodeID(ctx)
+	if err != nil {
+		return err
+	}
+	if n.NodeID, err = ids.ShortFromPrefixedString(rnodeID, constants.NodeIDPrefix); err != nil {
+		return err
+	}
+	for _, chainID := range chainIDs {
+		bootstrapped, err := cli.Info().Client().IsBootstrapped(ctx, chainID.String())
+		if err != nil {
+			return err
+		}
+		if !bootstrapped {
+			n.Unbootstrapped = append(n.Unbootstrapped, chainID)
+		}
+	}
+	return nil
+}
+
+// sampleChainHeights returns the height of every chain on every node that
+// connected, indexed by node then chain.
+func sampleChainHeights(ctx context.Context, nodes []livenessNode, chainIDs []ids.ID) [][]chainHeight {
+	hs := make([][]chainHeight, len(nodes))
+	var wg sync.WaitGroup
+	for n := range nodes {
+		hs[n] = make([]chainHeight, len(chainIDs))
+		if nodes[n].Err != nil {
+			continue
+		}
+		for c := range chainIDs {
+			wg.Add(1)
+			go func(n, c int) {
+				defer wg.Done()
+				h, err := fetchChainHeight(ctx, nodes[n].cli, chainIDs[c])
+				hs[n][c] = chainHeight{Height: h, Err: err}
+			}(n, c)
+		}
+	}
+	wg.Wait()
+	return hs
+}
+
+// fetchChainHeight returns the last accepted height of [chainID] on the
+// node of [cli], from the EVM JSON-RPC API or else the index API.
+func fetchChainHeight(ctx context.Context, cli client.Client, chainID ids.ID) (uint64, error) {
+	h, err := cli.EVM(chainID.String()).BlockNumber(ctx)
+	if err == nil {
+		return h, nil
+	}
+	zap.L().Debug("no EVM height; trying the index API", zap.Stringer("blockchainID", chainID), zap.Error(err))
+	icli := indexer.NewClient(cli.Config().URI, fmt.Sprintf("/ext/index/%s/block", chainID))
+	lc, err := icli.GetLastAccepted(ctx, &indexer.GetLastAcceptedArgs{Encoding: formatting.Hex})
+	if err != nil {
+		return 0, err
+	}
+	return icli.GetIndex(ctx, &indexer.GetIndexArgs{ContainerID: lc.ID, Encoding: formatting.Hex})
+}
+
+func makeLivenessStatus(
+	subnetID ids.ID,
+	ws map[ids.ShortID]uint64,
+	chains []livenessChain,
+	nodes []livenessNode,
+	connected map[ids.ShortID]bool,
+) *livenessStatus {
+	probed := map[ids.ShortID]*livenessNode{}
+	for idx := range nodes {
+		if nodes[idx].Err == nil {
+			probed[nodes[idx].NodeID] = &nodes[idx]
+		}
+	}
+	responsive := map[ids.ShortID]bool{}
+	vs := []livenessValidator{}
+	for _, s := range weights.Analyze(ws).Shares {
+		v := livenessValidator{NodeID: s.NodeID.PrefixedString(constants.NodeIDPrefix), Weight: s.Weight}
+		if n, ok := probed[s.NodeID]; ok {
+			v.URI = redact.String(n.URI)
+			v.Responsive = len(n.Unbootstrapped) == 0
+			if !v.Responsive {
+				v.Reason = fmt.Sprintf("%d chain(s) not bootstrapped", len(n.Unbootstrapped))
+			}
+		} else {
+			c, ok := connected[s.NodeID]
+			v.Responsive = c
+			switch {
+			case !ok:
+				v.Reason = "not probed; pass its API URI with --validator-uris"
+			case !c:
+				v.Reason = "not connected to the connected node"
+			}
+		}
+		responsive[s.NodeID] = v.Responsive
+		vs = append(vs, v)
+	}
+	w := liveness.ResponsiveWeight(ws, responsive)
+	var errs []peersFailed
+	for _, n := range nodes {
+		if n.Err != nil {
+			errs = append(errs, peersFailed{URI: redact.String(n.URI), Error: n.Err.Error()})
+		}
+	}
+	return &livenessStatus{
+		SubnetID:          subnetID.String(),
+		ResponsiveWeight:  w.Responsive,
+		TotalWeight:       w.Total,
+		ResponsivePercent: 100 * w.Fraction(),
+		ThresholdPercent:  100 * weights.LivenessThreshold,
+		Live:              w.Live(),
+		Chains:            chains,
+		Validators:        vs,
+		Errors:            errs,
+	}
+}
+
+func printLiveness(i *Info, st *livenessStatus) {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindSubnet, i.subnetID))})
+	for _, c := range st.Chains {
+		state := color.F("{{green}}progressing{{/}} {{light-gray}}(height %s){{/}}", humanize.Comma(int64(c.Height)))
+		switch liveness.ChainState(c.State) {
+		case liveness.ChainStalled:
+			state = color.F("{{red}}{{bold}}stalled{{/}} {{light-gray}}(height %s){{/}}", humanize.Comma(int64(c.Height)))
+		case liveness.ChainUnknown:
+			state = color.F("{{yellow}}unknown (no EVM or index API){{/}}")
+		}
+		if len(c.Stuck) > 0 {
+			state += color.F(" {{yellow}}stuck on %d validator(s){{/}}", len(c.Stuck))
+		}
+		tb.Append([]string{color.F("{{dark-green}}%s{{/}}", c.Name), state})
+	}
+	for _, v := range st.Validators {
+		status := color.F("{{green}}responsive{{/}}")
+		if !v.Responsive {
+			status = color.F("{{red}}%s{{/}}", v.Reason)
+		}
+		if v.URI != "" {
+			status += color.F(" {{light-gray}}%s{{/}}", v.URI)
+		}
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", v.NodeID),
+			color.F("{{light-gray}}%s{{/}} %s", humanize.Comma(int64(v.Weight)), status),
+		})
+	}
+	c := "{{green}}"
+	if !st.Live {
+		c = "{{red}}"
+	}
+	tb.Append([]string{color.F("{{magenta}}RESPONSIVE WEIGHT{{/}}"), color.F(c+"{{bold}}%s / %s (%.1f%%, %.0f%% needed){{/}}", humanize.Comma(int64(st.ResponsiveWeight)), humanize.Comma(int64(st.TotalWeight)), st.ResponsivePercent, st.ThresholdPercent)})
+	tb.Render()
+	color.Print(buf.String())
+
+	for _, e := range st.Errors {
+		color.Outf("{{red}}failed to probe %s: %s{{/}}\n", e.URI, e.Error)
+	}
+	for _, c := range st.Chains {
+		for _, nodeID := range c.Stuck {
+			color.Outf("{{yellow}}%s is stuck on %s while other validators progress{{/}}\n", c.Name, nodeID)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newStatusNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Prints the parameters of the connected network",
+		Long: `
+Prints the staking parameters (min/max stake, min/max stake duration,
+delegation fee floor), the current supply and the current tx fees of the
+connected network.
+
+$ subnet-cli status network --private-uri=https://api.avax-test.network
+
+$ subnet-cli status network --private-uri=https://api.avax-test.network --format=json
+
+With "--profiles", queries several networks at once and compares them side
+by side (e.g., mirrored staging and production subnets); each profile is a
+well-known network name, a URI, or name=URI:
+
+$ subnet-cli status network --profiles=fuji,mainnet
+
+$ subnet-cli status network --profiles=staging=http://10.0.0.1:9650,production=https://api.avax.network
+
+`,
+		Args: cobra.NoArgs,
+		RunE: statusNetworkFunc,
+	}
+	cmd.PersistentFlags().StringVar(&statusNetworkFormat, "format", "text", "output format (text, json)")
+	cmd.PersistentFlags().StringSliceVar(&statusProfiles, "profiles", nil, "networks to query and compare instead of --private-uri (fuji, mainnet, a URI, or name=URI)")
+	return cmd
+}
+
+var statusNetworkFormat string
+
+// feeTxTypes are the tx types "status network" prices, in display order.
+var feeTxTypes = []client.TxType{
+	client.TxTypeBase,
+	client.TxTypeCreateSubnet,
+	client.TxTypeCreateBlockchain,
+	client.TxTypeAddValidator,
+	client.TxTypeAddSubnetValidator,
+	client.TxTypeConvertSubnetToL1,
+	client.TxTypeRegisterL1Validator,
+	client.TxTypeSetL1ValidatorWeight,
+	client.TxTypeIncreaseL1ValidatorBalance,
+}
+
+// l1TxTypes only exist since Etna.
+var l1TxTypes = map[client.TxType]bool{
+	client.TxTypeConvertSubnetToL1:          true,
+	client.TxTypeRegisterL1Validator:        true,
+	client.TxTypeSetL1ValidatorWeight:       true,
+	client.TxTypeIncreaseL1ValidatorBalance: true,
+}
+
+// networkStatus are the parameters "status network" prints; amounts are in
+// nAVAX.
+type networkStatus struct {
+	NetworkName   string `json:"networkName"`
+	NetworkID     uint32 `json:"networkID"`
+	LatestUpgrade string `json:"latestUpgrade,omitempty"`
+
+	MinValidatorStake uint64 `json:"minValidatorStake"`
+	MaxValidatorStake uint64 `json:"maxValidatorStake"`
+	MinDelegatorStake uint64 `json:"minDelegatorStake"`
+	// MinDelegationFeePercent is the lowest delegation fee validators can
+	// charge delegators.
+	MinDelegationFeePercent float64 `json:"minDelegationFeePercent"`
+	MinStakeDurationSeconds uint64  `json:"minStakeDurationSeconds"`
+	MaxStakeDurationSeconds uint64  `json:"maxStakeDurationSeconds"`
+
+	// CurrentSupply is an upper bound of the AVAX supply; nil if the node
+	// does not serve it.
+	CurrentSupply *uint64 `json:"currentSupply,omitempty"`
+
+	Fees []txFee `json:"fees"`
+}
+
+// txFee is the current fee of a tx type, or the reason it is unavailable.
+type txFee struct {
+	TxType client.TxType `json:"txType"`
+	Fee    *uint64       `json:"fee,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+func statusNetworkFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	switch statusNetworkFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExportFormat, statusNetworkFormat)
+	}
+	if len(statusProfiles) > 0 {
+		return statusProfilesFunc(cmd)
+	}
+	cli, info, err := InitClient(ctx, privateURI, false)
+	if err != nil {
+		return err
+	}
+	st := loadNetworkStatus(ctx, cli, info)
+	if statusNetworkFormat == "json" {
+		b, err := json.MarshalIndent(st, "", "  ")
+		if err != nil {
+			return err
+		}
+		// machine-readable output is printed even in quiet mode
+		fmt.Fprintln(color.Stdout, string(b))
+		return nil
+	}
+	color.Print(makeNetworkTable(info, st))
+	return nil
+}
+
+func statusProfilesFunc(cmd *cobra.Command) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if privateURI != "" {
+		return ErrProfilesPrivateURI
+	}
+	ps, err := parseProfiles(statusProfiles)
+	if err != nil {
+		return err
+	}
+	sts := loadProfileStatuses(ctx, ps)
+	if statusNetworkFormat == "json" {
+		b, err := json.MarshalIndent(sts, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(color.Stdout, string(b))
+		return nil
+	}
+	color.Print(makeProfilesTable(sts))
+	return nil
+}
+
+// loadNetworkStatus collects the parameters of the network of [i], the
+// current supply and the fee of every tx type the network accepts.
+func loadNetworkStatus(ctx context.Context, cli client.Client, i *Info) *networkStatus {
+	st := &networkStatus{
+		NetworkName:             i.networkName,
+		NetworkID:               i.networkID,
+		MinValidatorStake:       i.params.minValidatorStake,
+		MaxValidatorStake:       i.params.maxValidatorStake,
+		MinDelegatorStake:       i.params.minDelegatorStake,
+		MinDelegationFeePercent: float64(i.params.minDelegationFee) / 10_000,
+		MinStakeDurationSeconds: uint64(i.params.minStakeDuration / time.Second),
+		MaxStakeDurationSeconds: uint64(i.params.maxStakeDuration / time.Second),
+		Fees:                    []txFee{},
+	}
+	if latest, ok := version.Latest(i.upgrades); ok {
+		st.LatestUpgrade = latest.Name
+	}
+
+	supply, err := cli.P().Client().GetCurrentSupply(ctx)
+	if err != nil {
+		zap.L().Warn("failed to fetch current supply", zap.Error(err))
+	} else {
+		st.CurrentSupply = &supply
+	}
+
+	for _, txType := range feeTxTypes {
+		if l1TxTypes[txType] && !i.upgradeActive(version.Etna) {
+			continue
+		}
+		if up, ok := txDisabledBy[txType]; ok && i.upgradeActive(up) {
+			continue
+		}
+		fee, err := i.fees.Fee(ctx, txType, 0)
+		if err != nil {
+			st.Fees = append(st.Fees, txFee{TxType: txType, Error: err.Error()})
+			continue
+		}
+		st.Fees = append(st.Fees, txFee{TxType: txType, Fee: &fee})
+	}
+	return st
+}
+
+// makeNetworkTable renders [st], with amounts in fiat if "--price-source"
+// is set.
+func makeNetworkTable(i *Info, st *networkStatus) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+
+	avax := func(v uint64) string {
+		return color.F("{{light-gray}}{{bold}}%s{{/}}", amount.Format(v)) + i.fiat(v)
+	}
+	tb.Append([]string{color.F("{{cyan}}{{bold}}NETWORK{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}} {{light-gray}}(ID %d){{/}}", st.NetworkName, st.NetworkID)})
+	if st.LatestUpgrade != "" {
+		tb.Append([]string{color.F("{{cyan}}{{bold}}LATEST UPGRADE{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", st.LatestUpgrade)})
+	}
+	tb.Append([]string{color.F("{{magenta}}MIN VALIDATOR STAKE{{/}}"), avax(st.MinValidatorStake)})
+	tb.Append([]string{color.F("{{magenta}}MAX VALIDATOR STAKE{{/}}"), avax(st.MaxValidatorStake)})
+	tb.Append([]string{color.F("{{magenta}}MIN DELEGATOR STAKE{{/}}"), avax(st.MinDelegatorStake)})
+	tb.Append([]string{color.F("{{magenta}}MIN DELEGATION FEE{{/}}"), color.F("{{light-gray}}{{bold}}%s%%{{/}}", humanize.FormatFloat("#,###.####", st.MinDelegationFeePercent))})
+	tb.Append([]string{color.F("{{magenta}}MIN STAKE DURATION{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", formatDuration(time.Duration(st.MinStakeDurationSeconds)*time.Second))})
+	tb.Append([]string{color.F("{{magenta}}MAX STAKE DURATION{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", formatDuration(time.Duration(st.MaxStakeDurationSeconds)*time.Second))})
+	if st.CurrentSupply != nil {
+		tb.Append([]string{color.F("{{dark-green}}CURRENT SUPPLY{{/}}"), avax(*st.CurrentSupply)})
+	}
+	for _, f := range st.Fees {
+		name := color.F("{{red}}{{bold}}%s FEE{{/}}", f.TxType)
+		if f.Fee == nil {
+			tb.Append([]string{name, color.F("{{light-gray}}unavailable (%s){{/}}", f.Error)})
+			continue
+		}
+		tb.Append([]string{name, avax(*f.Fee)})
+	}
+	tb.Render()
+	return buf.String()
+}
+
+// formatDuration formats [d] in days if it is a whole number of days.
+func formatDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
@@ -0,0 +1,296 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/connectivity"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	statusPeersFormat string
+
+	ErrValidatorsDisconnected = errors.New("subnet validators are not all connected")
+)
+
+func newStatusPeersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Prints which subnet validators are connected to each other",
+		Long: `
+Queries the peers of every subnet validator in "--validator-uris" (default
+to the connected URI) and prints which validators see each other. Subnet
+validators that cannot see each other are a common cause of stalled
+subnets; the command fails if any queried validator does not see another
+one.
+
+$ subnet-cli status peers \
+--private-uri=http://localhost:49738 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--validator-uris=http://10.0.0.1:9650,http://10.0.0.2:9650,http://10.0.0.3:9650
+
+`,
+		Args: cobra.NoArgs,
+		RunE: statusPeersFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringSliceVar(&validatorURIs, "validator-uris", nil, "API URIs of the subnet validators to query the peers of (default to the connected URI)")
+	cmd.PersistentFlags().StringVar(&statusPeersFormat, "format", "text", "output format (text, json)")
+	return cmd
+}
+
+// nodePeers are the peers a validator API reports, or why it failed.
+type nodePeers struct {
+	URI    string
+	NodeID ids.ShortID
+	Peers  []ids.ShortID
+	Err    error
+}
+
+// peersStatus is the JSON output of "status peers".
+type peersStatus struct {
+	SubnetID string        `json:"subnetID"`
+	Nodes    []peersNode   `json:"nodes"`
+	Missing  []peersLink   `json:"missing"`
+	Isolated []string      `json:"isolated"`
+	Errors   []peersFailed `json:"errors,omitempty"`
+}
+
+type peersNode struct {
+	NodeID  string `json:"nodeID"`
+	URI     string `json:"uri,omitempty"`
+	Queried bool   `json:"queried"`
+}
+
+type peersLink struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type peersFailed struct {
+	URI   string `json:"uri"`
+	Error string `json:"error"`
+}
+
+func statusPeersFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	switch statusPeersFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExportFormat, statusPeersFormat)
+	}
+	cli, info, err := InitReadClient(ctx, privateURI)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	nodes := make([]ids.ShortID, 0, len(ws))
+	for nodeID := range ws {
+		nodes = append(nodes, nodeID)
+	}
+	m := connectivity.New(nodes)
+
+	uris := validatorURIs
+	if len(uris) == 0 {
+		uris = []string{info.uri}
+	}
+	nps := loadNodePeers(ctx, uris)
+	nodeURIs := map[ids.ShortID]string{}
+	for _, np := range nps {
+		if np.Err != nil {
+			continue
+		}
+		if _, ok := ws[np.NodeID]; !ok {
+			color.Outf("{{yellow}}%s (%s) does not validate subnet %s; ignoring it{{/}}\n", np.NodeID.PrefixedString(constants.NodeIDPrefix), redact.String(np.URI), info.subnetID)
+			continue
+		}
+		nodeURIs[np.NodeID] = redact.String(np.URI)
+		m.SetPeers(np.NodeID, np.Peers)
+	}
+
+	st := makePeersStatus(info.subnetID, m, nodeURIs, nps)
+	if statusPeersFormat == "json" {
+		b, err := json.MarshalIndent(st, "", "  ")
+		if err != nil {
+			return err
+		}
+		// machine-readable output is printed even in quiet mode
+		fmt.Fprintln(color.Stdout, string(b))
+	} else {
+		color.Print(makePeersTable(m, nodeURIs))
+		printPeersSummary(m, st)
+	}
+	if len(st.Missing) > 0 {
+		return fmt.Errorf("%w: %d missing connections", ErrValidatorsDisconnected, len(st.Missing))
+	}
+	return nil
+}
+
+// loadNodePeers queries the node ID and the peers of every URI
+// concurrently; a URI that fails does not fail the others.
+func loadNodePeers(ctx context.Context, uris []string) []nodePeers {
+	nps := make([]nodePeers, len(uris))
+	var wg sync.WaitGroup
+	for idx, uri := range uris {
+		nps[idx].URI = uri
+		wg.Add(1)
+		go func(np *nodePeers) {
+			defer wg.Done()
+			np.NodeID, np.Peers, np.Err = loadPeers(ctx, np.URI)
+			if np.Err != nil {
+				np.Err = redact.Error(np.Err)
+			}
+		}(&nps[idx])
+	}
+	wg.Wait()
+	return nps
+}
+
+func loadPeers(ctx context.Context, uri string) (ids.ShortID, []ids.ShortID, error) {
+	cli, _, err := InitClient(ctx, uri, false)
+	if err != nil {
+		return ids.ShortEmpty, nil, err
+	}
+	rnodeID, err := cli.Info().Client().GetNodeID(ctx)
+	if err != nil {
+		return ids.ShortEmpty, nil, err
+	}
+	nodeID, err := ids.ShortFromPrefixedString(rnodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return ids.ShortEmpty, nil, err
+	}
+	ps, err := cli.Info().Client().Peers(ctx)
+	if err != nil {
+		return ids.ShortEmpty, nil, err
+	}
+	peers := make([]ids.ShortID, 0, len(ps))
+	for _, p := range ps {
+		peerID, err := ids.ShortFromPrefixedString(p.ID, constants.NodeIDPrefix)
+		if err != nil {
+			zap.L().Warn("ignoring peer with invalid node ID", zap.String("uri", redact.String(uri)), zap.String("nodeID", p.ID))
+			continue
+		}
+		peers = append(peers, peerID)
+	}
+	return nodeID, peers, nil
+}
+
+func makePeersStatus(subnetID ids.ID, m *connectivity.Matrix, nodeURIs map[ids.ShortID]string, nps []nodePeers) *peersStatus {
+	st := &peersStatus{
+		SubnetID: subnetID.String(),
+		Nodes:    make([]peersNode, 0, len(m.Nodes)),
+		Missing:  []peersLink{},
+		Isolated: []string{},
+	}
+	for _, nodeID := range m.Nodes {
+		st.Nodes = append(st.Nodes, peersNode{
+			NodeID:  nodeID.PrefixedString(constants.NodeIDPrefix),
+			URI:     nodeURIs[nodeID],
+			Queried: m.Queried(nodeID),
+		})
+	}
+	for _, l := range m.Missing() {
+		st.Missing = append(st.Missing, peersLink{
+			From: l.From.PrefixedString(constants.NodeIDPrefix),
+			To:   l.To.PrefixedString(constants.NodeIDPrefix),
+		})
+	}
+	for _, nodeID := range m.Isolated() {
+		st.Isolated = append(st.Isolated, nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	for _, np := range nps {
+		if np.Err != nil {
+			st.Errors = append(st.Errors, peersFailed{URI: redact.String(np.URI), Error: np.Err.Error()})
+		}
+	}
+	return st
+}
+
+// makePeersTable renders the N×N matrix of [m]: the row of a validator
+// shows which validators it sees, by their number.
+func makePeersTable(m *connectivity.Matrix, nodeURIs map[ids.ShortID]string) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+
+	header := []string{"#", "node ID", "URI"}
+	for idx := range m.Nodes {
+		header = append(header, strconv.Itoa(idx+1))
+	}
+	tb.SetHeader(header)
+	for idx, from := range m.Nodes {
+		row := []string{
+			color.F("{{cyan}}%d{{/}}", idx+1),
+			color.F("{{orange}}%s{{/}}", from.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}%s{{/}}", nodeURIs[from]),
+		}
+		for _, to := range m.Nodes {
+			switch m.State(from, to) {
+			case connectivity.Self:
+				row = append(row, color.F("{{light-gray}}-{{/}}"))
+			case connectivity.Connected:
+				row = append(row, color.F("{{green}}✓{{/}}"))
+			case connectivity.Disconnected:
+				row = append(row, color.F("{{red}}{{bold}}✗{{/}}"))
+			default:
+				row = append(row, color.F("{{light-gray}}?{{/}}"))
+			}
+		}
+		tb.Append(row)
+	}
+	tb.Render()
+	return buf.String()
+}
+
+func printPeersSummary(m *connectivity.Matrix, st *peersStatus) {
+	for _, e := range st.Errors {
+		color.Outf("{{red}}failed to query the peers of %s: %s{{/}}\n", e.URI, e.Error)
+	}
+	for _, l := range st.Missing {
+		color.Outf("{{red}}%s does not see %s{{/}}\n", l.From, l.To)
+	}
+	for _, nodeID := range st.Isolated {
+		color.Outf("{{red}}{{bold}}%s is not seen by any queried validator{{/}}\n", nodeID)
+	}
+	unqueried := 0
+	for _, n := range st.Nodes {
+		if !n.Queried {
+			unqueried++
+		}
+	}
+	if unqueried > 0 {
+		color.Outf("{{yellow}}%d of %d validators were not queried; pass their API URIs with --validator-uris{{/}}\n", unqueried, len(m.Nodes))
+	}
+	if len(st.Missing) == 0 && unqueried < len(m.Nodes) {
+		color.Outf("{{green}}all queried validators see every subnet validator{{/}}\n")
+	}
+}
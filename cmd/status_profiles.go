@@ -0,0 +1,203 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	statusProfiles []string
+
+	ErrUnknownProfile     = errors.New("unknown profile")
+	ErrProfilesPrivateURI = errors.New("--profiles and --private-uri are mutually exclusive")
+)
+
+// statusProfile is a network "status network --profiles" queries.
+type statusProfile struct {
+	Name string
+	URI  string
+}
+
+// parseProfiles parses "--profiles" entries: the name of a well-known
+// network (see [publicURIs]), a URI, or "name=URI" (e.g.,
+// "staging=http://10.0.0.1:9650").
+func parseProfiles(ss []string) ([]statusProfile, error) {
+	ps := make([]statusProfile, 0, len(ss))
+	seen := map[string]bool{}
+	for _, s := range ss {
+		s = strings.TrimSpace(s)
+		p := statusProfile{Name: s, URI: s}
+		switch {
+		case strings.Contains(s, "=") && !strings.Contains(strings.SplitN(s, "=", 2)[0], "://"):
+			kv := strings.SplitN(s, "=", 2)
+			p = statusProfile{Name: strings.TrimSpace(kv[0]), URI: strings.TrimSpace(kv[1])}
+		case publicURIs[s] != "":
+			p.URI = publicURIs[s]
+		case !strings.Contains(s, "://") && !strings.HasPrefix(s, "unix:"):
+			return nil, fmt.Errorf("%w: %q (expected %s, a URI or name=URI)", ErrUnknownProfile, s, strings.Join(publicNetworkNames(), ", "))
+		}
+		if p.Name == "" || p.URI == "" {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownProfile, s)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("%w: %q listed twice", ErrUnknownProfile, p.Name)
+		}
+		seen[p.Name] = true
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func publicNetworkNames() []string {
+	names := make([]string, 0, len(publicURIs))
+	for name := range publicURIs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileStatus is the status of one profile, or why it is unavailable.
+type profileStatus struct {
+	Profile string         `json:"profile"`
+	URI     string         `json:"uri"`
+	Status  *networkStatus `json:"status,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// loadProfileStatuses queries every profile concurrently; a profile that
+// fails does not fail the others.
+func loadProfileStatuses(ctx context.Context, ps []statusProfile) []profileStatus {
+	sts := make([]profileStatus, len(ps))
+	var wg sync.WaitGroup
+	for idx, p := range ps {
+		sts[idx] = profileStatus{Profile: p.Name, URI: redact.String(p.URI)}
+		wg.Add(1)
+		go func(idx int, p statusProfile) {
+			defer wg.Done()
+			cli, info, err := InitClient(ctx, p.URI, false)
+			if err != nil {
+				sts[idx].Error = redact.Error(err).Error()
+				return
+			}
+			sts[idx].Status = loadNetworkStatus(ctx, cli, info)
+		}(idx, p)
+	}
+	wg.Wait()
+	return sts
+}
+
+// makeProfilesTable renders one column per profile, highlighting the rows
+// whose values differ between the profiles.
+func makeProfilesTable(sts []profileStatus) string {
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+
+	header := []string{""}
+	for _, st := range sts {
+		header = append(header, st.Profile)
+	}
+	tb.SetHeader(header)
+
+	row := func(name string, value func(*networkStatus) string) {
+		cells := make([]string, 0, len(sts))
+		values := map[string]bool{}
+		for _, st := range sts {
+			if st.Status == nil {
+				cells = append(cells, "")
+				continue
+			}
+			v := value(st.Status)
+			values[v] = true
+			cells = append(cells, v)
+		}
+		if len(values) == 0 {
+			return
+		}
+		c := "{{light-gray}}{{bold}}"
+		if len(values) > 1 {
+			c = "{{yellow}}{{bold}}"
+		}
+		r := []string{color.F("{{magenta}}%s{{/}}", name)}
+		for _, v := range cells {
+			r = append(r, color.F(c+"%s{{/}}", v))
+		}
+		tb.Append(r)
+	}
+
+	uris := []string{color.F("{{cyan}}{{bold}}URI{{/}}")}
+	errs := []string{color.F("{{red}}ERROR{{/}}")}
+	failed := false
+	for _, st := range sts {
+		uris = append(uris, color.F("{{light-gray}}%s{{/}}", st.URI))
+		errs = append(errs, color.F("{{red}}%s{{/}}", st.Error))
+		failed = failed || st.Error != ""
+	}
+	tb.Append(uris)
+	if failed {
+		tb.Append(errs)
+	}
+	row("NETWORK", func(s *networkStatus) string { return fmt.Sprintf("%s (ID %d)", s.NetworkName, s.NetworkID) })
+	row("LATEST UPGRADE", func(s *networkStatus) string { return s.LatestUpgrade })
+	row("MIN VALIDATOR STAKE", func(s *networkStatus) string { return amount.Format(s.MinValidatorStake) })
+	row("MAX VALIDATOR STAKE", func(s *networkStatus) string { return amount.Format(s.MaxValidatorStake) })
+	row("MIN DELEGATOR STAKE", func(s *networkStatus) string { return amount.Format(s.MinDelegatorStake) })
+	row("MIN DELEGATION FEE", func(s *networkStatus) string {
+		return humanize.FormatFloat("#,###.####", s.MinDelegationFeePercent) + "%"
+	})
+	row("MIN STAKE DURATION", func(s *networkStatus) string {
+		return formatDuration(time.Duration(s.MinStakeDurationSeconds) * time.Second)
+	})
+	row("MAX STAKE DURATION", func(s *networkStatus) string {
+		return formatDuration(time.Duration(s.MaxStakeDurationSeconds) * time.Second)
+	})
+	row("CURRENT SUPPLY", func(s *networkStatus) string {
+		if s.CurrentSupply == nil {
+			return "unavailable"
+		}
+		return amount.Format(*s.CurrentSupply)
+	})
+	for _, txType := range feeTxTypes {
+		row(fmt.Sprintf("%s FEE", txType), func(s *networkStatus) string {
+			return formatTxFee(s, txType)
+		})
+	}
+	tb.Render()
+	return buf.String()
+}
+
+// formatTxFee formats the fee of [txType] in [s].
+func formatTxFee(s *networkStatus, txType client.TxType) string {
+	for _, f := range s.Fees {
+		if f.TxType != txType {
+			continue
+		}
+		if f.Fee == nil {
+			return "unavailable"
+		}
+		return amount.Format(*f.Fee)
+	}
+	return "n/a"
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/weights"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// default fraction of subnet weight that must sign a Warp message
+const warpQuorum = 0.67
+
+var ErrWarpNotReady = errors.New("subnet not ready for warp messaging")
+
+func newStatusWarpCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "warp",
+		Short: "Checks whether a subnet is ready for Warp messaging",
+		Long: `
+Checks that every subnet validator has registered a BLS key on the
+P-Chain, reports each validator that has not, and reports the aggregate
+weight available to sign Warp messages.
+
+$ subnet-cli status warp \
+--private-uri=http://localhost:49738 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"
+
+`,
+		RunE: statusWarpFunc,
+	}
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	return cmd
+}
+
+func statusWarpFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitReadClient(ctx, privateURI)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	keys, err := cli.P().GetBLSPublicKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	d := weights.Analyze(ws)
+	signing := uint64(0)
+	missing := []weights.Share{}
+	// read-only: no key is loaded, so the base table (balances) does not apply
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetColWidth(1500)
+	tb.SetCenterSeparator("*")
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.SetRowLine(true)
+	tb.Append([]string{color.F("{{orange}}URI{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", info.uri)})
+	tb.Append([]string{color.F("{{orange}}NETWORK NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", info.networkName)})
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	for _, s := range d.Shares {
+		pk, ok := keys[s.NodeID]
+		status := color.F("{{red}}no BLS key{{/}}")
+		if ok {
+			signing += s.Weight
+			status = color.F("{{green}}%s{{/}}", pk)
+		} else {
+			missing = append(missing, s)
+		}
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", s.NodeID.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}%s{{/}} %s", humanize.Comma(int64(s.Weight)), status),
+		})
+	}
+	fraction := 0.0
+	if d.Total > 0 {
+		fraction = float64(signing) / float64(d.Total)
+	}
+	tb.Append([]string{color.F("{{magenta}}SIGNING WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s / %s (%.1f%%){{/}}", humanize.Comma(int64(signing)), humanize.Comma(int64(d.Total)), 100*fraction)})
+	tb.Render()
+	color.Print(buf.String())
+
+	if len(keys) == 0 {
+		color.Outf("{{yellow}}the connected node reports no BLS keys; it may predate BLS proof-of-possession support{{/}}\n")
+	}
+	for _, s := range missing {
+		color.Outf("{{red}}%s (weight %s) has not registered a BLS key and cannot sign Warp messages{{/}}\n", s.NodeID.PrefixedString(constants.NodeIDPrefix), humanize.Comma(int64(s.Weight)))
+	}
+	if d.Total == 0 || fraction < warpQuorum {
+		color.Outf("{{red}}signing weight %.1f%% is below the %.0f%% quorum{{/}}\n", 100*fraction, 100*warpQuorum)
+		return ErrWarpNotReady
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %d of %d validators have no BLS key", ErrWarpNotReady, len(missing), len(d.Shares))
+	}
+	color.Outf("{{green}}subnet %s is ready for Warp messaging{{/}}\n", info.subnetID)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/telemetry"
+)
+
+// TelemetryCommand implements "subnet-cli telemetry" command.
+func TelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manages the opt-in anonymized usage stats",
+		Long: `
+Telemetry is off unless turned on. When on, each run reports the command
+(e.g., "subnet-cli create subnet"), its duration, the class of its error
+(e.g., timeout), the OS and a random installation ID. Flags, arguments,
+keys, addresses and IDs are never reported. Setting DO_NOT_TRACK turns it
+off regardless of the setting.
+
+$ subnet-cli telemetry status
+
+`,
+	}
+	cmd.AddCommand(
+		newTelemetryOnCommand(),
+		newTelemetryOffCommand(),
+		newTelemetryStatusCommand(),
+	)
+	return cmd
+}
+
+func loadTelemetry() (*telemetry.Config, error) {
+	path, err := telemetry.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return telemetry.Load(path)
+}
+
+// reportTelemetry reports a run of [cmd] if telemetry is on. Failures are
+// only logged, so telemetry never affects the command outcome.
+func reportTelemetry(ctx context.Context, cmd *cobra.Command, took time.Duration, err error) {
+	if cmd == nil || cmd.Parent() != nil && cmd.Parent().Name() == "telemetry" {
+		return
+	}
+	c, lerr := loadTelemetry()
+	if lerr != nil {
+		zap.L().Debug("failed to load telemetry config", zap.Error(lerr))
+		return
+	}
+	if rerr := c.Report(ctx, c.NewEvent(cmd.CommandPath(), took, err)); rerr != nil {
+		zap.L().Debug("failed to report telemetry", zap.Error(rerr))
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newTelemetryOffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "off",
+		Short: "Turns off the anonymized usage stats",
+		Long: `
+Turns off the anonymized usage stats and forgets the installation ID.
+
+$ subnet-cli telemetry off
+
+`,
+		Args: cobra.NoArgs,
+		RunE: telemetryOffFunc,
+	}
+	return cmd
+}
+
+func telemetryOffFunc(cmd *cobra.Command, args []string) error {
+	c, err := loadTelemetry()
+	if err != nil {
+		return err
+	}
+	c.Disable()
+	if err := c.Save(); err != nil {
+		return err
+	}
+	color.Outf("{{green}}telemetry off{{/}}\n")
+	return nil
+}
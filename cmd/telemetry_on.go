@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var telemetryEndpoint string
+
+func newTelemetryOnCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "on",
+		Short: "Turns on the anonymized usage stats",
+		Long: `
+Turns on the anonymized usage stats, sent to "--endpoint"; release builds
+have no default endpoint.
+
+$ subnet-cli telemetry on --endpoint=[STATS URL]
+
+`,
+		Args: cobra.NoArgs,
+		RunE: telemetryOnFunc,
+	}
+	cmd.PersistentFlags().StringVar(&telemetryEndpoint, "endpoint", "", "URL to send the stats to (required: release builds have no default endpoint, so nothing is sent without it)")
+	return cmd
+}
+
+func telemetryOnFunc(cmd *cobra.Command, args []string) error {
+	c, err := loadTelemetry()
+	if err != nil {
+		return err
+	}
+	if err := c.Enable(); err != nil {
+		return err
+	}
+	if telemetryEndpoint != "" {
+		c.Endpoint = telemetryEndpoint
+	}
+	if err := c.Save(); err != nil {
+		return err
+	}
+	color.Outf("{{green}}telemetry on, thanks!{{/}}\n")
+	if c.URL() == "" {
+		color.Outf("{{yellow}}this build has no telemetry endpoint, so nothing is sent until one is set with --endpoint{{/}}\n")
+	}
+	return nil
+}
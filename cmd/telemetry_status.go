@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/telemetry"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newTelemetryStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows whether the anonymized usage stats are on",
+		Long: `
+Shows whether the anonymized usage stats are on, and where they are sent.
+
+$ subnet-cli telemetry status
+
+`,
+		Args: cobra.NoArgs,
+		RunE: telemetryStatusFunc,
+	}
+	return cmd
+}
+
+func telemetryStatusFunc(cmd *cobra.Command, args []string) error {
+	c, err := loadTelemetry()
+	if err != nil {
+		return err
+	}
+	_, disabled := os.LookupEnv(telemetry.EnvDisable)
+	switch {
+	case c.Active():
+		color.Outf("{{green}}telemetry on{{/}} (sent to %s as %s)\n", c.URL(), c.ID)
+	case !c.Enabled:
+		color.Outf("{{yellow}}telemetry off{{/}}\n")
+	case disabled:
+		color.Outf("{{yellow}}telemetry on but disabled by %s{{/}}\n", telemetry.EnvDisable)
+	default:
+		color.Outf("{{yellow}}telemetry on but this build has no endpoint (set one with \"telemetry on --endpoint\"){{/}}\n")
+	}
+	color.Result(c.Active())
+	return nil
+}
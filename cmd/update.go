@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/update"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const updateTimeout = 5 * time.Minute
+
+var (
+	updateCheckOnly  bool
+	updateReleaseURL string
+)
+
+// UpdateCommand implements "subnet-cli update" command.
+func UpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Updates subnet-cli to the latest release",
+		Long: `
+Checks the latest GitHub release and, if newer, downloads the archive for
+this platform, verifies it against the release checksums (signed with the
+release key embedded in subnet-cli) and replaces the running binary in place (the previous one is kept as ".old"). Outdated
+CLIs can build txs the network no longer accepts (e.g., after codec or fee
+changes).
+
+$ subnet-cli update --check
+$ subnet-cli update
+
+`,
+		Args: cobra.NoArgs,
+		RunE: updateFunc,
+	}
+	cmd.PersistentFlags().BoolVar(&updateCheckOnly, "check", false, "'true' to only check for a newer release")
+	cmd.PersistentFlags().StringVar(&updateReleaseURL, "release-url", update.LatestReleaseURL, "GitHub API URL of the release to update to")
+	return cmd
+}
+
+func updateFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), updateTimeout)
+	defer cancel()
+
+	rel, err := update.Latest(ctx, updateReleaseURL)
+	if err != nil {
+		return err
+	}
+	latest := rel.Version()
+	if version.Version == version.Dev {
+		color.Outf("{{yellow}}this is a development build; the latest release is %s{{/}}\n", latest)
+		if !force {
+			color.Outf("{{yellow}}use --force to replace it{{/}}\n")
+			return nil
+		}
+	} else {
+		newer, err := update.Newer(version.Version, latest)
+		if err != nil {
+			return err
+		}
+		if !newer && !force {
+			color.Outf("{{green}}subnet-cli %s is up to date{{/}}\n", version.Version)
+			color.Result(version.Version)
+			return nil
+		}
+		color.Outf("{{blue}}subnet-cli %s is available (running %s){{/}}\n", latest, version.Version)
+	}
+	if updateCheckOnly {
+		color.Result(latest)
+		return nil
+	}
+
+	if enablePrompt {
+		prompt := promptui.Select{
+			Label:  color.F("\n{{blue}}{{bold}}Update to %s?{{/}}", latest),
+			Stdout: os.Stdout,
+			Items: []string{
+				color.F("{{green}}Yes, update!{{/}}"),
+				color.F("{{red}}No, stop it!{{/}}"),
+			},
+		}
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		if idx == 1 {
+			return nil
+		}
+	}
+
+	archiveName := update.ArchiveName(latest, runtime.GOOS, runtime.GOARCH)
+	archiveURL, err := rel.Asset(archiveName)
+	if err != nil {
+		return err
+	}
+	checksumsURL, err := rel.Asset(update.ChecksumsName(latest))
+	if err != nil {
+		return err
+	}
+	signatureURL, err := rel.Asset(update.SignatureName(latest))
+	if err != nil {
+		return err
+	}
+	color.Outf("{{yellow}}downloading %s...{{/}}\n", archiveURL)
+	archive, err := update.Download(ctx, archiveURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := update.Download(ctx, checksumsURL)
+	if err != nil {
+		return err
+	}
+	sig, err := update.Download(ctx, signatureURL)
+	if err != nil {
+		return err
+	}
+	if err := update.VerifySignature(checksums, sig); err != nil {
+		return err
+	}
+	if err := update.VerifyChecksum(archive, checksums, archiveName); err != nil {
+		return err
+	}
+	color.Outf("{{green}}verified the signed checksum of %s{{/}}\n", archiveName)
+
+	binName := "subnet-cli"
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	bin, err := update.ExtractBinary(archive, binName)
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	if err := update.Replace(exe, bin); err != nil {
+		return fmt.Errorf("failed to replace %q (is it writable?): %w", exe, err)
+	}
+	color.Outf("{{green}}updated %q to %s{{/}}\n", exe, latest)
+	color.Result(latest)
+	return nil
+}
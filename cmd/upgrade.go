@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// txDisabledBy are the upgrades after which the network rejects the tx
+// formats this build issues. The fee mechanism follows the network state on
+// its own (see [client.NewFeeCalculator]).
+var txDisabledBy = map[client.TxType]string{
+	// primary network validators must use AddPermissionlessValidatorTx
+	client.TxTypeAddValidator: version.Durango,
+}
+
+// upgradeActive returns true if the upgrade [name] is active on the
+// connected network.
+func (i *Info) upgradeActive(name string) bool {
+	return version.IsActive(i.upgrades, name)
+}
+
+// requireUpgrade fails unless the upgrade [name], which introduced the
+// txs of [feature], is active on the connected network.
+func (i *Info) requireUpgrade(name string, feature string) error {
+	if i.upgradeActive(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q has not activated %s (%s)", ErrUpgradeInactive, i.networkName, name, feature)
+}
+
+// CheckTxFormat refuses to issue a [txType] tx the connected network no
+// longer accepts, unless "--force" is set.
+func (i *Info) CheckTxFormat(txType client.TxType) error {
+	up, ok := txDisabledBy[txType]
+	if !ok || !i.upgradeActive(up) {
+		return nil
+	}
+	err := fmt.Errorf("%w: %s is rejected since %s on %q and this build only creates its pre-%s format", ErrUnsupportedUpgrade, txType, up, i.networkName, up)
+	if force {
+		color.Outf("{{yellow}}%v (--force set){{/}}\n", err)
+		return nil
+	}
+	color.Outf("{{red}}{{bold}}%v; use a subnet-cli built against avalanchego %s or later{{/}}\n", err, nodeVersionOf(up))
+	return err
+}
+
+// warnUpgrades warns once that the connected network runs upgrades whose
+// tx formats this build can't create.
+func (i *Info) warnUpgrades() {
+	latest, ok := version.Latest(i.upgrades)
+	if !ok || latest.Supported {
+		return
+	}
+	color.Errf("{{yellow}}%q runs %s; subnet-cli only creates %s-era txs (plus RemoveSubnetValidatorTx and the ACP-77 L1 txs), so other txs of later upgrades (e.g., AddPermissionlessValidatorTx) are unavailable{{/}}\n", i.networkName, latest.Name, supportedUpgrade())
+}
+
+func supportedUpgrade() string {
+	name := ""
+	for _, u := range version.Upgrades {
+		if u.Supported {
+			name = u.Name
+		}
+	}
+	return name
+}
+
+func nodeVersionOf(name string) string {
+	for _, u := range version.Upgrades {
+		if u.Name == name {
+			return u.NodeVersion
+		}
+	}
+	return ""
+}
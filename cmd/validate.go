@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/key"
+	"github.com/ava-labs/subnet-cli/internal/spec"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	ErrInvalidSpec  = errors.New("invalid spec")
+	ErrNoPrivateKey = errors.New("no private key path in spec or flags")
+)
+
+// ValidateCommand implements "subnet-cli validate" command.
+func ValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates a deployment spec without touching the network",
+		Long: `
+Checks the schema version, referential integrity, amounts, durations and
+key availability of a deployment spec. No network call is made.
+
+$ subnet-cli validate -f spec.yaml
+
+`,
+		RunE: validateFunc,
+	}
+	cmd.PersistentFlags().StringVarP(&specPath, "file", "f", "", "deployment spec file path")
+	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", "", "private key file path (overrides the spec)")
+	cmd.PersistentFlags().BoolVarP(&useLedger, "ledger", "l", false, "sign with ledger (skips the key check)")
+	return cmd
+}
+
+func validateFunc(cmd *cobra.Command, args []string) error {
+	s, err := spec.Load(specPath)
+	if err != nil {
+		return err
+	}
+
+	var vs spec.Violations
+	if err := s.Validate(); err != nil {
+		if !errors.As(err, &vs) {
+			return err
+		}
+	}
+
+	switch {
+	case useLedger:
+		color.Outf("{{yellow}}--ledger set; skipping key check{{/}}\n")
+	default:
+		keyPath := s.KeyPath()
+		if privKeyPath != "" {
+			keyPath = privKeyPath
+		}
+		// unknown networks are already reported by the spec validation
+		networkID, _ := s.NetworkID()
+		if keyPath == "" {
+			vs = append(vs, ErrNoPrivateKey)
+		} else if _, err := key.LoadSoft(networkID, keyPath); err != nil {
+			vs = append(vs, fmt.Errorf("private-key-path: %s: %w", keyPath, err))
+		}
+	}
+
+	for _, nodeID := range s.ExternalValidators() {
+		color.Outf("{{yellow}}%s is not added as a primary network validator by the spec; it must already validate{{/}}\n", nodeID)
+	}
+	for _, v := range vs {
+		color.Outf("{{red}}%v{{/}}\n", v)
+	}
+	if len(vs) > 0 {
+		return fmt.Errorf("%w: %d violation(s)", ErrInvalidSpec, len(vs))
+	}
+	color.Outf("{{green}}%s is valid (%d steps){{/}}\n", specPath, len(s.Plan()))
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/client"
+	internal_platformvm "github.com/ava-labs/subnet-cli/internal/platformvm"
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+	"github.com/ava-labs/subnet-cli/internal/window"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const (
+	defaultValidateStartBuffer = 30 * time.Second
+
+	// minimum time between now and the validate start so that the tx can
+	// be accepted before the window opens
+	validatePropagationBuffer = 10 * time.Second
+
+	// maximum number of re-issues of a tx whose validate start passed
+	maxStartTimeRefreshes = 3
+)
+
+// validateWindowFlags are the "--validate-start", "--validate-end" and
+// "--duration" flags of the commands that add primary network validators.
+type validateWindowFlags struct {
+	start    string
+	end      string
+	duration string
+}
+
+func (w *validateWindowFlags) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&w.start, "validate-start", "", "validate start in RFC3339 format or relative (e.g., now+5m) (default to 30 seconds after issuing)")
+	cmd.PersistentFlags().StringVar(&w.end, "validate-end", defaultValidateEnd, "validate end in RFC3339 format or relative (e.g., now+14d)")
+	cmd.PersistentFlags().StringVar(&w.duration, "duration", "", "validate duration from start (e.g., 14d, 336h); overrides --validate-end")
+}
+
+// ParseValidateWindow resolves [w] into [i.validateStart] and
+// [i.validateEnd].
+func ParseValidateWindow(i *Info, w validateWindowFlags) (err error) {
+	now := time.Now()
+	i.validateStart = now.Add(defaultValidateStartBuffer)
+	i.fixedValidateStart = w.start != ""
+	if i.fixedValidateStart {
+		i.validateStart, err = timeexpr.Parse(w.start, now)
+		if err != nil {
+			return err
+		}
+	}
+	if w.duration != "" {
+		d, err := timeexpr.ParseDuration(w.duration)
+		if err != nil {
+			return err
+		}
+		i.validateEnd = i.validateStart.Add(d)
+		return nil
+	}
+	i.validateEnd, err = timeexpr.Parse(w.end, now)
+	return err
+}
+
+// CheckValidateWindows runs the window sanity checks for every node in
+// [i.nodeIDs] that is about to be added to [i.subnetID], and reports all
+// violations at once before any tx is issued. Primary network windows are
+// staggered by [defaultStagger] per node.
+func CheckValidateWindows(ctx context.Context, cli client.Client, i *Info) error {
+	failed := false
+	for idx, nodeID := range i.nodeIDs {
+		w := window.Window{Start: i.validateStart, End: i.validateEnd}
+		opts := []window.OpOption{
+			window.WithPropagationBuffer(validatePropagationBuffer),
+			window.WithDurationLimits(i.params.minStakeDuration, i.params.maxStakeDuration),
+		}
+		if i.subnetID == ids.Empty {
+			w.End = w.End.Add(time.Duration(idx) * defaultStagger)
+		} else {
+			start, end, err := cli.P().GetValidator(ctx, ids.Empty, nodeID)
+			if err != nil {
+				if errors.Is(err, client.ErrValidatorNotFound) && i.subnetOnlyValidators() {
+					i.printSOVGuidance([]ids.ShortID{nodeID})
+				}
+				return fmt.Errorf("%w: primary network validator %s", err, nodeID)
+			}
+			opts = append(opts, window.WithPrimary(window.Window{Start: start, End: end}))
+			if w.End.IsZero() {
+				// subnet validation defaults to ending with the primary one
+				w.End = end
+			}
+		}
+
+		start, end, err := cli.P().GetPendingValidator(ctx, i.subnetID, nodeID)
+		switch {
+		case err == nil:
+			opts = append(opts, window.WithPending(window.Window{Start: start, End: end}))
+		case !errors.Is(err, client.ErrValidatorNotFound):
+			return err
+		}
+
+		var vs window.Violations
+		if errors.As(window.Check(w, opts...), &vs) {
+			failed = true
+			color.Outf("{{red}}invalid validate window %s for %s:{{/}}\n", w, nodeID)
+			for _, v := range vs {
+				color.Outf("{{red}}  - %v{{/}}\n", v)
+			}
+		}
+	}
+	if failed {
+		return ErrInvalidValidateWindow
+	}
+	return nil
+}
+
+// refreshStartTime calls [issue] and, if the tx fails because
+// [i.validateStart] passed before it was accepted (e.g., while waiting for
+// the batch or retrying), shifts the window forward to start
+// [defaultValidateStartBuffer] from now and re-issues it, after
+// confirmation if prompts are enabled. If [keepDuration] is set, the end
+// moves by the same amount; otherwise it stays (e.g., bounded by the
+// primary network validation).
+func refreshStartTime(i *Info, keepDuration bool, issue func() (time.Duration, error)) (time.Duration, error) {
+	for n := 0; ; n++ {
+		took, err := issue()
+		if n == maxStartTimeRefreshes || !internal_platformvm.StartTimeExpired(err) {
+			return took, err
+		}
+
+		start := time.Now().Add(defaultValidateStartBuffer)
+		end := i.validateEnd
+		if keepDuration {
+			end = end.Add(start.Sub(i.validateStart))
+		}
+		color.Outf("{{yellow}}validate start %s passed before the tx was accepted{{/}}\n", i.validateStart.Format(time.RFC3339))
+		if enablePrompt {
+			prompt := promptui.Select{
+				Label:  color.F("{{blue}}{{bold}}Re-issue with validate window %s - %s?{{/}}", start.Format(time.RFC3339), end.Format(time.RFC3339)),
+				Stdout: os.Stdout,
+				Items: []string{
+					color.F("{{green}}Yes, re-issue! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
+					color.F("{{red}}No, stop it!{{/}}"),
+				},
+			}
+			idx, _, perr := prompt.Run()
+			if perr != nil || idx == 1 {
+				return took, err
+			}
+		}
+		color.Outf("{{yellow}}re-issuing with validate window %s - %s{{/}}\n", start.Format(time.RFC3339), end.Format(time.RFC3339))
+		i.validateStart, i.validateEnd = start, end
+	}
+}
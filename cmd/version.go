@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/version"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var versionFormat string
+
+// VersionCommand implements "subnet-cli version" command.
+func VersionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Prints the build metadata",
+		Long: `
+Prints the version, git commit, build date, avalanchego dependency and the
+network upgrades this build supports; attach the JSON output to bug reports.
+
+$ subnet-cli version --format=json
+
+`,
+		Args: cobra.NoArgs,
+		RunE: versionFunc,
+	}
+	cmd.PersistentFlags().StringVar(&versionFormat, "format", "text", "output format (text, json)")
+	return cmd
+}
+
+func versionFunc(cmd *cobra.Command, args []string) error {
+	v := version.Get()
+	switch versionFormat {
+	case "json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		// machine-readable output is printed even in quiet mode
+		fmt.Fprintln(color.Stdout, string(b))
+		return nil
+	case "text":
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExportFormat, versionFormat)
+	}
+
+	color.Outf("{{bold}}subnet-cli %s{{/}}\n", v.Version)
+	if v.Commit != "" {
+		color.Outf("commit:      %s\n", v.Commit)
+	}
+	if v.Date != "" {
+		color.Outf("built:       %s\n", v.Date)
+	}
+	color.Outf("go:          %s (%s)\n", v.GoVersion, v.Platform)
+	color.Outf("avalanchego: %s\n", v.Avalanchego)
+	supported, unsupported := []string{}, []string{}
+	for _, u := range v.Upgrades {
+		if u.Supported {
+			supported = append(supported, u.Name)
+		} else {
+			unsupported = append(unsupported, u.Name)
+		}
+	}
+	color.Outf("upgrades:    {{green}}%s{{/}}", strings.Join(supported, ", "))
+	if len(unsupported) > 0 {
+		color.Outf(" {{light-gray}}(not supported: %s){{/}}", strings.Join(unsupported, ", "))
+	}
+	color.Outf("\n")
+	color.Result(v.Version)
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/alert"
+	"github.com/ava-labs/subnet-cli/internal/redact"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+var (
+	alertRulesPath string
+	watchInterval  time.Duration
+	watchOnce      bool
+
+	ErrAlertFiring = errors.New("alert firing")
+)
+
+// WatchCommand implements "subnet-cli watch" command.
+func WatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watches a subnet and reports alerts",
+		Long: `
+Polls the validators and the chain heights of a subnet every "--interval"
+and evaluates the alert rules of "--rules" against them. An alert is
+reported once when its rule starts holding (and once when it resolves),
+with the actions of its rule: "log" prints it, "webhook" posts it as JSON
+to the webhook URL of the rules file and "exit" stops watching with a
+non-zero exit code.
+
+$ cat alerts.yaml
+webhook: https://hooks.example.com/subnet
+rules:
+  - name: low uptime
+    when: validator uptime < 85%
+    actions: [log, webhook]
+  - when: validator expires < 7d
+  - when: chain height stalled 5m
+    actions: [log, webhook, exit]
+
+$ subnet-cli watch \
+--public-uri=https://api.avax-test.network \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1" \
+--rules=alerts.yaml \
+--interval=1m
+
+With "--once", evaluates the rules once (e.g., from cron) and exits with a
+non-zero code if a rule with the "exit" action holds. Stalled chains need
+two polls, so they are only reported while watching.
+
+`,
+		Args: cobra.NoArgs,
+		RunE: watchFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID or name (see \"subnet-cli name\")")
+	cmd.PersistentFlags().StringVar(&alertRulesPath, "rules", "", "YAML file of alert rules")
+	cmd.PersistentFlags().DurationVar(&watchInterval, "interval", time.Minute, "interval between polls")
+	cmd.PersistentFlags().BoolVar(&watchOnce, "once", false, "'true' to evaluate the rules once and exit")
+	return cmd
+}
+
+func watchFunc(cmd *cobra.Command, args []string) error {
+	if alertRulesPath == "" {
+		return fmt.Errorf("%w: --rules is required", alert.ErrInvalidRules)
+	}
+	rules, err := alert.Load(alertRulesPath)
+	if err != nil {
+		return err
+	}
+	// the agent runs until interrupted, so each poll has its own deadline
+	ctx, cancel := stepContext(cmd.Context())
+	cli, info, err := InitClient(ctx, publicURI, false)
+	cancel()
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = resolveSubnetID(info.networkID, subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	e := alert.NewEngine(rules)
+	color.Outf("{{blue}}watching subnet %s with %d rule(s) every %s{{/}}\n", info.subnetID, len(rules.Rules), watchInterval)
+	for {
+		ctx, cancel := stepContext(cmd.Context())
+		s, err := loadSnapshot(ctx, cli, info.subnetID)
+		if err == nil {
+			fired, resolved := e.Evaluate(s)
+			err = reportAlerts(ctx, rules, fired, resolved)
+			cancel()
+			if err != nil {
+				return err
+			}
+		} else {
+			cancel()
+			if cmd.Context().Err() != nil {
+				return nil
+			}
+			// a node hiccup should not stop the agent
+			color.Outf("{{red}}failed to poll subnet %s: %v{{/}}\n", info.subnetID, err)
+			if watchOnce {
+				return err
+			}
+		}
+		if watchOnce {
+			return nil
+		}
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// loadSnapshot collects the current validators of [subnetID], with the
+// uptime the connected node observes of their primary network validation,
+// and the heights of its chains on the connected node.
+func loadSnapshot(ctx context.Context, cli client.Client, subnetID ids.ID) (alert.Snapshot, error) {
+	s := alert.Snapshot{Time: time.Now()}
+	vs, err := cli.P().GetValidators(ctx, subnetID)
+	if err != nil {
+		return s, err
+	}
+	uptimes := map[ids.ShortID]*float64{}
+	if subnetID != constants.PrimaryNetworkID {
+		pvs, err := cli.P().GetValidators(ctx, ids.Empty)
+		if err != nil {
+			return s, err
+		}
+		for _, v := range pvs {
+			uptimes[v.NodeID] = v.Uptime
+		}
+	}
+	for _, v := range vs {
+		if v.Pending {
+			continue
+		}
+		uptime := v.Uptime
+		if uptime == nil {
+			uptime = uptimes[v.NodeID]
+		}
+		s.Validators = append(s.Validators, alert.Validator{
+			NodeID: v.NodeID.PrefixedString(constants.NodeIDPrefix),
+			Uptime: uptime,
+			End:    v.End,
+		})
+	}
+
+	bcs, err := cli.P().Client().GetBlockchains(ctx)
+	if err != nil {
+		return s, err
+	}
+	for _, bc := range bcs {
+		if bc.SubnetID != subnetID {
+			continue
+		}
+		c := alert.Chain{ID: bc.ID.String(), Name: bc.Name}
+		c.Height, err = fetchChainHeight(ctx, cli, bc.ID)
+		if err != nil {
+			zap.L().Debug("failed to fetch chain height", zap.Stringer("blockchainID", bc.ID), zap.Error(err))
+		}
+		c.Known = err == nil
+		s.Chains = append(s.Chains, c)
+	}
+	return s, nil
+}
+
+// reportAlerts runs the actions of the [fired] and [resolved] alerts, and
+// returns [ErrAlertFiring] if a fired alert has the "exit" action.
+func reportAlerts(ctx context.Context, rules *alert.Config, fired []alert.Alert, resolved []alert.Alert) error {
+	hooked := []alert.Alert{}
+	for _, a := range fired {
+		if a.Has(alert.ActionLog) {
+			color.Outf("{{red}}{{bold}}ALERT{{/}} {{red}}[%s] %s{{/}}\n", a.Rule, a.Message)
+			zap.L().Warn("alert firing", zap.String("rule", a.Rule), zap.String("subject", a.Subject), zap.String("message", a.Message))
+		}
+		if a.Has(alert.ActionWebhook) {
+			hooked = append(hooked, a)
+		}
+	}
+	for _, a := range resolved {
+		if a.Has(alert.ActionLog) {
+			color.Outf("{{green}}RESOLVED [%s] %s{{/}}\n", a.Rule, a.Subject)
+			zap.L().Info("alert resolved", zap.String("rule", a.Rule), zap.String("subject", a.Subject))
+		}
+		if a.Has(alert.ActionWebhook) {
+			hooked = append(hooked, a)
+		}
+	}
+	if len(hooked) > 0 {
+		err := alert.Post(ctx, rules.Webhook, hooked)
+		if err != nil {
+			// the agent keeps watching; the alerts are still logged
+			color.Outf("{{red}}failed to post %d alert(s) to the webhook: %v{{/}}\n", len(hooked), redact.Error(err))
+		}
+	}
+	for _, a := range fired {
+		if a.Has(alert.ActionExit) {
+			return fmt.Errorf("%w: [%s] %s", ErrAlertFiring, a.Rule, a.Message)
+		}
+	}
+	return nil
+}
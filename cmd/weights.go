@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// WeightsCommand implements "subnet-cli weights" command.
+func WeightsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "weights",
+		Short: "Sub-commands for planning subnet validator weights",
+	}
+	cmd.AddCommand(
+		newWeightsSuggestCommand(),
+		newWeightsShowCommand(),
+	)
+	return cmd
+}
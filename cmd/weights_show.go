@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/weights"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+func newWeightsShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Shows the current weight distribution of a subnet",
+		Long: `
+Shows the current validator weight distribution of a subnet and flags
+dangerous configurations.
+
+$ subnet-cli weights show \
+--public-uri=http://localhost:52250 \
+--subnet-id="24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"
+
+`,
+		RunE: weightsShowFunc,
+	}
+	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
+	cmd.PersistentFlags().StringVar(&subnetIDs, "subnet-id", "", "subnet ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "'true' to bypass the local cache of validator/subnet lookups")
+	return cmd
+}
+
+func weightsShowFunc(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	cli, info, err := InitReadClient(ctx, publicURI)
+	if err != nil {
+		return err
+	}
+	info.subnetID, err = ids.FromString(subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	ws, err := cli.P().GetValidatorWeights(ctx, info.subnetID)
+	if err != nil {
+		return err
+	}
+	d := weights.Analyze(ws)
+	// the weights of elastic subnet validators are their stake
+	info.loadStakeAsset(ctx, cli)
+	weight := func(w uint64) string {
+		if info.stakeAsset.IsAVAX() {
+			return humanize.Comma(int64(w))
+		}
+		return amount.FormatAsset(w, info.stakeAsset.Denomination, info.stakeAsset.Symbol)
+	}
+
+	buf, tb := BaseTableSetup(info)
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(info.networkID, names.KindSubnet, info.subnetID))})
+	tb.Append([]string{color.F("{{magenta}}TOTAL WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", weight(d.Total))})
+	for _, s := range d.Shares {
+		tb.Append([]string{
+			color.F("{{orange}}%s{{/}}", s.NodeID.PrefixedString(constants.NodeIDPrefix)),
+			color.F("{{light-gray}}{{bold}}%s{{/}} (%.1f%%)", weight(s.Weight), 100*s.Fraction),
+		})
+	}
+	tb.Append([]string{color.F("{{green}}TOLERATED OFFLINE{{/}}"), color.F("{{light-gray}}{{bold}}%d{{/}}", d.ToleratedFaults())})
+	tb.Render()
+	color.Print(buf.String())
+
+	for _, w := range d.Warnings {
+		color.Outf("{{yellow}}warning: %s{{/}}\n", w)
+	}
+	return nil
+}
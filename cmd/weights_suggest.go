@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/subnet-cli/internal/weights"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+// weightsSuggestOptions are the flags of "weights suggest".
+type weightsSuggestOptions struct {
+	validators     int
+	faultTolerance int
+	weight         uint64
+}
+
+func newWeightsSuggestCommand() *cobra.Command {
+	o := &weightsSuggestOptions{}
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Recommends per-validator weights",
+		Long: `
+Recommends per-validator weights for a permissioned subnet given a target
+validator count and the number of validators that may be offline at once.
+
+$ subnet-cli weights suggest --validators=5 --fault-tolerance=1
+
+`,
+		RunE: o.run,
+	}
+	cmd.PersistentFlags().IntVar(&o.validators, "validators", 5, "target number of subnet validators")
+	cmd.PersistentFlags().IntVar(&o.faultTolerance, "fault-tolerance", 1, "number of validators that may be offline at once")
+	cmd.PersistentFlags().Uint64Var(&o.weight, "validate-weight", defaultValidateWeight, "weight of each validator")
+	return cmd
+}
+
+func (o *weightsSuggestOptions) run(cmd *cobra.Command, args []string) error {
+	s, err := weights.Suggest(o.validators, o.faultTolerance, o.weight)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tb := tablewriter.NewWriter(buf)
+	tb.SetAutoWrapText(false)
+	tb.SetCenterSeparator("*")
+	tb.SetRowLine(true)
+	tb.SetAlignment(tablewriter.ALIGN_LEFT)
+	tb.Append([]string{color.F("{{orange}}VALIDATORS{{/}}"), color.F("{{light-gray}}{{bold}}%d{{/}}", s.Validators)})
+	tb.Append([]string{color.F("{{magenta}}EACH VALIDATE WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(s.Weight)))})
+	tb.Append([]string{color.F("{{magenta}}TOTAL WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(s.Total)))})
+	tb.Append([]string{color.F("{{green}}TOLERATED OFFLINE{{/}}"), color.F("{{light-gray}}{{bold}}%d{{/}}", s.Tolerated)})
+	tb.Render()
+	color.Print(buf.String())
+
+	for _, w := range s.Warnings {
+		color.Outf("{{yellow}}warning: %s{{/}}\n", w)
+	}
+	return nil
+}
@@ -4,181 +4,287 @@
 package cmd
 
 import (
-	"context"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/dustin/go-humanize"
 	"github.com/manifoldco/promptui"
-	"github.com/onsi/ginkgo/v2/formatter"
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/subnet-cli/client"
+	"github.com/ava-labs/subnet-cli/internal/names"
+	"github.com/ava-labs/subnet-cli/internal/rollback"
+	"github.com/ava-labs/subnet-cli/internal/version"
 	"github.com/ava-labs/subnet-cli/pkg/color"
 )
 
+// wizardOptions are the flags of "wizard".
+type wizardOptions struct {
+	nodeIDs       []string
+	window        validateWindowFlags
+	chainName     string
+	vmID          string
+	vmGenesisPath string
+	l1            bool
+}
+
 // WizardCommand implements "subnet-cli wizard" command.
 func WizardCommand() *cobra.Command {
+	o := &wizardOptions{}
 	cmd := &cobra.Command{
 		Use:   "wizard",
 		Short: "A magical command for creating an entire subnet",
-		RunE:  wizardFunc,
+		RunE:  o.run,
 	}
 
 	// "create subnet"
 	cmd.PersistentFlags().StringVar(&publicURI, "public-uri", "https://api.avax-test.network", "URI for avalanche network endpoints")
-	cmd.PersistentFlags().StringVar(&privKeyPath, "private-key-path", ".subnet-cli.pk", "private key file path")
-	cmd.PersistentFlags().BoolVarP(&useLedger, "ledger", "l", false, "use ledger to sign transactions")
+	addSignerFlags(cmd)
 
 	// "add validator"
-	cmd.PersistentFlags().StringSliceVar(&nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
-	end := time.Now().Add(defaultValDuration)
-	cmd.PersistentFlags().StringVar(&validateEnds, "validate-end", end.Format(time.RFC3339), "validate start timestamp in RFC3339 format")
+	cmd.PersistentFlags().StringSliceVar(&o.nodeIDs, "node-ids", nil, "a list of node IDs (must be formatted in ids.ID)")
+	o.window.addFlags(cmd)
+	cmd.PersistentFlags().BoolVar(&o.l1, "l1", false, "'true' to leave out the nodes that are not primary network validators instead of staking them, to validate the subnet once it is converted to an L1 (requires Etna)")
 
 	// "create blockchain"
-	cmd.PersistentFlags().StringVar(&chainName, "chain-name", "", "chain name")
-	cmd.PersistentFlags().StringVar(&vmIDs, "vm-id", "", "VM ID (must be formatted in ids.ID)")
-	cmd.PersistentFlags().StringVar(&vmGenesisPath, "vm-genesis-path", "", "VM genesis file path")
+	cmd.PersistentFlags().StringVar(&o.chainName, "chain-name", "", "chain name")
+	cmd.PersistentFlags().StringVar(&o.vmID, "vm-id", "", "VM ID (must be formatted in ids.ID)")
+	cmd.PersistentFlags().StringVar(&o.vmGenesisPath, "vm-genesis-path", "", "VM genesis file path")
+	addRPCEndpointFlags(cmd)
+	addFailureReportFlag(cmd)
 
 	return cmd
 }
 
-func wizardFunc(cmd *cobra.Command, args []string) error {
-	cli, info, err := InitClient(publicURI, true)
+var errNoNodeIDs = errors.New("no NodeIDs provided")
+
+func (o *wizardOptions) run(cmd *cobra.Command, args []string) (err error) {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if err := checkRPCEndpointFlags(); err != nil {
+		return err
+	}
+	cli, info, err := InitClient(ctx, publicURI, true)
 	if err != nil {
 		return err
 	}
 
-	if len(nodeIDs) == 0 {
-		return errors.New("no NodeIDs provided")
+	if len(o.nodeIDs) == 0 {
+		return errNoNodeIDs
 	}
 
 	// Parse Args
 	info.subnetID = ids.Empty
-	if err := ParseNodeIDs(cli, info); err != nil {
+	if err := ParseNodeIDs(ctx, cli, info, o.nodeIDs); err != nil {
 		return err
 	}
-	info.stakeAmount = stakeAmount
-	info.validateEnd, err = time.Parse(time.RFC3339, validateEnds)
-	if err != nil {
+	info.stakeAmount = defaultStakeAmount
+	switch {
+	case o.l1:
+		if err := info.requireUpgrade(version.Etna, "ACP-77 L1s"); err != nil {
+			return err
+		}
+		if len(info.nodeIDs) > 0 {
+			info.sovNodeIDs = info.deferSOVNodes()
+			info.printSOVGuidance(info.sovNodeIDs)
+		}
+	case info.subnetOnlyValidators() && len(info.nodeIDs) > 0:
+		color.Outf("{{yellow}}%q runs %s: with --l1, the %d node(s) that are not primary network validators are left to validate the subnet as an L1 instead of staking{{/}}\n", info.networkName, version.Etna, len(info.nodeIDs))
+	}
+	if err := ParseValidateWindow(info, o.window); err != nil {
+		return err
+	}
+	if err := CheckValidateWindows(ctx, cli, info); err != nil {
 		return err
 	}
 	info.validateWeight = defaultValidateWeight
 	info.validateRewardFeePercent = defaultValFeePercent
 	info.rewardAddr = info.key.Addresses()[0]
 	info.changeAddr = info.key.Addresses()[0]
-	info.vmID, err = ids.FromString(vmIDs)
+	info.vmID, err = ids.FromString(o.vmID)
 	if err != nil {
 		return err
 	}
-	vmGenesisBytes, err := ioutil.ReadFile(vmGenesisPath)
+	vmGenesisBytes, err := ioutil.ReadFile(o.vmGenesisPath)
 	if err != nil {
 		return err
 	}
-	info.chainName = chainName
-	info.vmGenesisPath = vmGenesisPath
+	info.chainName = o.chainName
+	info.vmGenesisPath = o.vmGenesisPath
 
 	// Compute dry run cost/actions for approval
 	info.totalStakeAmount = uint64(len(info.nodeIDs)) * info.stakeAmount
-	info.txFee = uint64(info.feeData.CreateSubnetTxFee) + uint64(info.feeData.TxFee)*uint64(len(info.allNodeIDs)) + uint64(info.feeData.CreateBlockchainTxFee)
-	info.requiredBalance = info.stakeAmount + info.txFee
+	info.txFee = 0
+	for _, f := range []struct {
+		txType client.TxType
+		n      int
+	}{
+		{client.TxTypeAddValidator, len(info.nodeIDs)},
+		{client.TxTypeCreateSubnet, 1},
+		{client.TxTypeAddSubnetValidator, len(info.allNodeIDs)},
+		{client.TxTypeCreateBlockchain, 1},
+	} {
+		fee, err := info.Fee(ctx, f.txType, f.n)
+		if err != nil {
+			return err
+		}
+		info.txFee += fee
+	}
+	info.requiredBalance = info.totalStakeAmount + info.txFee
+	info.weightChange = info.validateWeight * uint64(len(info.allNodeIDs))
 	if err := info.CheckBalance(); err != nil {
 		return err
 	}
-
-	msg := CreateSpellPreTable(info)
-	if enablePrompt {
-		msg = formatter.F("\n{{blue}}{{bold}}Ready to run wizard, should we continue?{{/}}\n") + msg
+	if err := info.CheckPolicy(cmd); err != nil {
+		return err
+	}
+	if ok, err := info.CheckApproval(ctx, cmd); !ok || err != nil {
+		return err
+	}
+	if err := info.CheckMainnetSpend(); err != nil {
+		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, msg)
 
-	prompt := promptui.Select{
-		Label:  "\n",
-		Stdout: os.Stdout,
-		Items: []string{
-			formatter.F("{{green}}Yes, let's create! {{bold}}{{underline}}I agree to pay the fee{{/}}{{green}}!{{/}}"),
-			formatter.F("{{red}}No, stop it!{{/}}"),
-		},
-	}
-	idx, _, err := prompt.Run()
+	changes, err := info.stateChanges(ctx, cli)
 	if err != nil {
-		return nil //nolint:nilerr
+		return err
+	}
+	// the new subnet starts without validators
+	subnetWeights := make(map[ids.ShortID]uint64, len(info.allNodeIDs))
+	for _, nodeID := range info.allNodeIDs {
+		subnetWeights[nodeID] = info.validateWeight
 	}
-	if idx == 1 {
+	changes = append(changes, validatorChanges(false, nil, subnetWeights)...)
+	if !confirmChanges(CreateSpellPreTable(info), changes, "run wizard", "I agree to pay the fee") {
 		return nil
 	}
+	if err := info.waitIssueAt(ctx, cli); err != nil {
+		return err
+	}
 	println()
 	println()
 
+	// Plan every tx so the balance can be re-checked before each one
+	b := new(batch)
+	for _, t := range []struct {
+		txType client.TxType
+		n      int
+		stake  uint64
+	}{
+		{client.TxTypeAddValidator, len(info.nodeIDs), info.stakeAmount},
+		{client.TxTypeCreateSubnet, 1, 0},
+		{client.TxTypeAddSubnetValidator, len(info.allNodeIDs), 0},
+		{client.TxTypeCreateBlockchain, 1, 0},
+	} {
+		if err := b.add(ctx, info, t.txType, t.n, t.stake); err != nil {
+			return err
+		}
+	}
+
+	// Track every tx so a failure midway reports what to undo or resume
+	r := rollback.New("wizard", info.networkID)
+	for i, nodeID := range info.nodeIDs {
+		r.Plan(validatorAction(rollback.KindAddValidator, nodeID, info.validateEnd.Add(time.Duration(i)*defaultStagger)))
+	}
+	r.Plan(rollback.Action{Kind: rollback.KindCreateSubnet})
+	for _, nodeID := range info.allNodeIDs {
+		r.Plan(nodeAction(rollback.KindAddSubnetValidator, nodeID))
+	}
+	r.Plan(rollback.Action{Kind: rollback.KindCreateBlockchain, Name: info.chainName})
+	defer func() {
+		err = reportRollback(info, r, err)
+	}()
+
 	// Ensure all nodes are validators on the primary network
 	for i, nodeID := range info.nodeIDs {
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-		info.validateStart = time.Now().Add(30 * time.Second)
-		took, err := cli.P().AddValidator(
-			ctx,
-			info.key,
-			nodeID,
-			info.validateStart,
-			info.validateEnd,
-			client.WithStakeAmount(info.stakeAmount),
-			client.WithRewardShares(info.validateRewardFeePercent*10000),
-			client.WithRewardAddress(info.rewardAddr),
-			client.WithChangeAddress(info.changeAddr),
-		)
-		cancel()
+		if err := b.check(ctx, cli, info); err != nil {
+			return err
+		}
+		if !info.fixedValidateStart {
+			info.validateStart = time.Now().Add(defaultValidateStartBuffer)
+		}
+		took, err := refreshStartTime(info, true, func() (time.Duration, error) {
+			return cli.P().AddValidator(
+				ctx,
+				info.key,
+				nodeID,
+				info.validateStart,
+				info.validateEnd,
+				client.WithStakeAmount(info.stakeAmount),
+				client.WithRewardShares(info.validateRewardFeePercent*10000),
+				client.WithRewardAddress(info.rewardAddr),
+				client.WithChangeAddress(info.changeAddr),
+			)
+		})
 		if err != nil {
 			return err
 		}
+		b.done()
+		r.Complete(validatorAction(rollback.KindAddValidator, nodeID, info.validateEnd))
+		if err := info.RecordApproval(); err != nil {
+			return err
+		}
 		color.Outf("{{magenta}}added %s to primary network validator set{{/}} {{light-gray}}(took %v){{/}}\n\n", nodeID, took)
 		if i < len(info.nodeIDs)-1 {
 			info.validateEnd = info.validateEnd.Add(defaultStagger)
 		}
 	}
 	if len(info.nodeIDs) > 0 {
-		WaitValidator(cli, info.nodeIDs, info)
+		if err := WaitValidator(ctx, cli, info.nodeIDs, info); err != nil {
+			return err
+		}
 		println()
 		println()
 	}
 
 	// Create subnet
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	if err := b.check(ctx, cli, info); err != nil {
+		return err
+	}
 	subnetID, took, err := cli.P().CreateSubnet(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
+	b.done()
 	info.subnetID = subnetID
+	r.Complete(rollback.Action{Kind: rollback.KindCreateSubnet, SubnetID: subnetID.String()})
+	if err := info.RecordApproval(); err != nil {
+		return err
+	}
 	color.Outf("{{magenta}}created subnet{{/}} %q {{light-gray}}(took %v){{/}}\n", info.subnetID, took)
+	color.Result(info.subnetID)
 
 	// Pause for operator to whitelist subnet on all validators (and to remind
-	// that a binary by the name of [vmIDs] must be in the plugins dir)
+	// that a binary by the name of [info.vmID] must be in the plugins dir)
 	color.Outf("\n\n\n{{cyan}}Now, time for some config changes on your node(s).\nSet --whitelisted-subnets=%s and move the compiled VM %s to <build-dir>/plugins/%s.\nWhen you're finished, restart your node.{{/}}\n", info.subnetID, info.vmID, info.vmID)
-	prompt = promptui.Select{
-		Label:  "\n",
-		Stdout: os.Stdout,
-		Items: []string{
-			formatter.F("{{green}}Yes, let's continue!{{bold}}{{underline}} I've updated --whitelisted-subnets, built my VM, and restarted my node(s)!{{/}}"),
-			formatter.F("{{red}}No, stop it!{{/}}"),
-		},
-	}
-	idx, _, err = prompt.Run()
-	if err != nil {
-		return nil //nolint:nilerr
-	}
-	if idx == 1 {
-		return nil
+	if enablePrompt {
+		prompt := promptui.Select{
+			Label:  "\n",
+			Stdout: os.Stdout,
+			Items: []string{
+				color.F("{{green}}Yes, let's continue!{{bold}}{{underline}} I've updated --whitelisted-subnets, built my VM, and restarted my node(s)!{{/}}"),
+				color.F("{{red}}No, stop it!{{/}}"),
+			},
+		}
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		if idx == 1 {
+			return nil
+		}
 	}
 	println()
 	println()
 
 	// Add validators to subnet
 	for _, nodeID := range info.allNodeIDs { // do all nodes, not parsed
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		if err := b.check(ctx, cli, info); err != nil {
+			return err
+		}
 		valInfo := info.valInfos[nodeID]
 		start := time.Now().Add(30 * time.Second)
 		took, err := cli.P().AddSubnetValidator(
@@ -188,23 +294,30 @@ func wizardFunc(cmd *cobra.Command, args []string) error {
 			nodeID,
 			start,
 			valInfo.end,
-			validateWeight,
+			info.validateWeight,
 		)
-		cancel()
 		if err != nil {
 			return err
 		}
+		b.done()
+		a := validatorAction(rollback.KindAddSubnetValidator, nodeID, valInfo.end)
+		a.SubnetID = info.subnetID.String()
+		r.Complete(a)
 		color.Outf("{{magenta}}added %s to subnet %s validator set{{/}} {{light-gray}}(took %v){{/}}\n\n", nodeID, info.subnetID, took)
 	}
 
 	// Because [info.subnetID] was set to the new subnetID, [WaitValidator] will
 	// lookup status for subnetID
-	WaitValidator(cli, info.allNodeIDs, info)
+	if err := WaitValidator(ctx, cli, info.allNodeIDs, info); err != nil {
+		return err
+	}
 	println()
 	println()
 
 	// Add blockchain to subnet
-	ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
+	if err := b.check(ctx, cli, info); err != nil {
+		return err
+	}
 	blockchainID, took, err := cli.P().CreateBlockchain(
 		ctx,
 		info.key,
@@ -213,48 +326,52 @@ func wizardFunc(cmd *cobra.Command, args []string) error {
 		info.vmID,
 		vmGenesisBytes,
 	)
-	cancel()
 	if err != nil {
 		return err
 	}
 	info.blockchainID = blockchainID
+	r.Complete(rollback.Action{Kind: rollback.KindCreateBlockchain, SubnetID: info.subnetID.String(), BlockchainID: blockchainID.String(), Name: info.chainName})
 	color.Outf("{{magenta}}created blockchain{{/}} %q {{light-gray}}(took %v){{/}}\n\n", info.blockchainID, took)
+	color.Result(info.blockchainID)
+	recordName(info.networkID, names.KindBlockchain, info.chainName, info.blockchainID)
 
 	// Print out summary of actions (subnetID, chainID, validator periods)
 	info.requiredBalance = 0
 	info.stakeAmount = 0
 	info.totalStakeAmount = 0
 	info.txFee = 0
-	ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
 	info.balance, err = cli.P().Balance(ctx, info.key)
-	cancel()
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(formatter.ColorableStdOut, CreateSpellPostTable(info))
-	return nil
+	color.Print(CreateSpellPostTable(info))
+	if len(info.sovNodeIDs) > 0 {
+		info.printSOVGuidance(info.sovNodeIDs)
+	}
+	return PrintRPCEndpoints(info, vmGenesisBytes)
 }
 
 func CreateSpellPreTable(i *Info) string {
 	buf, tb := BaseTableSetup(i)
 	if len(i.nodeIDs) > 0 {
-		tb.Append([]string{formatter.F("{{magenta}}NEW PRIMARY NETWORK VALIDATORS{{/}}"), formatter.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE END{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.validateEnd.Format(time.RFC3339))})
-		stakeAmount := float64(i.stakeAmount) / float64(units.Avax)
-		stakeAmounts := humanize.FormatFloat("#,###.###", stakeAmount)
-		tb.Append([]string{formatter.F("{{magenta}}STAKE AMOUNT{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}} $AVAX", stakeAmounts)})
+		tb.Append([]string{color.F("{{magenta}}NEW PRIMARY NETWORK VALIDATORS{{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE END{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.validateEnd.Format(time.RFC3339))})
+		tb.Append([]string{color.F("{{magenta}}STAKE AMOUNT{{/}}"), i.formatStake(i.stakeAmount)})
 		validateRewardFeePercent := humanize.FormatFloat("#,###.###", float64(i.validateRewardFeePercent))
-		tb.Append([]string{formatter.F("{{magenta}}VALIDATE REWARD FEE{{/}}"), formatter.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} %%", validateRewardFeePercent)})
-		tb.Append([]string{formatter.F("{{cyan}}{{bold}}REWARD ADDRESS{{/}}"), formatter.F("{{light-gray}}%s{{/}}", i.rewardAddr)})
-		tb.Append([]string{formatter.F("{{cyan}}{{bold}}CHANGE ADDRESS{{/}}"), formatter.F("{{light-gray}}%s{{/}}", i.changeAddr)})
+		tb.Append([]string{color.F("{{magenta}}VALIDATE REWARD FEE{{/}}"), color.F("{{light-gray}}{{bold}}{{underline}}%s{{/}} %%", validateRewardFeePercent)})
+		tb.Append([]string{color.F("{{cyan}}{{bold}}REWARD ADDRESS{{/}}"), color.F("{{light-gray}}%s{{/}}", i.FormatAddress(i.rewardAddr))})
+		tb.Append([]string{color.F("{{cyan}}{{bold}}CHANGE ADDRESS{{/}}"), color.F("{{light-gray}}%s{{/}}", i.FormatAddress(i.changeAddr))})
 	}
 
-	tb.Append([]string{formatter.F("{{orange}}NEW SUBNET VALIDATORS{{/}}"), formatter.F("{{light-gray}}{{bold}}%v{{/}}", i.allNodeIDs)})
-	tb.Append([]string{formatter.F("{{magenta}}SUBNET VALIDATION WEIGHT{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(i.validateWeight)))})
+	tb.Append([]string{color.F("{{orange}}NEW SUBNET VALIDATORS{{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}}", i.allNodeIDs)})
+	if len(i.sovNodeIDs) > 0 {
+		tb.Append([]string{color.F("{{orange}}L1 VALIDATORS (CONVERT L1){{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}} {{light-gray}}(no primary stake){{/}}", i.sovNodeIDs)})
+	}
+	tb.Append([]string{color.F("{{magenta}}SUBNET VALIDATION WEIGHT{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", humanize.Comma(int64(i.validateWeight)))})
 
-	tb.Append([]string{formatter.F("{{dark-green}}CHAIN NAME{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
-	tb.Append([]string{formatter.F("{{dark-green}}VM ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
-	tb.Append([]string{formatter.F("{{dark-green}}VM GENESIS PATH{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
+	tb.Append([]string{color.F("{{dark-green}}CHAIN NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
+	tb.Append([]string{color.F("{{dark-green}}VM ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
+	tb.Append([]string{color.F("{{dark-green}}VM GENESIS PATH{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
 	tb.Render()
 	return buf.String()
 }
@@ -262,16 +379,16 @@ func CreateSpellPreTable(i *Info) string {
 func CreateSpellPostTable(i *Info) string {
 	buf, tb := BaseTableSetup(i)
 	if len(i.nodeIDs) > 0 {
-		tb.Append([]string{formatter.F("{{magenta}}PRIMARY NETWORK VALIDATORS{{/}}"), formatter.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
+		tb.Append([]string{color.F("{{magenta}}PRIMARY NETWORK VALIDATORS{{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}}", i.nodeIDs)})
 	}
 
-	tb.Append([]string{formatter.F("{{orange}}SUBNET VALIDATORS{{/}}"), formatter.F("{{light-gray}}{{bold}}%v{{/}}", i.allNodeIDs)})
-	tb.Append([]string{formatter.F("{{blue}}SUBNET ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.subnetID)})
-	tb.Append([]string{formatter.F("{{blue}}BLOCKCHAIN ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.blockchainID)})
+	tb.Append([]string{color.F("{{orange}}SUBNET VALIDATORS{{/}}"), color.F("{{light-gray}}{{bold}}%v{{/}}", i.allNodeIDs)})
+	tb.Append([]string{color.F("{{blue}}SUBNET ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindSubnet, i.subnetID))})
+	tb.Append([]string{color.F("{{blue}}BLOCKCHAIN ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", namedID(i.networkID, names.KindBlockchain, i.blockchainID))})
 
-	tb.Append([]string{formatter.F("{{dark-green}}CHAIN NAME{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
-	tb.Append([]string{formatter.F("{{dark-green}}VM ID{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
-	tb.Append([]string{formatter.F("{{dark-green}}VM GENESIS PATH{{/}}"), formatter.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
+	tb.Append([]string{color.F("{{dark-green}}CHAIN NAME{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.chainName)})
+	tb.Append([]string{color.F("{{dark-green}}VM ID{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmID)})
+	tb.Append([]string{color.F("{{dark-green}}VM GENESIS PATH{{/}}"), color.F("{{light-gray}}{{bold}}%s{{/}}", i.vmGenesisPath)})
 	tb.Render()
 	return buf.String()
 }
@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package address converts between the address formats of a secp256k1 key:
+// P/X/C-Chain bech32 addresses and raw short IDs share the hash of the
+// compressed public key, while EVM addresses hash the uncompressed one.
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown address format")
+	ErrHRPMismatch   = errors.New("address HRP does not match the network")
+	ErrNoHRP         = errors.New("no network to format bech32 addresses for")
+	ErrNotDerivable  = errors.New("not derivable")
+)
+
+// Format is an address format to convert to.
+type Format string
+
+const (
+	FormatP       Format = "p"
+	FormatX       Format = "x"
+	FormatC       Format = "c"
+	FormatEVM     Format = "evm"
+	FormatShortID Format = "short-id"
+)
+
+// Formats lists the address formats in display order.
+var Formats = []Format{FormatP, FormatX, FormatC, FormatEVM, FormatShortID}
+
+// Addresses is a key in the formats its input converts to.
+type Addresses struct {
+	// Input describes the detected input format (e.g., "P-Chain address").
+	Input string
+	// HRP is the bech32 human-readable part, empty if unknown.
+	HRP string
+
+	shortID *ids.ShortID
+	evm     *evm.Address
+}
+
+// Convert detects the format of [s] (a bech32 address of any chain, a raw
+// short ID, an EVM address or a compressed hex public key). Bech32 addresses must use
+// [hrp] if it is set; otherwise their HRP is used for the conversion.
+func Convert(s string, hrp string) (*Addresses, error) {
+	s = strings.TrimSpace(s)
+	a := &Addresses{HRP: hrp}
+	raw := strings.TrimPrefix(s, "0x")
+	switch {
+	case isHex(raw) && len(raw) == 2*evm.AddressLen:
+		addr, err := evm.ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		a.Input, a.evm = "EVM address", &addr
+	case isHex(raw) && len(raw) == 2*crypto.SECP256K1RPKLen:
+		b, _ := hex.DecodeString(raw)
+		pk, err := new(crypto.FactorySECP256K1R).ToPublicKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrUnknownFormat, s, err)
+		}
+		pub := pk.(*crypto.PublicKeySECP256K1R)
+		shortID, addr := pub.Address(), evm.AddressFromPublicKey(pub)
+		a.Input, a.shortID, a.evm = "public key", &shortID, &addr
+	case strings.Contains(s, "-"):
+		chain, bhrp, b, err := formatting.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrUnknownFormat, s, err)
+		}
+		if hrp != "" && bhrp != hrp {
+			return nil, fmt.Errorf("%w: %q has HRP %q, expected %q", ErrHRPMismatch, s, bhrp, hrp)
+		}
+		shortID, err := ids.ToShortID(b)
+		if err != nil {
+			return nil, err
+		}
+		a.Input, a.HRP, a.shortID = chain+"-Chain address", bhrp, &shortID
+	default:
+		shortID, err := ids.ShortFromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+		}
+		a.Input, a.shortID = "short ID", &shortID
+	}
+	return a, nil
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// Network returns the name of the network of [a.HRP], if it is known.
+func (a *Addresses) Network() string {
+	networkID, ok := constants.NetworkHRPToNetworkID[a.HRP]
+	if !ok {
+		return ""
+	}
+	return constants.NetworkName(networkID)
+}
+
+// Get returns the address in format [f]. Short IDs and EVM addresses hash
+// the public key differently, so they only convert into each other for
+// public key inputs.
+func (a *Addresses) Get(f Format) (string, error) {
+	switch f {
+	case FormatEVM:
+		if a.evm == nil {
+			return "", fmt.Errorf("%w: EVM address of a %s (needs the public key)", ErrNotDerivable, a.Input)
+		}
+		return a.evm.Hex(), nil
+	case FormatShortID, FormatP, FormatX, FormatC:
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+	if a.shortID == nil {
+		return "", fmt.Errorf("%w: short ID of an %s (needs the public key)", ErrNotDerivable, a.Input)
+	}
+	if f == FormatShortID {
+		return a.shortID.String(), nil
+	}
+	if a.HRP == "" {
+		return "", ErrNoHRP
+	}
+	return formatting.FormatAddress(strings.ToUpper(string(f)), a.HRP, a.shortID[:])
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package address
+
+import (
+	"errors"
+	"testing"
+)
+
+// "ewoq" key used by local networks
+const (
+	ewoqPublicKey = "0x0327448e78ffa8cdb24cf19be0204ad954b1bdb4db8c51183534c1eecf2ebd094e"
+	ewoqShortID   = "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"
+	ewoqPAddr     = "P-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+	ewoqXAddr     = "X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+	ewoqCAddr     = "C-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+	ewoqEVMAddr   = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s      string
+		hrp    string
+		exp    map[Format]string
+		expErr error
+	}{
+		{
+			s:   ewoqPublicKey,
+			hrp: "local",
+			exp: map[Format]string{FormatP: ewoqPAddr, FormatX: ewoqXAddr, FormatC: ewoqCAddr, FormatEVM: ewoqEVMAddr, FormatShortID: ewoqShortID},
+		},
+		{
+			s:   ewoqXAddr,
+			exp: map[Format]string{FormatP: ewoqPAddr, FormatC: ewoqCAddr, FormatShortID: ewoqShortID},
+		},
+		{
+			s:   ewoqShortID,
+			hrp: "local",
+			exp: map[Format]string{FormatP: ewoqPAddr},
+		},
+		{s: ewoqEVMAddr, exp: map[Format]string{FormatEVM: ewoqEVMAddr}},
+		{s: ewoqPAddr, hrp: "fuji", expErr: ErrHRPMismatch},
+		{s: "P-local1invalid", expErr: ErrUnknownFormat},
+		{s: "hello", expErr: ErrUnknownFormat},
+	}
+	for i, tv := range tt {
+		a, err := Convert(tv.s, tv.hrp)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		for f, exp := range tv.exp {
+			got, err := a.Get(f)
+			if err != nil {
+				t.Fatalf("#%d(%s): %v", i, f, err)
+			}
+			if got != exp {
+				t.Fatalf("#%d(%s): expected %q, got %q", i, f, exp, got)
+			}
+		}
+	}
+
+	// short IDs and EVM addresses don't convert into each other
+	a, err := Convert(ewoqEVMAddr, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Get(FormatShortID); !errors.Is(err, ErrNotDerivable) {
+		t.Fatalf("expected %v, got %v", ErrNotDerivable, err)
+	}
+	a, err = Convert(ewoqShortID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Get(FormatEVM); !errors.Is(err, ErrNotDerivable) {
+		t.Fatalf("expected %v, got %v", ErrNotDerivable, err)
+	}
+	if _, err := a.Get(FormatP); !errors.Is(err, ErrNoHRP) {
+		t.Fatalf("expected %v, got %v", ErrNoHRP, err)
+	}
+	if a.Network() != "" {
+		t.Fatalf("unexpected network %q", a.Network())
+	}
+}
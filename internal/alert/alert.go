@@ -0,0 +1,347 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package alert evaluates monitoring rules (e.g., "validator uptime < 85%")
+// against periodic snapshots of a subnet and reports the alerts that start
+// or stop firing.
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+)
+
+var ErrInvalidRules = errors.New("invalid alert rules")
+
+// Actions run when an alert fires or resolves.
+const (
+	// ActionLog prints the alert; the default.
+	ActionLog = "log"
+	// ActionWebhook posts the alert to [Config.Webhook].
+	ActionWebhook = "webhook"
+	// ActionExit stops watching with a non-zero exit code.
+	ActionExit = "exit"
+)
+
+// Config is an alert rules file.
+//
+//	webhook: https://hooks.example.com/subnet
+//	rules:
+//	  - name: low uptime
+//	    when: validator uptime < 85%
+//	  - when: validator expires < 7d
+//	    actions: [log, webhook]
+//	  - when: chain height stalled 5m
+//	    actions: [log, exit]
+type Config struct {
+	// Webhook is the URL alerts with [ActionWebhook] are posted to.
+	Webhook string `yaml:"webhook,omitempty"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule is a condition and what to do when it holds.
+type Rule struct {
+	// Name defaults to [When].
+	Name string `yaml:"name,omitempty"`
+	// When is one of:
+	//
+	//	validator uptime <op> <percent>    (e.g., "validator uptime < 85%")
+	//	validator expires <op> <duration>  (e.g., "validator expires < 7d")
+	//	chain height stalled <duration>    (e.g., "chain height stalled 5m")
+	//
+	// where <op> is "<", "<=", ">" or ">=".
+	When string `yaml:"when"`
+	// Actions default to [ActionLog].
+	Actions []string `yaml:"actions,omitempty"`
+
+	cond condition
+}
+
+// Has returns true if the rule runs [action].
+func (r Rule) Has(action string) bool {
+	for _, a := range r.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	metricUptime  = "uptime"
+	metricExpires = "expires"
+	metricStalled = "stalled"
+)
+
+type condition struct {
+	metric string
+	op     string
+	// value is the uptime fraction or the duration in nanoseconds.
+	value float64
+}
+
+func (c condition) holds(v float64) bool {
+	switch c.op {
+	case "<":
+		return v < c.value
+	case "<=":
+		return v <= c.value
+	case ">":
+		return v > c.value
+	default:
+		return v >= c.value
+	}
+}
+
+// Load reads the rules at [path].
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse parses and validates rules.
+func Parse(b []byte) (*Config, error) {
+	c := new(Config)
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	if len(c.Rules) == 0 {
+		return nil, fmt.Errorf("%w: no rules", ErrInvalidRules)
+	}
+	names := map[string]bool{}
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		cond, err := parseCondition(r.When)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule #%d: %v", ErrInvalidRules, i+1, err)
+		}
+		r.cond = cond
+		if r.Name == "" {
+			r.Name = strings.Join(strings.Fields(r.When), " ")
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("%w: rule %q defined twice", ErrInvalidRules, r.Name)
+		}
+		names[r.Name] = true
+		if len(r.Actions) == 0 {
+			r.Actions = []string{ActionLog}
+		}
+		for _, a := range r.Actions {
+			switch a {
+			case ActionLog, ActionExit:
+			case ActionWebhook:
+				if c.Webhook == "" {
+					return nil, fmt.Errorf("%w: rule %q: webhook action without a webhook URL", ErrInvalidRules, r.Name)
+				}
+			default:
+				return nil, fmt.Errorf("%w: rule %q: unknown action %q (expected %s, %s or %s)", ErrInvalidRules, r.Name, a, ActionLog, ActionWebhook, ActionExit)
+			}
+		}
+	}
+	return c, nil
+}
+
+func parseCondition(s string) (condition, error) {
+	fs := strings.Fields(s)
+	switch {
+	case len(fs) == 4 && fs[0] == "validator" && fs[1] == metricUptime:
+		op, err := parseOp(fs[2])
+		if err != nil {
+			return condition{}, err
+		}
+		if !strings.HasSuffix(fs[3], "%") {
+			return condition{}, fmt.Errorf("uptime %q is not a percentage", fs[3])
+		}
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(fs[3], "%"), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return condition{}, fmt.Errorf("invalid uptime %q", fs[3])
+		}
+		return condition{metric: metricUptime, op: op, value: pct / 100}, nil
+	case len(fs) == 4 && fs[0] == "validator" && fs[1] == metricExpires:
+		op, err := parseOp(fs[2])
+		if err != nil {
+			return condition{}, err
+		}
+		d, err := timeexpr.ParseDuration(fs[3])
+		if err != nil {
+			return condition{}, err
+		}
+		return condition{metric: metricExpires, op: op, value: float64(d)}, nil
+	case len(fs) == 4 && fs[0] == "chain" && fs[1] == "height" && fs[2] == metricStalled:
+		d, err := timeexpr.ParseDuration(fs[3])
+		if err != nil {
+			return condition{}, err
+		}
+		if d <= 0 {
+			return condition{}, fmt.Errorf("stall duration %q must be positive", fs[3])
+		}
+		return condition{metric: metricStalled, op: ">=", value: float64(d)}, nil
+	}
+	return condition{}, fmt.Errorf("unknown condition %q (expected \"validator uptime < 85%%\", \"validator expires < 7d\" or \"chain height stalled 5m\")", s)
+}
+
+func parseOp(s string) (string, error) {
+	switch s {
+	case "<", "<=", ">", ">=":
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown operator %q", s)
+}
+
+// Snapshot is the state of a subnet at a time.
+type Snapshot struct {
+	Time       time.Time
+	Validators []Validator
+	Chains     []Chain
+}
+
+// Validator is a subnet validator.
+type Validator struct {
+	NodeID string
+	// Uptime is in [0, 1]; nil if unknown.
+	Uptime *float64
+	End    time.Time
+}
+
+// Chain is a blockchain of the subnet.
+type Chain struct {
+	ID   string
+	Name string
+	// Height is the last accepted height, if [Known].
+	Height uint64
+	Known  bool
+}
+
+// Alert is a rule that started or stopped holding for a subject.
+type Alert struct {
+	Rule string `json:"rule"`
+	When string `json:"when"`
+	// Subject is the node ID or the chain the rule holds for.
+	Subject  string    `json:"subject"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+	Resolved bool      `json:"resolved"`
+
+	Actions []string `json:"-"`
+}
+
+// Has returns true if the rule of the alert runs [action].
+func (a Alert) Has(action string) bool {
+	return Rule{Actions: a.Actions}.Has(action)
+}
+
+type heightMark struct {
+	height uint64
+	since  time.Time
+}
+
+// Engine evaluates rules against successive snapshots.
+type Engine struct {
+	rules   []Rule
+	heights map[string]heightMark
+	// firing are the alerts that hold, by rule and subject.
+	firing map[string]Alert
+}
+
+// NewEngine returns an engine evaluating the rules of [c].
+func NewEngine(c *Config) *Engine {
+	return &Engine{
+		rules:   c.Rules,
+		heights: map[string]heightMark{},
+		firing:  map[string]Alert{},
+	}
+}
+
+// Evaluate returns the alerts that started holding with [s] and the ones
+// that stopped, sorted by rule and subject. An alert that keeps holding is
+// only reported once.
+func (e *Engine) Evaluate(s Snapshot) (fired []Alert, resolved []Alert) {
+	for _, c := range s.Chains {
+		if !c.Known {
+			continue
+		}
+		if m, ok := e.heights[c.ID]; !ok || m.height != c.Height {
+			e.heights[c.ID] = heightMark{height: c.Height, since: s.Time}
+		}
+	}
+
+	holding := map[string]Alert{}
+	for _, r := range e.rules {
+		for _, a := range e.check(r, s) {
+			a.Rule, a.When, a.Time, a.Actions = r.Name, r.When, s.Time, r.Actions
+			holding[r.Name+"/"+a.Subject] = a
+		}
+	}
+	for k, a := range holding {
+		if _, ok := e.firing[k]; !ok {
+			fired = append(fired, a)
+		}
+	}
+	for k, a := range e.firing {
+		if _, ok := holding[k]; !ok {
+			a.Resolved, a.Time = true, s.Time
+			resolved = append(resolved, a)
+		}
+	}
+	e.firing = holding
+	sortAlerts(fired)
+	sortAlerts(resolved)
+	return fired, resolved
+}
+
+// check returns the subjects [r] holds for in [s].
+func (e *Engine) check(r Rule, s Snapshot) []Alert {
+	as := []Alert{}
+	switch r.cond.metric {
+	case metricUptime:
+		for _, v := range s.Validators {
+			if v.Uptime != nil && r.cond.holds(*v.Uptime) {
+				as = append(as, Alert{Subject: v.NodeID, Message: fmt.Sprintf("%s uptime is %.1f%%", v.NodeID, 100**v.Uptime)})
+			}
+		}
+	case metricExpires:
+		for _, v := range s.Validators {
+			left := v.End.Sub(s.Time)
+			if r.cond.holds(float64(left)) {
+				as = append(as, Alert{Subject: v.NodeID, Message: fmt.Sprintf("%s stops validating in %s (%s)", v.NodeID, left.Round(time.Minute), v.End.UTC().Format(time.RFC3339))})
+			}
+		}
+	case metricStalled:
+		for _, c := range s.Chains {
+			m, ok := e.heights[c.ID]
+			if !ok {
+				continue
+			}
+			stalled := s.Time.Sub(m.since)
+			if r.cond.holds(float64(stalled)) {
+				as = append(as, Alert{Subject: c.Name, Message: fmt.Sprintf("%s has been at height %d for %s", c.Name, m.height, stalled.Round(time.Second))})
+			}
+		}
+	}
+	return as
+}
+
+func sortAlerts(as []Alert) {
+	sort.Slice(as, func(i, j int) bool {
+		if as[i].Rule != as[j].Rule {
+			return as[i].Rule < as[j].Rule
+		}
+		return as[i].Subject < as[j].Subject
+	})
+}
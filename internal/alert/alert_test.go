@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s        string
+		expErr   error
+		expNames []string
+	}{
+		{
+			s: `
+rules:
+  - name: low uptime
+    when: validator uptime < 85%
+  - when: validator  expires <= 7d
+  - when: chain height stalled 5m
+    actions: [log, exit]
+`,
+			expNames: []string{"low uptime", "validator expires <= 7d", "chain height stalled 5m"},
+		},
+		{s: "rules: []", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator uptime < 85", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator uptime != 85%", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator expires < soon", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: chain height stalled 0s", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: subnet is down", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator uptime < 85%\n    actions: [page]", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator uptime < 85%\n    actions: [webhook]", expErr: ErrInvalidRules},
+		{s: "rules:\n  - when: validator uptime < 85%\n  - when: validator uptime < 85%", expErr: ErrInvalidRules},
+		{s: "rule:\n  - when: validator uptime < 85%", expErr: ErrInvalidRules},
+	}
+	for i, tv := range tt {
+		c, err := Parse([]byte(tv.s))
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if tv.expErr != nil {
+			continue
+		}
+		names := []string{}
+		for _, r := range c.Rules {
+			names = append(names, r.Name)
+		}
+		if !reflect.DeepEqual(names, tv.expNames) {
+			t.Fatalf("#%d: expected rules %v, got %v", i, tv.expNames, names)
+		}
+		if !c.Rules[0].Has(ActionLog) || !c.Rules[2].Has(ActionExit) {
+			t.Fatalf("#%d: unexpected actions %+v", i, c.Rules)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	c, err := Parse([]byte(`
+rules:
+  - name: uptime
+    when: validator uptime < 85%
+  - name: expiry
+    when: validator expires < 7d
+  - name: stall
+    when: chain height stalled 5m
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(c)
+
+	now := time.Unix(1_000_000, 0)
+	low, high := 0.8, 0.99
+	snapshot := func(at time.Duration, uptime *float64, height uint64) Snapshot {
+		return Snapshot{
+			Time: now.Add(at),
+			Validators: []Validator{
+				{NodeID: "NodeID-A", Uptime: uptime, End: now.Add(30 * 24 * time.Hour)},
+				{NodeID: "NodeID-B", End: now.Add(3 * 24 * time.Hour)},
+			},
+			Chains: []Chain{
+				{ID: "c1", Name: "mychain", Height: height, Known: true},
+				{ID: "c2", Name: "other"},
+			},
+		}
+	}
+	subjects := func(as []Alert) []string {
+		ss := []string{}
+		for _, a := range as {
+			ss = append(ss, a.Rule+"/"+a.Subject)
+		}
+		return ss
+	}
+
+	tt := []struct {
+		s           Snapshot
+		expFired    []string
+		expResolved []string
+	}{
+		{s: snapshot(0, &low, 10), expFired: []string{"expiry/NodeID-B", "uptime/NodeID-A"}, expResolved: []string{}},
+		// still holding, so not reported again
+		{s: snapshot(time.Minute, &low, 10), expFired: []string{}, expResolved: []string{}},
+		{s: snapshot(5*time.Minute, &high, 10), expFired: []string{"stall/mychain"}, expResolved: []string{"uptime/NodeID-A"}},
+		{s: snapshot(6*time.Minute, nil, 11), expFired: []string{}, expResolved: []string{"stall/mychain"}},
+	}
+	for i, tv := range tt {
+		fired, resolved := e.Evaluate(tv.s)
+		if ss := subjects(fired); !reflect.DeepEqual(ss, tv.expFired) {
+			t.Fatalf("#%d: expected fired %v, got %v", i, tv.expFired, ss)
+		}
+		if ss := subjects(resolved); !reflect.DeepEqual(ss, tv.expResolved) {
+			t.Fatalf("#%d: expected resolved %v, got %v", i, tv.expResolved, ss)
+		}
+		for _, a := range resolved {
+			if !a.Resolved {
+				t.Fatalf("#%d: expected %+v to be resolved", i, a)
+			}
+		}
+	}
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+
+	posted := make(chan payload, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil || len(p.Alerts) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posted <- p
+		if p.Alerts[0].Subject == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	as := []Alert{{Rule: "uptime", Subject: "NodeID-A", Message: "NodeID-A uptime is 80.0%"}}
+	if err := Post(context.Background(), srv.URL, as); err != nil {
+		t.Fatal(err)
+	}
+	if p := <-posted; len(p.Alerts) != 1 || p.Alerts[0].Message != as[0].Message {
+		t.Fatalf("unexpected payload %+v", p)
+	}
+	if err := Post(context.Background(), srv.URL, []Alert{{Subject: "fail"}}); !errors.Is(err, ErrWebhook) {
+		t.Fatalf("expected %v, got %v", ErrWebhook, err)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+var ErrWebhook = errors.New("webhook request failed")
+
+// payload is the body posted to the webhook.
+type payload struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Post posts [as] to the webhook [url] as {"alerts": [...]}.
+func Post(ctx context.Context, url string, as []Alert) error {
+	b, err := json.Marshal(payload{Alerts: as})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%w: status code %d: %s", ErrWebhook, resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package amount implements parsing and formatting of AVAX denominations.
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+var (
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrUnknownUnit   = errors.New("unknown unit")
+	ErrOverflow      = errors.New("amount overflows uint64")
+)
+
+// ordered so that the longest suffix is matched first
+var denominations = []struct {
+	suffix string
+	unit   uint64
+	digits int
+}{
+	{"navax", units.NanoAvax, 0},
+	{"uavax", units.MicroAvax, 3},
+	{"mavax", units.MilliAvax, 6},
+	{"avax", units.Avax, 9},
+}
+
+// Parse parses a human-readable AVAX amount into nano-AVAX.
+//
+// e.g.,
+//   Parse("2000avax")        == 2000 * units.Avax
+//   Parse("1.5AVAX")         == 1.5 * units.Avax
+//   Parse("25000000000nAVAX") == 25 * units.Avax
+//   Parse("1000")            == 1000 (bare integers are nano-AVAX)
+//
+func Parse(s string) (uint64, error) {
+	raw := strings.ToLower(strings.TrimSpace(s))
+	if raw == "" {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	unit, digits := uint64(units.NanoAvax), 0
+	num := raw
+	for _, d := range denominations {
+		if strings.HasSuffix(raw, d.suffix) {
+			unit, digits = d.unit, d.digits
+			num = strings.TrimSpace(strings.TrimSuffix(raw, d.suffix))
+			break
+		}
+	}
+	if num == "" {
+		return 0, fmt.Errorf("%w: %q has no value", ErrInvalidAmount, s)
+	}
+	whole, frac, err := splitDecimal(s, num)
+	if err != nil {
+		return 0, err
+	}
+	if len(frac) > digits {
+		return 0, fmt.Errorf("%w: %q is more precise than 1 nAVAX", ErrInvalidAmount, s)
+	}
+	return joinDecimal(s, whole, frac, unit, digits)
+}
+
+// ParseAsset parses a decimal amount (e.g., "1.5", "1_000") of an asset with
+// [denomination] decimal places into its base units.
+func ParseAsset(s string, denomination uint8) (uint64, error) {
+	num := strings.TrimSpace(s)
+	if num == "" {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+	digits := int(denomination)
+	unit := uint64(1)
+	for i := 0; i < digits; i++ {
+		if unit > math.MaxUint64/10 {
+			return 0, fmt.Errorf("%w: denomination %d", ErrOverflow, denomination)
+		}
+		unit *= 10
+	}
+	whole, frac, err := splitDecimal(s, num)
+	if err != nil {
+		return 0, err
+	}
+	if len(frac) > digits {
+		return 0, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, s, digits)
+	}
+	return joinDecimal(s, whole, frac, unit, digits)
+}
+
+// splitDecimal splits the number [num] of the amount [s] into its whole and
+// fractional digits, dropping trailing zeros of the latter.
+func splitDecimal(s string, num string) (whole string, frac string, err error) {
+	for _, r := range num {
+		if (r < '0' || r > '9') && r != '.' && r != '_' {
+			return "", "", fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+		}
+	}
+	num = strings.ReplaceAll(num, "_", "")
+
+	whole = num
+	if idx := strings.IndexByte(num, '.'); idx >= 0 {
+		whole, frac = num[:idx], num[idx+1:]
+		if strings.ContainsRune(frac, '.') || (whole == "" && frac == "") {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+	return whole, strings.TrimRight(frac, "0"), nil
+}
+
+// joinDecimal returns [whole] * [unit] plus [frac] as [digits] decimal
+// places.
+func joinDecimal(s string, whole string, frac string, unit uint64, digits int) (uint64, error) {
+	v := uint64(0)
+	if whole != "" {
+		w, err := strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrOverflow, s)
+		}
+		if w > math.MaxUint64/unit {
+			return 0, fmt.Errorf("%w: %q", ErrOverflow, s)
+		}
+		v = w * unit
+	}
+	if frac != "" {
+		f, err := strconv.ParseUint(frac+strings.Repeat("0", digits-len(frac)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		if v > math.MaxUint64-f {
+			return 0, fmt.Errorf("%w: %q", ErrOverflow, s)
+		}
+		v += f
+	}
+	return v, nil
+}
+
+// Format formats nano-AVAX as the shortest exact AVAX amount (e.g., "1.5avax").
+func Format(v uint64) string {
+	whole, frac := v/units.Avax, v%units.Avax
+	if frac == 0 {
+		return fmt.Sprintf("%davax", whole)
+	}
+	fs := strings.TrimRight(fmt.Sprintf("%09d", frac), "0")
+	return fmt.Sprintf("%d.%savax", whole, fs)
+}
+
+// FormatAsset formats [v] base units of an asset with [denomination]
+// decimal places as the shortest exact amount followed by [symbol] (e.g.,
+// "1.5 TOKEN"), for assets other than AVAX.
+func FormatAsset(v uint64, denomination uint8, symbol string) string {
+	s := strconv.FormatUint(v, 10)
+	d := int(denomination)
+	if len(s) <= d {
+		s = strings.Repeat("0", d-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-d], strings.TrimRight(s[len(s)-d:], "0")
+	if frac != "" {
+		whole += "." + frac
+	}
+	if symbol == "" {
+		return whole
+	}
+	return whole + " " + symbol
+}
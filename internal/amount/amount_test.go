@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package amount
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s      string
+		exp    uint64
+		expErr error
+	}{
+		{s: "2000avax", exp: 2000 * units.Avax},
+		{s: "1.5AVAX", exp: 1500 * units.MilliAvax},
+		{s: "25000000000nAVAX", exp: 25 * units.Avax},
+		{s: "1000", exp: 1000},
+		{s: ".5avax", exp: 500 * units.MilliAvax},
+		{s: "1_000 avax", exp: 1000 * units.Avax},
+		{s: "2mavax", exp: 2 * units.MilliAvax},
+		{s: "0.000000001avax", exp: 1},
+		{s: "0.0000000001avax", expErr: ErrInvalidAmount},
+		{s: "1.5navax", expErr: ErrInvalidAmount},
+		{s: "1.2.3avax", expErr: ErrInvalidAmount},
+		{s: "avax", expErr: ErrInvalidAmount},
+		{s: "", expErr: ErrInvalidAmount},
+		{s: "10eth", expErr: ErrUnknownUnit},
+		{s: "-1avax", expErr: ErrUnknownUnit},
+		{s: "99999999999999999999avax", expErr: ErrOverflow},
+	}
+	for i, tv := range tt {
+		v, err := Parse(tv.s)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d(%q): unexpected error %v, expected %v", i, tv.s, err, tv.expErr)
+		}
+		if v != tv.exp {
+			t.Fatalf("#%d(%q): unexpected amount %d, expected %d", i, tv.s, v, tv.exp)
+		}
+	}
+}
+
+func TestParseAsset(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s            string
+		denomination uint8
+		exp          uint64
+		expErr       error
+	}{
+		{s: "1.5", denomination: 3, exp: 1500},
+		{s: "1_000", denomination: 0, exp: 1000},
+		{s: "0.0005", denomination: 4, exp: 5},
+		{s: "12", denomination: 18, exp: 12_000_000_000_000_000_000},
+		{s: "19", denomination: 18, expErr: ErrOverflow},
+		{s: "1", denomination: 20, expErr: ErrOverflow},
+		{s: "0.5", denomination: 0, expErr: ErrInvalidAmount},
+		{s: "", denomination: 9, expErr: ErrInvalidAmount},
+		{s: "1avax", denomination: 9, expErr: ErrUnknownUnit},
+	}
+	for i, tv := range tt {
+		v, err := ParseAsset(tv.s, tv.denomination)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d(%q): unexpected error %v, expected %v", i, tv.s, err, tv.expErr)
+		}
+		if v != tv.exp {
+			t.Fatalf("#%d(%q): unexpected amount %d, expected %d", i, tv.s, v, tv.exp)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []uint64{0, 1, 1500 * units.MilliAvax, 2000 * units.Avax} {
+		s := Format(v)
+		pv, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pv != v {
+			t.Fatalf("%q: unexpected round-trip %d, expected %d", s, pv, v)
+		}
+	}
+}
+
+func TestFormatAsset(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		v            uint64
+		denomination uint8
+		symbol       string
+		exp          string
+	}{
+		{v: 1500, denomination: 3, symbol: "TKN", exp: "1.5 TKN"},
+		{v: 7, denomination: 0, symbol: "TKN", exp: "7 TKN"},
+		{v: 5, denomination: 4, symbol: "TKN", exp: "0.0005 TKN"},
+		{v: 0, denomination: 18, symbol: "TKN", exp: "0 TKN"},
+		{v: 12_000_000_000_000_000_000, denomination: 18, exp: "12"},
+	}
+	for i, tv := range tt {
+		if s := FormatAsset(tv.v, tv.denomination, tv.symbol); s != tv.exp {
+			t.Fatalf("#%d: unexpected %q, expected %q", i, s, tv.exp)
+		}
+	}
+}
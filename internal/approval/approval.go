@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package approval implements two-person approval: one operator signs a
+// proposal of an operation, and a second operator with a different key
+// approves it before the txs are issued.
+package approval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+// CurrentVersion is the proposal file version.
+const CurrentVersion = 1
+
+// DefaultTTL is how long a proposal can be approved.
+const DefaultTTL = 24 * time.Hour
+
+var (
+	ErrInvalidSignature = errors.New("invalid proposal signature")
+	ErrSameApprover     = errors.New("approver must use a different key than the proposer")
+	ErrExpired          = errors.New("proposal expired")
+	ErrAlreadyApproved  = errors.New("proposal already approved")
+	ErrMismatch         = errors.New("operation does not match the proposal")
+)
+
+// Proposal is a signed description of an operation.
+type Proposal struct {
+	Version int      `json:"version"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	// Flags are the flags set on the command, except those selecting the
+	// key or the output.
+	Flags        map[string]string `json:"flags,omitempty"`
+	Network      string            `json:"network"`
+	Spend        uint64            `json:"spend"`
+	WeightChange uint64            `json:"weightChange,omitempty"`
+	Proposer     ids.ShortID       `json:"proposer"`
+	Created      time.Time         `json:"created"`
+	Expires      time.Time         `json:"expires"`
+	Signature    string            `json:"signature,omitempty"`
+
+	Approval *Approval `json:"approval,omitempty"`
+}
+
+// Approval records the second operator's sign-off.
+type Approval struct {
+	Approver  ids.ShortID `json:"approver"`
+	Approved  time.Time   `json:"approved"`
+	Signature string      `json:"signature"`
+}
+
+// digest returns the hash of the proposal without its signatures.
+func (p *Proposal) digest() ([]byte, error) {
+	c := *p
+	c.Signature = ""
+	c.Approval = nil
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(b)
+	return h[:], nil
+}
+
+// approvalDigest binds the approval to the proposal and the approver.
+func (p *Proposal) approvalDigest(approver ids.ShortID, approved time.Time) ([]byte, error) {
+	d, err := p.digest()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256([]byte(fmt.Sprintf("%x|%s|%s|%d", d, p.Signature, approver, approved.UnixNano())))
+	return h[:], nil
+}
+
+// Sign signs the proposal as the first address of [s].
+func (p *Proposal) Sign(ctx context.Context, s key.Signer) error {
+	addrs := s.Addresses()
+	if len(addrs) == 0 {
+		return fmt.Errorf("%w: signer has no address", key.ErrCantSign)
+	}
+	p.Proposer = addrs[0]
+	d, err := p.digest()
+	if err != nil {
+		return err
+	}
+	sig, err := sign(ctx, s, d, p.Proposer)
+	if err != nil {
+		return err
+	}
+	p.Signature = sig
+	return nil
+}
+
+// Verify checks the proposer signature, and the approval signature if
+// approved.
+func (p *Proposal) Verify() error {
+	d, err := p.digest()
+	if err != nil {
+		return err
+	}
+	if err := verify(d, p.Signature, p.Proposer); err != nil {
+		return err
+	}
+	if p.Approval == nil {
+		return nil
+	}
+	d, err = p.approvalDigest(p.Approval.Approver, p.Approval.Approved)
+	if err != nil {
+		return err
+	}
+	return verify(d, p.Approval.Signature, p.Approval.Approver)
+}
+
+// Approve signs the approval of the verified proposal as the first address
+// of [s], which must differ from the proposer.
+func (p *Proposal) Approve(ctx context.Context, s key.Signer, now time.Time) error {
+	if p.Approval != nil {
+		return fmt.Errorf("%w: by %s at %s", ErrAlreadyApproved, p.Approval.Approver, p.Approval.Approved.Format(time.RFC3339))
+	}
+	if now.After(p.Expires) {
+		return fmt.Errorf("%w: at %s", ErrExpired, p.Expires.Format(time.RFC3339))
+	}
+	addrs := s.Addresses()
+	if len(addrs) == 0 {
+		return fmt.Errorf("%w: signer has no address", key.ErrCantSign)
+	}
+	for _, addr := range addrs {
+		if addr == p.Proposer {
+			return ErrSameApprover
+		}
+	}
+	approved := now.UTC()
+	d, err := p.approvalDigest(addrs[0], approved)
+	if err != nil {
+		return err
+	}
+	sig, err := sign(ctx, s, d, addrs[0])
+	if err != nil {
+		return err
+	}
+	p.Approval = &Approval{Approver: addrs[0], Approved: approved, Signature: sig}
+	return nil
+}
+
+// Match returns an error listing every difference between the proposal
+// and the operation [o] (whose signature fields are ignored).
+func (p *Proposal) Match(o *Proposal) error {
+	diffs := []string{}
+	if p.Command != o.Command {
+		diffs = append(diffs, fmt.Sprintf("command %q != %q", o.Command, p.Command))
+	}
+	if p.Network != o.Network {
+		diffs = append(diffs, fmt.Sprintf("network %q != %q", o.Network, p.Network))
+	}
+	if strings.Join(p.Args, " ") != strings.Join(o.Args, " ") {
+		diffs = append(diffs, fmt.Sprintf("args %q != %q", o.Args, p.Args))
+	}
+	names := map[string]struct{}{}
+	for k := range p.Flags {
+		names[k] = struct{}{}
+	}
+	for k := range o.Flags {
+		names[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		if p.Flags[k] != o.Flags[k] {
+			diffs = append(diffs, fmt.Sprintf("--%s %q != %q", k, o.Flags[k], p.Flags[k]))
+		}
+	}
+	// fees may change between proposal and approval; more spend or weight
+	// than approved may not
+	if o.Spend > p.Spend {
+		diffs = append(diffs, fmt.Sprintf("spend %d > %d", o.Spend, p.Spend))
+	}
+	if o.WeightChange > p.WeightChange {
+		diffs = append(diffs, fmt.Sprintf("weight change %d > %d", o.WeightChange, p.WeightChange))
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrMismatch, strings.Join(diffs, "; "))
+}
+
+// Load reads the proposal at [p].
+func Load(p string) (*Proposal, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	pr := new(Proposal)
+	if err := json.Unmarshal(b, pr); err != nil {
+		return nil, fmt.Errorf("%q: %w", p, err)
+	}
+	return pr, nil
+}
+
+// Save writes the proposal to [p].
+func (p *Proposal) Save(path string) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func sign(ctx context.Context, s key.Signer, hash []byte, addr ids.ShortID) (string, error) {
+	sigs, err := s.SignHash(ctx, hash, []ids.ShortID{addr})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sigs[0][:]), nil
+}
+
+func verify(hash []byte, sig string, addr ids.ShortID) error {
+	b, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	f := &crypto.FactorySECP256K1R{}
+	pk, err := f.RecoverHashPublicKey(hash, b)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if pk.Address() != addr {
+		return fmt.Errorf("%w: signed by %s, not %s", ErrInvalidSignature, pk.Address(), addr)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package approval
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+func testSigner(t *testing.T, encoded string) key.Signer {
+	opts := []key.SOpOption{}
+	if encoded != "" {
+		opts = append(opts, key.WithPrivateKeyEncoded(encoded))
+	}
+	k, err := key.NewSoft(constants.LocalID, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func testProposal(now time.Time) *Proposal {
+	return &Proposal{
+		Version: CurrentVersion,
+		Command: "add subnet-validator",
+		Flags:   map[string]string{"node-ids": "NodeID-a", "validate-weight": "1000"},
+		Network: "fuji",
+		Spend:   1_000_000,
+		Created: now,
+		Expires: now.Add(DefaultTTL),
+	}
+}
+
+func TestApprove(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_650_000_000, 0).UTC()
+	proposer := testSigner(t, key.EwoqPrivateKey)
+	approver := testSigner(t, "")
+
+	tt := []struct {
+		name   string
+		signer key.Signer
+		now    time.Time
+		err    error
+	}{
+		{name: "different key", signer: approver, now: now},
+		{name: "same key", signer: proposer, now: now, err: ErrSameApprover},
+		{name: "expired", signer: approver, now: now.Add(2 * DefaultTTL), err: ErrExpired},
+	}
+	for _, tv := range tt {
+		p := testProposal(now)
+		if err := p.Sign(context.Background(), proposer); err != nil {
+			t.Fatal(err)
+		}
+		err := p.Approve(context.Background(), tv.signer, tv.now)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if err := p.Verify(); err != nil {
+			t.Fatalf("%s: %v", tv.name, err)
+		}
+		if err := p.Approve(context.Background(), approver, tv.now); !errors.Is(err, ErrAlreadyApproved) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, ErrAlreadyApproved, err)
+		}
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_650_000_000, 0).UTC()
+	p := testProposal(now)
+	if err := p.Sign(context.Background(), testSigner(t, key.EwoqPrivateKey)); err != nil {
+		t.Fatal(err)
+	}
+	fp := filepath.Join(t.TempDir(), "proposal.json")
+	if err := p.Save(fp); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Flags["validate-weight"] = "9000"
+	if err := loaded.Verify(); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSignature, err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_650_000_000, 0).UTC()
+	tt := []struct {
+		name   string
+		modify func(o *Proposal)
+		err    error
+	}{
+		{name: "same", modify: func(o *Proposal) {}},
+		{name: "lower fee", modify: func(o *Proposal) { o.Spend-- }},
+		{name: "higher spend", modify: func(o *Proposal) { o.Spend++ }, err: ErrMismatch},
+		{name: "network", modify: func(o *Proposal) { o.Network = "mainnet" }, err: ErrMismatch},
+		{name: "flag", modify: func(o *Proposal) { o.Flags["validate-weight"] = "2000" }, err: ErrMismatch},
+		{name: "extra flag", modify: func(o *Proposal) { o.Flags["subnet-id"] = "x" }, err: ErrMismatch},
+		{name: "args", modify: func(o *Proposal) { o.Args = []string{"x"} }, err: ErrMismatch},
+	}
+	for _, tv := range tt {
+		o := testProposal(now)
+		tv.modify(o)
+		if err := testProposal(now).Match(o); !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package asset builds the initial state of new X-Chain assets (e.g., the
+// staking token of an elastic subnet).
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+var (
+	ErrInvalidHolder    = errors.New("invalid holder")
+	ErrInvalidMinterSet = errors.New("invalid minter set")
+	ErrNoInitialState   = errors.New("asset has neither holders nor minter sets")
+	ErrSupplyOverflow   = errors.New("total supply overflows uint64")
+)
+
+// Holder is an initial allocation of a new asset, as passed to
+// "create asset --holder".
+type Holder struct {
+	Address ids.ShortID
+	// Amount is in base units of the asset.
+	Amount uint64
+}
+
+// ParseHolder parses comma-separated key=value pairs (e.g.,
+// "address=X-fuji1...,amount=1000"), with the amount in units of an asset
+// with [denomination] decimal places.
+func ParseHolder(networkID uint32, s string, denomination uint8) (Holder, error) {
+	var h Holder
+	seen := map[string]bool{}
+	err := parsePairs(s, func(k string, v string) (err error) {
+		switch k {
+		case "address":
+			h.Address, err = key.ParsePAddress(networkID, v)
+		case "amount":
+			h.Amount, err = amount.ParseAsset(v, denomination)
+		default:
+			return fmt.Errorf("unknown key %q", k)
+		}
+		seen[k] = true
+		return err
+	})
+	if err != nil {
+		return Holder{}, fmt.Errorf("%w: %q: %v", ErrInvalidHolder, s, err)
+	}
+	if !seen["address"] || h.Amount == 0 {
+		return Holder{}, fmt.Errorf("%w: %q needs an address and a non-zero amount", ErrInvalidHolder, s)
+	}
+	return h, nil
+}
+
+// MinterSet is a threshold of addresses that may mint more of an asset, as
+// passed to "create asset --minter-set".
+type MinterSet struct {
+	Threshold uint32
+	Addresses []ids.ShortID
+}
+
+// ParseMinterSet parses comma-separated key=value pairs with one "address"
+// per minter (e.g., "threshold=2,address=X-fuji1...,address=X-fuji1...").
+// The threshold defaults to 1.
+func ParseMinterSet(networkID uint32, s string) (MinterSet, error) {
+	m := MinterSet{Threshold: 1}
+	seen := map[ids.ShortID]bool{}
+	err := parsePairs(s, func(k string, v string) error {
+		switch k {
+		case "threshold":
+			t, err := strconv.ParseUint(v, 10, 32)
+			m.Threshold = uint32(t)
+			return err
+		case "address":
+			addr, err := key.ParsePAddress(networkID, v)
+			if err != nil {
+				return err
+			}
+			if seen[addr] {
+				return fmt.Errorf("duplicate address %q", v)
+			}
+			seen[addr] = true
+			m.Addresses = append(m.Addresses, addr)
+			return nil
+		default:
+			return fmt.Errorf("unknown key %q", k)
+		}
+	})
+	if err != nil {
+		return MinterSet{}, fmt.Errorf("%w: %q: %v", ErrInvalidMinterSet, s, err)
+	}
+	if m.Threshold == 0 || int(m.Threshold) > len(m.Addresses) {
+		return MinterSet{}, fmt.Errorf("%w: %q has threshold %d of %d addresses", ErrInvalidMinterSet, s, m.Threshold, len(m.Addresses))
+	}
+	return m, nil
+}
+
+func parsePairs(s string, f func(k string, v string) error) error {
+	for _, kv := range strings.Split(s, ",") {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 {
+			return errors.New("expected key=value pairs")
+		}
+		if err := f(strings.TrimSpace(kvs[0]), strings.TrimSpace(kvs[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Supply returns the initial supply allocated to [holders].
+func Supply(holders []Holder) (uint64, error) {
+	supply := uint64(0)
+	for _, h := range holders {
+		if supply > math.MaxUint64-h.Amount {
+			return 0, ErrSupplyOverflow
+		}
+		supply += h.Amount
+	}
+	return supply, nil
+}
+
+// InitialState returns the secp256k1fx state of a new asset: one transfer
+// output per holder (allocations to the same address are merged) and one
+// mint output per minter set. Without minter sets, the supply is fixed.
+func InitialState(holders []Holder, minters []MinterSet) (*avm.InitialState, error) {
+	if len(holders) == 0 && len(minters) == 0 {
+		return nil, ErrNoInitialState
+	}
+	if _, err := Supply(holders); err != nil {
+		return nil, err
+	}
+	amounts := map[ids.ShortID]uint64{}
+	outs := []verify.State{}
+	for _, h := range holders {
+		if _, ok := amounts[h.Address]; !ok {
+			outs = append(outs, &secp256k1fx.TransferOutput{
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{h.Address}},
+			})
+		}
+		amounts[h.Address] += h.Amount
+	}
+	for _, out := range outs {
+		out := out.(*secp256k1fx.TransferOutput)
+		out.Amt = amounts[out.Addrs[0]]
+	}
+	for _, m := range minters {
+		addrs := append([]ids.ShortID{}, m.Addresses...)
+		ids.SortShortIDs(addrs)
+		outs = append(outs, &secp256k1fx.MintOutput{
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: m.Threshold, Addrs: addrs},
+		})
+	}
+	// secp256k1fx is the first fx of the X-Chain
+	state := &avm.InitialState{FxIndex: 0, Outs: outs}
+	state.Sort(codec.XCodecManager)
+	return state, nil
+}
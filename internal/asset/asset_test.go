@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package asset
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+func testAddress(t *testing.T, addr ids.ShortID) string {
+	s, err := key.FormatXAddress(constants.FujiID, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestParseHolder(t *testing.T) {
+	t.Parallel()
+
+	a := testAddress(t, ids.ShortID{1})
+	tt := []struct {
+		s      string
+		exp    Holder
+		expErr error
+	}{
+		{s: "address=" + a + ",amount=1.5", exp: Holder{Address: ids.ShortID{1}, Amount: 1500}},
+		{s: "amount=7, address=" + a, exp: Holder{Address: ids.ShortID{1}, Amount: 7000}},
+		{s: "address=" + a, expErr: ErrInvalidHolder},
+		{s: "address=" + a + ",amount=0", expErr: ErrInvalidHolder},
+		{s: "address=" + a + ",amount=0.0001", expErr: ErrInvalidHolder},
+		{s: "address=X-avax1invalid,amount=1", expErr: ErrInvalidHolder},
+		{s: "address=" + a + ",weight=1", expErr: ErrInvalidHolder},
+		{s: a, expErr: ErrInvalidHolder},
+	}
+	for i, tv := range tt {
+		h, err := ParseHolder(constants.FujiID, tv.s, 3)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if h != tv.exp {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.exp, h)
+		}
+	}
+}
+
+func TestParseMinterSet(t *testing.T) {
+	t.Parallel()
+
+	a, b := testAddress(t, ids.ShortID{1}), testAddress(t, ids.ShortID{2})
+	tt := []struct {
+		s         string
+		threshold uint32
+		addrs     int
+		expErr    error
+	}{
+		{s: "address=" + a, threshold: 1, addrs: 1},
+		{s: "threshold=2,address=" + a + ",address=" + b, threshold: 2, addrs: 2},
+		{s: "threshold=3,address=" + a + ",address=" + b, expErr: ErrInvalidMinterSet},
+		{s: "threshold=0,address=" + a, expErr: ErrInvalidMinterSet},
+		{s: "address=" + a + ",address=" + a, expErr: ErrInvalidMinterSet},
+		{s: "threshold=1", expErr: ErrInvalidMinterSet},
+	}
+	for i, tv := range tt {
+		m, err := ParseMinterSet(constants.FujiID, tv.s)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if m.Threshold != tv.threshold || len(m.Addresses) != tv.addrs {
+			t.Fatalf("#%d: unexpected minter set %+v", i, m)
+		}
+	}
+}
+
+func TestInitialState(t *testing.T) {
+	t.Parallel()
+
+	if _, err := InitialState(nil, nil); !errors.Is(err, ErrNoInitialState) {
+		t.Fatalf("expected %v, got %v", ErrNoInitialState, err)
+	}
+	if _, err := InitialState([]Holder{{Amount: math.MaxUint64}, {Amount: 1}}, nil); !errors.Is(err, ErrSupplyOverflow) {
+		t.Fatalf("expected %v, got %v", ErrSupplyOverflow, err)
+	}
+
+	holders := []Holder{
+		{Address: ids.ShortID{2}, Amount: 10},
+		{Address: ids.ShortID{1}, Amount: 5},
+		{Address: ids.ShortID{2}, Amount: 20},
+	}
+	minters := []MinterSet{{Threshold: 1, Addresses: []ids.ShortID{{3}, {1}}}}
+	state, err := InitialState(holders, minters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Verify(codec.XCodecManager, 1); err != nil {
+		t.Fatal(err)
+	}
+	// duplicate holders are merged
+	if len(state.Outs) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(state.Outs))
+	}
+	supply := uint64(0)
+	mints := 0
+	for _, out := range state.Outs {
+		switch out := out.(type) {
+		case *secp256k1fx.TransferOutput:
+			supply += out.Amt
+		case *secp256k1fx.MintOutput:
+			mints++
+			if out.Addrs[0] != (ids.ShortID{1}) {
+				t.Fatalf("unsorted minters %v", out.Addrs)
+			}
+		}
+	}
+	if supply != 35 || mints != 1 {
+		t.Fatalf("unexpected supply %d, %d mint outputs", supply, mints)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package avacli converts the subnet configurations of avalanche-cli
+// (ref. https://github.com/ava-labs/avalanche-cli) to and from specs, so
+// teams can migrate between the tools.
+package avacli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/spec"
+)
+
+const (
+	// SidecarFile and GenesisFile are the files of a subnet configuration
+	// (e.g., "~/.avalanche-cli/subnets/<name>/sidecar.json").
+	SidecarFile = "sidecar.json"
+	GenesisFile = "genesis.json"
+
+	// VM kinds of a sidecar.
+	SubnetEVM = "SubnetEVM"
+	CustomVM  = "Custom"
+
+	idLen = 32
+)
+
+var (
+	ErrInvalidSidecar = errors.New("invalid sidecar")
+	ErrUnknownNetwork = errors.New("unknown network")
+)
+
+// networks maps the network keys of a sidecar to network names.
+var networks = map[string]string{
+	"Mainnet":       "mainnet",
+	"Fuji":          "fuji",
+	"Local Network": "local",
+}
+
+// NetworkData is a deployment of the subnet.
+type NetworkData struct {
+	SubnetID     string `json:"SubnetID"`
+	BlockchainID string `json:"BlockchainID"`
+}
+
+// Sidecar describes a subnet configuration of avalanche-cli.
+type Sidecar struct {
+	Name      string `json:"Name"`
+	VM        string `json:"VM"`
+	VMVersion string `json:"VMVersion,omitempty"`
+	Subnet    string `json:"Subnet"`
+	TokenName string `json:"TokenName,omitempty"`
+	ChainID   string `json:"ChainID,omitempty"`
+	Version   string `json:"Version,omitempty"`
+	// Networks are the deployments keyed by network (e.g., "Fuji").
+	Networks     map[string]NetworkData `json:"Networks,omitempty"`
+	ImportedVMID string                 `json:"ImportedVMID,omitempty"`
+}
+
+// Load reads the sidecar in the subnet configuration directory [dir].
+func Load(dir string) (*Sidecar, error) {
+	b, err := os.ReadFile(filepath.Join(dir, SidecarFile))
+	if err != nil {
+		return nil, err
+	}
+	sc := new(Sidecar)
+	if err := json.Unmarshal(b, sc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSidecar, err)
+	}
+	if sc.Name == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrInvalidSidecar)
+	}
+	if sc.Subnet == "" {
+		sc.Subnet = sc.Name
+	}
+	return sc, nil
+}
+
+// Save writes the sidecar and [genesis] to the subnet configuration
+// directory [dir].
+func (sc *Sidecar) Save(dir string, genesis []byte) error {
+	b, err := json.MarshalIndent(sc, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, GenesisFile), genesis, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, SidecarFile), b, 0o644)
+}
+
+// nameVMID pads [name] to an ID, as avalanche-cli derives VM IDs.
+func nameVMID(name string) (ids.ID, error) {
+	if len(name) > idLen {
+		return ids.Empty, fmt.Errorf("%w: name must be <= %d bytes, found %d", ErrInvalidSidecar, idLen, len(name))
+	}
+	b := make([]byte, idLen)
+	copy(b, name)
+	return ids.ToID(b)
+}
+
+// VMID returns the VM ID of the subnet: the imported one, or the one
+// derived from the name.
+func (sc *Sidecar) VMID() (ids.ID, error) {
+	if sc.ImportedVMID != "" {
+		return ids.FromString(sc.ImportedVMID)
+	}
+	return nameVMID(sc.Name)
+}
+
+// SetVMID records [vmID] as imported, unless it is the one derived from
+// the name.
+func (sc *Sidecar) SetVMID(vmID ids.ID) {
+	sc.ImportedVMID = ""
+	if derived, err := nameVMID(sc.Name); err != nil || derived != vmID {
+		sc.ImportedVMID = vmID.String()
+	}
+}
+
+// networkKey returns the sidecar key of [network] (e.g., "Fuji" for
+// "fuji").
+func networkKey(network string) (string, error) {
+	for k, name := range networks {
+		if name == network {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownNetwork, network)
+}
+
+// Deployment returns the deployment of the subnet on [network] (e.g.,
+// "fuji"), if any.
+func (sc *Sidecar) Deployment(network string) (NetworkData, bool, error) {
+	k, err := networkKey(network)
+	if err != nil {
+		return NetworkData{}, false, err
+	}
+	nd, ok := sc.Networks[k]
+	return nd, ok, nil
+}
+
+// SetDeployment records the deployment of the subnet on [network],
+// keeping the ones on other networks.
+func (sc *Sidecar) SetDeployment(network string, nd NetworkData) error {
+	k, err := networkKey(network)
+	if err != nil {
+		return err
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]NetworkData)
+	}
+	sc.Networks[k] = nd
+	return nil
+}
+
+// VMKind guesses the sidecar VM kind from the chain [genesis]: subnet-evm
+// genesis configs have a fee config.
+func VMKind(genesis []byte) string {
+	var g struct {
+		Config struct {
+			FeeConfig json.RawMessage `json:"feeConfig"`
+		} `json:"config"`
+	}
+	if json.Unmarshal(genesis, &g) == nil && len(g.Config.FeeConfig) > 0 {
+		return SubnetEVM
+	}
+	return CustomVM
+}
+
+// ToSpec converts the sidecar to a spec on [network] whose chain genesis
+// is at [genesisPath]. A subnet already deployed on [network] is referenced
+// by its ID, so it is not created again.
+func (sc *Sidecar) ToSpec(network string, genesisPath string) (*spec.Spec, error) {
+	vmID, err := sc.VMID()
+	if err != nil {
+		return nil, err
+	}
+	nd, _, err := sc.Deployment(network)
+	if err != nil {
+		return nil, err
+	}
+	return &spec.Spec{
+		Version: spec.CurrentVersion,
+		Network: network,
+		Subnets: []spec.Subnet{{
+			Name: sc.Subnet,
+			ID:   nd.SubnetID,
+			Chains: []spec.Chain{{
+				// avalanche-cli names the chain after the subnet configuration
+				Name:    sc.Name,
+				VMID:    vmID.String(),
+				Genesis: genesisPath,
+			}},
+		}},
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avacli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const testSidecar = `{
+  "Name": "mysubnet",
+  "VM": "SubnetEVM",
+  "VMVersion": "v0.2.5",
+  "Subnet": "mysubnet",
+  "TokenName": "TEST",
+  "ChainID": "99999",
+  "Version": "1.0.0",
+  "Networks": {
+    "Fuji": {
+      "SubnetID": "24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1",
+      "BlockchainID": "2tig763Fw6aEczGgdZTkwNJEvr4TXHXZV5yQE5V2BcDB1Jy2Ut"
+    }
+  }
+}`
+
+func TestToSpec(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SidecarFile), []byte(testSidecar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		network  string
+		subnetID string
+		expErr   error
+	}{
+		{network: "fuji", subnetID: "24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"},
+		{network: "mainnet"},
+		{network: "devnet", expErr: ErrUnknownNetwork},
+	}
+	for i, tv := range tt {
+		s, err := sc.ToSpec(tv.network, "genesis.json")
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		sn := s.Subnets[0]
+		if sn.Name != "mysubnet" || sn.ID != tv.subnetID {
+			t.Fatalf("#%d: unexpected subnet %+v", i, sn)
+		}
+		// "mysubnet" padded to 32 bytes
+		if exp := "qDNsVQJfGpi2RfCcESbeZauGqPVjtXwoopVMtrGkdoUxmFKov"; sn.Chains[0].VMID != exp {
+			t.Fatalf("#%d: expected VM ID %s, got %s", i, exp, sn.Chains[0].VMID)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SidecarFile), []byte(`{"VM": "SubnetEVM"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); !errors.Is(err, ErrInvalidSidecar) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSidecar, err)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	vmID := ids.GenerateTestID()
+	genesis := []byte(`{"config":{"chainId":99999,"feeConfig":{"gasLimit":8000000}}}`)
+	tt := []struct {
+		name        string
+		vmID        ids.ID
+		expImported string
+	}{
+		{name: "mysubnet", vmID: ids.ID{'m', 'y', 's', 'u', 'b', 'n', 'e', 't'}},
+		{name: "mysubnet", vmID: vmID, expImported: vmID.String()},
+	}
+	for i, tv := range tt {
+		dir := t.TempDir()
+		sc := &Sidecar{Name: tv.name, Subnet: tv.name, VM: VMKind(genesis)}
+		sc.SetVMID(tv.vmID)
+		if err := sc.SetDeployment("fuji", NetworkData{SubnetID: "a", BlockchainID: "b"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sc.Save(dir, genesis); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.VM != SubnetEVM {
+			t.Fatalf("#%d: expected %s, got %s", i, SubnetEVM, loaded.VM)
+		}
+		if loaded.ImportedVMID != tv.expImported {
+			t.Fatalf("#%d: expected imported VM ID %q, got %q", i, tv.expImported, loaded.ImportedVMID)
+		}
+		if id, err := loaded.VMID(); err != nil || id != tv.vmID {
+			t.Fatalf("#%d: expected VM ID %s, got %s (%v)", i, tv.vmID, id, err)
+		}
+		if nd, ok, err := loaded.Deployment("fuji"); err != nil || !ok || nd.BlockchainID != "b" {
+			t.Fatalf("#%d: unexpected deployment %+v (%v, %v)", i, nd, ok, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bootstrap estimates how long a blockchain takes to bootstrap from
+// the progression of its last accepted height.
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// DefaultWindow is how many samples the rate is computed over.
+const DefaultWindow = 10
+
+// Sample is the last accepted height at a time.
+type Sample struct {
+	Time   time.Time
+	Height uint64
+}
+
+// Estimator keeps the latest height samples of a bootstrapping blockchain.
+type Estimator struct {
+	window  int
+	samples []Sample
+	target  uint64
+}
+
+// NewEstimator returns an estimator computing the rate over the last
+// [window] samples ([DefaultWindow] if less than 2).
+func NewEstimator(window int) *Estimator {
+	if window <= 1 {
+		window = DefaultWindow
+	}
+	return &Estimator{window: window}
+}
+
+// Add records the [height] accepted at [t]. A height lower than the
+// previous one (e.g., the node restarted) resets the samples.
+func (e *Estimator) Add(t time.Time, height uint64) {
+	if n := len(e.samples); n > 0 && height < e.samples[n-1].Height {
+		e.samples = e.samples[:0]
+	}
+	e.samples = append(e.samples, Sample{Time: t, Height: height})
+	if len(e.samples) > e.window {
+		e.samples = e.samples[len(e.samples)-e.window:]
+	}
+}
+
+// SetTarget sets the height bootstrapping catches up to (e.g., the last
+// accepted height of a bootstrapped node).
+func (e *Estimator) SetTarget(height uint64) {
+	e.target = height
+}
+
+// Estimate is the bootstrap progress.
+type Estimate struct {
+	Height uint64
+	// Target is zero if unknown.
+	Target uint64
+	// Rate is in blocks per second, zero until two samples are apart.
+	Rate float64
+	// ETA is the estimated time left; zero if unknown (no target or no
+	// progress).
+	ETA time.Duration
+}
+
+// Estimate returns the progress of the samples, or false if there are none.
+func (e *Estimator) Estimate() (Estimate, bool) {
+	if len(e.samples) == 0 {
+		return Estimate{}, false
+	}
+	first, last := e.samples[0], e.samples[len(e.samples)-1]
+	est := Estimate{Height: last.Height, Target: e.target}
+	if elapsed := last.Time.Sub(first.Time); elapsed > 0 {
+		est.Rate = float64(last.Height-first.Height) / elapsed.Seconds()
+	}
+	if est.Target > est.Height && est.Rate > 0 {
+		est.ETA = time.Duration(float64(est.Target-est.Height) / est.Rate * float64(time.Second)).Round(time.Second)
+	}
+	return est, true
+}
+
+// String formats the estimate
+// (e.g., "height 1,200/5,000 (24%) · 80.0 blocks/s · ETA 47s").
+func (est Estimate) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "height %s", humanize.Comma(int64(est.Height)))
+	if est.Target > 0 {
+		pct := 100.0
+		if est.Height < est.Target {
+			pct = float64(est.Height) * 100 / float64(est.Target)
+		}
+		fmt.Fprintf(&sb, "/%s (%.0f%%)", humanize.Comma(int64(est.Target)), pct)
+	}
+	fmt.Fprintf(&sb, " · %.1f blocks/s", est.Rate)
+	if est.ETA > 0 {
+		fmt.Fprintf(&sb, " · ETA %s", est.ETA)
+	}
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimator(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	tt := []struct {
+		window  int
+		heights []uint64
+		target  uint64
+		exp     Estimate
+		expStr  string
+	}{
+		{
+			heights: []uint64{1_000},
+			exp:     Estimate{Height: 1_000},
+			expStr:  "height 1,000 · 0.0 blocks/s",
+		},
+		{
+			heights: []uint64{0, 100, 200},
+			exp:     Estimate{Height: 200, Rate: 10},
+			expStr:  "height 200 · 10.0 blocks/s",
+		},
+		{
+			heights: []uint64{1_000, 1_400, 1_800, 2_200},
+			target:  5_000,
+			exp:     Estimate{Height: 2_200, Target: 5_000, Rate: 40, ETA: 70 * time.Second},
+			expStr:  "height 2,200/5,000 (44%) · 40.0 blocks/s · ETA 1m10s",
+		},
+		{
+			// only the last 2 samples count
+			window:  2,
+			heights: []uint64{0, 1_000, 1_100},
+			target:  2_100,
+			exp:     Estimate{Height: 1_100, Target: 2_100, Rate: 10, ETA: 100 * time.Second},
+			expStr:  "height 1,100/2,100 (52%) · 10.0 blocks/s · ETA 1m40s",
+		},
+		{
+			// the node restarted
+			heights: []uint64{5_000, 6_000, 100, 300},
+			exp:     Estimate{Height: 300, Rate: 20},
+			expStr:  "height 300 · 20.0 blocks/s",
+		},
+		{
+			// the target moved behind
+			heights: []uint64{0, 200},
+			target:  100,
+			exp:     Estimate{Height: 200, Target: 100, Rate: 20},
+			expStr:  "height 200/100 (100%) · 20.0 blocks/s",
+		},
+	}
+	for i, tv := range tt {
+		e := NewEstimator(tv.window)
+		for j, h := range tv.heights {
+			e.Add(start.Add(time.Duration(j)*10*time.Second), h)
+		}
+		e.SetTarget(tv.target)
+		est, ok := e.Estimate()
+		if !ok {
+			t.Fatalf("#%d: expected an estimate", i)
+		}
+		if est != tv.exp {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.exp, est)
+		}
+		if s := est.String(); s != tv.expStr {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.expStr, s)
+		}
+	}
+
+	if _, ok := NewEstimator(0).Estimate(); ok {
+		t.Fatal("expected no estimate without samples")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package cache implements a local cache of read-only API responses, so
+// repeated lookups against public endpoints are fast and do not count
+// against their rate limits.
+package cache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// registers the "sqlite" driver
+	_ "modernc.org/sqlite"
+)
+
+// DirName is the name of the cache directory under the user cache
+// directory (e.g., "~/.cache/subnet-cli").
+const DirName = "subnet-cli"
+
+// FileName is the name of the cache database in the cache directory.
+const FileName = "cache.db"
+
+var ErrEmptyDir = errors.New("empty cache directory")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS entries (
+	key     TEXT PRIMARY KEY,
+	expires INTEGER NOT NULL,
+	value   BLOB NOT NULL
+);
+`
+
+// Cache is a SQLite-backed cache of JSON values with per-entry TTLs.
+type Cache struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// New returns a cache stored in [dir], creating it if needed.
+func New(dir string) (*Cache, error) {
+	if dir == "" {
+		return nil, ErrEmptyDir
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, FileName)
+	// concurrent invocations wait for each other's writes
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{db: db, now: time.Now}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// drop expired entries so the database does not grow unbounded
+	if _, err := db.Exec("DELETE FROM entries WHERE expires <= ?", c.now().UnixNano()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Default returns the cache in the user cache directory.
+func Default() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, DirName))
+}
+
+func (c *Cache) Close() error { return c.db.Close() }
+
+// Key returns the cache key of [parts] (e.g., endpoint, method and
+// arguments), which must be JSON-encodable.
+func Key(parts ...interface{}) (string, error) {
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// Get decodes the entry of [key] into [v], returning false if the entry
+// is missing, expired or unreadable.
+func (c *Cache) Get(key string, v interface{}) bool {
+	var b []byte
+	err := c.db.QueryRow(
+		"SELECT value FROM entries WHERE key = ? AND expires > ?",
+		key, c.now().UnixNano(),
+	).Scan(&b)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(b, v) == nil
+}
+
+// Put stores [v] under [key] for [ttl].
+func (c *Cache) Put(key string, v interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = c.db.Exec(
+		"REPLACE INTO entries (key, expires, value) VALUES (?, ?, ?)",
+		key, c.now().Add(ttl).UnixNano(), b,
+	)
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	k1, err := Key("http://localhost:9650", "getSubnets", []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := Key("http://localhost:9650", "getSubnets", []string{"b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k1 == k2 {
+		t.Fatal("expected different keys for different arguments")
+	}
+
+	var v []string
+	if c.Get(k1, &v) {
+		t.Fatal("unexpected hit on empty cache")
+	}
+	if err := c.Put(k1, []string{"x", "y"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		elapsed time.Duration
+		key     string
+		expHit  bool
+	}{
+		{elapsed: 0, key: k1, expHit: true},
+		{elapsed: 59 * time.Second, key: k1, expHit: true},
+		{elapsed: 59 * time.Second, key: k2, expHit: false},
+		{elapsed: time.Minute, key: k1, expHit: false},
+	}
+	for i, tv := range tt {
+		now = time.Unix(1000, 0).Add(tv.elapsed)
+		v = nil
+		if hit := c.Get(tv.key, &v); hit != tv.expHit {
+			t.Fatalf("#%d: expected hit %v, got %v", i, tv.expHit, hit)
+		}
+		if tv.expHit && (len(v) != 2 || v[0] != "x" || v[1] != "y") {
+			t.Fatalf("#%d: unexpected value %v", i, v)
+		}
+	}
+
+	// corrupt entries are misses
+	if err := c.Put(k2, nil, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.db.Exec("UPDATE entries SET value = ? WHERE key = ?", []byte("{"), k2); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get(k2, &v) {
+		t.Fatal("unexpected hit on corrupt entry")
+	}
+}
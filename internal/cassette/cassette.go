@@ -0,0 +1,264 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package cassette records HTTP exchanges to a file ("cassette") and
+// replays them without a network, so command behaviors can be tested
+// deterministically and reported issues reproduced from captured traffic.
+//
+// Requests are matched on their HTTP method, URL and JSON-RPC method, and
+// the exchanges of each match are replayed in the recorded order (e.g.,
+// successive tx status polls). Request bodies are not compared: txs embed
+// the time they were built at, so a replayed run never rebuilds the
+// recorded bytes.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Version is the cassette file format version.
+const Version = 1
+
+var (
+	ErrMiss               = errors.New("no recorded exchange")
+	ErrUnsupportedVersion = errors.New("unsupported cassette version")
+)
+
+// Interaction is one recorded exchange.
+type Interaction struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	RPCMethod string      `json:"rpcMethod,omitempty"`
+	Request   interface{} `json:"request,omitempty"`
+
+	Status int `json:"status,omitempty"`
+	// Response is the JSON response body, or the raw body as a string if
+	// it is not JSON.
+	Response        interface{} `json:"response,omitempty"`
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty"`
+	// Error is the transport error of the exchange, if any.
+	Error string `json:"error,omitempty"`
+}
+
+func (i Interaction) key() string {
+	return i.Method + " " + i.URL + " " + i.RPCMethod
+}
+
+// Cassette is a recording.
+type Cassette struct {
+	Version      int           `json:"version"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads the cassette at [path].
+func Load(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := new(Cassette)
+	d := json.NewDecoder(bytes.NewReader(b))
+	// keep large integers (e.g., nAVAX amounts) exact
+	d.UseNumber()
+	if err := d.Decode(c); err != nil {
+		return nil, fmt.Errorf("%q: %w", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("%w: %d (expected %d)", ErrUnsupportedVersion, c.Version, Version)
+	}
+	return c, nil
+}
+
+// RPCMethod returns the JSON-RPC method of the request [body], or "" if it
+// is not a JSON-RPC request.
+func RPCMethod(body []byte) string {
+	var rpc struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(body, &rpc) != nil {
+		return ""
+	}
+	return rpc.Method
+}
+
+// Recorder appends interactions to a cassette file.
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+	c    Cassette
+}
+
+// NewRecorder returns a recorder writing to [path], which it creates (or
+// truncates) right away so a run without any exchange still leaves an
+// empty cassette.
+func NewRecorder(path string) (*Recorder, error) {
+	r := &Recorder{path: path, c: Cassette{Version: Version, RecordedAt: time.Now().UTC(), Interactions: []Interaction{}}}
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Add appends [i] and rewrites the cassette, so it stays complete even if
+// the process exits abruptly.
+func (r *Recorder) Add(i Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.c.Interactions = append(r.c.Interactions, i)
+	return r.save()
+}
+
+func (r *Recorder) save() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.c); err != nil {
+		return err
+	}
+	// write to a temporary file first, so an interrupted write never
+	// leaves a truncated cassette
+	f, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), r.path)
+}
+
+// Player is an [http.RoundTripper] serving the interactions of a cassette.
+type Player struct {
+	mu     sync.Mutex
+	queues map[string][]Interaction
+	// normalize maps request URLs to their recorded form (e.g., with
+	// credentials redacted); nil keeps them as is.
+	normalize func(u string) string
+}
+
+var _ http.RoundTripper = &Player{}
+
+// NewPlayer returns a player of [c]. [normalize], if not nil, maps each
+// request URL to the form it was recorded in.
+func NewPlayer(c *Cassette, normalize func(u string) string) *Player {
+	p := &Player{queues: make(map[string][]Interaction), normalize: normalize}
+	for _, i := range c.Interactions {
+		k := i.key()
+		p.queues[k] = append(p.queues[k], i)
+	}
+	return p
+}
+
+// Remaining returns the number of interactions not replayed yet.
+func (p *Player) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, q := range p.queues {
+		n += len(q)
+	}
+	return n
+}
+
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	u := req.URL.String()
+	if p.normalize != nil {
+		u = p.normalize(u)
+	}
+	rpcMethod := RPCMethod(body)
+	k := Interaction{Method: req.Method, URL: u, RPCMethod: rpcMethod}.key()
+
+	p.mu.Lock()
+	q := p.queues[k]
+	if len(q) == 0 {
+		p.mu.Unlock()
+		if rpcMethod != "" {
+			return nil, fmt.Errorf("%w for %s %s (%s)", ErrMiss, req.Method, u, rpcMethod)
+		}
+		return nil, fmt.Errorf("%w for %s %s", ErrMiss, req.Method, u)
+	}
+	i := q[0]
+	p.queues[k] = q[1:]
+	p.mu.Unlock()
+
+	if i.Error != "" {
+		return nil, errors.New(i.Error)
+	}
+	b, err := responseBody(i.Response, body)
+	if err != nil {
+		return nil, err
+	}
+	header := i.ResponseHeaders.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
+
+// responseBody encodes the recorded [response], answering the JSON-RPC
+// request [reqBody] with its ID.
+func responseBody(response interface{}, reqBody []byte) ([]byte, error) {
+	if s, ok := response.(string); ok {
+		return []byte(s), nil
+	}
+	if response == nil {
+		return nil, nil
+	}
+	if m, ok := response.(map[string]interface{}); ok {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if _, hasID := m["id"]; hasID && json.Unmarshal(reqBody, &req) == nil && len(req.ID) > 0 {
+			cm := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				cm[k] = v
+			}
+			cm["id"] = req.ID
+			response = cm
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(response); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []Interaction{
+		{Method: "POST", URL: "http://node/ext/P", RPCMethod: "platform.getTxStatus", Status: 200, Response: map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{"status": "Processing"}}},
+		{Method: "POST", URL: "http://node/ext/P", RPCMethod: "platform.getTxStatus", Status: 200, Response: map[string]interface{}{"jsonrpc": "2.0", "id": 2, "result": map[string]interface{}{"status": "Committed"}}},
+		{Method: "POST", URL: "http://node/ext/P", RPCMethod: "platform.getBalance", Status: 200, Response: map[string]interface{}{"jsonrpc": "2.0", "id": 3, "result": map[string]interface{}{"balance": "18446744073709551615"}}},
+		{Method: "GET", URL: "http://node/ext/health", Status: 503, Response: "unhealthy"},
+		{Method: "POST", URL: "http://node/ext/info", RPCMethod: "info.peers", Error: "connection refused"},
+	} {
+		if err := r.Add(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPlayer(c, nil)
+
+	tt := []struct {
+		method string
+		url    string
+		body   string
+		status int
+		resp   string
+		err    string
+	}{
+		{method: "POST", url: "http://node/ext/P", body: `{"jsonrpc":"2.0","method":"platform.getTxStatus","id":7}`, status: 200, resp: `{"id":7,"jsonrpc":"2.0","result":{"status":"Processing"}}`},
+		{method: "POST", url: "http://node/ext/P", body: `{"jsonrpc":"2.0","method":"platform.getBalance","id":8}`, status: 200, resp: `{"id":8,"jsonrpc":"2.0","result":{"balance":"18446744073709551615"}}`},
+		{method: "POST", url: "http://node/ext/P", body: `{"jsonrpc":"2.0","method":"platform.getTxStatus","id":9}`, status: 200, resp: `{"id":9,"jsonrpc":"2.0","result":{"status":"Committed"}}`},
+		{method: "POST", url: "http://node/ext/P", body: `{"jsonrpc":"2.0","method":"platform.getTxStatus","id":10}`, err: ErrMiss.Error()},
+		{method: "GET", url: "http://node/ext/health", status: 503, resp: "unhealthy"},
+		{method: "POST", url: "http://node/ext/info", body: `{"method":"info.peers"}`, err: "connection refused"},
+	}
+	for i, tv := range tt {
+		req, err := http.NewRequest(tv.method, tv.url, strings.NewReader(tv.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := p.RoundTrip(req)
+		if tv.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tv.err) {
+				t.Fatalf("#%d: expected error %q, got %v", i, tv.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tv.status || string(b) != tv.resp {
+			t.Fatalf("#%d: expected %d %s, got %d %s", i, tv.status, tv.resp, res.StatusCode, b)
+		}
+	}
+	if n := p.Remaining(); n != 0 {
+		t.Fatalf("expected every interaction replayed, %d left", n)
+	}
+}
+
+func TestLoadVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.c.Version = Version + 1
+	if err := r.save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected %v, got %v", ErrUnsupportedVersion, err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package clipboard copies text to the system clipboard with the platform
+// clipboard tools, falling back to the OSC 52 terminal escape sequence
+// (e.g., over SSH, where the terminal owns the clipboard).
+package clipboard
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var ErrUnavailable = errors.New("no clipboard available (install wl-copy, xclip or xsel, or use a terminal supporting OSC 52)")
+
+// commands returns the clipboard tools to try on [goos], in order of
+// preference.
+func commands(goos string, getenv func(string) string) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var cmds [][]string
+	if getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	if getenv("DISPLAY") != "" {
+		cmds = append(cmds,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+	// WSL shares the Windows clipboard
+	if getenv("WSL_DISTRO_NAME") != "" {
+		cmds = append(cmds, []string{"clip.exe"})
+	}
+	return cmds
+}
+
+// OSC52 returns the escape sequence that asks the terminal to set its
+// clipboard to [text].
+func OSC52(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	// tmux and screen only forward escape sequences wrapped for them
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// Write copies [text] to the clipboard. If no clipboard tool succeeds, it
+// writes the OSC 52 sequence to [term] (nil if not a terminal).
+func Write(ctx context.Context, text string, term io.Writer) error {
+	var errs []string
+	for _, c := range commands(runtime.GOOS, os.Getenv) {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.CommandContext(ctx, c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c[0], err))
+			continue
+		}
+		return nil
+	}
+	if term != nil {
+		_, err := io.WriteString(term, OSC52(text))
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnavailable, strings.Join(errs, "; "))
+	}
+	return ErrUnavailable
+}
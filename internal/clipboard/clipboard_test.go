@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clipboard
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		goos string
+		env  map[string]string
+		cmds []string
+	}{
+		{goos: "darwin", cmds: []string{"pbcopy"}},
+		{goos: "windows", cmds: []string{"clip.exe"}},
+		{goos: "linux"},
+		{goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, cmds: []string{"wl-copy", "xclip", "xsel"}},
+		{goos: "linux", env: map[string]string{"DISPLAY": ":0"}, cmds: []string{"xclip", "xsel"}},
+		{goos: "linux", env: map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, cmds: []string{"clip.exe"}},
+	}
+	for i, tv := range tt {
+		var names []string
+		for _, c := range commands(tv.goos, func(k string) string { return tv.env[k] }) {
+			names = append(names, c[0])
+		}
+		if !reflect.DeepEqual(names, tv.cmds) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.cmds, names)
+		}
+	}
+}
+
+func TestOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	id := "2PsShLjrFFwR51DMcAh8pyuwzLn1Ym3zRhuXLTmLCR1STk2mL6"
+	seq := OSC52(id)
+	if !strings.HasPrefix(seq, "\x1b]52;c;") || !strings.HasSuffix(seq, "\a") {
+		t.Fatalf("unexpected sequence %q", seq)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b]52;c;"), "\a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != id {
+		t.Fatalf("expected %q, got %q", id, b)
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	if seq := OSC52(id); !strings.HasPrefix(seq, "\x1bPtmux;\x1b\x1b]52;c;") || !strings.HasSuffix(seq, "\x1b\\") {
+		t.Fatalf("unexpected tmux sequence %q", seq)
+	}
+}
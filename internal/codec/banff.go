@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// The avalanchego dependency also predates the Banff upgrade, which added
+// the first P-Chain txs after the ones it registers.
+// ref. "vms/platformvm/txs" of avalanchego v1.9.
+
+// removeSubnetValidatorTypeID is the codec type ID of
+// [RemoveSubnetValidatorTx], right after "platformvm.StakeableLockOut".
+const removeSubnetValidatorTypeID = 23
+
+var (
+	ErrRemovePrimaryNetworkValidator = errors.New("can't remove a primary network validator")
+	ErrEmptyNodeID                   = errors.New("empty node ID")
+)
+
+// RemoveSubnetValidatorTx removes [NodeID] from the validators of the
+// permissioned subnet [Subnet] before its validation period ends.
+type RemoveSubnetValidatorTx struct {
+	platformvm.BaseTx `serialize:"true"`
+
+	NodeID     ids.ShortID       `serialize:"true" json:"nodeID"`
+	Subnet     ids.ID            `serialize:"true" json:"subnetID"`
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+func (tx *RemoveSubnetValidatorTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx.Subnet == constants.PrimaryNetworkID:
+		return ErrRemovePrimaryNetworkValidator
+	case tx.NodeID == ids.ShortEmpty:
+		return ErrEmptyNodeID
+	}
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	return tx.SubnetAuth.Verify()
+}
+
+func (*RemoveSubnetValidatorTx) SemanticVerify(*platformvm.VM, platformvm.MutableState, *platformvm.Tx) error {
+	return ErrNotExecutable
+}
+
+// Banff blocks carry their timestamp instead of an advance time tx and are
+// registered after the Banff txs, which this codec does not all know.
+const (
+	firstBanffTxTypeID    = removeSubnetValidatorTypeID
+	firstBanffBlockTypeID = 29
+)
+
+// BanffProposalBlock proposes [Tx], which only takes effect if the next
+// block is a [BanffCommitBlock].
+type BanffProposalBlock struct {
+	Time uint64 `serialize:"true" json:"time"`
+	// Transactions is unused by the network.
+	Transactions []*platformvm.Tx `serialize:"true" json:"-"`
+	ParentID     ids.ID           `serialize:"true" json:"parentID"`
+	Height       uint64           `serialize:"true" json:"height"`
+	Tx           *platformvm.Tx   `serialize:"true" json:"tx"`
+}
+
+// BanffAbortBlock rejects the proposal of its parent.
+type BanffAbortBlock struct {
+	Time     uint64 `serialize:"true" json:"time"`
+	ParentID ids.ID `serialize:"true" json:"parentID"`
+	Height   uint64 `serialize:"true" json:"height"`
+}
+
+// BanffCommitBlock accepts the proposal of its parent.
+type BanffCommitBlock struct {
+	Time     uint64 `serialize:"true" json:"time"`
+	ParentID ids.ID `serialize:"true" json:"parentID"`
+	Height   uint64 `serialize:"true" json:"height"`
+}
+
+// BanffStandardBlock executes [Transactions] in order.
+type BanffStandardBlock struct {
+	Time         uint64           `serialize:"true" json:"time"`
+	ParentID     ids.ID           `serialize:"true" json:"parentID"`
+	Height       uint64           `serialize:"true" json:"height"`
+	Transactions []*platformvm.Tx `serialize:"true" json:"txs"`
+}
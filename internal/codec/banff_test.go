@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestRemoveSubnetValidatorTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := &snow.Context{NetworkID: 5, ChainID: ids.Empty}
+	subnetID := ids.GenerateTestID()
+	tt := []struct {
+		name   string
+		subnet ids.ID
+		nodeID ids.ShortID
+		err    error
+	}{
+		{name: "valid", subnet: subnetID, nodeID: ids.ShortID{1}},
+		{name: "primary network", subnet: ids.Empty, nodeID: ids.ShortID{1}, err: ErrRemovePrimaryNetworkValidator},
+		{name: "empty node ID", subnet: subnetID, err: ErrEmptyNodeID},
+	}
+	for _, tv := range tt {
+		utx := &RemoveSubnetValidatorTx{
+			BaseTx:     platformvm.BaseTx{BaseTx: avax.BaseTx{NetworkID: 5, BlockchainID: ids.Empty}},
+			NodeID:     tv.nodeID,
+			Subnet:     tv.subnet,
+			SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0}},
+		}
+		initialize(t, utx)
+		if err := utx.SyntacticVerify(ctx); !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+
+		var tx platformvm.UnsignedTx = utx
+		b, err := PCodecManager.Marshal(platformvm.CodecVersion, &tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := binary.BigEndian.Uint32(b[2:]); id != removeSubnetValidatorTypeID {
+			t.Fatalf("%s: unexpected type ID %d", tv.name, id)
+		}
+		var decoded platformvm.UnsignedTx
+		if _, err := PCodecManager.Unmarshal(b, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		got, ok := decoded.(*RemoveSubnetValidatorTx)
+		if !ok {
+			t.Fatalf("%s: unexpected type %T", tv.name, decoded)
+		}
+		if got.NodeID != tv.nodeID || got.Subnet != tv.subnet {
+			t.Fatalf("%s: unexpected tx %+v", tv.name, got)
+		}
+	}
+}
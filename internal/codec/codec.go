@@ -41,6 +41,23 @@ func init() {
 		pc.RegisterType(&platformvm.UnsignedRewardValidatorTx{}),
 		pc.RegisterType(&platformvm.StakeableLockIn{}),
 		pc.RegisterType(&platformvm.StakeableLockOut{}),
+		pc.RegisterType(&RemoveSubnetValidatorTx{}),
+	)
+	// TransformSubnetTx, the permissionless staker txs and their signers
+	pc.SkipRegistrations(firstBanffBlockTypeID - firstBanffTxTypeID - 1)
+	errs.Add(
+		pc.RegisterType(&BanffProposalBlock{}),
+		pc.RegisterType(&BanffAbortBlock{}),
+		pc.RegisterType(&BanffCommitBlock{}),
+		pc.RegisterType(&BanffStandardBlock{}),
+	)
+	// the Durango txs
+	pc.SkipRegistrations(firstEtnaTypeID - firstBanffBlockTypeID - 4)
+	errs.Add(
+		pc.RegisterType(&ConvertSubnetToL1Tx{}),
+		pc.RegisterType(&RegisterL1ValidatorTx{}),
+		pc.RegisterType(&SetL1ValidatorWeightTx{}),
+		pc.RegisterType(&IncreaseL1ValidatorBalanceTx{}),
 		PCodecManager.RegisterCodec(0, pc),
 	)
 	if errs.Errored() {
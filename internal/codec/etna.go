@@ -0,0 +1,180 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// The avalanchego dependency predates the Etna upgrade (ACP-77 sovereign
+// L1s), so its P-Chain txs are defined here with the same serialization.
+// ref. "vms/platformvm/txs" of avalanchego v1.12.
+
+// firstEtnaTypeID is the codec type ID of [ConvertSubnetToL1Tx]. The types
+// registered by the dependency end at 22 and [RemoveSubnetValidatorTx] takes
+// 23; the other Banff and Durango txs and their blocks take the IDs in
+// between.
+const firstEtnaTypeID = 35
+
+// MaxManagerAddressLen bounds the validator manager address of an L1.
+const MaxManagerAddressLen = 4096
+
+var (
+	ErrNotExecutable      = errors.New("txs of the upgrades after the dependency are only executed by the network")
+	ErrNoL1Validators     = errors.New("no L1 validators")
+	ErrL1ValidatorsOrder  = errors.New("L1 validators not sorted and unique by node ID")
+	ErrInvalidL1Validator = errors.New("invalid L1 validator")
+	ErrManagerAddressLen  = errors.New("validator manager address too long")
+	ErrZeroBalance        = errors.New("zero balance increase")
+	ErrEmptyWarpMessage   = errors.New("empty warp message")
+)
+
+// ProofOfPossession is a BLS public key and the signature of it by its
+// secret key.
+type ProofOfPossession struct {
+	PublicKey         [48]byte `serialize:"true" json:"publicKey"`
+	ProofOfPossession [96]byte `serialize:"true" json:"proofOfPossession"`
+}
+
+// PChainOwner is a threshold of P-Chain addresses (e.g., that receives the
+// remaining balance of an L1 validator).
+type PChainOwner struct {
+	Threshold uint32        `serialize:"true" json:"threshold"`
+	Addresses []ids.ShortID `serialize:"true" json:"addresses"`
+}
+
+// ConvertSubnetToL1Validator is an initial validator of a converted L1.
+type ConvertSubnetToL1Validator struct {
+	NodeID []byte `serialize:"true" json:"nodeID"`
+	Weight uint64 `serialize:"true" json:"weight"`
+	// Balance is the nAVAX prepaid for the continuous validator fee.
+	Balance               uint64            `serialize:"true" json:"balance"`
+	Signer                ProofOfPossession `serialize:"true" json:"signer"`
+	RemainingBalanceOwner PChainOwner       `serialize:"true" json:"remainingBalanceOwner"`
+	DeactivationOwner     PChainOwner       `serialize:"true" json:"deactivationOwner"`
+}
+
+// ConvertSubnetToL1Tx converts a permissioned subnet to a sovereign L1,
+// whose validator set is then managed by the contract at [Address] on
+// [ChainID].
+type ConvertSubnetToL1Tx struct {
+	platformvm.BaseTx `serialize:"true"`
+
+	Subnet     ids.ID                        `serialize:"true" json:"subnetID"`
+	ChainID    ids.ID                        `serialize:"true" json:"chainID"`
+	Address    []byte                        `serialize:"true" json:"address"`
+	Validators []*ConvertSubnetToL1Validator `serialize:"true" json:"validators"`
+	SubnetAuth verify.Verifiable             `serialize:"true" json:"subnetAuthorization"`
+}
+
+// SortL1Validators sorts [vs] by node ID, as the network requires.
+func SortL1Validators(vs []*ConvertSubnetToL1Validator) {
+	sort.Slice(vs, func(i, j int) bool { return bytes.Compare(vs[i].NodeID, vs[j].NodeID) < 0 })
+}
+
+func (tx *ConvertSubnetToL1Tx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case len(tx.Validators) == 0:
+		return ErrNoL1Validators
+	case len(tx.Address) > MaxManagerAddressLen:
+		return fmt.Errorf("%w: %d bytes", ErrManagerAddressLen, len(tx.Address))
+	}
+	for i, v := range tx.Validators {
+		if i > 0 && bytes.Compare(tx.Validators[i-1].NodeID, v.NodeID) >= 0 {
+			return ErrL1ValidatorsOrder
+		}
+		if v.Weight == 0 || len(v.NodeID) != len(ids.ShortEmpty) {
+			return fmt.Errorf("%w: node ID %x, weight %d", ErrInvalidL1Validator, v.NodeID, v.Weight)
+		}
+	}
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	return tx.SubnetAuth.Verify()
+}
+
+func (*ConvertSubnetToL1Tx) SemanticVerify(*platformvm.VM, platformvm.MutableState, *platformvm.Tx) error {
+	return ErrNotExecutable
+}
+
+// L1ValidationID returns the validation ID of the initial validator at
+// [index] (in node ID order) of the L1 converted from [subnetID].
+func L1ValidationID(subnetID ids.ID, index uint32) ids.ID {
+	p := wrappers.Packer{Bytes: make([]byte, len(ids.Empty)+wrappers.IntLen)}
+	p.PackFixedBytes(subnetID[:])
+	p.PackInt(index)
+	return hashing.ComputeHash256Array(p.Bytes)
+}
+
+// RegisterL1ValidatorTx adds a validator to an L1, as requested by its
+// validator manager in the signed Warp message [Message].
+type RegisterL1ValidatorTx struct {
+	platformvm.BaseTx `serialize:"true"`
+
+	// Balance is the nAVAX prepaid for the continuous validator fee.
+	Balance           uint64   `serialize:"true" json:"balance"`
+	ProofOfPossession [96]byte `serialize:"true" json:"proofOfPossession"`
+	Message           []byte   `serialize:"true" json:"message"`
+}
+
+func (tx *RegisterL1ValidatorTx) SyntacticVerify(ctx *snow.Context) error {
+	if len(tx.Message) == 0 {
+		return ErrEmptyWarpMessage
+	}
+	return tx.BaseTx.SyntacticVerify(ctx)
+}
+
+func (*RegisterL1ValidatorTx) SemanticVerify(*platformvm.VM, platformvm.MutableState, *platformvm.Tx) error {
+	return ErrNotExecutable
+}
+
+// SetL1ValidatorWeightTx sets the weight of an L1 validator (removing it
+// with a zero weight), as requested by its validator manager in the signed
+// Warp message [Message].
+type SetL1ValidatorWeightTx struct {
+	platformvm.BaseTx `serialize:"true"`
+
+	Message []byte `serialize:"true" json:"message"`
+}
+
+func (tx *SetL1ValidatorWeightTx) SyntacticVerify(ctx *snow.Context) error {
+	if len(tx.Message) == 0 {
+		return ErrEmptyWarpMessage
+	}
+	return tx.BaseTx.SyntacticVerify(ctx)
+}
+
+func (*SetL1ValidatorWeightTx) SemanticVerify(*platformvm.VM, platformvm.MutableState, *platformvm.Tx) error {
+	return ErrNotExecutable
+}
+
+// IncreaseL1ValidatorBalanceTx adds [Balance] to the continuous-fee balance
+// of the L1 validator [ValidationID], reactivating it if it ran out.
+type IncreaseL1ValidatorBalanceTx struct {
+	platformvm.BaseTx `serialize:"true"`
+
+	ValidationID ids.ID `serialize:"true" json:"validationID"`
+	Balance      uint64 `serialize:"true" json:"balance"`
+}
+
+func (tx *IncreaseL1ValidatorBalanceTx) SyntacticVerify(ctx *snow.Context) error {
+	if tx.Balance == 0 {
+		return ErrZeroBalance
+	}
+	return tx.BaseTx.SyntacticVerify(ctx)
+}
+
+func (*IncreaseL1ValidatorBalanceTx) SemanticVerify(*platformvm.VM, platformvm.MutableState, *platformvm.Tx) error {
+	return ErrNotExecutable
+}
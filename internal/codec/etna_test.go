@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func testConvertTx(vs ...*ConvertSubnetToL1Validator) *ConvertSubnetToL1Tx {
+	return &ConvertSubnetToL1Tx{
+		BaseTx: platformvm.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    5,
+			BlockchainID: ids.Empty,
+		}},
+		Subnet:     ids.GenerateTestID(),
+		ChainID:    ids.GenerateTestID(),
+		Address:    []byte{0x02, 0x00},
+		Validators: vs,
+		SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0}},
+	}
+}
+
+func testL1Validator(nodeID ids.ShortID, weight uint64) *ConvertSubnetToL1Validator {
+	return &ConvertSubnetToL1Validator{
+		NodeID:                nodeID[:],
+		Weight:                weight,
+		Balance:               1_000_000_000,
+		RemainingBalanceOwner: PChainOwner{Threshold: 1, Addresses: []ids.ShortID{{1}}},
+		DeactivationOwner:     PChainOwner{Threshold: 1, Addresses: []ids.ShortID{{1}}},
+	}
+}
+
+// initialize sets the bytes of [utx], as signing does.
+func initialize(t *testing.T, utx platformvm.UnsignedTx) {
+	b, err := PCodecManager.Marshal(platformvm.CodecVersion, &utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utx.Initialize(b, b)
+}
+
+func TestConvertSubnetToL1TxCodec(t *testing.T) {
+	t.Parallel()
+
+	utx := testConvertTx(testL1Validator(ids.ShortID{1}, 100))
+	tx := &platformvm.Tx{UnsignedTx: utx}
+	b, err := PCodecManager.Marshal(platformvm.CodecVersion, &tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// codec version, then the type ID
+	if v := binary.BigEndian.Uint16(b); v != platformvm.CodecVersion {
+		t.Fatalf("unexpected codec version %d", v)
+	}
+	if id := binary.BigEndian.Uint32(b[2:]); id != firstEtnaTypeID {
+		t.Fatalf("unexpected type ID %d", id)
+	}
+
+	var decoded platformvm.UnsignedTx
+	if _, err := PCodecManager.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := decoded.(*ConvertSubnetToL1Tx)
+	if !ok {
+		t.Fatalf("unexpected type %T", decoded)
+	}
+	if got.Subnet != utx.Subnet || got.ChainID != utx.ChainID || !bytes.Equal(got.Address, utx.Address) {
+		t.Fatalf("unexpected tx %+v", got)
+	}
+	if len(got.Validators) != 1 || got.Validators[0].Weight != 100 || got.Validators[0].Balance != 1_000_000_000 {
+		t.Fatalf("unexpected validators %+v", got.Validators)
+	}
+}
+
+func TestConvertSubnetToL1TxVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := &snow.Context{NetworkID: 5, ChainID: ids.Empty}
+	a, b := testL1Validator(ids.ShortID{1}, 100), testL1Validator(ids.ShortID{2}, 100)
+	tt := []struct {
+		name string
+		tx   *ConvertSubnetToL1Tx
+		err  error
+	}{
+		{name: "valid", tx: testConvertTx(a, b)},
+		{name: "no validators", tx: testConvertTx(), err: ErrNoL1Validators},
+		{name: "unsorted", tx: testConvertTx(b, a), err: ErrL1ValidatorsOrder},
+		{name: "duplicate", tx: testConvertTx(a, a), err: ErrL1ValidatorsOrder},
+		{name: "zero weight", tx: testConvertTx(testL1Validator(ids.ShortID{1}, 0)), err: ErrInvalidL1Validator},
+	}
+	for _, tv := range tt {
+		initialize(t, tv.tx)
+		if err := tv.tx.SyntacticVerify(ctx); !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+	}
+
+	vs := []*ConvertSubnetToL1Validator{b, a}
+	SortL1Validators(vs)
+	tx := testConvertTx(vs...)
+	initialize(t, tx)
+	if err := tx.SyntacticVerify(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIncreaseL1ValidatorBalanceTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := &snow.Context{NetworkID: 5, ChainID: ids.Empty}
+	tt := []struct {
+		balance uint64
+		err     error
+	}{
+		{balance: 1_000_000_000},
+		{balance: 0, err: ErrZeroBalance},
+	}
+	for i, tv := range tt {
+		utx := &IncreaseL1ValidatorBalanceTx{
+			BaseTx:       platformvm.BaseTx{BaseTx: avax.BaseTx{NetworkID: 5, BlockchainID: ids.Empty}},
+			ValidationID: ids.GenerateTestID(),
+			Balance:      tv.balance,
+		}
+		initialize(t, utx)
+		if err := utx.SyntacticVerify(ctx); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		var tx platformvm.UnsignedTx = utx
+		b, err := PCodecManager.Marshal(platformvm.CodecVersion, &tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := binary.BigEndian.Uint32(b[2:]); id != firstEtnaTypeID+3 {
+			t.Fatalf("#%d: unexpected type ID %d", i, id)
+		}
+	}
+}
+
+func TestL1ValidationID(t *testing.T) {
+	t.Parallel()
+
+	subnetID := ids.GenerateTestID()
+	a, b := L1ValidationID(subnetID, 0), L1ValidationID(subnetID, 1)
+	if a == b || a == subnetID {
+		t.Fatalf("unexpected validation IDs %s, %s", a, b)
+	}
+	if L1ValidationID(subnetID, 0) != a {
+		t.Fatal("validation ID not deterministic")
+	}
+}
+
+func TestL1ValidatorTxs(t *testing.T) {
+	t.Parallel()
+
+	ctx := &snow.Context{NetworkID: 5, ChainID: ids.Empty}
+	base := platformvm.BaseTx{BaseTx: avax.BaseTx{NetworkID: 5, BlockchainID: ids.Empty}}
+	tt := []struct {
+		name   string
+		tx     platformvm.UnsignedTx
+		typeID uint32
+		err    error
+	}{
+		{name: "register", tx: &RegisterL1ValidatorTx{BaseTx: base, Balance: 1, Message: []byte{1}}, typeID: firstEtnaTypeID + 1},
+		{name: "register without message", tx: &RegisterL1ValidatorTx{BaseTx: base, Balance: 1}, typeID: firstEtnaTypeID + 1, err: ErrEmptyWarpMessage},
+		{name: "set weight", tx: &SetL1ValidatorWeightTx{BaseTx: base, Message: []byte{1}}, typeID: firstEtnaTypeID + 2},
+		{name: "set weight without message", tx: &SetL1ValidatorWeightTx{BaseTx: base}, typeID: firstEtnaTypeID + 2, err: ErrEmptyWarpMessage},
+	}
+	for _, tv := range tt {
+		initialize(t, tv.tx)
+		if err := tv.tx.SyntacticVerify(ctx); !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		b, err := PCodecManager.Marshal(platformvm.CodecVersion, &tv.tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := binary.BigEndian.Uint32(b[2:]); id != tv.typeID {
+			t.Fatalf("%s: unexpected type ID %d", tv.name, id)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// XCodecVersion is the codec version of X-Chain txs and UTXOs.
+const XCodecVersion = 0
+
+// XCodecManager serializes X-Chain txs and UTXOs. The feature extensions
+// are registered in the order of the X-Chain, so secp256k1fx outputs of
+// initial asset states use the fx index 0.
+var XCodecManager codec.Manager
+
+func init() {
+	var err error
+	_, XCodecManager, err = avm.NewCodecs([]avm.Fx{
+		&secp256k1fx.Fx{},
+		&nftfx.Fx{},
+		&propertyfx.Fx{},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
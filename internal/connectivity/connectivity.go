@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package connectivity builds the matrix of which subnet validators are
+// connected to each other, from the peers each validator reports.
+package connectivity
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// State is whether a validator sees another one.
+type State int
+
+const (
+	// Unknown is the state of a validator whose peers were not queried.
+	Unknown State = iota
+	// Self is the state of a validator with itself.
+	Self
+	Connected
+	Disconnected
+)
+
+// Link is a validator [From] that does not see the validator [To].
+type Link struct {
+	From ids.ShortID
+	To   ids.ShortID
+}
+
+// Matrix is the connectivity between subnet validators.
+type Matrix struct {
+	// Nodes are the validators, sorted by node ID.
+	Nodes []ids.ShortID
+	// peers are the peers of the queried validators.
+	peers map[ids.ShortID]map[ids.ShortID]bool
+}
+
+// New returns the matrix of [nodes], with no validator queried yet.
+func New(nodes []ids.ShortID) *Matrix {
+	sorted := append([]ids.ShortID(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	return &Matrix{Nodes: sorted, peers: map[ids.ShortID]map[ids.ShortID]bool{}}
+}
+
+// SetPeers records the [peers] that [node] reports.
+func (m *Matrix) SetPeers(node ids.ShortID, peers []ids.ShortID) {
+	ps := make(map[ids.ShortID]bool, len(peers))
+	for _, p := range peers {
+		ps[p] = true
+	}
+	m.peers[node] = ps
+}
+
+// Queried returns true if the peers of [node] were recorded.
+func (m *Matrix) Queried(node ids.ShortID) bool {
+	_, ok := m.peers[node]
+	return ok
+}
+
+// State returns whether [from] sees [to].
+func (m *Matrix) State(from, to ids.ShortID) State {
+	if from == to {
+		return Self
+	}
+	ps, ok := m.peers[from]
+	switch {
+	case !ok:
+		return Unknown
+	case ps[to]:
+		return Connected
+	default:
+		return Disconnected
+	}
+}
+
+// Missing returns the validators that do not see another validator, in
+// matrix order.
+func (m *Matrix) Missing() []Link {
+	ls := []Link{}
+	for _, from := range m.Nodes {
+		for _, to := range m.Nodes {
+			if m.State(from, to) == Disconnected {
+				ls = append(ls, Link{From: from, To: to})
+			}
+		}
+	}
+	return ls
+}
+
+// Isolated returns the validators that no queried validator sees, while
+// at least one validator was queried besides them.
+func (m *Matrix) Isolated() []ids.ShortID {
+	isolated := []ids.ShortID{}
+	for _, to := range m.Nodes {
+		seen, queried := false, false
+		for _, from := range m.Nodes {
+			switch m.State(from, to) {
+			case Connected:
+				seen = true
+			case Disconnected:
+				queried = true
+			}
+		}
+		if queried && !seen {
+			isolated = append(isolated, to)
+		}
+	}
+	return isolated
+}
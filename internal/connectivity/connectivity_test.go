@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package connectivity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestMatrix(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := ids.ShortID{1}, ids.ShortID{2}, ids.ShortID{3}
+	tt := []struct {
+		peers       map[ids.ShortID][]ids.ShortID
+		expMissing  []Link
+		expIsolated []ids.ShortID
+	}{
+		{
+			// nothing queried
+			expMissing:  []Link{},
+			expIsolated: []ids.ShortID{},
+		},
+		{
+			peers: map[ids.ShortID][]ids.ShortID{
+				a: {b, c},
+				b: {a, c},
+				c: {a, b, ids.ShortID{4}},
+			},
+			expMissing:  []Link{},
+			expIsolated: []ids.ShortID{},
+		},
+		{
+			// c is cut off from a and b
+			peers: map[ids.ShortID][]ids.ShortID{
+				a: {b},
+				b: {a},
+			},
+			expMissing:  []Link{{From: a, To: c}, {From: b, To: c}},
+			expIsolated: []ids.ShortID{c},
+		},
+		{
+			// b does not see a, which sees it; c was not queried
+			peers: map[ids.ShortID][]ids.ShortID{
+				a: {b, c},
+				b: {c},
+			},
+			expMissing:  []Link{{From: b, To: a}},
+			expIsolated: []ids.ShortID{a},
+		},
+	}
+	for i, tv := range tt {
+		m := New([]ids.ShortID{c, a, b})
+		if !reflect.DeepEqual(m.Nodes, []ids.ShortID{a, b, c}) {
+			t.Fatalf("#%d: unexpected order %v", i, m.Nodes)
+		}
+		for nodeID, peers := range tv.peers {
+			m.SetPeers(nodeID, peers)
+		}
+		if missing := m.Missing(); !reflect.DeepEqual(missing, tv.expMissing) {
+			t.Fatalf("#%d: expected missing %v, got %v", i, tv.expMissing, missing)
+		}
+		if isolated := m.Isolated(); !reflect.DeepEqual(isolated, tv.expIsolated) {
+			t.Fatalf("#%d: expected isolated %v, got %v", i, tv.expIsolated, isolated)
+		}
+	}
+
+	m := New([]ids.ShortID{a, b})
+	m.SetPeers(a, nil)
+	for _, tv := range []struct {
+		from, to ids.ShortID
+		exp      State
+	}{
+		{from: a, to: a, exp: Self},
+		{from: a, to: b, exp: Disconnected},
+		{from: b, to: a, exp: Unknown},
+	} {
+		if s := m.State(tv.from, tv.to); s != tv.exp {
+			t.Fatalf("expected state %d from %s to %s, got %d", tv.exp, tv.from, tv.to, s)
+		}
+	}
+	m.SetPeers(b, []ids.ShortID{a})
+	if s := m.State(b, a); s != Connected {
+		t.Fatalf("expected connected, got %d", s)
+	}
+}
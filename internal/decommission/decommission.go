@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package decommission records what remains of a retired subnet.
+package decommission
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/names"
+)
+
+// Permanent lists the on-chain resources that no tx can delete.
+var Permanent = []string{
+	"the subnet itself (CreateSubnetTx) and its control keys",
+	"every blockchain of the subnet (CreateChainTx), including its genesis",
+}
+
+// ValidatorsPermanent is also permanent on networks without the Banff
+// upgrade.
+const ValidatorsPermanent = "subnet validators until their end time: removing one early needs RemoveSubnetValidatorTx, which the network has not activated (Banff)"
+
+// NodeSteps lists what the node operators should do once the subnet is
+// retired.
+var NodeSteps = []string{
+	"remove the subnet ID from --whitelisted-subnets and restart",
+	"delete the chain configs (--chain-config-dir) and chain aliases of its blockchains",
+	"delete the VM binary from --plugin-dir if no other subnet uses it",
+}
+
+// Validator is a subnet validator at decommission time.
+type Validator struct {
+	NodeID  string    `json:"nodeID"`
+	Weight  uint64    `json:"weight,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Pending bool      `json:"pending,omitempty"`
+	// Removed is true if the validator was removed before its end time.
+	Removed bool `json:"removed,omitempty"`
+}
+
+// Blockchain is a blockchain of the subnet.
+type Blockchain struct {
+	ID   ids.ID `json:"id"`
+	Name string `json:"name"`
+	VMID ids.ID `json:"vmID"`
+}
+
+// Report is the archive of a decommissioned subnet.
+type Report struct {
+	NetworkID      uint32       `json:"networkID"`
+	SubnetID       ids.ID       `json:"subnetID"`
+	Decommissioned time.Time    `json:"decommissioned"`
+	Validators     []Validator  `json:"validators"`
+	Blockchains    []Blockchain `json:"blockchains"`
+	// Names are the local names of the subnet and its blockchains.
+	Names     []names.Entry `json:"names,omitempty"`
+	Permanent []string      `json:"permanent"`
+	NodeSteps []string      `json:"nodeSteps"`
+}
+
+// New returns the report of [subnetID] decommissioned at [now].
+func New(networkID uint32, subnetID ids.ID, now time.Time) *Report {
+	return &Report{
+		NetworkID:      networkID,
+		SubnetID:       subnetID,
+		Decommissioned: now.UTC(),
+		Validators:     []Validator{},
+		Blockchains:    []Blockchain{},
+		Permanent:      append([]string(nil), Permanent...),
+		NodeSteps:      NodeSteps,
+	}
+}
+
+// LastEnd returns when the last validator not removed stops validating,
+// or the zero time if there are none.
+func (r *Report) LastEnd() time.Time {
+	last := time.Time{}
+	for _, v := range r.Validators {
+		if !v.Removed && v.End.After(last) {
+			last = v.End
+		}
+	}
+	return last
+}
+
+// ArchiveName returns the file name of the report.
+func (r *Report) ArchiveName() string {
+	return fmt.Sprintf("decommissioned-%s.json", r.SubnetID)
+}
+
+// Save writes the report to [dir] and returns its path.
+func (r *Report) Save(dir string) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, r.ArchiveName())
+	return p, os.WriteFile(p, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package decommission
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := New(5, ids.GenerateTestID(), now)
+	if !r.LastEnd().IsZero() {
+		t.Fatalf("unexpected last end %v", r.LastEnd())
+	}
+	r.Validators = append(r.Validators,
+		Validator{NodeID: "NodeID-a", End: now.Add(time.Hour)},
+		Validator{NodeID: "NodeID-b", End: now.Add(48 * time.Hour), Pending: true},
+		Validator{NodeID: "NodeID-c", End: now.Add(2 * time.Hour)},
+	)
+	if end := r.LastEnd(); !end.Equal(now.Add(48 * time.Hour)) {
+		t.Fatalf("unexpected last end %v", end)
+	}
+	// removed validators no longer validate
+	r.Validators[1].Removed = true
+	if end := r.LastEnd(); !end.Equal(now.Add(2 * time.Hour)) {
+		t.Fatalf("unexpected last end %v", end)
+	}
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	p, err := r.Save(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(p) != "decommissioned-"+r.SubnetID.String()+".json" {
+		t.Fatalf("unexpected path %q", p)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Report)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.SubnetID != r.SubnetID || len(loaded.Validators) != 3 || len(loaded.Permanent) != len(Permanent) {
+		t.Fatalf("unexpected report %+v", loaded)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package devnet generates containerized local networks for subnet
+// development.
+package devnet
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+)
+
+// MaxValidators is the number of genesis stakers of the "local" network.
+const MaxValidators = 5
+
+const (
+	DefaultName   = "devnet"
+	DefaultImage  = "avaplatform/avalanchego"
+	DefaultSubnet = "10.10.0.0/24"
+
+	httpPort    = 9650
+	stakingPort = 9651
+
+	pluginDir      = "/avalanchego/build/plugins"
+	stakersDir     = "/stakers"
+	chainConfigDir = "/chain-configs"
+	genesisDir     = "/genesis"
+	dataDir        = "/data"
+)
+
+var (
+	ErrInvalidValidators = fmt.Errorf("validators must be between 1 and %d", MaxValidators)
+	ErrInvalidSubnet     = errors.New("invalid docker network subnet")
+)
+
+// stakers are the staking keys of the "local" network genesis validators.
+// ref. "avalanchego/staking/local".
+//
+//go:embed stakers
+var stakers embed.FS
+
+// Staker is a "local" network genesis validator.
+type Staker struct {
+	Name   string
+	NodeID ids.ShortID
+	Cert   []byte
+	Key    []byte
+}
+
+// Stakers returns the first [n] "local" network genesis validators.
+func Stakers(n int) ([]Staker, error) {
+	if n < 1 || n > MaxValidators {
+		return nil, ErrInvalidValidators
+	}
+	ret := make([]Staker, n)
+	for i := range ret {
+		name := fmt.Sprintf("staker%d", i+1)
+		cert, err := stakers.ReadFile("stakers/" + name + ".crt")
+		if err != nil {
+			return nil, err
+		}
+		key, err := stakers.ReadFile("stakers/" + name + ".key")
+		if err != nil {
+			return nil, err
+		}
+		nodeID, err := node.IDFromCert(cert)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = Staker{Name: name, NodeID: nodeID, Cert: cert, Key: key}
+	}
+	return ret, nil
+}
+
+// Config describes the devnet to generate.
+type Config struct {
+	// Name prefixes the docker volumes holding the node data.
+	Name       string
+	Validators int
+	Image      string
+	Version    string
+	// Subnet is the docker network subnet the nodes get static IPs in.
+	Subnet string
+
+	// VMID and VMBinary (host path) install the VM plugin on every node.
+	VMID     ids.ID
+	VMBinary string
+
+	TrackSubnets []string
+}
+
+type compose struct {
+	Version  string                    `yaml:"version"`
+	Services yaml.MapSlice             `yaml:"services"`
+	Volumes  yaml.MapSlice             `yaml:"volumes"`
+	Networks map[string]composeNetwork `yaml:"networks"`
+}
+
+type composeService struct {
+	Image    string                           `yaml:"image"`
+	Command  []string                         `yaml:"command"`
+	Ports    []string                         `yaml:"ports"`
+	Volumes  []string                         `yaml:"volumes"`
+	Networks map[string]composeServiceNetwork `yaml:"networks"`
+}
+
+type composeServiceNetwork struct {
+	IPv4Address string `yaml:"ipv4_address"`
+}
+
+type composeNetwork struct {
+	IPAM struct {
+		Config []map[string]string `yaml:"config"`
+	} `yaml:"ipam"`
+}
+
+// NodeIP returns the static IP of the [i]-th (0-indexed) node.
+func NodeIP(subnet string, i int) (net.IP, error) {
+	ip, _, err := net.ParseCIDR(subnet)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSubnet, subnet)
+	}
+	ip = ip.To4()
+	ret := make(net.IP, len(ip))
+	copy(ret, ip)
+	ret[3] += byte(11 + i)
+	return ret, nil
+}
+
+// VolumeName returns the docker volume holding the [i]-th node's data.
+func VolumeName(name string, i int) string {
+	return fmt.Sprintf("%s-node%d-data", name, i+1)
+}
+
+// HostHTTPPort returns the host port the [i]-th node's API is published on.
+func HostHTTPPort(i int) int { return httpPort + 2*i }
+
+// Compose returns the docker-compose.yaml for [cfg]. Relative volume paths
+// ("./stakers", "./chain-configs", "./genesis") are relative to the
+// directory the file is written to.
+func Compose(cfg Config) ([]byte, error) {
+	ss, err := Stakers(cfg.Validators)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.Image == "" {
+		cfg.Image = DefaultImage
+	}
+	if cfg.Version == "" {
+		cfg.Version = node.DefaultVersion
+	}
+	if cfg.Subnet == "" {
+		cfg.Subnet = DefaultSubnet
+	}
+	trackKey, err := node.TrackSubnetsKey(cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+	beaconIP, err := NodeIP(cfg.Subnet, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	c := compose{
+		Version:  "3.8",
+		Networks: map[string]composeNetwork{},
+	}
+	n := composeNetwork{}
+	n.IPAM.Config = []map[string]string{{"subnet": cfg.Subnet}}
+	c.Networks["devnet"] = n
+
+	for i, s := range ss {
+		name := fmt.Sprintf("node%d", i+1)
+		ip, err := NodeIP(cfg.Subnet, i)
+		if err != nil {
+			return nil, err
+		}
+		cmd := []string{
+			"/avalanchego/build/avalanchego",
+			"--network-id=local",
+			fmt.Sprintf("--public-ip=%s", ip),
+			"--http-host=0.0.0.0",
+			fmt.Sprintf("--staking-tls-cert-file=%s/%s.crt", stakersDir, s.Name),
+			fmt.Sprintf("--staking-tls-key-file=%s/%s.key", stakersDir, s.Name),
+			fmt.Sprintf("--db-dir=%s/db", dataDir),
+			fmt.Sprintf("--log-dir=%s/logs", dataDir),
+			fmt.Sprintf("--chain-config-dir=%s", chainConfigDir),
+		}
+		if i > 0 {
+			cmd = append(cmd,
+				fmt.Sprintf("--bootstrap-ips=%s:%d", beaconIP, stakingPort),
+				fmt.Sprintf("--bootstrap-ids=%s", ss[0].NodeID.PrefixedString(constants.NodeIDPrefix)),
+			)
+		}
+		if len(cfg.TrackSubnets) > 0 {
+			cmd = append(cmd, fmt.Sprintf("--%s=%s", trackKey, strings.Join(cfg.TrackSubnets, ",")))
+		}
+		svc := composeService{
+			Image:   fmt.Sprintf("%s:%s", cfg.Image, cfg.Version),
+			Command: cmd,
+			Ports:   []string{fmt.Sprintf("%d:%d", HostHTTPPort(i), httpPort)},
+			Volumes: []string{
+				fmt.Sprintf("./stakers:%s:ro", stakersDir),
+				fmt.Sprintf("./chain-configs:%s:ro", chainConfigDir),
+				fmt.Sprintf("./genesis:%s:ro", genesisDir),
+				fmt.Sprintf("%s-data:%s", name, dataDir),
+			},
+			Networks: map[string]composeServiceNetwork{"devnet": {IPv4Address: ip.String()}},
+		}
+		if cfg.VMBinary != "" {
+			svc.Volumes = append(svc.Volumes, fmt.Sprintf("%s:%s/%s:ro", cfg.VMBinary, pluginDir, cfg.VMID))
+		}
+		c.Services = append(c.Services, yaml.MapItem{Key: name, Value: svc})
+		c.Volumes = append(c.Volumes, yaml.MapItem{Key: name + "-data", Value: map[string]string{"name": VolumeName(cfg.Name, i)}})
+	}
+	return yaml.Marshal(c)
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package devnet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestStakers(t *testing.T) {
+	t.Parallel()
+
+	ss, err := Stakers(MaxValidators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := ss[0].NodeID.PrefixedString(constants.NodeIDPrefix); id != "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg" {
+		t.Fatalf("unexpected staker1 node ID %s", id)
+	}
+	for _, n := range []int{0, MaxValidators + 1} {
+		if _, err := Stakers(n); !errors.Is(err, ErrInvalidValidators) {
+			t.Fatalf("%d: expected %v, got %v", n, ErrInvalidValidators, err)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	b, err := Compose(Config{
+		Validators:   3,
+		VMID:         ids.ID{'s', 'u', 'b', 'n', 'e', 't', '-', 'e', 'v', 'm'},
+		VMBinary:     "/tmp/subnet-evm",
+		TrackSubnets: []string{"24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	for _, exp := range []string{
+		"node3:",
+		"ipv4_address: 10.10.0.13",
+		"9654:9650",
+		"--bootstrap-ips=10.10.0.11:9651",
+		"--bootstrap-ids=NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg",
+		"--whitelisted-subnets=24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1",
+		"/tmp/subnet-evm:/avalanchego/build/plugins/",
+	} {
+		if !strings.Contains(s, exp) {
+			t.Fatalf("compose file missing %q:\n%s", exp, s)
+		}
+	}
+	if strings.Contains(s, "node4:") {
+		t.Fatal("unexpected node4")
+	}
+}
+
+func TestNodeIP(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NodeIP("not-a-cidr", 0); !errors.Is(err, ErrInvalidSubnet) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSubnet, err)
+	}
+}
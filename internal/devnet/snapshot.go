@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package devnet
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ComposeFile is the compose file name within a devnet directory.
+	ComposeFile = "docker-compose.yaml"
+
+	filesArchive = "devnet.tar.gz"
+	// helperImage runs tar against the node data volumes.
+	helperImage = "busybox"
+)
+
+var (
+	ErrSnapshotExists   = errors.New("snapshot already exists")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrNoVolumes        = errors.New("no node data volumes in compose file")
+	ErrUnsafePath       = errors.New("unsafe path in archive")
+)
+
+// Volumes returns the named docker volumes declared in a compose file.
+func Volumes(compose []byte) ([]string, error) {
+	var c struct {
+		Volumes map[string]struct {
+			Name string `yaml:"name"`
+		} `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(compose, &c); err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0, len(c.Volumes))
+	for _, v := range c.Volumes {
+		if v.Name != "" {
+			ret = append(ret, v.Name)
+		}
+	}
+	if len(ret) == 0 {
+		return nil, ErrNoVolumes
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// Archive writes the regular files under [dir] as a gzipped tarball.
+func Archive(dir string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name: filepath.ToSlash(rel),
+			Mode: int64(fi.Mode().Perm()),
+			Size: int64(len(b)),
+		}); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Extract unpacks a tarball written by [Archive] into [dir].
+func Extract(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			return fmt.Errorf("%w: %q", ErrUnsafePath, hdr.Name)
+		}
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, b, os.FileMode(hdr.Mode)); err != nil {
+			return err
+		}
+	}
+}
+
+func docker(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+	c := exec.CommandContext(ctx, "docker", args...)
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%w: docker %s: %s", err, strings.Join(args, " "), bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// SaveSnapshot stops the devnet in [dir], captures its files and node data
+// volumes into [snapshotDir] and starts it again.
+func SaveSnapshot(ctx context.Context, dir string, snapshotDir string) error {
+	if _, err := os.Stat(snapshotDir); err == nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotExists, snapshotDir)
+	}
+	compose := filepath.Join(dir, ComposeFile)
+	b, err := ioutil.ReadFile(compose)
+	if err != nil {
+		return err
+	}
+	volumes, err := Volumes(b)
+	if err != nil {
+		return err
+	}
+	absSnapshot, err := filepath.Abs(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(absSnapshot, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(absSnapshot, filesArchive))
+	if err != nil {
+		return err
+	}
+	err = Archive(dir, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := docker(ctx, "compose", "-f", compose, "stop"); err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if err := docker(ctx, "run", "--rm",
+			"-v", v+":/data:ro",
+			"-v", absSnapshot+":/snapshot",
+			helperImage, "tar", "czf", "/snapshot/"+v+".tar.gz", "-C", "/data", ".",
+		); err != nil {
+			return err
+		}
+	}
+	return docker(ctx, "compose", "-f", compose, "start")
+}
+
+// LoadSnapshot restores the devnet files into [dir] and the node data
+// volumes from [snapshotDir], then (re)starts the devnet.
+func LoadSnapshot(ctx context.Context, snapshotDir string, dir string) error {
+	absSnapshot, err := filepath.Abs(snapshotDir)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(absSnapshot, filesArchive))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, snapshotDir)
+	}
+	if err != nil {
+		return err
+	}
+	compose := filepath.Join(dir, ComposeFile)
+	if _, err := os.Stat(compose); err == nil {
+		if err := docker(ctx, "compose", "-f", compose, "down"); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	err = Extract(f, dir)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(compose)
+	if err != nil {
+		return err
+	}
+	volumes, err := Volumes(b)
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if err := docker(ctx, "volume", "create", v); err != nil {
+			return err
+		}
+		if err := docker(ctx, "run", "--rm",
+			"-v", v+":/data",
+			"-v", absSnapshot+":/snapshot:ro",
+			helperImage, "sh", "-c", "rm -rf /data/* && tar xzf /snapshot/"+v+".tar.gz -C /data",
+		); err != nil {
+			return err
+		}
+	}
+	return docker(ctx, "compose", "-f", compose, "up", "-d")
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package devnet
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVolumes(t *testing.T) {
+	t.Parallel()
+
+	b, err := Compose(Config{Name: "mydev", Validators: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := Volumes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 2 || vs[0] != "mydev-node1-data" || vs[1] != "mydev-node2-data" {
+		t.Fatalf("unexpected volumes %v", vs)
+	}
+	if _, err := Volumes([]byte("services: {}\n")); !errors.Is(err, ErrNoVolumes) {
+		t.Fatalf("expected %v, got %v", ErrNoVolumes, err)
+	}
+}
+
+func TestArchiveExtract(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "stakers"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "stakers", "staker1.crt"), []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := Archive(src, buf); err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	if err := Extract(buf, dst); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dst, "stakers", "staker1.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "cert" {
+		t.Fatalf("unexpected content %q", b)
+	}
+}
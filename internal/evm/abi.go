@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"math/big"
+)
+
+const wordLen = 32
+
+// Selector returns the 4-byte function selector of a canonical signature
+// (e.g., "setAdmin(address)").
+func Selector(sig string) []byte {
+	return Keccak256([]byte(sig))[:4]
+}
+
+// WordUint encodes [v] as a 32-byte ABI word.
+func WordUint(v *big.Int) []byte {
+	w := make([]byte, wordLen)
+	v.FillBytes(w)
+	return w
+}
+
+// WordAddress encodes [a] as a 32-byte ABI word.
+func WordAddress(a Address) []byte {
+	w := make([]byte, wordLen)
+	copy(w[wordLen-AddressLen:], a[:])
+	return w
+}
+
+// WordBool encodes [b] as a 32-byte ABI word.
+func WordBool(b bool) []byte {
+	if b {
+		return WordUint(big.NewInt(1))
+	}
+	return WordUint(big.NewInt(0))
+}
+
+// Call concatenates a selector with static ABI words.
+func Call(sig string, words ...[]byte) []byte {
+	out := Selector(sig)
+	for _, w := range words {
+		out = append(out, w...)
+	}
+	return out
+}
+
+// DecodeUint decodes the [idx]-th 32-byte word of [ret].
+func DecodeUint(ret []byte, idx int) *big.Int {
+	if len(ret) < (idx+1)*wordLen {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(ret[idx*wordLen : (idx+1)*wordLen])
+}
+
+// DecodeAddress decodes the [idx]-th 32-byte word of [ret] as an address.
+func DecodeAddress(ret []byte, idx int) Address {
+	var a Address
+	if len(ret) < (idx+1)*wordLen {
+		return a
+	}
+	copy(a[:], ret[(idx+1)*wordLen-AddressLen:(idx+1)*wordLen])
+	return a
+}
+
+// Arg is an ABI-encoded argument of a call with dynamic types (e.g.,
+// "bytes" or "T[]"), which [Call] cannot encode.
+type Arg struct {
+	enc     []byte
+	dynamic bool
+}
+
+// Static returns the static [words] (e.g., of [WordUint]) as an argument.
+func Static(words ...[]byte) Arg {
+	a := Arg{}
+	for _, w := range words {
+		a.enc = append(a.enc, w...)
+	}
+	return a
+}
+
+// Bytes returns a "bytes" argument.
+func Bytes(b []byte) Arg {
+	enc := WordUint(big.NewInt(int64(len(b))))
+	enc = append(enc, b...)
+	if r := len(b) % wordLen; r != 0 {
+		enc = append(enc, make([]byte, wordLen-r)...)
+	}
+	return Arg{enc: enc, dynamic: true}
+}
+
+// Tuple returns a struct argument of [args].
+func Tuple(args ...Arg) Arg {
+	a := Arg{enc: Encode(args...)}
+	for _, arg := range args {
+		a.dynamic = a.dynamic || arg.dynamic
+	}
+	return a
+}
+
+// Array returns a dynamic-length array argument ("T[]") of [args].
+func Array(args ...Arg) Arg {
+	enc := WordUint(big.NewInt(int64(len(args))))
+	return Arg{enc: append(enc, Encode(args...)...), dynamic: true}
+}
+
+// Encode encodes [args] as the arguments of a call: static arguments in
+// place, dynamic ones at the offset in their place.
+func Encode(args ...Arg) []byte {
+	headLen := 0
+	for _, a := range args {
+		if a.dynamic {
+			headLen += wordLen
+		} else {
+			headLen += len(a.enc)
+		}
+	}
+	head, tail := []byte{}, []byte{}
+	for _, a := range args {
+		if !a.dynamic {
+			head = append(head, a.enc...)
+			continue
+		}
+		head = append(head, WordUint(big.NewInt(int64(headLen+len(tail))))...)
+		tail = append(tail, a.enc...)
+	}
+	return append(head, tail...)
+}
+
+// CallArgs concatenates a selector with the encoded [args].
+func CallArgs(sig string, args ...Arg) []byte {
+	return append(Selector(sig), Encode(args...)...)
+}
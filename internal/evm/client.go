@@ -0,0 +1,303 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRPC       = errors.New("evm rpc error")
+	ErrTxFailed  = errors.New("evm tx reverted")
+	ErrNoReceipt = errors.New("evm tx receipt not found")
+)
+
+// Client is a minimal EVM JSON-RPC client
+// (e.g., "http://localhost:9650/ext/bc/<blockchainID>/rpc").
+type Client struct {
+	uri string
+	cli *http.Client
+	id  uint64
+}
+
+func NewClient(uri string) *Client {
+	return &Client{uri: uri, cli: http.DefaultClient}
+}
+
+func (c *Client) URI() string { return c.uri }
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *Client) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	b, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.uri, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s returned status code %d", ErrRPC, method, resp.StatusCode)
+	}
+	var rr rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return err
+	}
+	if rr.Error != nil {
+		return fmt.Errorf("%w: %s: %s (%d)", ErrRPC, method, rr.Error.Message, rr.Error.Code)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rr.Result, result)
+}
+
+func (c *Client) callQuantity(ctx context.Context, method string, params ...interface{}) (*big.Int, error) {
+	var s string
+	if err := c.call(ctx, method, &s, params...); err != nil {
+		return nil, err
+	}
+	return parseQuantity(s)
+}
+
+func parseQuantity(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid quantity %q", ErrRPC, s)
+	}
+	return v, nil
+}
+
+func encodeQuantity(v *big.Int) string {
+	return "0x" + v.Text(16)
+}
+
+func encodeBytes(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.callQuantity(ctx, "eth_chainId")
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	v, err := c.callQuantity(ctx, "eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+	return v.Uint64(), nil
+}
+
+func (c *Client) Balance(ctx context.Context, a Address) (*big.Int, error) {
+	return c.callQuantity(ctx, "eth_getBalance", a.Hex(), "latest")
+}
+
+func (c *Client) Nonce(ctx context.Context, a Address) (uint64, error) {
+	v, err := c.callQuantity(ctx, "eth_getTransactionCount", a.Hex(), "pending")
+	if err != nil {
+		return 0, err
+	}
+	return v.Uint64(), nil
+}
+
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	return c.callQuantity(ctx, "eth_gasPrice")
+}
+
+func (c *Client) Code(ctx context.Context, a Address) ([]byte, error) {
+	var s string
+	if err := c.call(ctx, "eth_getCode", &s, a.Hex(), "latest"); err != nil {
+		return nil, err
+	}
+	return decodeBytes(s)
+}
+
+// CallContract executes a read-only call against [to].
+func (c *Client) CallContract(ctx context.Context, from Address, to Address, data []byte) ([]byte, error) {
+	var s string
+	if err := c.call(ctx, "eth_call", &s, map[string]string{
+		"from": from.Hex(),
+		"to":   to.Hex(),
+		"data": encodeBytes(data),
+	}, "latest"); err != nil {
+		return nil, err
+	}
+	return decodeBytes(s)
+}
+
+// EstimateGas estimates the gas of a call (nil [to] for contract creation),
+// with the predicates of [accessList].
+func (c *Client) EstimateGas(ctx context.Context, from Address, to *Address, value *big.Int, data []byte, accessList []AccessTuple) (uint64, error) {
+	msg := map[string]interface{}{
+		"from": from.Hex(),
+		"data": encodeBytes(data),
+	}
+	if to != nil {
+		msg["to"] = to.Hex()
+	}
+	if value != nil {
+		msg["value"] = encodeQuantity(value)
+	}
+	if len(accessList) > 0 {
+		list := make([]map[string]interface{}, 0, len(accessList))
+		for _, t := range accessList {
+			keys := make([]string, 0, len(t.StorageKeys))
+			for i := range t.StorageKeys {
+				keys = append(keys, encodeBytes(t.StorageKeys[i][:]))
+			}
+			list = append(list, map[string]interface{}{"address": t.Address.Hex(), "storageKeys": keys})
+		}
+		msg["accessList"] = list
+	}
+	v, err := c.callQuantity(ctx, "eth_estimateGas", msg)
+	if err != nil {
+		return 0, err
+	}
+	return v.Uint64(), nil
+}
+
+func (c *Client) SendRawTransaction(ctx context.Context, raw []byte) ([]byte, error) {
+	var s string
+	if err := c.call(ctx, "eth_sendRawTransaction", &s, encodeBytes(raw)); err != nil {
+		return nil, err
+	}
+	return decodeBytes(s)
+}
+
+// Receipt is the subset of a tx receipt the CLI uses.
+type Receipt struct {
+	Status          string  `json:"status"`
+	ContractAddress *string `json:"contractAddress"`
+	BlockNumber     string  `json:"blockNumber"`
+	GasUsed         string  `json:"gasUsed"`
+}
+
+// Succeeded returns true if the tx was executed without reverting.
+func (r *Receipt) Succeeded() bool { return r.Status == "0x1" }
+
+func (c *Client) Receipt(ctx context.Context, txHash []byte) (*Receipt, error) {
+	var r *Receipt
+	if err := c.call(ctx, "eth_getTransactionReceipt", &r, encodeBytes(txHash)); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ErrNoReceipt
+	}
+	return r, nil
+}
+
+// WaitReceipt polls until [txHash] is included and returns its receipt.
+func (c *Client) WaitReceipt(ctx context.Context, txHash []byte, interval time.Duration) (*Receipt, error) {
+	for {
+		r, err := c.Receipt(ctx, txHash)
+		if err == nil {
+			if !r.Succeeded() {
+				return r, fmt.Errorf("%w: %s", ErrTxFailed, encodeBytes(txHash))
+			}
+			return r, nil
+		}
+		if !errors.Is(err, ErrNoReceipt) {
+			zap.L().Warn("failed to fetch receipt", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Transact fills in nonce, gas price, and gas limit, signs [tx] with [k],
+// issues it, and waits for the receipt.
+func (c *Client) Transact(ctx context.Context, k *crypto.PrivateKeySECP256K1R, tx *Tx, interval time.Duration) ([]byte, *Receipt, error) {
+	from := AddressFromKey(k)
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tx.Nonce, err = c.Nonce(ctx, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tx.GasPrice == nil {
+		tx.GasPrice, err = c.GasPrice(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if tx.Value == nil {
+		tx.Value = new(big.Int)
+	}
+	if tx.Gas == 0 {
+		gas, err := c.EstimateGas(ctx, from, tx.To, tx.Value, tx.Data, tx.AccessList)
+		if err != nil {
+			return nil, nil, err
+		}
+		// leave headroom for state changes between estimation and inclusion
+		tx.Gas = gas + gas/5
+	}
+	raw, hash, err := tx.Sign(chainID, k)
+	if err != nil {
+		return nil, nil, err
+	}
+	zap.L().Info("issuing evm tx",
+		zap.String("from", from.Hex()),
+		zap.Uint64("nonce", tx.Nonce),
+		zap.Uint64("gas", tx.Gas),
+		zap.String("hash", encodeBytes(hash)),
+	)
+	if _, err := c.SendRawTransaction(ctx, raw); err != nil {
+		return hash, nil, err
+	}
+	r, err := c.WaitReceipt(ctx, hash, interval)
+	return hash, r, err
+}
+
+// HashString formats a tx hash.
+func HashString(h []byte) string { return encodeBytes(h) }
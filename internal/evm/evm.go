@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package evm implements a minimal EVM JSON-RPC client and transaction
+// signer for administering subnet-evm chains.
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"golang.org/x/crypto/sha3"
+)
+
+var ErrInvalidAddress = errors.New("invalid EVM address")
+
+const AddressLen = 20
+
+// Address is a 20-byte EVM account address.
+type Address [AddressLen]byte
+
+// Hex returns the EIP-55 checksummed hex representation.
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	h := Keccak256([]byte(lower))
+	out := make([]byte, len(lower))
+	for i := range lower {
+		c := lower[i]
+		nibble := h[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && nibble&0xf >= 8 {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return "0x" + string(out)
+}
+
+func (a Address) String() string { return a.Hex() }
+
+// ParseAddress parses a 0x-prefixed (or bare) hex address.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil || len(b) != AddressLen {
+		return a, fmt.Errorf("%w: %q", ErrInvalidAddress, s)
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Keccak256 returns the legacy Keccak-256 hash used by Ethereum.
+func Keccak256(bs ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range bs {
+		_, _ = h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// AddressFromKey derives the EVM address of [k].
+func AddressFromKey(k *crypto.PrivateKeySECP256K1R) Address {
+	return AddressFromPublicKey(k.PublicKey().(*crypto.PublicKeySECP256K1R))
+}
+
+// AddressFromPublicKey derives the EVM address of [pk], which (unlike the
+// P/X-Chain short ID) hashes the uncompressed key.
+func AddressFromPublicKey(pk *crypto.PublicKeySECP256K1R) Address {
+	pub := pk.ToECDSA()
+	buf := make([]byte, 64)
+	pub.X.FillBytes(buf[:32])
+	pub.Y.FillBytes(buf[32:])
+	var a Address
+	copy(a[:], Keccak256(buf)[12:])
+	return a
+}
+
+// Ether is 10^18 wei, the base denomination of native EVM tokens.
+var Ether = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// FormatEther formats [wei] in whole tokens with up to 6 decimals.
+func FormatEther(wei *big.Int) string {
+	f := new(big.Float).Quo(new(big.Float).SetInt(wei), new(big.Float).SetInt(Ether))
+	return f.Text('f', 6)
+}
+
+var ErrInvalidAmount = errors.New("invalid token amount")
+
+// ParseEther parses an amount of whole tokens with up to 18 decimals (e.g.,
+// "1.5") in wei.
+func ParseEther(s string) (*big.Int, error) {
+	whole, frac := strings.TrimSpace(s), ""
+	if i := strings.IndexByte(whole, '.'); i >= 0 {
+		whole, frac = whole[:i], whole[i+1:]
+	}
+	if whole+frac == "" || len(frac) > 18 || strings.ContainsAny(whole+frac, "+-") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	wei, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", 18-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return wei, nil
+}
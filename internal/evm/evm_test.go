@@ -0,0 +1,218 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+// "ewoq" key used by local networks
+const (
+	rawEwoqPk       = "ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+	ewoqEVMAddrxHex = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+)
+
+func ewoqKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	skBytes, err := formatting.Decode(formatting.CB58, rawEwoqPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpk, err := new(crypto.FactorySECP256K1R).ToPrivateKey(skBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rpk.(*crypto.PrivateKeySECP256K1R)
+}
+
+func TestAddressFromKey(t *testing.T) {
+	t.Parallel()
+
+	if a := AddressFromKey(ewoqKey(t)); a.Hex() != ewoqEVMAddrxHex {
+		t.Fatalf("unexpected address %s, expected %s", a.Hex(), ewoqEVMAddrxHex)
+	}
+	a, err := ParseAddress(ewoqEVMAddrxHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != AddressFromKey(ewoqKey(t)) {
+		t.Fatalf("unexpected parsed address %s", a)
+	}
+}
+
+func TestRLP(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		enc []byte
+		exp string
+	}{
+		{enc: rlpUint(0), exp: "80"},
+		{enc: rlpUint(15), exp: "0f"},
+		{enc: rlpUint(1024), exp: "820400"},
+		{enc: rlpBytes([]byte("dog")), exp: "83646f67"},
+		{enc: rlpList(rlpBytes([]byte("cat")), rlpBytes([]byte("dog"))), exp: "c88363617483646f67"},
+		{enc: rlpList(), exp: "c0"},
+	}
+	for i, tv := range tt {
+		if got := hex.EncodeToString(tv.enc); got != tv.exp {
+			t.Fatalf("#%d: unexpected encoding %s, expected %s", i, got, tv.exp)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	k := ewoqKey(t)
+	to := AddressFromKey(k)
+	tx := &Tx{
+		Nonce:    1,
+		GasPrice: big.NewInt(25_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1),
+	}
+	chainID := big.NewInt(43112)
+	raw, hash, err := tx.Sign(chainID, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, Keccak256(raw)) {
+		t.Fatal("unexpected tx hash")
+	}
+
+	// the signature must recover to the signer
+	sighash := Keccak256(rlpList(append(tx.fields(), rlpBigInt(chainID), rlpUint(0), rlpUint(0))...))
+	sig, err := k.SignHash(sighash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := new(crypto.FactorySECP256K1R).RecoverHashPublicKey(sighash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Address() != k.PublicKey().Address() {
+		t.Fatal("signature does not recover to signer")
+	}
+}
+
+func TestCreateAddress(t *testing.T) {
+	t.Parallel()
+
+	// ref. go-ethereum "crypto.CreateAddress" test vector
+	from, err := ParseAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := CreateAddress(from, 0); a.Hex() != "0x333c3310824b7c685133F2BeDb2CA4b8b4DF633d" {
+		t.Fatalf("unexpected contract address %s", a.Hex())
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	// ref. "f(uint256,uint32[],bytes10,bytes)" of the Solidity ABI spec
+	var b10 [wordLen]byte
+	copy(b10[:], "1234567890")
+	got := CallArgs("f(uint256,uint32[],bytes10,bytes)",
+		Static(WordUint(big.NewInt(0x123))),
+		Array(Static(WordUint(big.NewInt(0x456))), Static(WordUint(big.NewInt(0x789)))),
+		Static(b10[:]),
+		Bytes([]byte("Hello, world!")),
+	)
+	exp := "8be65246" +
+		"0000000000000000000000000000000000000000000000000000000000000123" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"3132333435363738393000000000000000000000000000000000000000000000" +
+		"00000000000000000000000000000000000000000000000000000000000000e0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000456" +
+		"0000000000000000000000000000000000000000000000000000000000000789" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"48656c6c6f2c20776f726c642100000000000000000000000000000000000000"
+	if h := hex.EncodeToString(got); h != exp {
+		t.Fatalf("unexpected encoding\n%s\nexpected\n%s", h, exp)
+	}
+
+	// a static tuple is encoded in place
+	if enc := Encode(Tuple(Static(WordUint(big.NewInt(1))), Static(WordBool(true)))); len(enc) != 2*wordLen {
+		t.Fatalf("unexpected static tuple %x", enc)
+	}
+	// a dynamic tuple is encoded at its offset
+	if enc := Encode(Tuple(Bytes([]byte{1}))); !bytes.Equal(enc[:wordLen], WordUint(big.NewInt(wordLen))) {
+		t.Fatalf("unexpected dynamic tuple %x", enc)
+	}
+}
+
+func TestSignAccessList(t *testing.T) {
+	t.Parallel()
+
+	k := ewoqKey(t)
+	to := AddressFromKey(k)
+	tx := &Tx{
+		Nonce:      1,
+		GasPrice:   big.NewInt(25_000_000_000),
+		Gas:        100_000,
+		To:         &to,
+		Value:      big.NewInt(0),
+		AccessList: []AccessTuple{{Address: to, StorageKeys: [][32]byte{{1}}}},
+	}
+	chainID := big.NewInt(43112)
+	raw, hash, err := tx.Sign(chainID, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0] != accessListTxType || !bytes.Equal(hash, Keccak256(raw)) {
+		t.Fatalf("unexpected tx %x", raw)
+	}
+
+	// the signature must recover to the signer
+	sighash := Keccak256([]byte{accessListTxType}, rlpList(tx.accessListFields(chainID)...))
+	sig, err := k.SignHash(sighash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := new(crypto.FactorySECP256K1R).RecoverHashPublicKey(sighash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Address() != k.PublicKey().Address() {
+		t.Fatal("signature does not recover to signer")
+	}
+}
+
+func TestParseEther(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s   string
+		exp string
+		err error
+	}{
+		{s: "1", exp: "1000000000000000000"},
+		{s: "1.5", exp: "1500000000000000000"},
+		{s: ".000000000000000001", exp: "1"},
+		{s: "0.0000000000000000001", err: ErrInvalidAmount},
+		{s: "-1", err: ErrInvalidAmount},
+		{s: "1e3", err: ErrInvalidAmount},
+		{s: "", err: ErrInvalidAmount},
+	}
+	for _, tv := range tt {
+		wei, err := ParseEther(tv.s)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%q: expected %v, got %v", tv.s, tv.err, err)
+		}
+		if err == nil && wei.String() != tv.exp {
+			t.Fatalf("%q: expected %s, got %s", tv.s, tv.exp, wei)
+		}
+	}
+}
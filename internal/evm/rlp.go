@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"math/big"
+)
+
+// rlpBytes encodes a byte string.
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpUint encodes an unsigned integer as a big-endian byte string with no
+// leading zeros.
+func rlpUint(v uint64) []byte {
+	return rlpBigInt(new(big.Int).SetUint64(v))
+}
+
+func rlpBigInt(v *big.Int) []byte {
+	if v == nil {
+		return rlpBytes(nil)
+	}
+	return rlpBytes(v.Bytes())
+}
+
+// rlpList encodes already-encoded items as a list.
+func rlpList(items ...[]byte) []byte {
+	n := 0
+	for _, it := range items {
+		n += len(it)
+	}
+	out := rlpHeader(0xc0, n)
+	for _, it := range items {
+		out = append(out, it...)
+	}
+	return out
+}
+
+func rlpHeader(offset byte, n int) []byte {
+	if n < 56 {
+		return []byte{offset + byte(n)}
+	}
+	lenBytes := new(big.Int).SetUint64(uint64(n)).Bytes()
+	return append([]byte{offset + 55 + byte(len(lenBytes))}, lenBytes...)
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// accessListTxType is the EIP-2718 type of an EIP-2930 transaction.
+const accessListTxType = 0x01
+
+// Tx is a legacy (pre-EIP-1559) transaction, signed with EIP-155 replay
+// protection. Every EVM chain created by subnet-evm accepts it. With an
+// [AccessList] (e.g., to carry a Warp message predicate), it is signed as
+// an EIP-2930 transaction instead.
+type Tx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	// nil for contract creation
+	To         *Address
+	Value      *big.Int
+	Data       []byte
+	AccessList []AccessTuple
+}
+
+// AccessTuple is an entry of the access list of an EIP-2930 transaction.
+type AccessTuple struct {
+	Address     Address
+	StorageKeys [][32]byte
+}
+
+func (tx *Tx) fields() [][]byte {
+	to := []byte(nil)
+	if tx.To != nil {
+		to = tx.To[:]
+	}
+	return [][]byte{
+		rlpUint(tx.Nonce),
+		rlpBigInt(tx.GasPrice),
+		rlpUint(tx.Gas),
+		rlpBytes(to),
+		rlpBigInt(tx.Value),
+		rlpBytes(tx.Data),
+	}
+}
+
+// accessListFields returns the fields of [tx] as an EIP-2930 transaction
+// of [chainID].
+func (tx *Tx) accessListFields(chainID *big.Int) [][]byte {
+	tuples := make([][]byte, 0, len(tx.AccessList))
+	for _, t := range tx.AccessList {
+		keys := make([][]byte, 0, len(t.StorageKeys))
+		for i := range t.StorageKeys {
+			keys = append(keys, rlpBytes(t.StorageKeys[i][:]))
+		}
+		tuples = append(tuples, rlpList(rlpBytes(t.Address[:]), rlpList(keys...)))
+	}
+	return append([][]byte{rlpBigInt(chainID)}, append(tx.fields(), rlpList(tuples...))...)
+}
+
+// Sign signs [tx] for [chainID] and returns the raw signed bytes (for
+// "eth_sendRawTransaction") and the tx hash.
+func (tx *Tx) Sign(chainID *big.Int, k *crypto.PrivateKeySECP256K1R) (raw []byte, hash []byte, err error) {
+	if len(tx.AccessList) > 0 {
+		return tx.signAccessList(chainID, k)
+	}
+	sighash := Keccak256(rlpList(append(tx.fields(), rlpBigInt(chainID), rlpUint(0), rlpUint(0))...))
+	sig, err := k.SignHash(sighash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, nil, ErrInvalidSignature
+	}
+	// [r || s || recovery id]
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	v := new(big.Int).Mul(chainID, big.NewInt(2))
+	v.Add(v, big.NewInt(35+int64(sig[64])))
+
+	raw = rlpList(append(tx.fields(), rlpBigInt(v), rlpBigInt(r), rlpBigInt(s))...)
+	return raw, Keccak256(raw), nil
+}
+
+func (tx *Tx) signAccessList(chainID *big.Int, k *crypto.PrivateKeySECP256K1R) (raw []byte, hash []byte, err error) {
+	fields := tx.accessListFields(chainID)
+	sighash := Keccak256([]byte{accessListTxType}, rlpList(fields...))
+	sig, err := k.SignHash(sighash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, nil, ErrInvalidSignature
+	}
+	// [r || s || y parity]
+	fields = append(fields, rlpUint(uint64(sig[64])), rlpBytes(trimZeros(sig[:32])), rlpBytes(trimZeros(sig[32:64])))
+	raw = append([]byte{accessListTxType}, rlpList(fields...)...)
+	return raw, Keccak256(raw), nil
+}
+
+func trimZeros(b []byte) []byte {
+	return new(big.Int).SetBytes(b).Bytes()
+}
+
+// CreateAddress returns the address of a contract deployed by [from] at
+// [nonce].
+func CreateAddress(from Address, nonce uint64) Address {
+	var a Address
+	copy(a[:], Keccak256(rlpList(rlpBytes(from[:]), rlpUint(nonce)))[12:])
+	return a
+}
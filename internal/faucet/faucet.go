@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package faucet implements a client for the test network token faucet.
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+var ErrFaucet = errors.New("faucet request failed")
+
+// DefaultURL is the Fuji faucet "sendToken" endpoint.
+const DefaultURL = "https://faucet.avax.network/api/sendToken"
+
+type Request struct {
+	// Address is the bech32 P-Chain address or the hex C-Chain address.
+	Address string `json:"address"`
+	// Chain is "P" or "C".
+	Chain string `json:"chain"`
+	// Token is the captcha response token (if the faucet requires one).
+	Token string `json:"token,omitempty"`
+	// CouponID is a faucet coupon (if any).
+	CouponID string `json:"couponId,omitempty"`
+}
+
+type Response struct {
+	Message string `json:"message"`
+	TxHash  string `json:"txHash"`
+}
+
+// Send requests tokens from the faucet at [url]. [apiKey] is attached as a
+// bearer token when not empty.
+func Send(ctx context.Context, url string, apiKey string, r Request) (*Response, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(Response)
+	_ = json.Unmarshal(body, ret)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := ret.Message
+		if msg == "" {
+			msg = string(body)
+		}
+		return nil, fmt.Errorf("%w: status code %d: %s", ErrFaucet, resp.StatusCode, msg)
+	}
+	return ret, nil
+}
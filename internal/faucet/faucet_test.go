@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		apiKey  string
+		status  int
+		body    string
+		expResp *Response
+		expErr  error
+		expMsg  string
+	}{
+		{
+			apiKey:  "key",
+			status:  http.StatusOK,
+			body:    `{"message":"Transaction successful","txHash":"2Qg6"}`,
+			expResp: &Response{Message: "Transaction successful", TxHash: "2Qg6"},
+		},
+		{
+			status: http.StatusTooManyRequests,
+			body:   `{"message":"Too many requests. Please try again after 1440 minutes"}`,
+			expErr: ErrFaucet,
+			expMsg: "status code 429: Too many requests. Please try again after 1440 minutes",
+		},
+		{
+			status: http.StatusBadRequest,
+			body:   `{"message":"Captcha verification failed!"}`,
+			expErr: ErrFaucet,
+			expMsg: "status code 400: Captcha verification failed!",
+		},
+		{
+			status: http.StatusBadGateway,
+			body:   "<html>bad gateway</html>",
+			expErr: ErrFaucet,
+			expMsg: "status code 502: <html>bad gateway</html>",
+		},
+	}
+	for i, tv := range tt {
+		var (
+			got  Request
+			auth string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(tv.status)
+			_, _ = w.Write([]byte(tv.body))
+		}))
+
+		req := Request{Address: "P-fuji1abc", Chain: "P", Token: "captcha"}
+		resp, err := Send(context.Background(), srv.URL, tv.apiKey, req)
+		srv.Close()
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if err != nil && !strings.HasSuffix(err.Error(), tv.expMsg) {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.expMsg, err)
+		}
+		if !reflect.DeepEqual(resp, tv.expResp) {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.expResp, resp)
+		}
+		if got != req {
+			t.Fatalf("#%d: expected request %+v, got %+v", i, req, got)
+		}
+		expAuth := ""
+		if tv.apiKey != "" {
+			expAuth = "Bearer " + tv.apiKey
+		}
+		if auth != expAuth {
+			t.Fatalf("#%d: expected authorization %q, got %q", i, expAuth, auth)
+		}
+	}
+}
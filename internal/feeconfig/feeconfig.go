@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package feeconfig provides presets and checks of the subnet-evm fee
+// config ("config.feeConfig" of a subnet-evm genesis).
+package feeconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrUnknownPreset    = errors.New("unknown fee preset")
+	ErrInvalidFeeConfig = errors.New("invalid fee config")
+	ErrInvalidGenesis   = errors.New("invalid subnet-evm genesis")
+)
+
+const (
+	// TxGas is the gas of a plain transfer, the smallest transaction.
+	TxGas = 21_000
+	// Gwei is 1e9 wei.
+	Gwei = 1_000_000_000
+	// TargetWindow is the window (in seconds) over which subnet-evm
+	// compares the gas used to [Config.TargetGas].
+	TargetWindow = 10
+)
+
+// Config is the subnet-evm fee config.
+type Config struct {
+	GasLimit                 uint64 `json:"gasLimit"`
+	TargetBlockRate          uint64 `json:"targetBlockRate"`
+	MinBaseFee               uint64 `json:"minBaseFee"`
+	TargetGas                uint64 `json:"targetGas"`
+	BaseFeeChangeDenominator uint64 `json:"baseFeeChangeDenominator"`
+	MinBlockGasCost          uint64 `json:"minBlockGasCost"`
+	MaxBlockGasCost          uint64 `json:"maxBlockGasCost"`
+	BlockGasCostStep         uint64 `json:"blockGasCostStep"`
+}
+
+// Preset is a named fee config for a kind of workload.
+type Preset struct {
+	Name        string
+	Description string
+	Config      Config
+}
+
+// Presets are the built-in presets, with "c-chain" as the default.
+var Presets = []Preset{
+	{
+		Name:        "c-chain",
+		Description: "C-Chain-like defaults: 8M gas blocks every ~2s, 25 gwei minimum base fee",
+		Config: Config{
+			GasLimit:                 8_000_000,
+			TargetBlockRate:          2,
+			MinBaseFee:               25 * Gwei,
+			TargetGas:                15_000_000,
+			BaseFeeChangeDenominator: 36,
+			MinBlockGasCost:          0,
+			MaxBlockGasCost:          1_000_000,
+			BlockGasCostStep:         200_000,
+		},
+	},
+	{
+		Name:        "gaming",
+		Description: "low latency: blocks every ~1s without a block gas cost, 1 gwei minimum base fee that reacts slowly",
+		Config: Config{
+			GasLimit:                 8_000_000,
+			TargetBlockRate:          1,
+			MinBaseFee:               1 * Gwei,
+			TargetGas:                40_000_000,
+			BaseFeeChangeDenominator: 48,
+			MinBlockGasCost:          0,
+			MaxBlockGasCost:          0,
+			BlockGasCostStep:         0,
+		},
+	},
+	{
+		Name:        "defi",
+		Description: "high throughput: 20M gas blocks every ~2s, 25 gwei minimum base fee that rises quickly under congestion",
+		Config: Config{
+			GasLimit:                 20_000_000,
+			TargetBlockRate:          2,
+			MinBaseFee:               25 * Gwei,
+			TargetGas:                100_000_000,
+			BaseFeeChangeDenominator: 36,
+			MinBlockGasCost:          0,
+			MaxBlockGasCost:          4_000_000,
+			BlockGasCostStep:         500_000,
+		},
+	},
+}
+
+// DefaultPreset is the name of the default preset.
+const DefaultPreset = "c-chain"
+
+// Lookup returns the preset [name].
+func Lookup(name string) (Preset, error) {
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	names := make([]string, 0, len(Presets))
+	for _, p := range Presets {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return Preset{}, fmt.Errorf("%w: %q (expected one of %v)", ErrUnknownPreset, name, names)
+}
+
+// Field is a field of the fee config with what it controls.
+type Field struct {
+	Name        string
+	Value       uint64
+	Explanation string
+}
+
+// Explain returns the fields of [c] with what each controls.
+func Explain(c Config) []Field {
+	return []Field{
+		{"gasLimit", c.GasLimit, fmt.Sprintf("max gas of a block (~%d transfers)", c.GasLimit/TxGas)},
+		{"targetBlockRate", c.TargetBlockRate, "seconds between blocks the block gas cost aims for"},
+		{"minBaseFee", c.MinBaseFee, fmt.Sprintf("floor of the base fee (%g gwei)", float64(c.MinBaseFee)/Gwei)},
+		{"targetGas", c.TargetGas, fmt.Sprintf("gas per %ds the base fee aims for: above it the base fee rises, below it falls", TargetWindow)},
+		{"baseFeeChangeDenominator", c.BaseFeeChangeDenominator, fmt.Sprintf("base fee changes by at most 1/%d of itself per block", c.BaseFeeChangeDenominator)},
+		{"minBlockGasCost", c.MinBlockGasCost, "block gas cost of a block produced after the target rate"},
+		{"maxBlockGasCost", c.MaxBlockGasCost, "block gas cost of a block produced right after its parent"},
+		{"blockGasCostStep", c.BlockGasCostStep, "change of the block gas cost per second off the target rate"},
+	}
+}
+
+// Check returns an error if [c] is invalid, and warnings about settings
+// that stall or price out a chain under load.
+func Check(c Config) (warnings []string, err error) {
+	switch {
+	case c.GasLimit < TxGas:
+		return nil, fmt.Errorf("%w: gasLimit %d cannot fit a %d gas transfer", ErrInvalidFeeConfig, c.GasLimit, TxGas)
+	case c.TargetBlockRate == 0:
+		return nil, fmt.Errorf("%w: targetBlockRate must be positive", ErrInvalidFeeConfig)
+	case c.BaseFeeChangeDenominator == 0:
+		return nil, fmt.Errorf("%w: baseFeeChangeDenominator must be positive", ErrInvalidFeeConfig)
+	case c.TargetGas == 0:
+		return nil, fmt.Errorf("%w: targetGas must be positive", ErrInvalidFeeConfig)
+	case c.MinBlockGasCost > c.MaxBlockGasCost:
+		return nil, fmt.Errorf("%w: minBlockGasCost %d exceeds maxBlockGasCost %d", ErrInvalidFeeConfig, c.MinBlockGasCost, c.MaxBlockGasCost)
+	}
+
+	// the gas the chain can process at its target block rate over the
+	// target window
+	capacity := c.GasLimit * TargetWindow / c.TargetBlockRate
+	if c.TargetGas < c.GasLimit {
+		warnings = append(warnings, fmt.Sprintf("targetGas %d is below gasLimit %d: a single full block per %ds raises the base fee", c.TargetGas, c.GasLimit, TargetWindow))
+	} else if c.TargetGas > capacity {
+		warnings = append(warnings, fmt.Sprintf("targetGas %d exceeds the %d gas of full blocks every %ds: the base fee never rises, so spam is only priced by minBaseFee", c.TargetGas, capacity, c.TargetBlockRate))
+	}
+	if c.MaxBlockGasCost > c.GasLimit/2 {
+		warnings = append(warnings, fmt.Sprintf("maxBlockGasCost %d is over half of gasLimit %d: fast blocks may not fit the block gas cost of their transactions and the chain may stall", c.MaxBlockGasCost, c.GasLimit))
+	}
+	if c.BaseFeeChangeDenominator < 8 {
+		warnings = append(warnings, fmt.Sprintf("baseFeeChangeDenominator %d lets the base fee swing by over 1/8 per block", c.BaseFeeChangeDenominator))
+	}
+	if c.MinBaseFee == 0 {
+		warnings = append(warnings, "minBaseFee is 0: transactions are free on an idle chain")
+	}
+	return warnings, nil
+}
+
+// Apply sets the fee config of the subnet-evm [genesis] to [c], with the
+// genesis block gas limit (which subnet-evm requires to match), and keeps
+// the other fields.
+func Apply(genesis []byte, c Config) ([]byte, error) {
+	g := map[string]json.RawMessage{}
+	if err := json.Unmarshal(genesis, &g); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGenesis, err)
+	}
+	config := map[string]json.RawMessage{}
+	if b, ok := g["config"]; ok {
+		if err := json.Unmarshal(b, &config); err != nil {
+			return nil, fmt.Errorf("%w: config: %v", ErrInvalidGenesis, err)
+		}
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	config["feeConfig"] = b
+	if g["config"], err = json.Marshal(config); err != nil {
+		return nil, err
+	}
+	g["gasLimit"] = json.RawMessage(fmt.Sprintf("%q", fmt.Sprintf("0x%x", c.GasLimit)))
+	return json.MarshalIndent(g, "", "  ")
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feeconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Lookup(DefaultPreset); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lookup("fast"); !errors.Is(err, ErrUnknownPreset) {
+		t.Fatalf("expected %v, got %v", ErrUnknownPreset, err)
+	}
+	// presets are valid and need no warning
+	for _, p := range Presets {
+		warnings, err := Check(p.Config)
+		if err != nil {
+			t.Fatalf("%s: %v", p.Name, err)
+		}
+		if len(warnings) > 0 {
+			t.Fatalf("%s: unexpected warnings %v", p.Name, warnings)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	base, _ := Lookup(DefaultPreset)
+	tt := []struct {
+		name     string
+		change   func(*Config)
+		warnings int
+		err      error
+	}{
+		{name: "small gas limit", change: func(c *Config) { c.GasLimit = 20_000 }, err: ErrInvalidFeeConfig},
+		{name: "no block rate", change: func(c *Config) { c.TargetBlockRate = 0 }, err: ErrInvalidFeeConfig},
+		{name: "no denominator", change: func(c *Config) { c.BaseFeeChangeDenominator = 0 }, err: ErrInvalidFeeConfig},
+		{name: "no target gas", change: func(c *Config) { c.TargetGas = 0 }, err: ErrInvalidFeeConfig},
+		{name: "block gas cost bounds", change: func(c *Config) { c.MinBlockGasCost = 2_000_000 }, err: ErrInvalidFeeConfig},
+		{name: "target below limit", change: func(c *Config) { c.TargetGas = 1_000_000 }, warnings: 1},
+		{name: "unreachable target", change: func(c *Config) { c.TargetGas = 100_000_000 }, warnings: 1},
+		{name: "large block gas cost", change: func(c *Config) { c.MaxBlockGasCost = 5_000_000 }, warnings: 1},
+		{name: "free", change: func(c *Config) { c.MinBaseFee = 0; c.BaseFeeChangeDenominator = 2 }, warnings: 2},
+	}
+	for _, tv := range tt {
+		c := base.Config
+		tv.change(&c)
+		warnings, err := Check(c)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		if len(warnings) != tv.warnings {
+			t.Fatalf("%s: expected %d warning(s), got %v", tv.name, tv.warnings, warnings)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	p, _ := Lookup("defi")
+	b, err := Apply([]byte(`{"config":{"chainId":99999,"feeConfig":{"gasLimit":8000000}},"gasLimit":"0x7A1200","alloc":{}}`), p.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g struct {
+		Config struct {
+			ChainID   uint64 `json:"chainId"`
+			FeeConfig Config `json:"feeConfig"`
+		} `json:"config"`
+		GasLimit string                 `json:"gasLimit"`
+		Alloc    map[string]interface{} `json:"alloc"`
+	}
+	if err := json.Unmarshal(b, &g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Config.ChainID != 99999 || g.Alloc == nil {
+		t.Fatalf("lost genesis fields: %s", b)
+	}
+	if g.Config.FeeConfig != p.Config {
+		t.Fatalf("expected %+v, got %+v", p.Config, g.Config.FeeConfig)
+	}
+	if g.GasLimit != "0x1312d00" {
+		t.Fatalf("unexpected gas limit %q", g.GasLimit)
+	}
+	if _, err := Apply([]byte("not json"), p.Config); !errors.Is(err, ErrInvalidGenesis) {
+		t.Fatalf("expected %v, got %v", ErrInvalidGenesis, err)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package grafana renders a Grafana dashboard and Prometheus alert rules
+// for the avalanchego metrics of a subnet's validators and blockchains.
+//
+// avalanchego serves its metrics at "/ext/metrics", with the metrics of
+// each blockchain prefixed by "avalanche_<blockchain ID>_" (unless the
+// chain is aliased). The series of a validator are told apart by the
+// [Target.NodeLabel] label, which the Prometheus scrape config must set to
+// the node ID of each target.
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultNodeLabel is the default label holding the node ID of a target.
+const DefaultNodeLabel = "node_id"
+
+// Target is what the dashboard and the rules monitor.
+type Target struct {
+	Network  string
+	SubnetID ids.ID
+	Chains   []Chain
+	// NodeIDs are the prefixed node IDs of the validators.
+	NodeIDs []string
+	// NodeLabel is the label of the node ID ([DefaultNodeLabel] if empty).
+	NodeLabel string
+	// MinUptime is the weighted average uptime percentage alerted below.
+	MinUptime float64
+}
+
+// Chain is a blockchain of the subnet.
+type Chain struct {
+	ID   ids.ID
+	Name string
+}
+
+func (t Target) nodeLabel() string {
+	if t.NodeLabel == "" {
+		return DefaultNodeLabel
+	}
+	return t.NodeLabel
+}
+
+// nodeSelector selects the series of the validators.
+func (t Target) nodeSelector() string {
+	nodeIDs := append([]string(nil), t.NodeIDs...)
+	sort.Strings(nodeIDs)
+	return fmt.Sprintf("%s=~%q", t.nodeLabel(), strings.Join(nodeIDs, "|"))
+}
+
+// chainMetric returns the metric [name] of [c] (e.g., "blks_accepted_count").
+func chainMetric(c Chain, name string) string {
+	return fmt.Sprintf("avalanche_%s_%s", c.ID, name)
+}
+
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name       string   `json:"name"`
+	Label      string   `json:"label"`
+	Type       string   `json:"type"`
+	Query      string   `json:"query"`
+	Multi      bool     `json:"multi,omitempty"`
+	IncludeAll bool     `json:"includeAll,omitempty"`
+	AllValue   string   `json:"allValue,omitempty"`
+	Options    []option `json:"options,omitempty"`
+	Current    *option  `json:"current,omitempty"`
+}
+
+type option struct {
+	Text     string `json:"text"`
+	Value    string `json:"value"`
+	Selected bool   `json:"selected"`
+}
+
+type panel struct {
+	ID         int        `json:"id"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	GridPos    gridPos    `json:"gridPos"`
+	Datasource datasource `json:"datasource"`
+	Targets    []query    `json:"targets"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type query struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	RefID        string `json:"refId"`
+}
+
+// Dashboard returns the dashboard JSON of [t], importable in Grafana. It
+// asks for a Prometheus data source on import and lets the viewer filter
+// the validators.
+func Dashboard(t Target) ([]byte, error) {
+	sel := fmt.Sprintf("%s=~\"$node\"", t.nodeLabel())
+	legend := fmt.Sprintf("{{%s}}", t.nodeLabel())
+
+	nodes := variable{
+		Name:       "node",
+		Label:      "Validator",
+		Type:       "custom",
+		Query:      strings.Join(t.NodeIDs, ","),
+		Multi:      true,
+		IncludeAll: true,
+		AllValue:   strings.Join(t.NodeIDs, "|"),
+		Current:    &option{Text: "All", Value: "$__all", Selected: true},
+	}
+	for _, nodeID := range t.NodeIDs {
+		nodes.Options = append(nodes.Options, option{Text: nodeID, Value: nodeID})
+	}
+	d := dashboard{
+		UID:           "subnet-" + shortID(t.SubnetID),
+		Title:         fmt.Sprintf("Subnet %s (%s)", t.SubnetID, t.Network),
+		Tags:          []string{"avalanche", "subnet", t.Network},
+		SchemaVersion: 36,
+		Refresh:       "30s",
+		Time:          timeRange{From: "now-6h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+			nodes,
+		}},
+	}
+
+	add := func(title string, qs ...query) {
+		// two panels per row
+		n := len(d.Panels)
+		x, y := 0, 8*(n/2)
+		if n%2 == 1 {
+			x = 12
+		}
+		for i := range qs {
+			qs[i].RefID = string(rune('A' + i))
+			if qs[i].LegendFormat == "" {
+				qs[i].LegendFormat = legend
+			}
+		}
+		d.Panels = append(d.Panels, panel{
+			ID:         n + 1,
+			Type:       "timeseries",
+			Title:      title,
+			GridPos:    gridPos{H: 8, W: 12, X: x, Y: y},
+			Datasource: datasource{Type: "prometheus", UID: "${datasource}"},
+			Targets:    qs,
+		})
+	}
+	add("Validators up", query{Expr: fmt.Sprintf("up{%s}", sel)})
+	add("Connected peers", query{Expr: fmt.Sprintf("avalanche_network_peers{%s}", sel)})
+	add("Weighted average uptime (%)", query{Expr: fmt.Sprintf("avalanche_network_node_uptime_weighted_average{%s}", sel)})
+	add("Rewarding stake (%)", query{Expr: fmt.Sprintf("avalanche_network_node_uptime_rewarding_stake{%s}", sel)})
+	for _, c := range t.Chains {
+		add(fmt.Sprintf("%s: accepted blocks/s", c.Name),
+			query{Expr: fmt.Sprintf("rate(%s{%s}[5m])", chainMetric(c, "blks_accepted_count"), sel)})
+		add(fmt.Sprintf("%s: processing blocks", c.Name),
+			query{Expr: fmt.Sprintf("%s{%s}", chainMetric(c, "blks_processing"), sel)})
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// shortID returns a prefix of [id] short enough for a Grafana UID (at most
+// 40 characters).
+func shortID(id ids.ID) string {
+	s := id.String()
+	if len(s) > 32 {
+		return s[:32]
+	}
+	return s
+}
+
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []rule `yaml:"rules"`
+}
+
+type rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// AlertRules returns the Prometheus alert rules of [t]: validators down,
+// without peers or below the minimum uptime, and stalled chains.
+func AlertRules(t Target) ([]byte, error) {
+	sel := t.nodeSelector()
+	node := fmt.Sprintf("{{ $labels.%s }}", t.nodeLabel())
+	labels := func(severity string) map[string]string {
+		return map[string]string{"severity": severity, "subnet_id": t.SubnetID.String(), "network": t.Network}
+	}
+	g := ruleGroup{Name: fmt.Sprintf("subnet-%s", t.SubnetID)}
+	g.Rules = append(g.Rules,
+		rule{
+			Alert:       "SubnetValidatorDown",
+			Expr:        fmt.Sprintf("up{%s} == 0", sel),
+			For:         "5m",
+			Labels:      labels("critical"),
+			Annotations: map[string]string{"summary": fmt.Sprintf("validator %s of subnet %s is down", node, t.SubnetID)},
+		},
+		rule{
+			Alert:       "SubnetValidatorNoPeers",
+			Expr:        fmt.Sprintf("avalanche_network_peers{%s} == 0", sel),
+			For:         "5m",
+			Labels:      labels("critical"),
+			Annotations: map[string]string{"summary": fmt.Sprintf("validator %s of subnet %s has no peers", node, t.SubnetID)},
+		},
+		rule{
+			Alert:       "SubnetValidatorLowUptime",
+			Expr:        fmt.Sprintf("avalanche_network_node_uptime_weighted_average{%s} < %g", sel, t.MinUptime),
+			For:         "15m",
+			Labels:      labels("warning"),
+			Annotations: map[string]string{"summary": fmt.Sprintf("validator %s of subnet %s observes a weighted average uptime of {{ $value }}%% (below %g%%)", node, t.SubnetID, t.MinUptime)},
+		},
+	)
+	for _, c := range t.Chains {
+		g.Rules = append(g.Rules,
+			rule{
+				Alert:  "SubnetChainStalled",
+				Expr:   fmt.Sprintf("sum(rate(%s{%s}[5m])) == 0", chainMetric(c, "blks_accepted_count"), sel),
+				For:    "5m",
+				Labels: mergeLabels(labels("critical"), map[string]string{"blockchain_id": c.ID.String(), "chain": c.Name}),
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("blockchain %s (%s) of subnet %s accepted no block for 5m", c.Name, c.ID, t.SubnetID),
+				},
+			},
+			rule{
+				Alert:  "SubnetChainLagging",
+				Expr:   fmt.Sprintf("rate(%s{%s}[5m]) == 0 and on() sum(rate(%s{%s}[5m])) > 0", chainMetric(c, "blks_accepted_count"), sel, chainMetric(c, "blks_accepted_count"), sel),
+				For:    "10m",
+				Labels: mergeLabels(labels("warning"), map[string]string{"blockchain_id": c.ID.String(), "chain": c.Name}),
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("validator %s accepts no block of %s while other validators do", node, c.Name),
+				},
+			},
+		)
+	}
+	return yaml.Marshal(ruleFile{Groups: []ruleGroup{g}})
+}
+
+func mergeLabels(a, b map[string]string) map[string]string {
+	for k, v := range b {
+		a[k] = v
+	}
+	return a
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grafana
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gopkg.in/yaml.v2"
+)
+
+func testTarget() Target {
+	return Target{
+		Network:   "fuji",
+		SubnetID:  ids.ID{1},
+		Chains:    []Chain{{ID: ids.ID{2}, Name: "mychain"}},
+		NodeIDs:   []string{"NodeID-B", "NodeID-A"},
+		MinUptime: 85,
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	t.Parallel()
+
+	tv := testTarget()
+	b, err := Dashboard(tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d dashboard
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	// 4 node panels and 2 per chain
+	if len(d.Panels) != 6 {
+		t.Fatalf("expected 6 panels, got %d", len(d.Panels))
+	}
+	accepted := d.Panels[4].Targets[0].Expr
+	if exp := `rate(avalanche_` + tv.Chains[0].ID.String() + `_blks_accepted_count{node_id=~"$node"}[5m])`; accepted != exp {
+		t.Fatalf("expected %q, got %q", exp, accepted)
+	}
+	if p := d.Panels[5]; p.GridPos.X != 12 || p.GridPos.Y != 16 || p.ID != 6 {
+		t.Fatalf("unexpected layout %+v", p)
+	}
+	nodes := d.Templating.List[1]
+	if nodes.Name != "node" || len(nodes.Options) != 2 || nodes.AllValue != "NodeID-B|NodeID-A" {
+		t.Fatalf("unexpected node variable %+v", nodes)
+	}
+	if len(d.UID) > 40 {
+		t.Fatalf("UID %q is too long", d.UID)
+	}
+}
+
+func TestAlertRules(t *testing.T) {
+	t.Parallel()
+
+	tv := testTarget()
+	tv.NodeLabel = "instance_node"
+	b, err := AlertRules(tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f ruleFile
+	if err := yaml.UnmarshalStrict(b, &f); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Groups) != 1 || len(f.Groups[0].Rules) != 5 {
+		t.Fatalf("unexpected rules %+v", f)
+	}
+	names := []string{}
+	for _, r := range f.Groups[0].Rules {
+		names = append(names, r.Alert)
+		if !strings.Contains(r.Expr, `instance_node=~"NodeID-A|NodeID-B"`) {
+			t.Fatalf("expected %s to select the sorted node IDs, got %q", r.Alert, r.Expr)
+		}
+		if r.Labels["subnet_id"] != tv.SubnetID.String() {
+			t.Fatalf("unexpected labels %v", r.Labels)
+		}
+	}
+	if s := strings.Join(names, ","); s != "SubnetValidatorDown,SubnetValidatorNoPeers,SubnetValidatorLowUptime,SubnetChainStalled,SubnetChainLagging" {
+		t.Fatalf("unexpected alerts %s", s)
+	}
+	if r := f.Groups[0].Rules[2]; !strings.HasSuffix(r.Expr, "< 85") {
+		t.Fatalf("unexpected uptime rule %q", r.Expr)
+	}
+	if r := f.Groups[0].Rules[3]; r.Labels["blockchain_id"] != tv.Chains[0].ID.String() {
+		t.Fatalf("unexpected chain labels %v", r.Labels)
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package index follows P-Chain blocks through the avalanchego index API
+// and stores the subnet-relevant events, so history queries run offline.
+//
+// Events are stored in a SQLite database, through a pure Go driver so the
+// release builds stay static.
+package index
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	ajson "github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"go.uber.org/zap"
+
+	// registers the "sqlite" driver
+	_ "modernc.org/sqlite"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+// Endpoint is the index API endpoint of P-Chain blocks; the node must run
+// with "--index-enabled".
+const Endpoint = "/ext/index/P/block"
+
+// DefaultBatchSize is the number of blocks fetched per request.
+const DefaultBatchSize = 1024
+
+var ErrUnexpectedBlock = errors.New("unexpected block")
+
+// EventKind is the kind of an indexed [Event].
+type EventKind string
+
+const (
+	EventSubnetCreated    EventKind = "subnet-created"
+	EventChainCreated     EventKind = "chain-created"
+	EventValidatorAdded   EventKind = "validator-added"
+	EventValidatorRemoved EventKind = "validator-removed"
+)
+
+// Event is a subnet-relevant P-Chain event. Subnet validators leave at
+// their [End] time without a tx, so only primary network removals and
+// early subnet removals (after Banff) are indexed as
+// [EventValidatorRemoved].
+type Event struct {
+	Kind EventKind `json:"kind"`
+	// Index is the position of the accepting block in the index API.
+	Index    uint64    `json:"index"`
+	BlockID  ids.ID    `json:"blockID"`
+	TxID     ids.ID    `json:"txID"`
+	Time     time.Time `json:"time"`
+	SubnetID ids.ID    `json:"subnetID"`
+
+	NodeID ids.ShortID `json:"nodeID,omitempty"`
+	Start  time.Time   `json:"start,omitempty"`
+	End    time.Time   `json:"end,omitempty"`
+	Weight uint64      `json:"weight,omitempty"`
+
+	ChainID   ids.ID `json:"chainID,omitempty"`
+	ChainName string `json:"chainName,omitempty"`
+	VMID      ids.ID `json:"vmID,omitempty"`
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS state (
+	key   TEXT PRIMARY KEY,
+	value BLOB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS events (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	subnet_id TEXT NOT NULL,
+	kind      TEXT NOT NULL,
+	tx_id     TEXT NOT NULL,
+	node_id   TEXT NOT NULL,
+	event     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_subnet ON events (subnet_id, node_id);
+CREATE INDEX IF NOT EXISTS events_tx ON events (tx_id);
+CREATE TABLE IF NOT EXISTS skipped (
+	idx      INTEGER PRIMARY KEY,
+	block_id TEXT NOT NULL,
+	error    TEXT NOT NULL
+);
+`
+
+// keys of the state table
+const (
+	nextKey     = "next"
+	proposalKey = "proposal"
+)
+
+// Store persists the indexed events.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the store at [path].
+func Open(path string) (*Store, error) {
+	// retry instead of failing while "index run --follow" writes
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// Next returns the index of the next block to process.
+func (s *Store) Next() (uint64, error) {
+	return getNext(s.db)
+}
+
+// Skipped returns the indexes of the blocks that could not be decoded, in
+// order; their events are missing from the store.
+func (s *Store) Skipped() ([]uint64, error) {
+	rows, err := s.db.Query("SELECT idx FROM skipped ORDER BY idx")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var idxs []uint64
+	for rows.Next() {
+		var idx int64
+		if err := rows.Scan(&idx); err != nil {
+			return nil, err
+		}
+		idxs = append(idxs, uint64(idx))
+	}
+	return idxs, rows.Err()
+}
+
+// querier is a [*sql.DB] or [*sql.Tx].
+type querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getNext(q querier) (uint64, error) {
+	var next int64
+	err := q.QueryRow("SELECT value FROM state WHERE key = ?", nextKey).Scan(&next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return uint64(next), err
+}
+
+// Events returns the events of [subnetID] in acceptance order, only those
+// of [nodeID] unless it is empty.
+func (s *Store) Events(subnetID ids.ID, nodeID ids.ShortID) ([]Event, error) {
+	query := "SELECT event FROM events WHERE subnet_id = ?"
+	args := []interface{}{subnetID.String()}
+	if nodeID != ids.ShortEmpty {
+		query += " AND node_id = ?"
+		args = append(args, nodeID.String())
+	}
+	rows, err := s.db.Query(query+" ORDER BY seq", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var evs []Event
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		var ev Event
+		if err := json.Unmarshal(b, &ev); err != nil {
+			return nil, err
+		}
+		evs = append(evs, ev)
+	}
+	return evs, rows.Err()
+}
+
+// Process indexes the block [c] at position [idx]. Blocks must be processed
+// in order, since proposal txs only take effect in the following commit
+// block.
+func (s *Store) Process(idx uint64, c indexer.Container) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+	next, err := getNext(tx)
+	if err != nil {
+		return err
+	}
+	if idx != next {
+		return fmt.Errorf("%w: got index %d, expected %d", ErrUnexpectedBlock, idx, next)
+	}
+	var blk interface{}
+	if _, err := codec.PCodecManager.Unmarshal(c.Bytes, &blk); err != nil {
+		// e.g. a tx type the codec does not know, which must not stop the
+		// index at the first such block
+		zap.L().Warn("skipping undecodable P-Chain block",
+			zap.Uint64("index", idx),
+			zap.Stringer("blockID", c.ID),
+			zap.Error(err),
+		)
+		if _, err := tx.Exec(
+			"REPLACE INTO skipped (idx, block_id, error) VALUES (?, ?, ?)",
+			int64(idx), c.ID.String(), err.Error(),
+		); err != nil {
+			return err
+		}
+	}
+	accepted := time.Unix(0, c.Timestamp)
+
+	var evs []Event
+	switch blk := blk.(type) {
+	case *platformvm.StandardBlock:
+		evs, err = txEvents(tx, blk.Txs)
+	case *codec.BanffStandardBlock:
+		evs, err = txEvents(tx, blk.Transactions)
+	case *platformvm.ProposalBlock:
+		err = propose(tx, &blk.Tx)
+	case *codec.BanffProposalBlock:
+		err = propose(tx, blk.Tx)
+	case *platformvm.CommitBlock, *codec.BanffCommitBlock:
+		evs, err = decide(tx, true)
+	case *platformvm.AbortBlock, *codec.BanffAbortBlock:
+		evs, err = decide(tx, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, ev := range evs {
+		ev.Index, ev.BlockID, ev.Time = idx, c.ID, accepted
+		b, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO events (subnet_id, kind, tx_id, node_id, event) VALUES (?, ?, ?, ?, ?)",
+			ev.SubnetID.String(), string(ev.Kind), ev.TxID.String(), ev.NodeID.String(), b,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("REPLACE INTO state (key, value) VALUES (?, ?)", nextKey, int64(idx+1)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// txEvents returns the events of the decision txs [txs].
+func txEvents(q querier, txs []*platformvm.Tx) ([]Event, error) {
+	var evs []Event
+	for _, ptx := range txs {
+		ev, err := txEvent(q, ptx)
+		if err != nil {
+			return nil, err
+		}
+		if ev != nil {
+			evs = append(evs, *ev)
+		}
+	}
+	return evs, nil
+}
+
+// propose stores the event of the proposal tx [ptx], which only takes
+// effect if the next block commits it.
+func propose(tx *sql.Tx, ptx *platformvm.Tx) error {
+	ev, err := txEvent(tx, ptx)
+	if err != nil {
+		return err
+	}
+	if ev == nil {
+		_, err = tx.Exec("DELETE FROM state WHERE key = ?", proposalKey)
+		return err
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("REPLACE INTO state (key, value) VALUES (?, ?)", proposalKey, b)
+	return err
+}
+
+// decide returns the events of the stored proposal once committed, or
+// aborted if [commit] is false.
+func decide(tx *sql.Tx, commit bool) ([]Event, error) {
+	var b []byte
+	err := tx.QueryRow("SELECT value FROM state WHERE key = ?", proposalKey).Scan(&b)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ev Event
+	if err := json.Unmarshal(b, &ev); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM state WHERE key = ?", proposalKey); err != nil {
+		return nil, err
+	}
+	// a validator is removed whether or not it is rewarded
+	if commit || ev.Kind == EventValidatorRemoved {
+		return []Event{ev}, nil
+	}
+	return nil, nil
+}
+
+// txEvent returns the event of [tx], or nil if it is not subnet-relevant.
+func txEvent(q querier, tx *platformvm.Tx) (*Event, error) {
+	signedBytes, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, tx)
+	if err != nil {
+		return nil, err
+	}
+	txID := hashing.ComputeHash256Array(signedBytes)
+
+	switch utx := tx.UnsignedTx.(type) {
+	case *platformvm.UnsignedCreateSubnetTx:
+		return &Event{Kind: EventSubnetCreated, TxID: txID, SubnetID: txID}, nil
+	case *platformvm.UnsignedCreateChainTx:
+		return &Event{
+			Kind:      EventChainCreated,
+			TxID:      txID,
+			SubnetID:  utx.SubnetID,
+			ChainID:   txID,
+			ChainName: utx.ChainName,
+			VMID:      utx.VMID,
+		}, nil
+	case *platformvm.UnsignedAddValidatorTx:
+		return validatorEvent(txID, constants.PrimaryNetworkID, utx.Validator), nil
+	case *platformvm.UnsignedAddSubnetValidatorTx:
+		return validatorEvent(txID, utx.Validator.Subnet, utx.Validator.Validator), nil
+	case *codec.RemoveSubnetValidatorTx:
+		ev := &Event{Kind: EventValidatorRemoved, TxID: txID, SubnetID: utx.Subnet, NodeID: utx.NodeID}
+		var b []byte
+		err := q.QueryRow(
+			"SELECT event FROM events WHERE subnet_id = ? AND node_id = ? AND kind = ? ORDER BY seq DESC LIMIT 1",
+			utx.Subnet.String(), utx.NodeID.String(), string(EventValidatorAdded),
+		).Scan(&b)
+		if errors.Is(err, sql.ErrNoRows) {
+			// added before the indexed range
+			return ev, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var added Event
+		if err := json.Unmarshal(b, &added); err != nil {
+			return nil, err
+		}
+		ev.Start, ev.End, ev.Weight = added.Start, added.End, added.Weight
+		return ev, nil
+	case *platformvm.UnsignedRewardValidatorTx:
+		var b []byte
+		err := q.QueryRow(
+			"SELECT event FROM events WHERE tx_id = ? AND kind = ? AND subnet_id = ?",
+			utx.TxID.String(), string(EventValidatorAdded), constants.PrimaryNetworkID.String(),
+		).Scan(&b)
+		if errors.Is(err, sql.ErrNoRows) {
+			// a delegator, or a validator added before the indexed range
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var added Event
+		if err := json.Unmarshal(b, &added); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Kind:     EventValidatorRemoved,
+			TxID:     txID,
+			SubnetID: constants.PrimaryNetworkID,
+			NodeID:   added.NodeID,
+			Start:    added.Start,
+			End:      added.End,
+			Weight:   added.Weight,
+		}, nil
+	}
+	return nil, nil
+}
+
+func validatorEvent(txID ids.ID, subnetID ids.ID, v platformvm.Validator) *Event {
+	return &Event{
+		Kind:     EventValidatorAdded,
+		TxID:     txID,
+		SubnetID: subnetID,
+		NodeID:   v.NodeID,
+		Start:    time.Unix(int64(v.Start), 0),
+		End:      time.Unix(int64(v.End), 0),
+		Weight:   v.Wght,
+	}
+}
+
+// Sync indexes every block accepted since the last sync and returns the
+// number of blocks processed.
+func Sync(ctx context.Context, cli indexer.Client, s *Store, batchSize uint64) (int, error) {
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	lc, err := cli.GetLastAccepted(ctx, &indexer.GetLastAcceptedArgs{Encoding: formatting.Hex})
+	if err != nil {
+		return 0, err
+	}
+	last, err := cli.GetIndex(ctx, &indexer.GetIndexArgs{ContainerID: lc.ID, Encoding: formatting.Hex})
+	if err != nil {
+		return 0, err
+	}
+	next, err := s.Next()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for next <= last && ctx.Err() == nil {
+		cs, err := cli.GetContainerRange(ctx, &indexer.GetContainerRangeArgs{
+			StartIndex: ajson.Uint64(next),
+			NumToFetch: ajson.Uint64(batchSize),
+			Encoding:   formatting.Hex,
+		})
+		if err != nil {
+			return n, err
+		}
+		if len(cs) == 0 {
+			break
+		}
+		for _, c := range cs {
+			if err := s.Process(next, c); err != nil {
+				return n, err
+			}
+			next++
+			n++
+		}
+		zap.L().Debug("indexed P-Chain blocks", zap.Uint64("next", next), zap.Uint64("last", last))
+	}
+	return n, ctx.Err()
+}
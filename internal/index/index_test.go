@@ -0,0 +1,251 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package index
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+func testTx(t *testing.T, utx platformvm.UnsignedTx) (*platformvm.Tx, ids.ID) {
+	tx := &platformvm.Tx{UnsignedTx: utx}
+	b, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, hashing.ComputeHash256Array(b)
+}
+
+// testContainer packs [blk], a P-Chain block of the codec.
+func testContainer(t *testing.T, blk interface{}) indexer.Container {
+	b, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, &blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return indexer.Container{ID: hashing.ComputeHash256Array(b), Bytes: b, Timestamp: 1}
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	owner := &secp256k1fx.OutputOwners{}
+	auth := &secp256k1fx.Input{}
+	nodeID := ids.GenerateTestShortID()
+
+	createSubnet, subnetID := testTx(t, &platformvm.UnsignedCreateSubnetTx{Owner: owner})
+	createChain, chainID := testTx(t, &platformvm.UnsignedCreateChainTx{
+		SubnetID:   subnetID,
+		ChainName:  "test",
+		VMID:       ids.GenerateTestID(),
+		SubnetAuth: auth,
+	})
+	addValidator, addValidatorID := testTx(t, &platformvm.UnsignedAddValidatorTx{
+		Validator:    platformvm.Validator{NodeID: nodeID, Start: 1, End: 2, Wght: 2000},
+		RewardsOwner: owner,
+	})
+	addSubnetValidator, _ := testTx(t, &platformvm.UnsignedAddSubnetValidatorTx{
+		Validator: platformvm.SubnetValidator{
+			Validator: platformvm.Validator{NodeID: nodeID, Start: 1, End: 2, Wght: 1000},
+			Subnet:    subnetID,
+		},
+		SubnetAuth: auth,
+	})
+	abortedSubnetValidator, _ := testTx(t, &platformvm.UnsignedAddSubnetValidatorTx{
+		Validator: platformvm.SubnetValidator{
+			Validator: platformvm.Validator{NodeID: ids.GenerateTestShortID(), Start: 1, End: 2, Wght: 1000},
+			Subnet:    subnetID,
+		},
+		SubnetAuth: auth,
+	})
+	reward, _ := testTx(t, &platformvm.UnsignedRewardValidatorTx{TxID: addValidatorID})
+
+	blks := []platformvm.Block{
+		&platformvm.StandardBlock{Txs: []*platformvm.Tx{createSubnet, createChain}},
+		&platformvm.ProposalBlock{Tx: *addValidator},
+		&platformvm.CommitBlock{},
+		&platformvm.ProposalBlock{Tx: *addSubnetValidator},
+		&platformvm.CommitBlock{},
+		&platformvm.ProposalBlock{Tx: *abortedSubnetValidator},
+		&platformvm.AbortBlock{},
+		&platformvm.ProposalBlock{Tx: *reward},
+		&platformvm.AbortBlock{},
+	}
+	dir := t.TempDir()
+	s, err := Open(filepath.Join(dir, "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for i, blk := range blks {
+		if err := s.Process(uint64(i), testContainer(t, blk)); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+	}
+	if err := s.Process(0, testContainer(t, blks[0])); !errors.Is(err, ErrUnexpectedBlock) {
+		t.Fatalf("expected %v, got %v", ErrUnexpectedBlock, err)
+	}
+
+	tt := []struct {
+		subnetID ids.ID
+		exp      []EventKind
+	}{
+		{subnetID: constants.PrimaryNetworkID, exp: []EventKind{EventValidatorAdded, EventValidatorRemoved}},
+		{subnetID: subnetID, exp: []EventKind{EventSubnetCreated, EventChainCreated, EventValidatorAdded}},
+	}
+	for _, tv := range tt {
+		evs, err := s.Events(tv.subnetID, ids.ShortEmpty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(evs) != len(tv.exp) {
+			t.Fatalf("%s: expected %d events, got %+v", tv.subnetID, len(tv.exp), evs)
+		}
+		for i, ev := range evs {
+			if ev.Kind != tv.exp[i] {
+				t.Fatalf("%s: #%d expected %q, got %q", tv.subnetID, i, tv.exp[i], ev.Kind)
+			}
+			if ev.Kind == EventChainCreated && ev.ChainID != chainID {
+				t.Fatalf("expected chain %s, got %s", chainID, ev.ChainID)
+			}
+			if ev.Kind == EventValidatorRemoved && (ev.NodeID != nodeID || ev.Index != 8) {
+				t.Fatalf("unexpected removal %+v", ev)
+			}
+		}
+	}
+
+	evs, err := s.Events(constants.PrimaryNetworkID, ids.GenerateTestShortID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 0 {
+		t.Fatalf("expected no events of another node, got %+v", evs)
+	}
+
+	// reopening resumes after the last processed block
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Open(filepath.Join(dir, "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	next, err := s.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint64(len(blks)) {
+		t.Fatalf("expected next %d, got %d", len(blks), next)
+	}
+}
+
+func TestProcessBanff(t *testing.T) {
+	t.Parallel()
+
+	owner := &secp256k1fx.OutputOwners{}
+	auth := &secp256k1fx.Input{}
+	nodeID := ids.GenerateTestShortID()
+
+	createSubnet, subnetID := testTx(t, &platformvm.UnsignedCreateSubnetTx{Owner: owner})
+	addValidator, _ := testTx(t, &platformvm.UnsignedAddValidatorTx{
+		Validator:    platformvm.Validator{NodeID: nodeID, Start: 1, End: 2, Wght: 2000},
+		RewardsOwner: owner,
+	})
+	addSubnetValidator, _ := testTx(t, &platformvm.UnsignedAddSubnetValidatorTx{
+		Validator: platformvm.SubnetValidator{
+			Validator: platformvm.Validator{NodeID: nodeID, Start: 1, End: 2, Wght: 1000},
+			Subnet:    subnetID,
+		},
+		SubnetAuth: auth,
+	})
+	removeSubnetValidator, _ := testTx(t, &codec.RemoveSubnetValidatorTx{
+		NodeID:     nodeID,
+		Subnet:     subnetID,
+		SubnetAuth: auth,
+	})
+
+	// a standard block of a tx type the codec skips (AddPermissionlessValidatorTx)
+	p := wrappers.Packer{MaxSize: 1024}
+	p.PackShort(platformvm.CodecVersion)
+	p.PackInt(32)
+	p.PackLong(1)
+	p.PackFixedBytes(ids.Empty[:])
+	p.PackLong(1)
+	p.PackInt(1)
+	p.PackInt(25)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	unknown := indexer.Container{ID: hashing.ComputeHash256Array(p.Bytes), Bytes: p.Bytes, Timestamp: 1}
+
+	ctrs := []indexer.Container{
+		testContainer(t, &codec.BanffStandardBlock{Time: 1, Transactions: []*platformvm.Tx{createSubnet}}),
+		testContainer(t, &codec.BanffProposalBlock{Time: 1, Tx: addValidator}),
+		testContainer(t, &codec.BanffCommitBlock{Time: 1}),
+		unknown,
+		testContainer(t, &codec.BanffStandardBlock{Time: 1, Transactions: []*platformvm.Tx{addSubnetValidator}}),
+		testContainer(t, &codec.BanffStandardBlock{Time: 2, Transactions: []*platformvm.Tx{removeSubnetValidator}}),
+	}
+	s, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for i, c := range ctrs {
+		if err := s.Process(uint64(i), c); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+	}
+
+	tt := []struct {
+		subnetID ids.ID
+		exp      []EventKind
+	}{
+		{subnetID: constants.PrimaryNetworkID, exp: []EventKind{EventValidatorAdded}},
+		{subnetID: subnetID, exp: []EventKind{EventSubnetCreated, EventValidatorAdded, EventValidatorRemoved}},
+	}
+	for _, tv := range tt {
+		evs, err := s.Events(tv.subnetID, ids.ShortEmpty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(evs) != len(tv.exp) {
+			t.Fatalf("%s: expected %d events, got %+v", tv.subnetID, len(tv.exp), evs)
+		}
+		for i, ev := range evs {
+			if ev.Kind != tv.exp[i] {
+				t.Fatalf("%s: #%d expected %q, got %q", tv.subnetID, i, tv.exp[i], ev.Kind)
+			}
+			if ev.Kind == EventValidatorRemoved && (ev.NodeID != nodeID || ev.Weight != 1000 || ev.Index != 5) {
+				t.Fatalf("unexpected removal %+v", ev)
+			}
+		}
+	}
+
+	skipped, err := s.Skipped()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || skipped[0] != 3 {
+		t.Fatalf("expected skipped [3], got %v", skipped)
+	}
+	next, err := s.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != uint64(len(ctrs)) {
+		t.Fatalf("expected next %d, got %d", len(ctrs), next)
+	}
+}
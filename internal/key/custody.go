@@ -0,0 +1,426 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avacrypto "github.com/ava-labs/avalanchego/utils/crypto"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/poll"
+	"github.com/ava-labs/subnet-cli/pkg/color"
+)
+
+const (
+	// DefaultCustodyPollInterval is how often a pending signing request is
+	// checked for approval.
+	DefaultCustodyPollInterval = 5 * time.Second
+	// DefaultApprovalTimeout is how long to wait for the approvers.
+	DefaultApprovalTimeout = 30 * time.Minute
+
+	// DefaultFireblocksURL is the Fireblocks API endpoint.
+	DefaultFireblocksURL = "https://api.fireblocks.io"
+
+	custodyNote = "subnet-cli P-Chain tx"
+)
+
+var (
+	ErrInvalidCustodyKey = errors.New("invalid custody key")
+	ErrSigningRejected   = errors.New("signing request rejected")
+)
+
+// custodyBackend is a custody service (e.g., MPC) that signs a hash once
+// the signing request is approved by its policy.
+type custodyBackend interface {
+	// publicKey returns the compressed secp256k1 public key.
+	publicKey(ctx context.Context) ([]byte, error)
+	// submit requests the signature of [hash] and returns the request ID.
+	submit(ctx context.Context, hash []byte) (string, error)
+	// status returns the [r || s] (optionally followed by [v]) signature of
+	// request [id], or nil if it is still pending. It returns
+	// [ErrSigningRejected] if the request will never be signed.
+	status(ctx context.Context, id string) ([]byte, error)
+}
+
+// CustodyConfig configures how long a [CustodySigner] waits for approval.
+type CustodyConfig struct {
+	// PollInterval defaults to [DefaultCustodyPollInterval].
+	PollInterval time.Duration
+	// ApprovalTimeout defaults to [DefaultApprovalTimeout].
+	ApprovalTimeout time.Duration
+}
+
+var _ Signer = &CustodySigner{}
+
+// CustodySigner signs with a key held by a custody service, waiting for each
+// signing request to be approved.
+type CustodySigner struct {
+	backend custodyBackend
+	addr    ids.ShortID
+	cfg     CustodyConfig
+}
+
+// FireblocksConfig configures [NewFireblocksSigner].
+type FireblocksConfig struct {
+	CustodyConfig
+
+	// URL defaults to [DefaultFireblocksURL].
+	URL string
+	// APIKey is the ID of the API user.
+	APIKey string
+	// Secret is the PEM-encoded RSA private key of the API user.
+	Secret []byte
+	// VaultAccountID and AddressIndex select the key derived at
+	// m/44'/9000'/VaultAccountID'/0/AddressIndex.
+	VaultAccountID uint32
+	AddressIndex   uint32
+}
+
+// NewFireblocksSigner returns a signer that submits each hash as a Fireblocks
+// raw signing transaction, signed once approved by the workspace policy.
+func NewFireblocksSigner(ctx context.Context, cfg FireblocksConfig) (*CustodySigner, error) {
+	if cfg.URL == "" {
+		cfg.URL = DefaultFireblocksURL
+	}
+	blk, _ := pem.Decode(cfg.Secret)
+	if blk == nil {
+		return nil, fmt.Errorf("%w: invalid Fireblocks secret PEM", ErrInvalidCustodyKey)
+	}
+	var secret *rsa.PrivateKey
+	parsed, err := x509.ParsePKCS8PrivateKey(blk.Bytes)
+	switch {
+	case err == nil:
+		var ok bool
+		if secret, ok = parsed.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("%w: Fireblocks secret is %T, expected RSA", ErrInvalidCustodyKey, parsed)
+		}
+	default:
+		if secret, err = x509.ParsePKCS1PrivateKey(blk.Bytes); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCustodyKey, err)
+		}
+	}
+	return newCustodySigner(ctx, &fireblocks{
+		url:    strings.TrimSuffix(cfg.URL, "/"),
+		apiKey: cfg.APIKey,
+		secret: secret,
+		path:   []uint32{44, 9000, cfg.VaultAccountID, 0, cfg.AddressIndex},
+	}, cfg.CustodyConfig)
+}
+
+// NewHTTPCustodySigner returns a signer backed by a generic custody service
+// at [endpoint], authenticated with the bearer [token]. The service
+// implements:
+//
+//	GET  /public-key          -> {"publicKey": "<compressed hex>"}
+//	POST /sign-requests       {"hash": "<hex>", "note": "..."} -> {"id": "..."}
+//	GET  /sign-requests/{id}  -> {"status": "pending|signed|rejected", "signature": "<r || s [|| v] hex>"}
+func NewHTTPCustodySigner(ctx context.Context, endpoint string, token string, cfg CustodyConfig) (*CustodySigner, error) {
+	return newCustodySigner(ctx, &httpCustody{
+		url:   strings.TrimSuffix(endpoint, "/"),
+		token: token,
+	}, cfg)
+}
+
+func newCustodySigner(ctx context.Context, b custodyBackend, cfg CustodyConfig) (*CustodySigner, error) {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultCustodyPollInterval
+	}
+	if cfg.ApprovalTimeout == 0 {
+		cfg.ApprovalTimeout = DefaultApprovalTimeout
+	}
+	pub, err := b.publicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := keyFactory.ToPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCustodyKey, err)
+	}
+	return &CustodySigner{backend: b, addr: pk.Address(), cfg: cfg}, nil
+}
+
+func (k *CustodySigner) Addresses() []ids.ShortID { return []ids.ShortID{k.addr} }
+
+func (k *CustodySigner) SignHash(ctx context.Context, hash []byte, addrs []ids.ShortID) ([][avacrypto.SECP256K1RSigLen]byte, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	for _, addr := range addrs {
+		if addr != k.addr {
+			return nil, fmt.Errorf("%w: %s not owned by custody key", ErrCantSign, addr)
+		}
+	}
+
+	id, err := k.backend.submit(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	color.Outf("{{yellow}}submitted signing request %q, waiting for approval...{{/}}\n", id)
+
+	var (
+		raw  []byte
+		ferr error
+	)
+	pctx, cancel := context.WithTimeout(ctx, k.cfg.ApprovalTimeout)
+	_, err = poll.New(k.cfg.PollInterval).Poll(pctx, func() (bool, error) {
+		raw, ferr = k.backend.status(pctx, id)
+		if errors.Is(ferr, ErrSigningRejected) {
+			return false, poll.Fatal(ferr)
+		}
+		return raw != nil, ferr
+	})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("signing request %q not approved: %w", id, err)
+	}
+	if len(raw) < 64 {
+		return nil, fmt.Errorf("%w: %d-byte signature", ErrInvalidKMSSignature, len(raw))
+	}
+	sig, err := recoverSig(hash, new(big.Int).SetBytes(raw[:32]), new(big.Int).SetBytes(raw[32:64]), k.addr)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("signing request approved", zap.String("id", id))
+
+	// every address is the same key, and the signature is the same
+	sigs := make([][avacrypto.SECP256K1RSigLen]byte, len(addrs))
+	for i := range sigs {
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
+
+// doJSON sends [body] as JSON and decodes the JSON response into [resp].
+func doJSON(ctx context.Context, method string, u string, header http.Header, body []byte, resp interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrCantSign, method, u, res.Status, bytes.TrimSpace(b))
+	}
+	return json.Unmarshal(b, resp)
+}
+
+type httpCustody struct {
+	url   string
+	token string
+}
+
+func (h *httpCustody) do(ctx context.Context, method string, path string, body interface{}, resp interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	header := http.Header{}
+	if h.token != "" {
+		header.Set("Authorization", "Bearer "+h.token)
+	}
+	return doJSON(ctx, method, h.url+path, header, b, resp)
+}
+
+func (h *httpCustody) publicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		PublicKey string `json:"publicKey"`
+	}
+	if err := h.do(ctx, http.MethodGet, "/public-key", nil, &resp); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(resp.PublicKey)
+}
+
+func (h *httpCustody) submit(ctx context.Context, hash []byte) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	err := h.do(ctx, http.MethodPost, "/sign-requests", map[string]string{
+		"hash": hex.EncodeToString(hash),
+		"note": custodyNote,
+	}, &resp)
+	return resp.ID, err
+}
+
+func (h *httpCustody) status(ctx context.Context, id string) ([]byte, error) {
+	var resp struct {
+		Status    string `json:"status"`
+		Signature string `json:"signature"`
+	}
+	if err := h.do(ctx, http.MethodGet, "/sign-requests/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+	switch resp.Status {
+	case "signed":
+		return hex.DecodeString(resp.Signature)
+	case "rejected":
+		return nil, fmt.Errorf("%w: %q", ErrSigningRejected, id)
+	}
+	return nil, nil
+}
+
+// fireblocks implements raw signing with the Fireblocks API.
+//
+// ref. https://developers.fireblocks.com/reference/api-overview
+type fireblocks struct {
+	url    string
+	apiKey string
+	secret *rsa.PrivateKey
+	path   []uint32
+}
+
+// do signs the request with a JWT, as required by every Fireblocks call.
+func (f *fireblocks) do(ctx context.Context, method string, path string, body interface{}, resp interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	token, err := f.token(path, b)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("X-API-Key", f.apiKey)
+	header.Set("Authorization", "Bearer "+token)
+	return doJSON(ctx, method, f.url+path, header, b, resp)
+}
+
+func (f *fireblocks) token(path string, body []byte) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	bodyHash := sha256.Sum256(body)
+	now := time.Now().Unix()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"uri":      path,
+		"nonce":    hex.EncodeToString(nonce),
+		"iat":      now,
+		"exp":      now + 55,
+		"sub":      f.apiKey,
+		"bodyHash": hex.EncodeToString(bodyHash[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, f.secret, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+func (f *fireblocks) publicKey(ctx context.Context) ([]byte, error) {
+	path, err := json.Marshal(f.path)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Set("derivationPath", string(path))
+	q.Set("algorithm", "MPC_ECDSA_SECP256K1")
+	q.Set("compressed", "true")
+	var resp struct {
+		PublicKey string `json:"publicKey"`
+	}
+	if err := f.do(ctx, http.MethodGet, "/v1/vault/public_key_info?"+q.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(resp.PublicKey)
+}
+
+func (f *fireblocks) submit(ctx context.Context, hash []byte) (string, error) {
+	req := map[string]interface{}{
+		"operation": "RAW",
+		"note":      custodyNote,
+		"source": map[string]string{
+			"type": "VAULT_ACCOUNT",
+			"id":   fmt.Sprint(f.path[2]),
+		},
+		"extraParameters": map[string]interface{}{
+			"rawMessageData": map[string]interface{}{
+				"algorithm": "MPC_ECDSA_SECP256K1",
+				"messages": []map[string]interface{}{{
+					"content":        hex.EncodeToString(hash),
+					"derivationPath": f.path,
+				}},
+			},
+		},
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	err := f.do(ctx, http.MethodPost, "/v1/transactions", req, &resp)
+	return resp.ID, err
+}
+
+func (f *fireblocks) status(ctx context.Context, id string) ([]byte, error) {
+	var resp struct {
+		Status         string `json:"status"`
+		SignedMessages []struct {
+			Signature struct {
+				FullSig string `json:"fullSig"`
+			} `json:"signature"`
+		} `json:"signedMessages"`
+	}
+	if err := f.do(ctx, http.MethodGet, "/v1/transactions/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+	switch resp.Status {
+	case "COMPLETED":
+		if len(resp.SignedMessages) == 0 {
+			return nil, fmt.Errorf("%w: no signed message in %q", ErrInvalidKMSSignature, id)
+		}
+		return hex.DecodeString(resp.SignedMessages[0].Signature.FullSig)
+	case "REJECTED", "CANCELLED", "BLOCKED", "FAILED":
+		return nil, fmt.Errorf("%w: %q is %s", ErrSigningRejected, id, resp.Status)
+	}
+	return nil, nil
+}
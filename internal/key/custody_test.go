@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// fakeCustody serves the generic custody API, approving (or rejecting) each
+// request after [pending] status checks.
+type fakeCustody struct {
+	sk      *ecdsa.PrivateKey
+	pub     []byte
+	pending int
+	reject  bool
+
+	mu     sync.Mutex
+	hashes map[string][]byte
+	checks map[string]int
+}
+
+func (f *fakeCustody) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.URL.Path == "/public-key":
+		_ = json.NewEncoder(w).Encode(map[string]string{"publicKey": hex.EncodeToString(f.pub)})
+	case r.URL.Path == "/sign-requests" && r.Method == http.MethodPost:
+		var req struct {
+			Hash string `json:"hash"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		hash, _ := hex.DecodeString(req.Hash)
+		id := "req-1"
+		f.hashes[id] = hash
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": id})
+	default:
+		id := r.URL.Path[len("/sign-requests/"):]
+		f.checks[id]++
+		resp := map[string]string{"status": "pending"}
+		switch {
+		case f.checks[id] <= f.pending:
+		case f.reject:
+			resp["status"] = "rejected"
+		default:
+			r, s, err := ecdsa.Sign(rand.Reader, f.sk, f.hashes[id])
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			resp["status"] = "signed"
+			resp["signature"] = hex.EncodeToString(sig)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestCustodySigner(t *testing.T) {
+	t.Parallel()
+
+	soft, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := soft.Addresses()[0]
+	hash := hashing.ComputeHash256([]byte("tx"))
+
+	tt := []struct {
+		name    string
+		reject  bool
+		pending int
+		expErr  error
+	}{
+		{name: "approved", pending: 2},
+		{name: "rejected", pending: 2, reject: true, expErr: ErrSigningRejected},
+		// the wait for approval ends with the context of the caller
+		{name: "expired", pending: 1 << 30, expErr: context.DeadlineExceeded},
+	}
+	for _, tv := range tt {
+		srv := httptest.NewServer(&fakeCustody{
+			sk:      soft.Key().ToECDSA(),
+			pub:     soft.Key().PublicKey().Bytes(),
+			pending: tv.pending,
+			reject:  tv.reject,
+			hashes:  map[string][]byte{},
+			checks:  map[string]int{},
+		})
+		k, err := NewHTTPCustodySigner(context.Background(), srv.URL, "token", CustodyConfig{PollInterval: time.Millisecond})
+		if err != nil {
+			srv.Close()
+			t.Fatalf("%s: %v", tv.name, err)
+		}
+		if k.Addresses()[0] != addr {
+			t.Fatalf("%s: expected address %s, got %s", tv.name, addr, k.Addresses()[0])
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		sigs, err := k.SignHash(ctx, hash, []ids.ShortID{addr})
+		cancel()
+		srv.Close()
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.expErr, err)
+		}
+		if tv.expErr != nil {
+			continue
+		}
+		pk, err := keyFactory.RecoverHashPublicKey(hash, sigs[0][:])
+		if err != nil {
+			t.Fatalf("%s: %v", tv.name, err)
+		}
+		if pk.Address() != addr {
+			t.Fatalf("%s: signature recovers to %s, expected %s", tv.name, pk.Address(), addr)
+		}
+	}
+}
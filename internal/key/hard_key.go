@@ -4,37 +4,36 @@
 package key
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
-	"github.com/ava-labs/subnet-cli/internal/codec"
 	"github.com/ava-labs/subnet-cli/pkg/color"
 
 	ledger "github.com/ava-labs/avalanche-ledger-go"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto"
-	"github.com/ava-labs/avalanchego/utils/formatting"
-	"github.com/ava-labs/avalanchego/utils/hashing"
-	"github.com/ava-labs/avalanchego/vms/components/avax"
-	"github.com/ava-labs/avalanchego/vms/components/verify"
-	"github.com/ava-labs/avalanchego/vms/platformvm"
-	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/manifoldco/promptui"
 	"github.com/onsi/ginkgo/v2/formatter"
-	"go.uber.org/zap"
 )
 
 const (
 	numAddresses = 1024
 )
 
-var _ Key = &HardKey{}
+var (
+	_ Key    = &HardKey{}
+	_ Signer = &HardKey{}
+)
 
+// HardKey signs on a Ledger device; spending and credential assembly are
+// implemented by the embedded [SignerKey].
 type HardKey struct {
+	*SignerKey
+
 	l *ledger.Ledger
 
-	pAddrs       []string
 	shortAddrs   []ids.ShortID
 	shortAddrMap map[ids.ShortID]uint32
 }
@@ -101,14 +100,9 @@ func NewHard(networkID uint32) (*HardKey, error) {
 			return err
 		}
 		laddrs := len(addrs)
-		k.pAddrs = make([]string, laddrs)
 		k.shortAddrs = make([]ids.ShortID, laddrs)
 		k.shortAddrMap = map[ids.ShortID]uint32{}
 		for i, addr := range addrs {
-			k.pAddrs[i], err = formatting.FormatAddress("P", hrp, addr.ShortAddr[:])
-			if err != nil {
-				return err
-			}
 			k.shortAddrs[i] = addr.ShortAddr
 			k.shortAddrMap[addr.ShortAddr] = uint32(i)
 		}
@@ -117,7 +111,13 @@ func NewHard(networkID uint32) (*HardKey, error) {
 		return nil, err
 	}
 
-	color.Outf("{{yellow}}derived primary address from ledger: %s{{/}}\n", k.pAddrs[0])
+	signerKey, err := NewSignerKey(networkID, k)
+	if err != nil {
+		return nil, err
+	}
+	k.SignerKey = signerKey
+
+	color.Outf("{{yellow}}derived primary address from ledger: %s{{/}}\n", k.P()[0])
 	return k, nil
 }
 
@@ -125,157 +125,33 @@ func (h *HardKey) Disconnect() error {
 	return h.l.Disconnect()
 }
 
-func (h *HardKey) P() []string { return h.pAddrs }
-
+// Addresses returns the addresses derived from the device; it shadows the
+// embedded [SignerKey] method since it is called to construct it.
 func (h *HardKey) Addresses() []ids.ShortID {
 	return h.shortAddrs
 }
 
-func (h *HardKey) Spends(outputs []*avax.UTXO, opts ...OpOption) (
-	totalBalanceToSpend uint64,
-	inputs []*avax.TransferableInput,
-	signers [][]ids.ShortID,
-) {
-	ret := &Op{}
-	ret.applyOpts(opts)
-
-	for _, out := range outputs {
-		input, txsigners, err := h.spend(out, ret.time)
-		if err != nil {
-			zap.L().Warn("cannot spend with current key", zap.Error(err))
-			continue
-		}
-		totalBalanceToSpend += input.Amount()
-		inputs = append(inputs, &avax.TransferableInput{
-			UTXOID: out.UTXOID,
-			Asset:  out.Asset,
-			In:     input,
-		})
-		signers = append(signers, txsigners)
-		if ret.targetAmount > 0 &&
-			totalBalanceToSpend > ret.targetAmount+ret.feeDeduct {
-			break
-		}
-	}
-	SortTransferableInputsWithSigners(inputs, signers)
-	return totalBalanceToSpend, inputs, signers
-}
-
-func (h *HardKey) spend(output *avax.UTXO, time uint64) (
-	input avax.TransferableIn,
-	signers []ids.ShortID,
-	err error,
-) {
-	// "time" is used to check whether the key owner
-	// is still within the lock time (thus can't spend).
-	inputf, signers, err := h.lspend(output.Out, time)
-	if err != nil {
-		return nil, nil, err
-	}
-	var ok bool
-	input, ok = inputf.(avax.TransferableIn)
-	if !ok {
-		return nil, nil, ErrInvalidType
-	}
-	return input, signers, nil
-}
-
-func (h *HardKey) lspend(out verify.Verifiable, time uint64) (verify.Verifiable, []ids.ShortID, error) {
-	switch out := out.(type) {
-	case *secp256k1fx.MintOutput:
-		if sigIndices, signers, able := h.Match(&out.OutputOwners, time); able {
-			return &secp256k1fx.Input{
-				SigIndices: sigIndices,
-			}, signers, nil
-		}
-		return nil, nil, ErrCantSpend
-	case *secp256k1fx.TransferOutput:
-		if sigIndices, signers, able := h.Match(&out.OutputOwners, time); able {
-			return &secp256k1fx.TransferInput{
-				Amt: out.Amt,
-				Input: secp256k1fx.Input{
-					SigIndices: sigIndices,
-				},
-			}, signers, nil
-		}
-		return nil, nil, ErrCantSpend
-	}
-	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
-}
-
-func (h *HardKey) Match(owners *secp256k1fx.OutputOwners, time uint64) ([]uint32, []ids.ShortID, bool) {
-	if time < owners.Locktime {
-		return nil, nil, false
-	}
-	sigs := make([]uint32, 0, owners.Threshold)
-	signers := make([]ids.ShortID, 0, owners.Threshold)
-	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
-		if _, ok := h.shortAddrMap[owners.Addrs[i]]; ok {
-			sigs = append(sigs, i)
-			signers = append(signers, owners.Addrs[i])
-		}
-	}
-	return sigs, signers, uint32(len(sigs)) == owners.Threshold
-}
-
-// Sign transaction with the Ledger private key
-//
-// This is a slightly modified version of *platformvm.Tx.Sign().
-func (h *HardKey) Sign(pTx *platformvm.Tx, signers [][]ids.ShortID) error {
-	unsignedBytes, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, &pTx.UnsignedTx)
-	if err != nil {
-		return fmt.Errorf("couldn't marshal UnsignedTx: %w", err)
-	}
-	hash := hashing.ComputeHash256(unsignedBytes)
-
-	// Generate signature
-	uniqueSigners := map[uint32]struct{}{}
-	for _, inputSigners := range signers {
-		for _, signer := range inputSigners {
-			if v, ok := h.shortAddrMap[signer]; ok {
-				uniqueSigners[v] = struct{}{}
-			} else {
-				// Should never happen
-				return ErrCantSpend
-			}
+// SignHash signs [hash] on the device with the key of each of [addrs].
+func (h *HardKey) SignHash(_ context.Context, hash []byte, addrs []ids.ShortID) ([][crypto.SECP256K1RSigLen]byte, error) {
+	indices := make([]uint32, len(addrs))
+	for i, addr := range addrs {
+		idx, ok := h.shortAddrMap[addr]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s not derived from ledger", ErrCantSign, addr)
 		}
-	}
-	indices := make([]uint32, 0, len(uniqueSigners))
-	for idx := range uniqueSigners {
-		indices = append(indices, idx)
+		indices[i] = idx
 	}
 
 	var sigs [][]byte
-	if err := retriableLegerAction(func() error {
+	if err := retriableLegerAction(func() (err error) {
 		sigs, err = h.l.SignHash(hash, indices)
-		if err != nil {
-			return err
-		}
-		return nil
+		return err
 	}, "failed to sign hash"); err != nil {
-		return fmt.Errorf("problem generating signatures: %w", err)
-	}
-	sigMap := map[ids.ShortID][]byte{}
-	for i, idx := range indices {
-		sigMap[h.shortAddrs[idx]] = sigs[i]
-	}
-
-	// Add credentials to transaction
-	for _, inputSigners := range signers {
-		cred := &secp256k1fx.Credential{
-			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(inputSigners)),
-		}
-		for i, signer := range inputSigners {
-			copy(cred.Sigs[i][:], sigMap[signer])
-		}
-		pTx.Creds = append(pTx.Creds, cred)
+		return nil, err
 	}
-
-	// Create signed tx bytes
-	signedBytes, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, pTx)
-	if err != nil {
-		return fmt.Errorf("couldn't marshal ProposalTx: %w", err)
+	ret := make([][crypto.SECP256K1RSigLen]byte, len(sigs))
+	for i, sig := range sigs {
+		copy(ret[i][:], sig)
 	}
-	pTx.Initialize(unsignedBytes, signedBytes)
-	return nil
+	return ret, nil
 }
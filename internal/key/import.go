@@ -0,0 +1,389 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+// Format is an encoding of a private key accepted by [Import].
+type Format string
+
+const (
+	// FormatAuto detects the format from the data.
+	FormatAuto Format = ""
+	// FormatCB58 is the "PrivateKey-..." CB58 string of avalanchego and the
+	// web wallet.
+	FormatCB58 Format = "cb58"
+	// FormatHex is a (0x-prefixed) hex key, as exported by EVM wallets.
+	FormatHex Format = "hex"
+	// FormatPEM is a PEM-encoded SEC 1 or PKCS #8 secp256k1 key.
+	FormatPEM Format = "pem"
+	// FormatKeystore is a web wallet keystore file (JSON export).
+	FormatKeystore Format = "keystore"
+	// FormatMnemonic is a BIP-39 mnemonic phrase.
+	FormatMnemonic Format = "mnemonic"
+)
+
+// Formats lists the formats [Import] accepts.
+var Formats = []Format{FormatCB58, FormatHex, FormatPEM, FormatKeystore, FormatMnemonic}
+
+var (
+	ErrUnknownKeyFormat  = errors.New("unknown private key format")
+	ErrStakingKey        = errors.New("avalanchego staking (TLS) keys only identify a node and cannot own funds; use \"subnet-cli node id\" to get its node ID")
+	ErrUnsupportedCurve  = errors.New("unsupported key curve (expected secp256k1)")
+	ErrInvalidKeystore   = errors.New("invalid keystore")
+	ErrWrongPassword     = errors.New("wrong keystore password")
+	ErrInvalidMnemonic   = errors.New("invalid mnemonic")
+	ErrKeystoreNoWallets = errors.New("keystore has no wallets")
+)
+
+var (
+	oidECPublicKey  = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSECP256K1    = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidRSAPublicKey = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+)
+
+type IOp struct {
+	format       Format
+	password     string
+	addressIndex uint32
+}
+
+type IOpOption func(*IOp)
+
+func (op *IOp) applyOpts(opts []IOpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// To skip format detection.
+func WithFormat(f Format) IOpOption {
+	return func(op *IOp) {
+		op.format = f
+	}
+}
+
+// To decrypt a keystore.
+func WithPassword(password string) IOpOption {
+	return func(op *IOp) {
+		op.password = password
+	}
+}
+
+// To select the address m/44'/9000'/0'/0/[idx] of a mnemonic.
+func WithAddressIndex(idx uint32) IOpOption {
+	return func(op *IOp) {
+		op.addressIndex = idx
+	}
+}
+
+// DetectFormat guesses the format of [data], returning [FormatAuto] if
+// nothing matches.
+func DetectFormat(data []byte) Format {
+	s := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(s, "-----BEGIN"):
+		return FormatPEM
+	case strings.HasPrefix(s, "{"):
+		return FormatKeystore
+	case strings.HasPrefix(s, privKeyEncPfx):
+		return FormatCB58
+	}
+	if b, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil && len(b) == crypto.SECP256K1RSKLen {
+		return FormatHex
+	}
+	if n := len(strings.Fields(s)); n >= 12 && n%3 == 0 {
+		return FormatMnemonic
+	}
+	return FormatAuto
+}
+
+// Import decodes the private key in [data]; keystores need [WithPassword].
+// The same key owns the P-Chain and X-Chain addresses and the EVM address
+// of the C-Chain, so any format converts to the CLI key file.
+func Import(data []byte, opts ...IOpOption) (*crypto.PrivateKeySECP256K1R, Format, error) {
+	ret := &IOp{}
+	ret.applyOpts(opts)
+
+	format := ret.format
+	if format == FormatAuto {
+		format = DetectFormat(data)
+	}
+	s := strings.TrimSpace(string(data))
+	var (
+		pk  *crypto.PrivateKeySECP256K1R
+		err error
+	)
+	switch format {
+	case FormatCB58:
+		pk, err = decodePrivateKey(s)
+	case FormatHex:
+		var b []byte
+		b, err = hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err == nil {
+			pk, err = toPrivateKey(b)
+		}
+	case FormatPEM:
+		pk, err = importPEM([]byte(s))
+	case FormatKeystore:
+		redact.Register(ret.password)
+		pk, err = importKeystore([]byte(s), ret.password, ret.addressIndex)
+	case FormatMnemonic:
+		redact.Register(s, strings.Join(strings.Fields(s), " "))
+		pk, err = importMnemonic(s, ret.addressIndex)
+	default:
+		return nil, format, fmt.Errorf("%w: %q", ErrUnknownKeyFormat, format)
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	return pk, format, nil
+}
+
+func toPrivateKey(b []byte) (*crypto.PrivateKeySECP256K1R, error) {
+	if len(b) != crypto.SECP256K1RSKLen {
+		return nil, ErrInvalidPrivateKeyLen
+	}
+	rpk, err := keyFactory.ToPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := rpk.(*crypto.PrivateKeySECP256K1R)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return pk, nil
+}
+
+// importPEM decodes a SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY")
+// key; "crypto/x509" does not support secp256k1.
+func importPEM(data []byte) (*crypto.PrivateKeySECP256K1R, error) {
+	blk, _ := pem.Decode(data)
+	if blk == nil {
+		return nil, fmt.Errorf("%w: invalid PEM", ErrInvalidPrivateKey)
+	}
+	der := blk.Bytes
+	switch blk.Type {
+	case "RSA PRIVATE KEY", "CERTIFICATE":
+		return nil, ErrStakingKey
+	case "PRIVATE KEY":
+		var p8 struct {
+			Version    int
+			Algorithm  pkix.AlgorithmIdentifier
+			PrivateKey []byte
+		}
+		if _, err := asn1.Unmarshal(der, &p8); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+		}
+		switch {
+		case p8.Algorithm.Algorithm.Equal(oidRSAPublicKey):
+			return nil, ErrStakingKey
+		case !p8.Algorithm.Algorithm.Equal(oidECPublicKey):
+			return nil, fmt.Errorf("%w: algorithm %s", ErrUnsupportedCurve, p8.Algorithm.Algorithm)
+		}
+		var curve asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(p8.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSECP256K1) {
+			return nil, ErrUnsupportedCurve
+		}
+		der = p8.PrivateKey
+	case "EC PRIVATE KEY":
+	default:
+		return nil, fmt.Errorf("%w: PEM type %q", ErrUnknownKeyFormat, blk.Type)
+	}
+
+	var sec1 struct {
+		Version       int
+		PrivateKey    []byte
+		NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+		PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+	}
+	if _, err := asn1.Unmarshal(der, &sec1); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	if len(sec1.NamedCurveOID) > 0 && !sec1.NamedCurveOID.Equal(oidSECP256K1) {
+		return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedCurve, sec1.NamedCurveOID)
+	}
+	return toPrivateKey(sec1.PrivateKey)
+}
+
+// keystore is the web wallet keystore export.
+//
+// ref. https://github.com/ava-labs/avalanche-wallet/blob/master/src/js/Keystore.ts
+type keystore struct {
+	Version     string           `json:"version"`
+	Salt        string           `json:"salt"`
+	ActiveIndex int              `json:"activeIndex"`
+	Wallets     []keystoreWallet `json:"wallets"`
+}
+
+type keystoreWallet struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	IV   string `json:"iv"`
+}
+
+// keystoreIterations maps the keystore versions to their PBKDF2 iterations.
+var keystoreIterations = map[string]int{
+	"5.0": 100000,
+	"6.0": 200000,
+}
+
+func importKeystore(data []byte, password string, addressIndex uint32) (*crypto.PrivateKeySECP256K1R, error) {
+	var ks keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	iterations, ok := keystoreIterations[ks.Version]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidKeystore, ks.Version)
+	}
+	if len(ks.Wallets) == 0 {
+		return nil, ErrKeystoreNoWallets
+	}
+	idx := ks.ActiveIndex
+	if idx < 0 || idx >= len(ks.Wallets) {
+		idx = 0
+	}
+	w := ks.Wallets[idx]
+
+	salt, err := formatting.Decode(formatting.CB58, ks.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: salt: %v", ErrInvalidKeystore, err)
+	}
+	ciphertext, err := formatting.Decode(formatting.CB58, w.Key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key: %v", ErrInvalidKeystore, err)
+	}
+	iv, err := formatting.Decode(formatting.CB58, w.IV)
+	if err != nil {
+		return nil, fmt.Errorf("%w: iv: %v", ErrInvalidKeystore, err)
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), salt, iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	plaintext, err := gcm.Open(nil, iv, ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	switch w.Type {
+	case "mnemonic":
+		return importMnemonic(string(plaintext), addressIndex)
+	case "singleton":
+		return decodePrivateKey(string(plaintext))
+	}
+	return nil, fmt.Errorf("%w: unsupported wallet type %q", ErrInvalidKeystore, w.Type)
+}
+
+const hardened = 0x80000000
+
+// importMnemonic derives the key at m/44'/9000'/0'/0/[addressIndex], the
+// path of the web wallet.
+func importMnemonic(mnemonic string, addressIndex uint32) (*crypto.PrivateKeySECP256K1R, error) {
+	seed, err := mnemonicSeed(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := deriveBIP32(seed, []uint32{44 + hardened, 9000 + hardened, hardened, 0, addressIndex})
+	if err != nil {
+		return nil, err
+	}
+	return toPrivateKey(sk)
+}
+
+// mnemonicSeed checks [mnemonic] against the BIP-39 English wordlist and
+// its checksum, so a mistyped word fails instead of deriving another key,
+// and returns its seed (without passphrase).
+//
+// ref. https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+func mnemonicSeed(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(words))
+	}
+	for i, w := range words {
+		if _, ok := bip39.GetWordIndex(w); !ok {
+			return nil, fmt.Errorf("%w: word %d is not in the BIP-39 English wordlist", ErrInvalidMnemonic, i+1)
+		}
+	}
+	normalized := strings.Join(words, " ")
+	if _, err := bip39.EntropyFromMnemonic(normalized); err != nil {
+		return nil, fmt.Errorf("%w: checksum mismatch (a word may be mistyped or out of order)", ErrInvalidMnemonic)
+	}
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"), 2048, 64, sha512.New), nil
+}
+
+// deriveBIP32 derives the private key at [path] from [seed].
+//
+// ref. https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+func deriveBIP32(seed []byte, path []uint32) ([]byte, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	I := mac.Sum(nil)
+	k, c := new(big.Int).SetBytes(I[:32]), I[32:]
+	if k.Sign() == 0 || k.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("%w: invalid master key", ErrInvalidPrivateKey)
+	}
+	for _, idx := range path {
+		sk := make([]byte, 32)
+		k.FillBytes(sk)
+		data := make([]byte, 0, 37)
+		if idx >= hardened {
+			data = append(data, 0)
+			data = append(data, sk...)
+		} else {
+			pk, err := toPrivateKey(sk)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, pk.PublicKey().Bytes()...)
+		}
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], idx)
+		mac := hmac.New(sha512.New, c)
+		mac.Write(data)
+		I := mac.Sum(nil)
+		il := new(big.Int).SetBytes(I[:32])
+		if il.Cmp(secp256k1N) >= 0 {
+			return nil, fmt.Errorf("%w: invalid child %d", ErrInvalidPrivateKey, idx)
+		}
+		k = il.Add(il, k)
+		k.Mod(k, secp256k1N)
+		if k.Sign() == 0 {
+			return nil, fmt.Errorf("%w: invalid child %d", ErrInvalidPrivateKey, idx)
+		}
+		c = I[32:]
+	}
+	sk := make([]byte, 32)
+	k.FillBytes(sk)
+	return sk, nil
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+func testKeystore(t *testing.T, password string, plaintext string) []byte {
+	salt := []byte("0123456789abcdef")
+	iv := []byte("0123456789ab")
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), salt, keystoreIterations["6.0"], 32, sha256.New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := func(b []byte) string {
+		s, err := formatting.EncodeWithChecksum(formatting.CB58, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	b, err := json.Marshal(keystore{
+		Version: "6.0",
+		Salt:    enc(salt),
+		Wallets: []keystoreWallet{{
+			Type: "singleton",
+			Key:  enc(gcm.Seal(nil, iv, []byte(plaintext), nil)),
+			IV:   enc(iv),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	ewoq, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := ewoq.Raw()
+	sec1, err := asn1.Marshal(struct {
+		Version       int
+		PrivateKey    []byte
+		NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	}{1, raw, oidSECP256K1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256, err := asn1.Marshal(struct {
+		Version       int
+		PrivateKey    []byte
+		NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	}{1, raw, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystore := testKeystore(t, "password", EwoqPrivateKey)
+
+	tt := []struct {
+		name      string
+		data      []byte
+		opts      []IOpOption
+		expFormat Format
+		expErr    error
+	}{
+		{name: "cb58", data: []byte(EwoqPrivateKey + "\n"), expFormat: FormatCB58},
+		{name: "hex", data: []byte(hex.EncodeToString(raw)), expFormat: FormatHex},
+		{name: "0x hex", data: []byte("0x" + hex.EncodeToString(raw)), expFormat: FormatHex},
+		{name: "sec1 pem", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}), expFormat: FormatPEM},
+		{name: "p256 pem", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: p256}), expFormat: FormatPEM, expErr: ErrUnsupportedCurve},
+		{name: "staking key", data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte{0}}), expFormat: FormatPEM, expErr: ErrStakingKey},
+		{name: "keystore", data: keystore, opts: []IOpOption{WithPassword("password")}, expFormat: FormatKeystore},
+		{name: "keystore wrong password", data: keystore, opts: []IOpOption{WithPassword("wrong")}, expFormat: FormatKeystore, expErr: ErrWrongPassword},
+		{name: "unknown", data: []byte("hello"), expErr: ErrUnknownKeyFormat},
+	}
+	for _, tv := range tt {
+		pk, format, err := Import(tv.data, tv.opts...)
+		if format != tv.expFormat {
+			t.Fatalf("%s: expected format %q, got %q", tv.name, tv.expFormat, format)
+		}
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.expErr, err)
+		}
+		if tv.expErr != nil {
+			continue
+		}
+		if pk.PublicKey().Address() != ewoq.Addresses()[0] {
+			t.Fatalf("%s: imported %s, expected %s", tv.name, pk.PublicKey().Address(), ewoq.Addresses()[0])
+		}
+	}
+}
+
+func TestDeriveBIP32(t *testing.T) {
+	t.Parallel()
+
+	// BIP-32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tt := []struct {
+		path []uint32
+		exp  string
+	}{
+		{path: nil, exp: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{path: []uint32{hardened}, exp: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{path: []uint32{hardened, 1}, exp: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+	}
+	for i, tv := range tt {
+		sk, err := deriveBIP32(seed, tv.path)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if hex.EncodeToString(sk) != tv.exp {
+			t.Fatalf("#%d: expected %s, got %x", i, tv.exp, sk)
+		}
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	t.Parallel()
+
+	// BIP-39 vector without passphrase, and its first Ethereum account
+	// (m/44'/60'/0'/0/0) as derived by every BIP-44 wallet
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	seed, err := mnemonicSeed(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"; hex.EncodeToString(seed) != exp {
+		t.Fatalf("expected seed %s, got %x", exp, seed)
+	}
+	sk, err := deriveBIP32(seed, []uint32{44 + hardened, 60 + hardened, hardened, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := toPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := evm.AddressFromKey(pk); a.Hex() != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Fatalf("unexpected address %s", a.Hex())
+	}
+	if _, err := importMnemonic(" "+mnemonic+"\n", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		// typo in the first word
+		"abandn abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		// valid words, wrong checksum
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon",
+	} {
+		if _, err := importMnemonic(s, 0); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Fatalf("%q: expected %v, got %v", s, ErrInvalidMnemonic, err)
+		}
+	}
+}
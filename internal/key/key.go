@@ -6,6 +6,7 @@ package key
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sort"
 
@@ -19,6 +20,7 @@ import (
 var (
 	ErrInvalidType = errors.New("invalid type")
 	ErrCantSpend   = errors.New("can't spend")
+	ErrCantSign    = errors.New("can't sign")
 )
 
 // Key defines methods for key manager interface.
@@ -41,7 +43,7 @@ type Key interface {
 		signers [][]ids.ShortID,
 	)
 	// Sign generates [numSigs] signatures and attaches them to [pTx].
-	Sign(pTx *platformvm.Tx, signers [][]ids.ShortID) error
+	Sign(ctx context.Context, pTx *platformvm.Tx, signers [][]ids.ShortID) error
 }
 
 type Op struct {
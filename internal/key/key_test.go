@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 )
@@ -116,3 +117,41 @@ func TestNewKey(t *testing.T) {
 		}
 	}
 }
+
+func TestNetworkLabel(t *testing.T) {
+	t.Parallel()
+
+	keyPath := filepath.Join(t.TempDir(), "key.pk")
+	if _, ok, err := LoadNetworkLabel(keyPath); err != nil || ok {
+		t.Fatalf("unexpected label (ok %v, err %v)", ok, err)
+	}
+	if err := SaveNetworkLabel(keyPath, fallbackNetworkID); err != nil {
+		t.Fatal(err)
+	}
+	networkID, ok, err := LoadNetworkLabel(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || networkID != fallbackNetworkID {
+		t.Fatalf("unexpected network label %d, expected %d", networkID, fallbackNetworkID)
+	}
+}
+
+func TestParsePAddress(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := ParsePAddress(fallbackNetworkID, ewoqPChainAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != m.Addresses()[0] {
+		t.Fatalf("unexpected address %s, expected %s", addr, m.Addresses()[0])
+	}
+	if _, err := ParsePAddress(constants.FujiID, ewoqPChainAddr); !errors.Is(err, ErrInvalidAddressHRP) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidAddressHRP)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+var (
+	ErrInvalidKMSKey       = errors.New("invalid KMS key")
+	ErrInvalidKMSSignature = errors.New("invalid KMS signature")
+
+	// secp256k1 group order, and half of it for the low-S rule.
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// kmsBackend is a cloud KMS holding a secp256k1 sign-only key.
+type kmsBackend interface {
+	// publicKey returns the DER-encoded SubjectPublicKeyInfo.
+	publicKey(ctx context.Context) ([]byte, error)
+	// sign returns the DER-encoded ECDSA signature of the SHA-256 [digest].
+	sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+var _ Signer = &KMSSigner{}
+
+// KMSSigner signs with a secp256k1 key that never leaves the cloud KMS.
+type KMSSigner struct {
+	backend kmsBackend
+	addr    ids.ShortID
+}
+
+// NewAWSKMSSigner returns a signer backed by the AWS KMS key [keyARN]
+// (key spec ECC_SECG_P256K1, usage SIGN_VERIFY). It shells out to the "aws"
+// CLI, so its credentials and configuration apply.
+func NewAWSKMSSigner(ctx context.Context, keyARN string) (*KMSSigner, error) {
+	return newKMSSigner(ctx, &awsKMS{keyARN: keyARN})
+}
+
+// NewGCPKMSSigner returns a signer backed by the Cloud KMS key version
+// [name] ("projects/.../cryptoKeyVersions/N", algorithm
+// EC_SIGN_SECP256K1_SHA256). It authenticates with the token of the
+// "gcloud" CLI.
+func NewGCPKMSSigner(ctx context.Context, name string) (*KMSSigner, error) {
+	if !strings.HasPrefix(name, "projects/") || !strings.Contains(name, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("%w: expected projects/.../cryptoKeyVersions/N, got %q", ErrInvalidKMSKey, name)
+	}
+	return newKMSSigner(ctx, &gcpKMS{name: name})
+}
+
+func newKMSSigner(ctx context.Context, b kmsBackend) (*KMSSigner, error) {
+	der, err := b.publicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := spkiAddress(der)
+	if err != nil {
+		return nil, err
+	}
+	return &KMSSigner{backend: b, addr: addr}, nil
+}
+
+func (k *KMSSigner) Addresses() []ids.ShortID { return []ids.ShortID{k.addr} }
+
+func (k *KMSSigner) SignHash(ctx context.Context, hash []byte, addrs []ids.ShortID) ([][crypto.SECP256K1RSigLen]byte, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	for _, addr := range addrs {
+		if addr != k.addr {
+			return nil, fmt.Errorf("%w: %s not owned by KMS key", ErrCantSign, addr)
+		}
+	}
+	der, err := k.backend.sign(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := recoverableSig(hash, der, k.addr)
+	if err != nil {
+		return nil, err
+	}
+	// every address is the same key, and the signature is the same
+	sigs := make([][crypto.SECP256K1RSigLen]byte, len(addrs))
+	for i := range sigs {
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
+
+// spkiAddress returns the address of a DER-encoded secp256k1
+// SubjectPublicKeyInfo ("crypto/x509" does not support the curve).
+func spkiAddress(der []byte) (ids.ShortID, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return ids.ShortEmpty, fmt.Errorf("%w: %v", ErrInvalidKMSKey, err)
+	}
+	point := spki.PublicKey.Bytes
+	if len(point) != 65 || point[0] != 0x04 {
+		return ids.ShortEmpty, fmt.Errorf("%w: expected uncompressed secp256k1 point", ErrInvalidKMSKey)
+	}
+	compressed := make([]byte, 33)
+	compressed[0] = 0x02 + point[64]&1
+	copy(compressed[1:], point[1:33])
+	pk, err := keyFactory.ToPublicKey(compressed)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("%w: %v", ErrInvalidKMSKey, err)
+	}
+	return pk.Address(), nil
+}
+
+// recoverableSig converts a DER-encoded ECDSA signature into the
+// [r || s || v] format, finding the recovery ID for [addr].
+func recoverableSig(hash []byte, der []byte, addr ids.ShortID) (sig [crypto.SECP256K1RSigLen]byte, err error) {
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return sig, fmt.Errorf("%w: %v", ErrInvalidKMSSignature, err)
+	}
+	return recoverSig(hash, rs.R, rs.S, addr)
+}
+
+// recoverSig returns the [r || s || v] signature with a low S, finding the
+// recovery ID [v] for which the signature recovers to [addr].
+func recoverSig(hash []byte, r *big.Int, s *big.Int, addr ids.ShortID) (sig [crypto.SECP256K1RSigLen]byte, err error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.BitLen() > 256 || s.BitLen() > 256 {
+		return sig, ErrInvalidKMSSignature
+	}
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pk, err := keyFactory.RecoverHashPublicKey(hash, sig[:])
+		if err == nil && pk.Address() == addr {
+			return sig, nil
+		}
+	}
+	return sig, fmt.Errorf("%w: does not recover to %s", ErrInvalidKMSSignature, addr)
+}
+
+func runCLI(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %s", err, name, strings.Join(args[:2], " "), bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+type awsKMS struct {
+	keyARN string
+}
+
+func (a *awsKMS) publicKey(ctx context.Context) ([]byte, error) {
+	out, err := runCLI(ctx, "aws", "kms", "get-public-key", "--key-id", a.keyARN, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		PublicKey []byte `json:"PublicKey"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	return resp.PublicKey, nil
+}
+
+func (a *awsKMS) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	f, err := ioutil.TempFile("", "subnet-cli-digest")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(digest)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	out, err := runCLI(ctx, "aws", "kms", "sign",
+		"--key-id", a.keyARN,
+		"--message", "fileb://"+f.Name(),
+		"--message-type", "DIGEST",
+		"--signing-algorithm", "ECDSA_SHA_256",
+		"--output", "json",
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Signature []byte `json:"Signature"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+type gcpKMS struct {
+	name string
+}
+
+func (g *gcpKMS) do(ctx context.Context, method string, path string, body interface{}, resp interface{}) error {
+	token, err := runCLI(ctx, "gcloud", "auth", "print-access-token")
+	if err != nil {
+		return err
+	}
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, gcpKMSEndpoint+path, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+string(bytes.TrimSpace(token)))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrCantSign, method, path, res.Status, bytes.TrimSpace(b))
+	}
+	return json.Unmarshal(b, resp)
+}
+
+func (g *gcpKMS) publicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		PEM string `json:"pem"`
+	}
+	if err := g.do(ctx, http.MethodGet, g.name+"/publicKey", nil, &resp); err != nil {
+		return nil, err
+	}
+	blk, _ := pem.Decode([]byte(resp.PEM))
+	if blk == nil {
+		return nil, fmt.Errorf("%w: invalid PEM", ErrInvalidKMSKey)
+	}
+	return blk.Bytes, nil
+}
+
+func (g *gcpKMS) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"digest": map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+	}
+	var resp struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := g.do(ctx, http.MethodPost, g.name+":asymmetricSign", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// fakeKMS signs like a cloud KMS: plain DER-encoded ECDSA, with no recovery
+// ID and no low-S guarantee.
+type fakeKMS struct {
+	sk    *ecdsa.PrivateKey
+	highS bool
+}
+
+func (f *fakeKMS) publicKey(context.Context) ([]byte, error) {
+	pub := f.sk.PublicKey
+	point := make([]byte, 65)
+	point[0] = 0x04
+	pub.X.FillBytes(point[1:33])
+	pub.Y.FillBytes(point[33:])
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+func (f *fakeKMS) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, f.sk, digest)
+	if err != nil {
+		return nil, err
+	}
+	if f.highS == (s.Cmp(secp256k1HalfN) <= 0) {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestKMSSigner(t *testing.T) {
+	t.Parallel()
+
+	soft, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := soft.Addresses()[0]
+	hash := hashing.ComputeHash256([]byte("tx"))
+
+	for _, highS := range []bool{false, true} {
+		k, err := newKMSSigner(context.Background(), &fakeKMS{sk: soft.Key().ToECDSA(), highS: highS})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.Addresses()[0] != addr {
+			t.Fatalf("expected address %s, got %s", addr, k.Addresses()[0])
+		}
+		sigs, err := k.SignHash(context.Background(), hash, []ids.ShortID{addr, addr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sigs) != 2 {
+			t.Fatalf("expected 2 signatures, got %d", len(sigs))
+		}
+		pk, err := keyFactory.RecoverHashPublicKey(hash, sigs[0][:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pk.Address() != addr {
+			t.Fatalf("signature recovers to %s, expected %s", pk.Address(), addr)
+		}
+	}
+
+	k, err := newKMSSigner(context.Background(), &fakeKMS{sk: soft.Key().ToECDSA()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.SignHash(context.Background(), hash, []ids.ShortID{ids.GenerateTestShortID()}); !errors.Is(err, ErrCantSign) {
+		t.Fatalf("expected %v, got %v", ErrCantSign, err)
+	}
+
+	// the KMS call runs under the context of the caller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := k.SignHash(ctx, hash, []ids.ShortID{addr}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
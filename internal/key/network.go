@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+var ErrInvalidAddressHRP = errors.New("invalid address HRP")
+
+const networkLabelSuffix = ".network"
+
+// NetworkLabelPath returns the path of the network label file that is
+// stored next to the key file at [keyPath].
+func NetworkLabelPath(keyPath string) string {
+	return keyPath + networkLabelSuffix
+}
+
+// SaveNetworkLabel records the network ID that the key at [keyPath] was
+// created for.
+func SaveNetworkLabel(keyPath string, networkID uint32) error {
+	b := []byte(strconv.FormatUint(uint64(networkID), 10))
+	return ioutil.WriteFile(NetworkLabelPath(keyPath), b, fsModeWrite)
+}
+
+// LoadNetworkLabel returns the network ID that the key at [keyPath] was
+// labeled with. It returns false if the key was never labeled.
+func LoadNetworkLabel(keyPath string) (uint32, bool, error) {
+	b, err := ioutil.ReadFile(NetworkLabelPath(keyPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	networkID, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid network label %q: %w", NetworkLabelPath(keyPath), err)
+	}
+	return uint32(networkID), true, nil
+}
+
+// FormatPAddress formats [addr] as a P-Chain bech32 address with the HRP of
+// [networkID].
+func FormatPAddress(networkID uint32, addr ids.ShortID) (string, error) {
+	return formatting.FormatAddress("P", getHRP(networkID), addr[:])
+}
+
+// FormatXAddress formats [addr] as an X-Chain bech32 address with the HRP of
+// [networkID].
+func FormatXAddress(networkID uint32, addr ids.ShortID) (string, error) {
+	return formatting.FormatAddress("X", getHRP(networkID), addr[:])
+}
+
+// ParsePAddress parses a P-Chain bech32 address and verifies that its HRP
+// matches [networkID].
+func ParsePAddress(networkID uint32, addr string) (ids.ShortID, error) {
+	_, hrp, b, err := formatting.ParseAddress(addr)
+	if err != nil {
+		return ids.ShortEmpty, err
+	}
+	if expected := getHRP(networkID); hrp != expected {
+		return ids.ShortEmpty, fmt.Errorf("%w: %q (expected %q)", ErrInvalidAddressHRP, hrp, expected)
+	}
+	return ids.ToShortID(b)
+}
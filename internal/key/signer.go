@@ -0,0 +1,208 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+// Signer is a signing backend (e.g., software key, ledger, remote KMS). It
+// only ever sees tx hashes; UTXO selection and credential assembly are
+// shared by [SignerKey].
+type Signer interface {
+	// Addresses returns the addresses the signer holds the keys of.
+	Addresses() []ids.ShortID
+	// SignHash returns the recoverable secp256k1 signature of [hash] by the
+	// key of each of [addrs], in order. Remote backends give up once [ctx]
+	// is done.
+	SignHash(ctx context.Context, hash []byte, addrs []ids.ShortID) ([][crypto.SECP256K1RSigLen]byte, error)
+}
+
+var _ Key = &SignerKey{}
+
+// SignerKey implements [Key] on top of any [Signer].
+type SignerKey struct {
+	s Signer
+
+	pAddrs  []string
+	addrs   []ids.ShortID
+	addrMap map[ids.ShortID]struct{}
+}
+
+// NewSignerKey returns a key that spends the UTXOs owned by the addresses of
+// [s] and signs via [s].
+func NewSignerKey(networkID uint32, s Signer) (*SignerKey, error) {
+	hrp := getHRP(networkID)
+	addrs := s.Addresses()
+	k := &SignerKey{
+		s:       s,
+		pAddrs:  make([]string, len(addrs)),
+		addrs:   addrs,
+		addrMap: make(map[ids.ShortID]struct{}, len(addrs)),
+	}
+	for i, addr := range addrs {
+		pAddr, err := formatting.FormatAddress("P", hrp, addr[:])
+		if err != nil {
+			return nil, err
+		}
+		k.pAddrs[i] = pAddr
+		k.addrMap[addr] = struct{}{}
+	}
+	return k, nil
+}
+
+// Signer returns the underlying signing backend.
+func (k *SignerKey) Signer() Signer { return k.s }
+
+func (k *SignerKey) P() []string { return k.pAddrs }
+
+func (k *SignerKey) Addresses() []ids.ShortID { return k.addrs }
+
+func (k *SignerKey) Match(owners *secp256k1fx.OutputOwners, time uint64) ([]uint32, []ids.ShortID, bool) {
+	if time < owners.Locktime {
+		return nil, nil, false
+	}
+	sigs := make([]uint32, 0, owners.Threshold)
+	signers := make([]ids.ShortID, 0, owners.Threshold)
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(sigs)) < owners.Threshold; i++ {
+		if _, ok := k.addrMap[owners.Addrs[i]]; ok {
+			sigs = append(sigs, i)
+			signers = append(signers, owners.Addrs[i])
+		}
+	}
+	return sigs, signers, uint32(len(sigs)) == owners.Threshold
+}
+
+func (k *SignerKey) Spends(outputs []*avax.UTXO, opts ...OpOption) (
+	totalBalanceToSpend uint64,
+	inputs []*avax.TransferableInput,
+	signers [][]ids.ShortID,
+) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	for _, out := range outputs {
+		input, txsigners, err := k.spend(out.Out, ret.time)
+		if err != nil {
+			zap.L().Warn("cannot spend with current key", zap.Error(err))
+			continue
+		}
+		totalBalanceToSpend += input.Amount()
+		inputs = append(inputs, &avax.TransferableInput{
+			UTXOID: out.UTXOID,
+			Asset:  out.Asset,
+			In:     input,
+		})
+		signers = append(signers, txsigners)
+		if ret.targetAmount > 0 &&
+			totalBalanceToSpend > ret.targetAmount+ret.feeDeduct {
+			break
+		}
+	}
+	SortTransferableInputsWithSigners(inputs, signers)
+	return totalBalanceToSpend, inputs, signers
+}
+
+func (k *SignerKey) spend(out verify.Verifiable, time uint64) (avax.TransferableIn, []ids.ShortID, error) {
+	switch out := out.(type) {
+	case *secp256k1fx.MintOutput:
+		// mint outputs can't be spent as transferable inputs
+		return nil, nil, ErrCantSpend
+	case *secp256k1fx.TransferOutput:
+		if sigIndices, signers, able := k.Match(&out.OutputOwners, time); able {
+			return &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: sigIndices,
+				},
+			}, signers, nil
+		}
+		return nil, nil, ErrCantSpend
+	}
+	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
+}
+
+// Sign signs the tx hash once per unique signer and attaches the credentials.
+//
+// This is a slightly modified version of *platformvm.Tx.Sign().
+func (k *SignerKey) Sign(ctx context.Context, pTx *platformvm.Tx, signers [][]ids.ShortID) error {
+	unsignedBytes, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, &pTx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal UnsignedTx: %w", err)
+	}
+	creds, err := credentials(ctx, k.s, hashing.ComputeHash256(unsignedBytes), signers)
+	if err != nil {
+		return err
+	}
+	for _, cred := range creds {
+		pTx.Creds = append(pTx.Creds, cred)
+	}
+
+	// Create signed tx bytes
+	signedBytes, err := codec.PCodecManager.Marshal(platformvm.CodecVersion, pTx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal ProposalTx: %w", err)
+	}
+	pTx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+// credentials signs [hash] via [s] once per unique signer and returns the
+// credential of each input, in the order of [signers].
+func credentials(ctx context.Context, s Signer, hash []byte, signers [][]ids.ShortID) ([]*secp256k1fx.Credential, error) {
+	owned := map[ids.ShortID]struct{}{}
+	for _, addr := range s.Addresses() {
+		owned[addr] = struct{}{}
+	}
+	unique := []ids.ShortID{}
+	seen := map[ids.ShortID]struct{}{}
+	for _, inputSigners := range signers {
+		for _, signer := range inputSigners {
+			if _, ok := owned[signer]; !ok {
+				// Should never happen
+				return nil, ErrCantSpend
+			}
+			if _, ok := seen[signer]; ok {
+				continue
+			}
+			seen[signer] = struct{}{}
+			unique = append(unique, signer)
+		}
+	}
+	sigs, err := s.SignHash(ctx, hash, unique)
+	if err != nil {
+		return nil, fmt.Errorf("problem generating signatures: %w", err)
+	}
+	if len(sigs) != len(unique) {
+		return nil, fmt.Errorf("%w: expected %d signatures, got %d", ErrCantSign, len(unique), len(sigs))
+	}
+	sigMap := make(map[ids.ShortID][crypto.SECP256K1RSigLen]byte, len(unique))
+	for i, addr := range unique {
+		sigMap[addr] = sigs[i]
+	}
+
+	creds := make([]*secp256k1fx.Credential, len(signers))
+	for i, inputSigners := range signers {
+		creds[i] = &secp256k1fx.Credential{
+			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(inputSigners)),
+		}
+		for j, signer := range inputSigners {
+			creds[i].Sigs[j] = sigMap[signer]
+		}
+	}
+	return creds, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestSignerKey(t *testing.T) {
+	t.Parallel()
+
+	soft, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewSignerKey(fallbackNetworkID, soft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.P()[0] != ewoqPChainAddr {
+		t.Fatalf("unexpected P-Chain address %q, expected %q", k.P()[0], ewoqPChainAddr)
+	}
+
+	addr := soft.Addresses()[0]
+	tt := []struct {
+		owners  secp256k1fx.OutputOwners
+		time    uint64
+		expSigs []uint32
+		expOK   bool
+	}{
+		{owners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{addr}}, expSigs: []uint32{0}, expOK: true},
+		{owners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID(), addr}}, expSigs: []uint32{1}, expOK: true},
+		{owners: secp256k1fx.OutputOwners{Threshold: 2, Addrs: []ids.ShortID{ids.GenerateTestShortID(), addr}}, expOK: false},
+		{owners: secp256k1fx.OutputOwners{Threshold: 1, Locktime: 10, Addrs: []ids.ShortID{addr}}, time: 5, expOK: false},
+	}
+	for i, tv := range tt {
+		sigs, _, ok := k.Match(&tv.owners, tv.time)
+		if ok != tv.expOK {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expOK, ok)
+		}
+		if !ok {
+			continue
+		}
+		if len(sigs) != len(tv.expSigs) || sigs[0] != tv.expSigs[0] {
+			t.Fatalf("#%d: expected sig indices %v, got %v", i, tv.expSigs, sigs)
+		}
+	}
+
+	// signing through the generic key must match the software key
+	newTx := func() *platformvm.Tx {
+		return &platformvm.Tx{UnsignedTx: &platformvm.UnsignedCreateSubnetTx{
+			Owner: &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{addr}},
+		}}
+	}
+	signers := [][]ids.ShortID{{addr}, {addr}}
+	exp, got := newTx(), newTx()
+	if err := soft.Sign(context.Background(), exp, signers); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Sign(context.Background(), got, signers); err != nil {
+		t.Fatal(err)
+	}
+	if exp.ID() != got.ID() {
+		t.Fatalf("expected tx %s, got %s", exp.ID(), got.ID())
+	}
+
+	if err := k.Sign(context.Background(), newTx(), [][]ids.ShortID{{ids.GenerateTestShortID()}}); !errors.Is(err, ErrCantSpend) {
+		t.Fatalf("expected %v, got %v", ErrCantSpend, err)
+	}
+}
@@ -6,13 +6,16 @@ package key
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
 
 	"github.com/ava-labs/subnet-cli/internal/codec"
+	"github.com/ava-labs/subnet-cli/internal/redact"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto"
@@ -30,7 +33,10 @@ var (
 	ErrInvalidPrivateKeyEncoding = errors.New("invalid private key encoding")
 )
 
-var _ Key = &SoftKey{}
+var (
+	_ Key    = &SoftKey{}
+	_ Signer = &SoftKey{}
+)
 
 type SoftKey struct {
 	privKey        *crypto.PrivateKeySECP256K1R
@@ -122,6 +128,8 @@ func NewSoft(networkID uint32, opts ...SOpOption) (*SoftKey, error) {
 		return nil, ErrInvalidPrivateKeyEncoding
 	}
 
+	redact.Register(privKeyEncoded, hex.EncodeToString(privKey.Bytes()))
+
 	keyChain := secp256k1fx.NewKeychain()
 	keyChain.Add(privKey)
 
@@ -185,6 +193,28 @@ func LoadSoft(networkID uint32, keyPath string) (*SoftKey, error) {
 	return NewSoft(networkID, WithPrivateKey(privKey))
 }
 
+// ParseSoft creates the SoftKey of a "PrivateKey-..." (CB58) or
+// hex-encoded private key, as passed by value rather than in a key file.
+func ParseSoft(networkID uint32, secret string) (*SoftKey, error) {
+	secret = strings.TrimSpace(secret)
+	if strings.HasPrefix(secret, privKeyEncPfx) {
+		return NewSoft(networkID, WithPrivateKeyEncoded(secret))
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(secret, "0x"))
+	if err != nil || len(b) != privKeySize/2 {
+		return nil, ErrInvalidPrivateKeyEncoding
+	}
+	rpk, err := keyFactory.ToPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	privKey, ok := rpk.(*crypto.PrivateKeySECP256K1R)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return NewSoft(networkID, WithPrivateKey(privKey))
+}
+
 // readASCII reads into 'buf', stopping when the buffer is full or
 // when a non-printable control character is encountered.
 func readASCII(buf []byte, r io.ByteReader) (n int, err error) {
@@ -328,7 +358,7 @@ func (m *SoftKey) Addresses() []ids.ShortID {
 	return []ids.ShortID{m.privKey.PublicKey().Address()}
 }
 
-func (m *SoftKey) Sign(pTx *platformvm.Tx, signers [][]ids.ShortID) error {
+func (m *SoftKey) Sign(_ context.Context, pTx *platformvm.Tx, signers [][]ids.ShortID) error {
 	privsigners := make([][]*crypto.PrivateKeySECP256K1R, len(signers))
 	for i, inputSigners := range signers {
 		privsigners[i] = make([]*crypto.PrivateKeySECP256K1R, len(inputSigners))
@@ -344,6 +374,23 @@ func (m *SoftKey) Sign(pTx *platformvm.Tx, signers [][]ids.ShortID) error {
 	return pTx.Sign(codec.PCodecManager, privsigners)
 }
 
+// SignHash signs [hash] with the private key, which must own every address
+// in [addrs].
+func (m *SoftKey) SignHash(_ context.Context, hash []byte, addrs []ids.ShortID) ([][crypto.SECP256K1RSigLen]byte, error) {
+	sigs := make([][crypto.SECP256K1RSigLen]byte, len(addrs))
+	for i, addr := range addrs {
+		if addr != m.privKey.PublicKey().Address() {
+			return nil, fmt.Errorf("%w: %s not owned by key", ErrCantSign, addr)
+		}
+		sig, err := m.privKey.SignHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		copy(sigs[i][:], sig)
+	}
+	return sigs, nil
+}
+
 func (m *SoftKey) Match(owners *secp256k1fx.OutputOwners, time uint64) ([]uint32, []ids.ShortID, bool) {
 	indices, privs, ok := m.keyChain.Match(owners, time)
 	pks := make([]ids.ShortID, len(privs))
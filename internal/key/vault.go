@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/subnet-cli/internal/redact"
+)
+
+// DefaultVaultField is the secret field holding the private key, unless the
+// path selects another one with "#field".
+const DefaultVaultField = "private-key"
+
+var (
+	ErrInvalidVaultPath = errors.New("invalid vault path")
+	ErrEmptyVaultConfig = errors.New("vault address or token not set")
+
+	// Vault Transit has no secp256k1 key type, so it cannot sign P-Chain txs.
+	ErrVaultTransit = errors.New("vault transit can't hold secp256k1 keys; store the key in a KV secret")
+)
+
+// VaultConfig selects the Vault server, as the "vault" CLI does.
+type VaultConfig struct {
+	Addr      string
+	Token     string
+	Namespace string
+}
+
+// VaultConfigFromEnv reads VAULT_ADDR, VAULT_TOKEN (or ~/.vault-token) and
+// VAULT_NAMESPACE.
+func VaultConfigFromEnv() VaultConfig {
+	cfg := VaultConfig{
+		Addr:      os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+	if cfg.Token == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if b, err := ioutil.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+				cfg.Token = strings.TrimSpace(string(b))
+			}
+		}
+	}
+	return cfg
+}
+
+// LoadVault fetches the private key at [path] ("mount/path[#field]") from a
+// Vault KV secret (version 1 or 2), so that it is never written to disk.
+// For KV version 2, [path] is the API path (e.g., "secret/data/deployer").
+func LoadVault(ctx context.Context, networkID uint32, cfg VaultConfig, path string) (*SoftKey, error) {
+	if cfg.Addr == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("%w: set VAULT_ADDR and VAULT_TOKEN", ErrEmptyVaultConfig)
+	}
+	path, field := splitVaultPath(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidVaultPath)
+	}
+	if strings.HasPrefix(path, "transit/") {
+		return nil, ErrVaultTransit
+	}
+	redact.Register(cfg.Token)
+	header := http.Header{}
+	header.Set("X-Vault-Token", cfg.Token)
+	if cfg.Namespace != "" {
+		header.Set("X-Vault-Namespace", cfg.Namespace)
+	}
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	u := strings.TrimSuffix(cfg.Addr, "/") + "/v1/" + path
+	if err := doJSON(ctx, http.MethodGet, u, header, nil, &resp); err != nil {
+		return nil, fmt.Errorf("vault %q: %w", path, err)
+	}
+	data := resp.Data
+	// KV version 2 nests the secret with its metadata
+	if nested, ok := data["data"]; ok {
+		if _, meta := data["metadata"]; meta {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidVaultPath, path, err)
+			}
+		}
+	}
+	raw, ok := data[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: no field %q in %q", ErrInvalidVaultPath, field, path)
+	}
+	var secret string
+	if err := json.Unmarshal(raw, &secret); err != nil {
+		return nil, fmt.Errorf("%w: field %q of %q is not a string", ErrInvalidVaultPath, field, path)
+	}
+	return ParseSoft(networkID, secret)
+}
+
+func splitVaultPath(p string) (path string, field string) {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	field = DefaultVaultField
+	if i := strings.LastIndex(p, "#"); i >= 0 {
+		p, field = p[:i], p[i+1:]
+	}
+	return strings.TrimPrefix(p, "v1/"), field
+}
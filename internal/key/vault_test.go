@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadVault(t *testing.T) {
+	t.Parallel()
+
+	ewoq, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]string{
+		"/v1/kv/deployer":             `{"data":{"private-key":"` + EwoqPrivateKey + `"}}`,
+		"/v1/secret/data/deployer":    `{"data":{"data":{"private-key":"` + EwoqPrivateKey + `"},"metadata":{"version":3}}}`,
+		"/v1/secret/data/hex":         `{"data":{"data":{"pk":"0x` + hex.EncodeToString(ewoq.privKeyRaw) + `"},"metadata":{"version":1}}}`,
+		"/v1/secret/data/not-a-key":   `{"data":{"data":{"private-key":"hello"},"metadata":{"version":1}}}`,
+		"/v1/secret/data/not-a-field": `{"data":{"data":{"private-key":1},"metadata":{"version":1}}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		s, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(s))
+	}))
+	defer srv.Close()
+
+	tt := []struct {
+		path  string
+		token string
+		err   error
+	}{
+		{path: "kv/deployer", token: "token"},
+		{path: "/v1/secret/data/deployer", token: "token"},
+		{path: "secret/data/hex#pk", token: "token"},
+		{path: "secret/data/hex", token: "token", err: ErrInvalidVaultPath},
+		{path: "secret/data/not-a-key", token: "token", err: ErrInvalidPrivateKeyEncoding},
+		{path: "secret/data/not-a-field", token: "token", err: ErrInvalidVaultPath},
+		{path: "secret/data/missing", token: "token", err: ErrCantSign},
+		{path: "kv/deployer", token: "wrong", err: ErrCantSign},
+		{path: "kv/deployer", err: ErrEmptyVaultConfig},
+		{path: "transit/keys/deployer", token: "token", err: ErrVaultTransit},
+	}
+	for i, tv := range tt {
+		k, err := LoadVault(context.Background(), fallbackNetworkID, VaultConfig{Addr: srv.URL, Token: tv.token}, tv.path)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d (%s): expected %v, got %v", i, tv.path, tv.err, err)
+		}
+		if err == nil && k.Addresses()[0] != ewoq.Addresses()[0] {
+			t.Fatalf("#%d (%s): unexpected address %s", i, tv.path, k.Addresses()[0])
+		}
+	}
+}
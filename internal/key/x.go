@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/avm"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+// SignX signs the X-Chain [xTx] with [k] and attaches the credentials.
+// [Key.Sign] only serializes P-Chain txs, so this goes through the hash
+// signer of [k].
+//
+// This is a slightly modified version of *avm.Tx.SignSECP256K1Fx().
+func SignX(ctx context.Context, k Key, xTx *avm.Tx, signers [][]ids.ShortID) error {
+	s, err := hashSigner(k)
+	if err != nil {
+		return err
+	}
+	unsignedBytes, err := codec.XCodecManager.Marshal(codec.XCodecVersion, &xTx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal UnsignedTx: %w", err)
+	}
+	creds, err := credentials(ctx, s, hashing.ComputeHash256(unsignedBytes), signers)
+	if err != nil {
+		return err
+	}
+	for _, cred := range creds {
+		xTx.Creds = append(xTx.Creds, &avm.FxCredential{Verifiable: cred})
+	}
+	signedBytes, err := codec.XCodecManager.Marshal(codec.XCodecVersion, xTx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal Tx: %w", err)
+	}
+	xTx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+// hashSigner returns the signing backend of [k].
+func hashSigner(k Key) (Signer, error) {
+	switch s := k.(type) {
+	case *SignerKey:
+		return s.s, nil
+	case Signer:
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: %T can't sign X-Chain txs", ErrCantSign, k)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+func TestSignX(t *testing.T) {
+	t.Parallel()
+
+	soft, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewSignerKey(fallbackNetworkID, soft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := soft.Addresses()[0]
+	newTx := func() *avm.Tx {
+		return &avm.Tx{UnsignedTx: &avm.CreateAssetTx{
+			BaseTx: avm.BaseTx{BaseTx: avax.BaseTx{NetworkID: fallbackNetworkID, BlockchainID: ids.Empty}},
+			Name:   "Token",
+			Symbol: "TKN",
+		}}
+	}
+
+	// both keys must sign as the X-Chain itself does
+	exp := newTx()
+	if err := exp.SignSECP256K1Fx(codec.XCodecManager, [][]*crypto.PrivateKeySECP256K1R{{soft.Key()}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, signer := range []Key{soft, k} {
+		got := newTx()
+		if err := SignX(context.Background(), signer, got, [][]ids.ShortID{{addr}}); err != nil {
+			t.Fatal(err)
+		}
+		if exp.ID() != got.ID() {
+			t.Fatalf("%T: expected tx %s, got %s", signer, exp.ID(), got.ID())
+		}
+	}
+}
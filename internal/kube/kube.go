@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package kube generates Kubernetes manifests and Helm values for subnet
+// validator nodes.
+package kube
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ava-labs/subnet-cli/internal/node"
+)
+
+const (
+	DefaultName        = "avalanchego"
+	DefaultImage       = "avaplatform/avalanchego"
+	DefaultStorageSize = "500Gi"
+
+	httpPort    = 9650
+	stakingPort = 9651
+
+	// pluginDir is the plugin directory inside the avalanchego image (the
+	// default for releases using "build-dir").
+	pluginDir = "/avalanchego/build/plugins"
+	dataDir   = "/data"
+	// stakingDir is the default staking key location; the node generates
+	// its key there on first start, so it is persisted to keep the NodeID
+	// across restarts.
+	stakingDir = "/root/.avalanchego/staking"
+
+	// healthPath reports 200 only once every tracked chain is bootstrapped.
+	// ref. "api/health.NewGetAndPostHandler".
+	healthPath = "/ext/health"
+)
+
+var ErrInvalidReplicas = errors.New("replicas must be positive")
+
+// Config describes the validator StatefulSet.
+type Config struct {
+	Name      string
+	Namespace string
+	Replicas  int
+	Image     string
+	Version   string
+	NetworkID string
+
+	TrackSubnets []string
+
+	// VMImage is an image that contains the VM binary at VMImagePath; an
+	// init container copies it into the plugin directory as VMID.
+	VMID        ids.ID
+	VMImage     string
+	VMImagePath string
+
+	StorageSize  string
+	StorageClass string
+}
+
+func (cfg *Config) setDefaults() error {
+	if cfg.Replicas < 1 {
+		return ErrInvalidReplicas
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.Image == "" {
+		cfg.Image = DefaultImage
+	}
+	if cfg.Version == "" {
+		cfg.Version = node.DefaultVersion
+	}
+	if cfg.StorageSize == "" {
+		cfg.StorageSize = DefaultStorageSize
+	}
+	return nil
+}
+
+// args returns the avalanchego flags.
+func (cfg *Config) args() ([]string, error) {
+	args := []string{
+		fmt.Sprintf("--network-id=%s", cfg.NetworkID),
+		"--http-host=0.0.0.0",
+		fmt.Sprintf("--db-dir=%s/db", dataDir),
+		fmt.Sprintf("--log-dir=%s/logs", dataDir),
+	}
+	k, v, err := node.PluginDirArg(cfg.Version, pluginDir)
+	if err != nil {
+		return nil, err
+	}
+	if k == "plugin-dir" {
+		args = append(args, fmt.Sprintf("--%s=%s", k, v))
+	}
+	if len(cfg.TrackSubnets) > 0 {
+		tk, err := node.TrackSubnetsKey(cfg.Version)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", tk, strings.Join(cfg.TrackSubnets, ",")))
+	}
+	return args, nil
+}
+
+type object struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   metadata    `yaml:"metadata"`
+	Spec       interface{} `yaml:"spec"`
+}
+
+type metadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type port struct {
+	Name          string `yaml:"name"`
+	Port          int    `yaml:"port,omitempty"`
+	ContainerPort int    `yaml:"containerPort,omitempty"`
+}
+
+type volumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	SubPath   string `yaml:"subPath,omitempty"`
+}
+
+type container struct {
+	Name           string        `yaml:"name"`
+	Image          string        `yaml:"image"`
+	Command        []string      `yaml:"command,omitempty"`
+	Args           []string      `yaml:"args,omitempty"`
+	Ports          []port        `yaml:"ports,omitempty"`
+	VolumeMounts   []volumeMount `yaml:"volumeMounts,omitempty"`
+	ReadinessProbe *probe        `yaml:"readinessProbe,omitempty"`
+}
+
+type probe struct {
+	HTTPGet struct {
+		Path string `yaml:"path"`
+		Port int    `yaml:"port"`
+	} `yaml:"httpGet"`
+	InitialDelaySeconds int `yaml:"initialDelaySeconds"`
+	PeriodSeconds       int `yaml:"periodSeconds"`
+	FailureThreshold    int `yaml:"failureThreshold"`
+}
+
+// readinessProbe returns the probe that marks a node ready once it has
+// bootstrapped all of its chains.
+func readinessProbe() *probe {
+	p := &probe{InitialDelaySeconds: 30, PeriodSeconds: 30, FailureThreshold: 3}
+	p.HTTPGet.Path = healthPath
+	p.HTTPGet.Port = httpPort
+	return p
+}
+
+func (cfg *Config) labels() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": cfg.Name}
+}
+
+// containers returns the init containers and the avalanchego container.
+func (cfg *Config) containers() ([]container, container, error) {
+	args, err := cfg.args()
+	if err != nil {
+		return nil, container{}, err
+	}
+	image := fmt.Sprintf("%s:%s", cfg.Image, cfg.Version)
+	plugins := volumeMount{Name: "plugins", MountPath: "/plugins"}
+	inits := []container{{
+		// keep the VMs shipped with the image (e.g., the C-Chain "evm")
+		Name:         "copy-plugins",
+		Image:        image,
+		Command:      []string{"sh", "-c", fmt.Sprintf("cp -r %s/. /plugins/ 2>/dev/null || true", pluginDir)},
+		VolumeMounts: []volumeMount{plugins},
+	}}
+	if cfg.VMImage != "" {
+		inits = append(inits, container{
+			Name:         "install-vm",
+			Image:        cfg.VMImage,
+			Command:      []string{"sh", "-c", fmt.Sprintf("cp %s /plugins/%s && chmod +x /plugins/%s", cfg.VMImagePath, cfg.VMID, cfg.VMID)},
+			VolumeMounts: []volumeMount{plugins},
+		})
+	}
+	main := container{
+		Name:    "avalanchego",
+		Image:   image,
+		Command: []string{"/avalanchego/build/avalanchego"},
+		Args:    args,
+		Ports: []port{
+			{Name: "http", ContainerPort: httpPort},
+			{Name: "staking", ContainerPort: stakingPort},
+		},
+		VolumeMounts: []volumeMount{
+			{Name: "data", MountPath: dataDir},
+			{Name: "data", MountPath: stakingDir, SubPath: "staking"},
+			{Name: "plugins", MountPath: pluginDir},
+		},
+		ReadinessProbe: readinessProbe(),
+	}
+	return inits, main, nil
+}
+
+// Manifests returns the headless Service and the StatefulSet for [cfg] as
+// a multi-document YAML.
+func Manifests(cfg Config) ([]byte, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+	inits, main, err := cfg.containers()
+	if err != nil {
+		return nil, err
+	}
+	meta := metadata{Name: cfg.Name, Namespace: cfg.Namespace, Labels: cfg.labels()}
+
+	svc := object{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Metadata:   meta,
+		Spec: map[string]interface{}{
+			"clusterIP": "None",
+			"selector":  cfg.labels(),
+			"ports": []port{
+				{Name: "http", Port: httpPort},
+				{Name: "staking", Port: stakingPort},
+			},
+		},
+	}
+
+	pvc := map[string]interface{}{
+		"accessModes": []string{"ReadWriteOnce"},
+		"resources":   map[string]interface{}{"requests": map[string]string{"storage": cfg.StorageSize}},
+	}
+	if cfg.StorageClass != "" {
+		pvc["storageClassName"] = cfg.StorageClass
+	}
+	sts := object{
+		APIVersion: "apps/v1",
+		Kind:       "StatefulSet",
+		Metadata:   meta,
+		Spec: map[string]interface{}{
+			"serviceName": cfg.Name,
+			"replicas":    cfg.Replicas,
+			"selector":    map[string]interface{}{"matchLabels": cfg.labels()},
+			"template": map[string]interface{}{
+				"metadata": metadata{Name: cfg.Name, Labels: cfg.labels()},
+				"spec": map[string]interface{}{
+					"initContainers": inits,
+					"containers":     []container{main},
+					"volumes": []map[string]interface{}{
+						{"name": "plugins", "emptyDir": map[string]string{}},
+					},
+				},
+			},
+			"volumeClaimTemplates": []map[string]interface{}{{
+				"metadata": map[string]string{"name": "data"},
+				"spec":     pvc,
+			}},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	for i, o := range []object{svc, sts} {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+// HelmValues returns a values.yaml with the same settings, for charts that
+// template the avalanchego StatefulSet.
+func HelmValues(cfg Config) ([]byte, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+	inits, main, err := cfg.containers()
+	if err != nil {
+		return nil, err
+	}
+	persistence := map[string]interface{}{"enabled": true, "size": cfg.StorageSize}
+	if cfg.StorageClass != "" {
+		persistence["storageClass"] = cfg.StorageClass
+	}
+	values := yaml.MapSlice{
+		{Key: "nameOverride", Value: cfg.Name},
+		{Key: "replicaCount", Value: cfg.Replicas},
+		{Key: "image", Value: map[string]string{"repository": cfg.Image, "tag": cfg.Version}},
+		{Key: "networkID", Value: cfg.NetworkID},
+		{Key: "trackSubnets", Value: cfg.TrackSubnets},
+		{Key: "extraArgs", Value: main.Args},
+		{Key: "initContainers", Value: inits},
+		{Key: "readinessProbe", Value: main.ReadinessProbe},
+		{Key: "persistence", Value: persistence},
+		{Key: "service", Value: map[string]interface{}{"httpPort": httpPort, "stakingPort": stakingPort}},
+	}
+	return yaml.Marshal(values)
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kube
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestManifests(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		cfg    Config
+		expect []string
+		absent []string
+		err    error
+	}{
+		{
+			cfg: Config{
+				Replicas:     3,
+				NetworkID:    "fuji",
+				TrackSubnets: []string{"24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"},
+				VMID:         ids.ID{'s', 'p', 'a', 'c', 'e', 's'},
+				VMImage:      "example/spacesvm:latest",
+				VMImagePath:  "/spacesvm",
+			},
+			expect: []string{
+				"kind: Service",
+				"kind: StatefulSet",
+				"replicas: 3",
+				"--whitelisted-subnets=24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1",
+				"name: install-vm",
+				"cp /spacesvm /plugins/",
+				"path: /ext/health",
+				"storage: 500Gi",
+			},
+			absent: []string{"--plugin-dir"},
+		},
+		{
+			cfg:    Config{Replicas: 1, Version: "v1.10.0", NetworkID: "mainnet"},
+			expect: []string{"--plugin-dir=/avalanchego/build/plugins", "image: avaplatform/avalanchego:v1.10.0"},
+			absent: []string{"install-vm", "track-subnets"},
+		},
+		{
+			cfg: Config{},
+			err: ErrInvalidReplicas,
+		},
+	}
+	for i, tv := range tt {
+		b, err := Manifests(tv.cfg)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		s := string(b)
+		for _, exp := range tv.expect {
+			if !strings.Contains(s, exp) {
+				t.Fatalf("#%d: missing %q:\n%s", i, exp, s)
+			}
+		}
+		for _, exp := range tv.absent {
+			if strings.Contains(s, exp) {
+				t.Fatalf("#%d: unexpected %q:\n%s", i, exp, s)
+			}
+		}
+	}
+}
+
+func TestHelmValues(t *testing.T) {
+	t.Parallel()
+
+	b, err := HelmValues(Config{Replicas: 2, NetworkID: "fuji", StorageClass: "gp3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{"replicaCount: 2", "storageClass: gp3", "readinessProbe:"} {
+		if !strings.Contains(string(b), exp) {
+			t.Fatalf("missing %q:\n%s", exp, b)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package l1 parses the validators of sovereign L1s (ACP-77).
+package l1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+var ErrInvalidValidator = errors.New("invalid L1 validator")
+
+// Validator is an L1 validator as passed to "convert l1 --validator".
+type Validator struct {
+	NodeID    ids.ShortID
+	Weight    uint64
+	Balance   uint64
+	PublicKey [48]byte
+	PoP       [96]byte
+}
+
+// ParseValidator parses comma-separated key=value pairs (e.g.,
+// "node-id=NodeID-...,weight=100,balance=1avax,bls-public-key=0x...,bls-pop=0x..."),
+// with the balance defaulting to [defaultBalance].
+func ParseValidator(s string, defaultBalance uint64) (Validator, error) {
+	v := Validator{Balance: defaultBalance}
+	seen := map[string]bool{}
+	for _, kv := range strings.Split(s, ",") {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 {
+			return Validator{}, fmt.Errorf("%w: %q (expected key=value pairs)", ErrInvalidValidator, s)
+		}
+		k, val := strings.TrimSpace(kvs[0]), strings.TrimSpace(kvs[1])
+		var err error
+		switch k {
+		case "node-id":
+			v.NodeID, err = ids.ShortFromPrefixedString(val, constants.NodeIDPrefix)
+		case "weight":
+			v.Weight, err = strconv.ParseUint(val, 10, 64)
+		case "balance":
+			v.Balance, err = amount.Parse(val)
+		case "bls-public-key":
+			err = decodeHex(val, v.PublicKey[:])
+		case "bls-pop":
+			err = decodeHex(val, v.PoP[:])
+		default:
+			return Validator{}, fmt.Errorf("%w: %q has unknown key %q", ErrInvalidValidator, s, k)
+		}
+		if err != nil {
+			return Validator{}, fmt.Errorf("%w: %s: %v", ErrInvalidValidator, k, err)
+		}
+		seen[k] = true
+	}
+	for _, k := range []string{"node-id", "weight", "bls-public-key", "bls-pop"} {
+		if !seen[k] {
+			return Validator{}, fmt.Errorf("%w: %q needs %s", ErrInvalidValidator, s, k)
+		}
+	}
+	if v.Weight == 0 {
+		return Validator{}, fmt.Errorf("%w: %q has zero weight", ErrInvalidValidator, s)
+	}
+	return v, nil
+}
+
+func decodeHex(s string, dst []byte) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("%d bytes, expected %d", len(b), len(dst))
+	}
+	copy(dst, b)
+	return nil
+}
+
+// Codec returns [v] as an initial validator of a converted L1, whose
+// remaining balance and deactivation go to [owner].
+func (v Validator) Codec(owner ids.ShortID) *codec.ConvertSubnetToL1Validator {
+	o := codec.PChainOwner{Threshold: 1, Addresses: []ids.ShortID{owner}}
+	return &codec.ConvertSubnetToL1Validator{
+		NodeID:                v.NodeID.Bytes(),
+		Weight:                v.Weight,
+		Balance:               v.Balance,
+		Signer:                codec.ProofOfPossession{PublicKey: v.PublicKey, ProofOfPossession: v.PoP},
+		RemainingBalanceOwner: o,
+		DeactivationOwner:     o,
+	}
+}
+
+// SortValidators returns [vs] sorted by node ID, the order of the
+// validators of a ConvertSubnetToL1Tx.
+func SortValidators(vs []Validator) []Validator {
+	sorted := append([]Validator(nil), vs...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].NodeID[:], sorted[j].NodeID[:]) < 0 })
+	return sorted
+}
+
+// FormatRuntime formats how long a validator balance lasts, rounded down
+// (e.g., "~41 days").
+func FormatRuntime(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("~%d days", d/(24*time.Hour))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("~%d hours", d/time.Hour)
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("~%d minutes", d/time.Minute)
+	default:
+		return "< 2 minutes"
+	}
+}
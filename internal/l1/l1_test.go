@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package l1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestParseValidator(t *testing.T) {
+	t.Parallel()
+
+	nodeID := ids.GenerateTestShortID()
+	pk := "0x" + strings.Repeat("ab", 48)
+	pop := strings.Repeat("cd", 96)
+	base := "node-id=" + nodeID.PrefixedString("NodeID-") + ",weight=100,bls-public-key=" + pk + ",bls-pop=" + pop
+
+	tt := []struct {
+		s       string
+		balance uint64
+		weight  uint64
+		err     error
+	}{
+		{s: base, balance: 7, weight: 100},
+		{s: base + ",balance=1avax", balance: 1_000_000_000, weight: 100},
+		{s: strings.Replace(base, "weight=100", "weight=0", 1), err: ErrInvalidValidator},
+		{s: strings.Replace(base, ",bls-pop="+pop, "", 1), err: ErrInvalidValidator},
+		{s: base + ",bls-pop=0x00", err: ErrInvalidValidator},
+		{s: base + ",foo=bar", err: ErrInvalidValidator},
+		{s: base + ",balance", err: ErrInvalidValidator},
+		{s: strings.Replace(base, "NodeID-", "", 1), err: ErrInvalidValidator},
+	}
+	for i, tv := range tt {
+		v, err := ParseValidator(tv.s, 7)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: unexpected error %v, expected %v", i, err, tv.err)
+		}
+		if tv.err != nil {
+			continue
+		}
+		if v.NodeID != nodeID || v.Weight != tv.weight || v.Balance != tv.balance {
+			t.Fatalf("#%d: unexpected validator %+v", i, v)
+		}
+		if v.PublicKey[0] != 0xab || v.PoP[95] != 0xcd {
+			t.Fatalf("#%d: unexpected BLS key %x / %x", i, v.PublicKey, v.PoP)
+		}
+	}
+}
+
+func TestFormatRuntime(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		d   time.Duration
+		exp string
+	}{
+		{d: 41*24*time.Hour + 3*time.Hour, exp: "~41 days"},
+		{d: 47 * time.Hour, exp: "~47 hours"},
+		{d: 90 * time.Minute, exp: "~90 minutes"},
+		{d: time.Minute, exp: "< 2 minutes"},
+	}
+	for i, tv := range tt {
+		if s := FormatRuntime(tv.d); s != tv.exp {
+			t.Fatalf("#%d: unexpected runtime %q, expected %q", i, s, tv.exp)
+		}
+	}
+}
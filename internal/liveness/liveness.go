@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package liveness checks whether the chains of a subnet are producing
+// blocks and whether enough of its weight is responsive to keep consensus
+// going.
+package liveness
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/weights"
+)
+
+// ChainState is whether a chain is producing blocks.
+type ChainState string
+
+const (
+	// ChainUnknown is the state of a chain no validator reported the height
+	// of (e.g., neither an EVM chain nor indexed).
+	ChainUnknown     ChainState = "unknown"
+	ChainProgressing ChainState = "progressing"
+	ChainStalled     ChainState = "stalled"
+)
+
+// Probe is the last accepted height of a chain on a validator at the start
+// and at the end of the sample interval.
+type Probe struct {
+	NodeID ids.ShortID
+	Before uint64
+	After  uint64
+	// Err is why the validator did not report both heights.
+	Err error
+}
+
+// Chain is the state of a chain across the probed validators.
+type Chain struct {
+	State ChainState
+	// Height is the highest height reported.
+	Height uint64
+	// Stuck are the validators whose height did not move while the chain
+	// progressed on other validators.
+	Stuck []ids.ShortID
+}
+
+// CheckChain returns the state of a chain from its [probes]: it progresses
+// if its height grew on any validator, and stalled if it grew on none.
+func CheckChain(probes []Probe) Chain {
+	c := Chain{State: ChainUnknown, Stuck: []ids.ShortID{}}
+	reported := false
+	for _, p := range probes {
+		if p.Err != nil {
+			continue
+		}
+		reported = true
+		if p.After > c.Height {
+			c.Height = p.After
+		}
+		if p.After > p.Before {
+			c.State = ChainProgressing
+		}
+	}
+	if !reported {
+		return c
+	}
+	if c.State != ChainProgressing {
+		c.State = ChainStalled
+		return c
+	}
+	for _, p := range probes {
+		if p.Err == nil && p.After <= p.Before {
+			c.Stuck = append(c.Stuck, p.NodeID)
+		}
+	}
+	return c
+}
+
+// Weight is the responsive weight of a subnet.
+type Weight struct {
+	Responsive uint64
+	Total      uint64
+}
+
+// ResponsiveWeight returns the weight of the validators of [ws] that are
+// [responsive].
+func ResponsiveWeight(ws map[ids.ShortID]uint64, responsive map[ids.ShortID]bool) Weight {
+	w := Weight{}
+	for nodeID, v := range ws {
+		w.Total += v
+		if responsive[nodeID] {
+			w.Responsive += v
+		}
+	}
+	return w
+}
+
+// Fraction returns the fraction of the weight that is responsive.
+func (w Weight) Fraction() float64 {
+	if w.Total == 0 {
+		return 0
+	}
+	return float64(w.Responsive) / float64(w.Total)
+}
+
+// Live returns true if the responsive weight reaches
+// [weights.LivenessThreshold].
+func (w Weight) Live() bool {
+	return w.Total > 0 && float64(w.Responsive) >= weights.LivenessThreshold*float64(w.Total)
+}
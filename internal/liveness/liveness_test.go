@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package liveness
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestCheckChain(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := ids.ShortID{1}, ids.ShortID{2}, ids.ShortID{3}
+	errUnreachable := errors.New("unreachable")
+	tt := []struct {
+		probes []Probe
+		exp    Chain
+	}{
+		{
+			exp: Chain{State: ChainUnknown, Stuck: []ids.ShortID{}},
+		},
+		{
+			probes: []Probe{{NodeID: a, Err: errUnreachable}},
+			exp:    Chain{State: ChainUnknown, Stuck: []ids.ShortID{}},
+		},
+		{
+			probes: []Probe{
+				{NodeID: a, Before: 10, After: 12},
+				{NodeID: b, Before: 11, After: 13},
+				{NodeID: c, Err: errUnreachable},
+			},
+			exp: Chain{State: ChainProgressing, Height: 13, Stuck: []ids.ShortID{}},
+		},
+		{
+			probes: []Probe{
+				{NodeID: a, Before: 10, After: 10},
+				{NodeID: b, Before: 9, After: 10},
+			},
+			exp: Chain{State: ChainProgressing, Height: 10, Stuck: []ids.ShortID{a}},
+		},
+		{
+			probes: []Probe{
+				{NodeID: a, Before: 10, After: 10},
+				{NodeID: b, Before: 8, After: 8},
+			},
+			exp: Chain{State: ChainStalled, Height: 10, Stuck: []ids.ShortID{}},
+		},
+	}
+	for i, tv := range tt {
+		if c := CheckChain(tv.probes); !reflect.DeepEqual(c, tv.exp) {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.exp, c)
+		}
+	}
+}
+
+func TestResponsiveWeight(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := ids.ShortID{1}, ids.ShortID{2}, ids.ShortID{3}
+	ws := map[ids.ShortID]uint64{a: 40, b: 40, c: 20}
+	tt := []struct {
+		responsive  map[ids.ShortID]bool
+		expWeight   Weight
+		expFraction float64
+		expLive     bool
+	}{
+		{responsive: map[ids.ShortID]bool{a: true, b: true, c: true}, expWeight: Weight{Responsive: 100, Total: 100}, expFraction: 1, expLive: true},
+		{responsive: map[ids.ShortID]bool{a: true, b: true}, expWeight: Weight{Responsive: 80, Total: 100}, expFraction: 0.8, expLive: true},
+		{responsive: map[ids.ShortID]bool{a: true, b: false, c: true}, expWeight: Weight{Responsive: 60, Total: 100}, expFraction: 0.6},
+	}
+	for i, tv := range tt {
+		w := ResponsiveWeight(ws, tv.responsive)
+		if w != tv.expWeight {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.expWeight, w)
+		}
+		if f := w.Fraction(); f != tv.expFraction {
+			t.Fatalf("#%d: expected fraction %v, got %v", i, tv.expFraction, f)
+		}
+		if l := w.Live(); l != tv.expLive {
+			t.Fatalf("#%d: expected live %v, got %v", i, tv.expLive, l)
+		}
+	}
+	if (Weight{}).Live() {
+		t.Fatal("expected a subnet without weight not to be live")
+	}
+}
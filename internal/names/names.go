@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package names maps user-chosen names to subnet, blockchain and VM IDs,
+// so deployment scripts can refer to (and detect) resources by name.
+package names
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+// DefaultPath is the default registry file, next to the default key file.
+const DefaultPath = ".subnet-cli-names.json"
+
+// MemoPrefix prefixes the name embedded in the memo of a tagged tx.
+const MemoPrefix = "subnet-cli:"
+
+var (
+	ErrEmptyName    = errors.New("empty name")
+	ErrInvalidName  = errors.New("invalid name")
+	ErrNameTaken    = errors.New("name already taken")
+	ErrInvalidAlias = errors.New("invalid chain alias")
+	ErrNotNamed     = errors.New("not named")
+)
+
+// aliasRegexp matches chain aliases that are safe in API paths
+// (e.g., "/ext/bc/mychain/rpc").
+var aliasRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// Kind is the kind of a named resource.
+type Kind string
+
+const (
+	KindSubnet     Kind = "subnet"
+	KindBlockchain Kind = "blockchain"
+	// VM names are registered under [VMNetworkID].
+	KindVM Kind = "vm"
+)
+
+// Entry is a named resource on a network.
+type Entry struct {
+	NetworkID uint32 `json:"networkID"`
+	Kind      Kind   `json:"kind"`
+	Name      string `json:"name"`
+	ID        ids.ID `json:"id"`
+	// Alias is the chain alias set on the nodes, if any (blockchains only).
+	Alias   string    `json:"alias,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Registry is a names file.
+type Registry struct {
+	path    string
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads the registry at [path]; a missing file is an empty registry.
+func Load(path string) (*Registry, error) {
+	r := &Registry{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("%q: %w", path, err)
+	}
+	return r, nil
+}
+
+// Validate returns an error if [name] cannot be embedded in a tx memo.
+func Validate(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if len(MemoPrefix)+len(name) > avax.MaxMemoSize {
+		return fmt.Errorf("%w: %q is longer than %d bytes", ErrInvalidName, name, avax.MaxMemoSize-len(MemoPrefix))
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("%w: %q has leading or trailing spaces", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// ValidateAlias returns an error if [alias] cannot be used as a chain
+// alias in API paths.
+func ValidateAlias(alias string) error {
+	if !aliasRegexp.MatchString(alias) {
+		return fmt.Errorf("%w: %q (letters, digits, '-' and '_', starting with a letter)", ErrInvalidAlias, alias)
+	}
+	return nil
+}
+
+// SetAlias records the chain [alias] of the named blockchain [id].
+func (r *Registry) SetAlias(networkID uint32, id ids.ID, alias string) error {
+	if err := ValidateAlias(alias); err != nil {
+		return err
+	}
+	for i, e := range r.Entries {
+		if e.NetworkID == networkID && e.Kind == KindBlockchain && e.ID == id {
+			r.Entries[i].Alias = alias
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: blockchain %s", ErrNotNamed, id)
+}
+
+// Memo returns the tx memo tagging a resource with [name].
+func Memo(name string) []byte {
+	return []byte(MemoPrefix + name)
+}
+
+// Lookup returns the ID named [name].
+func (r *Registry) Lookup(networkID uint32, kind Kind, name string) (ids.ID, bool) {
+	for _, e := range r.Entries {
+		if e.NetworkID == networkID && e.Kind == kind && e.Name == name {
+			return e.ID, true
+		}
+	}
+	return ids.Empty, false
+}
+
+// Name returns the name of [id].
+func (r *Registry) Name(networkID uint32, kind Kind, id ids.ID) (string, bool) {
+	for _, e := range r.Entries {
+		if e.NetworkID == networkID && e.Kind == kind && e.ID == id {
+			return e.Name, true
+		}
+	}
+	return "", false
+}
+
+// Set names [id] as [name], replacing a previous name of [id]. A name
+// can only refer to one resource of each kind per network.
+func (r *Registry) Set(networkID uint32, kind Kind, name string, id ids.ID) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+	if prev, ok := r.Lookup(networkID, kind, name); ok {
+		if prev == id {
+			return nil
+		}
+		return fmt.Errorf("%w: %s %q is %s", ErrNameTaken, kind, name, prev)
+	}
+	alias := ""
+	entries := r.Entries[:0]
+	for _, e := range r.Entries {
+		if e.NetworkID == networkID && e.Kind == kind && e.ID == id {
+			alias = e.Alias
+			continue
+		}
+		entries = append(entries, e)
+	}
+	r.Entries = append(entries, Entry{
+		NetworkID: networkID,
+		Kind:      kind,
+		Name:      name,
+		ID:        id,
+		Alias:     alias,
+		Created:   time.Now().UTC(),
+	})
+	sort.SliceStable(r.Entries, func(i, j int) bool {
+		a, b := r.Entries[i], r.Entries[j]
+		if a.NetworkID != b.NetworkID {
+			return a.NetworkID < b.NetworkID
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+// Remove drops the name of [id] and returns its entry.
+func (r *Registry) Remove(networkID uint32, kind Kind, id ids.ID) (Entry, bool) {
+	for i, e := range r.Entries {
+		if e.NetworkID == networkID && e.Kind == kind && e.ID == id {
+			r.Entries = append(r.Entries[:i], r.Entries[i+1:]...)
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Save writes the registry back to its file.
+func (r *Registry) Save() error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(r.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "names.json")
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := ids.GenerateTestID(), ids.GenerateTestID()
+
+	tt := []struct {
+		networkID uint32
+		kind      Kind
+		name      string
+		id        ids.ID
+		expErr    error
+	}{
+		{networkID: 5, kind: KindSubnet, name: "prod", id: a},
+		{networkID: 5, kind: KindSubnet, name: "prod", id: a},
+		{networkID: 5, kind: KindSubnet, name: "prod", id: b, expErr: ErrNameTaken},
+		{networkID: 1, kind: KindSubnet, name: "prod", id: b},
+		{networkID: 5, kind: KindBlockchain, name: "prod", id: b},
+		{networkID: 5, kind: KindSubnet, name: "", id: b, expErr: ErrEmptyName},
+		{networkID: 5, kind: KindSubnet, name: " prod", id: b, expErr: ErrInvalidName},
+		{networkID: 5, kind: KindSubnet, name: strings.Repeat("x", 256), id: b, expErr: ErrInvalidName},
+	}
+	for i, tv := range tt {
+		if err := r.Set(tv.networkID, tv.kind, tv.name, tv.id); !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+	}
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", r.Entries)
+	}
+	if id, ok := r.Lookup(5, KindSubnet, "prod"); !ok || id != a {
+		t.Fatalf("expected %s, got %s (%v)", a, id, ok)
+	}
+	if name, ok := r.Name(1, KindSubnet, b); !ok || name != "prod" {
+		t.Fatalf("expected prod, got %q (%v)", name, ok)
+	}
+
+	// renaming replaces the previous name
+	if err := r.Set(5, KindSubnet, "staging", a); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup(5, KindSubnet, "prod"); ok {
+		t.Fatal("expected previous name to be removed")
+	}
+
+	if e, ok := r.Remove(5, KindSubnet, a); !ok || e.Name != "staging" {
+		t.Fatalf("expected staging, got %+v (%v)", e, ok)
+	}
+	if _, ok := r.Remove(5, KindSubnet, a); ok {
+		t.Fatal("expected name to be removed")
+	}
+	if len(r.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", r.Entries)
+	}
+}
+
+func TestCheckVM(t *testing.T) {
+	t.Parallel()
+
+	r, err := Load(filepath.Join(t.TempDir(), "names.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := ids.GenerateTestID(), ids.GenerateTestID()
+	if err := r.Set(VMNetworkID, KindVM, "subnetevm", a); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name   string
+		id     ids.ID
+		expErr error
+	}{
+		{name: "subnetevm", id: a},
+		{name: "timestampvm", id: b},
+		{name: "subnetevm", id: b, expErr: ErrNameTaken},
+		{name: "timestampvm", id: a, expErr: ErrVMIDTaken},
+	}
+	for i, tv := range tt {
+		if err := r.CheckVM(tv.name, tv.id); !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+	}
+}
+
+func TestSetAlias(t *testing.T) {
+	t.Parallel()
+
+	r, err := Load(filepath.Join(t.TempDir(), "names.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := ids.GenerateTestID()
+	if err := r.Set(5, KindBlockchain, "mychain", a); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		networkID uint32
+		id        ids.ID
+		alias     string
+		expErr    error
+	}{
+		{networkID: 5, id: a, alias: "mychain"},
+		{networkID: 5, id: a, alias: "my_chain-2"},
+		{networkID: 5, id: a, alias: "my/chain", expErr: ErrInvalidAlias},
+		{networkID: 5, id: a, alias: "2chain", expErr: ErrInvalidAlias},
+		{networkID: 5, id: a, alias: "", expErr: ErrInvalidAlias},
+		{networkID: 1, id: a, alias: "mychain", expErr: ErrNotNamed},
+		{networkID: 5, id: ids.GenerateTestID(), alias: "mychain", expErr: ErrNotNamed},
+	}
+	for i, tv := range tt {
+		if err := r.SetAlias(tv.networkID, tv.id, tv.alias); !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+	}
+
+	// renaming keeps the alias
+	if err := r.Set(5, KindBlockchain, "renamed", a); err != nil {
+		t.Fatal(err)
+	}
+	if r.Entries[0].Alias != "my_chain-2" {
+		t.Fatalf("expected alias to be kept, got %+v", r.Entries[0])
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// VMNetworkID is the network ID of the VM entries, since a VM ID is the
+// same on every network.
+const VMNetworkID uint32 = 0
+
+var ErrVMIDTaken = errors.New("VM ID already named")
+
+// CheckVM returns an error if [name] refers to another VM ID, or if [id]
+// is already named otherwise, so a team does not accidentally reuse a VM
+// ID for a different VM (or vice versa).
+func (r *Registry) CheckVM(name string, id ids.ID) error {
+	if prev, ok := r.Lookup(VMNetworkID, KindVM, name); ok && prev != id {
+		return fmt.Errorf("%w: VM %q is %s, not %s", ErrNameTaken, name, prev, id)
+	}
+	if prev, ok := r.Name(VMNetworkID, KindVM, id); ok && prev != name {
+		return fmt.Errorf("%w: VM ID %s is %q, not %q", ErrVMIDTaken, id, prev, name)
+	}
+	return nil
+}
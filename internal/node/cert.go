@@ -0,0 +1,30 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+var ErrInvalidCert = errors.New("invalid staking certificate")
+
+// IDFromCert derives the NodeID from a PEM-encoded staking certificate.
+//
+// ref. "node.Node.initNodeID".
+func IDFromCert(certPEM []byte) (ids.ShortID, error) {
+	blk, _ := pem.Decode(certPEM)
+	if blk == nil || blk.Type != "CERTIFICATE" {
+		return ids.ShortEmpty, ErrInvalidCert
+	}
+	cert, err := x509.ParseCertificate(blk.Bytes)
+	if err != nil {
+		return ids.ShortEmpty, err
+	}
+	return ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+}
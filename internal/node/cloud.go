@@ -0,0 +1,272 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrUnknownCloud    = errors.New("unknown cloud")
+	ErrMissingTemplate = errors.New("missing cloud template")
+	ErrNoPublicIP      = errors.New("instance has no public IP")
+)
+
+// Template describes the instances to launch, per cloud.
+//
+//	ssh-user: ubuntu
+//	aws:
+//	  region: us-east-1
+//	  instance-type: c5.2xlarge
+//	  image-id: ami-0123456789abcdef0
+//	  key-name: validators
+//	  security-group-ids: [sg-0123456789abcdef0]
+//	gcp:
+//	  project: my-project
+//	  zone: us-central1-a
+//	  machine-type: n2-standard-8
+//	  image-family: ubuntu-2004-lts
+//	  image-project: ubuntu-os-cloud
+//	  tags: [avalanchego]
+type Template struct {
+	SSHUser string       `yaml:"ssh-user,omitempty"`
+	AWS     *AWSTemplate `yaml:"aws,omitempty"`
+	GCP     *GCPTemplate `yaml:"gcp,omitempty"`
+}
+
+type AWSTemplate struct {
+	Region           string   `yaml:"region"`
+	InstanceType     string   `yaml:"instance-type"`
+	ImageID          string   `yaml:"image-id"`
+	KeyName          string   `yaml:"key-name"`
+	SecurityGroupIDs []string `yaml:"security-group-ids,omitempty"`
+	SubnetID         string   `yaml:"subnet-id,omitempty"`
+	VolumeSizeGB     int      `yaml:"volume-size-gb,omitempty"`
+}
+
+type GCPTemplate struct {
+	Project      string   `yaml:"project"`
+	Zone         string   `yaml:"zone"`
+	MachineType  string   `yaml:"machine-type"`
+	ImageFamily  string   `yaml:"image-family"`
+	ImageProject string   `yaml:"image-project"`
+	Tags         []string `yaml:"tags,omitempty"`
+	DiskSizeGB   int      `yaml:"disk-size-gb,omitempty"`
+}
+
+// LoadTemplate reads the cloud template at [p].
+func LoadTemplate(p string) (*Template, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Template)
+	if err := yaml.UnmarshalStrict(b, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Instance is a launched cloud instance.
+type Instance struct {
+	ID       string
+	PublicIP string
+}
+
+// Launcher launches instances in a cloud.
+type Launcher interface {
+	Launch(ctx context.Context, name string, count int) ([]Instance, error)
+}
+
+// NewLauncher returns the launcher for [cloud] ("aws" or "gcp"). Launchers
+// shell out to the "aws" and "gcloud" CLIs, so their credentials and
+// configuration apply.
+func NewLauncher(cloud string, t *Template) (Launcher, error) {
+	switch cloud {
+	case "aws":
+		if t.AWS == nil {
+			return nil, fmt.Errorf("%w: aws", ErrMissingTemplate)
+		}
+		return &awsLauncher{t: *t.AWS}, nil
+	case "gcp":
+		if t.GCP == nil {
+			return nil, fmt.Errorf("%w: gcp", ErrMissingTemplate)
+		}
+		return &gcpLauncher{t: *t.GCP}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCloud, cloud)
+}
+
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %s", err, name, args[0], bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+type awsLauncher struct {
+	t AWSTemplate
+}
+
+func (a *awsLauncher) runArgs(name string, count int) []string {
+	args := []string{
+		"ec2", "run-instances",
+		"--region", a.t.Region,
+		"--image-id", a.t.ImageID,
+		"--instance-type", a.t.InstanceType,
+		"--key-name", a.t.KeyName,
+		"--count", fmt.Sprint(count),
+		"--tag-specifications", fmt.Sprintf("ResourceType=instance,Tags=[{Key=Name,Value=%s}]", name),
+		"--output", "json",
+	}
+	if len(a.t.SecurityGroupIDs) > 0 {
+		args = append(args, "--security-group-ids")
+		args = append(args, a.t.SecurityGroupIDs...)
+	}
+	if a.t.SubnetID != "" {
+		args = append(args, "--subnet-id", a.t.SubnetID)
+	}
+	if a.t.VolumeSizeGB > 0 {
+		args = append(args, "--block-device-mappings", fmt.Sprintf("DeviceName=/dev/sda1,Ebs={VolumeSize=%d}", a.t.VolumeSizeGB))
+	}
+	return args
+}
+
+type awsInstance struct {
+	InstanceID      string `json:"InstanceId"`
+	PublicIPAddress string `json:"PublicIpAddress"`
+}
+
+func parseAWSRunInstances(b []byte) ([]string, error) {
+	var resp struct {
+		Instances []awsInstance `json:"Instances"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(resp.Instances))
+	for _, i := range resp.Instances {
+		ids = append(ids, i.InstanceID)
+	}
+	return ids, nil
+}
+
+func parseAWSDescribeInstances(b []byte) ([]Instance, error) {
+	var resp struct {
+		Reservations []struct {
+			Instances []awsInstance `json:"Instances"`
+		} `json:"Reservations"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	ret := []Instance{}
+	for _, r := range resp.Reservations {
+		for _, i := range r.Instances {
+			if i.PublicIPAddress == "" {
+				return nil, fmt.Errorf("%w: %s", ErrNoPublicIP, i.InstanceID)
+			}
+			ret = append(ret, Instance{ID: i.InstanceID, PublicIP: i.PublicIPAddress})
+		}
+	}
+	return ret, nil
+}
+
+func (a *awsLauncher) Launch(ctx context.Context, name string, count int) ([]Instance, error) {
+	out, err := run(ctx, "aws", a.runArgs(name, count)...)
+	if err != nil {
+		return nil, err
+	}
+	instanceIDs, err := parseAWSRunInstances(out)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"ec2", "wait", "instance-running", "--region", a.t.Region, "--instance-ids"}, instanceIDs...)
+	if _, err := run(ctx, "aws", args...); err != nil {
+		return nil, err
+	}
+	args = append([]string{"ec2", "describe-instances", "--region", a.t.Region, "--output", "json", "--instance-ids"}, instanceIDs...)
+	out, err = run(ctx, "aws", args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseAWSDescribeInstances(out)
+}
+
+type gcpLauncher struct {
+	t GCPTemplate
+}
+
+func (g *gcpLauncher) createArgs(name string, count int) []string {
+	args := []string{"compute", "instances", "create"}
+	for i := 0; i < count; i++ {
+		args = append(args, fmt.Sprintf("%s-%d", name, i))
+	}
+	args = append(args,
+		"--project", g.t.Project,
+		"--zone", g.t.Zone,
+		"--machine-type", g.t.MachineType,
+		"--image-family", g.t.ImageFamily,
+		"--image-project", g.t.ImageProject,
+		"--format", "json",
+	)
+	if len(g.t.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(g.t.Tags, ","))
+	}
+	if g.t.DiskSizeGB > 0 {
+		args = append(args, "--boot-disk-size", fmt.Sprintf("%dGB", g.t.DiskSizeGB))
+	}
+	return args
+}
+
+func parseGCPInstances(b []byte) ([]Instance, error) {
+	var resp []struct {
+		Name              string `json:"name"`
+		NetworkInterfaces []struct {
+			AccessConfigs []struct {
+				NatIP string `json:"natIP"`
+			} `json:"accessConfigs"`
+		} `json:"networkInterfaces"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	ret := make([]Instance, 0, len(resp))
+	for _, i := range resp {
+		ip := ""
+		for _, ni := range i.NetworkInterfaces {
+			for _, ac := range ni.AccessConfigs {
+				if ac.NatIP != "" && ip == "" {
+					ip = ac.NatIP
+				}
+			}
+		}
+		if ip == "" {
+			return nil, fmt.Errorf("%w: %s", ErrNoPublicIP, i.Name)
+		}
+		ret = append(ret, Instance{ID: i.Name, PublicIP: ip})
+	}
+	return ret, nil
+}
+
+func (g *gcpLauncher) Launch(ctx context.Context, name string, count int) ([]Instance, error) {
+	out, err := run(ctx, "gcloud", g.createArgs(name, count)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseGCPInstances(out)
+}
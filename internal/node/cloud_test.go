@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewLauncher(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &Template{AWS: &AWSTemplate{Region: "us-east-1"}}
+	if _, err := NewLauncher("aws", tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLauncher("gcp", tmpl); !errors.Is(err, ErrMissingTemplate) {
+		t.Fatalf("expected %v, got %v", ErrMissingTemplate, err)
+	}
+	if _, err := NewLauncher("azure", tmpl); !errors.Is(err, ErrUnknownCloud) {
+		t.Fatalf("expected %v, got %v", ErrUnknownCloud, err)
+	}
+}
+
+func TestAWSArgs(t *testing.T) {
+	t.Parallel()
+
+	a := &awsLauncher{t: AWSTemplate{
+		Region:           "us-east-1",
+		InstanceType:     "c5.2xlarge",
+		ImageID:          "ami-1",
+		KeyName:          "k",
+		SecurityGroupIDs: []string{"sg-1", "sg-2"},
+	}}
+	args := strings.Join(a.runArgs("val", 3), " ")
+	for _, exp := range []string{"--count 3", "--security-group-ids sg-1 sg-2", "Value=val"} {
+		if !strings.Contains(args, exp) {
+			t.Fatalf("%q missing %q", args, exp)
+		}
+	}
+}
+
+func TestParseAWS(t *testing.T) {
+	t.Parallel()
+
+	ids, err := parseAWSRunInstances([]byte(`{"Instances":[{"InstanceId":"i-1"},{"InstanceId":"i-2"}]}`))
+	if err != nil || len(ids) != 2 || ids[1] != "i-2" {
+		t.Fatalf("unexpected %v %v", ids, err)
+	}
+	is, err := parseAWSDescribeInstances([]byte(`{"Reservations":[{"Instances":[{"InstanceId":"i-1","PublicIpAddress":"1.2.3.4"}]}]}`))
+	if err != nil || len(is) != 1 || is[0].PublicIP != "1.2.3.4" {
+		t.Fatalf("unexpected %v %v", is, err)
+	}
+	if _, err = parseAWSDescribeInstances([]byte(`{"Reservations":[{"Instances":[{"InstanceId":"i-1"}]}]}`)); !errors.Is(err, ErrNoPublicIP) {
+		t.Fatalf("expected %v, got %v", ErrNoPublicIP, err)
+	}
+}
+
+func TestParseGCP(t *testing.T) {
+	t.Parallel()
+
+	g := &gcpLauncher{t: GCPTemplate{Project: "p", Zone: "z"}}
+	args := strings.Join(g.createArgs("val", 2), " ")
+	if !strings.Contains(args, "create val-0 val-1 --project p") {
+		t.Fatalf("unexpected args %q", args)
+	}
+	is, err := parseGCPInstances([]byte(`[{"name":"val-0","networkInterfaces":[{"accessConfigs":[{"natIP":"5.6.7.8"}]}]}]`))
+	if err != nil || len(is) != 1 || is[0].PublicIP != "5.6.7.8" || is[0].ID != "val-0" {
+		t.Fatalf("unexpected %v %v", is, err)
+	}
+}
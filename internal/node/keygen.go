@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+)
+
+const (
+	StakingCertFile = "staker.crt"
+	StakingKeyFile  = "staker.key"
+	// SignerKeyFile is the BLS secret key avalanchego loads with
+	// "--staking-signer-key-file".
+	SignerKeyFile = "signer.key"
+
+	blsSecretKeyLen = 32
+)
+
+var ErrBundleExists = errors.New("staking bundle already exists")
+
+// BLS12-381 scalar field order; a secret key is a non-zero scalar below it.
+var blsR, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+// Bundle is the staking identity of a node.
+type Bundle struct {
+	NodeID ids.ShortID
+	// Cert and Key are the PEM-encoded TLS staking certificate and key.
+	Cert []byte
+	Key  []byte
+	// SignerKey is the raw BLS secret key, if generated.
+	SignerKey []byte
+}
+
+// NewBundle generates a fresh staking certificate and key (and BLS secret
+// key if [withSigner]) so the NodeID is known before the node exists.
+func NewBundle(withSigner bool) (*Bundle, error) {
+	cert, key, err := staking.NewCertAndKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	nodeID, err := IDFromCert(cert)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{NodeID: nodeID, Cert: cert, Key: key}
+	if withSigner {
+		if b.SignerKey, err = newBLSSecretKey(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func newBLSSecretKey() ([]byte, error) {
+	for {
+		sk := make([]byte, blsSecretKeyLen)
+		if _, err := rand.Read(sk); err != nil {
+			return nil, err
+		}
+		v := new(big.Int).SetBytes(sk)
+		if v.Sign() != 0 && v.Cmp(blsR) < 0 {
+			return sk, nil
+		}
+	}
+}
+
+// Save writes the bundle files into [dir], refusing to overwrite an existing
+// identity.
+func (b *Bundle) Save(dir string) error {
+	files := map[string][]byte{
+		StakingCertFile: b.Cert,
+		StakingKeyFile:  b.Key,
+	}
+	if b.SignerKey != nil {
+		files[SignerKeyFile] = b.SignerKey
+	}
+	for name := range files {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Errorf("%w: %q", ErrBundleExists, p)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for name, data := range files {
+		mode := os.FileMode(0o600)
+		if name == StakingCertFile {
+			mode = 0o644
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
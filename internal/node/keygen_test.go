@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBundle(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.SignerKey) != blsSecretKeyLen || new(big.Int).SetBytes(b.SignerKey).Cmp(blsR) >= 0 {
+		t.Fatalf("invalid BLS secret key %x", b.SignerKey)
+	}
+
+	dir := t.TempDir()
+	if err := b.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := ioutil.ReadFile(filepath.Join(dir, StakingCertFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := IDFromCert(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeID != b.NodeID {
+		t.Fatalf("saved cert has node ID %s, expected %s", nodeID, b.NodeID)
+	}
+	if err := b.Save(dir); !errors.Is(err, ErrBundleExists) {
+		t.Fatalf("expected %v, got %v", ErrBundleExists, err)
+	}
+}
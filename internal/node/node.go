@@ -0,0 +1,189 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package node implements helpers to provision avalanchego validator nodes.
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidVersion  = errors.New("invalid avalanchego version")
+	ErrInvalidResponse = errors.New("invalid info.getNodeID response")
+)
+
+const (
+	// DefaultVersion is the avalanchego release installed by default.
+	DefaultVersion = "v1.7.6"
+
+	// HomeDir is the (remote home relative) install directory.
+	HomeDir = "avalanchego"
+	// PluginDir is where VM binaries are installed.
+	PluginDir = HomeDir + "/plugins"
+	// ConfigPath is the node config file passed via "--config-file".
+	ConfigPath = ".avalanchego/configs/node.json"
+
+	// trackSubnetsVersion is the first release that renamed
+	// "whitelisted-subnets" to "track-subnets".
+	trackSubnetsVersion = "v1.9.6"
+	// pluginDirVersion is the first release that replaced "build-dir" (whose
+	// "plugins" subdirectory holds the VMs) with "plugin-dir".
+	pluginDirVersion = "v1.9.0"
+)
+
+// parseVersion parses "vX.Y.Z" into its components.
+func parseVersion(v string) ([3]int, error) {
+	var ret [3]int
+	ss := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(ss) != 3 {
+		return ret, fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+	}
+	for i, s := range ss {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return ret, fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+		}
+		ret[i] = n
+	}
+	return ret, nil
+}
+
+// versionAtLeast returns true if [v] >= [min].
+func versionAtLeast(v string, min string) (bool, error) {
+	a, err := parseVersion(v)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseVersion(min)
+	if err != nil {
+		return false, err
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i], nil
+		}
+	}
+	return true, nil
+}
+
+// TrackSubnetsKey returns the node config key that lists the subnets to
+// track for the avalanchego [version].
+func TrackSubnetsKey(version string) (string, error) {
+	ok, err := versionAtLeast(version, trackSubnetsVersion)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return "track-subnets", nil
+	}
+	return "whitelisted-subnets", nil
+}
+
+// PluginDirArg returns the node config key and value that make the
+// avalanchego [version] load VMs from [dir], which must be named "plugins".
+func PluginDirArg(version string, dir string) (string, string, error) {
+	ok, err := versionAtLeast(version, pluginDirVersion)
+	if err != nil {
+		return "", "", err
+	}
+	if ok {
+		return "plugin-dir", dir, nil
+	}
+	return "build-dir", path.Dir(dir), nil
+}
+
+// Config returns the node config file contents for [version], with the
+// plugin directory under the remote [home].
+func Config(version string, home string, networkID string, trackSubnets []string) ([]byte, error) {
+	k, err := TrackSubnetsKey(version)
+	if err != nil {
+		return nil, err
+	}
+	pk, pv, err := PluginDirArg(version, home+"/"+PluginDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg := map[string]interface{}{
+		"network-id": networkID,
+		pk:           pv,
+	}
+	if len(trackSubnets) > 0 {
+		cfg[k] = strings.Join(trackSubnets, ",")
+	}
+	return json.MarshalIndent(cfg, "", "  ")
+}
+
+// InstallScript returns the shell script that downloads and unpacks the
+// avalanchego [version] release for linux/[arch] into [HomeDir].
+func InstallScript(version string, arch string) string {
+	url := fmt.Sprintf(
+		"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-linux-%s-%s.tar.gz",
+		version, arch, version,
+	)
+	return strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("curl -sSfL %s -o /tmp/avalanchego.tar.gz", url),
+		fmt.Sprintf("mkdir -p ~/%s ~/%s", HomeDir, PluginDir),
+		fmt.Sprintf("tar xzf /tmp/avalanchego.tar.gz -C ~/%s --strip-components=1", HomeDir),
+		"rm -f /tmp/avalanchego.tar.gz",
+		fmt.Sprintf("~/%s/avalanchego --version", HomeDir),
+	}, "\n")
+}
+
+// SystemdUnit returns a systemd unit running avalanchego as [user] from
+// [home].
+func SystemdUnit(user string, home string) string {
+	return fmt.Sprintf(`[Unit]
+Description=avalanchego
+After=network.target
+
+[Service]
+User=%s
+Type=simple
+ExecStart=%s/%s/avalanchego --config-file=%s/%s
+Restart=always
+RestartSec=5
+LimitNOFILE=32768
+
+[Install]
+WantedBy=multi-user.target
+`, user, home, HomeDir, home, ConfigPath)
+}
+
+// NodeIDRequest is the JSON-RPC body for "info.getNodeID".
+const NodeIDRequest = `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}`
+
+// ParseNodeIDResponse extracts the NodeID and, if the node has one, its
+// BLS public key from an "info.getNodeID" response.
+func ParseNodeIDResponse(b []byte) (nodeID string, blsPublicKey string, err error) {
+	var resp struct {
+		Result struct {
+			NodeID  string `json:"nodeID"`
+			NodePOP *struct {
+				PublicKey string `json:"publicKey"`
+			} `json:"nodePOP"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if resp.Error != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidResponse, resp.Error.Message)
+	}
+	if resp.Result.NodeID == "" {
+		return "", "", fmt.Errorf("%w: empty node ID", ErrInvalidResponse)
+	}
+	if resp.Result.NodePOP != nil {
+		blsPublicKey = resp.Result.NodePOP.PublicKey
+	}
+	return resp.Result.NodeID, blsPublicKey, nil
+}
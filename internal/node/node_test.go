@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTrackSubnetsKey(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		version string
+		exp     string
+		err     error
+	}{
+		{version: "v1.7.6", exp: "whitelisted-subnets"},
+		{version: "v1.9.5", exp: "whitelisted-subnets"},
+		{version: "v1.9.6", exp: "track-subnets"},
+		{version: "v1.11.0", exp: "track-subnets"},
+		{version: "latest", err: ErrInvalidVersion},
+	}
+	for i, tv := range tt {
+		k, err := TrackSubnetsKey(tv.version)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		if k != tv.exp {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.exp, k)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	b, err := Config("v1.7.6", "/home/ubuntu", "fuji", []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := map[string]string{}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["whitelisted-subnets"] != "a,b" {
+		t.Fatalf("unexpected subnets %q", cfg["whitelisted-subnets"])
+	}
+	if cfg["build-dir"] != "/home/ubuntu/avalanchego" {
+		t.Fatalf("unexpected build dir %q", cfg["build-dir"])
+	}
+
+	b, err = Config("v1.10.0", "/home/ubuntu", "fuji", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = map[string]string{}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["plugin-dir"] != "/home/ubuntu/avalanchego/plugins" {
+		t.Fatalf("unexpected plugin dir %q", cfg["plugin-dir"])
+	}
+}
+
+func TestParseNodeIDResponse(t *testing.T) {
+	t.Parallel()
+
+	nodeID, bls, err := ParseNodeIDResponse([]byte(`{"jsonrpc":"2.0","result":{"nodeID":"NodeID-5mb46qkSBj81k9g9e4VFjGGSbaaSLFRzD","nodePOP":{"publicKey":"0x8f95","proofOfPossession":"0x86a3"}},"id":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeID != "NodeID-5mb46qkSBj81k9g9e4VFjGGSbaaSLFRzD" || bls != "0x8f95" {
+		t.Fatalf("unexpected %q %q", nodeID, bls)
+	}
+
+	_, bls, err = ParseNodeIDResponse([]byte(`{"jsonrpc":"2.0","result":{"nodeID":"NodeID-5mb46qkSBj81k9g9e4VFjGGSbaaSLFRzD"},"id":1}`))
+	if err != nil || bls != "" {
+		t.Fatalf("unexpected %q %v", bls, err)
+	}
+
+	if _, _, err = ParseNodeIDResponse([]byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"not ready"},"id":1}`)); !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected %v, got %v", ErrInvalidResponse, err)
+	}
+}
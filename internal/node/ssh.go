@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSH runs commands on a remote host.
+type SSH struct {
+	host string
+	cli  *ssh.Client
+}
+
+type Op struct {
+	insecureHostKey bool
+	timeout         time.Duration
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithInsecureHostKey skips host key verification against
+// "~/.ssh/known_hosts".
+func WithInsecureHostKey(b bool) OpOption {
+	return func(op *Op) {
+		op.insecureHostKey = b
+	}
+}
+
+func WithTimeout(d time.Duration) OpOption {
+	return func(op *Op) {
+		op.timeout = d
+	}
+}
+
+// DialSSH connects to [host] ("host" or "host:port") as [user] with the
+// private key at [keyPath].
+func DialSSH(host string, user string, keyPath string, opts ...OpOption) (*SSH, error) {
+	ret := &Op{timeout: 30 * time.Second}
+	ret.applyOpts(opts)
+
+	pem, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey() //nolint:gosec
+	if !ret.insecureHostKey {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		hostKeyCallback, err = knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	cli, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         ret.timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SSH{host: host, cli: cli}, nil
+}
+
+// Run runs [cmd] in a remote shell and returns its stdout.
+func (s *SSH) Run(cmd string) ([]byte, error) {
+	sess, err := s.cli.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	var stdout, stderr bytes.Buffer
+	sess.Stdout = &stdout
+	sess.Stderr = &stderr
+	if err := sess.Run(cmd); err != nil {
+		return nil, fmt.Errorf("%w: %s on %s: %s", err, cmd, s.host, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Upload writes [data] to the remote [path] with [mode].
+func (s *SSH) Upload(path string, data []byte, mode os.FileMode) error {
+	sess, err := s.cli.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	var stderr bytes.Buffer
+	sess.Stdin = bytes.NewReader(data)
+	sess.Stderr = &stderr
+	cmd := fmt.Sprintf("mkdir -p \"$(dirname %s)\" && cat > %s && chmod %o %s", path, path, mode, path)
+	if err := sess.Run(cmd); err != nil {
+		return fmt.Errorf("%w: upload %s to %s: %s", err, path, s.host, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+func (s *SSH) Close() error { return s.cli.Close() }
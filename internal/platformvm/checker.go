@@ -8,12 +8,15 @@ package platformvm
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/subnet-cli/internal/bootstrap"
 	"github.com/ava-labs/subnet-cli/internal/poll"
 	"go.uber.org/zap"
 )
@@ -24,6 +27,10 @@ var (
 	ErrAbortedDropped         = errors.New("aborted/dropped")
 )
 
+// ExpectedCommitTime is how long a P-Chain tx typically takes to be
+// committed once issued.
+const ExpectedCommitTime = 2 * time.Second
+
 type Checker interface {
 	PollTx(ctx context.Context, txID ids.ID, s pstatus.Status) (time.Duration, error)
 	PollSubnet(ctx context.Context, subnetID ids.ID) (time.Duration, error)
@@ -49,7 +56,12 @@ func (c *checker) PollTx(ctx context.Context, txID ids.ID, s pstatus.Status) (ti
 		zap.String("txId", txID.String()),
 		zap.String("expectedStatus", s.String()),
 	)
-	return c.poller.Poll(ctx, func() (done bool, err error) {
+	expected := time.Duration(0)
+	if s == pstatus.Committed {
+		expected = ExpectedCommitTime
+	}
+	pctx := poll.WithPhase(ctx, fmt.Sprintf("tx %s %s", txID, strings.ToLower(s.String())), expected)
+	return c.poller.Poll(pctx, func() (done bool, err error) {
 		status, err := c.cli.GetTxStatus(ctx, txID, true)
 		if err != nil {
 			return false, err
@@ -60,12 +72,25 @@ func (c *checker) PollTx(ctx context.Context, txID ids.ID, s pstatus.Status) (ti
 		)
 		if s == pstatus.Committed &&
 			(status.Status == pstatus.Aborted || status.Status == pstatus.Dropped) {
-			return true, ErrAbortedDropped
+			return false, poll.Fatal(droppedError(txID, status.Status, status.Reason))
 		}
 		return status.Status == s, nil
 	})
 }
 
+// droppedError describes why the tx was not committed, with guidance for
+// common reasons (see [Hint]).
+func droppedError(txID ids.ID, s pstatus.Status, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("%w: tx %s %s", ErrAbortedDropped, txID, strings.ToLower(s.String()))
+	}
+	err := fmt.Errorf("%w: tx %s %s: %s", ErrAbortedDropped, txID, strings.ToLower(s.String()), reason)
+	if hint := Hint(reason); hint != "" {
+		err = fmt.Errorf("%w (%s)", err, hint)
+	}
+	return err
+}
+
 func (c *checker) PollSubnet(ctx context.Context, subnetID ids.ID) (took time.Duration, err error) {
 	if subnetID == ids.Empty {
 		return took, ErrEmptyID
@@ -88,7 +113,8 @@ func (c *checker) findSubnet(ctx context.Context, subnetID ids.ID) (took time.Du
 	zap.L().Info("finding subnets",
 		zap.String("subnetId", subnetID.String()),
 	)
-	took, err = c.poller.Poll(ctx, func() (done bool, err error) {
+	pctx := poll.WithPhase(ctx, fmt.Sprintf("subnet %s listed", subnetID), 0)
+	took, err = c.poller.Poll(pctx, func() (done bool, err error) {
 		ss, err := c.cli.GetSubnets(ctx, []ids.ID{subnetID})
 		if err != nil {
 			return false, err
@@ -136,9 +162,15 @@ func (c *checker) PollBlockchain(ctx context.Context, opts ...OpOption) (took ti
 		return took, err
 	}
 
+	phase := fmt.Sprintf("blockchain %s %s", ret.blockchainID, strings.ToLower(ret.blockchainStatus.String()))
+	if ret.checkBlockchainBootstrapped {
+		phase += " and bootstrapped"
+	}
 	statusPolled := false
+	est := bootstrap.NewEstimator(bootstrap.DefaultWindow)
+	pctx, setStatus := poll.WithStatus(poll.WithPhase(ctx, phase, 0))
 	prev = took
-	took, err = c.poller.Poll(ctx, func() (done bool, err error) {
+	took, err = c.poller.Poll(pctx, func() (done bool, err error) {
 		if !statusPolled {
 			status, err := c.cli.GetBlockchainStatus(ctx, ret.blockchainID.String())
 			if err != nil {
@@ -162,6 +194,7 @@ func (c *checker) PollBlockchain(ctx context.Context, opts ...OpOption) (took ti
 		}
 		if !bootstrapped {
 			zap.L().Debug("blockchain not bootstrapped yet; retrying")
+			sampleBootstrap(ctx, ret, est, setStatus)
 			return false, nil
 		}
 		return true, nil
@@ -170,11 +203,43 @@ func (c *checker) PollBlockchain(ctx context.Context, opts ...OpOption) (took ti
 	return took, err
 }
 
+// sampleBootstrap records the last accepted height of the bootstrapping
+// blockchain, if [ret.bootstrapHeight] is set, and shows the rate and ETA.
+func sampleBootstrap(ctx context.Context, ret *Op, est *bootstrap.Estimator, setStatus func(string)) {
+	if ret.bootstrapHeight == nil {
+		return
+	}
+	height, err := ret.bootstrapHeight(ctx)
+	if err != nil {
+		// e.g., the VM does not serve its API until bootstrapped
+		zap.L().Debug("failed to fetch bootstrap height", zap.Error(err))
+		return
+	}
+	est.Add(time.Now(), height)
+	if ret.bootstrapTarget != nil {
+		target, err := ret.bootstrapTarget(ctx)
+		if err != nil {
+			zap.L().Debug("failed to fetch bootstrap target height", zap.Error(err))
+		} else {
+			est.SetTarget(target)
+		}
+	}
+	e, _ := est.Estimate()
+	zap.L().Info("bootstrapping",
+		zap.Uint64("height", e.Height),
+		zap.Uint64("target", e.Target),
+		zap.Float64("blocksPerSecond", e.Rate),
+		zap.Duration("eta", e.ETA),
+	)
+	setStatus(e.String())
+}
+
 func (c *checker) findBlockchain(ctx context.Context, subnetID ids.ID) (bchID ids.ID, took time.Duration, err error) {
 	zap.L().Info("finding blockchains",
 		zap.String("subnetId", subnetID.String()),
 	)
-	took, err = c.poller.Poll(ctx, func() (done bool, err error) {
+	pctx := poll.WithPhase(ctx, fmt.Sprintf("blockchain of subnet %s listed", subnetID), 0)
+	took, err = c.poller.Poll(pctx, func() (done bool, err error) {
 		bcs, err := c.cli.GetBlockchains(ctx)
 		if err != nil {
 			return false, err
@@ -199,6 +264,9 @@ type Op struct {
 
 	info                        info.Client
 	checkBlockchainBootstrapped bool
+
+	bootstrapHeight func(context.Context) (uint64, error)
+	bootstrapTarget func(context.Context) (uint64, error)
 }
 
 type OpOption func(*Op)
@@ -235,3 +303,14 @@ func WithCheckBlockchainBootstrapped(info info.Client) OpOption {
 		op.checkBlockchainBootstrapped = true
 	}
 }
+
+// WithBootstrapHeights samples the last accepted height of the blockchain
+// with [height] while it bootstraps (see [WithCheckBlockchainBootstrapped])
+// and the height it catches up to with [target] (nil if unknown), to show
+// the rate and an ETA.
+func WithBootstrapHeights(height, target func(context.Context) (uint64, error)) OpOption {
+	return func(op *Op) {
+		op.bootstrapHeight = height
+		op.bootstrapTarget = target
+	}
+}
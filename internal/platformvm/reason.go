@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import "strings"
+
+// hints map substrings of the reasons the node reports for dropped txs
+// (ref. "platformvm.getTxStatus" with "includeReason") to guidance.
+var hints = []struct {
+	substrs []string
+	hint    string
+}{
+	{
+		substrs: []string{"insufficient funds", "no spendable funds", "failed to verify transfer"},
+		hint:    "fund the key's P-Chain address to cover the fee (and stake), e.g., with an export from the X-Chain or C-Chain",
+	},
+	{
+		substrs: []string{"already validating subnet", "already a primary network validator"},
+		hint:    "the node already validates (or will validate) it; check \"subnet-cli status\" and pick another node",
+	},
+	{
+		substrs: startTimeExpiredReasons,
+		hint:    "the validation start time passed before the tx was accepted; retry with a later --validate-start (e.g., now+5m)",
+	},
+	{
+		substrs: []string{"more than", "start time is too far in the future"},
+		hint:    "the validation start time is too far ahead of the chain time; retry with an earlier --validate-start",
+	},
+	{
+		substrs: []string{"must be a subset of the primary network"},
+		hint:    "the subnet validation period must be within the node's primary network validation period; adjust --validate-start/--validate-end",
+	},
+	{
+		substrs: []string{"staking period is too short", "staking period is too long"},
+		hint:    "adjust --validate-start/--validate-end to a staking period the network allows",
+	},
+	{
+		substrs: []string{"weight of this validator is too"},
+		hint:    "adjust the stake amount or --validate-weight to the range the network allows",
+	},
+}
+
+// reasons of txs whose validation start time passed before acceptance
+var startTimeExpiredReasons = []string{"start time is before the current chain time", "would have already unstaked"}
+
+// StartTimeExpired returns true if [err] (a drop or an issuance failure)
+// is caused by a validation start time that passed before the tx was
+// accepted, so the tx can be re-issued with a later start time.
+func StartTimeExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range startTimeExpiredReasons {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hint returns actionable guidance for the drop [reason] of a tx, or an
+// empty string if the reason is not a common one.
+func Hint(reason string) string {
+	reason = strings.ToLower(reason)
+	for _, h := range hints {
+		for _, s := range h.substrs {
+			if strings.Contains(reason, s) {
+				return h.hint
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	pstatus "github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+func TestHint(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		reason string
+		exp    string
+	}{
+		{reason: "failed to verify transfer: insufficient funds", exp: "fund the key's P-Chain address"},
+		{reason: "NodeID-abc is already a primary network validator", exp: "already validates"},
+		{reason: "start time is before the current chain time", exp: "later --validate-start"},
+		{reason: "staker is attempting to start staking more than 336h0m0s ahead of the current chain time", exp: "earlier --validate-start"},
+		{reason: "all subnets' staking period must be a subset of the primary network", exp: "primary network validation period"},
+		{reason: "something else", exp: ""},
+		{reason: "", exp: ""},
+	}
+	for i, tv := range tt {
+		hint := Hint(tv.reason)
+		if tv.exp == "" {
+			if hint != "" {
+				t.Fatalf("#%d: unexpected hint %q", i, hint)
+			}
+			continue
+		}
+		if !strings.Contains(hint, tv.exp) {
+			t.Fatalf("#%d: expected %q in hint, got %q", i, tv.exp, hint)
+		}
+	}
+}
+
+func TestStartTimeExpired(t *testing.T) {
+	t.Parallel()
+
+	txID := ids.GenerateTestID()
+	tt := []struct {
+		err error
+		exp bool
+	}{
+		{err: droppedError(txID, pstatus.Dropped, "failed to verify: start time is before the current chain time"), exp: true},
+		{err: fmt.Errorf("failed to issue tx: %w", errors.New("validator would have already unstaked")), exp: true},
+		{err: droppedError(txID, pstatus.Dropped, "failed to verify transfer: insufficient funds"), exp: false},
+		{err: nil, exp: false},
+	}
+	for i, tv := range tt {
+		if v := StartTimeExpired(tv.err); v != tv.exp {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.exp, v)
+		}
+	}
+}
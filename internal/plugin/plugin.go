@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package plugin finds and runs executables that extend subnet-cli with
+// extra commands (e.g., "subnet-cli-spacesvm" on PATH runs as
+// "subnet-cli spacesvm").
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Prefix is the executable name prefix of plugins.
+const Prefix = "subnet-cli-"
+
+// Environment passed to plugins, so they can share the CLI settings and
+// call back into the running binary (e.g., "$SUBNET_CLI --quiet create subnet").
+const (
+	EnvBinary    = "SUBNET_CLI"
+	EnvVersion   = "SUBNET_CLI_VERSION"
+	EnvNamesPath = "SUBNET_CLI_NAMES_PATH"
+)
+
+// Plugin is an executable found on PATH.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Find returns the plugin for the command [name], if any.
+func Find(name string) (Plugin, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsRune(name, os.PathSeparator) {
+		return Plugin{}, false
+	}
+	p, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+	return Plugin{Name: name, Path: p}, true
+}
+
+// List returns the plugins in the directories of [path] (formatted as
+// PATH), sorted by name. Like PATH lookups, the first directory wins.
+func List(path string) []Plugin {
+	seen := make(map[string]struct{})
+	plugins := []Plugin{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+			if !strings.HasPrefix(name, Prefix) || e.IsDir() {
+				continue
+			}
+			name = strings.TrimPrefix(name, Prefix)
+			if _, ok := seen[name]; ok || name == "" {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.Mode()&0o111 == 0 {
+				continue
+			}
+			seen[name] = struct{}{}
+			plugins = append(plugins, Plugin{Name: name, Path: filepath.Join(dir, e.Name())})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Run runs the plugin with [args], the standard streams of this process
+// and the current environment plus [env].
+func (p Plugin) Run(ctx context.Context, args []string, env map[string]string) error {
+	c := exec.CommandContext(ctx, p.Path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = os.Environ()
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		c.Env = append(c.Env, k+"="+env[k])
+	}
+	return c.Run()
+}
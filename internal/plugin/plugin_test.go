@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	for _, f := range []struct {
+		dir  string
+		name string
+		mode os.FileMode
+	}{
+		{dir1, "subnet-cli-spacesvm", 0o755},
+		{dir1, "subnet-cli-notes", 0o644},
+		{dir1, "kubectl-foo", 0o755},
+		{dir2, "subnet-cli-spacesvm", 0o755},
+		{dir2, "subnet-cli-evm-tools", 0o755},
+	} {
+		if err := os.WriteFile(filepath.Join(f.dir, f.name), []byte("#!/bin/sh\n"), f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plugins := List(dir1 + string(os.PathListSeparator) + dir2)
+	expected := []Plugin{
+		{Name: "evm-tools", Path: filepath.Join(dir2, "subnet-cli-evm-tools")},
+		{Name: "spacesvm", Path: filepath.Join(dir1, "subnet-cli-spacesvm")},
+	}
+	if !reflect.DeepEqual(plugins, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, plugins)
+	}
+}
+
+func TestFindInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "--help", "a/b"} {
+		if _, ok := Find(name); ok {
+			t.Fatalf("unexpected plugin for %q", name)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package policy enforces organization guardrails (spending limits,
+// validator weight changes, allowed networks and who may propose or
+// approve operations) before txs are issued.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+)
+
+// SystemPath is the policy enforced when neither "--policy-path" nor
+// [EnvPath] is set, if it exists.
+const SystemPath = "/etc/subnet-cli/policy.yaml"
+
+// EnvPath is the environment variable of the policy path.
+const EnvPath = "SUBNET_CLI_POLICY"
+
+var (
+	ErrInvalidPolicy    = errors.New("invalid policy")
+	ErrNetworkForbidden = errors.New("network not allowed by policy")
+	ErrSpendLimit       = errors.New("spend exceeds policy limit")
+	ErrWeightLimit      = errors.New("weight change exceeds policy limit")
+	ErrNoApprovalList   = errors.New("policy lists no proposers or approvers")
+	ErrNotProposer      = errors.New("address not allowed to propose by policy")
+	ErrNotApprover      = errors.New("address not allowed to approve by policy")
+	ErrApprovalRequired = errors.New("policy requires two-person approval")
+)
+
+// Policy is a policy file.
+//
+//	allowed-networks: [fuji, local]
+//	max-spend: 5avax
+//	max-spend-per-command:
+//	  add validator: 2001avax
+//	max-weight-change: 100
+//	proposers: [P-fuji18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t]
+//	approvers: [P-fuji1y0enzveu5pu2jrqmlhnm2x2ej5shjn3c053450]
+type Policy struct {
+	// AllowedNetworks are the network names txs may be issued on; all if
+	// empty.
+	AllowedNetworks []string `yaml:"allowed-networks,omitempty"`
+	// MaxSpend is the default limit of fees and stake of one command.
+	MaxSpend string `yaml:"max-spend,omitempty"`
+	// MaxSpendPerCommand overrides [MaxSpend] by command (e.g.,
+	// "add validator").
+	MaxSpendPerCommand map[string]string `yaml:"max-spend-per-command,omitempty"`
+	// MaxWeightChange limits the total subnet validator weight one command
+	// adds or changes; unlimited if 0.
+	MaxWeightChange uint64 `yaml:"max-weight-change,omitempty"`
+	// Proposers and Approvers are the P-Chain addresses (or short IDs)
+	// whose keys may propose and approve operations in two-person approval
+	// mode, which refuses to run unless both are set.
+	Proposers []string `yaml:"proposers,omitempty"`
+	Approvers []string `yaml:"approvers,omitempty"`
+
+	path      string
+	maxSpend  uint64
+	perCmdMax map[string]uint64
+	proposers map[ids.ShortID]struct{}
+	approvers map[ids.ShortID]struct{}
+}
+
+// Load reads the policy at [path].
+func Load(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{path: path, perCmdMax: map[string]uint64{}}
+	if err := yaml.UnmarshalStrict(b, p); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPolicy, path, err)
+	}
+	if p.MaxSpend != "" {
+		if p.maxSpend, err = amount.Parse(p.MaxSpend); err != nil {
+			return nil, fmt.Errorf("%w: %s: max-spend: %v", ErrInvalidPolicy, path, err)
+		}
+	}
+	for c, s := range p.MaxSpendPerCommand {
+		v, err := amount.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: max-spend-per-command %q: %v", ErrInvalidPolicy, path, c, err)
+		}
+		p.perCmdMax[c] = v
+	}
+	if p.proposers, err = parseAddresses(p.Proposers); err != nil {
+		return nil, fmt.Errorf("%w: %s: proposers: %v", ErrInvalidPolicy, path, err)
+	}
+	if p.approvers, err = parseAddresses(p.Approvers); err != nil {
+		return nil, fmt.Errorf("%w: %s: approvers: %v", ErrInvalidPolicy, path, err)
+	}
+	return p, nil
+}
+
+// parseAddresses parses P-Chain addresses of any network, or short IDs.
+func parseAddresses(ss []string) (map[ids.ShortID]struct{}, error) {
+	m := make(map[ids.ShortID]struct{}, len(ss))
+	for _, s := range ss {
+		if !strings.Contains(s, "-") {
+			id, err := ids.ShortFromString(s)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", s, err)
+			}
+			m[id] = struct{}{}
+			continue
+		}
+		chain, _, b, err := formatting.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", s, err)
+		}
+		if chain != "P" {
+			return nil, fmt.Errorf("%q: not a P-Chain address", s)
+		}
+		id, err := ids.ToShortID(b)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", s, err)
+		}
+		m[id] = struct{}{}
+	}
+	return m, nil
+}
+
+// Find returns the path of the policy in effect: [flag], else [EnvPath],
+// else [SystemPath] if it exists, else "".
+func Find(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if p := os.Getenv(EnvPath); p != "" {
+		return p
+	}
+	if _, err := os.Stat(SystemPath); err == nil {
+		return SystemPath
+	}
+	return ""
+}
+
+// Path returns the file the policy was loaded from.
+func (p *Policy) Path() string {
+	return p.path
+}
+
+// Op is an operation about to issue txs.
+type Op struct {
+	// Command is the command without the binary name (e.g.,
+	// "add validator").
+	Command string
+	Network string
+	// Spend is the fees and stake in nano-AVAX.
+	Spend uint64
+	// WeightChange is the total subnet validator weight added or changed.
+	WeightChange uint64
+}
+
+// Check returns an error if [op] violates the policy.
+func (p *Policy) Check(op Op) error {
+	if len(p.AllowedNetworks) > 0 {
+		allowed := false
+		for _, n := range p.AllowedNetworks {
+			if strings.EqualFold(n, op.Network) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %q (allowed: %s)", ErrNetworkForbidden, op.Network, strings.Join(p.AllowedNetworks, ", "))
+		}
+	}
+	limit, ok := p.perCmdMax[op.Command]
+	if !ok {
+		limit = p.maxSpend
+	}
+	if limit > 0 && op.Spend > limit {
+		return fmt.Errorf("%w: %q spends %s > %s", ErrSpendLimit, op.Command, amount.Format(op.Spend), amount.Format(limit))
+	}
+	if p.MaxWeightChange > 0 && op.WeightChange > p.MaxWeightChange {
+		return fmt.Errorf("%w: %q changes weight by %d > %d", ErrWeightLimit, op.Command, op.WeightChange, p.MaxWeightChange)
+	}
+	return nil
+}
+
+// RequiresApproval returns true if the policy lists proposers or
+// approvers, so operations may only run in two-person approval mode.
+func (p *Policy) RequiresApproval() bool {
+	return len(p.proposers) > 0 || len(p.approvers) > 0
+}
+
+// CheckProposer returns an error unless [addr] may propose operations.
+func (p *Policy) CheckProposer(addr ids.ShortID) error {
+	if len(p.proposers) == 0 || len(p.approvers) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoApprovalList, p.path)
+	}
+	if _, ok := p.proposers[addr]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotProposer, addr)
+	}
+	return nil
+}
+
+// CheckApprover returns an error unless [addr] may approve operations.
+func (p *Policy) CheckApprover(addr ids.ShortID) error {
+	if len(p.proposers) == 0 || len(p.approvers) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoApprovalList, p.path)
+	}
+	if _, ok := p.approvers[addr]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotApprover, addr)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const testPolicy = `
+allowed-networks: [fuji, local]
+max-spend: 5avax
+max-spend-per-command:
+  add validator: 2001avax
+max-weight-change: 100
+`
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		op  Op
+		err error
+	}{
+		{op: Op{Command: "create subnet", Network: "fuji", Spend: units.Avax}},
+		{op: Op{Command: "create subnet", Network: "Local", Spend: units.Avax}},
+		{op: Op{Command: "create subnet", Network: "mainnet", Spend: units.Avax}, err: ErrNetworkForbidden},
+		{op: Op{Command: "create blockchain", Network: "fuji", Spend: 6 * units.Avax}, err: ErrSpendLimit},
+		{op: Op{Command: "add validator", Network: "fuji", Spend: 2001 * units.Avax}},
+		{op: Op{Command: "add validator", Network: "fuji", Spend: 2002 * units.Avax}, err: ErrSpendLimit},
+		{op: Op{Command: "add subnet-validator", Network: "fuji", WeightChange: 100}},
+		{op: Op{Command: "add subnet-validator", Network: "fuji", WeightChange: 101}, err: ErrWeightLimit},
+	}
+	for i, tv := range tt {
+		if err := p.Check(tv.op); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+	}
+}
+
+func TestApprovalList(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	// the ewoq address on fuji, then the short ID of another key
+	s := "proposers: [P-fuji18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t]\napprovers: [P-local1y0enzveu5pu2jrqmlhnm2x2ej5shjn3c64gvmc, 6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV]\n"
+	if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ewoq, err := ids.ShortFromString("6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := ids.GenerateTestShortID()
+	if err := p.CheckProposer(ewoq); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckProposer(other); !errors.Is(err, ErrNotProposer) {
+		t.Fatalf("expected %v, got %v", ErrNotProposer, err)
+	}
+	if err := p.CheckApprover(ewoq); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckApprover(other); !errors.Is(err, ErrNotApprover) {
+		t.Fatalf("expected %v, got %v", ErrNotApprover, err)
+	}
+
+	// two-person approval needs both lists
+	path = filepath.Join(dir, "no-approvers.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckProposer(ewoq); !errors.Is(err, ErrNoApprovalList) {
+		t.Fatalf("expected %v, got %v", ErrNoApprovalList, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for i, s := range []string{
+		"max-spend: lots\n",
+		"max-spend-per-command:\n  add validator: -1\n",
+		"max-spend-per-day: 1avax\n",
+		"proposers: [X-fuji18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t]\n",
+		"approvers: [nope]\n",
+	} {
+		path := filepath.Join(dir, "policy.yaml")
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); !errors.Is(err, ErrInvalidPolicy) {
+			t.Fatalf("#%d: expected %v, got %v", i, ErrInvalidPolicy, err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package poll
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -14,15 +15,173 @@ import (
 
 var ErrAborted = errors.New("aborted")
 
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string { return e.err.Error() }
+func (e *fatalError) Unwrap() error { return e.err }
+
+// Fatal marks [err] as permanent (e.g., a dropped tx), so the poll stops
+// immediately and returns it instead of retrying until the context is
+// done. Other check errors are retried.
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fatalError{err: err}
+}
+
+// IsFatal returns true if [err] was marked with [Fatal].
+func IsFatal(err error) bool {
+	var ferr *fatalError
+	return errors.As(err, &ferr)
+}
+
+type phaseKey struct{}
+
+type phase struct {
+	name     string
+	expected time.Duration
+}
+
+// WithPhase describes the wait of the polls with [ctx] (e.g., "tx commit"),
+// which typically completes within [expected] (zero if unknown), so
+// progress indicators can show it.
+func WithPhase(ctx context.Context, name string, expected time.Duration) context.Context {
+	return context.WithValue(ctx, phaseKey{}, phase{name: name, expected: expected})
+}
+
+// Phase returns the phase set by [WithPhase].
+func Phase(ctx context.Context) (name string, expected time.Duration) {
+	p, _ := ctx.Value(phaseKey{}).(phase)
+	return p.name, p.expected
+}
+
+// Progress describes a poll after a check.
+type Progress struct {
+	// Phase and Expected are set by [WithPhase], if any.
+	Phase    string
+	Expected time.Duration
+	// Attempt is the number of checks so far.
+	Attempt int
+	Elapsed time.Duration
+	// Remaining is the time until the context deadline, zero if none.
+	Remaining time.Duration
+	Done      bool
+	// Err is the error of the last check, if any.
+	Err error
+}
+
+type statusKey struct{}
+
+type status struct {
+	mu sync.Mutex
+	s  string
+}
+
+// WithStatus returns a context whose polls show a status that the check
+// updates with the returned function as the wait progresses (e.g., the
+// height a blockchain bootstrapped to and an ETA).
+func WithStatus(ctx context.Context) (context.Context, func(string)) {
+	st := &status{}
+	return context.WithValue(ctx, statusKey{}, st), func(s string) {
+		st.mu.Lock()
+		st.s = s
+		st.mu.Unlock()
+	}
+}
+
+// Status returns the latest status set for [ctx] (see [WithStatus]).
+func Status(ctx context.Context) string {
+	st, ok := ctx.Value(statusKey{}).(*status)
+	if !ok {
+		return ""
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.s
+}
+
+type progressKey struct{}
+
+// WithProgress registers [fn] to be called after every check of the polls
+// with [ctx] (e.g., to render a spinner or an ETA). Callbacks registered
+// on parent contexts are still called, before [fn].
+func WithProgress(ctx context.Context, fn func(Progress)) context.Context {
+	if prev, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		next := fn
+		fn = func(p Progress) {
+			prev(p)
+			next(p)
+		}
+	}
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// report calls the callbacks registered with [WithProgress], if any.
+func report(ctx context.Context, start time.Time, attempt int, done bool, err error) {
+	fn, ok := ctx.Value(progressKey{}).(func(Progress))
+	if !ok {
+		return
+	}
+	p := Progress{
+		Attempt: attempt,
+		Elapsed: time.Since(start),
+		Done:    done,
+		Err:     err,
+	}
+	p.Phase, p.Expected = Phase(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		if p.Remaining = time.Until(deadline); p.Remaining < 0 {
+			p.Remaining = 0
+		}
+	}
+	fn(p)
+}
+
 type Poller interface {
 	// Polls until "check" function returns "done=true".
 	// If "check" returns a non-empty error, it logs and
-	// continues the polling until context is canceled.
+	// continues the polling until context is canceled,
+	// unless the error is marked with [Fatal], which
+	// aborts the polling and is returned.
 	// It returns the duration that it took to complete the check.
 	Poll(
 		ctx context.Context,
 		check func() (done bool, err error),
 	) (time.Duration, error)
+	// PollCh polls like "Poll" in the background and sends the outcome
+	// to the returned channel, so many concurrent polls (e.g., several txs
+	// or nodes) can be multiplexed with "select".
+	PollCh(
+		ctx context.Context,
+		check func() (done bool, err error),
+	) <-chan Result
+}
+
+// Result is the outcome of a poll.
+type Result struct {
+	// Took is the duration that it took to complete the check.
+	Took time.Duration
+	Err  error
+}
+
+// Async runs [poll] in a goroutine and sends its outcome to the returned
+// channel, which is buffered so the goroutine never leaks if the result is
+// not received. Pollers implement "PollCh" with it.
+func Async(
+	ctx context.Context,
+	poll func(context.Context, func() (bool, error)) (time.Duration, error),
+	check func() (done bool, err error),
+) <-chan Result {
+	ch := make(chan Result, 1)
+	go func() {
+		took, err := poll(ctx, check)
+		ch <- Result{Took: took, Err: err}
+		close(ch)
+	}()
+	return ch
 }
 
 var _ Poller = &poller{}
@@ -45,7 +204,7 @@ func (pl *poller) Poll(ctx context.Context, check func() (done bool, err error))
 	tc := time.NewTicker(1)
 	defer tc.Stop()
 
-	for ctx.Err() == nil {
+	for attempt := 1; ctx.Err() == nil; attempt++ {
 		select {
 		case <-ctx.Done():
 			return time.Since(start), ctx.Err()
@@ -54,6 +213,12 @@ func (pl *poller) Poll(ctx context.Context, check func() (done bool, err error))
 		}
 
 		done, err := check()
+		zap.L().Debug("poll check", zap.Bool("done", done), zap.Duration("elapsed", time.Since(start)))
+		report(ctx, start, attempt, done && err == nil, err)
+		if IsFatal(err) {
+			zap.L().Warn("poll aborted", zap.Error(err))
+			return time.Since(start), err
+		}
 		if err != nil {
 			zap.L().Warn("poll check failed", zap.Error(err))
 			continue
@@ -69,3 +234,7 @@ func (pl *poller) Poll(ctx context.Context, check func() (done bool, err error))
 
 	return time.Since(start), ctx.Err()
 }
+
+func (pl *poller) PollCh(ctx context.Context, check func() (done bool, err error)) <-chan Result {
+	return Async(ctx, pl.Poll, check)
+}
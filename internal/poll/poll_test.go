@@ -21,3 +21,148 @@ func TestPoll(t *testing.T) {
 		t.Fatalf("unexpected Poll error %v", err)
 	}
 }
+
+func TestPollCh(t *testing.T) {
+	t.Parallel()
+
+	pl := New(time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	n1, n2 := 0, 0
+	ch1 := pl.PollCh(ctx, func() (bool, error) {
+		n1++
+		return n1 == 3, nil
+	})
+	ch2 := pl.PollCh(ctx, func() (bool, error) {
+		n2++
+		return n2 == 5, nil
+	})
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-ch1:
+			if r.Err != nil {
+				t.Fatalf("unexpected PollCh error %v", r.Err)
+			}
+			ch1 = nil
+		case r := <-ch2:
+			if r.Err != nil {
+				t.Fatalf("unexpected PollCh error %v", r.Err)
+			}
+			ch2 = nil
+		}
+	}
+	if n1 != 3 || n2 != 5 {
+		t.Fatalf("unexpected checks %d, %d", n1, n2)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if r := <-pl.PollCh(canceled, nil); !errors.Is(r.Err, context.Canceled) {
+		t.Fatalf("unexpected PollCh error %v", r.Err)
+	}
+}
+
+func TestPollProgress(t *testing.T) {
+	t.Parallel()
+
+	pl := New(time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = WithPhase(ctx, "tx commit", time.Second)
+	var outer, inner []Progress
+	ctx = WithProgress(ctx, func(p Progress) { outer = append(outer, p) })
+	ctx = WithProgress(ctx, func(p Progress) { inner = append(inner, p) })
+
+	errCheck := errors.New("check failed")
+	n := 0
+	if _, err := pl.Poll(ctx, func() (bool, error) {
+		n++
+		switch n {
+		case 1:
+			return false, errCheck
+		case 2:
+			return false, nil
+		}
+		return true, nil
+	}); err != nil {
+		t.Fatalf("unexpected Poll error %v", err)
+	}
+	if len(outer) != 3 || len(inner) != 3 {
+		t.Fatalf("expected 3 callbacks, got %d, %d", len(outer), len(inner))
+	}
+	for i, p := range inner {
+		if p.Attempt != i+1 {
+			t.Fatalf("#%d: expected attempt %d, got %d", i, i+1, p.Attempt)
+		}
+		if p.Phase != "tx commit" || p.Expected != time.Second {
+			t.Fatalf("#%d: unexpected phase %q (%v)", i, p.Phase, p.Expected)
+		}
+		if p.Remaining <= 0 || p.Remaining > time.Minute {
+			t.Fatalf("#%d: unexpected remaining %v", i, p.Remaining)
+		}
+		if p.Done != (i == 2) {
+			t.Fatalf("#%d: unexpected done %v", i, p.Done)
+		}
+		var expErr error
+		if i == 0 {
+			expErr = errCheck
+		}
+		if !errors.Is(p.Err, expErr) {
+			t.Fatalf("#%d: unexpected error %v", i, p.Err)
+		}
+	}
+}
+
+func TestPollFatal(t *testing.T) {
+	t.Parallel()
+
+	pl := New(time.Millisecond)
+
+	errRetry := errors.New("retry")
+	errDropped := errors.New("dropped")
+	tt := []struct {
+		errs     []error
+		expErr   error
+		expCheck int
+	}{
+		{errs: []error{errRetry, errRetry, nil}, expCheck: 3},
+		{errs: []error{errRetry, Fatal(errDropped), nil}, expErr: errDropped, expCheck: 2},
+		{errs: []error{Fatal(nil)}, expCheck: 1},
+	}
+	for i, tv := range tt {
+		n := 0
+		_, err := pl.Poll(context.Background(), func() (bool, error) {
+			err := tv.errs[n]
+			n++
+			return err == nil, err
+		})
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if tv.expErr != nil && !IsFatal(err) {
+			t.Fatalf("#%d: expected fatal error, got %v", i, err)
+		}
+		if n != tv.expCheck {
+			t.Fatalf("#%d: expected %d checks, got %d", i, tv.expCheck, n)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	if s := Status(context.Background()); s != "" {
+		t.Fatalf("expected no status, got %q", s)
+	}
+	ctx, setStatus := WithStatus(context.Background())
+	if s := Status(ctx); s != "" {
+		t.Fatalf("expected no status, got %q", s)
+	}
+	setStatus("height 10")
+	setStatus("height 20")
+	if s := Status(WithPhase(ctx, "bootstrap", 0)); s != "height 20" {
+		t.Fatalf("expected the latest status, got %q", s)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package precompile encodes the calls of the stateful precompiles of
+// subnet-evm whose use is gated by an allow list (e.g., the tx allow list
+// or the fee manager).
+// ref. "precompile/allowlist" of subnet-evm
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+var (
+	ErrUnknownPrecompile = errors.New("unknown precompile")
+	ErrUnknownRole       = errors.New("unknown allow list role")
+)
+
+// Precompile is a stateful precompile of subnet-evm with an allow list.
+type Precompile struct {
+	Name        string
+	Description string
+	Address     evm.Address
+}
+
+var (
+	DeployerAllowList = Precompile{Name: "deployer", Description: "contract deployer allow list", Address: evm.Address{0x02}}
+	NativeMinter      = Precompile{Name: "minter", Description: "native minter", Address: evm.Address{0x02, 19: 0x01}}
+	TxAllowList       = Precompile{Name: "tx", Description: "tx allow list", Address: evm.Address{0x02, 19: 0x02}}
+	FeeManager        = Precompile{Name: "fee-manager", Description: "fee manager", Address: evm.Address{0x02, 19: 0x03}}
+	RewardManager     = Precompile{Name: "reward-manager", Description: "reward manager", Address: evm.Address{0x02, 19: 0x04}}
+)
+
+// Precompiles are the precompiles with an allow list.
+var Precompiles = []Precompile{TxAllowList, DeployerAllowList, FeeManager, NativeMinter, RewardManager}
+
+// Lookup returns the precompile [name].
+func Lookup(name string) (Precompile, error) {
+	names := make([]string, 0, len(Precompiles))
+	for _, p := range Precompiles {
+		if p.Name == name {
+			return p, nil
+		}
+		names = append(names, p.Name)
+	}
+	return Precompile{}, fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownPrecompile, name, strings.Join(names, ", "))
+}
+
+// Role is the role of an address in an allow list.
+type Role uint64
+
+const (
+	// None may not use the precompile.
+	None Role = iota
+	// Enabled may use the precompile (e.g., issue txs or deploy contracts).
+	Enabled
+	// Admin may also change the role of any address.
+	Admin
+	// Manager may also enable and disable addresses that are not admins
+	// or managers (since subnet-evm v0.6.0, Durango).
+	Manager
+)
+
+func (r Role) String() string {
+	switch r {
+	case None:
+		return "none"
+	case Enabled:
+		return "enabled"
+	case Admin:
+		return "admin"
+	case Manager:
+		return "manager"
+	}
+	return fmt.Sprintf("role(%d)", uint64(r))
+}
+
+// ParseRole parses "none", "enabled", "admin" or "manager".
+func ParseRole(s string) (Role, error) {
+	for _, r := range []Role{None, Enabled, Admin, Manager} {
+		if r.String() == s {
+			return r, nil
+		}
+	}
+	return None, fmt.Errorf("%w: %q (expected none, enabled, admin or manager)", ErrUnknownRole, s)
+}
+
+// CanUse returns true if [r] may use the precompile.
+func (r Role) CanUse() bool { return r != None }
+
+// CanSet returns true if an address of role [r] may change the role of an
+// address of role [from] to [to].
+func (r Role) CanSet(from Role, to Role) bool {
+	switch r {
+	case Admin:
+		return true
+	case Manager:
+		return (from == None || from == Enabled) && (to == None || to == Enabled)
+	}
+	return false
+}
+
+// SetRole returns the call that sets the role of [a] to [r].
+func SetRole(a evm.Address, r Role) []byte {
+	sig := map[Role]string{
+		None:    "setNone(address)",
+		Enabled: "setEnabled(address)",
+		Admin:   "setAdmin(address)",
+		Manager: "setManager(address)",
+	}[r]
+	return evm.Call(sig, evm.WordAddress(a))
+}
+
+// ReadAllowList returns the call that reads the role of [a].
+func ReadAllowList(a evm.Address) []byte {
+	return evm.Call("readAllowList(address)", evm.WordAddress(a))
+}
+
+// DecodeRole decodes the return value of [ReadAllowList].
+func DecodeRole(ret []byte) Role {
+	v := evm.DecodeUint(ret, 0)
+	if !v.IsUint64() {
+		return Role(^uint64(0))
+	}
+	return Role(v.Uint64())
+}
+
+// Active returns true if the precompile is activated on a chain, given the
+// [code] at its address: subnet-evm sets a single byte when activating it.
+func Active(code []byte) bool { return len(code) > 0 }
+
+// MintNativeCoin returns the call of the native minter that mints
+// [amount] wei of the native token to [to].
+func MintNativeCoin(to evm.Address, amount *big.Int) []byte {
+	return evm.Call("mintNativeCoin(address,uint256)", evm.WordAddress(to), evm.WordUint(amount))
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	p, err := Lookup("tx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Address.Hex() != "0x0200000000000000000000000000000000000002" {
+		t.Fatalf("unexpected address %s", p.Address)
+	}
+	if _, err := Lookup("warp"); !errors.Is(err, ErrUnknownPrecompile) {
+		t.Fatalf("expected %v, got %v", ErrUnknownPrecompile, err)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range []Role{None, Enabled, Admin, Manager} {
+		got, err := ParseRole(r.String())
+		if err != nil || got != r {
+			t.Fatalf("%s: unexpected role %s (%v)", r, got, err)
+		}
+	}
+	if _, err := ParseRole("owner"); !errors.Is(err, ErrUnknownRole) {
+		t.Fatalf("expected %v, got %v", ErrUnknownRole, err)
+	}
+
+	tt := []struct {
+		r, from, to Role
+		ok          bool
+	}{
+		{r: Admin, from: Admin, to: None, ok: true},
+		{r: Manager, from: None, to: Enabled, ok: true},
+		{r: Manager, from: Enabled, to: None, ok: true},
+		{r: Manager, from: None, to: Admin},
+		{r: Manager, from: Admin, to: None},
+		{r: Enabled, from: None, to: Enabled},
+	}
+	for _, tv := range tt {
+		if ok := tv.r.CanSet(tv.from, tv.to); ok != tv.ok {
+			t.Fatalf("%s setting %s to %s: expected %v", tv.r, tv.from, tv.to, tv.ok)
+		}
+	}
+}
+
+func TestCalls(t *testing.T) {
+	t.Parallel()
+
+	a := evm.Address{1}
+	if b := SetRole(a, Admin); !bytes.Equal(b[:4], evm.Selector("setAdmin(address)")) || evm.DecodeAddress(b[4:], 0) != a {
+		t.Fatalf("unexpected call %x", b)
+	}
+	// ref. the allow list ABI of subnet-evm
+	if b := ReadAllowList(a); !bytes.Equal(b[:4], []byte{0xeb, 0x54, 0xda, 0xe1}) {
+		t.Fatalf("unexpected selector %x", b[:4])
+	}
+	// ref. the native minter ABI of subnet-evm
+	if b := MintNativeCoin(a, big.NewInt(5)); !bytes.Equal(b[:4], []byte{0x4f, 0x5a, 0xaa, 0xba}) || evm.DecodeUint(b[4:], 1).Int64() != 5 {
+		t.Fatalf("unexpected call %x", b)
+	}
+	if r := DecodeRole(evm.WordUint(big.NewInt(3))); r != Manager {
+		t.Fatalf("unexpected role %s", r)
+	}
+}
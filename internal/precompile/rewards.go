@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+// BlackholeAddress is where subnet-evm sends the fees it burns.
+var BlackholeAddress = evm.Address{0x01}
+
+// RewardMode is where the reward manager sends the fees of a block.
+type RewardMode int
+
+const (
+	// Burn sends the fees to [BlackholeAddress] (the default).
+	Burn RewardMode = iota
+	// FeeRecipients sends the fees to the fee recipient (coinbase) each
+	// block producer sets.
+	FeeRecipients
+	// RewardAddress sends the fees to a fixed address.
+	RewardAddress
+)
+
+func (m RewardMode) String() string {
+	switch m {
+	case Burn:
+		return "burned"
+	case FeeRecipients:
+		return "fee recipients"
+	case RewardAddress:
+		return "reward address"
+	}
+	return "unknown"
+}
+
+// Rewards is the reward configuration of a chain.
+type Rewards struct {
+	Mode RewardMode
+	// Address is the reward address of [RewardAddress].
+	Address evm.Address
+}
+
+// SetRewardAddress returns the call that sends the fees to [a].
+func SetRewardAddress(a evm.Address) []byte {
+	return evm.Call("setRewardAddress(address)", evm.WordAddress(a))
+}
+
+// AllowFeeRecipients returns the call that sends the fees to the fee
+// recipients of the block producers.
+func AllowFeeRecipients() []byte {
+	return evm.Call("allowFeeRecipients()")
+}
+
+// DisableRewards returns the call that burns the fees.
+func DisableRewards() []byte {
+	return evm.Call("disableRewards()")
+}
+
+// AreFeeRecipientsAllowed returns the call that reads whether the fees go
+// to the fee recipients.
+func AreFeeRecipientsAllowed() []byte {
+	return evm.Call("areFeeRecipientsAllowed()")
+}
+
+// CurrentRewardAddress returns the call that reads the reward address.
+func CurrentRewardAddress() []byte {
+	return evm.Call("currentRewardAddress()")
+}
+
+// DecodeRewards decodes the return values of [AreFeeRecipientsAllowed] and
+// [CurrentRewardAddress].
+func DecodeRewards(allowed []byte, current []byte) Rewards {
+	if evm.DecodeUint(allowed, 0).Sign() != 0 {
+		return Rewards{Mode: FeeRecipients}
+	}
+	a := evm.DecodeAddress(current, 0)
+	if a == BlackholeAddress {
+		return Rewards{Mode: Burn, Address: a}
+	}
+	return Rewards{Mode: RewardAddress, Address: a}
+}
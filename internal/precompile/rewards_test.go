@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+)
+
+func TestRewards(t *testing.T) {
+	t.Parallel()
+
+	a := evm.Address{9}
+	if b := SetRewardAddress(a); !bytes.Equal(b[:4], evm.Selector("setRewardAddress(address)")) || evm.DecodeAddress(b[4:], 0) != a {
+		t.Fatalf("unexpected call %x", b)
+	}
+	if b := DisableRewards(); len(b) != 4 {
+		t.Fatalf("unexpected call %x", b)
+	}
+	if BlackholeAddress.Hex() != "0x0100000000000000000000000000000000000000" {
+		t.Fatalf("unexpected blackhole address %s", BlackholeAddress)
+	}
+
+	tt := []struct {
+		allowed bool
+		current evm.Address
+		exp     Rewards
+	}{
+		{current: BlackholeAddress, exp: Rewards{Mode: Burn, Address: BlackholeAddress}},
+		{current: a, exp: Rewards{Mode: RewardAddress, Address: a}},
+		{allowed: true, current: BlackholeAddress, exp: Rewards{Mode: FeeRecipients}},
+	}
+	for _, tv := range tt {
+		if r := DecodeRewards(evm.WordBool(tv.allowed), evm.WordAddress(tv.current)); r != tv.exp {
+			t.Fatalf("expected %+v, got %+v", tv.exp, r)
+		}
+	}
+}
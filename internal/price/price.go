@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package price converts AVAX amounts to fiat with a static rate or a
+// price feed.
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/dustin/go-humanize"
+)
+
+// CoinGecko is the price source "coingecko": the CoinGecko simple price
+// API, with "%s" replaced by the currency.
+const CoinGecko = "https://api.coingecko.com/api/v3/simple/price?ids=avalanche-2&vs_currencies=%s"
+
+// fetchTimeout bounds a price feed request.
+const fetchTimeout = 5 * time.Second
+
+var (
+	ErrInvalidSource = errors.New("invalid price source")
+	ErrNoPrice       = errors.New("price feed has no price")
+)
+
+// Quote is the price of 1 AVAX.
+type Quote struct {
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+	Source   string  `json:"source"`
+}
+
+// Fetch returns the quote of [source], which is a static rate (e.g.,
+// "25.5"), "coingecko" or the URL of a CoinGecko-compatible simple price
+// API (a JSON object whose nested [currency] field is the rate).
+func Fetch(ctx context.Context, source string, currency string) (*Quote, error) {
+	currency = strings.ToLower(currency)
+	if rate, err := strconv.ParseFloat(source, 64); err == nil {
+		if rate <= 0 {
+			return nil, fmt.Errorf("%w: rate %v", ErrInvalidSource, rate)
+		}
+		return &Quote{Currency: currency, Rate: rate, Source: "static"}, nil
+	}
+	u := source
+	if source == "coingecko" {
+		u = fmt.Sprintf(CoinGecko, currency)
+	}
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return nil, fmt.Errorf("%w: %q (expected a rate, \"coingecko\" or a URL)", ErrInvalidSource, source)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %s", ErrNoPrice, u, resp.Status)
+	}
+	var v interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&v); err != nil {
+		return nil, err
+	}
+	rate, ok := find(v, currency)
+	if !ok || rate <= 0 {
+		return nil, fmt.Errorf("%w: no %q in %s", ErrNoPrice, currency, u)
+	}
+	return &Quote{Currency: currency, Rate: rate, Source: u}, nil
+}
+
+// find returns the first number under the key [currency], depth first.
+func find(v interface{}, currency string) (float64, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	if f, ok := m[currency].(float64); ok {
+		return f, true
+	}
+	for _, c := range m {
+		if f, ok := find(c, currency); ok {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// Value returns the fiat value of [nAVAX].
+func (q *Quote) Value(nAVAX uint64) float64 {
+	return float64(nAVAX) / float64(units.Avax) * q.Rate
+}
+
+// Format returns the fiat value of [nAVAX] (e.g., "$1,234.56" or
+// "1,234.56 EUR").
+func (q *Quote) Format(nAVAX uint64) string {
+	s := humanize.FormatFloat("#,###.##", q.Value(nAVAX))
+	if q.Currency == "usd" {
+		return "$" + s
+	}
+	return s + " " + strings.ToUpper(q.Currency)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package price
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"avalanche-2":{"usd":20.5,"eur":18}}`))
+	}))
+	defer srv.Close()
+
+	tt := []struct {
+		source   string
+		currency string
+		rate     float64
+		err      error
+	}{
+		{source: "25", currency: "usd", rate: 25},
+		{source: "0", currency: "usd", err: ErrInvalidSource},
+		{source: "ftp://x", currency: "usd", err: ErrInvalidSource},
+		{source: srv.URL, currency: "USD", rate: 20.5},
+		{source: srv.URL, currency: "eur", rate: 18},
+		{source: srv.URL, currency: "jpy", err: ErrNoPrice},
+	}
+	for i, tv := range tt {
+		q, err := Fetch(context.Background(), tv.source, tv.currency)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		if err == nil && q.Rate != tv.rate {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.rate, q.Rate)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		q        Quote
+		nAVAX    uint64
+		expected string
+	}{
+		{Quote{Currency: "usd", Rate: 20}, 2000 * units.Avax, "$40,000.00"},
+		{Quote{Currency: "usd", Rate: 20}, units.MilliAvax, "$0.02"},
+		{Quote{Currency: "eur", Rate: 18.5}, 2 * units.Avax, "37.00 EUR"},
+	}
+	for i, tv := range tt {
+		if s := tv.q.Format(tv.nAVAX); s != tv.expected {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.expected, s)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package progress renders progress indicators for polling phases.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/subnet-cli/internal/poll"
+)
+
+const (
+	// clears the current terminal line
+	clearLine = "\r\033[2K"
+
+	refreshInterval = 100 * time.Millisecond
+)
+
+var spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var _ poll.Poller = &poller{}
+
+type poller struct {
+	next poll.Poller
+	w    io.Writer
+}
+
+// NewPoller wraps [next] to render a progress line to the terminal [w]
+// while polling, showing the phase (see [poll.WithPhase]), the elapsed
+// time, the expected time, the number of attempts and the time left until
+// the deadline. The line is replaced by a summary once the phase completes.
+func NewPoller(next poll.Poller, w io.Writer) poll.Poller {
+	return &poller{next: next, w: w}
+}
+
+func (p *poller) Poll(ctx context.Context, check func() (done bool, err error)) (time.Duration, error) {
+	name, expected := poll.Phase(ctx)
+	if name == "" {
+		name = "waiting"
+	}
+
+	start := time.Now()
+	var (
+		attempts  int64
+		remaining int64
+	)
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining = int64(time.Until(deadline))
+	}
+	ctx = poll.WithProgress(ctx, func(pr poll.Progress) {
+		atomic.StoreInt64(&attempts, int64(pr.Attempt))
+		atomic.StoreInt64(&remaining, int64(pr.Remaining))
+	})
+	line := func(mark string, elapsed time.Duration) string {
+		s := Line(mark, name, elapsed, expected, int(atomic.LoadInt64(&attempts)))
+		if st := poll.Status(ctx); st != "" {
+			s += " · " + st
+		}
+		return s
+	}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tc := time.NewTicker(refreshInterval)
+		defer tc.Stop()
+		for frame := 0; ; frame++ {
+			s := line(spinner[frame%len(spinner)], time.Since(start))
+			if r := atomic.LoadInt64(&remaining); r > 0 {
+				s += fmt.Sprintf(" · %s left", time.Duration(r).Round(time.Second))
+			}
+			fmt.Fprint(p.w, clearLine+s)
+			select {
+			case <-stop:
+				return
+			case <-tc.C:
+			}
+		}
+	}()
+
+	took, err := p.next.Poll(ctx, check)
+	close(stop)
+	wg.Wait()
+
+	mark := "✓"
+	if err != nil {
+		mark = "✗"
+	}
+	fmt.Fprintln(p.w, clearLine+line(mark, took))
+	return took, err
+}
+
+// PollCh does not render progress, since concurrent polls cannot share
+// one progress line.
+func (p *poller) PollCh(ctx context.Context, check func() (done bool, err error)) <-chan poll.Result {
+	return p.next.PollCh(ctx, check)
+}
+
+// Line formats a progress line
+// (e.g., "⠋ tx commit · 1.2s elapsed (expected ~2s) · attempt 2").
+func Line(mark string, name string, elapsed time.Duration, expected time.Duration, attempts int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s · %s elapsed", mark, name, elapsed.Round(100*time.Millisecond))
+	if expected > 0 {
+		fmt.Fprintf(&sb, " (expected ~%s)", expected)
+	}
+	if attempts > 0 {
+		fmt.Fprintf(&sb, " · attempt %d", attempts)
+	}
+	return sb.String()
+}
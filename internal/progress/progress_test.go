@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package progress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-cli/internal/poll"
+)
+
+// syncBuffer guards the buffer against the render goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLine(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		elapsed  time.Duration
+		expected time.Duration
+		attempts int
+		exp      string
+	}{
+		{name: "tx commit", elapsed: 1234 * time.Millisecond, expected: 2 * time.Second, attempts: 2, exp: "⠋ tx commit · 1.2s elapsed (expected ~2s) · attempt 2"},
+		{name: "bootstrap", elapsed: 3 * time.Second, exp: "⠋ bootstrap · 3s elapsed"},
+	}
+	for i, tv := range tt {
+		if s := Line("⠋", tv.name, tv.elapsed, tv.expected, tv.attempts); s != tv.exp {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.exp, s)
+		}
+	}
+}
+
+func TestPoller(t *testing.T) {
+	t.Parallel()
+
+	errCheck := errors.New("check failed")
+	tt := []struct {
+		fail    bool
+		timeout time.Duration
+		expErr  error
+		expLine string
+	}{
+		{expLine: "✓ tx commit"},
+		{fail: true, timeout: 50 * time.Millisecond, expErr: context.DeadlineExceeded, expLine: "✗ tx commit"},
+	}
+	for i, tv := range tt {
+		w := &syncBuffer{}
+		pl := NewPoller(poll.New(time.Millisecond), w)
+
+		ctx := context.Background()
+		if tv.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, tv.timeout)
+			defer cancel()
+		}
+		ctx = poll.WithPhase(ctx, "tx commit", time.Second)
+
+		n := 0
+		_, err := pl.Poll(ctx, func() (bool, error) {
+			n++
+			if tv.fail {
+				return false, errCheck
+			}
+			return n == 3, nil
+		})
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		out := w.String()
+		if !strings.Contains(out, tv.expLine) {
+			t.Fatalf("#%d: expected %q in %q", i, tv.expLine, out)
+		}
+		if !tv.fail && !strings.HasSuffix(out, "attempt 3\n") {
+			t.Fatalf("#%d: expected 3 attempts in %q", i, out)
+		}
+	}
+}
+
+func TestPollerStatus(t *testing.T) {
+	t.Parallel()
+
+	w := &syncBuffer{}
+	pl := NewPoller(poll.New(time.Millisecond), w)
+
+	ctx, setStatus := poll.WithStatus(poll.WithPhase(context.Background(), "bootstrap", 0))
+	n := 0
+	if _, err := pl.Poll(ctx, func() (bool, error) {
+		n++
+		setStatus(fmt.Sprintf("height %d", n))
+		return n == 3, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if out := w.String(); !strings.HasSuffix(out, "attempt 3 · height 3\n") {
+		t.Fatalf("expected the latest status in %q", out)
+	}
+}
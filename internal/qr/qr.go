@@ -0,0 +1,535 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package qr encodes byte payloads (e.g., addresses) as QR codes (ISO/IEC
+// 18004, byte mode) and renders them on terminals.
+//
+// ref. https://www.nayuki.io/page/qr-code-generator-library
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrTooLong = errors.New("payload too long for a QR code")
+
+// Level is the error correction level of a QR code.
+type Level int
+
+const (
+	// Low recovers ~7% of the codewords.
+	Low Level = iota
+	// Medium recovers ~15% of the codewords.
+	Medium
+	// Quartile recovers ~25% of the codewords.
+	Quartile
+	// High recovers ~30% of the codewords.
+	High
+)
+
+// formatBits are the format information bits of each level.
+var formatBits = [...]int{Low: 1, Medium: 0, Quartile: 3, High: 2}
+
+const (
+	minVersion = 1
+	maxVersion = 40
+)
+
+// eccCodewordsPerBlock and numBlocks are indexed by level and version.
+var (
+	eccCodewordsPerBlock = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	numBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// Code is an encoded QR code.
+type Code struct {
+	Version int
+	Level   Level
+	// Size is the number of modules per side.
+	Size int
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Dark returns true if the module at column [x] and row [y] is dark.
+func (c *Code) Dark(x int, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+// Encode encodes [data] in byte mode with the smallest version that fits at
+// [level].
+func Encode(data []byte, level Level) (*Code, error) {
+	version := minVersion
+	for ; ; version++ {
+		if version > maxVersion {
+			return nil, fmt.Errorf("%w: %d bytes", ErrTooLong, len(data))
+		}
+		if 4+charCountBits(version)+8*len(data) <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	// mode indicator, character count, data, terminator and padding
+	bb := &bitBuffer{}
+	bb.append(0x4, 4)
+	bb.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	capacity := numDataCodewords(version, level) * 8
+	bb.append(0, min(4, capacity-bb.n))
+	bb.append(0, (8-bb.n%8)%8)
+	for pad := 0xEC; bb.n < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	size := version*4 + 17
+	c := &Code{
+		Version:    version,
+		Level:      level,
+		Size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.isFunction[i] = make([]bool, size)
+	}
+	c.drawFunctionPatterns()
+	c.drawCodewords(c.addECCAndInterleave(bb.bytes()))
+
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); minPenalty < 0 || p < minPenalty {
+			best, minPenalty = mask, p
+		}
+		// masks are XORs, so applying one again undoes it
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+	return c, nil
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// numRawDataModules returns the number of modules that hold data (and error
+// correction) codewords, including remainder bits.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numBlocks[level][version]
+}
+
+type bitBuffer struct {
+	buf []byte
+	n   int
+}
+
+// append appends the [length] low bits of [v], most significant first.
+func (bb *bitBuffer) append(v int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		if bb.n%8 == 0 {
+			bb.buf = append(bb.buf, 0)
+		}
+		if (v>>i)&1 != 0 {
+			bb.buf[bb.n/8] |= 0x80 >> (bb.n % 8)
+		}
+		bb.n++
+	}
+}
+
+func (bb *bitBuffer) bytes() []byte { return bb.buf }
+
+func (c *Code) setFunction(x int, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.Size-4, 3)
+	c.drawFinderPattern(3, c.Size-4)
+
+	pos := alignmentPatternPositions(c.Version)
+	n := len(pos)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// skip the three corners with finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			c.drawAlignmentPattern(pos[i], pos[j])
+		}
+	}
+
+	// reserve the format bits, drawn once the mask is chosen
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinderPattern(x int, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignmentPattern(x int, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions returns the ascending center coordinates of the
+// alignment patterns on each axis.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	data := formatBits[c.Level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// first copy, around the top left finder pattern
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// second copy, split between the other two finder patterns
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	// always dark
+	c.setFunction(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// addECCAndInterleave splits [data] into blocks, appends the Reed-Solomon
+// error correction codewords of each and interleaves them.
+func (c *Code) addECCAndInterleave(data []byte) []byte {
+	nBlocks := numBlocks[c.Level][c.Version]
+	blockECCLen := eccCodewordsPerBlock[c.Level][c.Version]
+	rawCodewords := numRawDataModules(c.Version) / 8
+	numShortBlocks := nBlocks - rawCodewords%nBlocks
+	shortBlockLen := rawCodewords / nBlocks
+
+	divisor := rsDivisor(blockECCLen)
+	blocks := make([][]byte, nBlocks)
+	for i, k := 0, 0; i < nBlocks; i++ {
+		datLen := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		dat := data[k : k+datLen]
+		k += datLen
+		block := append([]byte{}, dat...)
+		if i < numShortBlocks {
+			// placeholder, skipped when interleaving
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, rsRemainder(dat, divisor)...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords draws [data] in the zigzag order of the data area.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					// upwards
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.modules[y][x] = bit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+				// remainder bits stay light
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan; the mask with the lowest
+// score is used.
+func (c *Code) penalty() int {
+	const (
+		n1 = 3
+		n2 = 3
+		n3 = 40
+		n4 = 10
+	)
+	result := 0
+	// runs of 5 or more modules of the same color, in rows and columns
+	for _, column := range []bool{false, true} {
+		for a := 0; a < c.Size; a++ {
+			run := 0
+			for b := 0; b < c.Size; b++ {
+				if b > 0 && c.at(a, b, column) == c.at(a, b-1, column) {
+					run++
+					if run == 5 {
+						result += n1
+					} else if run > 5 {
+						result++
+					}
+				} else {
+					run = 1
+				}
+			}
+		}
+	}
+	// 2x2 blocks of the same color
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			d := c.modules[y][x]
+			if d == c.modules[y][x+1] && d == c.modules[y+1][x] && d == c.modules[y+1][x+1] {
+				result += n2
+			}
+		}
+	}
+	// finder-like patterns (1:1:3:1:1) with 4 light modules on a side
+	finder := []bool{true, false, true, true, true, false, true}
+	for _, column := range []bool{false, true} {
+		for a := 0; a < c.Size; a++ {
+			for b := 0; b+len(finder) <= c.Size; b++ {
+				match := true
+				for k, d := range finder {
+					if c.at(a, b+k, column) != d {
+						match = false
+						break
+					}
+				}
+				if match && (c.light(a, b-4, b, column) || c.light(a, b+len(finder), b+len(finder)+4, column)) {
+					result += n3
+				}
+			}
+		}
+	}
+	// balance of dark and light modules
+	dark := 0
+	for _, row := range c.modules {
+		for _, d := range row {
+			if d {
+				dark++
+			}
+		}
+	}
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	if k > 0 {
+		result += k * n4
+	}
+	return result
+}
+
+// at returns the module [b] of row (or column) [a].
+func (c *Code) at(a int, b int, column bool) bool {
+	if column {
+		return c.modules[b][a]
+	}
+	return c.modules[a][b]
+}
+
+// light returns true if the modules [from, to) of row (or column) [a] are
+// light, counting the quiet zone outside the code as light.
+func (c *Code) light(a int, from int, to int, column bool) bool {
+	for b := from; b < to; b++ {
+		if b >= 0 && b < c.Size && c.at(a, b, column) {
+			return false
+		}
+	}
+	return true
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of [degree],
+// without its leading coefficient.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords of [data].
+func rsRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func bit(v int, i int) bool { return (v>>i)&1 != 0 }
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
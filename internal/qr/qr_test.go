@@ -0,0 +1,179 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	t.Parallel()
+
+	// "HELLO WORLD" at 1-M
+	// ref. https://www.thonky.com/qr-code-tutorial/error-correction-coding
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	exp := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(len(exp))); !bytes.Equal(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestTables(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		version  int
+		level    Level
+		dataLen  int
+		align    []int
+		rawCount int
+	}{
+		{version: 1, level: Medium, dataLen: 16, rawCount: 208},
+		{version: 2, level: Low, dataLen: 34, align: []int{6, 18}, rawCount: 359},
+		{version: 7, level: Quartile, dataLen: 88, align: []int{6, 22, 38}, rawCount: 1568},
+		{version: 32, level: High, dataLen: 845, align: []int{6, 34, 60, 86, 112, 138}, rawCount: 19723},
+		{version: 40, level: Low, dataLen: 2956, align: []int{6, 30, 58, 86, 114, 142, 170}, rawCount: 29648},
+	}
+	for _, tv := range tt {
+		if got := numDataCodewords(tv.version, tv.level); got != tv.dataLen {
+			t.Fatalf("version %d: expected %d data codewords, got %d", tv.version, tv.dataLen, got)
+		}
+		if got := alignmentPatternPositions(tv.version); !reflect.DeepEqual(got, tv.align) {
+			t.Fatalf("version %d: expected alignment patterns at %v, got %v", tv.version, tv.align, got)
+		}
+		if got := numRawDataModules(tv.version); got != tv.rawCount {
+			t.Fatalf("version %d: expected %d raw modules, got %d", tv.version, tv.rawCount, got)
+		}
+	}
+}
+
+// decode reads the data codewords back from [c], undoing the mask named by
+// its format bits and the block interleaving.
+func decode(t *testing.T, c *Code) []byte {
+	bits := 0
+	for i := 0; i <= 5; i++ {
+		if c.modules[i][8] {
+			bits |= 1 << i
+		}
+	}
+	// the second copy must match the first
+	for i := 0; i < 6; i++ {
+		if c.modules[8][c.Size-1-i] != bit(bits, i) {
+			t.Fatalf("format bit %d differs between copies", i)
+		}
+	}
+	for i, xy := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if c.modules[xy[1]][xy[0]] {
+			bits |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if c.modules[8][14-i] {
+			bits |= 1 << i
+		}
+	}
+	bits ^= 0x5412
+	if level := bits >> 13; level != formatBits[c.Level] {
+		t.Fatalf("expected level bits %d, got %d", formatBits[c.Level], level)
+	}
+	mask := (bits >> 10) & 7
+
+	c.applyMask(mask)
+	defer c.applyMask(mask)
+	raw := make([]byte, numRawDataModules(c.Version)/8)
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] && i < len(raw)*8 {
+					if c.modules[y][x] {
+						raw[i>>3] |= 0x80 >> (i & 7)
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// de-interleave the data codewords
+	nBlocks := numBlocks[c.Level][c.Version]
+	eccLen := eccCodewordsPerBlock[c.Level][c.Version]
+	numShortBlocks := nBlocks - len(raw)%nBlocks
+	shortDataLen := len(raw)/nBlocks - eccLen
+	blocks := make([][]byte, nBlocks)
+	k := 0
+	for idx := 0; idx <= shortDataLen; idx++ {
+		for j := range blocks {
+			if idx == shortDataLen && j < numShortBlocks {
+				continue
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+	data := []byte{}
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	return data
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		payload string
+		level   Level
+		version int
+	}{
+		{payload: "P-fuji18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t", level: Medium, version: 4},
+		{payload: "hello", level: High, version: 1},
+		{payload: strings.Repeat("0x00", 100), level: Medium, version: 15},
+		{payload: strings.Repeat("a", 1000), level: Low, version: 22},
+	}
+	for _, tv := range tt {
+		c, err := Encode([]byte(tv.payload), tv.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Version != tv.version || c.Size != tv.version*4+17 {
+			t.Fatalf("%d bytes: expected version %d, got %d (size %d)", len(tv.payload), tv.version, c.Version, c.Size)
+		}
+		data := decode(t, c)
+		// mode, 8 or 16 bit length, then the payload
+		count := charCountBits(c.Version)
+		bb := &bitBuffer{}
+		bb.append(0x4, 4)
+		bb.append(len(tv.payload), count)
+		for _, b := range []byte(tv.payload) {
+			bb.append(int(b), 8)
+		}
+		if prefix := bb.bytes()[:bb.n/8]; !bytes.Equal(data[:len(prefix)], prefix) {
+			t.Fatalf("%d bytes: payload not read back", len(tv.payload))
+		}
+		// light quiet zone and dark finder corners
+		if c.Dark(-1, 0) || !c.Dark(0, 0) || !c.Dark(c.Size-1, 0) || !c.Dark(0, c.Size-1) || c.Dark(c.Size-1, c.Size-1) && c.isFunction[c.Size-1][c.Size-1] {
+			t.Fatal("unexpected finder patterns")
+		}
+		lines := strings.Split(strings.TrimSuffix(c.Terminal(), "\n"), "\n")
+		if len(lines) != (c.Size+2*quietZone+1)/2 {
+			t.Fatalf("unexpected rendered height %d", len(lines))
+		}
+	}
+
+	if _, err := Encode(make([]byte, 3000), Low); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("expected %v, got %v", ErrTooLong, err)
+	}
+}
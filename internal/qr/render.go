@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package qr
+
+import "strings"
+
+// quietZone is the light margin around the code, in modules.
+const quietZone = 4
+
+// Terminal renders [c] with half-block characters (two rows per line).
+// Light modules are drawn as blocks, so that the code scans on the usual
+// light-on-dark terminals.
+func (c *Code) Terminal() string {
+	var sb strings.Builder
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			top, bottom := !c.Dark(x, y), !c.Dark(x, y+1)
+			// the last line of an odd height only has a top half
+			if y+1 >= c.Size+quietZone {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package redact scrubs secrets (private keys, mnemonics, auth tokens) from
+// every log and output path: the zap logger, error messages, printed output
+// and HTTP captures.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces every secret.
+const Redacted = "<redacted>"
+
+// minSecretLen is the shortest secret registered, so that a trivial value
+// (e.g., an empty password) never redacts unrelated output.
+const minSecretLen = 8
+
+// patterns match secrets that are redacted even if never registered.
+var patterns = []*regexp.Regexp{
+	// CB58-encoded private keys
+	regexp.MustCompile(`PrivateKey-[1-9A-HJ-NP-Za-km-z]{32,}`),
+}
+
+var (
+	mu      sync.RWMutex
+	secrets = map[string]struct{}{}
+	// sorted longest first, so a secret containing another is fully redacted
+	sorted []string
+)
+
+// Register redacts every occurrence of [ss] from now on. Surrounding
+// whitespace is ignored.
+func Register(ss ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range ss {
+		s = strings.TrimSpace(s)
+		if len(s) < minSecretLen {
+			continue
+		}
+		if _, ok := secrets[s]; ok {
+			continue
+		}
+		secrets[s] = struct{}{}
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+}
+
+// String returns [s] with every secret replaced by [Redacted].
+func String(s string) string {
+	mu.RLock()
+	for _, secret := range sorted {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	mu.RUnlock()
+	for _, p := range patterns {
+		s = p.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// Bytes returns [b] with every secret replaced by [Redacted].
+func Bytes(b []byte) []byte {
+	mu.RLock()
+	for _, secret := range sorted {
+		b = bytes.ReplaceAll(b, []byte(secret), []byte(Redacted))
+	}
+	mu.RUnlock()
+	for _, p := range patterns {
+		b = p.ReplaceAll(b, []byte(Redacted))
+	}
+	return b
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// Writer returns a writer that redacts each write to [w]. Secrets split
+// across writes are not redacted, which fmt never does.
+func Writer(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(Bytes(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type redactedError struct {
+	err error
+}
+
+// Error returns [err] with its message redacted, which still matches the
+// errors it wraps with [errors.Is] and [errors.As].
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+func (e *redactedError) Error() string { return String(e.err.Error()) }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+type core struct {
+	zapcore.Core
+}
+
+// Core wraps [c] to redact log messages and fields, for [zap.WrapCore].
+func Core(c zapcore.Core) zapcore.Core {
+	return &core{Core: c}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(Fields(fields))}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = String(ent.Message)
+	return c.Core.Write(ent, Fields(fields))
+}
+
+// Fields returns [fields] with string, error, stringer and reflected values
+// redacted.
+func Fields(fields []zapcore.Field) []zapcore.Field {
+	r := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = String(f.String)
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok {
+				f = zap.String(f.Key, String(err.Error()))
+			}
+		case zapcore.StringerType:
+			if s, ok := f.Interface.(fmt.Stringer); ok {
+				f = zap.String(f.Key, String(s.String()))
+			}
+		case zapcore.ReflectType:
+			if b, err := json.Marshal(f.Interface); err == nil {
+				f = zap.Any(f.Key, json.RawMessage(Bytes(b)))
+			}
+		}
+		r[i] = f
+	}
+	return r
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	Register("token-1234567890", "  token-1234567890-long  ", "short")
+	tt := []struct {
+		in  string
+		out string
+	}{
+		{in: "nothing to hide", out: "nothing to hide"},
+		{in: "auth token-1234567890 failed", out: "auth <redacted> failed"},
+		{in: "auth token-1234567890-long failed", out: "auth <redacted> failed"},
+		{in: "a short value", out: "a short value"},
+		{
+			in:  "key PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN loaded",
+			out: "key <redacted> loaded",
+		},
+		{in: "PrivateKey-abc", out: "PrivateKey-abc"},
+	}
+	for i, tv := range tt {
+		if out := String(tv.in); out != tv.out {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.out, out)
+		}
+		if out := string(Bytes([]byte(tv.in))); out != tv.out {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.out, out)
+		}
+	}
+}
+
+var errTest = errors.New("test error")
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	Register("error-secret-value")
+	err := Error(fmt.Errorf("%w: bad key error-secret-value", errTest))
+	if !errors.Is(err, errTest) {
+		t.Fatalf("expected %v to wrap %v", err, errTest)
+	}
+	if s := err.Error(); s != "test error: bad key <redacted>" {
+		t.Fatalf("unexpected message %q", s)
+	}
+	if Error(nil) != nil {
+		t.Fatal("expected nil")
+	}
+}
+
+func TestCore(t *testing.T) {
+	t.Parallel()
+
+	Register("log-secret-value")
+	buf := new(bytes.Buffer)
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	logger := zap.New(Core(zapcore.NewCore(enc, zapcore.AddSync(buf), zap.DebugLevel)))
+	logger.With(zap.String("with", "log-secret-value")).Info("msg log-secret-value",
+		zap.String("s", "log-secret-value"),
+		zap.Error(fmt.Errorf("failed with log-secret-value")),
+		zap.Any("m", map[string]string{"k": "log-secret-value"}),
+	)
+	out := buf.String()
+	if strings.Contains(out, "log-secret-value") {
+		t.Fatalf("secret logged: %s", out)
+	}
+	if strings.Count(out, Redacted) != 5 {
+		t.Fatalf("expected 5 redactions: %s", out)
+	}
+
+	buf.Reset()
+	w := Writer(buf)
+	if _, err := fmt.Fprintf(w, "token log-secret-value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "token <redacted>\n" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rollback reports what a multi-tx operation that failed midway
+// did, what it did not get to, and how each tx can be undone or resumed.
+package rollback
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Kinds of actions.
+const (
+	KindAddValidator       = "add validator"
+	KindCreateSubnet       = "create subnet"
+	KindAddSubnetValidator = "add subnet-validator"
+	KindCreateBlockchain   = "create blockchain"
+
+	KindRemoveSubnetValidator = "remove subnet-validator"
+)
+
+// Action is a tx of the operation.
+type Action struct {
+	Kind         string `json:"kind"`
+	NodeID       string `json:"nodeID,omitempty"`
+	SubnetID     string `json:"subnetID,omitempty"`
+	BlockchainID string `json:"blockchainID,omitempty"`
+	Name         string `json:"name,omitempty"`
+	// End is when the validation ends, for validator actions.
+	End *time.Time `json:"end,omitempty"`
+	// Weight is the subnet weight of the validator, for subnet validator
+	// actions.
+	Weight uint64 `json:"weight,omitempty"`
+	// VMID and GenesisPath are the VM and genesis file of the chain, for
+	// blockchain actions.
+	VMID        string `json:"vmID,omitempty"`
+	GenesisPath string `json:"genesisPath,omitempty"`
+
+	// Undo is the command that compensates the action, if any.
+	Undo string `json:"undo,omitempty"`
+	// Resume is the command that issues a remaining action.
+	Resume string `json:"resume,omitempty"`
+	// Note explains what can (or cannot) be done about the action.
+	Note string `json:"note,omitempty"`
+}
+
+// Report is the state of an operation when it failed.
+type Report struct {
+	Operation string    `json:"operation"`
+	NetworkID uint32    `json:"networkID"`
+	Failed    time.Time `json:"failed"`
+	Error     string    `json:"error,omitempty"`
+	Done      []Action  `json:"done"`
+	Remaining []Action  `json:"remaining"`
+}
+
+// New returns the report of [operation], with nothing planned yet.
+func New(operation string, networkID uint32) *Report {
+	return &Report{
+		Operation: operation,
+		NetworkID: networkID,
+		Done:      []Action{},
+		Remaining: []Action{},
+	}
+}
+
+// Plan adds [as] to the remaining actions.
+func (r *Report) Plan(as ...Action) {
+	r.Remaining = append(r.Remaining, as...)
+}
+
+// Complete moves the first remaining action of the same kind and node as
+// [a] to the done ones, replacing it with [a] (e.g., with the created ID
+// filled in).
+func (r *Report) Complete(a Action) {
+	for i, p := range r.Remaining {
+		if p.Kind == a.Kind && p.NodeID == a.NodeID {
+			r.Remaining = append(r.Remaining[:i:i], r.Remaining[i+1:]...)
+			break
+		}
+	}
+	r.Done = append(r.Done, a)
+}
+
+// Fail records that the operation stopped with [err] at [now].
+func (r *Report) Fail(err error, now time.Time) {
+	r.Failed = now.UTC()
+	r.Error = err.Error()
+}
+
+// Undoable returns the done actions that can be compensated.
+func (r *Report) Undoable() []Action {
+	as := []Action{}
+	for _, a := range r.Done {
+		if a.Undo != "" {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// Save writes the report to [p] as JSON.
+func (r *Report) Save(p string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rollback
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := New("wizard", 5)
+	r.Plan(
+		Action{Kind: KindAddValidator, NodeID: "NodeID-a"},
+		Action{Kind: KindAddValidator, NodeID: "NodeID-b"},
+		Action{Kind: KindCreateSubnet},
+		Action{Kind: KindAddSubnetValidator, NodeID: "NodeID-a"},
+	)
+	r.Complete(Action{Kind: KindAddValidator, NodeID: "NodeID-b"})
+	r.Complete(Action{Kind: KindCreateSubnet, SubnetID: "s", Undo: "subnet-cli decommission --subnet-id=s"})
+	r.Fail(errors.New("boom"), now)
+
+	if len(r.Done) != 2 || r.Done[1].SubnetID != "s" {
+		t.Fatalf("unexpected done actions %+v", r.Done)
+	}
+	exp := []Action{
+		{Kind: KindAddValidator, NodeID: "NodeID-a"},
+		{Kind: KindAddSubnetValidator, NodeID: "NodeID-a"},
+	}
+	if len(r.Remaining) != len(exp) {
+		t.Fatalf("expected %d remaining actions, got %+v", len(exp), r.Remaining)
+	}
+	for i := range exp {
+		if r.Remaining[i].Kind != exp[i].Kind || r.Remaining[i].NodeID != exp[i].NodeID {
+			t.Fatalf("#%d: expected %+v, got %+v", i, exp[i], r.Remaining[i])
+		}
+	}
+	if u := r.Undoable(); len(u) != 1 || u[0].Kind != KindCreateSubnet {
+		t.Fatalf("unexpected undoable actions %+v", u)
+	}
+
+	p := filepath.Join(t.TempDir(), "report.json")
+	if err := r.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Report
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Error != "boom" || !loaded.Failed.Equal(now) || len(loaded.Done) != 2 || len(loaded.Remaining) != 2 {
+		t.Fatalf("unexpected saved report %+v", loaded)
+	}
+}
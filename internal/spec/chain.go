@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseChain parses a chain definition of comma-separated key=value pairs
+// (e.g., "name=mychain,vm-id=tGas3T...,genesis=./genesis.json"), as passed
+// to "create blockchain --chain".
+func ParseChain(s string) (Chain, error) {
+	c := Chain{}
+	for _, kv := range strings.Split(s, ",") {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 {
+			return Chain{}, fmt.Errorf("%w: chain %q (expected key=value pairs)", ErrInvalidSpec, s)
+		}
+		k, v := strings.TrimSpace(kvs[0]), strings.TrimSpace(kvs[1])
+		switch k {
+		case "name":
+			c.Name = v
+		case "vm-id":
+			c.VMID = v
+		case "genesis":
+			c.Genesis = v
+		default:
+			return Chain{}, fmt.Errorf("%w: chain %q has unknown key %q", ErrInvalidSpec, s, k)
+		}
+	}
+	if c.Name == "" || c.VMID == "" || c.Genesis == "" {
+		return Chain{}, fmt.Errorf("%w: chain %q needs name, vm-id and genesis", ErrInvalidSpec, s)
+	}
+	return c, nil
+}
+
+// SubnetByID returns the subnet that references the existing subnet [id],
+// or the only such subnet if [id] is empty.
+func (s *Spec) SubnetByID(id string) (Subnet, bool) {
+	found := []Subnet{}
+	for _, sn := range s.Subnets {
+		if sn.ID == "" {
+			continue
+		}
+		if sn.ID == id {
+			return sn, true
+		}
+		found = append(found, sn)
+	}
+	if id == "" && len(found) == 1 {
+		return found[0], true
+	}
+	return Subnet{}, false
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseChain(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		s     string
+		chain Chain
+		err   error
+	}{
+		{
+			s:     "name=a,vm-id=tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH,genesis=./a.json",
+			chain: Chain{Name: "a", VMID: "tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH", Genesis: "./a.json"},
+		},
+		{
+			s:     "genesis = b.json, name = b, vm-id = x",
+			chain: Chain{Name: "b", VMID: "x", Genesis: "b.json"},
+		},
+		{s: "name=a,vm-id=x", err: ErrInvalidSpec},
+		{s: "name=a,vm=x,genesis=a.json", err: ErrInvalidSpec},
+		{s: "a", err: ErrInvalidSpec},
+	}
+	for i, tv := range tt {
+		c, err := ParseChain(tv.s)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		if !reflect.DeepEqual(c, tv.chain) {
+			t.Fatalf("#%d: expected %+v, got %+v", i, tv.chain, c)
+		}
+	}
+}
+
+func TestSubnetByID(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1", ""} {
+		sn, ok := s.SubnetByID(id)
+		if !ok || sn.Name != "b" {
+			t.Fatalf("#%d: expected subnet b, got %+v", i, sn)
+		}
+	}
+	if _, ok := s.SubnetByID("2bRCr6B4MiEfSjidDwxDpdCyviwnfUVqB2HGwhm947w9YYqb7r"); ok {
+		t.Fatal("unexpected subnet")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// SourceChain is a blockchain read from a live subnet.
+type SourceChain struct {
+	Name    string
+	VMID    ids.ID
+	Genesis []byte
+}
+
+// Clone returns a spec that recreates a subnet named [name] with the
+// validator [weights] and [chains] on [network]. The genesis of each chain
+// is written to [dir] as "<chain>.genesis", next to where the spec should
+// be saved.
+func Clone(network string, name string, weights map[ids.ShortID]uint64, chains []SourceChain, dir string) (*Spec, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	sn := Subnet{Name: name}
+	for nodeID, w := range weights {
+		sn.Validators = append(sn.Validators, SubnetValidator{
+			NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+			Weight: w,
+		})
+	}
+	sort.Slice(sn.Validators, func(i, j int) bool { return sn.Validators[i].NodeID < sn.Validators[j].NodeID })
+	for _, c := range chains {
+		genesis := c.Name + ".genesis"
+		if err := os.WriteFile(filepath.Join(dir, genesis), c.Genesis, 0o644); err != nil {
+			return nil, err
+		}
+		sn.Chains = append(sn.Chains, Chain{Name: c.Name, VMID: c.VMID.String(), Genesis: genesis})
+	}
+	return &Spec{
+		Version: CurrentVersion,
+		Network: network,
+		Subnets: []Subnet{sn},
+		dir:     dir,
+	}, nil
+}
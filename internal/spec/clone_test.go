@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	a, b := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	vmID := ids.GenerateTestID()
+	s, err := Clone("fuji", "staging", map[ids.ShortID]uint64{a: 10, b: 20}, []SourceChain{
+		{Name: "chain-a", VMID: vmID, Genesis: []byte(`{"a":1}`)},
+	}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	sn := s.Subnets[0]
+	if sn.Name != "staging" || len(sn.Validators) != 2 || sn.Validators[0].NodeID > sn.Validators[1].NodeID {
+		t.Fatalf("unexpected subnet %+v", sn)
+	}
+	b1, err := os.ReadFile(s.GenesisPath(sn.Chains[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b1) != `{"a":1}` || s.GenesisPath(sn.Chains[0]) != filepath.Join(dir, "chain-a.genesis") {
+		t.Fatalf("unexpected genesis %q at %q", b1, s.GenesisPath(sn.Chains[0]))
+	}
+	if plan := s.Plan(); len(plan) != 4 || plan[0].Kind != StepCreateSubnet {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDependencyCycle   = errors.New("dependency cycle")
+)
+
+// anyValidatorPrefix references the validators of a subnet as a group,
+// which is satisfied once any one of them is active.
+const anyValidatorPrefix = "subnet-validators/"
+
+// StepID returns the ID that "depends-on" entries reference the step by:
+//
+//	validator/<node-id>
+//	subnet/<subnet>
+//	subnet-validator/<subnet>/<node-id>
+//	chain/<subnet>/<chain>
+//
+// "subnet-validators/<subnet>" references all validators of a subnet and
+// is satisfied once any one of them is active.
+func (s *Spec) StepID(st Step) string {
+	switch st.Kind {
+	case StepAddValidator:
+		return "validator/" + s.Validators[st.Index].NodeID
+	case StepCreateSubnet:
+		return "subnet/" + s.Subnets[st.Subnet].Name
+	case StepAddSubnetValidator:
+		return "subnet-validator/" + s.Subnets[st.Subnet].Name + "/" + s.Subnets[st.Subnet].Validators[st.Index].NodeID
+	case StepCreateBlockchain:
+		return "chain/" + s.Subnets[st.Subnet].Name + "/" + s.Subnets[st.Subnet].Chains[st.Index].Name
+	}
+	return string(st.Kind)
+}
+
+// Dependency is a set of steps that must complete before a step runs.
+type Dependency struct {
+	// ID is the referenced step ID.
+	ID    string
+	Steps []Step
+	// Any is set if completing one of [Steps] is enough.
+	Any bool
+}
+
+// dependsOn returns the "depends-on" entries of the step.
+func (s *Spec) dependsOn(st Step) []string {
+	switch st.Kind {
+	case StepAddValidator:
+		return s.Validators[st.Index].DependsOn
+	case StepCreateSubnet:
+		return s.Subnets[st.Subnet].DependsOn
+	case StepAddSubnetValidator:
+		return s.Subnets[st.Subnet].Validators[st.Index].DependsOn
+	case StepCreateBlockchain:
+		return s.Subnets[st.Subnet].Chains[st.Index].DependsOn
+	}
+	return nil
+}
+
+// resolve returns the steps referenced by [id], or false if none matches.
+// A reference to a subnet that already exists ("id" set) resolves to no
+// step.
+func (s *Spec) resolve(steps []Step, id string) (Dependency, bool) {
+	if strings.HasPrefix(id, anyValidatorPrefix) {
+		name := strings.TrimPrefix(id, anyValidatorPrefix)
+		d := Dependency{ID: id, Any: true}
+		found := false
+		for si, sn := range s.Subnets {
+			if sn.Name != name {
+				continue
+			}
+			found = true
+			for _, st := range steps {
+				if st.Kind == StepAddSubnetValidator && st.Subnet == si {
+					d.Steps = append(d.Steps, st)
+				}
+			}
+		}
+		return d, found && len(d.Steps) > 0
+	}
+	for _, st := range steps {
+		if s.StepID(st) == id {
+			return Dependency{ID: id, Steps: []Step{st}}, true
+		}
+	}
+	for _, sn := range s.Subnets {
+		if sn.ID != "" && "subnet/"+sn.Name == id {
+			return Dependency{ID: id}, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// Dependencies returns what [st] waits for: its "depends-on" entries plus
+// the implicit ones (subnet validators and chains depend on their subnet
+// being created, and subnet validators on the primary network validator of
+// the same node if the spec adds it). Unknown references are skipped; see
+// [Spec.Validate].
+func (s *Spec) Dependencies(st Step) []Dependency {
+	return s.dependencies(s.steps(), st)
+}
+
+func (s *Spec) dependencies(steps []Step, st Step) []Dependency {
+	refs := []string{}
+	switch st.Kind {
+	case StepAddSubnetValidator:
+		nodeID := s.Subnets[st.Subnet].Validators[st.Index].NodeID
+		for _, v := range s.Validators {
+			if v.NodeID == nodeID {
+				refs = append(refs, "validator/"+nodeID)
+				break
+			}
+		}
+		fallthrough
+	case StepCreateBlockchain:
+		if s.Subnets[st.Subnet].ID == "" {
+			refs = append(refs, "subnet/"+s.Subnets[st.Subnet].Name)
+		}
+	}
+	refs = append(refs, s.dependsOn(st)...)
+
+	seen := map[string]bool{}
+	deps := []Dependency{}
+	for _, id := range refs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		d, ok := s.resolve(steps, id)
+		if !ok {
+			continue
+		}
+		if d.Any {
+			// a subnet validator waiting for any of its peers never waits
+			// for itself
+			others := []Step{}
+			for _, dst := range d.Steps {
+				if dst != st {
+					others = append(others, dst)
+				}
+			}
+			d.Steps = others
+		}
+		if len(d.Steps) > 0 {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+// order sorts [steps] topologically, keeping the given order among steps
+// that do not depend on each other. Steps in a cycle keep their relative
+// order after all others; see [Spec.Validate].
+func (s *Spec) order(steps []Step) []Step {
+	pos := make(map[Step]int, len(steps))
+	for i, st := range steps {
+		pos[st] = i
+	}
+	indegree := make([]int, len(steps))
+	dependents := make([][]int, len(steps))
+	for i, st := range steps {
+		for _, d := range s.dependencies(steps, st) {
+			for _, dst := range d.Steps {
+				j := pos[dst]
+				if j == i {
+					continue
+				}
+				indegree[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	ready := []int{}
+	for i := range steps {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	ordered := make([]Step, 0, len(steps))
+	done := make([]bool, len(steps))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, steps[i])
+		done[i] = true
+		for _, j := range dependents[i] {
+			indegree[j]--
+			if indegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	for i, st := range steps {
+		if !done[i] {
+			ordered = append(ordered, st)
+		}
+	}
+	return ordered
+}
+
+// checkDependencies reports unknown "depends-on" references and dependency
+// cycles.
+func (s *Spec) checkDependencies(add func(where string, err error)) {
+	steps := s.steps()
+	for _, st := range steps {
+		for _, id := range s.dependsOn(st) {
+			if _, ok := s.resolve(steps, id); !ok {
+				add(s.where(st), fmt.Errorf("%w: %q", ErrUnknownDependency, id))
+			}
+		}
+	}
+
+	ordered := s.order(steps)
+	placed := make(map[Step]bool, len(ordered))
+	for _, st := range ordered {
+		for _, d := range s.dependencies(steps, st) {
+			for _, dst := range d.Steps {
+				if !placed[dst] {
+					add(s.where(st), fmt.Errorf("%w: %s", ErrDependencyCycle, d.ID))
+					break
+				}
+			}
+		}
+		placed[st] = true
+	}
+}
+
+// where returns the location of the step's entry in the spec.
+func (s *Spec) where(st Step) string {
+	switch st.Kind {
+	case StepAddValidator:
+		return fmt.Sprintf("validators[%d]", st.Index)
+	case StepCreateSubnet:
+		return fmt.Sprintf("subnets[%d]", st.Subnet)
+	case StepAddSubnetValidator:
+		return fmt.Sprintf("subnets[%d].validators[%d]", st.Subnet, st.Index)
+	case StepCreateBlockchain:
+		return fmt.Sprintf("subnets[%d].chains[%d]", st.Subnet, st.Index)
+	}
+	return string(st.Kind)
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errTest = errors.New("test")
+
+func TestPlanDependsOn(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		replace [2]string
+		exp     []string
+	}{
+		{
+			name: "implicit",
+			exp: []string{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet/a",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"chain/a/c",
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			},
+		},
+		{
+			name:    "validator after chain",
+			replace: [2]string{"duration: 14d", "duration: 14d\n    depends-on: [chain/a/c]"},
+			exp: []string{
+				"subnet/a",
+				"chain/a/c",
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			},
+		},
+		{
+			name:    "chain after another subnet",
+			replace: [2]string{"genesis: genesis.json", "genesis: genesis.json\n        depends-on: [subnet-validators/b]"},
+			exp: []string{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet/a",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"chain/a/c",
+			},
+		},
+		{
+			name:    "existing subnet",
+			replace: [2]string{"weight: 20", "weight: 20\n        depends-on: [subnet/b, chain/a/c]"},
+			exp: []string{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet/a",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"chain/a/c",
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			},
+		},
+		{
+			name:    "cycle moves last",
+			replace: [2]string{"duration: 14d", "duration: 14d\n    depends-on: [subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4]"},
+			exp: []string{
+				"subnet/a",
+				"chain/a/c",
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			},
+		},
+	}
+	for i, tv := range tt {
+		b := testSpec
+		if tv.replace[0] != "" {
+			b = strings.Replace(b, tv.replace[0], tv.replace[1], 1)
+		}
+		s, err := Parse([]byte(b))
+		if err != nil {
+			t.Fatalf("#%d(%s): %v", i, tv.name, err)
+		}
+		plan := s.Plan()
+		if len(plan) != len(tv.exp) {
+			t.Fatalf("#%d(%s): expected %d steps, got %d", i, tv.name, len(tv.exp), len(plan))
+		}
+		for j, st := range plan {
+			if id := s.StepID(st); id != tv.exp[j] {
+				t.Fatalf("#%d(%s): step %d: expected %q, got %q", i, tv.name, j, tv.exp[j], id)
+			}
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		replace [2]string
+		fail    string
+		exp     map[string]Status
+		awaited []string
+	}{
+		{
+			name: "all done",
+			exp: map[string]Status{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+				"subnet/a": StatusDone,
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+				"chain/a/c": StatusDone,
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+			},
+			awaited: []string{"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4", "subnet/a"},
+		},
+		{
+			name: "failed validator skips its dependents",
+			fail: "validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			exp: map[string]Status{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusFailed,
+				"subnet/a": StatusDone,
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusSkipped,
+				"chain/a/c": StatusDone,
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusSkipped,
+			},
+			awaited: []string{"subnet/a"},
+		},
+		{
+			name:    "any subnet validator",
+			replace: [2]string{"genesis: genesis.json", "genesis: genesis.json\n        depends-on: [subnet-validators/a]"},
+			exp: map[string]Status{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+				"subnet/a": StatusDone,
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+				"chain/a/c": StatusDone,
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+			},
+			awaited: []string{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+				"subnet/a",
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			},
+		},
+		{
+			name:    "failed subnet validator skips chain",
+			replace: [2]string{"genesis: genesis.json", "genesis: genesis.json\n        depends-on: [subnet-validators/a]"},
+			fail:    "subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4",
+			exp: map[string]Status{
+				"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+				"subnet/a": StatusDone,
+				"subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusFailed,
+				"chain/a/c": StatusSkipped,
+				"subnet-validator/b/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4": StatusDone,
+			},
+			awaited: []string{"validator/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4", "subnet/a"},
+		},
+	}
+	for i, tv := range tt {
+		b := testSpec
+		if tv.replace[0] != "" {
+			b = strings.Replace(b, tv.replace[0], tv.replace[1], 1)
+		}
+		s, err := Parse([]byte(b))
+		if err != nil {
+			t.Fatalf("#%d(%s): %v", i, tv.name, err)
+		}
+		awaited := []string{}
+		reported := map[string][]Status{}
+		results := Executor{
+			Run: func(_ context.Context, st Step) error {
+				if s.StepID(st) == tv.fail {
+					return errTest
+				}
+				return nil
+			},
+			Await: func(_ context.Context, st Step) error {
+				awaited = append(awaited, s.StepID(st))
+				return nil
+			},
+			Report: func(st Step, status Status, _ error) {
+				reported[s.StepID(st)] = append(reported[s.StepID(st)], status)
+			},
+		}.Execute(context.Background(), s)
+		if len(results) != len(tv.exp) {
+			t.Fatalf("#%d(%s): expected %d results, got %d", i, tv.name, len(tv.exp), len(results))
+		}
+		for _, r := range results {
+			id := s.StepID(r.Step)
+			if r.Status != tv.exp[id] {
+				t.Fatalf("#%d(%s): %s: expected %s, got %s (%v)", i, tv.name, id, tv.exp[id], r.Status, r.Err)
+			}
+			switch r.Status {
+			case StatusFailed:
+				if !errors.Is(r.Err, errTest) {
+					t.Fatalf("#%d(%s): %s: expected %v, got %v", i, tv.name, id, errTest, r.Err)
+				}
+			case StatusSkipped:
+				if !errors.Is(r.Err, ErrDependencyFailed) {
+					t.Fatalf("#%d(%s): %s: expected %v, got %v", i, tv.name, id, ErrDependencyFailed, r.Err)
+				}
+			}
+			if last := reported[id][len(reported[id])-1]; last != r.Status {
+				t.Fatalf("#%d(%s): %s: last reported %s, expected %s", i, tv.name, id, last, r.Status)
+			}
+		}
+		if strings.Join(awaited, ",") != strings.Join(tv.awaited, ",") {
+			t.Fatalf("#%d(%s): expected awaits %v, got %v", i, tv.name, tv.awaited, awaited)
+		}
+	}
+}
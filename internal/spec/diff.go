@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+)
+
+// State is the live state of the networks a spec declares, keyed by subnet
+// ID ([constants.PrimaryNetworkID] for the primary network).
+type State map[ids.ID]*SubnetState
+
+type SubnetState struct {
+	// Validators maps current validators to their weight (stake amount for
+	// the primary network).
+	Validators map[ids.ShortID]uint64
+	// Pending are the validators added but not yet validating.
+	Pending map[ids.ShortID]bool
+	Chains  []ChainState
+}
+
+type ChainState struct {
+	ID   ids.ID
+	Name string
+	VMID ids.ID
+}
+
+type ChangeKind string
+
+const (
+	// ChangeMissing is declared in the spec but absent on chain.
+	ChangeMissing ChangeKind = "missing"
+	// ChangeExtra is on chain but not declared in the spec.
+	ChangeExtra ChangeKind = "extra"
+	// ChangeMismatch is on chain with different parameters.
+	ChangeMismatch ChangeKind = "mismatch"
+)
+
+// Change is a single difference between a spec and the live state.
+type Change struct {
+	Kind ChangeKind
+	// Subnet is the spec subnet name ("" for the primary network).
+	Subnet string
+	// Object describes what differs (e.g., "validator NodeID-...").
+	Object   string
+	Expected string
+	Actual   string
+}
+
+func (c Change) String() string {
+	where := "primary network"
+	if c.Subnet != "" {
+		where = fmt.Sprintf("subnet %q", c.Subnet)
+	}
+	switch c.Kind {
+	case ChangeMismatch:
+		return fmt.Sprintf("%s: %s mismatch: expected %s, got %s", where, c.Object, c.Expected, c.Actual)
+	case ChangeMissing:
+		if c.Actual != "" {
+			return fmt.Sprintf("%s: missing %s (%s)", where, c.Object, c.Actual)
+		}
+	}
+	return fmt.Sprintf("%s: %s %s", where, c.Kind, c.Object)
+}
+
+// Diff compares the spec with the live state. Extra primary network
+// validators are not reported since public networks have many that no
+// single spec declares. Subnets without an ID are reported missing.
+func (s *Spec) Diff(st State) []Change {
+	changes := []Change{}
+
+	primary := st[constants.PrimaryNetworkID]
+	if primary == nil {
+		primary = &SubnetState{}
+	}
+	for _, v := range s.Validators {
+		nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			continue
+		}
+		stake, ok := primary.Validators[nodeID]
+		switch {
+		case !ok && primary.Pending[nodeID]:
+			changes = append(changes, Change{Kind: ChangeMissing, Object: "validator " + v.NodeID, Actual: "pending"})
+		case !ok:
+			changes = append(changes, Change{Kind: ChangeMissing, Object: "validator " + v.NodeID})
+		case v.Stake != "":
+			exp, err := amount.Parse(v.Stake)
+			if err == nil && exp != stake {
+				changes = append(changes, Change{
+					Kind:     ChangeMismatch,
+					Object:   "stake of " + v.NodeID,
+					Expected: amount.Format(exp),
+					Actual:   amount.Format(stake),
+				})
+			}
+		}
+	}
+
+	for _, sn := range s.Subnets {
+		if sn.ID == "" {
+			changes = append(changes, Change{Kind: ChangeMissing, Subnet: sn.Name, Object: "subnet", Actual: "no id"})
+			continue
+		}
+		subnetID, err := ids.FromString(sn.ID)
+		if err != nil {
+			continue
+		}
+		ss := st[subnetID]
+		if ss == nil {
+			changes = append(changes, Change{Kind: ChangeMissing, Subnet: sn.Name, Object: "subnet " + sn.ID})
+			continue
+		}
+
+		declared := map[ids.ShortID]bool{}
+		for _, v := range sn.Validators {
+			nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+			if err != nil {
+				continue
+			}
+			declared[nodeID] = true
+			w, ok := ss.Validators[nodeID]
+			switch {
+			case !ok && ss.Pending[nodeID]:
+				changes = append(changes, Change{Kind: ChangeMissing, Subnet: sn.Name, Object: "validator " + v.NodeID, Actual: "pending"})
+			case !ok:
+				changes = append(changes, Change{Kind: ChangeMissing, Subnet: sn.Name, Object: "validator " + v.NodeID})
+			case v.Weight != 0 && v.Weight != w:
+				changes = append(changes, Change{
+					Kind:     ChangeMismatch,
+					Subnet:   sn.Name,
+					Object:   "weight of " + v.NodeID,
+					Expected: fmt.Sprint(v.Weight),
+					Actual:   fmt.Sprint(w),
+				})
+			}
+		}
+		extra := []string{}
+		for nodeID := range ss.Validators {
+			if !declared[nodeID] {
+				extra = append(extra, nodeID.PrefixedString(constants.NodeIDPrefix))
+			}
+		}
+		sort.Strings(extra)
+		for _, nodeID := range extra {
+			changes = append(changes, Change{Kind: ChangeExtra, Subnet: sn.Name, Object: "validator " + nodeID})
+		}
+
+		chains := map[string]ChainState{}
+		for _, c := range ss.Chains {
+			chains[c.Name] = c
+		}
+		for _, c := range sn.Chains {
+			live, ok := chains[c.Name]
+			if !ok {
+				changes = append(changes, Change{Kind: ChangeMissing, Subnet: sn.Name, Object: fmt.Sprintf("chain %q", c.Name)})
+				continue
+			}
+			delete(chains, c.Name)
+			if live.VMID.String() != c.VMID {
+				changes = append(changes, Change{
+					Kind:     ChangeMismatch,
+					Subnet:   sn.Name,
+					Object:   fmt.Sprintf("vm of chain %q", c.Name),
+					Expected: c.VMID,
+					Actual:   live.VMID.String(),
+				})
+			}
+		}
+		for _, c := range ss.Chains {
+			if _, ok := chains[c.Name]; ok {
+				changes = append(changes, Change{Kind: ChangeExtra, Subnet: sn.Name, Object: fmt.Sprintf("chain %q (%s)", c.Name, c.ID)})
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := ids.ShortFromPrefixedString("NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4", constants.NodeIDPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ids.ShortFromPrefixedString("NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", constants.NodeIDPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subnetID, err := ids.FromString("24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		state State
+		exp   []Change
+	}{
+		{
+			state: State{},
+			exp: []Change{
+				{Kind: ChangeMissing, Object: "validator NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4"},
+				{Kind: ChangeMissing, Subnet: "a", Object: "subnet", Actual: "no id"},
+				{Kind: ChangeMissing, Subnet: "b", Object: "subnet 24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1"},
+			},
+		},
+		{
+			state: State{
+				constants.PrimaryNetworkID: {Validators: map[ids.ShortID]uint64{nodeID: 2000 * units.Avax}},
+				subnetID: {
+					Validators: map[ids.ShortID]uint64{nodeID: 20},
+				},
+			},
+			exp: []Change{
+				{Kind: ChangeMissing, Subnet: "a", Object: "subnet", Actual: "no id"},
+			},
+		},
+		{
+			state: State{
+				constants.PrimaryNetworkID: {Validators: map[ids.ShortID]uint64{nodeID: 3000 * units.Avax}},
+				subnetID: {
+					Validators: map[ids.ShortID]uint64{nodeID: 10, other: 5},
+					Chains:     []ChainState{{Name: "x"}},
+				},
+			},
+			exp: []Change{
+				{Kind: ChangeMismatch, Object: "stake of NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4", Expected: "2000avax", Actual: "3000avax"},
+				{Kind: ChangeMissing, Subnet: "a", Object: "subnet", Actual: "no id"},
+				{Kind: ChangeMismatch, Subnet: "b", Object: "weight of NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4", Expected: "20", Actual: "10"},
+				{Kind: ChangeExtra, Subnet: "b", Object: "validator NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"},
+				{Kind: ChangeExtra, Subnet: "b", Object: `chain "x" (11111111111111111111111111111111LpoYY)`},
+			},
+		},
+	}
+	for i, tv := range tt {
+		changes := s.Diff(tv.state)
+		if len(changes) != len(tv.exp) {
+			t.Fatalf("#%d: expected %d changes, got %+v", i, len(tv.exp), changes)
+		}
+		for j := range tv.exp {
+			if changes[j] != tv.exp[j] {
+				t.Fatalf("#%d.%d: expected %+v, got %+v", i, j, tv.exp[j], changes[j])
+			}
+		}
+	}
+}
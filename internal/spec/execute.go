@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrDependencyFailed = errors.New("dependency not satisfied")
+
+// Status is the execution status of a step.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of a step.
+type Result struct {
+	Step   Step
+	Status Status
+	Err    error
+}
+
+// Executor runs the steps of a spec in dependency order (see [Spec.Plan]);
+// steps whose dependencies failed or were skipped are skipped, the others
+// still run.
+type Executor struct {
+	// Run issues the tx of a step.
+	Run func(ctx context.Context, st Step) error
+	// Await, if not nil, blocks until a step that completed is effective
+	// (e.g., a validator is active) before its first dependent runs. It is
+	// called at most once per step.
+	Await func(ctx context.Context, st Step) error
+	// Report, if not nil, is called on every status change.
+	Report func(st Step, status Status, err error)
+}
+
+// Execute runs the steps of [s] and returns their results in plan order.
+func (e Executor) Execute(ctx context.Context, s *Spec) []Result {
+	steps := s.steps()
+	plan := s.order(steps)
+	status := make(map[Step]Status, len(plan))
+	for _, st := range plan {
+		status[st] = StatusPending
+	}
+	awaited := map[Step]error{}
+	await := func(st Step) error {
+		if e.Await == nil {
+			return nil
+		}
+		if err, ok := awaited[st]; ok {
+			return err
+		}
+		err := e.Await(ctx, st)
+		awaited[st] = err
+		return err
+	}
+	report := func(st Step, to Status, err error) {
+		status[st] = to
+		if e.Report != nil {
+			e.Report(st, to, err)
+		}
+	}
+
+	results := make([]Result, 0, len(plan))
+	for _, st := range plan {
+		err := ctx.Err()
+		if err == nil {
+			for _, d := range s.dependencies(steps, st) {
+				if err = e.satisfy(d, status, await); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			report(st, StatusSkipped, err)
+			results = append(results, Result{Step: st, Status: StatusSkipped, Err: err})
+			continue
+		}
+
+		report(st, StatusRunning, nil)
+		if err := e.Run(ctx, st); err != nil {
+			report(st, StatusFailed, err)
+			results = append(results, Result{Step: st, Status: StatusFailed, Err: err})
+			continue
+		}
+		report(st, StatusDone, nil)
+		results = append(results, Result{Step: st, Status: StatusDone})
+	}
+	return results
+}
+
+// satisfy returns nil once [d] is met: all (or, if [d.Any], one) of its
+// steps are done and effective.
+func (e Executor) satisfy(d Dependency, status map[Step]Status, await func(Step) error) error {
+	var last error
+	for _, st := range d.Steps {
+		if status[st] != StatusDone {
+			last = fmt.Errorf("%w: %s is %s", ErrDependencyFailed, d.ID, status[st])
+			if !d.Any {
+				return last
+			}
+			continue
+		}
+		if err := await(st); err != nil {
+			last = fmt.Errorf("%w: %s: %v", ErrDependencyFailed, d.ID, err)
+			if !d.Any {
+				return last
+			}
+			continue
+		}
+		if d.Any {
+			return nil
+		}
+	}
+	return last
+}
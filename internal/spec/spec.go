@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package spec defines the declarative subnet deployment file consumed by
+// "subnet-cli simulate" and friends.
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+var ErrInvalidSpec = errors.New("invalid spec")
+
+// Spec describes a full subnet deployment.
+//
+//	version: 1
+//	network: fuji
+//	private-key-path: .insecure.test.key
+//	validators:
+//	  - node-id: NodeID-...
+//	    stake: 2000avax
+//	    duration: 14d
+//	subnets:
+//	  - name: mysubnet
+//	    validators:
+//	      - node-id: NodeID-...
+//	        weight: 1000
+//	    chains:
+//	      - name: mychain
+//	        vm-id: tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH
+//	        genesis: ./genesis.json
+//	        depends-on: [subnet-validators/mysubnet]
+//
+// Entries may depend on other entries by step ID (see [Spec.StepID]);
+// see [Spec.Dependencies] for the implicit dependencies.
+type Spec struct {
+	// Version is the schema version (see [CurrentVersion]).
+	Version int    `yaml:"version"`
+	Network string `yaml:"network,omitempty"`
+	URI     string `yaml:"uri,omitempty"`
+	// PrivateKeyPath is the key that pays for and signs the txs.
+	PrivateKeyPath string `yaml:"private-key-path,omitempty"`
+
+	// Validators are the primary network validators to add.
+	Validators []Validator `yaml:"validators,omitempty"`
+	Subnets    []Subnet    `yaml:"subnets,omitempty"`
+
+	// dir is the directory of the spec file; relative genesis paths are
+	// resolved against it.
+	dir string
+}
+
+type Validator struct {
+	NodeID           string   `yaml:"node-id"`
+	Stake            string   `yaml:"stake,omitempty"`
+	Start            string   `yaml:"start,omitempty"`
+	End              string   `yaml:"end,omitempty"`
+	Duration         string   `yaml:"duration,omitempty"`
+	RewardFeePercent uint32   `yaml:"reward-fee-percent,omitempty"`
+	DependsOn        []string `yaml:"depends-on,omitempty"`
+}
+
+type SubnetValidator struct {
+	NodeID    string   `yaml:"node-id"`
+	Weight    uint64   `yaml:"weight,omitempty"`
+	DependsOn []string `yaml:"depends-on,omitempty"`
+}
+
+type Subnet struct {
+	Name string `yaml:"name"`
+	// ID references an already created subnet.
+	ID         string            `yaml:"id,omitempty"`
+	Validators []SubnetValidator `yaml:"validators,omitempty"`
+	Chains     []Chain           `yaml:"chains,omitempty"`
+	DependsOn  []string          `yaml:"depends-on,omitempty"`
+}
+
+type Chain struct {
+	Name      string   `yaml:"name"`
+	VMID      string   `yaml:"vm-id"`
+	Genesis   string   `yaml:"genesis"`
+	DependsOn []string `yaml:"depends-on,omitempty"`
+}
+
+// Load reads and parses the spec at [p].
+func Load(p string) (*Spec, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	s.dir = filepath.Dir(p)
+	return s, nil
+}
+
+// Parse parses a spec; relative genesis paths resolve against the current
+// directory.
+func Parse(b []byte) (*Spec, error) {
+	s := new(Spec)
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	return s, nil
+}
+
+// Save writes the spec to [p].
+func (s *Spec) Save(p string) error {
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, b, 0o644)
+}
+
+// KeyPath returns the path of the spec private key ("" if unset).
+func (s *Spec) KeyPath() string {
+	if s.PrivateKeyPath == "" || filepath.IsAbs(s.PrivateKeyPath) || s.dir == "" {
+		return s.PrivateKeyPath
+	}
+	return filepath.Join(s.dir, s.PrivateKeyPath)
+}
+
+// GenesisPath returns the path of the chain genesis file.
+func (s *Spec) GenesisPath(c Chain) string {
+	if filepath.IsAbs(c.Genesis) || s.dir == "" {
+		return c.Genesis
+	}
+	return filepath.Join(s.dir, c.Genesis)
+}
+
+type StepKind string
+
+const (
+	StepAddValidator       StepKind = "add validator"
+	StepCreateSubnet       StepKind = "create subnet"
+	StepAddSubnetValidator StepKind = "add subnet-validator"
+	StepCreateBlockchain   StepKind = "create blockchain"
+)
+
+// Step is a single tx the spec requires.
+type Step struct {
+	Kind StepKind
+	// Subnet is the index into [Spec.Subnets] (-1 for the primary network).
+	Subnet int
+	// Index is the index of the validator or chain within its list.
+	Index int
+}
+
+// Plan returns the txs required to deploy the spec in issuance order:
+// primary validators, then for each subnet the subnet itself (unless it
+// already exists), its validators and its chains, with steps moved after
+// their dependencies (see [Spec.Dependencies]).
+func (s *Spec) Plan() []Step {
+	return s.order(s.steps())
+}
+
+// steps returns the txs required to deploy the spec in declaration order.
+func (s *Spec) steps() []Step {
+	steps := []Step{}
+	for i := range s.Validators {
+		steps = append(steps, Step{Kind: StepAddValidator, Subnet: -1, Index: i})
+	}
+	for si, sn := range s.Subnets {
+		if sn.ID == "" {
+			steps = append(steps, Step{Kind: StepCreateSubnet, Subnet: si})
+		}
+		for i := range sn.Validators {
+			steps = append(steps, Step{Kind: StepAddSubnetValidator, Subnet: si, Index: i})
+		}
+		for i := range sn.Chains {
+			steps = append(steps, Step{Kind: StepCreateBlockchain, Subnet: si, Index: i})
+		}
+	}
+	return steps
+}
+
+// Describe returns a human readable summary of the step.
+func (s *Spec) Describe(st Step) string {
+	switch st.Kind {
+	case StepAddValidator:
+		return fmt.Sprintf("%s %s", st.Kind, s.Validators[st.Index].NodeID)
+	case StepCreateSubnet:
+		return fmt.Sprintf("%s %q", st.Kind, s.Subnets[st.Subnet].Name)
+	case StepAddSubnetValidator:
+		return fmt.Sprintf("%s %s to %q", st.Kind, s.Subnets[st.Subnet].Validators[st.Index].NodeID, s.Subnets[st.Subnet].Name)
+	case StepCreateBlockchain:
+		return fmt.Sprintf("%s %q on %q", st.Kind, s.Subnets[st.Subnet].Chains[st.Index].Name, s.Subnets[st.Subnet].Name)
+	}
+	return string(st.Kind)
+}
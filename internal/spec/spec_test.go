@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"errors"
+	"testing"
+)
+
+const testSpec = `
+version: 1
+network: fuji
+validators:
+  - node-id: NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4
+    stake: 2000avax
+    duration: 14d
+subnets:
+  - name: a
+    validators:
+      - node-id: NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4
+    chains:
+      - name: c
+        vm-id: tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH
+        genesis: genesis.json
+  - name: b
+    id: 24tZhrm8j8GCJRE9PomW8FaeqbgGS4UAQjJnqqn8pq5NwYSYV1
+    validators:
+      - node-id: NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4
+        weight: 20
+`
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Step{
+		{Kind: StepAddValidator, Subnet: -1, Index: 0},
+		{Kind: StepCreateSubnet, Subnet: 0},
+		{Kind: StepAddSubnetValidator, Subnet: 0, Index: 0},
+		{Kind: StepCreateBlockchain, Subnet: 0, Index: 0},
+		{Kind: StepAddSubnetValidator, Subnet: 1, Index: 0},
+	}
+	plan := s.Plan()
+	if len(plan) != len(exp) {
+		t.Fatalf("expected %d steps, got %d", len(exp), len(plan))
+	}
+	for i := range exp {
+		if plan[i] != exp[i] {
+			t.Fatalf("#%d: expected %+v, got %+v", i, exp[i], plan[i])
+		}
+	}
+	if d := s.Describe(plan[3]); d != `create blockchain "c" on "a"` {
+		t.Fatalf("unexpected description %q", d)
+	}
+}
+
+func TestParseUnknownField(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Parse([]byte("subnet: []\n")); !errors.Is(err, ErrInvalidSpec) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSpec, err)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	"github.com/ava-labs/subnet-cli/internal/amount"
+	"github.com/ava-labs/subnet-cli/internal/timeexpr"
+	"github.com/ava-labs/subnet-cli/internal/window"
+)
+
+const (
+	// CurrentVersion is the spec schema version this release reads.
+	CurrentVersion = 1
+
+	// DefaultStartBuffer is how far in the future a validation starts when
+	// no start is given.
+	DefaultStartBuffer = 30 * time.Second
+	// DefaultEnd is the validation end when neither an end nor a duration
+	// is given.
+	DefaultEnd = "now+300d"
+
+	minRewardFeePercent = 2
+	maxRewardFeePercent = 100
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported spec version")
+	ErrUnknownNetwork     = errors.New("unknown network")
+	ErrDuplicate          = errors.New("duplicate entry")
+	ErrEmptyName          = errors.New("empty name")
+	ErrInvalidNodeID      = errors.New("invalid node ID")
+	ErrInvalidID          = errors.New("invalid ID")
+	ErrStakeTooLow        = errors.New("stake below minimum")
+	ErrInvalidRewardFee   = errors.New("invalid reward fee percent")
+	ErrMissingGenesis     = errors.New("missing genesis file")
+)
+
+// Violations is the set of all checks that a spec failed.
+type Violations []error
+
+func (vs Violations) Error() string {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		ss[i] = v.Error()
+	}
+	return strings.Join(ss, "; ")
+}
+
+// Is returns true if any of the violations matches [target].
+func (vs Violations) Is(target error) bool {
+	for _, v := range vs {
+		if errors.Is(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkID returns the network ID of [Spec.Network] (0 if unset).
+func (s *Spec) NetworkID() (uint32, error) {
+	if s.Network == "" {
+		return 0, nil
+	}
+	id, err := constants.NetworkID(s.Network)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownNetwork, s.Network)
+	}
+	return id, nil
+}
+
+// Window resolves the validation window relative to [now].
+func (v Validator) Window(now time.Time) (w window.Window, err error) {
+	w.Start = now.Add(DefaultStartBuffer)
+	if v.Start != "" {
+		if w.Start, err = timeexpr.Parse(v.Start, now); err != nil {
+			return w, err
+		}
+	}
+	switch {
+	case v.Duration != "":
+		d, err := timeexpr.ParseDuration(v.Duration)
+		if err != nil {
+			return w, err
+		}
+		w.End = w.Start.Add(d)
+	case v.End != "":
+		w.End, err = timeexpr.Parse(v.End, now)
+	default:
+		w.End, err = timeexpr.Parse(DefaultEnd, now)
+	}
+	return w, err
+}
+
+// StakeAmount returns the stake in nano-AVAX, defaulting to [min].
+func (v Validator) StakeAmount(min uint64) (uint64, error) {
+	if v.Stake == "" {
+		return min, nil
+	}
+	return amount.Parse(v.Stake)
+}
+
+type Op struct {
+	now time.Time
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithNow overrides the current time (for testing).
+func WithNow(now time.Time) OpOption {
+	return func(op *Op) {
+		op.now = now
+	}
+}
+
+// Validate checks the schema version, referential integrity (including
+// "depends-on" references and cycles), amounts and durations of the spec
+// without touching the network. All violations are returned at once as
+// [Violations].
+func (s *Spec) Validate(opts ...OpOption) error {
+	ret := &Op{now: time.Now()}
+	ret.applyOpts(opts)
+
+	vs := Violations{}
+	add := func(where string, err error) {
+		vs = append(vs, fmt.Errorf("%s: %w", where, err))
+	}
+
+	if s.Version != CurrentVersion {
+		add("version", fmt.Errorf("%w: %d (expected %d)", ErrUnsupportedVersion, s.Version, CurrentVersion))
+	}
+	networkID, err := s.NetworkID()
+	if err != nil {
+		add("network", err)
+	}
+	cfg := genesis.GetStakingConfig(networkID)
+
+	primary := map[ids.ShortID]bool{}
+	for i, v := range s.Validators {
+		where := fmt.Sprintf("validators[%d]", i)
+		nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+		switch {
+		case err != nil:
+			add(where, fmt.Errorf("%w: %q", ErrInvalidNodeID, v.NodeID))
+		case primary[nodeID]:
+			add(where, fmt.Errorf("%w: %s", ErrDuplicate, v.NodeID))
+		default:
+			primary[nodeID] = true
+		}
+		stake, err := v.StakeAmount(cfg.MinValidatorStake)
+		switch {
+		case err != nil:
+			add(where, err)
+		case stake < cfg.MinValidatorStake:
+			add(where, fmt.Errorf("%w: %s < %s", ErrStakeTooLow, amount.Format(stake), amount.Format(cfg.MinValidatorStake)))
+		}
+		if v.RewardFeePercent != 0 && (v.RewardFeePercent < minRewardFeePercent || v.RewardFeePercent > maxRewardFeePercent) {
+			add(where, fmt.Errorf("%w: %d", ErrInvalidRewardFee, v.RewardFeePercent))
+		}
+		w, err := v.Window(ret.now)
+		if err != nil {
+			add(where, err)
+			continue
+		}
+		if err := window.Check(w,
+			window.WithNow(ret.now),
+			window.WithDurationLimits(cfg.MinStakeDuration, cfg.MaxStakeDuration),
+		); err != nil {
+			add(where, err)
+		}
+	}
+
+	subnets := map[string]bool{}
+	for i, sn := range s.Subnets {
+		where := fmt.Sprintf("subnets[%d]", i)
+		switch {
+		case sn.Name == "":
+			add(where, ErrEmptyName)
+		case subnets[sn.Name]:
+			add(where, fmt.Errorf("%w: subnet %q", ErrDuplicate, sn.Name))
+		}
+		subnets[sn.Name] = true
+		if sn.ID != "" {
+			if _, err := ids.FromString(sn.ID); err != nil {
+				add(where, fmt.Errorf("%w: subnet %q", ErrInvalidID, sn.ID))
+			}
+		}
+
+		validators := map[ids.ShortID]bool{}
+		for j, v := range sn.Validators {
+			where := fmt.Sprintf("subnets[%d].validators[%d]", i, j)
+			nodeID, err := ids.ShortFromPrefixedString(v.NodeID, constants.NodeIDPrefix)
+			switch {
+			case err != nil:
+				add(where, fmt.Errorf("%w: %q", ErrInvalidNodeID, v.NodeID))
+			case validators[nodeID]:
+				add(where, fmt.Errorf("%w: %s", ErrDuplicate, v.NodeID))
+			default:
+				validators[nodeID] = true
+			}
+		}
+
+		chains := map[string]bool{}
+		for j, c := range sn.Chains {
+			where := fmt.Sprintf("subnets[%d].chains[%d]", i, j)
+			switch {
+			case c.Name == "":
+				add(where, ErrEmptyName)
+			case chains[c.Name]:
+				add(where, fmt.Errorf("%w: chain %q", ErrDuplicate, c.Name))
+			}
+			chains[c.Name] = true
+			if _, err := ids.FromString(c.VMID); err != nil {
+				add(where, fmt.Errorf("%w: vm %q", ErrInvalidID, c.VMID))
+			}
+			if fi, err := os.Stat(s.GenesisPath(c)); err != nil || fi.IsDir() {
+				add(where, fmt.Errorf("%w: %s", ErrMissingGenesis, s.GenesisPath(c)))
+			}
+		}
+	}
+	s.checkDependencies(add)
+
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
+
+// ExternalValidators returns the subnet validators that the spec does not
+// add to the primary network itself; they must already be primary network
+// validators when the spec is applied.
+func (s *Spec) ExternalValidators() []string {
+	primary := map[string]bool{}
+	for _, v := range s.Validators {
+		primary[v.NodeID] = true
+	}
+	seen := map[string]bool{}
+	ret := []string{}
+	for _, sn := range s.Subnets {
+		for _, v := range sn.Validators {
+			if primary[v.NodeID] || seen[v.NodeID] {
+				continue
+			}
+			seen[v.NodeID] = true
+			ret = append(ret, v.NodeID)
+		}
+	}
+	return ret
+}
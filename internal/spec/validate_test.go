@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spec
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-cli/internal/window"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "genesis.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name    string
+		replace [2]string
+		expErrs []error
+	}{
+		{name: "valid"},
+		{
+			name:    "unsupported version",
+			replace: [2]string{"version: 1", "version: 2"},
+			expErrs: []error{ErrUnsupportedVersion},
+		},
+		{
+			name:    "unknown network",
+			replace: [2]string{"network: fuji", "network: foo"},
+			expErrs: []error{ErrUnknownNetwork},
+		},
+		{
+			name:    "stake too low",
+			replace: [2]string{"stake: 2000avax", "stake: 0.5avax"},
+			expErrs: []error{ErrStakeTooLow},
+		},
+		{
+			name:    "duration too short",
+			replace: [2]string{"duration: 14d", "duration: 1h"},
+			expErrs: []error{window.ErrTooShort},
+		},
+		{
+			name:    "duplicate subnet",
+			replace: [2]string{"name: b", "name: a"},
+			expErrs: []error{ErrDuplicate},
+		},
+		{
+			name:    "invalid vm id",
+			replace: [2]string{"vm-id: tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH", "vm-id: foo"},
+			expErrs: []error{ErrInvalidID},
+		},
+		{
+			name:    "missing genesis",
+			replace: [2]string{"genesis: genesis.json", "genesis: missing.json"},
+			expErrs: []error{ErrMissingGenesis},
+		},
+		{
+			name:    "invalid node id",
+			replace: [2]string{"weight: 20", "weight: 20\n      - node-id: foo"},
+			expErrs: []error{ErrInvalidNodeID},
+		},
+		{
+			name:    "unknown dependency",
+			replace: [2]string{"genesis: genesis.json", "genesis: genesis.json\n        depends-on: [subnet/c]"},
+			expErrs: []error{ErrUnknownDependency},
+		},
+		{
+			name:    "dependency cycle",
+			replace: [2]string{"duration: 14d", "duration: 14d\n    depends-on: [subnet-validator/a/NodeID-741aqvs6R4iuHDyd1qT1NrFTmsgu78dc4]"},
+			expErrs: []error{ErrDependencyCycle},
+		},
+		{
+			name:    "multiple violations",
+			replace: [2]string{"version: 1\nnetwork: fuji", "version: 3\nnetwork: bar"},
+			expErrs: []error{ErrUnsupportedVersion, ErrUnknownNetwork},
+		},
+	}
+	for i, tv := range tt {
+		b := testSpec
+		if tv.replace[0] != "" {
+			b = strings.Replace(b, tv.replace[0], tv.replace[1], 1)
+		}
+		s, err := Parse([]byte(b))
+		if err != nil {
+			t.Fatalf("#%d(%s): %v", i, tv.name, err)
+		}
+		s.dir = dir
+		err = s.Validate(WithNow(now))
+		if tv.name == "valid" {
+			if err != nil {
+				t.Fatalf("#%d(%s): unexpected error %v", i, tv.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Fatalf("#%d(%s): expected error", i, tv.name)
+		}
+		for _, exp := range tv.expErrs {
+			if !errors.Is(err, exp) {
+				t.Fatalf("#%d(%s): expected %v, got %v", i, tv.name, exp, err)
+			}
+		}
+	}
+}
+
+func TestExternalValidators(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse([]byte(testSpec + "      - node-id: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := s.ExternalValidators()
+	if len(ext) != 1 || ext[0] != "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg" {
+		t.Fatalf("unexpected external validators %v", ext)
+	}
+}
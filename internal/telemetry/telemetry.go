@@ -0,0 +1,201 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package telemetry reports opt-in, anonymized usage stats: the command
+// run, its duration and the class of its error. Flags, arguments, keys,
+// addresses and IDs are never reported.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DefaultEndpoint is where events are sent unless configured otherwise.
+// Builds may set it with "-ldflags -X"; the release builds do not, so an
+// endpoint must be configured ("telemetry on --endpoint"). If empty,
+// nothing is sent.
+var DefaultEndpoint = ""
+
+const (
+	// EnvDisable disables telemetry regardless of the config if set
+	// (ref. https://consoledonottrack.com).
+	EnvDisable = "DO_NOT_TRACK"
+
+	reportTimeout = 2 * time.Second
+)
+
+// Error classes; never the error message, which may contain addresses.
+const (
+	ClassNone     = "none"
+	ClassTimeout  = "timeout"
+	ClassCanceled = "canceled"
+	ClassNetwork  = "network"
+	ClassOther    = "other"
+)
+
+// Config is the telemetry opt-in.
+type Config struct {
+	path string
+
+	Enabled bool `json:"enabled"`
+	// ID is a random installation ID to count unique users; it is not
+	// derived from anything on the machine.
+	ID       string `json:"id,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// DefaultPath returns the config file in the user config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "subnet-cli", "telemetry.json"), nil
+}
+
+// Load reads the config at [path]; a missing file is disabled telemetry.
+func Load(path string) (*Config, error) {
+	c := &Config{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%q: %w", path, err)
+	}
+	return c, nil
+}
+
+// Enable opts in, generating the installation ID on first use.
+func (c *Config) Enable() error {
+	c.Enabled = true
+	if c.ID != "" {
+		return nil
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	c.ID = hex.EncodeToString(b)
+	return nil
+}
+
+// Disable opts out and forgets the installation ID.
+func (c *Config) Disable() {
+	c.Enabled = false
+	c.ID = ""
+}
+
+// Save writes the config back to its file.
+func (c *Config) Save() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(b, '\n'), 0o600)
+}
+
+// Active returns true if events are reported.
+func (c *Config) Active() bool {
+	_, disabled := os.LookupEnv(EnvDisable)
+	return c.Enabled && !disabled && c.URL() != ""
+}
+
+// URL returns the endpoint events are sent to.
+func (c *Config) URL() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	return DefaultEndpoint
+}
+
+// Event is a single command run.
+type Event struct {
+	ID string `json:"id"`
+	// Command is the command path (e.g., "subnet-cli create subnet").
+	Command    string `json:"command"`
+	DurationMs int64  `json:"durationMs"`
+	ErrorClass string `json:"errorClass"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	GoVersion  string `json:"goVersion"`
+}
+
+// NewEvent describes a run of [command] that took [took] and failed with
+// [err], if not nil.
+func (c *Config) NewEvent(command string, took time.Duration, err error) Event {
+	return Event{
+		ID:         c.ID,
+		Command:    command,
+		DurationMs: took.Milliseconds(),
+		ErrorClass: Classify(err),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		GoVersion:  runtime.Version(),
+	}
+}
+
+// Classify maps [err] to a coarse class.
+func Classify(err error) string {
+	var nerr net.Error
+	switch {
+	case err == nil:
+		return ClassNone
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.As(err, &nerr):
+		if nerr.Timeout() {
+			return ClassTimeout
+		}
+		return ClassNetwork
+	}
+	return ClassOther
+}
+
+// Report sends [ev] if telemetry is active. Failures are returned for
+// logging only; telemetry must never fail a command.
+func (c *Config) Report(ctx context.Context, ev Event) error {
+	if !c.Active() {
+		return nil
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		err error
+		exp string
+	}{
+		{err: nil, exp: ClassNone},
+		{err: fmt.Errorf("poll: %w", context.DeadlineExceeded), exp: ClassTimeout},
+		{err: context.Canceled, exp: ClassCanceled},
+		{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, exp: ClassNetwork},
+		{err: errors.New("insufficient funds for P-avax1..."), exp: ClassOther},
+	}
+	for i, tv := range tt {
+		if c := Classify(tv.err); c != tv.exp {
+			t.Fatalf("#%d: expected %q, got %q", i, tv.exp, c)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Error(err)
+		}
+		events <- ev
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Endpoint = srv.URL
+	if c.Active() {
+		t.Fatal("expected telemetry to be off by default")
+	}
+	if err := c.Enable(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Enabled || c.ID == "" {
+		t.Fatalf("expected enabled config with an ID, got %+v", c)
+	}
+	ev := c.NewEvent("subnet-cli create subnet", 1500*time.Millisecond, context.DeadlineExceeded)
+	if err := c.Report(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	got := <-events
+	if got != ev || got.ErrorClass != ClassTimeout || got.DurationMs != 1500 {
+		t.Fatalf("unexpected event %+v", got)
+	}
+
+	c.Disable()
+	if c.Active() || c.ID != "" {
+		t.Fatalf("expected disabled config without an ID, got %+v", c)
+	}
+}
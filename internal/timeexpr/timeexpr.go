@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package timeexpr parses absolute and relative time expressions used by
+// command-line flags (e.g., "now+5m", "now+14d", RFC3339 timestamps).
+package timeexpr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpr = errors.New("invalid time expression")
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// ParseDuration extends "time.ParseDuration" with day ("d") and week ("w")
+// units (e.g., "14d", "1w2d", "2d1w", "36h"). Units may appear in any order.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty duration", ErrInvalidExpr)
+	}
+	total := time.Duration(0)
+	rest := s
+	for rest != "" {
+		// each component is a number followed by its unit
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidExpr, s)
+		}
+		j := strings.IndexFunc(rest[i:], func(r rune) bool { return r >= '0' && r <= '9' })
+		if j < 0 {
+			j = len(rest) - i
+		}
+		num, unit := rest[:i], rest[i:i+j]
+		rest = rest[i+j:]
+
+		var d time.Duration
+		switch unit {
+		case "w", "d":
+			n, err := strconv.ParseUint(num, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidExpr, s)
+			}
+			u := day
+			if unit == "w" {
+				u = week
+			}
+			if n > uint64(math.MaxInt64/int64(u)) {
+				return 0, fmt.Errorf("%w: %q overflows", ErrInvalidExpr, s)
+			}
+			d = time.Duration(n) * u
+		default:
+			var err error
+			if d, err = time.ParseDuration(num + unit); err != nil {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidExpr, s)
+			}
+		}
+		if d > math.MaxInt64-total {
+			return 0, fmt.Errorf("%w: %q overflows", ErrInvalidExpr, s)
+		}
+		total += d
+	}
+	return total, nil
+}
+
+// Parse parses [s] relative to [now]. Accepted formats are RFC3339
+// timestamps, "now", and "now+<duration>" where duration is accepted by
+// [ParseDuration].
+func Parse(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return time.Time{}, fmt.Errorf("%w: empty time", ErrInvalidExpr)
+	case s == "now":
+		return now, nil
+	case strings.HasPrefix(s, "now+"):
+		d, err := ParseDuration(strings.TrimPrefix(s, "now+"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q (expected RFC3339 or now+<duration>)", ErrInvalidExpr, s)
+	}
+	return t, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeexpr
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	tt := []struct {
+		s      string
+		exp    time.Time
+		expErr error
+	}{
+		{s: "now", exp: now},
+		{s: "now+5m", exp: now.Add(5 * time.Minute)},
+		{s: "now+14d", exp: now.Add(14 * day)},
+		{s: "now+1w2d3h", exp: now.Add(week + 2*day + 3*time.Hour)},
+		{s: "now+2d1w", exp: now.Add(week + 2*day)},
+		{s: "now+3h1d30m", exp: now.Add(day + 3*time.Hour + 30*time.Minute)},
+		{s: "now+1.5h", exp: now.Add(90 * time.Minute)},
+		{s: "2022-02-01T00:00:00Z", exp: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{s: "now-5m", expErr: ErrInvalidExpr},
+		{s: "now+", expErr: ErrInvalidExpr},
+		{s: "now+5x", expErr: ErrInvalidExpr},
+		{s: "now+1.5d", expErr: ErrInvalidExpr},
+		{s: "now+d", expErr: ErrInvalidExpr},
+		{s: "now+5", expErr: ErrInvalidExpr},
+		{s: "now+15000w", exp: now.Add(15000 * week)},
+		{s: "now+20000w", expErr: ErrInvalidExpr},
+		{s: "now+999999d", expErr: ErrInvalidExpr},
+		{s: "now+15000w15000w", expErr: ErrInvalidExpr},
+		{s: "now+2562047h1d", expErr: ErrInvalidExpr},
+		{s: "now+2562048h", expErr: ErrInvalidExpr},
+		{s: "tomorrow", expErr: ErrInvalidExpr},
+		{s: "", expErr: ErrInvalidExpr},
+	}
+	for i, tv := range tt {
+		v, err := Parse(tv.s, now)
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d(%q): unexpected error %v, expected %v", i, tv.s, err, tv.expErr)
+		}
+		if !v.Equal(tv.exp) {
+			t.Fatalf("#%d(%q): unexpected time %v, expected %v", i, tv.s, v, tv.exp)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package update finds, verifies and installs subnet-cli releases.
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	avago_version "github.com/ava-labs/avalanchego/version"
+)
+
+// LatestReleaseURL is the GitHub API endpoint of the latest release.
+const LatestReleaseURL = "https://api.github.com/repos/ava-labs/subnet-cli/releases/latest"
+
+// maximum size of a downloaded artifact
+const maxDownloadSize = 256 * 1024 * 1024
+
+var (
+	ErrNoAsset          = errors.New("release has no asset")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrNoReleaseKey     = errors.New("no release key")
+	ErrNoBinary         = errors.New("archive has no binary")
+)
+
+// releaseKey is the public key of the cosign key releases sign their
+// checksums with (see ".goreleaser.yml"). Until the maintainers commit it,
+// the file is a placeholder and every release is refused.
+//
+//go:embed cosign.pub
+var releaseKey []byte
+
+// Release is a GitHub release.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the "v" prefix.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Asset returns the download URL of the asset [name].
+func (r *Release) Asset(name string) (string, error) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrNoAsset, name)
+}
+
+// ArchiveName returns the release archive of [goos]/[goarch] (ref.
+// goreleaser defaults).
+func ArchiveName(version string, goos string, goarch string) string {
+	return fmt.Sprintf("subnet-cli_%s_%s_%s.tar.gz", version, goos, goarch)
+}
+
+// ChecksumsName returns the release file listing the SHA-256 of every
+// archive.
+func ChecksumsName(version string) string {
+	return fmt.Sprintf("subnet-cli_%s_checksums.txt", version)
+}
+
+// SignatureName returns the release file with the cosign signature of
+// [ChecksumsName].
+func SignatureName(version string) string {
+	return ChecksumsName(version) + ".sig"
+}
+
+// Latest fetches the latest release from [url] (e.g., [LatestReleaseURL]).
+func Latest(ctx context.Context, url string) (*Release, error) {
+	b, err := Download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Release)
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Newer returns true if [latest] is newer than [current] (e.g., "0.0.2"
+// and "0.0.1").
+func Newer(current string, latest string) (bool, error) {
+	p := avago_version.NewDefaultParser()
+	cv, err := p.Parse("v" + strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return false, err
+	}
+	lv, err := p.Parse("v" + strings.TrimPrefix(latest, "v"))
+	if err != nil {
+		return false, err
+	}
+	return lv.Compare(cv) > 0, nil
+}
+
+// Download fetches [url].
+func Download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// VerifyChecksum checks [data] against the SHA-256 of [name] in the
+// goreleaser [checksums] file ("<hex>  <name>" lines).
+func VerifyChecksum(data []byte, checksums []byte, name string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	sc := bufio.NewScanner(bytes.NewReader(checksums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		if !strings.EqualFold(fields[0], actual) {
+			return fmt.Errorf("%w: %s is %s, expected %s", ErrChecksumMismatch, name, actual, fields[0])
+		}
+		return nil
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %q not in checksums", ErrChecksumMismatch, name)
+}
+
+// VerifySignature checks the "cosign sign-blob" signature [sig] (base64
+// ASN.1 ECDSA) of [checksums] against the release key embedded in the
+// binary, so a compromised release host can't swap in both the archive
+// and its checksum.
+func VerifySignature(checksums []byte, sig []byte) error {
+	if _, err := parsePublicKey(releaseKey); err != nil {
+		return fmt.Errorf("%w: this build embeds no valid release key (%v)", ErrNoReleaseKey, err)
+	}
+	return verifySignature(checksums, sig, releaseKey)
+}
+
+func verifySignature(checksums []byte, sig []byte, publicKey []byte) error {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	digest := sha256.Sum256(checksums)
+	if !ecdsa.VerifyASN1(pub, digest[:], der) {
+		return fmt.Errorf("%w: checksums not signed by the release key", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// parsePublicKey parses a PEM ECDSA public key (e.g., "cosign.pub").
+func parsePublicKey(b []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM public key")
+	}
+	k, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := k.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unexpected public key %T", k)
+	}
+	return pub, nil
+}
+
+// ExtractBinary returns the file [name] in the gzipped tar [archive].
+func ExtractBinary(archive []byte, name string) ([]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %q", ErrNoBinary, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == name {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+}
+
+// Replace atomically replaces the executable at [exe] with [bin]. The
+// previous binary is kept as "[exe].old" until the next update, since a
+// running executable cannot be removed on every platform.
+func Replace(exe string, bin []byte) error {
+	fi, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, bin, fi.Mode().Perm()|0o500); err != nil {
+		return err
+	}
+	old := exe + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, exe); err != nil {
+		// restore the previous binary
+		_ = os.Rename(old, exe)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		current string
+		latest  string
+		exp     bool
+	}{
+		{current: "0.0.1", latest: "0.0.2", exp: true},
+		{current: "v0.1.0", latest: "v0.0.9", exp: false},
+		{current: "0.0.2", latest: "v0.0.2", exp: false},
+	}
+	for i, tv := range tt {
+		v, err := Newer(tv.current, tv.latest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != tv.exp {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.exp, v)
+		}
+	}
+	if _, err := Newer("dev", "0.0.2"); err == nil {
+		t.Fatal("expected error for dev version")
+	}
+}
+
+func testArchive(t *testing.T, name string, content []byte) []byte {
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyAndReplace(t *testing.T) {
+	t.Parallel()
+
+	archive := testArchive(t, "subnet-cli", []byte("new"))
+	sum := sha256.Sum256(archive)
+	name := ArchiveName("0.0.2", "linux", "amd64")
+	checksums := []byte(fmt.Sprintf("%s  %s\n%s  other.tar.gz\n", hex.EncodeToString(sum[:]), name, hex.EncodeToString(make([]byte, 32))))
+
+	tt := []struct {
+		data   []byte
+		name   string
+		expErr error
+	}{
+		{data: archive, name: name},
+		{data: []byte("tampered"), name: name, expErr: ErrChecksumMismatch},
+		{data: archive, name: "missing.tar.gz", expErr: ErrChecksumMismatch},
+	}
+	for i, tv := range tt {
+		if err := VerifyChecksum(tv.data, checksums, tv.name); !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+	}
+
+	if _, err := ExtractBinary(archive, "other"); !errors.Is(err, ErrNoBinary) {
+		t.Fatalf("expected %v, got %v", ErrNoBinary, err)
+	}
+	bin, err := ExtractBinary(archive, "subnet-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exe := filepath.Join(t.TempDir(), "subnet-cli")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(exe, bin); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "new" {
+		t.Fatalf("expected new binary, got %q", b)
+	}
+	if b, err = os.ReadFile(exe + ".old"); err != nil || string(b) != "old" {
+		t.Fatalf("expected previous binary to be kept, got %q (%v)", b, err)
+	}
+}
+
+func testReleaseKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	// the embedded key is a placeholder until the maintainers commit one
+	expErr := ErrInvalidSignature
+	if _, err := parsePublicKey(releaseKey); err != nil {
+		expErr = ErrNoReleaseKey
+	}
+
+	k, pub := testReleaseKey(t)
+	_, otherPub := testReleaseKey(t)
+	checksums := []byte("abc  subnet-cli_0.0.2_linux_amd64.tar.gz\n")
+	digest := sha256.Sum256(checksums)
+	der, err := ecdsa.SignASN1(rand.Reader, k, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "cosign sign-blob" writes the base64 signature
+	sig := []byte(base64.StdEncoding.EncodeToString(der) + "\n")
+
+	tt := []struct {
+		checksums []byte
+		sig       []byte
+		pub       []byte
+		expErr    error
+	}{
+		{checksums: checksums, sig: sig, pub: pub},
+		{checksums: []byte("tampered"), sig: sig, pub: pub, expErr: ErrInvalidSignature},
+		{checksums: checksums, sig: sig, pub: otherPub, expErr: ErrInvalidSignature},
+		{checksums: checksums, sig: []byte("not base64!"), pub: pub, expErr: ErrInvalidSignature},
+	}
+	for i, tv := range tt {
+		if err := verifySignature(tv.checksums, tv.sig, tv.pub); !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+	}
+	if err := VerifySignature(checksums, sig); !errors.Is(err, expErr) {
+		t.Fatalf("expected %v, got %v", expErr, err)
+	}
+}
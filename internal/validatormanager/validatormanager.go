@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package validatormanager encodes the calls that set up the reference
+// validator manager contracts of sovereign L1s (ACP-77): "PoAValidatorManager"
+// and "NativeTokenStakingManager" of ava-labs/icm-contracts.
+package validatormanager
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+	"github.com/ava-labs/subnet-cli/internal/warp"
+)
+
+var ErrInvalidSettings = errors.New("invalid validator manager settings")
+
+// WarpPrecompile is the address of the Warp precompile of subnet-evm, whose
+// access list entry carries the signed message a tx uses.
+var WarpPrecompile = evm.Address{0x02, 19: 0x05}
+
+// Kind is the kind of a validator manager.
+type Kind string
+
+const (
+	// PoA lets its owner add and remove validators.
+	PoA Kind = "poa"
+	// PoS lets anyone stake the native token to validate.
+	PoS Kind = "pos"
+)
+
+// ParseKind parses "poa" or "pos".
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case PoA, PoS:
+		return k, nil
+	}
+	return "", fmt.Errorf("%w: unknown kind %q (expected %q or %q)", ErrInvalidSettings, s, PoA, PoS)
+}
+
+// Settings are the settings every validator manager takes.
+type Settings struct {
+	SubnetID ids.ID
+	// ChurnPeriod is the period over which the weight changes are bounded
+	// by MaxChurnPercent of the total weight.
+	ChurnPeriod     time.Duration
+	MaxChurnPercent uint8
+}
+
+func (s Settings) check() error {
+	if s.SubnetID == ids.Empty {
+		return fmt.Errorf("%w: empty subnet ID", ErrInvalidSettings)
+	}
+	if s.MaxChurnPercent == 0 || s.MaxChurnPercent > 100 {
+		return fmt.Errorf("%w: max churn %d%% not in (0, 100]", ErrInvalidSettings, s.MaxChurnPercent)
+	}
+	return nil
+}
+
+func (s Settings) arg() evm.Arg {
+	return evm.Static(
+		s.SubnetID[:],
+		evm.WordUint(big.NewInt(int64(s.ChurnPeriod/time.Second))),
+		evm.WordUint(big.NewInt(int64(s.MaxChurnPercent))),
+	)
+}
+
+// PoSSettings are the staking settings of a PoS validator manager.
+type PoSSettings struct {
+	Settings
+	// MinStake and MaxStake bound the stake of a validator (in wei).
+	MinStake         *big.Int
+	MaxStake         *big.Int
+	MinStakeDuration time.Duration
+	// MinDelegationFeeBips is the minimum fee validators charge delegators
+	// (in basis points).
+	MinDelegationFeeBips uint16
+	// MaxStakeMultiplier bounds the delegated stake to a multiple of the
+	// validator stake.
+	MaxStakeMultiplier uint8
+	// WeightToValueFactor converts the stake to a validator weight.
+	WeightToValueFactor *big.Int
+	RewardCalculator    evm.Address
+	// UptimeBlockchainID is the chain whose validators sign uptime proofs.
+	UptimeBlockchainID ids.ID
+}
+
+func (s PoSSettings) check() error {
+	if err := s.Settings.check(); err != nil {
+		return err
+	}
+	switch {
+	case s.MinStake == nil || s.MaxStake == nil || s.MinStake.Sign() <= 0 || s.MinStake.Cmp(s.MaxStake) > 0:
+		return fmt.Errorf("%w: stake bounds must satisfy 0 < min <= max", ErrInvalidSettings)
+	case s.MinDelegationFeeBips == 0 || s.MinDelegationFeeBips > 10_000:
+		return fmt.Errorf("%w: min delegation fee %d bips not in (0, 10000]", ErrInvalidSettings, s.MinDelegationFeeBips)
+	case s.MaxStakeMultiplier == 0:
+		return fmt.Errorf("%w: zero max stake multiplier", ErrInvalidSettings)
+	case s.WeightToValueFactor == nil || s.WeightToValueFactor.Sign() <= 0:
+		return fmt.Errorf("%w: weight to value factor must be positive", ErrInvalidSettings)
+	case s.UptimeBlockchainID == ids.Empty:
+		return fmt.Errorf("%w: empty uptime blockchain ID", ErrInvalidSettings)
+	}
+	return nil
+}
+
+// DeployData returns the creation code of a validator manager from its
+// [bytecode], constructed to be initialized by a call (rather than through
+// a proxy).
+func DeployData(bytecode []byte) []byte {
+	// constructor(ICMInitializable.Allowed)
+	return append(append([]byte(nil), bytecode...), evm.WordUint(big.NewInt(0))...)
+}
+
+// InitializePoA returns the call that initializes a PoA validator manager
+// owned by [owner].
+func InitializePoA(s Settings, owner evm.Address) ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return evm.CallArgs("initialize((bytes32,uint64,uint8),address)", s.arg(), evm.Static(evm.WordAddress(owner))), nil
+}
+
+// Owner returns the call that reads the owner of a PoA validator manager.
+func Owner() []byte {
+	return evm.Call("owner()")
+}
+
+// InitializePoS returns the call that initializes a native token PoS
+// validator manager.
+func InitializePoS(s PoSSettings) ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return evm.CallArgs("initialize(((bytes32,uint64,uint8),uint256,uint256,uint64,uint16,uint8,uint256,address,bytes32))", evm.Tuple(
+		s.Settings.arg(),
+		evm.Static(
+			evm.WordUint(s.MinStake),
+			evm.WordUint(s.MaxStake),
+			evm.WordUint(big.NewInt(int64(s.MinStakeDuration/time.Second))),
+			evm.WordUint(big.NewInt(int64(s.MinDelegationFeeBips))),
+			evm.WordUint(big.NewInt(int64(s.MaxStakeMultiplier))),
+			evm.WordUint(s.WeightToValueFactor),
+			evm.WordAddress(s.RewardCalculator),
+			s.UptimeBlockchainID[:],
+		),
+	)), nil
+}
+
+// ConversionData returns what the ConvertSubnetToL1Tx of [subnetID] set,
+// given the manager and the initial validators [vs] it was issued with.
+func ConversionData(subnetID ids.ID, chainID ids.ID, manager evm.Address, vs []l1.Validator) *warp.SubnetToL1ConversionData {
+	d := &warp.SubnetToL1ConversionData{
+		SubnetID:       subnetID,
+		ManagerChainID: chainID,
+		ManagerAddress: manager[:],
+	}
+	for _, v := range l1.SortValidators(vs) {
+		d.Validators = append(d.Validators, warp.SubnetToL1ConversionValidatorData{
+			NodeID:       v.NodeID.Bytes(),
+			BLSPublicKey: v.PublicKey,
+			Weight:       v.Weight,
+		})
+	}
+	return d
+}
+
+// InitializeValidatorSet returns the call that registers the initial
+// validators of [d] on its validator manager. The tx must carry the signed
+// [warp.SubnetToL1Conversion] message of [d] as its first Warp predicate.
+func InitializeValidatorSet(d *warp.SubnetToL1ConversionData) []byte {
+	var manager evm.Address
+	copy(manager[:], d.ManagerAddress)
+	validators := make([]evm.Arg, 0, len(d.Validators))
+	for _, v := range d.Validators {
+		validators = append(validators, evm.Tuple(
+			evm.Bytes(v.NodeID),
+			evm.Bytes(v.BLSPublicKey[:]),
+			evm.Static(evm.WordUint(new(big.Int).SetUint64(v.Weight))),
+		))
+	}
+	return evm.CallArgs("initializeValidatorSet((bytes32,bytes32,address,(bytes,bytes,uint64)[]),uint32)",
+		evm.Tuple(
+			evm.Static(d.SubnetID[:], d.ManagerChainID[:], evm.WordAddress(manager)),
+			evm.Array(validators...),
+		),
+		// index of the Warp predicate of the tx
+		evm.Static(evm.WordUint(big.NewInt(0))),
+	)
+}
+
+// Predicate returns the access list entry that carries the [signed] Warp
+// message.
+func Predicate(signed []byte) evm.AccessTuple {
+	return evm.AccessTuple{Address: WarpPrecompile, StorageKeys: warp.PackPredicate(signed)}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatormanager
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/evm"
+	"github.com/ava-labs/subnet-cli/internal/l1"
+)
+
+func TestInitialize(t *testing.T) {
+	t.Parallel()
+
+	s := Settings{SubnetID: ids.ID{1}, ChurnPeriod: time.Hour, MaxChurnPercent: 20}
+	owner := evm.Address{2}
+	b, err := InitializePoA(s, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// selector, 3 settings words and the owner
+	if len(b) != 4+4*32 || !bytes.Equal(b[:4], evm.Selector("initialize((bytes32,uint64,uint8),address)")) {
+		t.Fatalf("unexpected call %x", b)
+	}
+	if evm.DecodeUint(b[4:], 1).Int64() != 3600 || evm.DecodeAddress(b[4:], 3) != owner {
+		t.Fatalf("unexpected arguments %x", b[4:])
+	}
+	if o := Owner(); !bytes.Equal(o, evm.Selector("owner()")) {
+		t.Fatalf("unexpected owner call %x", o)
+	}
+
+	pos := PoSSettings{
+		Settings:             s,
+		MinStake:             big.NewInt(1),
+		MaxStake:             big.NewInt(10),
+		MinStakeDuration:     24 * time.Hour,
+		MinDelegationFeeBips: 100,
+		MaxStakeMultiplier:   4,
+		WeightToValueFactor:  big.NewInt(1),
+		UptimeBlockchainID:   ids.ID{3},
+	}
+	if b, err = InitializePoS(pos); err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 4+11*32 || evm.DecodeUint(b[4:], 5).Int64() != 86400 {
+		t.Fatalf("unexpected call %x", b)
+	}
+
+	tt := []struct {
+		name   string
+		change func(*PoSSettings)
+	}{
+		{name: "no subnet", change: func(s *PoSSettings) { s.SubnetID = ids.Empty }},
+		{name: "churn", change: func(s *PoSSettings) { s.MaxChurnPercent = 101 }},
+		{name: "stake bounds", change: func(s *PoSSettings) { s.MinStake = big.NewInt(11) }},
+		{name: "delegation fee", change: func(s *PoSSettings) { s.MinDelegationFeeBips = 0 }},
+		{name: "multiplier", change: func(s *PoSSettings) { s.MaxStakeMultiplier = 0 }},
+		{name: "uptime chain", change: func(s *PoSSettings) { s.UptimeBlockchainID = ids.Empty }},
+	}
+	for _, tv := range tt {
+		invalid := pos
+		tv.change(&invalid)
+		if _, err := InitializePoS(invalid); !errors.Is(err, ErrInvalidSettings) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, ErrInvalidSettings, err)
+		}
+	}
+	if _, err := ParseKind("pow"); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSettings, err)
+	}
+}
+
+func TestInitializeValidatorSet(t *testing.T) {
+	t.Parallel()
+
+	manager := evm.Address{9}
+	vs := []l1.Validator{
+		{NodeID: ids.ShortID{2}, Weight: 20, PublicKey: [48]byte{2}},
+		{NodeID: ids.ShortID{1}, Weight: 10, PublicKey: [48]byte{1}},
+	}
+	d := ConversionData(ids.ID{1}, ids.ID{2}, manager, vs)
+	if !bytes.Equal(d.Validators[0].NodeID, vs[1].NodeID[:]) || d.Validators[1].Weight != 20 {
+		t.Fatalf("validators not sorted by node ID: %+v", d.Validators)
+	}
+	if vs[0].Weight != 20 {
+		t.Fatal("sorted the validators in place")
+	}
+
+	b := InitializeValidatorSet(d)[4:]
+	// the conversion data is at offset 64, followed by the message index
+	if evm.DecodeUint(b, 0).Int64() != 64 || evm.DecodeUint(b, 1).Sign() != 0 {
+		t.Fatalf("unexpected head %x", b[:64])
+	}
+	data := b[64:]
+	if evm.DecodeAddress(data, 2) != manager || evm.DecodeUint(data, 3).Int64() != 4*32 {
+		t.Fatalf("unexpected conversion data %x", data)
+	}
+	validators := data[4*32:]
+	if evm.DecodeUint(validators, 0).Int64() != 2 {
+		t.Fatalf("unexpected validators %x", validators)
+	}
+	// the first validator: node ID, public key and weight
+	v := validators[32+evm.DecodeUint(validators, 1).Int64():]
+	if evm.DecodeUint(v, 2).Int64() != 10 {
+		t.Fatalf("unexpected validator %x", v)
+	}
+	nodeID := v[evm.DecodeUint(v, 0).Int64():]
+	if evm.DecodeUint(nodeID, 0).Int64() != 20 || !bytes.Equal(nodeID[32:52], vs[1].NodeID[:]) {
+		t.Fatalf("unexpected node ID %x", nodeID)
+	}
+
+	p := Predicate([]byte{1})
+	if p.Address != WarpPrecompile || len(p.StorageKeys) != 1 {
+		t.Fatalf("unexpected predicate %+v", p)
+	}
+	if WarpPrecompile.Hex() != "0x0200000000000000000000000000000000000005" {
+		t.Fatalf("unexpected precompile address %s", WarpPrecompile)
+	}
+}
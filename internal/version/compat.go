@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	avago_version "github.com/ava-labs/avalanchego/version"
+)
+
+// Avalanchego is the avalanchego version whose RPC and codec this build
+// uses.
+var Avalanchego = avago_version.Current
+
+// Compat is how a node version relates to [Avalanchego].
+type Compat int
+
+const (
+	// Compatible nodes only differ by patch version.
+	Compatible Compat = iota
+	NodeOlder
+	NodeNewer
+)
+
+func (c Compat) String() string {
+	switch c {
+	case Compatible:
+		return "compatible"
+	case NodeOlder:
+		return "node older"
+	case NodeNewer:
+		return "node newer"
+	}
+	return "unknown"
+}
+
+// CheckNode compares the version reported by a node (e.g.,
+// "avalanche/1.7.6") with [Avalanchego]. Minor versions may change the
+// APIs and the tx format, so only patch differences are compatible.
+func CheckNode(nodeVersion string) (Compat, error) {
+	v, err := avago_version.VersionParser.Parse(nodeVersion)
+	if err != nil {
+		return Compatible, err
+	}
+	switch {
+	case v.Major() == Avalanchego.Major() && v.Minor() == Avalanchego.Minor():
+		return Compatible, nil
+	case v.Compare(Avalanchego) < 0:
+		return NodeOlder, nil
+	}
+	return NodeNewer, nil
+}
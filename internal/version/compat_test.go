@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCheckNode(t *testing.T) {
+	t.Parallel()
+
+	major, minor := Avalanchego.Major(), Avalanchego.Minor()
+	tt := []struct {
+		nodeVersion string
+		exp         Compat
+		expErr      bool
+	}{
+		{nodeVersion: fmt.Sprintf("avalanche/%d.%d.%d", major, minor, Avalanchego.Patch()+1), exp: Compatible},
+		{nodeVersion: fmt.Sprintf("avalanche/%d.%d.0", major, minor+1), exp: NodeNewer},
+		{nodeVersion: fmt.Sprintf("avalanche/%d.0.0", major+1), exp: NodeNewer},
+		{nodeVersion: fmt.Sprintf("avalanche/%d.%d.99", major, minor-1), exp: NodeOlder},
+		{nodeVersion: "1.7.6", expErr: true},
+	}
+	for i, tv := range tt {
+		c, err := CheckNode(tv.nodeVersion)
+		if (err != nil) != tv.expErr {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if err == nil && c != tv.exp {
+			t.Fatalf("#%d: expected %s, got %s", i, tv.exp, c)
+		}
+	}
+}
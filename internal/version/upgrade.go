@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+	avago_version "github.com/ava-labs/avalanchego/version"
+)
+
+// Network upgrade names.
+const (
+	ApricotPhase5 = "ApricotPhase5"
+	Banff         = "Banff"
+	Cortina       = "Cortina"
+	Durango       = "Durango"
+	// Etna introduced dynamic fees and ACP-77 sovereign L1s.
+	Etna = "Etna"
+)
+
+// activation returns the activation times on mainnet and fuji.
+func activation(mainnet string, fuji string) map[uint32]time.Time {
+	m, err := time.Parse(time.RFC3339, mainnet)
+	if err != nil {
+		panic(err)
+	}
+	f, err := time.Parse(time.RFC3339, fuji)
+	if err != nil {
+		panic(err)
+	}
+	return map[uint32]time.Time{constants.MainnetID: m, constants.FujiID: f}
+}
+
+// Active returns the upgrades active on [networkID] at [now]. The public
+// networks follow their activation schedule. Other (e.g., local) networks
+// activate every upgrade the node ships at genesis, so [nodeVersion] (e.g.,
+// "avalanche/1.11.3") decides; if it can't be parsed, only the upgrades
+// this build supports are assumed.
+func Active(networkID uint32, nodeVersion string, now time.Time) []Upgrade {
+	node, err := avago_version.VersionParser.Parse(nodeVersion)
+	active := []Upgrade{}
+	for _, u := range Upgrades {
+		var ok bool
+		if t, public := u.Activation[networkID]; public {
+			ok = !now.Before(t)
+		} else if err == nil {
+			ok = node.Compare(mustVersion(u.NodeVersion)) >= 0
+		} else {
+			ok = u.Supported
+		}
+		if !ok {
+			break
+		}
+		active = append(active, u)
+	}
+	return active
+}
+
+// IsActive returns true if the upgrade [name] is in [active].
+func IsActive(active []Upgrade, name string) bool {
+	for _, u := range active {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the last upgrade of [active], or false if none is.
+func Latest(active []Upgrade) (Upgrade, bool) {
+	if len(active) == 0 {
+		return Upgrade{}, false
+	}
+	return active[len(active)-1], true
+}
+
+func mustVersion(s string) avago_version.Version {
+	v, err := avago_version.NewDefaultParser().Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestActive(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		networkID   uint32
+		nodeVersion string
+		now         string
+		latest      string
+	}{
+		{networkID: constants.MainnetID, now: "2022-01-01T00:00:00Z", latest: ApricotPhase5},
+		{networkID: constants.MainnetID, now: "2022-10-18T16:00:00Z", latest: Banff},
+		// the node version does not matter on public networks
+		{networkID: constants.FujiID, nodeVersion: "avalanche/1.7.6", now: "2024-02-14T00:00:00Z", latest: Durango},
+		{networkID: constants.MainnetID, now: "2026-01-01T00:00:00Z", latest: Etna},
+		{networkID: constants.MainnetID, now: "2021-01-01T00:00:00Z"},
+		{networkID: 1337, nodeVersion: "avalanche/1.7.6", now: "2026-01-01T00:00:00Z", latest: ApricotPhase5},
+		{networkID: 1337, nodeVersion: "avalanche/1.11.3", now: "2020-01-01T00:00:00Z", latest: Durango},
+		{networkID: 1337, nodeVersion: "avalanche/1.12.0", now: "2020-01-01T00:00:00Z", latest: Etna},
+		{networkID: 1337, nodeVersion: "", now: "2026-01-01T00:00:00Z", latest: ApricotPhase5},
+	}
+	for i, tv := range tt {
+		now, err := time.Parse(time.RFC3339, tv.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		active := Active(tv.networkID, tv.nodeVersion, now)
+		latest, ok := Latest(active)
+		if ok != (tv.latest != "") || latest.Name != tv.latest {
+			t.Fatalf("#%d: expected latest %q, got %q", i, tv.latest, latest.Name)
+		}
+		if tv.latest != "" && !IsActive(active, ApricotPhase5) {
+			t.Fatalf("#%d: expected %s active", i, ApricotPhase5)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package version describes the subnet-cli build.
+package version
+
+import (
+	"runtime"
+	"time"
+)
+
+// Dev is the version of source builds.
+const Dev = "dev"
+
+// Build metadata, set by the release build with "-ldflags -X".
+var (
+	// Version is the release version (e.g., "0.0.2").
+	Version = Dev
+	// Commit is the git commit the release was built from.
+	Commit = ""
+	// Date is the build date in RFC3339.
+	Date = ""
+)
+
+// Upgrade is a network upgrade and whether this build supports its APIs
+// and tx format.
+type Upgrade struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	// NodeVersion is the first avalanchego release that ships the upgrade.
+	NodeVersion string `json:"nodeVersion"`
+	// Activation is when the upgrade activated on the public networks.
+	Activation map[uint32]time.Time `json:"-"`
+}
+
+// Upgrades are the network upgrades subnet-cli knows of, in activation
+// order. Later upgrades need an avalanchego dependency that ships them.
+var Upgrades = []Upgrade{
+	{
+		Name: ApricotPhase5, Supported: true, NodeVersion: "v1.7.0",
+		Activation: activation("2021-12-02T18:00:00Z", "2021-11-24T15:00:00Z"),
+	},
+	{
+		Name: Banff, NodeVersion: "v1.9.0",
+		Activation: activation("2022-10-18T16:00:00Z", "2022-10-03T14:00:00Z"),
+	},
+	{
+		Name: Cortina, NodeVersion: "v1.10.0",
+		Activation: activation("2023-04-25T15:00:00Z", "2023-04-06T15:00:00Z"),
+	},
+	{
+		Name: Durango, NodeVersion: "v1.11.0",
+		Activation: activation("2024-03-06T16:00:00Z", "2024-02-13T16:00:00Z"),
+	},
+	{
+		Name: Etna, NodeVersion: "v1.12.0",
+		Activation: activation("2024-12-16T17:00:00Z", "2024-11-25T16:00:00Z"),
+	},
+}
+
+// Info is the build metadata for support triage.
+type Info struct {
+	Version     string    `json:"version"`
+	Commit      string    `json:"commit,omitempty"`
+	Date        string    `json:"date,omitempty"`
+	GoVersion   string    `json:"goVersion"`
+	Platform    string    `json:"platform"`
+	Avalanchego string    `json:"avalanchego"`
+	Upgrades    []Upgrade `json:"upgrades"`
+}
+
+// Get returns the build metadata.
+func Get() Info {
+	return Info{
+		Version:     Version,
+		Commit:      Commit,
+		Date:        Date,
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		Avalanchego: Avalanchego.String(),
+		Upgrades:    Upgrades,
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	v := Get()
+	if v.Version != Version {
+		t.Fatalf("unexpected version %q", v.Version)
+	}
+	if v.Avalanchego != Avalanchego.String() {
+		t.Fatalf("unexpected avalanchego version %q", v.Avalanchego)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["commit"]; ok {
+		t.Fatal("empty commit should be omitted")
+	}
+	if len(m["upgrades"].([]interface{})) != len(Upgrades) {
+		t.Fatalf("unexpected upgrades %v", m["upgrades"])
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var ErrAggregation = errors.New("signature aggregation failed")
+
+type aggregateRequest struct {
+	Message          string `json:"message"`
+	Justification    string `json:"justification,omitempty"`
+	SigningSubnetID  string `json:"signing-subnet-id"`
+	QuorumPercentage uint64 `json:"quorum-percentage"`
+}
+
+type aggregateResponse struct {
+	SignedMessage string `json:"signed-message"`
+	Error         string `json:"error"`
+}
+
+// Aggregate collects the signatures of the validators of [subnetID] for
+// [unsigned] from the signature aggregator at [url] (its
+// "/aggregate-signatures" endpoint), until [quorum] percent of the subnet
+// weight signed. The validators only sign messages their chain sent, so
+// the validator manager must have sent [unsigned] first; P-Chain messages
+// are signed given the [justification] of their content (e.g., the subnet
+// ID of a [SubnetToL1Conversion]).
+func Aggregate(ctx context.Context, url string, unsigned []byte, justification []byte, subnetID ids.ID, quorum uint64) (*Message, []byte, error) {
+	b, err := json.Marshal(aggregateRequest{
+		Message:          hex.EncodeToString(unsigned),
+		Justification:    hex.EncodeToString(justification),
+		SigningSubnetID:  subnetID.String(),
+		QuorumPercentage: quorum,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := new(aggregateResponse)
+	_ = json.Unmarshal(body, ret)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := ret.Error
+		if msg == "" {
+			msg = strings.TrimSpace(string(body))
+		}
+		return nil, nil, fmt.Errorf("%w: status code %d: %s", ErrAggregation, resp.StatusCode, msg)
+	}
+	signed, err := hex.DecodeString(strings.TrimPrefix(ret.SignedMessage, "0x"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrAggregation, err)
+	}
+	m, err := ParseMessage(signed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrAggregation, err)
+	}
+	// the aggregator must not sign a different message
+	if got, err := m.UnsignedMessage.Bytes(); err != nil || !bytes.Equal(got, unsigned) {
+		return nil, nil, fmt.Errorf("%w: signed message differs from the request", ErrAggregation)
+	}
+	return m, signed, nil
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package warp builds the Avalanche Warp messages the P-Chain exchanges
+// with the validator managers of sovereign L1s (ACP-77).
+// ref. "vms/platformvm/warp" of avalanchego v1.12
+package warp
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	internal_codec "github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+const codecVersion = 0
+
+var ErrInvalidMessage = errors.New("invalid warp message")
+
+var (
+	// warpCodec serializes signed messages
+	warpCodec codec.Manager
+	// payloadCodec serializes the payload of an unsigned message
+	payloadCodec codec.Manager
+	// messageCodec serializes the P-Chain messages in an [AddressedCall]
+	messageCodec codec.Manager
+)
+
+func init() {
+	errs := wrappers.Errs{}
+
+	wc := linearcodec.NewDefault()
+	warpCodec = codec.NewDefaultManager()
+	errs.Add(
+		wc.RegisterType(&BitSetSignature{}),
+		warpCodec.RegisterCodec(codecVersion, wc),
+	)
+
+	pc := linearcodec.NewDefault()
+	payloadCodec = codec.NewDefaultManager()
+	// Hash
+	pc.SkipRegistrations(1)
+	errs.Add(
+		pc.RegisterType(&AddressedCall{}),
+		payloadCodec.RegisterCodec(codecVersion, pc),
+	)
+
+	mc := linearcodec.NewDefault()
+	messageCodec = codec.NewDefaultManager()
+	errs.Add(
+		mc.RegisterType(&SubnetToL1Conversion{}),
+		mc.RegisterType(&RegisterL1Validator{}),
+	)
+	// L1ValidatorRegistration
+	mc.SkipRegistrations(1)
+	errs.Add(
+		mc.RegisterType(&L1ValidatorWeight{}),
+		messageCodec.RegisterCodec(codecVersion, mc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// UnsignedMessage is a message sent from [SourceChainID].
+type UnsignedMessage struct {
+	NetworkID     uint32 `serialize:"true"`
+	SourceChainID ids.ID `serialize:"true"`
+	Payload       []byte `serialize:"true"`
+}
+
+func (m *UnsignedMessage) Bytes() ([]byte, error) {
+	return warpCodec.Marshal(codecVersion, m)
+}
+
+// ParseUnsignedMessage parses the bytes of an unsigned message.
+func ParseUnsignedMessage(b []byte) (*UnsignedMessage, error) {
+	m := new(UnsignedMessage)
+	if _, err := warpCodec.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Signature is the aggregate signature of a message; [BitSetSignature] is
+// the only kind.
+type Signature interface{}
+
+// BitSetSignature is the BLS signature of a message aggregated from the
+// validators in the bit set [Signers] (indexed by the canonical validator
+// order of the signing subnet).
+type BitSetSignature struct {
+	Signers   []byte   `serialize:"true"`
+	Signature [96]byte `serialize:"true"`
+}
+
+// Message is a signed message, as the P-Chain txs of L1 validators carry.
+type Message struct {
+	UnsignedMessage `serialize:"true"`
+	Signature       Signature `serialize:"true"`
+}
+
+// ParseMessage parses the bytes of a signed message.
+func ParseMessage(b []byte) (*Message, error) {
+	m := new(Message)
+	if _, err := warpCodec.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if _, ok := m.Signature.(*BitSetSignature); !ok {
+		return nil, ErrInvalidMessage
+	}
+	return m, nil
+}
+
+// AddressedCall is a payload sent by the contract [SourceAddress] (e.g.,
+// the validator manager of an L1).
+type AddressedCall struct {
+	SourceAddress []byte `serialize:"true"`
+	Payload       []byte `serialize:"true"`
+}
+
+func (c *AddressedCall) Bytes() ([]byte, error) {
+	var p interface{} = c
+	return payloadCodec.Marshal(codecVersion, &p)
+}
+
+// ParseAddressedCall parses the payload of an unsigned message.
+func ParseAddressedCall(b []byte) (*AddressedCall, error) {
+	var p interface{}
+	if _, err := payloadCodec.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	c, ok := p.(*AddressedCall)
+	if !ok {
+		return nil, ErrInvalidMessage
+	}
+	return c, nil
+}
+
+// SubnetToL1Conversion reports the conversion of a subnet to an L1 by the
+// P-Chain, with the ID of its [SubnetToL1ConversionData].
+type SubnetToL1Conversion struct {
+	ID ids.ID `serialize:"true"`
+}
+
+func (c *SubnetToL1Conversion) Bytes() ([]byte, error) {
+	var p interface{} = c
+	return messageCodec.Marshal(codecVersion, &p)
+}
+
+// SubnetToL1ConversionData is what a ConvertSubnetToL1Tx set: the
+// validator manager and the initial validators, sorted by node ID.
+type SubnetToL1ConversionData struct {
+	SubnetID       ids.ID                              `serialize:"true"`
+	ManagerChainID ids.ID                              `serialize:"true"`
+	ManagerAddress []byte                              `serialize:"true"`
+	Validators     []SubnetToL1ConversionValidatorData `serialize:"true"`
+}
+
+// SubnetToL1ConversionValidatorData is an initial validator of an L1.
+type SubnetToL1ConversionValidatorData struct {
+	NodeID       []byte   `serialize:"true"`
+	BLSPublicKey [48]byte `serialize:"true"`
+	Weight       uint64   `serialize:"true"`
+}
+
+// ConversionID returns the ID of [d], which the P-Chain signs in a
+// [SubnetToL1Conversion] message.
+func (d *SubnetToL1ConversionData) ConversionID() (ids.ID, error) {
+	b, err := messageCodec.Marshal(codecVersion, d)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return hashing.ComputeHash256Array(b), nil
+}
+
+// RegisterL1Validator adds a validator to an L1 until [Expiry] (Unix
+// seconds), after which the message is no longer accepted.
+type RegisterL1Validator struct {
+	SubnetID              ids.ID                     `serialize:"true"`
+	NodeID                []byte                     `serialize:"true"`
+	BLSPublicKey          [48]byte                   `serialize:"true"`
+	Expiry                uint64                     `serialize:"true"`
+	RemainingBalanceOwner internal_codec.PChainOwner `serialize:"true"`
+	DisableOwner          internal_codec.PChainOwner `serialize:"true"`
+	Weight                uint64                     `serialize:"true"`
+}
+
+func (r *RegisterL1Validator) Bytes() ([]byte, error) {
+	var p interface{} = r
+	return messageCodec.Marshal(codecVersion, &p)
+}
+
+// ValidationID returns the validation ID the validator registered by [r]
+// gets.
+func (r *RegisterL1Validator) ValidationID() (ids.ID, error) {
+	b, err := r.Bytes()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return hashing.ComputeHash256Array(b), nil
+}
+
+// L1ValidatorWeight sets the weight of an L1 validator, which is removed
+// with a zero weight. [Nonce] must be at least the validator's minimum
+// nonce, so old messages can't be replayed.
+type L1ValidatorWeight struct {
+	ValidationID ids.ID `serialize:"true"`
+	Nonce        uint64 `serialize:"true"`
+	Weight       uint64 `serialize:"true"`
+}
+
+func (w *L1ValidatorWeight) Bytes() ([]byte, error) {
+	var p interface{} = w
+	return messageCodec.Marshal(codecVersion, &p)
+}
+
+// NewManagerMessage returns the unsigned message the validator manager at
+// [address] on [chainID] sends with the P-Chain message [msg].
+func NewManagerMessage(networkID uint32, chainID ids.ID, address []byte, msg []byte) ([]byte, error) {
+	call, err := (&AddressedCall{SourceAddress: address, Payload: msg}).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return (&UnsignedMessage{NetworkID: networkID, SourceChainID: chainID, Payload: call}).Bytes()
+}
+
+// NewPChainMessage returns the unsigned message the P-Chain sends with the
+// P-Chain message [msg] (e.g., a [SubnetToL1Conversion]), signed by the
+// primary network validators.
+func NewPChainMessage(networkID uint32, msg []byte) ([]byte, error) {
+	call, err := (&AddressedCall{Payload: msg}).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return (&UnsignedMessage{NetworkID: networkID, SourceChainID: constants.PlatformChainID, Payload: call}).Bytes()
+}
+
+// PredicateDelimiter ends the signed message packed in a predicate.
+const PredicateDelimiter = 0xff
+
+// PackPredicate packs the [signed] message as the storage keys of the
+// access list entry of the warp precompile, which a subnet-evm tx carries
+// to use the message (ref. "predicate.PackPredicate" of subnet-evm).
+func PackPredicate(signed []byte) [][32]byte {
+	b := append(append([]byte(nil), signed...), PredicateDelimiter)
+	keys := make([][32]byte, (len(b)+31)/32)
+	for i := range keys {
+		copy(keys[i][:], b[i*32:])
+	}
+	return keys
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/internal/codec"
+)
+
+func testRegister() *RegisterL1Validator {
+	owner := codec.PChainOwner{Threshold: 1, Addresses: []ids.ShortID{{1}}}
+	return &RegisterL1Validator{
+		SubnetID:              ids.ID{1},
+		NodeID:                make([]byte, 20),
+		Expiry:                1_700_000_000,
+		RemainingBalanceOwner: owner,
+		DisableOwner:          owner,
+		Weight:                100,
+	}
+}
+
+func TestManagerMessage(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name   string
+		msg    interface{ Bytes() ([]byte, error) }
+		typeID byte
+	}{
+		{name: "conversion", msg: &SubnetToL1Conversion{ID: ids.ID{1}}, typeID: 0},
+		{name: "register", msg: testRegister(), typeID: 1},
+		{name: "weight", msg: &L1ValidatorWeight{ValidationID: ids.ID{2}, Nonce: 3}, typeID: 3},
+	}
+	for _, tv := range tt {
+		msg, err := tv.msg.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// codec version, then the type ID
+		if !bytes.Equal(msg[:6], []byte{0, 0, 0, 0, 0, tv.typeID}) {
+			t.Fatalf("%s: unexpected prefix %x", tv.name, msg[:6])
+		}
+		b, err := NewManagerMessage(5, ids.ID{3}, []byte{0xfe}, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		um, err := ParseUnsignedMessage(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if um.NetworkID != 5 || um.SourceChainID != (ids.ID{3}) {
+			t.Fatalf("%s: unexpected message %+v", tv.name, um)
+		}
+		call, err := ParseAddressedCall(um.Payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(call.SourceAddress, []byte{0xfe}) || !bytes.Equal(call.Payload, msg) {
+			t.Fatalf("%s: unexpected call %+v", tv.name, call)
+		}
+	}
+}
+
+func TestValidationID(t *testing.T) {
+	t.Parallel()
+
+	a, b := testRegister(), testRegister()
+	b.Weight++
+	ida, err := a.ValidationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idb, err := b.ValidationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ida == idb || ida == ids.Empty {
+		t.Fatalf("unexpected validation IDs %s, %s", ida, idb)
+	}
+}
+
+func TestConversionID(t *testing.T) {
+	t.Parallel()
+
+	d := &SubnetToL1ConversionData{
+		SubnetID:       ids.ID{1},
+		ManagerChainID: ids.ID{2},
+		ManagerAddress: []byte{0xfe},
+		Validators:     []SubnetToL1ConversionValidatorData{{NodeID: []byte{3}, BLSPublicKey: [48]byte{4}, Weight: 5}},
+	}
+	id, err := d.ConversionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// codec version, IDs, then length-prefixed address and validators
+	b := []byte{0, 0}
+	b = append(b, d.SubnetID[:]...)
+	b = append(b, d.ManagerChainID[:]...)
+	b = append(b, 0, 0, 0, 1, 0xfe, 0, 0, 0, 1, 0, 0, 0, 1, 3)
+	b = append(b, d.Validators[0].BLSPublicKey[:]...)
+	b = append(b, 0, 0, 0, 0, 0, 0, 0, 5)
+	if exp := ids.ID(sha256.Sum256(b)); id != exp {
+		t.Fatalf("expected conversion ID %s, got %s", exp, id)
+	}
+
+	msg, err := NewPChainMessage(5, []byte{6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	um, err := ParseUnsignedMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := ParseAddressedCall(um.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if um.SourceChainID != ids.Empty || len(call.SourceAddress) != 0 || !bytes.Equal(call.Payload, []byte{6}) {
+		t.Fatalf("unexpected P-Chain message %+v %+v", um, call)
+	}
+}
+
+func TestPackPredicate(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		signed []byte
+		keys   int
+	}{
+		{signed: nil, keys: 1},
+		{signed: bytes.Repeat([]byte{1}, 31), keys: 1},
+		{signed: bytes.Repeat([]byte{1}, 32), keys: 2},
+		{signed: bytes.Repeat([]byte{1}, 40), keys: 2},
+	}
+	for _, tv := range tt {
+		keys := PackPredicate(tv.signed)
+		if len(keys) != tv.keys {
+			t.Fatalf("%d bytes: expected %d keys, got %d", len(tv.signed), tv.keys, len(keys))
+		}
+		b := []byte{}
+		for _, k := range keys {
+			b = append(b, k[:]...)
+		}
+		if !bytes.Equal(b[:len(tv.signed)], tv.signed) || b[len(tv.signed)] != PredicateDelimiter {
+			t.Fatalf("%d bytes: unexpected predicate %x", len(tv.signed), b)
+		}
+		for _, c := range b[len(tv.signed)+1:] {
+			if c != 0 {
+				t.Fatalf("%d bytes: unexpected padding %x", len(tv.signed), b)
+			}
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	unsigned, err := NewManagerMessage(5, ids.ID{3}, []byte{0xfe}, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	um, err := ParseUnsignedMessage(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := *um
+	other.NetworkID = 1
+
+	tt := []struct {
+		name   string
+		signed *UnsignedMessage
+		status int
+		err    error
+	}{
+		{name: "signed", signed: um, status: http.StatusOK},
+		{name: "different message", signed: &other, status: http.StatusOK, err: ErrAggregation},
+		{name: "no quorum", status: http.StatusInternalServerError, err: ErrAggregation},
+	}
+	for _, tv := range tt {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := new(aggregateRequest)
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.Message != hex.EncodeToString(unsigned) || req.QuorumPercentage != 67 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if tv.signed == nil {
+				w.WriteHeader(tv.status)
+				_ = json.NewEncoder(w).Encode(aggregateResponse{Error: "not enough stake"})
+				return
+			}
+			b, err := warpCodec.Marshal(codecVersion, &Message{UnsignedMessage: *tv.signed, Signature: &BitSetSignature{Signers: []byte{1}}})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(aggregateResponse{SignedMessage: "0x" + hex.EncodeToString(b)})
+		}))
+		m, _, err := Aggregate(context.Background(), srv.URL, unsigned, nil, ids.ID{4}, 67)
+		srv.Close()
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+		if err == nil && m.NetworkID != 5 {
+			t.Fatalf("%s: unexpected message %+v", tv.name, m)
+		}
+	}
+}
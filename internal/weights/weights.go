@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package weights implements validator weight planning for permissioned
+// subnets.
+package weights
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var ErrInvalidPlan = errors.New("invalid weight plan")
+
+const (
+	// LivenessThreshold is the fraction of total weight that must be online
+	// for consensus to keep making progress.
+	LivenessThreshold = 0.8
+
+	// a single validator above these fractions of total weight can halt
+	// (or unilaterally drive) the subnet
+	haltThreshold    = 1.0 / 3.0
+	controlThreshold = 2.0 / 3.0
+)
+
+// Share is one validator's weight and its fraction of the total.
+type Share struct {
+	NodeID   ids.ShortID
+	Weight   uint64
+	Fraction float64
+}
+
+// Distribution is a weight distribution sorted by descending weight.
+type Distribution struct {
+	Total    uint64
+	Shares   []Share
+	Warnings []string
+}
+
+// Analyze computes the distribution of [ws] and flags dangerous
+// configurations.
+func Analyze(ws map[ids.ShortID]uint64) Distribution {
+	d := Distribution{Shares: make([]Share, 0, len(ws))}
+	for nodeID, w := range ws {
+		d.Total += w
+		d.Shares = append(d.Shares, Share{NodeID: nodeID, Weight: w})
+	}
+	sort.Slice(d.Shares, func(i, j int) bool {
+		if d.Shares[i].Weight != d.Shares[j].Weight {
+			return d.Shares[i].Weight > d.Shares[j].Weight
+		}
+		return d.Shares[i].NodeID.String() < d.Shares[j].NodeID.String()
+	})
+	if d.Total == 0 {
+		return d
+	}
+	for i := range d.Shares {
+		s := &d.Shares[i]
+		s.Fraction = float64(s.Weight) / float64(d.Total)
+		switch {
+		case s.Fraction > controlThreshold:
+			d.Warnings = append(d.Warnings, fmt.Sprintf("%s holds %.1f%% (>66%%) of total weight and controls consensus alone", s.NodeID.PrefixedString(constants.NodeIDPrefix), 100*s.Fraction))
+		case s.Fraction > haltThreshold:
+			d.Warnings = append(d.Warnings, fmt.Sprintf("%s holds %.1f%% (>33%%) of total weight and can halt the subnet alone", s.NodeID.PrefixedString(constants.NodeIDPrefix), 100*s.Fraction))
+		}
+	}
+	if tolerated := d.ToleratedFaults(); tolerated == 0 && len(d.Shares) > 1 {
+		d.Warnings = append(d.Warnings, "no single validator can go offline without halting the subnet")
+	}
+	return d
+}
+
+// Apply returns the weights of [ws] after setting the weight of every node
+// of [changes], where a zero weight removes the node. [ws] is not modified.
+func Apply(ws map[ids.ShortID]uint64, changes map[ids.ShortID]uint64) map[ids.ShortID]uint64 {
+	after := make(map[ids.ShortID]uint64, len(ws)+len(changes))
+	for nodeID, w := range ws {
+		after[nodeID] = w
+	}
+	for nodeID, w := range changes {
+		if w == 0 {
+			delete(after, nodeID)
+			continue
+		}
+		after[nodeID] = w
+	}
+	return after
+}
+
+// ToleratedFaults returns how many of the heaviest validators can be offline
+// at the same time while keeping the online weight above the liveness
+// threshold.
+func (d Distribution) ToleratedFaults() int {
+	offline := uint64(0)
+	for i, s := range d.Shares {
+		offline += s.Weight
+		if float64(d.Total-offline) < LivenessThreshold*float64(d.Total) {
+			return i
+		}
+	}
+	return len(d.Shares)
+}
+
+// Suggestion is a recommended validator set layout.
+type Suggestion struct {
+	Validators int
+	Weight     uint64
+	Total      uint64
+	Tolerated  int
+	Warnings   []string
+}
+
+// Suggest recommends per-validator weights for [validators] validators that
+// must tolerate [faults] validators being offline. Equal weights maximize
+// the number of tolerated faults, so the suggestion uses [weight] for every
+// validator and raises the validator count when it is too small.
+func Suggest(validators int, faults int, weight uint64) (Suggestion, error) {
+	if validators <= 0 || faults < 0 || weight == 0 {
+		return Suggestion{}, fmt.Errorf("%w: validators %d, faults %d, weight %d", ErrInvalidPlan, validators, faults, weight)
+	}
+	s := Suggestion{Validators: validators, Weight: weight}
+
+	// with equal weights, [faults] offline validators keep the subnet live
+	// iff (n - faults) / n >= LivenessThreshold
+	minValidators := 1
+	for float64(minValidators-faults) < LivenessThreshold*float64(minValidators) {
+		minValidators++
+	}
+	if validators < minValidators {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("%d validators cannot tolerate %d offline (need at least %d); using %d", validators, faults, minValidators, minValidators))
+		s.Validators = minValidators
+	}
+	s.Total = weight * uint64(s.Validators)
+
+	ws := make(map[ids.ShortID]uint64, s.Validators)
+	for i := 0; i < s.Validators; i++ {
+		ws[ids.ShortID{byte(i), byte(i >> 8), byte(i >> 16)}] = weight
+	}
+	d := Analyze(ws)
+	s.Tolerated = d.ToleratedFaults()
+	if s.Validators == 1 || s.Validators == 2 {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("each of the %d validators holds >33%% of total weight", s.Validators))
+	}
+	return s, nil
+}
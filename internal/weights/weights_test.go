@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package weights
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		validators    int
+		faults        int
+		expValidators int
+		expTolerated  int
+	}{
+		{validators: 5, faults: 1, expValidators: 5, expTolerated: 1},
+		{validators: 3, faults: 1, expValidators: 5, expTolerated: 1},
+		{validators: 10, faults: 2, expValidators: 10, expTolerated: 2},
+		{validators: 4, faults: 0, expValidators: 4, expTolerated: 0},
+	}
+	for i, tv := range tt {
+		s, err := Suggest(tv.validators, tv.faults, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Validators != tv.expValidators || s.Tolerated != tv.expTolerated {
+			t.Fatalf("#%d: unexpected suggestion %+v", i, s)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	d := Analyze(map[ids.ShortID]uint64{
+		{1}: 700,
+		{2}: 200,
+		{3}: 100,
+	})
+	if d.Total != 1000 || d.Shares[0].Weight != 700 {
+		t.Fatalf("unexpected distribution %+v", d)
+	}
+	if len(d.Warnings) != 2 {
+		t.Fatalf("unexpected warnings %v", d.Warnings)
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	ws := map[ids.ShortID]uint64{{1}: 100, {2}: 200}
+	after := Apply(ws, map[ids.ShortID]uint64{{2}: 0, {3}: 300, {1}: 50})
+	if len(after) != 2 || after[ids.ShortID{1}] != 50 || after[ids.ShortID{3}] != 300 {
+		t.Fatalf("unexpected weights %v", after)
+	}
+	if len(ws) != 2 || ws[ids.ShortID{1}] != 100 {
+		t.Fatalf("input modified %v", ws)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package window implements validation window sanity checks.
+package window
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrStartTooSoon    = errors.New("validate start too soon")
+	ErrEndBeforeStart  = errors.New("validate end before start")
+	ErrTooShort        = errors.New("validate duration too short")
+	ErrTooLong         = errors.New("validate duration too long")
+	ErrOutsidePrimary  = errors.New("validate window outside primary network validation")
+	ErrOverlapsPending = errors.New("validate window overlaps pending validator")
+)
+
+// Window is a validation period.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("[%s, %s]", w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+}
+
+func (w Window) overlaps(o Window) bool {
+	return w.Start.Before(o.End) && o.Start.Before(w.End)
+}
+
+// Violations is the set of all checks that a window failed.
+type Violations []error
+
+func (vs Violations) Error() string {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		ss[i] = v.Error()
+	}
+	return strings.Join(ss, "; ")
+}
+
+// Is returns true if any of the violations matches [target].
+func (vs Violations) Is(target error) bool {
+	for _, v := range vs {
+		if errors.Is(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type Op struct {
+	now         time.Time
+	buffer      time.Duration
+	minDuration time.Duration
+	maxDuration time.Duration
+	primary     *Window
+	pending     []Window
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithNow overrides the current time (for testing).
+func WithNow(now time.Time) OpOption {
+	return func(op *Op) {
+		op.now = now
+	}
+}
+
+// WithPropagationBuffer requires the start to be at least [d] in the future,
+// so the tx can be accepted before the window opens.
+func WithPropagationBuffer(d time.Duration) OpOption {
+	return func(op *Op) {
+		op.buffer = d
+	}
+}
+
+// WithDurationLimits bounds the window length (e.g., network staking limits).
+func WithDurationLimits(min time.Duration, max time.Duration) OpOption {
+	return func(op *Op) {
+		op.minDuration = min
+		op.maxDuration = max
+	}
+}
+
+// WithPrimary requires the window to be within the primary network
+// validation period (for subnet validators).
+func WithPrimary(w Window) OpOption {
+	return func(op *Op) {
+		op.primary = &w
+	}
+}
+
+// WithPending rejects windows that overlap an existing pending validation.
+func WithPending(ws ...Window) OpOption {
+	return func(op *Op) {
+		op.pending = append(op.pending, ws...)
+	}
+}
+
+// Check runs every configured check against [w] and returns all violations
+// at once, or nil if the window is valid.
+func Check(w Window, opts ...OpOption) error {
+	ret := &Op{now: time.Now()}
+	ret.applyOpts(opts)
+
+	var vs Violations
+	if earliest := ret.now.Add(ret.buffer); !w.Start.After(earliest) {
+		vs = append(vs, fmt.Errorf("%w: start %s must be after %s", ErrStartTooSoon, w.Start.Format(time.RFC3339), earliest.Format(time.RFC3339)))
+	}
+	d := w.End.Sub(w.Start)
+	switch {
+	case d <= 0:
+		vs = append(vs, fmt.Errorf("%w: %s", ErrEndBeforeStart, w))
+	case ret.minDuration > 0 && d < ret.minDuration:
+		vs = append(vs, fmt.Errorf("%w: %v < minimum %v", ErrTooShort, d, ret.minDuration))
+	case ret.maxDuration > 0 && d > ret.maxDuration:
+		vs = append(vs, fmt.Errorf("%w: %v > maximum %v", ErrTooLong, d, ret.maxDuration))
+	}
+	if ret.primary != nil && (w.Start.Before(ret.primary.Start) || w.End.After(ret.primary.End)) {
+		vs = append(vs, fmt.Errorf("%w: %s not within %s", ErrOutsidePrimary, w, ret.primary))
+	}
+	for _, p := range ret.pending {
+		if w.overlaps(p) {
+			vs = append(vs, fmt.Errorf("%w: %s overlaps %s", ErrOverlapsPending, w, p))
+		}
+	}
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package window
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	opts := []OpOption{
+		WithNow(now),
+		WithPropagationBuffer(time.Minute),
+		WithDurationLimits(day, 365*day),
+	}
+
+	if err := Check(Window{Start: now.Add(time.Hour), End: now.Add(2 * day)}, opts...); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// all violations must be reported at once
+	err := Check(
+		Window{Start: now, End: now.Add(time.Hour)},
+		append(opts,
+			WithPrimary(Window{Start: now.Add(time.Minute), End: now.Add(10 * day)}),
+			WithPending(Window{Start: now.Add(30 * time.Minute), End: now.Add(day)}),
+		)...,
+	)
+	for _, exp := range []error{ErrStartTooSoon, ErrTooShort, ErrOutsidePrimary, ErrOverlapsPending} {
+		if !errors.Is(err, exp) {
+			t.Fatalf("expected %v in %v", exp, err)
+		}
+	}
+	if errors.Is(err, ErrTooLong) {
+		t.Fatalf("unexpected %v in %v", ErrTooLong, err)
+	}
+}
@@ -5,10 +5,92 @@ package color
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	formatter "github.com/onsi/ginkgo/v2/formatter"
 )
 
+// Stdout and Stderr are the writers of all (possibly colored) output.
+var (
+	Stdout io.Writer = formatter.ColorableStdOut
+	Stderr io.Writer = formatter.ColorableStdErr
+)
+
+var (
+	enabled = true
+	quiet   = false
+	results []string
+)
+
+// Setup disables colors if [noColor] is set, the NO_COLOR environment
+// variable is set (ref. https://no-color.org), or stdout is not a terminal,
+// so piped and machine-readable output never contains ANSI codes.
+func Setup(noColor bool) {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		noColor = true
+	}
+	if !isTerminal(os.Stdout) {
+		noColor = true
+	}
+	if noColor {
+		enabled = false
+		formatter.SingletonFormatter = formatter.New(formatter.ColorModeNone)
+	}
+}
+
+// Enabled returns true if output is colored.
+func Enabled() bool { return enabled }
+
+// SetQuiet suppresses all stdout output except [Result], so scripts can
+// consume the final IDs one per line.
+func SetQuiet(b bool) { quiet = b }
+
+// Quiet returns true if only results are printed.
+func Quiet() bool { return quiet }
+
+// Result prints a final result (e.g., a created subnet ID) on its own line
+// in quiet mode, where it is the only output. Otherwise it is only recorded
+// (see [Results]), since the commands already report results in their
+// tables and messages.
+func Result(v interface{}) {
+	results = append(results, fmt.Sprint(v))
+	if quiet {
+		fmt.Fprintln(Stdout, v)
+	}
+}
+
+// Results returns every result reported so far, in order.
+func Results() []string { return results }
+
+// Interactive returns true if progress indicators should be shown, i.e.,
+// not in quiet mode and stderr is a terminal.
+func Interactive() bool {
+	return !quiet && isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// F formats [format] with color tags (e.g., "{{green}}ok{{/}}"), which
+// are stripped when colors are disabled.
+func F(format string, args ...interface{}) string {
+	return formatter.F(format, args...)
+}
+
+// Print writes the already formatted [s] to stdout, unless quiet.
+func Print(s string) {
+	if quiet {
+		return
+	}
+	fmt.Fprint(Stdout, s)
+}
+
 // Outputs to stdout.
 //
 // e.g.,
@@ -19,14 +101,12 @@ import (
 // https://github.com/onsi/ginkgo/blob/v2.0.0/formatter/formatter.go#L52-L73
 //
 func Outf(format string, args ...interface{}) {
-	s := formatter.F(format, args...)
-	fmt.Fprint(formatter.ColorableStdOut, s)
+	Print(F(format, args...))
 }
 
 // Outputs to stderr.
 func Errf(format string, args ...interface{}) {
-	s := formatter.F(format, args...)
-	fmt.Fprint(formatter.ColorableStdErr, s)
+	fmt.Fprint(Stderr, F(format, args...))
 }
 
 func Greenf(format string, args ...interface{}) {
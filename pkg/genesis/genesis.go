@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package genesis implements the gRPC interface (see genesis.proto) that
+// lets VM authors plug their genesis tooling into "subnet-cli genesis".
+package genesis
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ServiceName is the fully qualified gRPC service name.
+const ServiceName = "subnetcli.genesis.v1.Genesis"
+
+// Generator builds and checks the genesis of a VM.
+type Generator interface {
+	// Generate returns the genesis for [params] (decoded JSON values).
+	Generate(ctx context.Context, params map[string]interface{}) ([]byte, error)
+	// Validate returns an error if the VM would reject [genesis].
+	Validate(ctx context.Context, genesis []byte) error
+}
+
+// InvalidArgument wraps [err] so the caller receives INVALID_ARGUMENT
+// instead of UNKNOWN; use it for bad parameters or genesis.
+func InvalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// NewClient returns a Generator that calls the Genesis service on [conn].
+func NewClient(conn grpc.ClientConnInterface) Generator {
+	return &client{conn: conn}
+}
+
+type client struct {
+	conn grpc.ClientConnInterface
+}
+
+func (c *client) Generate(ctx context.Context, params map[string]interface{}) ([]byte, error) {
+	req, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(wrapperspb.BytesValue)
+	if err := c.conn.Invoke(ctx, "/"+ServiceName+"/Generate", req, resp); err != nil {
+		return nil, err
+	}
+	return resp.GetValue(), nil
+}
+
+func (c *client) Validate(ctx context.Context, genesis []byte) error {
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/Validate", wrapperspb.Bytes(genesis), new(emptypb.Empty))
+}
+
+// RegisterServer registers [g] as the Genesis service of [s].
+func RegisterServer(s *grpc.Server, g Generator) {
+	s.RegisterService(&serviceDesc, g)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Generator)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Generate", Handler: generateHandler},
+		{MethodName: "Validate", Handler: validateHandler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "genesis.proto",
+}
+
+func generateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(structpb.Struct)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		b, err := srv.(Generator).Generate(ctx, req.(*structpb.Struct).AsMap())
+		if err != nil {
+			return nil, err
+		}
+		return wrapperspb.Bytes(b), nil
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/Generate"}
+	return interceptor(ctx, req, info, handler)
+}
+
+func validateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(wrapperspb.BytesValue)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if err := srv.(Generator).Validate(ctx, req.(*wrapperspb.BytesValue).GetValue()); err != nil {
+			return nil, err
+		}
+		return new(emptypb.Empty), nil
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/Validate"}
+	return interceptor(ctx, req, info, handler)
+}
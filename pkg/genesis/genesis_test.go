@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pluginEnv selects the behavior of the test binary when launched as a
+// plugin (see [TestMain]).
+const pluginEnv = "SUBNET_CLI_GENESIS_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(pluginEnv) {
+	case "":
+	case "serve":
+		if err := Serve(testGenerator{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "garbage":
+		fmt.Println("hello")
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		os.Exit(0)
+	case "silent":
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		os.Exit(0)
+	}
+	handshakeTimeout = 3 * time.Second
+	os.Exit(m.Run())
+}
+
+var errNoAlloc = errors.New("no alloc")
+
+// testGenerator generates a JSON genesis with the "alloc" parameter.
+type testGenerator struct{}
+
+func (testGenerator) Generate(_ context.Context, params map[string]interface{}) ([]byte, error) {
+	alloc, ok := params["alloc"]
+	if !ok {
+		return nil, InvalidArgument(errNoAlloc)
+	}
+	return json.Marshal(map[string]interface{}{"alloc": alloc})
+}
+
+func (testGenerator) Validate(_ context.Context, genesis []byte) error {
+	g := make(map[string]interface{})
+	if err := json.Unmarshal(genesis, &g); err != nil {
+		return InvalidArgument(err)
+	}
+	if _, ok := g["alloc"]; !ok {
+		return InvalidArgument(errNoAlloc)
+	}
+	if g["alloc"] == "fail" {
+		return errors.New("internal failure")
+	}
+	return nil
+}
+
+// plugin writes a plugin executable re-running the test binary in [mode].
+func plugin(t *testing.T, mode string) string {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), PluginPrefix+mode)
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q\n", pluginEnv, mode, exe)
+	if err := os.WriteFile(p, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLaunch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	g, stop, err := Launch(ctx, plugin(t, "serve"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := stop(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	b, err := g.Generate(ctx, map[string]interface{}{"alloc": map[string]interface{}{"0x01": "100"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"alloc":{"0x01":"100"}}` {
+		t.Fatalf("unexpected genesis %s", b)
+	}
+	if err := g.Validate(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		generate map[string]interface{}
+		validate string
+		expCode  codes.Code
+	}{
+		{generate: map[string]interface{}{}, expCode: codes.InvalidArgument},
+		{validate: "{", expCode: codes.InvalidArgument},
+		{validate: "{}", expCode: codes.InvalidArgument},
+		{validate: `{"alloc":"fail"}`, expCode: codes.Unknown},
+	}
+	for i, tv := range tt {
+		if tv.generate != nil {
+			_, err = g.Generate(ctx, tv.generate)
+		} else {
+			err = g.Validate(ctx, []byte(tv.validate))
+		}
+		if code := status.Code(err); code != tv.expCode {
+			t.Fatalf("#%d: expected %v, got %v (%v)", i, tv.expCode, code, err)
+		}
+	}
+}
+
+func TestLaunchHandshake(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		mode   string
+		expErr error
+	}{
+		{mode: "garbage", expErr: ErrHandshake},
+		{mode: "silent", expErr: ErrHandshake},
+	}
+	for i, tv := range tt {
+		start := time.Now()
+		_, _, err := Launch(context.Background(), plugin(t, tv.mode))
+		if !errors.Is(err, tv.expErr) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.expErr, err)
+		}
+		if tv.mode == "silent" && time.Since(start) < handshakeTimeout {
+			t.Fatalf("#%d: returned before the handshake timeout", i)
+		}
+	}
+
+	// a canceled context stops waiting for the handshake
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := Launch(ctx, plugin(t, "silent")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// PluginPrefix is the executable name prefix of genesis plugins: the
+// plugin for "--vm spacesvm" is "subnet-cli-genesis-spacesvm" on PATH.
+const PluginPrefix = "subnet-cli-genesis-"
+
+// Handshake is the first line a plugin prints to stdout, followed by the
+// TCP address it serves on (e.g., "subnet-cli-genesis|1|127.0.0.1:41203").
+const Handshake = "subnet-cli-genesis|1|"
+
+// handshakeTimeout bounds the plugin startup (a variable for tests).
+var handshakeTimeout = 10 * time.Second
+
+var ErrHandshake = errors.New("invalid genesis plugin handshake")
+
+// Serve serves [g] on a local port and prints the handshake line; plugin
+// executables call it from main. It returns once stdin is closed, which
+// happens when subnet-cli exits.
+func Serve(g Generator) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer()
+	RegisterServer(s, g)
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		s.Stop()
+	}()
+	fmt.Fprintf(os.Stdout, "%s%s\n", Handshake, ln.Addr())
+	return s.Serve(ln)
+}
+
+// Dial connects to a Genesis service at [addr] (e.g., a plugin running
+// under a debugger).
+func Dial(ctx context.Context, addr string) (Generator, func() error, error) {
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewClient(conn), conn.Close, nil
+}
+
+// Launch starts the plugin executable [path] and connects to it. The
+// returned function closes the connection and stops the plugin.
+func Launch(ctx context.Context, path string) (Generator, func() error, error) {
+	c := exec.Command(path)
+	c.Stderr = os.Stderr
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, nil, err
+	}
+	stop := func() error {
+		_ = stdin.Close()
+		done := make(chan error, 1)
+		go func() { done <- c.Wait() }()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			_ = c.Process.Kill()
+			<-done
+		}
+		return nil
+	}
+
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(stdout).ReadString('\n')
+		lines <- strings.TrimSpace(line)
+		// keep draining, so a chatty plugin never blocks on a full pipe
+		_, _ = io.Copy(io.Discard, stdout)
+	}()
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(handshakeTimeout):
+		_ = stop()
+		return nil, nil, fmt.Errorf("%w: %s printed nothing in %v", ErrHandshake, path, handshakeTimeout)
+	case <-ctx.Done():
+		_ = stop()
+		return nil, nil, ctx.Err()
+	}
+	if !strings.HasPrefix(line, Handshake) {
+		_ = stop()
+		return nil, nil, fmt.Errorf("%w: %s printed %q", ErrHandshake, path, line)
+	}
+	g, closeConn, err := Dial(ctx, strings.TrimPrefix(line, Handshake))
+	if err != nil {
+		_ = stop()
+		return nil, nil, err
+	}
+	return g, func() error {
+		err := closeConn()
+		_ = stop()
+		return err
+	}, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnet
+
+import (
+	"github.com/ava-labs/subnet-cli/internal/key"
+)
+
+// Key pays for and signs the txs.
+type Key = key.Key
+
+// LoadKey loads the hex-encoded private key file at [p] (as written by
+// "subnet-cli create key").
+func LoadKey(networkID uint32, p string) (Key, error) {
+	k, err := key.LoadSoft(networkID, p)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewKey creates a key from a "PrivateKey-" prefixed CB58 private key.
+func NewKey(networkID uint32, encoded string) (Key, error) {
+	k, err := key.NewSoft(networkID, key.WithPrivateKeyEncoded(encoded))
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewLedgerKey connects to a Ledger device running the Avalanche app.
+func NewLedgerKey(networkID uint32) (Key, error) {
+	k, err := key.NewHard(networkID)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Signer is a signing backend (e.g., an HSM or a remote KMS) that only
+// signs tx hashes.
+type Signer = key.Signer
+
+// NewSignerKey returns a key that spends the UTXOs of the addresses of [s]
+// and signs via [s].
+func NewSignerKey(networkID uint32, s Signer) (Key, error) {
+	k, err := key.NewSignerKey(networkID, s)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
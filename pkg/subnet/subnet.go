@@ -0,0 +1,224 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package subnet is the Go API for deploying subnets, for tools that embed
+// subnet deployment instead of shelling out to "subnet-cli". Every call
+// takes a context and an options struct; zero-valued options fall back to
+// the same defaults as the CLI.
+//
+// e.g.,
+//
+//	cli, err := subnet.New(ctx, subnet.Config{URI: "https://api.avax-test.network"})
+//	k, err := subnet.LoadKey(cli.NetworkID(), ".subnet-cli.pk")
+//	subnetID, err := cli.CreateSubnet(ctx, k, subnet.CreateSubnetOptions{Wait: true})
+//	err = cli.AddSubnetValidator(ctx, k, subnet.AddSubnetValidatorOptions{
+//		SubnetID: subnetID,
+//		NodeID:   nodeID,
+//		End:      time.Now().Add(14 * 24 * time.Hour),
+//		Wait:     true,
+//	})
+//	chainID, err := cli.CreateChain(ctx, k, subnet.CreateChainOptions{
+//		SubnetID: subnetID,
+//		Name:     "mychain",
+//		VMID:     vmID,
+//		Genesis:  genesis,
+//		Wait:     true,
+//	})
+package subnet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/subnet-cli/client"
+)
+
+const (
+	// DefaultPollInterval is the tx status poll interval.
+	DefaultPollInterval = time.Second
+	// DefaultStartBuffer is how far in the future a validation starts when
+	// no start time is given.
+	DefaultStartBuffer = 30 * time.Second
+	// DefaultWeight is the subnet validator weight when none is given.
+	DefaultWeight = 1000
+	// DefaultRewardFeePercent is the delegation fee of new primary network
+	// validators when none is given.
+	DefaultRewardFeePercent = 2
+)
+
+var (
+	ErrEmptyNodeID = errors.New("empty node ID")
+	ErrEmptyEnd    = errors.New("empty validation end time")
+)
+
+// Config configures the [Client].
+type Config struct {
+	// URI is the avalanchego API endpoint (e.g., https://api.avax-test.network).
+	URI string
+	// PollInterval defaults to [DefaultPollInterval].
+	PollInterval time.Duration
+}
+
+// Client deploys subnets, validators and chains on the network at
+// [Config.URI].
+type Client struct {
+	cli client.Client
+}
+
+// New connects to the network at [cfg.URI].
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	cli, err := client.New(ctx, client.Config{
+		URI:          cfg.URI,
+		PollInterval: cfg.PollInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cli: cli}, nil
+}
+
+// NetworkID returns the ID of the connected network.
+func (c *Client) NetworkID() uint32 { return c.cli.NetworkID() }
+
+// Client returns the underlying low-level client.
+func (c *Client) Client() client.Client { return c.cli }
+
+// Balance returns the P-Chain balance of [k] in nano-AVAX.
+func (c *Client) Balance(ctx context.Context, k Key) (uint64, error) {
+	return c.cli.P().Balance(ctx, k)
+}
+
+// CreateSubnetOptions configures [Client.CreateSubnet].
+type CreateSubnetOptions struct {
+	// DryRun builds and signs the tx without issuing it; the returned ID is
+	// the ID the subnet would get.
+	DryRun bool
+	// Wait blocks until the tx is committed.
+	Wait bool
+}
+
+// CreateSubnet creates a subnet controlled by [k].
+func (c *Client) CreateSubnet(ctx context.Context, k Key, opts CreateSubnetOptions) (ids.ID, error) {
+	subnetID, _, err := c.cli.P().CreateSubnet(ctx, k,
+		client.WithDryMode(opts.DryRun),
+		client.WithPoll(opts.Wait),
+	)
+	return subnetID, err
+}
+
+// AddValidatorOptions configures [Client.AddValidator].
+type AddValidatorOptions struct {
+	NodeID ids.ShortID
+	// Start defaults to [DefaultStartBuffer] from now.
+	Start time.Time
+	End   time.Time
+	// StakeAmount (in nano-AVAX) defaults to the network minimum.
+	StakeAmount uint64
+	// RewardFeePercent defaults to [DefaultRewardFeePercent].
+	RewardFeePercent uint32
+	// RewardAddress and ChangeAddress default to the first address of the key.
+	RewardAddress ids.ShortID
+	ChangeAddress ids.ShortID
+	// Wait blocks until the tx is committed.
+	Wait bool
+}
+
+// AddValidator adds [opts.NodeID] as a primary network validator, staked
+// by [k].
+func (c *Client) AddValidator(ctx context.Context, k Key, opts AddValidatorOptions) error {
+	if opts.NodeID == ids.ShortEmpty {
+		return ErrEmptyNodeID
+	}
+	if opts.End.IsZero() {
+		return ErrEmptyEnd
+	}
+	if opts.Start.IsZero() {
+		opts.Start = time.Now().Add(DefaultStartBuffer)
+	}
+	if opts.StakeAmount == 0 {
+		opts.StakeAmount = genesis.GetStakingConfig(c.cli.NetworkID()).MinValidatorStake
+	}
+	if opts.RewardFeePercent == 0 {
+		opts.RewardFeePercent = DefaultRewardFeePercent
+	}
+	if opts.RewardAddress == ids.ShortEmpty {
+		opts.RewardAddress = k.Addresses()[0]
+	}
+	if opts.ChangeAddress == ids.ShortEmpty {
+		opts.ChangeAddress = k.Addresses()[0]
+	}
+	_, err := c.cli.P().AddValidator(ctx, k, opts.NodeID, opts.Start, opts.End,
+		client.WithStakeAmount(opts.StakeAmount),
+		client.WithRewardShares(opts.RewardFeePercent*10000),
+		client.WithRewardAddress(opts.RewardAddress),
+		client.WithChangeAddress(opts.ChangeAddress),
+		client.WithPoll(opts.Wait),
+	)
+	return err
+}
+
+// AddSubnetValidatorOptions configures [Client.AddSubnetValidator].
+type AddSubnetValidatorOptions struct {
+	SubnetID ids.ID
+	NodeID   ids.ShortID
+	// Start defaults to [DefaultStartBuffer] from now.
+	Start time.Time
+	End   time.Time
+	// Weight defaults to [DefaultWeight].
+	Weight uint64
+	// Wait blocks until the tx is committed.
+	Wait bool
+}
+
+// AddSubnetValidator adds [opts.NodeID], which must validate the primary
+// network for the whole period, as a validator of [opts.SubnetID].
+func (c *Client) AddSubnetValidator(ctx context.Context, k Key, opts AddSubnetValidatorOptions) error {
+	if opts.SubnetID == ids.Empty {
+		return client.ErrEmptyID
+	}
+	if opts.NodeID == ids.ShortEmpty {
+		return ErrEmptyNodeID
+	}
+	if opts.End.IsZero() {
+		return ErrEmptyEnd
+	}
+	if opts.Start.IsZero() {
+		opts.Start = time.Now().Add(DefaultStartBuffer)
+	}
+	if opts.Weight == 0 {
+		opts.Weight = DefaultWeight
+	}
+	_, err := c.cli.P().AddSubnetValidator(ctx, k, opts.SubnetID, opts.NodeID, opts.Start, opts.End, opts.Weight,
+		client.WithPoll(opts.Wait),
+	)
+	return err
+}
+
+// CreateChainOptions configures [Client.CreateChain].
+type CreateChainOptions struct {
+	SubnetID ids.ID
+	Name     string
+	VMID     ids.ID
+	Genesis  []byte
+	// Wait blocks until the chain is created and bootstrapped by the
+	// connected node.
+	Wait bool
+}
+
+// CreateChain creates a blockchain of [opts.VMID] on [opts.SubnetID].
+func (c *Client) CreateChain(ctx context.Context, k Key, opts CreateChainOptions) (ids.ID, error) {
+	if opts.SubnetID == ids.Empty || opts.VMID == ids.Empty {
+		return ids.Empty, client.ErrEmptyID
+	}
+	chainID, _, err := c.cli.P().CreateBlockchain(ctx, k, opts.SubnetID, opts.Name, opts.VMID, opts.Genesis,
+		client.WithPoll(opts.Wait),
+	)
+	return chainID, err
+}
@@ -99,7 +99,7 @@ var _ = ginkgo.BeforeSuite(func() {
 	gomega.Ω(err).Should(gomega.BeNil())
 
 	color.Outf("{{green}}creating subnet-cli client{{/}}\n")
-	cli, err = client.New(client.Config{
+	cli, err = client.New(context.Background(), client.Config{
 		URI:          uris[0],
 		PollInterval: time.Second,
 	})